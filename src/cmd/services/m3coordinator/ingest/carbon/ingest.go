@@ -60,6 +60,7 @@ var (
 	// Used for parsing carbon names into tags.
 	carbonSeparatorByte  = byte('.')
 	carbonSeparatorBytes = []byte{carbonSeparatorByte}
+	carbonTagSeparator   = byte(';')
 
 	errCannotGenerateTagsFromEmptyName = errors.New("cannot generate tags from empty name")
 	errIOptsMustBeSet                  = errors.New("carbon ingester options: instrument options must be st")
@@ -275,7 +276,17 @@ func (i *ingester) writeWithOptions(
 	opts ingest.WriteOptions,
 ) error {
 	resources.datapoints[0] = ts.Datapoint{Timestamp: timestamp, Value: value}
-	tags, err := GenerateTagsFromNameIntoSlice(resources.name, i.tagOpts, resources.tags)
+	var (
+		tags models.Tags
+		err  error
+	)
+	if bytes.IndexByte(resources.name, carbonTagSeparator) != -1 {
+		tags, err = GenerateTagsFromTaggedNameIntoSlice(resources.name,
+			i.tagOpts, resources.tags)
+	} else {
+		tags, err = GenerateTagsFromNameIntoSlice(resources.name, i.tagOpts,
+			resources.tags)
+	}
 	if err != nil {
 		i.logger.Error("err generating tags from carbon",
 			zap.String("name", string(resources.name)), zap.Error(err))
@@ -393,6 +404,106 @@ func generateTagsFromName(
 	return models.Tags{Opts: opts, Tags: tags}, nil
 }
 
+// GenerateTagsFromTaggedName accepts a graphite tagged carbon metric name and
+// converts it into a list of key-value pair tags, storing the path under the
+// metric name tag, such that an input like:
+//      foo.bar;dc=east;app=web
+// becomes
+//      __name__:foo.bar
+//      app:web
+//      dc:east
+func GenerateTagsFromTaggedName(
+	name []byte,
+	opts models.TagOptions,
+) (models.Tags, error) {
+	return generateTagsFromTaggedName(name, opts, nil)
+}
+
+// GenerateTagsFromTaggedNameIntoSlice does the same thing as
+// GenerateTagsFromTaggedName except it allows the caller to provide the slice
+// into which the tags are appended.
+func GenerateTagsFromTaggedNameIntoSlice(
+	name []byte,
+	opts models.TagOptions,
+	tags []models.Tag,
+) (models.Tags, error) {
+	return generateTagsFromTaggedName(name, opts, tags)
+}
+
+func generateTagsFromTaggedName(
+	name []byte,
+	opts models.TagOptions,
+	tags []models.Tag,
+) (models.Tags, error) {
+	if len(name) == 0 {
+		return models.EmptyTags(), errCannotGenerateTagsFromEmptyName
+	}
+
+	pathEnd := bytes.IndexByte(name, carbonTagSeparator)
+	if pathEnd == -1 {
+		pathEnd = len(name)
+	}
+	if pathEnd == 0 {
+		return models.EmptyTags(), fmt.Errorf(
+			"carbon metric: %s has an empty path", string(name))
+	}
+
+	numTags := bytes.Count(name[pathEnd:], []byte{carbonTagSeparator}) + 1
+	if cap(tags) >= numTags {
+		tags = tags[:0]
+	} else {
+		tags = make([]models.Tag, 0, numTags)
+	}
+
+	tags = append(tags, models.Tag{
+		Name:  opts.MetricName(),
+		Value: name[:pathEnd],
+	})
+	for remaining := name[pathEnd:]; len(remaining) > 0; {
+		// Skip the separator preceding the tag.
+		remaining = remaining[1:]
+		part := remaining
+		if idx := bytes.IndexByte(remaining, carbonTagSeparator); idx != -1 {
+			part, remaining = remaining[:idx], remaining[idx:]
+		} else {
+			remaining = nil
+		}
+
+		idx := bytes.IndexByte(part, '=')
+		if idx <= 0 {
+			return models.EmptyTags(), fmt.Errorf(
+				"carbon metric: invalid tag %q in series %s", part, string(name))
+		}
+
+		tagName, value := part[:idx], part[idx+1:]
+		if bytes.Equal(tagName, opts.MetricName()) {
+			return models.EmptyTags(), fmt.Errorf(
+				"carbon metric: tag %s is reserved for the series path in series %s",
+				string(tagName), string(name))
+		}
+		if err := graphite.ValidateTag(string(tagName), string(value)); err != nil {
+			return models.EmptyTags(), fmt.Errorf(
+				"carbon metric: invalid tag %q in series %s: %v", part, string(name), err)
+		}
+
+		// NB: the last value of a repeated tag takes precedence, same as
+		// when parsing tagged names elsewhere.
+		replaced := false
+		for i := 1; i < len(tags); i++ {
+			if bytes.Equal(tags[i].Name, tagName) {
+				tags[i].Value = value
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			tags = append(tags, models.Tag{Name: tagName, Value: value})
+		}
+	}
+
+	return models.Tags{Opts: opts, Tags: tags}.Normalize(), nil
+}
+
 // Compile all the carbon ingestion rules into regexp so that we can
 // perform matching. Also, generate all the mapping rules and storage
 // policies that we will need to pass to the DownsamplerAndWriter upfront
@@ -359,6 +359,82 @@ func TestGenerateTagsFromName(t *testing.T) {
 	}
 }
 
+func TestGenerateTagsFromTaggedName(t *testing.T) {
+	opts := models.NewTagOptions().SetIDSchemeType(models.TypeGraphite)
+	tags, err := GenerateTagsFromTaggedName([]byte("foo.bar;dc=east;app=web"), opts)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("foo.bar;app=web;dc=east"), tags.ID())
+
+	name, ok := tags.Name()
+	require.True(t, ok)
+	assert.Equal(t, []byte("foo.bar"), name)
+
+	value, ok := tags.Get([]byte("dc"))
+	require.True(t, ok)
+	assert.Equal(t, []byte("east"), value)
+
+	_, ok = tags.Get(graphite.TagName(0))
+	assert.False(t, ok)
+
+	// The last value of a repeated tag takes precedence.
+	tags, err = GenerateTagsFromTaggedNameIntoSlice(
+		[]byte("foo.bar;dc=east;dc=west"), opts, make([]models.Tag, 0, 4))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("foo.bar;dc=west"), tags.ID())
+
+	for _, name := range []string{
+		"", ";dc=east", "foo.bar;dc", "foo.bar;dc=", "foo.bar;", "foo.bar;name=baz",
+		"foo.bar;" + string(opts.MetricName()) + "=baz",
+	} {
+		_, err := GenerateTagsFromTaggedName([]byte(name), opts)
+		require.Error(t, err, name)
+	}
+}
+
+func TestIngesterHandleTaggedConn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockDownsamplerAndWriter := ingest.NewMockDownsamplerAndWriter(ctrl)
+
+	var (
+		lock  = sync.Mutex{}
+		found = []testMetric{}
+	)
+	mockDownsamplerAndWriter.EXPECT().
+		Write(gomock.Any(), gomock.Any(), gomock.Any(), xtime.Second, gomock.Any(), gomock.Any()).DoAndReturn(func(
+		_ context.Context,
+		tags models.Tags,
+		dp ts.Datapoints,
+		unit xtime.Unit,
+		annotation []byte,
+		overrides ingest.WriteOptions,
+	) interface{} {
+		lock.Lock()
+		found = append(found, testMetric{
+			tags: tags.Clone(), timestamp: int(dp[0].Timestamp.Unix()), value: dp[0].Value})
+		lock.Unlock()
+		return nil
+	}).AnyTimes()
+
+	packet := []byte("foo.bar;dc=east;app=web 1 1\nfoo.bar;dc=west 2 2\nfoo.bar;dc 3 3\n")
+	byteConn := &byteConn{b: bytes.NewBuffer(packet)}
+	ingester, err := NewIngester(mockDownsamplerAndWriter, testRulesMatchAll, testOptions)
+	require.NoError(t, err)
+	ingester.Handle(byteConn)
+
+	assertTestMetricsAreEqual(t, []testMetric{
+		{
+			tags:      mustGenerateTagsFromTaggedName(t, []byte("foo.bar;dc=east;app=web")),
+			timestamp: 1,
+			value:     1,
+		},
+		{
+			tags:      mustGenerateTagsFromTaggedName(t, []byte("foo.bar;dc=west")),
+			timestamp: 2,
+			value:     2,
+		},
+	}, found)
+}
+
 // byteConn implements the net.Conn interface so that we can test the handler without
 // going over the network.
 type byteConn struct {
@@ -475,6 +551,12 @@ func mustGenerateTagsFromName(t *testing.T, name []byte) models.Tags {
 	return tags
 }
 
+func mustGenerateTagsFromTaggedName(t *testing.T, name []byte) models.Tags {
+	tags, err := GenerateTagsFromTaggedName(name, testTagOpts)
+	require.NoError(t, err)
+	return tags
+}
+
 var (
 	// Boilerplate to deal with optional config value nonsense.
 	trueVar          = true
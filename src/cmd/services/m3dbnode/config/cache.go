@@ -20,12 +20,19 @@
 
 package config
 
-import "github.com/m3db/m3/src/dbnode/storage/series"
+import (
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/storage/series"
+)
 
 var (
 	defaultPostingsListCacheSize   = 2 << 14 // 32,768
 	defaultPostingsListCacheRegexp = true
 	defaultPostingsListCacheTerms  = true
+
+	defaultPostingsListCachePersistMaxEntries = 1024
+	defaultPostingsListCachePersistInterval   = 10 * time.Minute
 )
 
 // CacheConfigurations is the cache configurations.
@@ -72,9 +79,10 @@ type LRUSeriesCachePolicyConfiguration struct {
 
 // PostingsListCacheConfiguration is the postings list cache configuration.
 type PostingsListCacheConfiguration struct {
-	Size        *int  `yaml:"size"`
-	CacheRegexp *bool `yaml:"cacheRegexp"`
-	CacheTerms  *bool `yaml:"cacheTerms"`
+	Size        *int                                   `yaml:"size"`
+	CacheRegexp *bool                                  `yaml:"cacheRegexp"`
+	CacheTerms  *bool                                  `yaml:"cacheTerms"`
+	Persist     *PostingsListCachePersistConfiguration `yaml:"persist"`
 }
 
 // SizeOrDefault returns the provided size or the default value is none is
@@ -97,6 +105,16 @@ func (p *PostingsListCacheConfiguration) CacheRegexpOrDefault() bool {
 	return *p.CacheRegexp
 }
 
+// PersistOrDefault returns the provided persist configuration or the default
+// value, which does not persist the cache, if none is provided.
+func (p *PostingsListCacheConfiguration) PersistOrDefault() PostingsListCachePersistConfiguration {
+	if p.Persist == nil {
+		return PostingsListCachePersistConfiguration{}
+	}
+
+	return *p.Persist
+}
+
 // CacheTermsOrDefault returns the provided cache terms configuration value
 // or the default value is none is provided.
 func (p *PostingsListCacheConfiguration) CacheTermsOrDefault() bool {
@@ -106,3 +124,38 @@ func (p *PostingsListCacheConfiguration) CacheTermsOrDefault() bool {
 
 	return *p.CacheTerms
 }
+
+// PostingsListCachePersistConfiguration is the configuration for persisting
+// the most recently used postings lists of the sealed index segments alongside
+// the index filesets, so that they are loaded into the cache at bootstrap.
+type PostingsListCachePersistConfiguration struct {
+	// Enabled determines whether the postings lists are persisted.
+	Enabled bool `yaml:"enabled"`
+
+	// MaxEntriesPerSegment is the max number of postings lists persisted
+	// per index segment.
+	MaxEntriesPerSegment *int `yaml:"maxEntriesPerSegment"`
+
+	// Interval is the interval at which the postings lists are persisted.
+	Interval *time.Duration `yaml:"interval"`
+}
+
+// MaxEntriesPerSegmentOrDefault returns the provided max entries per segment
+// or the default value if none is provided.
+func (p PostingsListCachePersistConfiguration) MaxEntriesPerSegmentOrDefault() int {
+	if p.MaxEntriesPerSegment == nil {
+		return defaultPostingsListCachePersistMaxEntries
+	}
+
+	return *p.MaxEntriesPerSegment
+}
+
+// IntervalOrDefault returns the provided interval or the default value if
+// none is provided.
+func (p PostingsListCachePersistConfiguration) IntervalOrDefault() time.Duration {
+	if p.Interval == nil {
+		return defaultPostingsListCachePersistInterval
+	}
+
+	return *p.Interval
+}
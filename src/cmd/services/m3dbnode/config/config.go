@@ -32,6 +32,7 @@ import (
 	coordinatorcfg "github.com/m3db/m3/src/cmd/services/m3query/config"
 	"github.com/m3db/m3/src/dbnode/client"
 	"github.com/m3db/m3/src/dbnode/environment"
+	"github.com/m3db/m3/src/dbnode/persist/fs/commitlog"
 	"github.com/m3db/m3/src/dbnode/storage/series"
 	"github.com/m3db/m3/src/x/config/hostid"
 	"github.com/m3db/m3/src/x/instrument"
@@ -157,6 +158,13 @@ type DBConfiguration struct {
 	// Limits contains configuration for limits that can be applied to M3DB for the purposes
 	// of applying back-pressure or protecting the db nodes.
 	Limits Limits `yaml:"limits"`
+
+	// Quotas contains the per namespace and per tenant quotas, the quotas can
+	// also be set at runtime with the quotas KV key.
+	Quotas *QuotasConfiguration `yaml:"quotas"`
+
+	// Backup contains the configuration for backups of namespaces.
+	Backup *BackupConfiguration `yaml:"backup"`
 }
 
 // InitDefaultsAndValidate initializes all default values and validates the Configuration.
@@ -214,6 +222,18 @@ type IndexConfiguration struct {
 	// block boundaries by eagerly writing the series to the next block
 	// preemptively.
 	ForwardIndexThreshold float64 `yaml:"forwardIndexThreshold" validate:"min=0.0,max=1.0"`
+
+	// DictionaryEncodedDocuments determines whether flushed index segments
+	// encode the tags of their documents once in a dictionary, matching the
+	// client side fetchTaggedDictionaryEncodedTags option. It defaults to
+	// false since nodes which write these segments cannot be downgraded to a
+	// release which does not read them.
+	//
+	// NB: writing a segment holds every distinct tag name and value of the
+	// segment in memory, and opening a segment at bootstrap holds a reference
+	// to each of them for the lifetime of the segment, so both cost memory
+	// proportional to the number of unique tag values.
+	DictionaryEncodedDocuments bool `yaml:"dictionaryEncodedDocuments"`
 }
 
 // TransformConfiguration contains configuration options that can transform
@@ -286,6 +306,11 @@ type CommitLogPolicy struct {
 	// enough for almost all workloads assuming a reasonable batch size is used.
 	QueueChannel *CommitLogQueuePolicy `yaml:"queueChannel"`
 
+	// The compression applied to commit log chunks, defaults to none. Commit
+	// logs written with any compression type can be read regardless of the
+	// currently configured type.
+	Compression commitlog.CompressionType `yaml:"compression"`
+
 	// Deprecated. Left in struct to keep old YAMLs parseable.
 	// TODO(V1): remove
 	DeprecatedBlockSize *time.Duration `yaml:"blockSize"`
@@ -387,6 +412,14 @@ type ProtoConfiguration struct {
 	SchemaRegistry map[string]NamespaceProtoSchema `yaml:"schema_registry"`
 }
 
+// BackupConfiguration is the configuration for backups of namespaces.
+type BackupConfiguration struct {
+	// AllowedDestinations are the root directories that backups requested
+	// through the node RPC may be written beneath, backups can not be
+	// requested when none are configured.
+	AllowedDestinations []string `yaml:"allowedDestinations"`
+}
+
 // NamespaceProtoSchema is the namespace protobuf schema.
 type NamespaceProtoSchema struct {
 	// For application m3db client integration test convenience (where a local dbnode is started as a docker container),
@@ -345,6 +345,7 @@ func TestConfiguration(t *testing.T) {
     maxQueryIDsConcurrency: 0
     forwardIndexProbability: 0
     forwardIndexThreshold: 0
+    dictionaryEncodedDocuments: false
   transforms:
     truncateBy: 0
     forceValue: null
@@ -406,6 +407,7 @@ func TestConfiguration(t *testing.T) {
     asyncWriteWorkerPoolSize: null
     asyncWriteMaxConcurrency: null
     useV2BatchAPIs: null
+    fetchTaggedDictionaryEncodedTags: null
   gcPercentage: 100
   writeNewSeriesLimitPerSecond: 1048576
   writeNewSeriesBackoffDuration: 2ms
@@ -427,6 +429,7 @@ func TestConfiguration(t *testing.T) {
       size: 100
       cacheRegexp: false
       cacheTerms: false
+      persist: null
   fs:
     filePathPrefix: /var/lib/m3db
     writeBufferSize: 65536
@@ -448,6 +451,7 @@ func TestConfiguration(t *testing.T) {
       calculationType: fixed
       size: 2097152
     queueChannel: null
+    compression: none
     blockSize: null
   repair:
     enabled: false
@@ -605,6 +609,10 @@ func TestConfiguration(t *testing.T) {
       size: 8
       lowWatermark: 0
       highWatermark: 0
+    histogramEncoderPool:
+      size: null
+      lowWatermark: null
+      highWatermark: null
   config:
     services:
     - async: false
@@ -714,6 +722,8 @@ func TestConfiguration(t *testing.T) {
     maxOutstandingWriteRequests: 0
     maxOutstandingReadRequests: 0
     maxOutstandingRepairedBytes: 0
+  quotas: null
+  backup: null
 coordinator: null
 `
 
@@ -109,6 +109,13 @@ var (
 			refillLowWaterMark:  defaultRefillLowWaterMark,
 			refillHighWaterMark: defaultRefillHighWaterMark,
 		},
+		"histogramEncoder": poolPolicyDefault{
+			// NB: Only the namespaces with histograms enabled use histogram
+			// encoders so this pool is much smaller than the encoder pool.
+			size:                4096,
+			refillLowWaterMark:  defaultRefillLowWaterMark,
+			refillHighWaterMark: defaultRefillHighWaterMark,
+		},
 		"closers": poolPolicyDefault{
 			// NB(r): Note this has to be bigger than context pool by
 			// big fraction (by factor of say 4) since each context
@@ -368,6 +375,9 @@ type PoolingPolicy struct {
 
 	// The policy for the PostingsListPool.
 	PostingsListPool PoolPolicy `yaml:"postingsListPool"`
+
+	// The policy for the histogram Encoder pool.
+	HistogramEncoderPool PoolPolicy `yaml:"histogramEncoderPool"`
 }
 
 // InitDefaultsAndValidate initializes all default values and validates the configuration
@@ -438,6 +448,9 @@ func (p *PoolingPolicy) InitDefaultsAndValidate() error {
 	if err := p.PostingsListPool.initDefaultsAndValidate("postingsList"); err != nil {
 		return err
 	}
+	if err := p.HistogramEncoderPool.initDefaultsAndValidate("histogramEncoder"); err != nil {
+		return err
+	}
 	if err := p.BytesPool.initDefaultsAndValidate("bytes"); err != nil {
 		return err
 	}
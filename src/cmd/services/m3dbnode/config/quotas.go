@@ -0,0 +1,97 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package config
+
+import (
+	"github.com/m3db/m3/src/dbnode/runtime"
+)
+
+// QuotasConfiguration is the configuration for per namespace and per tenant
+// quotas, it can be overridden at runtime by the quotas KV key.
+type QuotasConfiguration struct {
+	// TenantTag is the name of the tag that identifies the tenant of a series,
+	// tenant quotas are not enforced if it is not set.
+	TenantTag string `yaml:"tenantTag"`
+
+	// Namespaces are the quotas by namespace ID.
+	Namespaces map[string]QuotaConfiguration `yaml:"namespaces"`
+
+	// Tenants are the quotas by tenant.
+	Tenants map[string]QuotaConfiguration `yaml:"tenants"`
+
+	// DefaultTenant is the quota for tenants without a quota of their own.
+	DefaultTenant QuotaConfiguration `yaml:"defaultTenant"`
+}
+
+// QuotaConfiguration is the configuration for a single quota, a zero limit
+// is not enforced.
+type QuotaConfiguration struct {
+	// DatapointsPerSecond is the number of datapoints that can be written
+	// per second.
+	DatapointsPerSecond int64 `yaml:"datapointsPerSecond" validate:"min=0"`
+
+	// NewSeriesPerSecond is the number of new series that can be inserted
+	// per second.
+	NewSeriesPerSecond int64 `yaml:"newSeriesPerSecond" validate:"min=0"`
+
+	// MaxSeries is the number of series that can be held in memory.
+	MaxSeries int64 `yaml:"maxSeries" validate:"min=0"`
+
+	// MaxQueryDocs is the number of index documents a single query can match.
+	MaxQueryDocs int64 `yaml:"maxQueryDocs" validate:"min=0"`
+
+	// MaxAggregateQueryTerms is the number of terms a single aggregate query
+	// can return.
+	MaxAggregateQueryTerms int64 `yaml:"maxAggregateQueryTerms" validate:"min=0"`
+}
+
+// Quota returns the runtime quota.
+func (c QuotaConfiguration) Quota() runtime.Quota {
+	return runtime.Quota{
+		DatapointsPerSecond:    c.DatapointsPerSecond,
+		NewSeriesPerSecond:     c.NewSeriesPerSecond,
+		MaxSeries:              c.MaxSeries,
+		MaxQueryDocs:           c.MaxQueryDocs,
+		MaxAggregateQueryTerms: c.MaxAggregateQueryTerms,
+	}
+}
+
+// NewQuotaOptions returns the runtime quota options.
+func (c QuotasConfiguration) NewQuotaOptions() (runtime.QuotaOptions, error) {
+	namespaces := make(map[string]runtime.Quota, len(c.Namespaces))
+	for ns, quota := range c.Namespaces {
+		namespaces[ns] = quota.Quota()
+	}
+	tenants := make(map[string]runtime.Quota, len(c.Tenants))
+	for tenant, quota := range c.Tenants {
+		tenants[tenant] = quota.Quota()
+	}
+
+	opts := runtime.NewQuotaOptions().
+		SetTenantTag(c.TenantTag).
+		SetNamespaceQuotas(namespaces).
+		SetTenantQuotas(tenants).
+		SetDefaultTenantQuota(c.DefaultTenant.Quota())
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	return opts, nil
+}
@@ -0,0 +1,72 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/m3db/m3/src/dbnode/runtime"
+
+	"github.com/stretchr/testify/require"
+	yaml "gopkg.in/yaml.v2"
+)
+
+func TestQuotasConfigurationNewQuotaOptions(t *testing.T) {
+	str := `
+tenantTag: tenant
+namespaces:
+  metrics:
+    datapointsPerSecond: 1000
+    maxSeries: 100
+tenants:
+  foo:
+    newSeriesPerSecond: 10
+defaultTenant:
+  maxQueryDocs: 500
+  maxAggregateQueryTerms: 50
+`
+	var cfg QuotasConfiguration
+	require.NoError(t, yaml.Unmarshal([]byte(str), &cfg))
+
+	opts, err := cfg.NewQuotaOptions()
+	require.NoError(t, err)
+	require.Equal(t, "tenant", opts.TenantTag())
+	require.Equal(t, map[string]runtime.Quota{
+		"metrics": {DatapointsPerSecond: 1000, MaxSeries: 100},
+	}, opts.NamespaceQuotas())
+	require.Equal(t, map[string]runtime.Quota{
+		"foo": {NewSeriesPerSecond: 10},
+	}, opts.TenantQuotas())
+	require.Equal(t, runtime.Quota{
+		MaxQueryDocs:           500,
+		MaxAggregateQueryTerms: 50,
+	}, opts.DefaultTenantQuota())
+}
+
+func TestQuotasConfigurationNewQuotaOptionsInvalid(t *testing.T) {
+	cfg := QuotasConfiguration{
+		Tenants: map[string]QuotaConfiguration{
+			"foo": {MaxSeries: -1},
+		},
+	}
+	_, err := cfg.NewQuotaOptions()
+	require.Error(t, err)
+}
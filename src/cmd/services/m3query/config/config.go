@@ -66,6 +66,11 @@ var (
 	defaultCarbonIngesterAggregationType = aggregation.Mean
 
 	defaultStorageQueryLimit = 10000
+
+	defaultResultsCacheMaxBytes = 256 * 1024 * 1024
+
+	// 2m is the default query timeout in Prometheus
+	defaultRulesQueryTimeout = 2 * time.Minute
 )
 
 // Configuration is the configuration for the query service.
@@ -136,6 +141,14 @@ type Configuration struct {
 	// Experimental is the configuration for the experimental API group.
 	Experimental ExperimentalAPIConfiguration `yaml:"experimental"`
 
+	// ResultsCache is the configuration for caching the results of range
+	// queries, results are not cached if unset.
+	ResultsCache *ResultsCacheConfiguration `yaml:"resultsCache"`
+
+	// Rules is the configuration for evaluating Prometheus recording and
+	// alerting rules, rules are not evaluated if unset.
+	Rules *RulesConfiguration `yaml:"rules"`
+
 	// Cache configurations.
 	//
 	// Deprecated: cache configurations are no longer supported. Remove from file
@@ -183,6 +196,79 @@ type DeprecatedQueryConversionCacheConfiguration struct {
 	Size *int `yaml:"size"`
 }
 
+// ResultsCacheConfiguration is the configuration for caching the results of
+// range queries.
+type ResultsCacheConfiguration struct {
+	// SplitInterval is the interval range queries are split into, the
+	// results of each completed interval are cached separately.
+	SplitInterval time.Duration `yaml:"splitInterval"`
+
+	// MaxFreshness is how long after an interval ends before its results are
+	// cached.
+	MaxFreshness time.Duration `yaml:"maxFreshness"`
+
+	// InMemory is the configuration for the in-memory cache backend.
+	InMemory InMemoryResultsCacheConfiguration `yaml:"inMemory"`
+}
+
+// InMemoryResultsCacheConfiguration is the configuration for the in-memory
+// results cache backend.
+type InMemoryResultsCacheConfiguration struct {
+	// MaxBytes is the maximum size of the cached results.
+	MaxBytes int `yaml:"maxBytes"`
+}
+
+// MaxBytesOrDefault returns the maximum size of the cached results if
+// provided, or the default value if not.
+func (c InMemoryResultsCacheConfiguration) MaxBytesOrDefault() int {
+	if c.MaxBytes > 0 {
+		return c.MaxBytes
+	}
+
+	return defaultResultsCacheMaxBytes
+}
+
+// RulesConfiguration is the configuration for evaluating Prometheus
+// recording and alerting rules.
+type RulesConfiguration struct {
+	// RuleFiles are the patterns of the Prometheus rule files to load.
+	RuleFiles []string `yaml:"ruleFiles"`
+
+	// EvaluationInterval is the interval of rule groups that do not set one.
+	EvaluationInterval time.Duration `yaml:"evaluationInterval"`
+
+	// QueryTimeout is the timeout for evaluating the query of a rule.
+	QueryTimeout time.Duration `yaml:"queryTimeout"`
+
+	// Alertmanager is the configuration for sending alerts to Alertmanager,
+	// alerts are not sent if unset.
+	Alertmanager *AlertmanagerConfiguration `yaml:"alertmanager"`
+}
+
+// QueryTimeoutOrDefault returns the rule query timeout if provided, or the
+// default value if not.
+func (c RulesConfiguration) QueryTimeoutOrDefault() time.Duration {
+	if c.QueryTimeout > 0 {
+		return c.QueryTimeout
+	}
+
+	return defaultRulesQueryTimeout
+}
+
+// AlertmanagerConfiguration is the configuration for sending alerts to
+// Alertmanager.
+type AlertmanagerConfiguration struct {
+	// URLs are the base URLs of the Alertmanager instances to send alerts to.
+	URLs []string `yaml:"urls" validate:"nonzero"`
+
+	// Timeout is the timeout for sending alerts.
+	Timeout time.Duration `yaml:"timeout"`
+
+	// ResendDelay is how long to wait before sending an alert that is still
+	// firing again.
+	ResendDelay time.Duration `yaml:"resendDelay"`
+}
+
 // ResultOptions are the result options for query.
 type ResultOptions struct {
 	// KeepNans keeps NaNs before returning query results.
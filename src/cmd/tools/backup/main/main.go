@@ -0,0 +1,122 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/generated/thrift/rpc"
+	nchannel "github.com/m3db/m3/src/dbnode/network/server/tchannelthrift/node/channel"
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/dbnode/persist/fs/backup"
+
+	"github.com/uber/tchannel-go"
+	"github.com/uber/tchannel-go/thrift"
+	"go.uber.org/zap"
+)
+
+const (
+	modeBackup  = "backup"
+	modeRestore = "restore"
+)
+
+var (
+	optMode        = flag.String("mode", modeBackup, "Mode, either backup or restore")
+	optEndpoint    = flag.String("endpoint", "127.0.0.1:9000", "Node tchannel endpoint to back up [backup mode]")
+	optNamespace   = flag.String("namespace", "metrics", "Namespace to back up [backup mode]")
+	optTimeout     = flag.Duration("timeout", time.Hour, "Timeout of the backup request [backup mode]")
+	optPathPrefix  = flag.String("path-prefix", "/var/lib/m3db", "Path prefix to restore into [restore mode]")
+	optBackupID    = flag.String("backup-id", "", "Backup ID")
+	optDestination = flag.String("destination", "", "Backup destination, a local path or file:// URL")
+)
+
+func main() {
+	flag.Parse()
+	if *optBackupID == "" ||
+		*optDestination == "" ||
+		(*optMode != modeBackup && *optMode != modeRestore) {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	rawLogger, err := zap.NewDevelopment()
+	if err != nil {
+		log.Fatalf("unable to create logger: %+v", err)
+	}
+	logger := rawLogger.Sugar()
+
+	switch *optMode {
+	case modeBackup:
+		if *optEndpoint == "" || *optNamespace == "" {
+			flag.Usage()
+			os.Exit(1)
+		}
+
+		channel, err := tchannel.NewChannel("backup", nil)
+		if err != nil {
+			logger.Fatalf("unable to create tchannel: %v", err)
+		}
+		defer channel.Close()
+
+		endpoint := &thrift.ClientOptions{HostPort: *optEndpoint}
+		client := rpc.NewTChanNodeClient(thrift.NewClient(channel, nchannel.ChannelName, endpoint))
+
+		ctx, cancel := thrift.NewContext(*optTimeout)
+		defer cancel()
+
+		logger.Infof("backing up namespace %s of %s to %s", *optNamespace, *optEndpoint, *optDestination)
+		result, err := client.Backup(ctx, &rpc.BackupRequest{
+			NameSpace:   []byte(*optNamespace),
+			BackupId:    *optBackupID,
+			Destination: *optDestination,
+		})
+		if err != nil {
+			logger.Fatalf("unable to backup: %v", err)
+		}
+
+		logger.Infof("successfully backed up %d files (%d bytes) as %s",
+			result.NumFiles, result.NumBytes, *optBackupID)
+
+	case modeRestore:
+		if *optPathPrefix == "" {
+			flag.Usage()
+			os.Exit(1)
+		}
+
+		store, err := backup.NewBlobStore(*optDestination)
+		if err != nil {
+			logger.Fatalf("unable to create blob store: %v", err)
+		}
+
+		logger.Infof("restoring %s from %s into %s", *optBackupID, *optDestination, *optPathPrefix)
+		opts := fs.NewOptions().SetFilePathPrefix(*optPathPrefix)
+		manifest, err := backup.Restore(store, *optBackupID, opts)
+		if err != nil {
+			logger.Fatalf("unable to restore: %v", err)
+		}
+
+		logger.Infof("successfully restored %d files of namespace %s, shards: %v",
+			len(manifest.Files), manifest.Namespace, manifest.Shards)
+	}
+}
@@ -38,6 +38,7 @@ type aggregateAttempt struct {
 	args             aggregateAttemptArgs
 	resultIter       AggregatedTagsIterator
 	resultExhaustive bool
+	resultNextPage   index.PageToken
 }
 
 type aggregateAttemptArgs struct {
@@ -50,11 +51,12 @@ func (f *aggregateAttempt) reset() {
 	f.args = aggregateAttemptArgsZeroed
 	f.resultIter = nil
 	f.resultExhaustive = false
+	f.resultNextPage = nil
 }
 
 func (f *aggregateAttempt) performAttempt() error {
 	var err error
-	f.resultIter, f.resultExhaustive, err = f.session.aggregateAttempt(
+	f.resultIter, f.resultExhaustive, f.resultNextPage, err = f.session.aggregateAttempt(
 		f.args.ns, f.args.query, f.args.opts)
 	return err
 }
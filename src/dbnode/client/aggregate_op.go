@@ -45,8 +45,15 @@ func (f *aggregateOp) update(req rpc.AggregateQueryRawRequest, fn completionFn)
 	f.completionFn = fn
 }
 
+func (f *aggregateOp) paginated() bool {
+	return f.request.PageToken != nil
+}
+
 func (f *aggregateOp) requestLimit(defaultValue int) int {
-	if f.request.Limit == nil {
+	if f.request.Limit == nil || f.paginated() {
+		// NB: the terms of every host are kept in full since a page holds
+		// the terms of the series up to the end of the page, which the
+		// limit would otherwise cut short.
 		return defaultValue
 	}
 	return int(*f.request.Limit)
@@ -228,6 +228,22 @@ func (mr *MockSessionMockRecorder) FetchTagged(namespace, q, opts interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchTagged", reflect.TypeOf((*MockSession)(nil).FetchTagged), namespace, q, opts)
 }
 
+// FetchTaggedPage mocks base method
+func (m *MockSession) FetchTaggedPage(namespace ident.ID, q index.Query, opts index.QueryOptions) (encoding.SeriesIterators, index.PageToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchTaggedPage", namespace, q, opts)
+	ret0, _ := ret[0].(encoding.SeriesIterators)
+	ret1, _ := ret[1].(index.PageToken)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// FetchTaggedPage indicates an expected call of FetchTaggedPage
+func (mr *MockSessionMockRecorder) FetchTaggedPage(namespace, q, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchTaggedPage", reflect.TypeOf((*MockSession)(nil).FetchTaggedPage), namespace, q, opts)
+}
+
 // FetchTaggedIDs mocks base method
 func (m *MockSession) FetchTaggedIDs(namespace ident.ID, q index.Query, opts index.QueryOptions) (TaggedIDsIterator, bool, error) {
 	m.ctrl.T.Helper()
@@ -244,6 +260,22 @@ func (mr *MockSessionMockRecorder) FetchTaggedIDs(namespace, q, opts interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchTaggedIDs", reflect.TypeOf((*MockSession)(nil).FetchTaggedIDs), namespace, q, opts)
 }
 
+// FetchTaggedIDsPage mocks base method
+func (m *MockSession) FetchTaggedIDsPage(namespace ident.ID, q index.Query, opts index.QueryOptions) (TaggedIDsIterator, index.PageToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchTaggedIDsPage", namespace, q, opts)
+	ret0, _ := ret[0].(TaggedIDsIterator)
+	ret1, _ := ret[1].(index.PageToken)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// FetchTaggedIDsPage indicates an expected call of FetchTaggedIDsPage
+func (mr *MockSessionMockRecorder) FetchTaggedIDsPage(namespace, q, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchTaggedIDsPage", reflect.TypeOf((*MockSession)(nil).FetchTaggedIDsPage), namespace, q, opts)
+}
+
 // Aggregate mocks base method
 func (m *MockSession) Aggregate(namespace ident.ID, q index.Query, opts index.AggregationOptions) (AggregatedTagsIterator, bool, error) {
 	m.ctrl.T.Helper()
@@ -260,6 +292,37 @@ func (mr *MockSessionMockRecorder) Aggregate(namespace, q, opts interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Aggregate", reflect.TypeOf((*MockSession)(nil).Aggregate), namespace, q, opts)
 }
 
+// AggregatePage mocks base method
+func (m *MockSession) AggregatePage(namespace ident.ID, q index.Query, opts index.AggregationOptions) (AggregatedTagsIterator, index.PageToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AggregatePage", namespace, q, opts)
+	ret0, _ := ret[0].(AggregatedTagsIterator)
+	ret1, _ := ret[1].(index.PageToken)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// AggregatePage indicates an expected call of AggregatePage
+func (mr *MockSessionMockRecorder) AggregatePage(namespace, q, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AggregatePage", reflect.TypeOf((*MockSession)(nil).AggregatePage), namespace, q, opts)
+}
+
+// IndexCardinality mocks base method
+func (m *MockSession) IndexCardinality(namespace ident.ID, opts index.CardinalityOptions) (index.CardinalityResults, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IndexCardinality", namespace, opts)
+	ret0, _ := ret[0].(index.CardinalityResults)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IndexCardinality indicates an expected call of IndexCardinality
+func (mr *MockSessionMockRecorder) IndexCardinality(namespace, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IndexCardinality", reflect.TypeOf((*MockSession)(nil).IndexCardinality), namespace, opts)
+}
+
 // ShardID mocks base method
 func (m *MockSession) ShardID(id ident.ID) (uint32, error) {
 	m.ctrl.T.Helper()
@@ -816,6 +879,22 @@ func (mr *MockAdminSessionMockRecorder) FetchTagged(namespace, q, opts interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchTagged", reflect.TypeOf((*MockAdminSession)(nil).FetchTagged), namespace, q, opts)
 }
 
+// FetchTaggedPage mocks base method
+func (m *MockAdminSession) FetchTaggedPage(namespace ident.ID, q index.Query, opts index.QueryOptions) (encoding.SeriesIterators, index.PageToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchTaggedPage", namespace, q, opts)
+	ret0, _ := ret[0].(encoding.SeriesIterators)
+	ret1, _ := ret[1].(index.PageToken)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// FetchTaggedPage indicates an expected call of FetchTaggedPage
+func (mr *MockAdminSessionMockRecorder) FetchTaggedPage(namespace, q, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchTaggedPage", reflect.TypeOf((*MockAdminSession)(nil).FetchTaggedPage), namespace, q, opts)
+}
+
 // FetchTaggedIDs mocks base method
 func (m *MockAdminSession) FetchTaggedIDs(namespace ident.ID, q index.Query, opts index.QueryOptions) (TaggedIDsIterator, bool, error) {
 	m.ctrl.T.Helper()
@@ -832,6 +911,22 @@ func (mr *MockAdminSessionMockRecorder) FetchTaggedIDs(namespace, q, opts interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchTaggedIDs", reflect.TypeOf((*MockAdminSession)(nil).FetchTaggedIDs), namespace, q, opts)
 }
 
+// FetchTaggedIDsPage mocks base method
+func (m *MockAdminSession) FetchTaggedIDsPage(namespace ident.ID, q index.Query, opts index.QueryOptions) (TaggedIDsIterator, index.PageToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchTaggedIDsPage", namespace, q, opts)
+	ret0, _ := ret[0].(TaggedIDsIterator)
+	ret1, _ := ret[1].(index.PageToken)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// FetchTaggedIDsPage indicates an expected call of FetchTaggedIDsPage
+func (mr *MockAdminSessionMockRecorder) FetchTaggedIDsPage(namespace, q, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchTaggedIDsPage", reflect.TypeOf((*MockAdminSession)(nil).FetchTaggedIDsPage), namespace, q, opts)
+}
+
 // Aggregate mocks base method
 func (m *MockAdminSession) Aggregate(namespace ident.ID, q index.Query, opts index.AggregationOptions) (AggregatedTagsIterator, bool, error) {
 	m.ctrl.T.Helper()
@@ -848,6 +943,37 @@ func (mr *MockAdminSessionMockRecorder) Aggregate(namespace, q, opts interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Aggregate", reflect.TypeOf((*MockAdminSession)(nil).Aggregate), namespace, q, opts)
 }
 
+// AggregatePage mocks base method
+func (m *MockAdminSession) AggregatePage(namespace ident.ID, q index.Query, opts index.AggregationOptions) (AggregatedTagsIterator, index.PageToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AggregatePage", namespace, q, opts)
+	ret0, _ := ret[0].(AggregatedTagsIterator)
+	ret1, _ := ret[1].(index.PageToken)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// AggregatePage indicates an expected call of AggregatePage
+func (mr *MockAdminSessionMockRecorder) AggregatePage(namespace, q, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AggregatePage", reflect.TypeOf((*MockAdminSession)(nil).AggregatePage), namespace, q, opts)
+}
+
+// IndexCardinality mocks base method
+func (m *MockAdminSession) IndexCardinality(namespace ident.ID, opts index.CardinalityOptions) (index.CardinalityResults, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IndexCardinality", namespace, opts)
+	ret0, _ := ret[0].(index.CardinalityResults)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IndexCardinality indicates an expected call of IndexCardinality
+func (mr *MockAdminSessionMockRecorder) IndexCardinality(namespace, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IndexCardinality", reflect.TypeOf((*MockAdminSession)(nil).IndexCardinality), namespace, opts)
+}
+
 // ShardID mocks base method
 func (m *MockAdminSession) ShardID(id ident.ID) (uint32, error) {
 	m.ctrl.T.Helper()
@@ -2377,6 +2503,34 @@ func (mr *MockOptionsMockRecorder) UseV2BatchAPIs() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseV2BatchAPIs", reflect.TypeOf((*MockOptions)(nil).UseV2BatchAPIs))
 }
 
+// SetFetchTaggedDictionaryEncodedTags mocks base method
+func (m *MockOptions) SetFetchTaggedDictionaryEncodedTags(value bool) Options {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetFetchTaggedDictionaryEncodedTags", value)
+	ret0, _ := ret[0].(Options)
+	return ret0
+}
+
+// SetFetchTaggedDictionaryEncodedTags indicates an expected call of SetFetchTaggedDictionaryEncodedTags
+func (mr *MockOptionsMockRecorder) SetFetchTaggedDictionaryEncodedTags(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetFetchTaggedDictionaryEncodedTags", reflect.TypeOf((*MockOptions)(nil).SetFetchTaggedDictionaryEncodedTags), value)
+}
+
+// FetchTaggedDictionaryEncodedTags mocks base method
+func (m *MockOptions) FetchTaggedDictionaryEncodedTags() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchTaggedDictionaryEncodedTags")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// FetchTaggedDictionaryEncodedTags indicates an expected call of FetchTaggedDictionaryEncodedTags
+func (mr *MockOptionsMockRecorder) FetchTaggedDictionaryEncodedTags() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchTaggedDictionaryEncodedTags", reflect.TypeOf((*MockOptions)(nil).FetchTaggedDictionaryEncodedTags))
+}
+
 // MockAdminOptions is a mock of AdminOptions interface
 type MockAdminOptions struct {
 	ctrl     *gomock.Controller
@@ -3744,6 +3898,34 @@ func (mr *MockAdminOptionsMockRecorder) UseV2BatchAPIs() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseV2BatchAPIs", reflect.TypeOf((*MockAdminOptions)(nil).UseV2BatchAPIs))
 }
 
+// SetFetchTaggedDictionaryEncodedTags mocks base method
+func (m *MockAdminOptions) SetFetchTaggedDictionaryEncodedTags(value bool) Options {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetFetchTaggedDictionaryEncodedTags", value)
+	ret0, _ := ret[0].(Options)
+	return ret0
+}
+
+// SetFetchTaggedDictionaryEncodedTags indicates an expected call of SetFetchTaggedDictionaryEncodedTags
+func (mr *MockAdminOptionsMockRecorder) SetFetchTaggedDictionaryEncodedTags(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetFetchTaggedDictionaryEncodedTags", reflect.TypeOf((*MockAdminOptions)(nil).SetFetchTaggedDictionaryEncodedTags), value)
+}
+
+// FetchTaggedDictionaryEncodedTags mocks base method
+func (m *MockAdminOptions) FetchTaggedDictionaryEncodedTags() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchTaggedDictionaryEncodedTags")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// FetchTaggedDictionaryEncodedTags indicates an expected call of FetchTaggedDictionaryEncodedTags
+func (mr *MockAdminOptionsMockRecorder) FetchTaggedDictionaryEncodedTags() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchTaggedDictionaryEncodedTags", reflect.TypeOf((*MockAdminOptions)(nil).FetchTaggedDictionaryEncodedTags))
+}
+
 // SetOrigin mocks base method
 func (m *MockAdminOptions) SetOrigin(value topology.Host) AdminOptions {
 	m.ctrl.T.Helper()
@@ -4065,6 +4247,22 @@ func (mr *MockclientSessionMockRecorder) FetchTagged(namespace, q, opts interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchTagged", reflect.TypeOf((*MockclientSession)(nil).FetchTagged), namespace, q, opts)
 }
 
+// FetchTaggedPage mocks base method
+func (m *MockclientSession) FetchTaggedPage(namespace ident.ID, q index.Query, opts index.QueryOptions) (encoding.SeriesIterators, index.PageToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchTaggedPage", namespace, q, opts)
+	ret0, _ := ret[0].(encoding.SeriesIterators)
+	ret1, _ := ret[1].(index.PageToken)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// FetchTaggedPage indicates an expected call of FetchTaggedPage
+func (mr *MockclientSessionMockRecorder) FetchTaggedPage(namespace, q, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchTaggedPage", reflect.TypeOf((*MockclientSession)(nil).FetchTaggedPage), namespace, q, opts)
+}
+
 // FetchTaggedIDs mocks base method
 func (m *MockclientSession) FetchTaggedIDs(namespace ident.ID, q index.Query, opts index.QueryOptions) (TaggedIDsIterator, bool, error) {
 	m.ctrl.T.Helper()
@@ -4081,6 +4279,22 @@ func (mr *MockclientSessionMockRecorder) FetchTaggedIDs(namespace, q, opts inter
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchTaggedIDs", reflect.TypeOf((*MockclientSession)(nil).FetchTaggedIDs), namespace, q, opts)
 }
 
+// FetchTaggedIDsPage mocks base method
+func (m *MockclientSession) FetchTaggedIDsPage(namespace ident.ID, q index.Query, opts index.QueryOptions) (TaggedIDsIterator, index.PageToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchTaggedIDsPage", namespace, q, opts)
+	ret0, _ := ret[0].(TaggedIDsIterator)
+	ret1, _ := ret[1].(index.PageToken)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// FetchTaggedIDsPage indicates an expected call of FetchTaggedIDsPage
+func (mr *MockclientSessionMockRecorder) FetchTaggedIDsPage(namespace, q, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchTaggedIDsPage", reflect.TypeOf((*MockclientSession)(nil).FetchTaggedIDsPage), namespace, q, opts)
+}
+
 // Aggregate mocks base method
 func (m *MockclientSession) Aggregate(namespace ident.ID, q index.Query, opts index.AggregationOptions) (AggregatedTagsIterator, bool, error) {
 	m.ctrl.T.Helper()
@@ -4097,6 +4311,37 @@ func (mr *MockclientSessionMockRecorder) Aggregate(namespace, q, opts interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Aggregate", reflect.TypeOf((*MockclientSession)(nil).Aggregate), namespace, q, opts)
 }
 
+// AggregatePage mocks base method
+func (m *MockclientSession) AggregatePage(namespace ident.ID, q index.Query, opts index.AggregationOptions) (AggregatedTagsIterator, index.PageToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AggregatePage", namespace, q, opts)
+	ret0, _ := ret[0].(AggregatedTagsIterator)
+	ret1, _ := ret[1].(index.PageToken)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// AggregatePage indicates an expected call of AggregatePage
+func (mr *MockclientSessionMockRecorder) AggregatePage(namespace, q, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AggregatePage", reflect.TypeOf((*MockclientSession)(nil).AggregatePage), namespace, q, opts)
+}
+
+// IndexCardinality mocks base method
+func (m *MockclientSession) IndexCardinality(namespace ident.ID, opts index.CardinalityOptions) (index.CardinalityResults, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IndexCardinality", namespace, opts)
+	ret0, _ := ret[0].(index.CardinalityResults)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IndexCardinality indicates an expected call of IndexCardinality
+func (mr *MockclientSessionMockRecorder) IndexCardinality(namespace, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IndexCardinality", reflect.TypeOf((*MockclientSession)(nil).IndexCardinality), namespace, opts)
+}
+
 // ShardID mocks base method
 func (m *MockclientSession) ShardID(id ident.ID) (uint32, error) {
 	m.ctrl.T.Helper()
@@ -27,6 +27,7 @@ import (
 	"time"
 
 	"github.com/m3db/m3/src/dbnode/encoding"
+	"github.com/m3db/m3/src/dbnode/encoding/histogram"
 	"github.com/m3db/m3/src/dbnode/encoding/m3tsz"
 	"github.com/m3db/m3/src/dbnode/environment"
 	"github.com/m3db/m3/src/dbnode/namespace"
@@ -100,6 +101,10 @@ type Configuration struct {
 	// UseV2BatchAPIs determines whether the V2 batch APIs are used. Note that the M3DB nodes must
 	// have support for the V2 APIs in order for this feature to be used.
 	UseV2BatchAPIs *bool `yaml:"useV2BatchAPIs"`
+
+	// FetchTaggedDictionaryEncodedTags determines whether fetch tagged requests ask
+	// for dictionary encoded tags to reduce the size of the responses.
+	FetchTaggedDictionaryEncodedTags *bool `yaml:"fetchTaggedDictionaryEncodedTags"`
 }
 
 // ProtoConfiguration is the configuration for running with ProtoDataMode enabled.
@@ -304,6 +309,10 @@ func (c Configuration) NewAdminClient(
 		v = v.SetUseV2BatchAPIs(*c.UseV2BatchAPIs)
 	}
 
+	if c.FetchTaggedDictionaryEncodedTags != nil {
+		v = v.SetFetchTaggedDictionaryEncodedTags(*c.FetchTaggedDictionaryEncodedTags)
+	}
+
 	if buildAsyncPool {
 		var size int
 		if c.AsyncWriteWorkerPoolSize == nil {
@@ -372,7 +381,7 @@ func (c Configuration) NewAdminClient(
 
 	v = v.SetReaderIteratorAllocate(func(r io.Reader, _ namespace.SchemaDescr) encoding.ReaderIterator {
 		intOptimized := m3tsz.DefaultIntOptimizationEnabled
-		return m3tsz.NewReaderIterator(r, intOptimized, encodingOpts)
+		return histogram.NewReaderIterator(r, intOptimized, encodingOpts)
 	})
 
 	if c.Proto != nil && c.Proto.Enabled {
@@ -52,3 +52,15 @@ func TestConsistencyResultError(t *testing.T) {
 	assert.Equal(t, 1, NumSuccess(err))
 	assert.Equal(t, 2, NumError(err))
 }
+
+func TestResourceExhaustedError(t *testing.T) {
+	exhaustedErr := xerrors.NewRenamedError(&rpc.Error{
+		Type: rpc.ErrorType_RESOURCE_EXHAUSTED,
+	}, fmt.Errorf("renamed error"))
+
+	assert.True(t, IsResourceExhaustedError(exhaustedErr))
+	assert.False(t, IsBadRequestError(exhaustedErr))
+	assert.False(t, IsResourceExhaustedError(&rpc.Error{
+		Type: rpc.ErrorType_INTERNAL_ERROR,
+	}))
+}
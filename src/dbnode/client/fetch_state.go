@@ -27,6 +27,7 @@ import (
 	"time"
 
 	"github.com/m3db/m3/src/dbnode/encoding"
+	"github.com/m3db/m3/src/dbnode/storage/index"
 	"github.com/m3db/m3/src/dbnode/topology"
 	"github.com/m3db/m3/src/dbnode/x/xpool"
 	"github.com/m3db/m3/src/x/serialize"
@@ -115,6 +116,7 @@ func (f *fetchState) ResetFetchTagged(
 	f.fetchTaggedOp = op
 	f.stateType = fetchTaggedFetchState
 	f.tagResultAccumulator.Reset(startTime, endTime, topoMap, majority, consistencyLevel)
+	f.tagResultAccumulator.SetPaginated(op.paginated())
 }
 
 func (f *fetchState) ResetAggregate(
@@ -128,6 +130,7 @@ func (f *fetchState) ResetAggregate(
 	f.aggregateOp = op
 	f.stateType = aggregateFetchState
 	f.tagResultAccumulator.Reset(startTime, endTime, topoMap, majority, consistencyLevel)
+	f.tagResultAccumulator.SetPaginated(op.paginated())
 }
 
 func (f *fetchState) completionFn(
@@ -241,6 +244,21 @@ func (f *fetchState) asAggregatedTagsIterator(pools fetchTaggedPools) (Aggregate
 	return f.tagResultAccumulator.AsAggregatedTagsIterator(limit, pools)
 }
 
+func (f *fetchState) nextPageToken() (index.PageToken, error) {
+	f.Lock()
+	defer f.Unlock()
+
+	if !f.done {
+		return nil, errFetchStateStillProcessing
+	}
+
+	if err := f.err; err != nil {
+		return nil, err
+	}
+
+	return f.tagResultAccumulator.NextPageToken(), nil
+}
+
 // NB(prateek): this is backed by the sessionPools struct, but we're restricting it to a narrow
 // interface to force the fetchTagged code-paths to be explicit about the pools they need access
 // to. The alternative is to either expose the sessionPools struct (which is a worse abstraction),
@@ -255,4 +273,5 @@ type fetchTaggedPools interface {
 	ID() ident.Pool
 	ReaderSliceOfSlicesIterator() *readerSliceOfSlicesIteratorPool
 	TagDecoder() serialize.TagDecoderPool
+	TagDecoderOptions() serialize.TagDecoderOptions
 }
@@ -42,6 +42,8 @@ type fetchTaggedAttempt struct {
 	dataResultIters      encoding.SeriesIterators
 	idsResultExhaustive  bool
 	dataResultExhaustive bool
+	idsResultNextPage    index.PageToken
+	dataResultNextPage   index.PageToken
 }
 
 type fetchTaggedAttemptArgs struct {
@@ -57,18 +59,20 @@ func (f *fetchTaggedAttempt) reset() {
 	f.idsResultExhaustive = false
 	f.dataResultIters = nil
 	f.dataResultExhaustive = false
+	f.idsResultNextPage = nil
+	f.dataResultNextPage = nil
 }
 
 func (f *fetchTaggedAttempt) performIDsAttempt() error {
 	var err error
-	f.idsResultIter, f.idsResultExhaustive, err = f.session.fetchTaggedIDsAttempt(
+	f.idsResultIter, f.idsResultExhaustive, f.idsResultNextPage, err = f.session.fetchTaggedIDsAttempt(
 		f.args.ns, f.args.query, f.args.opts)
 	return err
 }
 
 func (f *fetchTaggedAttempt) performDataAttempt() error {
 	var err error
-	f.dataResultIters, f.dataResultExhaustive, err = f.session.fetchTaggedAttempt(
+	f.dataResultIters, f.dataResultExhaustive, f.dataResultNextPage, err = f.session.fetchTaggedAttempt(
 		f.args.ns, f.args.query, f.args.opts)
 	return err
 }
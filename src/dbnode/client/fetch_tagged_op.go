@@ -45,6 +45,10 @@ func (f *fetchTaggedOp) update(req rpc.FetchTaggedRequest, fn completionFn) {
 	f.completionFn = fn
 }
 
+func (f *fetchTaggedOp) paginated() bool {
+	return f.request.PageToken != nil
+}
+
 func (f *fetchTaggedOp) requestLimit(defaultValue int) int {
 	if f.request.Limit == nil {
 		return defaultValue
@@ -29,10 +29,13 @@ import (
 	"github.com/m3db/m3/src/cluster/shard"
 	"github.com/m3db/m3/src/dbnode/encoding"
 	"github.com/m3db/m3/src/dbnode/generated/thrift/rpc"
+	"github.com/m3db/m3/src/dbnode/storage/index"
 	"github.com/m3db/m3/src/dbnode/topology"
 	xerrors "github.com/m3db/m3/src/x/errors"
 	"github.com/m3db/m3/src/x/ident"
 	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/x/serialize"
+	xtime "github.com/m3db/m3/src/x/time"
 )
 
 type fetchTaggedResultAccumulatorOpts struct {
@@ -64,6 +67,25 @@ type fetchTaggedResultAccumulator struct {
 	aggResponses   aggregateResults
 	exhaustive     bool
 
+	// paginated is set when the fetch is for a single page of a query. The
+	// page ends at the smallest last ID of the pages of the hosts that have
+	// not been exhausted, since the hosts may hold more series before the
+	// last ID of the pages of the other hosts.
+	paginated  bool
+	hasPageEnd bool
+	pageEnd    []byte
+	// pageLastID is the last ID of the page when it is cut short by the limit.
+	pageLastID []byte
+	// pageFinishedBlocks are the blocks every host finished at the end of the
+	// page, they are only carried to the next page if every host ended its
+	// page at the same ID.
+	pageFinishedBlocks []xtime.UnixNano
+	pageBlocksDiffer   bool
+
+	// tagDictionaries are the tag dictionaries of the responses whose
+	// elements reference their tags from a dictionary, keyed by element.
+	tagDictionaries map[*rpc.FetchTaggedIDResult_]*fetchTaggedTagDictionary
+
 	startTime        time.Time
 	endTime          time.Time
 	majority         int
@@ -86,11 +108,20 @@ func (accum *fetchTaggedResultAccumulator) AddFetchTaggedResponse(
 	opts fetchTaggedResultAccumulatorOpts,
 	resultErr error,
 ) (bool, error) {
+	if opts.response != nil && resultErr == nil {
+		resultErr = accum.addPageToken(opts.response.NextPageToken)
+	}
+	if resultErr != nil {
+		// NB: the blocks finished by the other hosts may not be finished by
+		// the host that failed.
+		accum.pageBlocksDiffer = true
+	}
 	if opts.response != nil && resultErr == nil {
 		accum.exhaustive = accum.exhaustive && opts.response.Exhaustive
 		for _, elem := range opts.response.Elements {
 			accum.fetchResponses = append(accum.fetchResponses, elem)
 		}
+		accum.addTagDictionary(opts.response)
 	}
 
 	return accum.accumulatedResult(opts.host, resultErr)
@@ -100,6 +131,9 @@ func (accum *fetchTaggedResultAccumulator) AddAggregateResponse(
 	opts aggregateResultAccumulatorOpts,
 	resultErr error,
 ) (bool, error) {
+	if opts.response != nil && resultErr == nil {
+		resultErr = accum.addPageToken(opts.response.NextPageToken)
+	}
 	if opts.response != nil && resultErr == nil {
 		accum.exhaustive = accum.exhaustive && opts.response.Exhaustive
 		for _, elem := range opts.response.Results {
@@ -109,6 +143,146 @@ func (accum *fetchTaggedResultAccumulator) AddAggregateResponse(
 	return accum.accumulatedResult(opts.host, resultErr)
 }
 
+func (accum *fetchTaggedResultAccumulator) addTagDictionary(
+	response *rpc.FetchTaggedResult_,
+) {
+	// NB: hosts that do not support dictionary encoded tags ignore the
+	// request for them and respond without a dictionary.
+	if response.TagDictionary == nil {
+		return
+	}
+	if accum.tagDictionaries == nil {
+		accum.tagDictionaries = make(map[*rpc.FetchTaggedIDResult_]*fetchTaggedTagDictionary)
+	}
+	dict := &fetchTaggedTagDictionary{data: response.TagDictionary}
+	for _, elem := range response.Elements {
+		accum.tagDictionaries[elem] = dict
+	}
+}
+
+// tagDecoder returns a decoder for the tags of an element, resolving the
+// tags by reference from the response's dictionary when it has one.
+func (accum *fetchTaggedResultAccumulator) tagDecoder(
+	pools fetchTaggedPools,
+	elem *rpc.FetchTaggedIDResult_,
+) (serialize.TagDecoder, error) {
+	dict, err := accum.tagDictionary(pools, elem)
+	if err != nil {
+		return nil, err
+	}
+	if dict == nil {
+		return pools.TagDecoder().Get(), nil
+	}
+	return serialize.NewTagDictionaryDecoder(dict, pools.TagDecoderOptions()), nil
+}
+
+func (accum *fetchTaggedResultAccumulator) tagDictionary(
+	pools fetchTaggedPools,
+	elem *rpc.FetchTaggedIDResult_,
+) (*serialize.TagDictionary, error) {
+	dict, ok := accum.tagDictionaries[elem]
+	if !ok || !serialize.IsDictionaryEncodedTags(elem.EncodedTags) {
+		return nil, nil
+	}
+	return dict.decode(pools.TagDecoderOptions())
+}
+
+// addPageToken narrows the page to end at the last ID of the page of a host,
+// hosts that have been exhausted return a nil token.
+func (accum *fetchTaggedResultAccumulator) addPageToken(token []byte) error {
+	if !accum.paginated {
+		return nil
+	}
+	if token == nil {
+		// NB: the series of an exhausted host after the end of the page are
+		// left for the next page, so its blocks may not be finished.
+		accum.pageBlocksDiffer = true
+		return nil
+	}
+
+	cursor, err := index.PageToken(token).QueryCursor()
+	if err != nil {
+		return xerrors.NewNonRetryableError(err)
+	}
+	if !accum.hasPageEnd {
+		accum.pageEnd = cursor.AfterID
+		accum.hasPageEnd = true
+		accum.pageFinishedBlocks = cursor.FinishedBlocks
+		return nil
+	}
+
+	switch cmp := bytes.Compare(cursor.AfterID, accum.pageEnd); {
+	case cmp < 0:
+		accum.pageEnd = cursor.AfterID
+		accum.pageBlocksDiffer = true
+	case cmp > 0:
+		accum.pageBlocksDiffer = true
+	default:
+		accum.pageFinishedBlocks = intersectBlocks(accum.pageFinishedBlocks,
+			cursor.FinishedBlocks)
+	}
+	return nil
+}
+
+// intersectBlocks returns the block starts that are in both a and b.
+func intersectBlocks(a, b []xtime.UnixNano) []xtime.UnixNano {
+	var result []xtime.UnixNano
+	for _, blockStart := range a {
+		for _, other := range b {
+			if blockStart == other {
+				result = append(result, blockStart)
+				break
+			}
+		}
+	}
+	return result
+}
+
+// inPage returns whether a series ID belongs to the page of a paginated fetch.
+func (accum *fetchTaggedResultAccumulator) inPage(id []byte) bool {
+	if !accum.paginated || !accum.hasPageEnd {
+		return true
+	}
+	return bytes.Compare(id, accum.pageEnd) <= 0
+}
+
+// SetPaginated sets whether the fetch is for a single page of a query.
+func (accum *fetchTaggedResultAccumulator) SetPaginated(paginated bool) {
+	accum.paginated = paginated
+}
+
+// NextPageToken returns the token to resume a paginated fetch from, it is nil
+// once the fetch has been exhausted.
+func (accum *fetchTaggedResultAccumulator) NextPageToken() index.PageToken {
+	if !accum.paginated {
+		return nil
+	}
+	if accum.pageLastID != nil {
+		return index.QueryCursor{AfterID: accum.pageLastID}.PageToken()
+	}
+	if accum.hasPageEnd {
+		cursor := index.QueryCursor{AfterID: accum.pageEnd}
+		if !accum.pageBlocksDiffer && accum.numHostsPending == 0 {
+			cursor.FinishedBlocks = accum.pageFinishedBlocks
+		}
+		return cursor.PageToken()
+	}
+	return nil
+}
+
+// setPageLastID records the last ID of the page of a paginated fetch when
+// the limit cuts the page short, the next page resumes after it.
+func (accum *fetchTaggedResultAccumulator) setPageLastID(
+	id []byte,
+	count int,
+	limit int,
+	hasMore bool,
+) {
+	if accum.paginated && count >= limit && hasMore {
+		accum.pageLastID = id
+	}
+}
+
 func (accum *fetchTaggedResultAccumulator) accumulatedResult(
 	host topology.Host,
 	resultErr error,
@@ -211,6 +385,12 @@ func (accum *fetchTaggedResultAccumulator) Clear() {
 	accum.majority, accum.numHostsPending, accum.numShardsPending = 0, 0, 0
 	accum.startTime, accum.endTime = time.Time{}, time.Time{}
 	accum.topoMap = nil
+	accum.paginated, accum.hasPageEnd = false, false
+	accum.pageEnd, accum.pageLastID = nil, nil
+	accum.pageFinishedBlocks, accum.pageBlocksDiffer = nil, false
+	for elem := range accum.tagDictionaries {
+		delete(accum.tagDictionaries, elem)
+	}
 	accum.exhaustive = true
 }
 
@@ -247,7 +427,17 @@ func (accum *fetchTaggedResultAccumulator) sliceResponsesAsSeriesIter(
 	pools fetchTaggedPools,
 	elems fetchTaggedIDResults,
 	descr namespace.SchemaDescr,
-) encoding.SeriesIterator {
+) (encoding.SeriesIterator, error) {
+	// pick the first element as they all have identical ids/tags
+	// NB: safe to assume this element exists as it's only called within
+	// a forEachID lambda, which provides the guarantee that len(elems) != 0
+	elem := elems[0]
+
+	decoder, err := accum.tagDecoder(pools, elem)
+	if err != nil {
+		return nil, err
+	}
+
 	numElems := len(elems)
 	iters := pools.MultiReaderIteratorArray().Get(numElems)[:numElems]
 	for idx, elem := range elems {
@@ -258,13 +448,7 @@ func (accum *fetchTaggedResultAccumulator) sliceResponsesAsSeriesIter(
 		iters[idx] = multiIter
 	}
 
-	// pick the first element as they all have identical ids/tags
-	// NB: safe to assume this element exists as it's only called within
-	// a forEachID lambda, which provides the guarantee that len(elems) != 0
-	elem := elems[0]
-
 	encodedTags := pools.CheckedBytesWrapper().Get(elem.EncodedTags)
-	decoder := pools.TagDecoder().Get()
 	decoder.Reset(encodedTags)
 
 	tsID := pools.CheckedBytesWrapper().Get(elem.ID)
@@ -279,7 +463,7 @@ func (accum *fetchTaggedResultAccumulator) sliceResponsesAsSeriesIter(
 		Replicas:       iters,
 	})
 
-	return seriesIter
+	return seriesIter, nil
 }
 
 func (accum *fetchTaggedResultAccumulator) AsEncodingSeriesIterators(
@@ -288,9 +472,13 @@ func (accum *fetchTaggedResultAccumulator) AsEncodingSeriesIterators(
 	results := fetchTaggedIDResultsSortedByID(accum.fetchResponses)
 	sort.Sort(results)
 	accum.fetchResponses = fetchTaggedIDResults(results)
+	accum.pageLastID = nil
 
 	numElements := 0
-	accum.fetchResponses.forEachID(func(_ fetchTaggedIDResults, _ bool) bool {
+	accum.fetchResponses.forEachID(func(elems fetchTaggedIDResults, _ bool) bool {
+		if !accum.inPage(elems[0].ID) {
+			return false
+		}
 		numElements++
 		return numElements < limit
 	})
@@ -299,13 +487,26 @@ func (accum *fetchTaggedResultAccumulator) AsEncodingSeriesIterators(
 	result.Reset(numElements)
 	count := 0
 	moreElems := false
+	var err error
 	accum.fetchResponses.forEachID(func(elems fetchTaggedIDResults, hasMore bool) bool {
-		seriesIter := accum.sliceResponsesAsSeriesIter(pools, elems, descr)
+		if !accum.inPage(elems[0].ID) {
+			return false
+		}
+		var seriesIter encoding.SeriesIterator
+		seriesIter, err = accum.sliceResponsesAsSeriesIter(pools, elems, descr)
+		if err != nil {
+			return false
+		}
 		result.SetAt(count, seriesIter)
 		count++
 		moreElems = hasMore
+		accum.setPageLastID(elems[0].ID, count, limit, hasMore)
 		return count < limit
 	})
+	if err != nil {
+		result.Close()
+		return nil, false, err
+	}
 
 	exhaustive := accum.exhaustive && count <= limit && !moreElems
 	return result, exhaustive, nil
@@ -323,12 +524,27 @@ func (accum *fetchTaggedResultAccumulator) AsTaggedIDsIterator(
 	results := fetchTaggedIDResultsSortedByID(accum.fetchResponses)
 	sort.Sort(results)
 	accum.fetchResponses = fetchTaggedIDResults(results)
+	accum.pageLastID = nil
+	var err error
 	accum.fetchResponses.forEachID(func(elems fetchTaggedIDResults, hasMore bool) bool {
-		iter.addBacking(elems[0].NameSpace, elems[0].ID, elems[0].EncodedTags)
+		if !accum.inPage(elems[0].ID) {
+			return false
+		}
+		var dict *serialize.TagDictionary
+		dict, err = accum.tagDictionary(pools, elems[0])
+		if err != nil {
+			return false
+		}
+		iter.addBacking(elems[0].NameSpace, elems[0].ID, elems[0].EncodedTags, dict)
 		count++
 		moreElems = hasMore
+		accum.setPageLastID(elems[0].ID, count, limit, hasMore)
 		return count < limit
 	})
+	if err != nil {
+		iter.Finalize()
+		return nil, false, err
+	}
 
 	exhaustive := accum.exhaustive && count <= limit && !moreElems
 	return iter, exhaustive, nil
@@ -456,6 +672,23 @@ func (res fetchTaggedShardConsistencyResults) initialize(length int) fetchTagged
 	return res
 }
 
+// fetchTaggedTagDictionary is the tag dictionary of a single fetchTagged
+// response, it is decoded at most once and shared by the response's elements.
+type fetchTaggedTagDictionary struct {
+	data []byte
+	dict *serialize.TagDictionary
+	err  error
+}
+
+func (d *fetchTaggedTagDictionary) decode(
+	opts serialize.TagDecoderOptions,
+) (*serialize.TagDictionary, error) {
+	if d.dict == nil && d.err == nil {
+		d.dict, _, d.err = serialize.DecodeTagDictionary(d.data, opts.TagSerializationLimits())
+	}
+	return d.dict, d.err
+}
+
 type fetchTaggedIDResults []*rpc.FetchTaggedIDResult_
 
 // lambda to iterate over fetchTagged responses a single id at a time, `hasMore` indicates
@@ -29,12 +29,14 @@ import (
 	"github.com/m3db/m3/src/dbnode/encoding"
 	"github.com/m3db/m3/src/dbnode/generated/thrift/rpc"
 	"github.com/m3db/m3/src/dbnode/network/server/tchannelthrift/convert"
+	"github.com/m3db/m3/src/dbnode/storage/index"
 	"github.com/m3db/m3/src/dbnode/topology"
 	"github.com/m3db/m3/src/dbnode/topology/testutil"
 	"github.com/m3db/m3/src/dbnode/ts"
 	"github.com/m3db/m3/src/dbnode/x/xio"
 	"github.com/m3db/m3/src/x/context"
 	"github.com/m3db/m3/src/x/ident"
+	"github.com/m3db/m3/src/x/serialize"
 	xtime "github.com/m3db/m3/src/x/time"
 
 	"github.com/stretchr/testify/assert"
@@ -140,6 +142,165 @@ func TestFetchTaggedResultsAccumulatorIdsMergeUnstrictMajority(t *testing.T) {
 	require.True(t, matcher.Matches(resultsIter))
 }
 
+func TestFetchTaggedResultsAccumulatorIdsMergePaginated(t *testing.T) {
+	// rf=3, 3 identical hosts, with same shards
+	topoMap := testutil.MustNewTopologyMap(3, map[string][]shard.Shard{
+		"testhost0": testutil.ShardsRange(0, 29, shard.Available),
+		"testhost1": testutil.ShardsRange(0, 29, shard.Available),
+		"testhost2": testutil.ShardsRange(0, 29, shard.Available),
+	})
+
+	th := newTestFetchTaggedHelper(t)
+	pageResult := func(ts testSerieses, lastID string) *rpc.FetchTaggedResult_ {
+		res := ts.toRPCResult(th, testStartTime, false)
+		res.NextPageToken = index.QueryCursor{AfterID: []byte(lastID)}.PageToken()
+		return res
+	}
+	workflow := testFetchStateWorkflow{
+		t:         t,
+		topoMap:   topoMap,
+		level:     topology.ReadConsistencyLevelAll,
+		paginated: true,
+		startTime: testStartTime,
+		endTime:   testEndTime,
+		steps: []testFetchStateWorklowStep{
+			testFetchStateWorklowStep{
+				hostname:          "testhost0",
+				fetchTaggedResult: pageResult(newTestSerieses(1, 5), "id005"),
+			},
+			testFetchStateWorklowStep{
+				hostname:          "testhost1",
+				fetchTaggedResult: pageResult(newTestSerieses(3, 8), "id008"),
+			},
+			testFetchStateWorklowStep{
+				hostname:          "testhost2",
+				fetchTaggedResult: newTestSerieses(2, 4).toRPCResult(th, testStartTime, true),
+				expectedDone:      true,
+			},
+		},
+	}
+	accum := workflow.run()
+
+	// the page ends at the last ID of the page of testhost0 since it may hold
+	// more series before the last ID of the page of testhost1.
+	resultsIter, _, err := accum.AsTaggedIDsIterator(10, th.pools)
+	require.NoError(t, err)
+	matcher := newTestSerieses(1, 5).indexMatcher()
+	require.True(t, matcher.Matches(resultsIter))
+
+	cursor, err := accum.NextPageToken().QueryCursor()
+	require.NoError(t, err)
+	require.Equal(t, []byte("id005"), cursor.AfterID)
+	require.Nil(t, cursor.FinishedBlocks)
+
+	// restrict to 3 elements, ensuring the next page resumes after the limit
+	resultsIter, _, err = accum.AsTaggedIDsIterator(3, th.pools)
+	require.NoError(t, err)
+	matcher = newTestSerieses(1, 3).indexMatcher()
+	require.True(t, matcher.Matches(resultsIter))
+
+	cursor, err = accum.NextPageToken().QueryCursor()
+	require.NoError(t, err)
+	require.Equal(t, []byte("id003"), cursor.AfterID)
+}
+
+func TestFetchTaggedResultsAccumulatorIdsMergePaginatedFinishedBlocks(t *testing.T) {
+	// rf=3, 3 identical hosts, with same shards
+	topoMap := testutil.MustNewTopologyMap(3, map[string][]shard.Shard{
+		"testhost0": testutil.ShardsRange(0, 29, shard.Available),
+		"testhost1": testutil.ShardsRange(0, 29, shard.Available),
+		"testhost2": testutil.ShardsRange(0, 29, shard.Available),
+	})
+
+	var (
+		th          = newTestFetchTaggedHelper(t)
+		blockSize   = 2 * time.Hour
+		firstBlock  = xtime.ToUnixNano(testStartTime)
+		secondBlock = xtime.ToUnixNano(testStartTime.Add(blockSize))
+		thirdBlock  = xtime.ToUnixNano(testStartTime.Add(2 * blockSize))
+	)
+	pageResult := func(ts testSerieses, blocks ...xtime.UnixNano) *rpc.FetchTaggedResult_ {
+		res := ts.toRPCResult(th, testStartTime, false)
+		res.NextPageToken = index.QueryCursor{
+			AfterID:        []byte("id005"),
+			FinishedBlocks: blocks,
+		}.PageToken()
+		return res
+	}
+	workflow := testFetchStateWorkflow{
+		t:         t,
+		topoMap:   topoMap,
+		level:     topology.ReadConsistencyLevelAll,
+		paginated: true,
+		startTime: testStartTime,
+		endTime:   testEndTime,
+		steps: []testFetchStateWorklowStep{
+			testFetchStateWorklowStep{
+				hostname:          "testhost0",
+				fetchTaggedResult: pageResult(newTestSerieses(1, 5), firstBlock, secondBlock),
+			},
+			testFetchStateWorklowStep{
+				hostname:          "testhost1",
+				fetchTaggedResult: pageResult(newTestSerieses(1, 5), secondBlock, firstBlock),
+			},
+			testFetchStateWorklowStep{
+				hostname:          "testhost2",
+				fetchTaggedResult: pageResult(newTestSerieses(1, 5), secondBlock, thirdBlock),
+				expectedDone:      true,
+			},
+		},
+	}
+	accum := workflow.run()
+
+	// only the blocks finished by every host are skipped by the next page.
+	cursor, err := accum.NextPageToken().QueryCursor()
+	require.NoError(t, err)
+	require.Equal(t, []byte("id005"), cursor.AfterID)
+	require.Equal(t, []xtime.UnixNano{secondBlock}, cursor.FinishedBlocks)
+}
+
+func TestFetchTaggedResultsAccumulatorIdsMergePaginatedExhausted(t *testing.T) {
+	// rf=3, 3 identical hosts, with same shards
+	topoMap := testutil.MustNewTopologyMap(3, map[string][]shard.Shard{
+		"testhost0": testutil.ShardsRange(0, 29, shard.Available),
+		"testhost1": testutil.ShardsRange(0, 29, shard.Available),
+		"testhost2": testutil.ShardsRange(0, 29, shard.Available),
+	})
+
+	th := newTestFetchTaggedHelper(t)
+	workflow := testFetchStateWorkflow{
+		t:         t,
+		topoMap:   topoMap,
+		level:     topology.ReadConsistencyLevelAll,
+		paginated: true,
+		startTime: testStartTime,
+		endTime:   testEndTime,
+		steps: []testFetchStateWorklowStep{
+			testFetchStateWorklowStep{
+				hostname:          "testhost0",
+				fetchTaggedResult: newTestSerieses(1, 5).toRPCResult(th, testStartTime, true),
+			},
+			testFetchStateWorklowStep{
+				hostname:          "testhost1",
+				fetchTaggedResult: newTestSerieses(3, 8).toRPCResult(th, testStartTime, true),
+			},
+			testFetchStateWorklowStep{
+				hostname:          "testhost2",
+				fetchTaggedResult: newTestSerieses(2, 4).toRPCResult(th, testStartTime, true),
+				expectedDone:      true,
+			},
+		},
+	}
+	accum := workflow.run()
+
+	resultsIter, resultsExhaustive, err := accum.AsTaggedIDsIterator(10, th.pools)
+	require.NoError(t, err)
+	require.True(t, resultsExhaustive)
+	matcher := newTestSerieses(1, 8).indexMatcher()
+	require.True(t, matcher.Matches(resultsIter))
+	require.Nil(t, accum.NextPageToken())
+}
+
 func TestFetchTaggedResultsAccumulatorIdsMergeReportsExhaustiveCorrectly(t *testing.T) {
 	// rf=3, 3 identical hosts, with same shards
 	topoMap := testutil.MustNewTopologyMap(3, map[string][]shard.Shard{
@@ -235,6 +396,66 @@ func TestFetchTaggedResultsAccumulatorSeriesItersDatapoints(t *testing.T) {
 	append(sg0, sg1...).assertMatchesEncodingIters(t, iters)
 }
 
+func TestFetchTaggedResultsAccumulatorDictionaryEncodedTags(t *testing.T) {
+	// rf=3, 3 identical hosts, with same shards
+	topoMap := testutil.MustNewTopologyMap(3, map[string][]shard.Shard{
+		"testhost0": testutil.ShardsRange(0, 29, shard.Available),
+		"testhost1": testutil.ShardsRange(0, 29, shard.Available),
+		"testhost2": testutil.ShardsRange(0, 29, shard.Available),
+	})
+
+	var (
+		sg0 = newTestSerieses(1, 5)
+		sg1 = newTestSerieses(4, 10)
+	)
+
+	var (
+		startTime = time.Now().Add(-time.Hour).Truncate(time.Hour)
+		endTime   = time.Now().Truncate(time.Hour)
+		numPoints = 100
+	)
+	sg0.addDatapoints(numPoints, startTime, endTime)
+	sg1.addDatapoints(numPoints, startTime, endTime)
+
+	// NB: only the first host encodes the tags against a dictionary, as hosts
+	// that do not support it respond with the tags inline.
+	th := newTestFetchTaggedHelper(t)
+	workflow := testFetchStateWorkflow{
+		t:         t,
+		topoMap:   topoMap,
+		level:     topology.ReadConsistencyLevelUnstrictMajority,
+		startTime: startTime,
+		endTime:   endTime,
+		steps: []testFetchStateWorklowStep{
+			testFetchStateWorklowStep{
+				hostname:          "testhost0",
+				fetchTaggedResult: sg0.toDictionaryRPCResult(th, startTime, true),
+			},
+			testFetchStateWorklowStep{
+				hostname:          "testhost1",
+				fetchTaggedResult: sg1.toRPCResult(th, startTime, true),
+				expectedDone:      true,
+			},
+		},
+	}
+	accum := workflow.run()
+
+	resultsIter, resultsExhaustive, err := accum.AsTaggedIDsIterator(100, th.pools)
+	require.NoError(t, err)
+	require.True(t, resultsExhaustive)
+	matcher := newTestSerieses(1, 10).indexMatcher()
+	require.True(t, matcher.Matches(resultsIter))
+
+	iters, exhaust, err := accum.AsEncodingSeriesIterators(100, th.pools, nil)
+	require.NoError(t, err)
+	require.True(t, exhaust)
+	require.Equal(t, 10, iters.Len())
+	for i, iter := range iters.Iters() {
+		require.True(t, ident.NewTagIterMatcher(
+			ident.NewTagsIterator(newTestTags(i+1))).Matches(iter.Tags()))
+	}
+}
+
 func TestFetchTaggedResultsAccumulatorSeriesItersDatapointsNSplit(t *testing.T) {
 	// rf=3, 3 identical hosts, with same shards
 	topoMap := testutil.MustNewTopologyMap(3, map[string][]shard.Shard{
@@ -295,6 +516,7 @@ type testFetchStateWorkflow struct {
 	t         *testing.T
 	topoMap   topology.Map
 	level     topology.ReadConsistencyLevel
+	paginated bool
 	startTime time.Time
 	endTime   time.Time
 	steps     []testFetchStateWorklowStep
@@ -316,6 +538,7 @@ func (tm testFetchStateWorkflow) run() fetchTaggedResultAccumulator {
 	accum = newFetchTaggedResultAccumulator()
 	accum.Clear()
 	accum.Reset(tm.startTime, tm.endTime, tm.topoMap, majority, tm.level)
+	accum.SetPaginated(tm.paginated)
 	for _, s := range tm.steps {
 		var (
 			done bool
@@ -434,6 +657,18 @@ func (ts testSerieses) toRPCResult(th testFetchTaggedHelper, start time.Time, ex
 	return res
 }
 
+func (ts testSerieses) toDictionaryRPCResult(th testFetchTaggedHelper, start time.Time, exhaustive bool) *rpc.FetchTaggedResult_ {
+	res := ts.toRPCResult(th, start, exhaustive)
+	dict := serialize.NewTagDictionaryBuilder(serialize.NewTagEncoderOptions())
+	for i, s := range ts {
+		encodedTags, err := dict.Encode(ident.NewTagsIterator(s.tags))
+		require.NoError(th.t, err)
+		res.Elements[i].EncodedTags = encodedTags
+	}
+	res.TagDictionary = dict.Dictionary()
+	return res
+}
+
 func (ts testSerieses) toRPCAggResultMap() map[string]map[string]struct{} {
 	aggedMap := make(map[string]map[string]struct{})
 	for _, s := range ts {
@@ -291,7 +291,8 @@ func initTestFetchTaggedPools() *testFetchTaggedPools {
 	pools.checkedBytesWrapper = xpool.NewCheckedBytesWrapperPool(opts)
 	pools.checkedBytesWrapper.Init()
 
-	pools.tagDecoder = serialize.NewTagDecoderPool(serialize.NewTagDecoderOptions(), opts)
+	pools.tagDecoderOpts = serialize.NewTagDecoderOptions()
+	pools.tagDecoder = serialize.NewTagDecoderPool(pools.tagDecoderOpts, opts)
 	pools.tagDecoder.Init()
 
 	return pools
@@ -309,6 +310,7 @@ type testFetchTaggedPools struct {
 	id                       ident.Pool
 	checkedBytesWrapper      xpool.CheckedBytesWrapperPool
 	tagDecoder               serialize.TagDecoderPool
+	tagDecoderOpts           serialize.TagDecoderOptions
 }
 
 func (p testFetchTaggedPools) ReaderSliceOfSlicesIterator() *readerSliceOfSlicesIteratorPool {
@@ -342,3 +344,7 @@ func (p testFetchTaggedPools) CheckedBytesWrapper() xpool.CheckedBytesWrapperPoo
 func (p testFetchTaggedPools) TagDecoder() serialize.TagDecoderPool {
 	return p.tagDecoder
 }
+
+func (p testFetchTaggedPools) TagDecoderOptions() serialize.TagDecoderOptions {
+	return p.tagDecoderOpts
+}
@@ -41,6 +41,10 @@ type taggedIDsIterator struct {
 		nses [][]byte
 		ids  [][]byte
 		tags [][]byte
+
+		// dicts are the tag dictionaries the tags reference, nil for
+		// tags that hold their literals inline.
+		dicts []*serialize.TagDictionary
 	}
 }
 
@@ -65,7 +69,12 @@ func (i *taggedIDsIterator) Next() bool {
 		return false
 	}
 
-	dec := i.pools.TagDecoder().Get()
+	var dec serialize.TagDecoder
+	if dict := i.backing.dicts[i.currentIdx]; dict != nil {
+		dec = serialize.NewTagDictionaryDecoder(dict, i.pools.TagDecoderOptions())
+	} else {
+		dec = i.pools.TagDecoder().Get()
+	}
 	wb := i.pools.CheckedBytesWrapper().Get(i.backing.tags[i.currentIdx])
 	dec.Reset(wb)
 
@@ -75,10 +84,14 @@ func (i *taggedIDsIterator) Next() bool {
 	return true
 }
 
-func (i *taggedIDsIterator) addBacking(nsID, tsID, tags []byte) {
+func (i *taggedIDsIterator) addBacking(
+	nsID, tsID, tags []byte,
+	dict *serialize.TagDictionary,
+) {
 	i.backing.nses = append(i.backing.nses, nsID)
 	i.backing.ids = append(i.backing.ids, tsID)
 	i.backing.tags = append(i.backing.tags, tags)
+	i.backing.dicts = append(i.backing.dicts, dict)
 }
 
 func (i *taggedIDsIterator) Finalize() {
@@ -86,6 +99,7 @@ func (i *taggedIDsIterator) Finalize() {
 	i.backing.nses = nil
 	i.backing.ids = nil
 	i.backing.tags = nil
+	i.backing.dicts = nil
 }
 
 func (i *taggedIDsIterator) release() {
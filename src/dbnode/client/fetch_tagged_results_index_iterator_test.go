@@ -70,7 +70,7 @@ func TestFetchTaggedResultsIndexIterator(t *testing.T) {
 				require.NoError(t, err)
 				data, ok := enc.Data()
 				require.True(t, ok)
-				iter.addBacking(ns.Bytes(), id.Bytes(), data.Bytes())
+				iter.addBacking(ns.Bytes(), id.Bytes(), data.Bytes(), nil)
 			}
 
 			// validate iter
@@ -0,0 +1,108 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package client
+
+import (
+	"github.com/m3db/m3/src/dbnode/generated/thrift/rpc"
+	"github.com/m3db/m3/src/dbnode/storage/index"
+)
+
+type indexCardinalityOp struct {
+	request      rpc.IndexCardinalityRequest
+	completionFn completionFn
+}
+
+func (o *indexCardinalityOp) Size() int {
+	// IndexCardinality is always a single op
+	return 1
+}
+
+func (o *indexCardinalityOp) CompletionFn() completionFn {
+	return o.completionFn
+}
+
+type termCardinalityKey struct {
+	field string
+	term  string
+}
+
+// mergeIndexCardinalityResults merges the cardinality results returned by
+// each host. Every series is indexed by each of its replicas so series counts
+// are summed and divided by the replication factor, while the number of
+// distinct values of a field is the largest seen by any host since values are
+// usually spread across all shards. Hosts only return their own top fields
+// and terms so the merged results are approximate when a limit is set.
+func mergeIndexCardinalityResults(
+	hostResults []index.CardinalityResults,
+	replicas int,
+	match index.CardinalityMatch,
+	limit int,
+) index.CardinalityResults {
+	if replicas < 1 {
+		replicas = 1
+	}
+
+	var (
+		numSeries int64
+		fields    = make(map[string]index.FieldCardinality)
+		terms     = make(map[termCardinalityKey]index.TermCardinality)
+	)
+	for _, r := range hostResults {
+		numSeries += r.NumSeries
+		for _, f := range r.Fields {
+			key := string(f.Field)
+			curr, ok := fields[key]
+			if !ok {
+				curr = index.FieldCardinality{Field: f.Field}
+			}
+			if f.NumValues > curr.NumValues {
+				curr.NumValues = f.NumValues
+			}
+			curr.NumSeries += f.NumSeries
+			fields[key] = curr
+		}
+		for _, t := range r.Terms {
+			key := termCardinalityKey{field: string(t.Field), term: string(t.Term)}
+			curr, ok := terms[key]
+			if !ok {
+				curr = index.TermCardinality{Field: t.Field, Term: t.Term}
+			}
+			curr.NumSeries += t.NumSeries
+			terms[key] = curr
+		}
+	}
+
+	result := index.CardinalityResults{
+		NumSeries: numSeries / int64(replicas),
+		Fields:    make([]index.FieldCardinality, 0, len(fields)),
+		Terms:     make([]index.TermCardinality, 0, len(terms)),
+	}
+	for _, f := range fields {
+		f.NumSeries /= int64(replicas)
+		result.Fields = append(result.Fields, f)
+	}
+	for _, t := range terms {
+		t.NumSeries /= int64(replicas)
+		result.Terms = append(result.Terms, t)
+	}
+
+	return index.RankCardinalityResults(result, match, limit)
+}
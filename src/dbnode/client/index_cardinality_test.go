@@ -0,0 +1,77 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package client
+
+import (
+	"testing"
+
+	"github.com/m3db/m3/src/dbnode/storage/index"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeIndexCardinalityResults(t *testing.T) {
+	hostResults := []index.CardinalityResults{
+		{
+			NumSeries: 4,
+			Fields: []index.FieldCardinality{
+				{Field: []byte("city"), NumValues: 2, NumSeries: 4},
+			},
+			Terms: []index.TermCardinality{
+				{Field: []byte("city"), Term: []byte("nyc"), NumSeries: 3},
+				{Field: []byte("city"), Term: []byte("sf"), NumSeries: 1},
+			},
+		},
+		{
+			NumSeries: 6,
+			Fields: []index.FieldCardinality{
+				{Field: []byte("city"), NumValues: 3, NumSeries: 4},
+				{Field: []byte("host"), NumValues: 2, NumSeries: 2},
+			},
+			Terms: []index.TermCardinality{
+				{Field: []byte("city"), Term: []byte("nyc"), NumSeries: 1},
+				{Field: []byte("city"), Term: []byte("sf"), NumSeries: 1},
+				{Field: []byte("city"), Term: []byte("la"), NumSeries: 2},
+				{Field: []byte("host"), Term: []byte("a"), NumSeries: 2},
+			},
+		},
+	}
+
+	require.Equal(t, index.CardinalityResults{
+		NumSeries: 5,
+		Fields: []index.FieldCardinality{
+			{Field: []byte("city"), NumValues: 3, NumSeries: 4},
+			{Field: []byte("host"), NumValues: 2, NumSeries: 1},
+		},
+		Terms: []index.TermCardinality{
+			{Field: []byte("city"), Term: []byte("nyc"), NumSeries: 2},
+			{Field: []byte("city"), Term: []byte("la"), NumSeries: 1},
+			{Field: []byte("city"), Term: []byte("sf"), NumSeries: 1},
+			{Field: []byte("host"), Term: []byte("a"), NumSeries: 1},
+		},
+	}, mergeIndexCardinalityResults(hostResults, 2, index.CardinalityMatch{}, 0))
+
+	limited := mergeIndexCardinalityResults(hostResults, 2, index.CardinalityMatch{}, 1)
+	require.Len(t, limited.Fields, 1)
+	require.Equal(t, "city", string(limited.Fields[0].Field))
+	require.Len(t, limited.Terms, 1)
+	require.Equal(t, "nyc", string(limited.Terms[0].Term))
+}
@@ -29,6 +29,7 @@ import (
 
 	"github.com/m3db/m3/src/dbnode/clock"
 	"github.com/m3db/m3/src/dbnode/encoding"
+	"github.com/m3db/m3/src/dbnode/encoding/histogram"
 	"github.com/m3db/m3/src/dbnode/encoding/m3tsz"
 	"github.com/m3db/m3/src/dbnode/encoding/proto"
 	"github.com/m3db/m3/src/dbnode/environment"
@@ -160,6 +161,11 @@ const (
 	// defaultUseV2BatchAPIs is the default setting for whether the v2 version of the batch APIs should
 	// be used.
 	defaultUseV2BatchAPIs = false
+
+	// defaultFetchTaggedDictionaryEncodedTags is the default setting for whether
+	// fetch tagged requests ask for dictionary encoded tags, nodes that do not
+	// support them ignore the request so it is safe to enable by default.
+	defaultFetchTaggedDictionaryEncodedTags = true
 )
 
 var (
@@ -259,6 +265,7 @@ type options struct {
 	asyncWriteWorkerPool                    xsync.PooledWorkerPool
 	asyncWriteMaxConcurrency                int
 	useV2BatchAPIs                          bool
+	fetchTaggedDictionaryEncodedTags        bool
 }
 
 // NewOptions creates a new set of client options with defaults
@@ -358,6 +365,7 @@ func newOptions() *options {
 		asyncTopologyInitializers:               []topology.Initializer{},
 		asyncWriteMaxConcurrency:                defaultAsyncWriteMaxConcurrency,
 		useV2BatchAPIs:                          defaultUseV2BatchAPIs,
+		fetchTaggedDictionaryEncodedTags:        defaultFetchTaggedDictionaryEncodedTags,
 	}
 	return opts.SetEncodingM3TSZ().(*options)
 }
@@ -396,7 +404,9 @@ func (o *options) Validate() error {
 func (o *options) SetEncodingM3TSZ() Options {
 	opts := *o
 	opts.readerIteratorAllocate = func(r io.Reader, _ namespace.SchemaDescr) encoding.ReaderIterator {
-		return m3tsz.NewReaderIterator(r, m3tsz.DefaultIntOptimizationEnabled, encoding.NewOptions())
+		// NB: The histogram iterator also reads M3TSZ streams, which lets the
+		// series of namespaces with histograms enabled be read as well.
+		return histogram.NewReaderIterator(r, m3tsz.DefaultIntOptimizationEnabled, encoding.NewOptions())
 	}
 	opts.isProtoEnabled = false
 	return &opts
@@ -954,3 +964,13 @@ func (o *options) SetUseV2BatchAPIs(value bool) Options {
 func (o *options) UseV2BatchAPIs() bool {
 	return o.useV2BatchAPIs
 }
+
+func (o *options) SetFetchTaggedDictionaryEncodedTags(value bool) Options {
+	opts := *o
+	opts.fetchTaggedDictionaryEncodedTags = value
+	return &opts
+}
+
+func (o *options) FetchTaggedDictionaryEncodedTags() bool {
+	return o.fetchTaggedDictionaryEncodedTags
+}
@@ -227,16 +227,39 @@ func (s replicatedSession) Aggregate(
 	return s.session.Aggregate(ns, q, opts)
 }
 
+// AggregatePage aggregates values from a single page of the series matching the given set of constraints.
+func (s replicatedSession) AggregatePage(
+	ns ident.ID, q index.Query, opts index.AggregationOptions,
+) (AggregatedTagsIterator, index.PageToken, error) {
+	return s.session.AggregatePage(ns, q, opts)
+}
+
 // FetchTagged resolves the provided query to known IDs, and fetches the data for them.
 func (s replicatedSession) FetchTagged(namespace ident.ID, q index.Query, opts index.QueryOptions) (results encoding.SeriesIterators, exhaustive bool, err error) {
 	return s.session.FetchTagged(namespace, q, opts)
 }
 
+// FetchTaggedPage resolves the provided query to known IDs, and fetches the data for a single page of them.
+func (s replicatedSession) FetchTaggedPage(namespace ident.ID, q index.Query, opts index.QueryOptions) (results encoding.SeriesIterators, next index.PageToken, err error) {
+	return s.session.FetchTaggedPage(namespace, q, opts)
+}
+
 // FetchTaggedIDs resolves the provided query to known IDs.
 func (s replicatedSession) FetchTaggedIDs(namespace ident.ID, q index.Query, opts index.QueryOptions) (iter TaggedIDsIterator, exhaustive bool, err error) {
 	return s.session.FetchTaggedIDs(namespace, q, opts)
 }
 
+// FetchTaggedIDsPage resolves the provided query to a single page of known IDs.
+func (s replicatedSession) FetchTaggedIDsPage(namespace ident.ID, q index.Query, opts index.QueryOptions) (iter TaggedIDsIterator, next index.PageToken, err error) {
+	return s.session.FetchTaggedIDsPage(namespace, q, opts)
+}
+
+// IndexCardinality returns the number of distinct values and series of the
+// tag names and tags indexed by the namespace, merged across all hosts.
+func (s replicatedSession) IndexCardinality(namespace ident.ID, opts index.CardinalityOptions) (index.CardinalityResults, error) {
+	return s.session.IndexCardinality(namespace, opts)
+}
+
 // ShardID returns the given shard for an ID for callers
 // to easily discern what shard is failing when operations
 // for given IDs begin failing.
@@ -312,6 +312,7 @@ func newSession(opts Options) (clientSession, error) {
 		))
 	s.pools.tagDecoder = serialize.NewTagDecoderPool(opts.TagDecoderOptions(), tagDecoderPoolOpts)
 	s.pools.tagDecoder.Init()
+	s.pools.tagDecoderOpts = opts.TagDecoderOptions()
 
 	wrapperPoolOpts := pool.NewObjectPoolOptions().
 		SetSize(opts.CheckedBytesWrapperPoolSize()).
@@ -1175,13 +1176,33 @@ func (s *session) Aggregate(
 	return iter, exhaustive, err
 }
 
+func (s *session) AggregatePage(
+	ns ident.ID, q index.Query, opts index.AggregationOptions,
+) (AggregatedTagsIterator, index.PageToken, error) {
+	if !opts.Paginated() {
+		opts.PageToken = index.FirstPageToken()
+	}
+	f := s.pools.aggregateAttempt.Get()
+	f.args.ns = ns
+	f.args.query = q
+	f.args.opts = opts
+	err := s.fetchRetrier.Attempt(f.attemptFn)
+	iter, next := f.resultIter, f.resultNextPage
+	s.pools.aggregateAttempt.Put(f)
+	return iter, next, err
+}
+
 func (s *session) aggregateAttempt(
 	ns ident.ID, q index.Query, opts index.AggregationOptions,
-) (AggregatedTagsIterator, bool, error) {
+) (AggregatedTagsIterator, bool, index.PageToken, error) {
+	if err := validatePageToken(opts.QueryOptions); err != nil {
+		return nil, false, nil, err
+	}
+
 	s.state.RLock()
 	if s.state.status != statusOpen {
 		s.state.RUnlock()
-		return nil, false, errSessionStatusNotOpen
+		return nil, false, nil, errSessionStatusNotOpen
 	}
 
 	// NB(prateek): we have to clone the namespace, as we cannot guarantee the lifecycle
@@ -1192,7 +1213,7 @@ func (s *session) aggregateAttempt(
 	if err != nil {
 		s.state.RUnlock()
 		nsClone.Finalize()
-		return nil, false, xerrors.NewNonRetryableError(err)
+		return nil, false, nil, xerrors.NewNonRetryableError(err)
 	}
 
 	fetchState, err := s.newFetchStateWithRLock(nsClone, newFetchStateOpts{
@@ -1204,7 +1225,7 @@ func (s *session) aggregateAttempt(
 	s.state.RUnlock()
 
 	if err != nil {
-		return nil, false, err
+		return nil, false, nil, err
 	}
 
 	// it's safe to Wait() here, as we still hold the lock on fetchState, after it's
@@ -1215,12 +1236,16 @@ func (s *session) aggregateAttempt(
 	// the fetchState Lock
 	fetchState.Unlock()
 	iters, exhaustive, err := fetchState.asAggregatedTagsIterator(s.pools)
+	var next index.PageToken
+	if err == nil {
+		next, err = fetchState.nextPageToken()
+	}
 
 	// must Unlock() before decRef'ing, as the latter releases the fetchState back into a
 	// pool if ref count == 0.
 	fetchState.decRef()
 
-	return iters, exhaustive, err
+	return iters, exhaustive, next, err
 }
 
 func (s *session) FetchTagged(
@@ -1249,17 +1274,65 @@ func (s *session) FetchTaggedIDs(
 	return iter, exhaustive, err
 }
 
+func (s *session) FetchTaggedPage(
+	ns ident.ID, q index.Query, opts index.QueryOptions,
+) (encoding.SeriesIterators, index.PageToken, error) {
+	if !opts.Paginated() {
+		opts.PageToken = index.FirstPageToken()
+	}
+	f := s.pools.fetchTaggedAttempt.Get()
+	f.args.ns = ns
+	f.args.query = q
+	f.args.opts = opts
+	err := s.fetchRetrier.Attempt(f.dataAttemptFn)
+	iters, next := f.dataResultIters, f.dataResultNextPage
+	s.pools.fetchTaggedAttempt.Put(f)
+	return iters, next, err
+}
+
+func (s *session) FetchTaggedIDsPage(
+	ns ident.ID, q index.Query, opts index.QueryOptions,
+) (TaggedIDsIterator, index.PageToken, error) {
+	if !opts.Paginated() {
+		opts.PageToken = index.FirstPageToken()
+	}
+	f := s.pools.fetchTaggedAttempt.Get()
+	f.args.ns = ns
+	f.args.query = q
+	f.args.opts = opts
+	err := s.fetchRetrier.Attempt(f.idsAttemptFn)
+	iter, next := f.idsResultIter, f.idsResultNextPage
+	s.pools.fetchTaggedAttempt.Put(f)
+	return iter, next, err
+}
+
+// validatePageToken returns an error if the query is paginated with a page
+// token that the nodes cannot resume the query from. Every node is sent the
+// same token since the pages of every node end at the same series ID.
+func validatePageToken(opts index.QueryOptions) error {
+	if !opts.Paginated() {
+		return nil
+	}
+	if _, err := opts.PageToken.QueryCursor(); err != nil {
+		return xerrors.NewNonRetryableError(xerrors.NewInvalidParamsError(err))
+	}
+	return nil
+}
+
 func (s *session) fetchTaggedAttempt(
 	ns ident.ID, q index.Query, opts index.QueryOptions,
-) (encoding.SeriesIterators, bool, error) {
+) (encoding.SeriesIterators, bool, index.PageToken, error) {
 	nsCtx, err := s.nsCtxFor(ns)
 	if err != nil {
-		return nil, false, err
+		return nil, false, nil, err
+	}
+	if err := validatePageToken(opts); err != nil {
+		return nil, false, nil, err
 	}
 	s.state.RLock()
 	if s.state.status != statusOpen {
 		s.state.RUnlock()
-		return nil, false, errSessionStatusNotOpen
+		return nil, false, nil, errSessionStatusNotOpen
 	}
 
 	// NB(prateek): we have to clone the namespace, as we cannot guarantee the lifecycle
@@ -1275,7 +1348,11 @@ func (s *session) fetchTaggedAttempt(
 	if err != nil {
 		s.state.RUnlock()
 		nsClone.Finalize()
-		return nil, false, xerrors.NewNonRetryableError(err)
+		return nil, false, nil, xerrors.NewNonRetryableError(err)
+	}
+	if s.opts.FetchTaggedDictionaryEncodedTags() {
+		dictionaryEncodedTags := true
+		req.DictionaryEncodedTags = &dictionaryEncodedTags
 	}
 
 	fetchState, err := s.newFetchStateWithRLock(nsClone, newFetchStateOpts{
@@ -1287,7 +1364,7 @@ func (s *session) fetchTaggedAttempt(
 	s.state.RUnlock()
 
 	if err != nil {
-		return nil, false, err
+		return nil, false, nil, err
 	}
 
 	// it's safe to Wait() here, as we still hold the lock on fetchState, after it's
@@ -1298,21 +1375,29 @@ func (s *session) fetchTaggedAttempt(
 	// the fetchState Lock
 	fetchState.Unlock()
 	iters, exhaustive, err := fetchState.asEncodingSeriesIterators(s.pools, nsCtx.Schema)
+	var next index.PageToken
+	if err == nil {
+		next, err = fetchState.nextPageToken()
+	}
 
 	// must Unlock() before decRef'ing, as the latter releases the fetchState back into a
 	// pool if ref count == 0.
 	fetchState.decRef()
 
-	return iters, exhaustive, err
+	return iters, exhaustive, next, err
 }
 
 func (s *session) fetchTaggedIDsAttempt(
 	ns ident.ID, q index.Query, opts index.QueryOptions,
-) (TaggedIDsIterator, bool, error) {
+) (TaggedIDsIterator, bool, index.PageToken, error) {
+	if err := validatePageToken(opts); err != nil {
+		return nil, false, nil, err
+	}
+
 	s.state.RLock()
 	if s.state.status != statusOpen {
 		s.state.RUnlock()
-		return nil, false, errSessionStatusNotOpen
+		return nil, false, nil, errSessionStatusNotOpen
 	}
 
 	// NB(prateek): we have to clone the namespace, as we cannot guarantee the lifecycle
@@ -1328,7 +1413,11 @@ func (s *session) fetchTaggedIDsAttempt(
 	if err != nil {
 		s.state.RUnlock()
 		nsClone.Finalize()
-		return nil, false, xerrors.NewNonRetryableError(err)
+		return nil, false, nil, xerrors.NewNonRetryableError(err)
+	}
+	if s.opts.FetchTaggedDictionaryEncodedTags() {
+		dictionaryEncodedTags := true
+		req.DictionaryEncodedTags = &dictionaryEncodedTags
 	}
 
 	fetchState, err := s.newFetchStateWithRLock(nsClone, newFetchStateOpts{
@@ -1340,7 +1429,7 @@ func (s *session) fetchTaggedIDsAttempt(
 	s.state.RUnlock()
 
 	if err != nil {
-		return nil, false, err
+		return nil, false, nil, err
 	}
 
 	// it's safe to Wait() here, as we still hold the lock on fetchState, after it's
@@ -1351,12 +1440,16 @@ func (s *session) fetchTaggedIDsAttempt(
 	// the fetchState Lock
 	fetchState.Unlock()
 	iter, exhaustive, err := fetchState.asTaggedIDsIterator(s.pools)
+	var next index.PageToken
+	if err == nil {
+		next, err = fetchState.nextPageToken()
+	}
 
 	// must Unlock() before decRef'ing, as the latter releases the fetchState back into a
 	// pool if ref count == 0.
 	fetchState.decRef()
 
-	return iter, exhaustive, err
+	return iter, exhaustive, next, err
 }
 
 type newFetchStateOpts struct {
@@ -1867,6 +1960,65 @@ func (s *session) Truncate(namespace ident.ID) (int64, error) {
 	return truncated, resultErr.FinalError()
 }
 
+func (s *session) IndexCardinality(
+	namespace ident.ID,
+	opts index.CardinalityOptions,
+) (index.CardinalityResults, error) {
+	request, err := convert.ToRPCIndexCardinalityRequest(namespace, opts)
+	if err != nil {
+		return index.CardinalityResults{}, err
+	}
+
+	var (
+		wg            sync.WaitGroup
+		enqueueErr    xerrors.MultiError
+		resultErrLock sync.Mutex
+		resultErr     xerrors.MultiError
+		hostResults   []index.CardinalityResults
+	)
+
+	op := &indexCardinalityOp{request: request}
+	op.completionFn = func(result interface{}, err error) {
+		resultErrLock.Lock()
+		if err != nil {
+			resultErr = resultErr.Add(err)
+		} else {
+			res := result.(*rpc.IndexCardinalityResult_)
+			hostResults = append(hostResults, convert.FromRPCIndexCardinalityResult(res))
+		}
+		resultErrLock.Unlock()
+		wg.Done()
+	}
+
+	s.state.RLock()
+	if s.state.status != statusOpen {
+		s.state.RUnlock()
+		return index.CardinalityResults{}, errSessionStatusNotOpen
+	}
+	replicas := s.state.topoMap.Replicas()
+	for idx := range s.state.queues {
+		wg.Add(1)
+		if err := s.state.queues[idx].Enqueue(op); err != nil {
+			wg.Done()
+			enqueueErr = enqueueErr.Add(err)
+		}
+	}
+	s.state.RUnlock()
+
+	if err := enqueueErr.FinalError(); err != nil {
+		s.log.Error("failed to enqueue request", zap.Error(err))
+		return index.CardinalityResults{}, err
+	}
+
+	// Wait for the statistics of every host.
+	wg.Wait()
+
+	if err := resultErr.FinalError(); err != nil {
+		return index.CardinalityResults{}, err
+	}
+	return mergeIndexCardinalityResults(hostResults, replicas, opts.Match, opts.Limit), nil
+}
+
 // NB(r): Excluding maligned struct check here as we can
 // live with a few extra bytes since this struct is only
 // ever passed by stack, its much more readable not optimized
@@ -41,6 +41,7 @@ type sessionPools struct {
 	multiReaderIteratorArray    encoding.MultiReaderIteratorArrayPool
 	tagEncoder                  serialize.TagEncoderPool
 	tagDecoder                  serialize.TagDecoderPool
+	tagDecoderOpts              serialize.TagDecoderOptions
 	readerSliceOfSlicesIterator *readerSliceOfSlicesIteratorPool
 	multiReaderIterator         encoding.MultiReaderIteratorPool
 	seriesIterator              encoding.SeriesIteratorPool
@@ -72,6 +73,10 @@ func (s sessionPools) TagDecoder() serialize.TagDecoderPool {
 	return s.tagDecoder
 }
 
+func (s sessionPools) TagDecoderOptions() serialize.TagDecoderOptions {
+	return s.tagDecoderOpts
+}
+
 func (s sessionPools) TagEncoder() serialize.TagEncoderPool {
 	return s.tagEncoder
 }
@@ -76,12 +76,29 @@ type Session interface {
 	// FetchTagged resolves the provided query to known IDs, and fetches the data for them.
 	FetchTagged(namespace ident.ID, q index.Query, opts index.QueryOptions) (results encoding.SeriesIterators, exhaustive bool, err error)
 
+	// FetchTaggedPage resolves the provided query to known IDs, and fetches the data for a single
+	// page of at most opts.Limit IDs in increasing ID order, resumed from opts.PageToken or from
+	// the first page when it is not set. The returned page token is nil once all pages have been fetched.
+	FetchTaggedPage(namespace ident.ID, q index.Query, opts index.QueryOptions) (results encoding.SeriesIterators, next index.PageToken, err error)
+
 	// FetchTaggedIDs resolves the provided query to known IDs.
 	FetchTaggedIDs(namespace ident.ID, q index.Query, opts index.QueryOptions) (iter TaggedIDsIterator, exhaustive bool, err error)
 
+	// FetchTaggedIDsPage resolves the provided query to a single page of known IDs, paginated
+	// the same as FetchTaggedPage.
+	FetchTaggedIDsPage(namespace ident.ID, q index.Query, opts index.QueryOptions) (iter TaggedIDsIterator, next index.PageToken, err error)
+
 	// Aggregate aggregates values from the database for the given set of constraints.
 	Aggregate(namespace ident.ID, q index.Query, opts index.AggregationOptions) (iter AggregatedTagsIterator, exhaustive bool, err error)
 
+	// AggregatePage aggregates values from a single page of the series matching the given set of
+	// constraints, paginated the same as FetchTaggedPage.
+	AggregatePage(namespace ident.ID, q index.Query, opts index.AggregationOptions) (iter AggregatedTagsIterator, next index.PageToken, err error)
+
+	// IndexCardinality returns the number of distinct values and series of the
+	// tag names and tags indexed by the namespace, merged across all hosts.
+	IndexCardinality(namespace ident.ID, opts index.CardinalityOptions) (index.CardinalityResults, error)
+
 	// ShardID returns the given shard for an ID for callers
 	// to easily discern what shard is failing when operations
 	// for given IDs begin failing.
@@ -533,6 +550,14 @@ type Options interface {
 
 	// UseV2BatchAPIs returns whether the V2 batch APIs should be used.
 	UseV2BatchAPIs() bool
+
+	// SetFetchTaggedDictionaryEncodedTags sets whether fetch tagged requests
+	// ask for the tags of the results to be dictionary encoded.
+	SetFetchTaggedDictionaryEncodedTags(value bool) Options
+
+	// FetchTaggedDictionaryEncodedTags returns whether fetch tagged requests
+	// ask for the tags of the results to be dictionary encoded.
+	FetchTaggedDictionaryEncodedTags() bool
 }
 
 // AdminOptions is a set of administration client options.
@@ -0,0 +1,329 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package histogram
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/encoding"
+	"github.com/m3db/m3/src/dbnode/encoding/m3tsz"
+	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/dbnode/ts"
+	"github.com/m3db/m3/src/dbnode/x/xio"
+	"github.com/m3db/m3/src/x/checked"
+	"github.com/m3db/m3/src/x/context"
+	xtime "github.com/m3db/m3/src/x/time"
+)
+
+// Make sure encoder implements encoding.Encoder.
+var _ encoding.Encoder = &Encoder{}
+
+var (
+	encErrPrefix           = "histogram encoder:"
+	errEncoderClosed       = fmt.Errorf("%s encoder is closed", encErrPrefix)
+	errNoEncodedDatapoints = fmt.Errorf("%s encoder has no encoded datapoints", encErrPrefix)
+)
+
+// Encoder compresses streams of datapoints whose values can be histograms.
+type Encoder struct {
+	opts   encoding.Options
+	stream encoding.OStream
+
+	numEncoded    int
+	lastEncodedDP ts.Datapoint
+
+	timestampEncoder m3tsz.TimestampEncoder
+	floatEncoder     m3tsz.FloatEncoderAndIterator
+
+	// State of the last encoded histogram.
+	upperBounds []float64
+	counts      []uint64
+
+	varIntBuf [binary.MaxVarintLen64]byte
+	closed    bool
+}
+
+// NewEncoder creates a new histogram encoder.
+func NewEncoder(start time.Time, opts encoding.Options) *Encoder {
+	initAllocIfEmpty := opts.EncoderPool() == nil
+	stream := encoding.NewOStream(nil, initAllocIfEmpty, opts.BytesPool())
+	return &Encoder{
+		opts:   opts,
+		stream: stream,
+		timestampEncoder: m3tsz.NewTimestampEncoder(
+			start, opts.DefaultTimeUnit(), opts),
+	}
+}
+
+// Encode encodes a datapoint and, if the annotation is set, the histogram it
+// holds. The annotation must be empty or a histogram encoded with
+// ts.Histogram.Annotation.
+func (enc *Encoder) Encode(dp ts.Datapoint, timeUnit xtime.Unit, annotation ts.Annotation) error {
+	if enc.closed {
+		return errEncoderClosed
+	}
+
+	// Decode the histogram before any data is written so that it can be
+	// validated upfront, otherwise errors could be encountered mid-write
+	// leaving the stream in a corrupted state.
+	var (
+		histogram    ts.Histogram
+		hasHistogram bool
+	)
+	if len(annotation) > 0 {
+		var err error
+		histogram, hasHistogram, err = ts.HistogramFromAnnotation(annotation)
+		if err != nil {
+			return fmt.Errorf("%s error decoding histogram: %v", encErrPrefix, err)
+		}
+		if !hasHistogram {
+			return fmt.Errorf("%s annotation is not a histogram", encErrPrefix)
+		}
+		if err := histogram.Validate(); err != nil {
+			return fmt.Errorf("%s invalid histogram: %v", encErrPrefix, err)
+		}
+	}
+
+	if enc.numEncoded == 0 {
+		enc.stream.WriteByte(streamMagic)
+		enc.encodeVarInt(currentEncodingSchemeVersion)
+	}
+
+	if timeUnit != enc.timestampEncoder.TimeUnit {
+		enc.stream.WriteBit(opCodeNoMoreDataOrTimeUnitChange)
+		enc.stream.WriteBit(opCodeTimeUnitChange)
+		// NB: The time unit is written manually rather than by the timestamp
+		// encoder since the marker encoding scheme it uses relies on bit
+		// combinations that can legitimately be written by this encoder.
+		enc.timestampEncoder.WriteTimeUnit(enc.stream, timeUnit)
+	} else {
+		enc.stream.WriteBit(opCodeMoreData)
+	}
+
+	err := enc.timestampEncoder.WriteTime(enc.stream, dp.Timestamp, nil, timeUnit)
+	if err != nil {
+		return fmt.Errorf("%s error encoding timestamp: %v", encErrPrefix, err)
+	}
+	enc.floatEncoder.WriteFloat(enc.stream, dp.Value)
+
+	if hasHistogram {
+		enc.stream.WriteBit(opCodeHistogram)
+		enc.encodeHistogram(histogram)
+	} else {
+		enc.stream.WriteBit(opCodeNoHistogram)
+	}
+
+	enc.numEncoded++
+	enc.lastEncodedDP = dp
+	return nil
+}
+
+func (enc *Encoder) encodeHistogram(h ts.Histogram) {
+	if enc.bucketsChanged(h) {
+		enc.stream.WriteBit(opCodeBucketsChanged)
+		enc.encodeVarInt(uint64(len(h.Buckets)))
+		enc.upperBounds = enc.upperBounds[:0]
+		enc.counts = enc.counts[:0]
+		for _, b := range h.Buckets {
+			enc.stream.WriteBits(math.Float64bits(b.UpperBound), 64)
+			enc.upperBounds = append(enc.upperBounds, b.UpperBound)
+			enc.counts = append(enc.counts, 0)
+		}
+	} else {
+		enc.stream.WriteBit(opCodeBucketsUnchanged)
+	}
+
+	// Counts are encoded as the delta from the count of the same bucket in
+	// the previous histogram, which is usually small for cumulative counters.
+	for i, b := range h.Buckets {
+		delta := int64(b.Count - enc.counts[i])
+		enc.encodeVarInt(zigZagEncode(delta))
+		enc.counts[i] = b.Count
+	}
+}
+
+func (enc *Encoder) bucketsChanged(h ts.Histogram) bool {
+	if len(h.Buckets) != len(enc.upperBounds) {
+		return true
+	}
+	for i, b := range h.Buckets {
+		if b.UpperBound != enc.upperBounds[i] {
+			return true
+		}
+	}
+	return false
+}
+
+// Stream returns a copy of the underlying data stream.
+func (enc *Encoder) Stream(ctx context.Context) (xio.SegmentReader, bool) {
+	seg := enc.segmentZeroCopy(ctx)
+	if seg.Len() == 0 {
+		return nil, false
+	}
+
+	if readerPool := enc.opts.SegmentReaderPool(); readerPool != nil {
+		reader := readerPool.Get()
+		reader.Reset(seg)
+		return reader, true
+	}
+	return xio.NewSegmentReader(seg), true
+}
+
+func (enc *Encoder) segmentZeroCopy(ctx context.Context) ts.Segment {
+	length := enc.stream.Len()
+	if length == 0 {
+		return ts.Segment{}
+	}
+
+	// We need a tail to capture an immutable snapshot of the encoder data
+	// as the last byte can change after this method returns.
+	rawBuffer, _ := enc.stream.Rawbytes()
+	lastByte := rawBuffer[length-1]
+
+	// Take ref up to last byte.
+	headBytes := rawBuffer[:length-1]
+
+	// Zero copy from the output stream.
+	var head checked.Bytes
+	if pool := enc.opts.CheckedBytesWrapperPool(); pool != nil {
+		head = pool.Get(headBytes)
+	} else {
+		head = checked.NewBytes(headBytes, nil)
+	}
+
+	// Make sure the ostream bytes ref is delayed from finalizing
+	// until this operation is complete (since this is zero copy).
+	buffer, _ := enc.stream.CheckedBytes()
+	ctx.RegisterCloser(buffer.DelayFinalizer())
+
+	// Take a shared ref to a known good tail.
+	tail := tails[lastByte]
+
+	// Only discard the head since tails are shared for process life time.
+	return ts.NewSegment(head, tail, ts.FinalizeHead)
+}
+
+func (enc *Encoder) segmentTakeOwnership() ts.Segment {
+	if enc.stream.Len() == 0 {
+		return ts.Segment{}
+	}
+
+	// Take ref from the ostream.
+	head := enc.stream.Discard()
+
+	return ts.NewSegment(head, nil, ts.FinalizeHead)
+}
+
+// NumEncoded returns the number of encoded datapoints.
+func (enc *Encoder) NumEncoded() int {
+	return enc.numEncoded
+}
+
+// LastEncoded returns the last encoded datapoint, it does not include the
+// histogram of the datapoint.
+func (enc *Encoder) LastEncoded() (ts.Datapoint, error) {
+	if enc.closed {
+		return ts.Datapoint{}, errEncoderClosed
+	}
+	if enc.numEncoded == 0 {
+		return ts.Datapoint{}, errNoEncodedDatapoints
+	}
+	return enc.lastEncodedDP, nil
+}
+
+// Len returns the length of the data stream.
+func (enc *Encoder) Len() int {
+	return enc.stream.Len()
+}
+
+// SetSchema is a no-op since the histogram encoder is not schema aware.
+func (enc *Encoder) SetSchema(_ namespace.SchemaDescr) {}
+
+// Reset resets the encoder for reuse.
+func (enc *Encoder) Reset(start time.Time, capacity int, _ namespace.SchemaDescr) {
+	enc.stream.Reset(enc.newBuffer(capacity))
+	enc.timestampEncoder = m3tsz.NewTimestampEncoder(
+		start, enc.opts.DefaultTimeUnit(), enc.opts)
+	enc.floatEncoder = m3tsz.FloatEncoderAndIterator{}
+	enc.lastEncodedDP = ts.Datapoint{}
+	enc.upperBounds = enc.upperBounds[:0]
+	enc.counts = enc.counts[:0]
+	enc.closed = false
+	enc.numEncoded = 0
+}
+
+// Close closes the encoder.
+func (enc *Encoder) Close() {
+	if enc.closed {
+		return
+	}
+
+	enc.Reset(time.Time{}, 0, nil)
+	enc.stream.Reset(nil)
+	enc.closed = true
+
+	if pool := enc.opts.EncoderPool(); pool != nil {
+		pool.Put(enc)
+	}
+}
+
+// Discard closes the encoder and transfers ownership of the data stream to
+// the caller.
+func (enc *Encoder) Discard() ts.Segment {
+	segment := enc.segmentTakeOwnership()
+	// Close the encoder since its no longer needed
+	enc.Close()
+	return segment
+}
+
+// DiscardReset does the same thing as Discard except it also resets the encoder
+// for reuse.
+func (enc *Encoder) DiscardReset(start time.Time, capacity int, descr namespace.SchemaDescr) ts.Segment {
+	segment := enc.segmentTakeOwnership()
+	enc.Reset(start, capacity, descr)
+	return segment
+}
+
+func (enc *Encoder) encodeVarInt(x uint64) {
+	numBytes := binary.PutUvarint(enc.varIntBuf[:], x)
+	enc.stream.WriteBytes(enc.varIntBuf[:numBytes])
+}
+
+func (enc *Encoder) newBuffer(capacity int) checked.Bytes {
+	if bytesPool := enc.opts.BytesPool(); bytesPool != nil {
+		return bytesPool.Get(capacity)
+	}
+	return checked.NewBytes(make([]byte, 0, capacity), nil)
+}
+
+// tails is a list of all possible tails based on the
+// byte value of the last byte. For the histogram encoder
+// they are all the same.
+var tails [256]checked.Bytes
+
+func init() {
+	for i := 0; i < 256; i++ {
+		tails[i] = checked.NewBytes([]byte{byte(i)}, nil)
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package histogram implements a compressed encoding of datapoints whose
+// values can be histograms, alongside the M3TSZ encoding of float values.
+//
+// Each datapoint holds a float value compressed the same way as M3TSZ and
+// optionally a histogram, histograms are passed to and returned from the
+// encoder and iterator as annotations (see ts.Histogram). The upper bounds of
+// the buckets are only encoded when they change and the counts of the buckets
+// are encoded as the delta from the previous histogram.
+//
+// Streams start with a magic byte that cannot begin an M3TSZ stream, which
+// lets the iterator read both encodings so that series of namespaces with and
+// without histograms can be read without configuring the encoding.
+package histogram
+
+const (
+	// streamMagic is the first byte of histogram streams, M3TSZ streams start
+	// with the block start in nanoseconds whose first bit is always zero for
+	// block starts after the epoch.
+	streamMagic byte = 0xff
+
+	currentEncodingSchemeVersion = 1
+)
+
+const (
+	opCodeNoMoreDataOrTimeUnitChange = 0
+	opCodeMoreData                   = 1
+
+	opCodeNoMoreData     = 0
+	opCodeTimeUnitChange = 1
+
+	opCodeNoHistogram = 0
+	opCodeHistogram   = 1
+
+	opCodeBucketsUnchanged = 0
+	opCodeBucketsChanged   = 1
+)
+
+func zigZagEncode(v int64) uint64 {
+	return uint64((v << 1) ^ (v >> 63))
+}
+
+func zigZagDecode(v uint64) int64 {
+	return int64(v>>1) ^ -int64(v&1)
+}
@@ -0,0 +1,322 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package histogram
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/m3db/m3/src/dbnode/encoding"
+	"github.com/m3db/m3/src/dbnode/encoding/m3tsz"
+	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/dbnode/ts"
+	xtime "github.com/m3db/m3/src/x/time"
+)
+
+var (
+	itErrPrefix = "histogram iterator:"
+)
+
+type iterator struct {
+	opts         encoding.Options
+	intOptimized bool
+	stream       encoding.IStream
+	err          error
+
+	tsIterator    m3tsz.TimestampIterator
+	floatIterator m3tsz.FloatEncoderAndIterator
+
+	// State of the last read histogram.
+	upperBounds  []float64
+	counts       []uint64
+	hasHistogram bool
+	annotation   []byte
+
+	// m3tszIter reads the stream if it is not a histogram stream.
+	m3tszIter encoding.ReaderIterator
+	isM3TSZ   bool
+
+	consumedHeader bool
+	done           bool
+	closed         bool
+}
+
+// NewIterator creates a new iterator that reads both histogram and M3TSZ
+// streams.
+func NewIterator(
+	reader io.Reader,
+	_ namespace.SchemaDescr,
+	opts encoding.Options,
+) encoding.ReaderIterator {
+	return NewReaderIterator(reader, m3tsz.DefaultIntOptimizationEnabled, opts)
+}
+
+// NewReaderIterator creates a new iterator that reads both histogram and M3TSZ
+// streams, M3TSZ streams are read with the given int optimization setting.
+func NewReaderIterator(
+	reader io.Reader,
+	intOptimized bool,
+	opts encoding.Options,
+) encoding.ReaderIterator {
+	return &iterator{
+		opts:         opts,
+		intOptimized: intOptimized,
+		stream:       encoding.NewIStream(reader, opts.IStreamReaderSizeM3TSZ()),
+		tsIterator:   m3tsz.NewTimestampIterator(opts, true),
+	}
+}
+
+func (it *iterator) Next() bool {
+	if it.err != nil || it.done || it.closed {
+		return false
+	}
+
+	if !it.consumedHeader {
+		if !it.readHeader() {
+			return false
+		}
+	}
+
+	if it.isM3TSZ {
+		return it.m3tszIter.Next()
+	}
+
+	moreDataControlBit, err := it.stream.ReadBit()
+	if err == io.EOF {
+		it.done = true
+		return false
+	}
+	if err != nil {
+		it.err = fmt.Errorf("%s error reading more data control bit: %v", itErrPrefix, err)
+		return false
+	}
+
+	if moreDataControlBit == opCodeNoMoreDataOrTimeUnitChange {
+		// The next bit will tell us whether we've reached the end of the
+		// stream or that the time unit has changed.
+		noMoreDataControlBit, err := it.stream.ReadBit()
+		if err == io.EOF || (err == nil && noMoreDataControlBit == opCodeNoMoreData) {
+			it.done = true
+			return false
+		}
+		if err != nil {
+			it.err = fmt.Errorf("%s error reading no more data control bit: %v", itErrPrefix, err)
+			return false
+		}
+		if err := it.tsIterator.ReadTimeUnit(it.stream); err != nil {
+			it.err = fmt.Errorf("%s error reading new time unit: %v", itErrPrefix, err)
+			return false
+		}
+	}
+
+	_, done, err := it.tsIterator.ReadTimestamp(it.stream)
+	if err != nil {
+		it.err = fmt.Errorf("%s error reading timestamp: %v", itErrPrefix, err)
+		return false
+	}
+	if done {
+		// This should never happen since we never encode the EndOfStream marker.
+		it.err = fmt.Errorf("%s unexpected end of timestamp stream", itErrPrefix)
+		return false
+	}
+
+	if err := it.floatIterator.ReadFloat(it.stream); err != nil {
+		it.err = fmt.Errorf("%s error reading value: %v", itErrPrefix, err)
+		return false
+	}
+
+	histogramControlBit, err := it.stream.ReadBit()
+	if err != nil {
+		it.err = fmt.Errorf("%s error reading histogram control bit: %v", itErrPrefix, err)
+		return false
+	}
+	it.hasHistogram = histogramControlBit == opCodeHistogram
+	if it.hasHistogram {
+		if err := it.readHistogram(); err != nil {
+			it.err = fmt.Errorf("%s error reading histogram: %v", itErrPrefix, err)
+			return false
+		}
+	}
+
+	return true
+}
+
+// readHeader detects the encoding of the stream and consumes the header of
+// histogram streams, it returns false if there is nothing to read.
+func (it *iterator) readHeader() bool {
+	magic, err := it.stream.PeekBits(8)
+	if err == io.EOF {
+		it.done = true
+		return false
+	}
+	if err != nil {
+		it.err = fmt.Errorf("%s error reading stream header: %v", itErrPrefix, err)
+		return false
+	}
+	it.consumedHeader = true
+
+	if byte(magic) != streamMagic {
+		// NB: Nothing has been consumed from the stream yet so the M3TSZ
+		// iterator reads the stream from the start.
+		it.isM3TSZ = true
+		reader := byteAlignedReader{stream: it.stream}
+		if it.m3tszIter == nil {
+			// The M3TSZ iterator is owned by this iterator so it must not
+			// return itself to the pool when closed.
+			opts := it.opts.SetReaderIteratorPool(nil)
+			it.m3tszIter = m3tsz.NewReaderIterator(reader, it.intOptimized, opts)
+		} else {
+			it.m3tszIter.Reset(reader, nil)
+		}
+		return true
+	}
+
+	if _, err := it.stream.ReadByte(); err != nil {
+		it.err = fmt.Errorf("%s error reading stream header: %v", itErrPrefix, err)
+		return false
+	}
+	// Can ignore the version number for now because we only have one.
+	if _, err := binary.ReadUvarint(it.stream); err != nil {
+		it.err = fmt.Errorf("%s error reading stream header: %v", itErrPrefix, err)
+		return false
+	}
+	return true
+}
+
+func (it *iterator) readHistogram() error {
+	bucketsControlBit, err := it.stream.ReadBit()
+	if err != nil {
+		return err
+	}
+
+	if bucketsControlBit == opCodeBucketsChanged {
+		numBuckets, err := binary.ReadUvarint(it.stream)
+		if err != nil {
+			return err
+		}
+		it.upperBounds = it.upperBounds[:0]
+		it.counts = it.counts[:0]
+		for i := uint64(0); i < numBuckets; i++ {
+			bits, err := it.stream.ReadBits(64)
+			if err != nil {
+				return err
+			}
+			it.upperBounds = append(it.upperBounds, math.Float64frombits(bits))
+			it.counts = append(it.counts, 0)
+		}
+	}
+
+	for i := range it.counts {
+		delta, err := binary.ReadUvarint(it.stream)
+		if err != nil {
+			return err
+		}
+		it.counts[i] += uint64(zigZagDecode(delta))
+	}
+	return nil
+}
+
+// Current returns the current datapoint, the annotation holds the histogram
+// of the datapoint if it has one and is only valid until the next call to
+// Next.
+func (it *iterator) Current() (ts.Datapoint, xtime.Unit, ts.Annotation) {
+	if it.isM3TSZ {
+		return it.m3tszIter.Current()
+	}
+
+	var (
+		dp = ts.Datapoint{
+			Timestamp: it.tsIterator.PrevTime,
+			Value:     math.Float64frombits(it.floatIterator.PrevFloatBits),
+		}
+		unit = it.tsIterator.TimeUnit
+	)
+	if !it.hasHistogram {
+		return dp, unit, nil
+	}
+
+	h := ts.Histogram{Buckets: make([]ts.HistogramBucket, 0, len(it.counts))}
+	for i, count := range it.counts {
+		h.Buckets = append(h.Buckets, ts.HistogramBucket{
+			UpperBound: it.upperBounds[i],
+			Count:      count,
+		})
+	}
+	it.annotation = h.AppendAnnotation(it.annotation[:0])
+	return dp, unit, it.annotation
+}
+
+func (it *iterator) Err() error {
+	if it.err == nil && it.isM3TSZ {
+		return it.m3tszIter.Err()
+	}
+	return it.err
+}
+
+func (it *iterator) Reset(reader io.Reader, _ namespace.SchemaDescr) {
+	it.stream.Reset(reader)
+	it.tsIterator = m3tsz.NewTimestampIterator(it.opts, true)
+	it.floatIterator = m3tsz.FloatEncoderAndIterator{}
+	it.upperBounds = it.upperBounds[:0]
+	it.counts = it.counts[:0]
+	it.hasHistogram = false
+	it.annotation = it.annotation[:0]
+	if it.m3tszIter != nil {
+		it.m3tszIter.Reset(nil, nil)
+	}
+	it.isM3TSZ = false
+
+	it.err = nil
+	it.consumedHeader = false
+	it.done = false
+	it.closed = false
+}
+
+func (it *iterator) Close() {
+	if it.closed {
+		return
+	}
+
+	it.Reset(nil, nil)
+	it.stream.Reset(nil)
+	it.closed = true
+
+	if pool := it.opts.ReaderIteratorPool(); pool != nil {
+		pool.Put(it)
+	}
+}
+
+// byteAlignedReader reads the remaining bytes of a stream that is aligned on
+// a byte boundary, unlike the stream it returns the bytes that were read
+// before reaching the end of the stream without an error.
+type byteAlignedReader struct {
+	stream encoding.IStream
+}
+
+func (r byteAlignedReader) Read(b []byte) (int, error) {
+	n, err := r.stream.Read(b)
+	if err == io.ErrUnexpectedEOF {
+		err = nil
+	}
+	return n, err
+}
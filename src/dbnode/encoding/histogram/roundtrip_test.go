@@ -0,0 +1,226 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package histogram
+
+import (
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/encoding"
+	"github.com/m3db/m3/src/dbnode/encoding/m3tsz"
+	"github.com/m3db/m3/src/dbnode/ts"
+	"github.com/m3db/m3/src/dbnode/x/xio"
+	"github.com/m3db/m3/src/x/context"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testDatapoint struct {
+	dp        ts.Datapoint
+	unit      xtime.Unit
+	histogram *ts.Histogram
+}
+
+func newTestHistogram(bounds []float64, counts []uint64) *ts.Histogram {
+	h := &ts.Histogram{}
+	for i := range bounds {
+		h.Buckets = append(h.Buckets, ts.HistogramBucket{
+			UpperBound: bounds[i],
+			Count:      counts[i],
+		})
+	}
+	return h
+}
+
+func TestRoundTrip(t *testing.T) {
+	var (
+		start   = time.Now().Truncate(time.Hour)
+		bounds  = []float64{0.1, 0.5, 1, 5}
+		bounds2 = []float64{0.25, 1}
+		input   = []testDatapoint{
+			{unit: xtime.Second, histogram: newTestHistogram(bounds, []uint64{1, 2, 3, 4})},
+			{unit: xtime.Second, histogram: newTestHistogram(bounds, []uint64{2, 4, 6, 10})},
+			{unit: xtime.Second, dp: ts.Datapoint{Value: 42.5}},
+			{unit: xtime.Millisecond, histogram: newTestHistogram(bounds, []uint64{2, 4, 6, 10})},
+			// Counts going backwards (i.e. a counter reset) must round trip.
+			{unit: xtime.Millisecond, histogram: newTestHistogram(bounds, []uint64{0, 1, 1, 1})},
+			{unit: xtime.Second, histogram: newTestHistogram(bounds2, []uint64{7, 9})},
+			{unit: xtime.Nanosecond, dp: ts.Datapoint{Value: -1}},
+		}
+	)
+
+	var (
+		enc  = NewEncoder(start, encoding.NewOptions())
+		curr = start
+	)
+	for i := range input {
+		duration, err := xtime.DurationFromUnit(input[i].unit)
+		require.NoError(t, err)
+		curr = curr.Add(duration)
+		input[i].dp.Timestamp = curr
+
+		var annotation ts.Annotation
+		if h := input[i].histogram; h != nil {
+			input[i].dp.Value = float64(h.Count())
+			annotation = h.Annotation()
+		}
+		require.NoError(t, enc.Encode(input[i].dp, input[i].unit, annotation))
+
+		last, err := enc.LastEncoded()
+		require.NoError(t, err)
+		require.Equal(t, input[i].dp, last)
+	}
+	require.Equal(t, len(input), enc.NumEncoded())
+
+	ctx := context.NewContext()
+	defer ctx.Close()
+
+	stream, ok := enc.Stream(ctx)
+	require.True(t, ok)
+
+	iter := NewIterator(stream, nil, encoding.NewOptions())
+	defer iter.Close()
+
+	i := 0
+	for iter.Next() {
+		require.True(t, i < len(input))
+		dp, unit, annotation := iter.Current()
+		require.True(t, input[i].dp.Timestamp.Equal(dp.Timestamp))
+		require.Equal(t, input[i].dp.Value, dp.Value)
+		require.Equal(t, input[i].unit, unit)
+
+		h, ok, err := ts.HistogramFromAnnotation(annotation)
+		require.NoError(t, err)
+		if input[i].histogram == nil {
+			require.False(t, ok)
+		} else {
+			require.True(t, ok)
+			require.True(t, input[i].histogram.Equal(h))
+		}
+		i++
+	}
+	require.NoError(t, iter.Err())
+	require.Equal(t, len(input), i)
+}
+
+func TestEncodeInvalidAnnotation(t *testing.T) {
+	var (
+		start = time.Now().Truncate(time.Hour)
+		enc   = NewEncoder(start, encoding.NewOptions())
+		dp    = ts.Datapoint{Timestamp: start.Add(time.Second), Value: 1}
+	)
+
+	err := enc.Encode(dp, xtime.Second, ts.Annotation("not a histogram"))
+	require.Error(t, err)
+
+	unsorted := newTestHistogram([]float64{5, 1}, []uint64{1, 2})
+	err = enc.Encode(dp, xtime.Second, unsorted.Annotation())
+	require.Error(t, err)
+
+	// Nothing should have been written for rejected datapoints.
+	require.Equal(t, 0, enc.NumEncoded())
+	require.Equal(t, 0, enc.Len())
+	_, err = enc.LastEncoded()
+	require.Error(t, err)
+}
+
+func TestEncoderClosed(t *testing.T) {
+	start := time.Now().Truncate(time.Hour)
+	enc := NewEncoder(start, encoding.NewOptions())
+	enc.Close()
+
+	err := enc.Encode(ts.Datapoint{Timestamp: start}, xtime.Second, nil)
+	require.Equal(t, errEncoderClosed, err)
+}
+
+func TestIteratorReadsM3TSZStreams(t *testing.T) {
+	var (
+		start        = time.Now().Truncate(time.Hour)
+		m3tszEnc     = m3tsz.NewEncoder(start, nil, m3tsz.DefaultIntOptimizationEnabled, encoding.NewOptions())
+		histogramEnc = NewEncoder(start, encoding.NewOptions())
+		h            = newTestHistogram([]float64{1, 5}, []uint64{3, 4})
+		m3tszInput   = []ts.Datapoint{
+			{Timestamp: start.Add(time.Second), Value: 1.5},
+			{Timestamp: start.Add(2 * time.Second), Value: 12},
+		}
+		histogramInput = ts.Datapoint{
+			Timestamp: start.Add(time.Second),
+			Value:     float64(h.Count()),
+		}
+	)
+	for _, dp := range m3tszInput {
+		require.NoError(t, m3tszEnc.Encode(dp, xtime.Second, nil))
+	}
+	require.NoError(t, histogramEnc.Encode(histogramInput, xtime.Second, h.Annotation()))
+
+	ctx := context.NewContext()
+	defer ctx.Close()
+
+	requireM3TSZ := func(iter encoding.ReaderIterator) {
+		i := 0
+		for iter.Next() {
+			require.True(t, i < len(m3tszInput))
+			dp, unit, annotation := iter.Current()
+			require.True(t, m3tszInput[i].Timestamp.Equal(dp.Timestamp))
+			require.Equal(t, m3tszInput[i].Value, dp.Value)
+			require.Equal(t, xtime.Second, unit)
+			require.Empty(t, annotation)
+			i++
+		}
+		require.NoError(t, iter.Err())
+		require.Equal(t, len(m3tszInput), i)
+	}
+
+	stream, ok := m3tszEnc.Stream(ctx)
+	require.True(t, ok)
+	iter := NewIterator(stream, nil, encoding.NewOptions())
+	defer iter.Close()
+	requireM3TSZ(iter)
+
+	// Iterators can be reset between streams of either encoding.
+	stream, ok = histogramEnc.Stream(ctx)
+	require.True(t, ok)
+	iter.Reset(stream, nil)
+	require.True(t, iter.Next())
+	dp, _, annotation := iter.Current()
+	require.True(t, histogramInput.Timestamp.Equal(dp.Timestamp))
+	require.Equal(t, histogramInput.Value, dp.Value)
+	actual, ok, err := ts.HistogramFromAnnotation(annotation)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.True(t, h.Equal(actual))
+	require.False(t, iter.Next())
+	require.NoError(t, iter.Err())
+
+	stream, ok = m3tszEnc.Stream(ctx)
+	require.True(t, ok)
+	iter.Reset(stream, nil)
+	requireM3TSZ(iter)
+}
+
+func TestIteratorEmptyStream(t *testing.T) {
+	iter := NewIterator(xio.NewSegmentReader(ts.Segment{}), nil, encoding.NewOptions())
+	defer iter.Close()
+
+	require.False(t, iter.Next())
+	require.NoError(t, iter.Err())
+}
@@ -31,6 +31,9 @@
 	It has these top-level messages:
 		RetentionOptions
 		IndexOptions
+		AggregationOptions
+		ColdTierOptions
+		WriteClass
 		NamespaceOptions
 		Registry
 		SchemaOptions
@@ -121,8 +124,11 @@ func (m *RetentionOptions) GetFutureRetentionPeriodNanos() int64 {
 }
 
 type IndexOptions struct {
-	Enabled        bool  `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
-	BlockSizeNanos int64 `protobuf:"varint,2,opt,name=blockSizeNanos,proto3" json:"blockSizeNanos,omitempty"`
+	Enabled                 bool     `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	BlockSizeNanos          int64    `protobuf:"varint,2,opt,name=blockSizeNanos,proto3" json:"blockSizeNanos,omitempty"`
+	TokenizedFields         []string `protobuf:"bytes,3,rep,name=tokenizedFields" json:"tokenizedFields,omitempty"`
+	CompactionAgeNanos      int64    `protobuf:"varint,4,opt,name=compactionAgeNanos,proto3" json:"compactionAgeNanos,omitempty"`
+	CompactedBlockSizeNanos int64    `protobuf:"varint,5,opt,name=compactedBlockSizeNanos,proto3" json:"compactedBlockSizeNanos,omitempty"`
 }
 
 func (m *IndexOptions) Reset()                    { *m = IndexOptions{} }
@@ -144,23 +150,169 @@ func (m *IndexOptions) GetBlockSizeNanos() int64 {
 	return 0
 }
 
+func (m *IndexOptions) GetTokenizedFields() []string {
+	if m != nil {
+		return m.TokenizedFields
+	}
+	return nil
+}
+
+func (m *IndexOptions) GetCompactionAgeNanos() int64 {
+	if m != nil {
+		return m.CompactionAgeNanos
+	}
+	return 0
+}
+
+func (m *IndexOptions) GetCompactedBlockSizeNanos() int64 {
+	if m != nil {
+		return m.CompactedBlockSizeNanos
+	}
+	return 0
+}
+
+type AggregationOptions struct {
+	Enabled          bool     `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	ResolutionNanos  int64    `protobuf:"varint,2,opt,name=resolutionNanos,proto3" json:"resolutionNanos,omitempty"`
+	SourceNamespace  string   `protobuf:"bytes,3,opt,name=sourceNamespace,proto3" json:"sourceNamespace,omitempty"`
+	AggregationTypes []string `protobuf:"bytes,4,rep,name=aggregationTypes" json:"aggregationTypes,omitempty"`
+	IdScheme         string   `protobuf:"bytes,5,opt,name=idScheme,proto3" json:"idScheme,omitempty"`
+}
+
+func (m *AggregationOptions) Reset()                    { *m = AggregationOptions{} }
+func (m *AggregationOptions) String() string            { return proto.CompactTextString(m) }
+func (*AggregationOptions) ProtoMessage()               {}
+func (*AggregationOptions) Descriptor() ([]byte, []int) { return fileDescriptorNamespace, []int{2} }
+
+func (m *AggregationOptions) GetEnabled() bool {
+	if m != nil {
+		return m.Enabled
+	}
+	return false
+}
+
+func (m *AggregationOptions) GetResolutionNanos() int64 {
+	if m != nil {
+		return m.ResolutionNanos
+	}
+	return 0
+}
+
+func (m *AggregationOptions) GetSourceNamespace() string {
+	if m != nil {
+		return m.SourceNamespace
+	}
+	return ""
+}
+
+func (m *AggregationOptions) GetAggregationTypes() []string {
+	if m != nil {
+		return m.AggregationTypes
+	}
+	return nil
+}
+
+func (m *AggregationOptions) GetIdScheme() string {
+	if m != nil {
+		return m.IdScheme
+	}
+	return ""
+}
+
+type ColdTierOptions struct {
+	FilePathPrefix string `protobuf:"bytes,1,opt,name=filePathPrefix,proto3" json:"filePathPrefix,omitempty"`
+	AgeNanos       int64  `protobuf:"varint,2,opt,name=ageNanos,proto3" json:"ageNanos,omitempty"`
+}
+
+func (m *ColdTierOptions) Reset()                    { *m = ColdTierOptions{} }
+func (m *ColdTierOptions) String() string            { return proto.CompactTextString(m) }
+func (*ColdTierOptions) ProtoMessage()               {}
+func (*ColdTierOptions) Descriptor() ([]byte, []int) { return fileDescriptorNamespace, []int{3} }
+
+func (m *ColdTierOptions) GetFilePathPrefix() string {
+	if m != nil {
+		return m.FilePathPrefix
+	}
+	return ""
+}
+
+func (m *ColdTierOptions) GetAgeNanos() int64 {
+	if m != nil {
+		return m.AgeNanos
+	}
+	return 0
+}
+
+type WriteClass struct {
+	Name              string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	TagName           string `protobuf:"bytes,2,opt,name=tagName,proto3" json:"tagName,omitempty"`
+	TagValuePattern   string `protobuf:"bytes,3,opt,name=tagValuePattern,proto3" json:"tagValuePattern,omitempty"`
+	BufferPastNanos   int64  `protobuf:"varint,4,opt,name=bufferPastNanos,proto3" json:"bufferPastNanos,omitempty"`
+	BufferFutureNanos int64  `protobuf:"varint,5,opt,name=bufferFutureNanos,proto3" json:"bufferFutureNanos,omitempty"`
+}
+
+func (m *WriteClass) Reset()                    { *m = WriteClass{} }
+func (m *WriteClass) String() string            { return proto.CompactTextString(m) }
+func (*WriteClass) ProtoMessage()               {}
+func (*WriteClass) Descriptor() ([]byte, []int) { return fileDescriptorNamespace, []int{4} }
+
+func (m *WriteClass) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *WriteClass) GetTagName() string {
+	if m != nil {
+		return m.TagName
+	}
+	return ""
+}
+
+func (m *WriteClass) GetTagValuePattern() string {
+	if m != nil {
+		return m.TagValuePattern
+	}
+	return ""
+}
+
+func (m *WriteClass) GetBufferPastNanos() int64 {
+	if m != nil {
+		return m.BufferPastNanos
+	}
+	return 0
+}
+
+func (m *WriteClass) GetBufferFutureNanos() int64 {
+	if m != nil {
+		return m.BufferFutureNanos
+	}
+	return 0
+}
+
 type NamespaceOptions struct {
-	BootstrapEnabled  bool              `protobuf:"varint,1,opt,name=bootstrapEnabled,proto3" json:"bootstrapEnabled,omitempty"`
-	FlushEnabled      bool              `protobuf:"varint,2,opt,name=flushEnabled,proto3" json:"flushEnabled,omitempty"`
-	WritesToCommitLog bool              `protobuf:"varint,3,opt,name=writesToCommitLog,proto3" json:"writesToCommitLog,omitempty"`
-	CleanupEnabled    bool              `protobuf:"varint,4,opt,name=cleanupEnabled,proto3" json:"cleanupEnabled,omitempty"`
-	RepairEnabled     bool              `protobuf:"varint,5,opt,name=repairEnabled,proto3" json:"repairEnabled,omitempty"`
-	RetentionOptions  *RetentionOptions `protobuf:"bytes,6,opt,name=retentionOptions" json:"retentionOptions,omitempty"`
-	SnapshotEnabled   bool              `protobuf:"varint,7,opt,name=snapshotEnabled,proto3" json:"snapshotEnabled,omitempty"`
-	IndexOptions      *IndexOptions     `protobuf:"bytes,8,opt,name=indexOptions" json:"indexOptions,omitempty"`
-	SchemaOptions     *SchemaOptions    `protobuf:"bytes,9,opt,name=schemaOptions" json:"schemaOptions,omitempty"`
-	ColdWritesEnabled bool              `protobuf:"varint,10,opt,name=coldWritesEnabled,proto3" json:"coldWritesEnabled,omitempty"`
+	BootstrapEnabled      bool                `protobuf:"varint,1,opt,name=bootstrapEnabled,proto3" json:"bootstrapEnabled,omitempty"`
+	FlushEnabled          bool                `protobuf:"varint,2,opt,name=flushEnabled,proto3" json:"flushEnabled,omitempty"`
+	WritesToCommitLog     bool                `protobuf:"varint,3,opt,name=writesToCommitLog,proto3" json:"writesToCommitLog,omitempty"`
+	CleanupEnabled        bool                `protobuf:"varint,4,opt,name=cleanupEnabled,proto3" json:"cleanupEnabled,omitempty"`
+	RepairEnabled         bool                `protobuf:"varint,5,opt,name=repairEnabled,proto3" json:"repairEnabled,omitempty"`
+	RetentionOptions      *RetentionOptions   `protobuf:"bytes,6,opt,name=retentionOptions" json:"retentionOptions,omitempty"`
+	SnapshotEnabled       bool                `protobuf:"varint,7,opt,name=snapshotEnabled,proto3" json:"snapshotEnabled,omitempty"`
+	IndexOptions          *IndexOptions       `protobuf:"bytes,8,opt,name=indexOptions" json:"indexOptions,omitempty"`
+	SchemaOptions         *SchemaOptions      `protobuf:"bytes,9,opt,name=schemaOptions" json:"schemaOptions,omitempty"`
+	ColdWritesEnabled     bool                `protobuf:"varint,10,opt,name=coldWritesEnabled,proto3" json:"coldWritesEnabled,omitempty"`
+	AggregationOptions    *AggregationOptions `protobuf:"bytes,11,opt,name=aggregationOptions" json:"aggregationOptions,omitempty"`
+	ColdTierOptions       *ColdTierOptions    `protobuf:"bytes,12,opt,name=coldTierOptions" json:"coldTierOptions,omitempty"`
+	WriteClasses          []*WriteClass       `protobuf:"bytes,13,rep,name=writeClasses" json:"writeClasses,omitempty"`
+	ValueSummariesEnabled bool                `protobuf:"varint,14,opt,name=valueSummariesEnabled,proto3" json:"valueSummariesEnabled,omitempty"`
+	HistogramsEnabled     bool                `protobuf:"varint,15,opt,name=histogramsEnabled,proto3" json:"histogramsEnabled,omitempty"`
 }
 
 func (m *NamespaceOptions) Reset()                    { *m = NamespaceOptions{} }
 func (m *NamespaceOptions) String() string            { return proto.CompactTextString(m) }
 func (*NamespaceOptions) ProtoMessage()               {}
-func (*NamespaceOptions) Descriptor() ([]byte, []int) { return fileDescriptorNamespace, []int{2} }
+func (*NamespaceOptions) Descriptor() ([]byte, []int) { return fileDescriptorNamespace, []int{5} }
 
 func (m *NamespaceOptions) GetBootstrapEnabled() bool {
 	if m != nil {
@@ -232,6 +384,41 @@ func (m *NamespaceOptions) GetColdWritesEnabled() bool {
 	return false
 }
 
+func (m *NamespaceOptions) GetAggregationOptions() *AggregationOptions {
+	if m != nil {
+		return m.AggregationOptions
+	}
+	return nil
+}
+
+func (m *NamespaceOptions) GetColdTierOptions() *ColdTierOptions {
+	if m != nil {
+		return m.ColdTierOptions
+	}
+	return nil
+}
+
+func (m *NamespaceOptions) GetWriteClasses() []*WriteClass {
+	if m != nil {
+		return m.WriteClasses
+	}
+	return nil
+}
+
+func (m *NamespaceOptions) GetValueSummariesEnabled() bool {
+	if m != nil {
+		return m.ValueSummariesEnabled
+	}
+	return false
+}
+
+func (m *NamespaceOptions) GetHistogramsEnabled() bool {
+	if m != nil {
+		return m.HistogramsEnabled
+	}
+	return false
+}
+
 type Registry struct {
 	Namespaces map[string]*NamespaceOptions `protobuf:"bytes,1,rep,name=namespaces" json:"namespaces,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value"`
 }
@@ -239,7 +426,7 @@ type Registry struct {
 func (m *Registry) Reset()                    { *m = Registry{} }
 func (m *Registry) String() string            { return proto.CompactTextString(m) }
 func (*Registry) ProtoMessage()               {}
-func (*Registry) Descriptor() ([]byte, []int) { return fileDescriptorNamespace, []int{3} }
+func (*Registry) Descriptor() ([]byte, []int) { return fileDescriptorNamespace, []int{6} }
 
 func (m *Registry) GetNamespaces() map[string]*NamespaceOptions {
 	if m != nil {
@@ -251,6 +438,9 @@ func (m *Registry) GetNamespaces() map[string]*NamespaceOptions {
 func init() {
 	proto.RegisterType((*RetentionOptions)(nil), "namespace.RetentionOptions")
 	proto.RegisterType((*IndexOptions)(nil), "namespace.IndexOptions")
+	proto.RegisterType((*AggregationOptions)(nil), "namespace.AggregationOptions")
+	proto.RegisterType((*ColdTierOptions)(nil), "namespace.ColdTierOptions")
+	proto.RegisterType((*WriteClass)(nil), "namespace.WriteClass")
 	proto.RegisterType((*NamespaceOptions)(nil), "namespace.NamespaceOptions")
 	proto.RegisterType((*Registry)(nil), "namespace.Registry")
 }
@@ -342,6 +532,166 @@ func (m *IndexOptions) MarshalTo(dAtA []byte) (int, error) {
 		i++
 		i = encodeVarintNamespace(dAtA, i, uint64(m.BlockSizeNanos))
 	}
+	if len(m.TokenizedFields) > 0 {
+		for _, s := range m.TokenizedFields {
+			dAtA[i] = 0x1a
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
+	}
+	if m.CompactionAgeNanos != 0 {
+		dAtA[i] = 0x20
+		i++
+		i = encodeVarintNamespace(dAtA, i, uint64(m.CompactionAgeNanos))
+	}
+	if m.CompactedBlockSizeNanos != 0 {
+		dAtA[i] = 0x28
+		i++
+		i = encodeVarintNamespace(dAtA, i, uint64(m.CompactedBlockSizeNanos))
+	}
+	return i, nil
+}
+
+func (m *AggregationOptions) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *AggregationOptions) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Enabled {
+		dAtA[i] = 0x8
+		i++
+		if m.Enabled {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	if m.ResolutionNanos != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintNamespace(dAtA, i, uint64(m.ResolutionNanos))
+	}
+	if len(m.SourceNamespace) > 0 {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintNamespace(dAtA, i, uint64(len(m.SourceNamespace)))
+		i += copy(dAtA[i:], m.SourceNamespace)
+	}
+	if len(m.AggregationTypes) > 0 {
+		for _, s := range m.AggregationTypes {
+			dAtA[i] = 0x22
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
+	}
+	if len(m.IdScheme) > 0 {
+		dAtA[i] = 0x2a
+		i++
+		i = encodeVarintNamespace(dAtA, i, uint64(len(m.IdScheme)))
+		i += copy(dAtA[i:], m.IdScheme)
+	}
+	return i, nil
+}
+
+func (m *ColdTierOptions) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ColdTierOptions) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.FilePathPrefix) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintNamespace(dAtA, i, uint64(len(m.FilePathPrefix)))
+		i += copy(dAtA[i:], m.FilePathPrefix)
+	}
+	if m.AgeNanos != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintNamespace(dAtA, i, uint64(m.AgeNanos))
+	}
+	return i, nil
+}
+
+func (m *WriteClass) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *WriteClass) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Name) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintNamespace(dAtA, i, uint64(len(m.Name)))
+		i += copy(dAtA[i:], m.Name)
+	}
+	if len(m.TagName) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintNamespace(dAtA, i, uint64(len(m.TagName)))
+		i += copy(dAtA[i:], m.TagName)
+	}
+	if len(m.TagValuePattern) > 0 {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintNamespace(dAtA, i, uint64(len(m.TagValuePattern)))
+		i += copy(dAtA[i:], m.TagValuePattern)
+	}
+	if m.BufferPastNanos != 0 {
+		dAtA[i] = 0x20
+		i++
+		i = encodeVarintNamespace(dAtA, i, uint64(m.BufferPastNanos))
+	}
+	if m.BufferFutureNanos != 0 {
+		dAtA[i] = 0x28
+		i++
+		i = encodeVarintNamespace(dAtA, i, uint64(m.BufferFutureNanos))
+	}
 	return i, nil
 }
 
@@ -460,6 +810,58 @@ func (m *NamespaceOptions) MarshalTo(dAtA []byte) (int, error) {
 		}
 		i++
 	}
+	if m.AggregationOptions != nil {
+		dAtA[i] = 0x5a
+		i++
+		i = encodeVarintNamespace(dAtA, i, uint64(m.AggregationOptions.Size()))
+		n5, err := m.AggregationOptions.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n5
+	}
+	if m.ColdTierOptions != nil {
+		dAtA[i] = 0x62
+		i++
+		i = encodeVarintNamespace(dAtA, i, uint64(m.ColdTierOptions.Size()))
+		n6, err := m.ColdTierOptions.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n6
+	}
+	if len(m.WriteClasses) > 0 {
+		for _, msg := range m.WriteClasses {
+			dAtA[i] = 0x6a
+			i++
+			i = encodeVarintNamespace(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if m.ValueSummariesEnabled {
+		dAtA[i] = 0x70
+		i++
+		if m.ValueSummariesEnabled {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	if m.HistogramsEnabled {
+		dAtA[i] = 0x78
+		i++
+		if m.HistogramsEnabled {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
 	return i, nil
 }
 
@@ -554,26 +956,101 @@ func (m *IndexOptions) Size() (n int) {
 	if m.BlockSizeNanos != 0 {
 		n += 1 + sovNamespace(uint64(m.BlockSizeNanos))
 	}
+	if len(m.TokenizedFields) > 0 {
+		for _, s := range m.TokenizedFields {
+			l = len(s)
+			n += 1 + l + sovNamespace(uint64(l))
+		}
+	}
+	if m.CompactionAgeNanos != 0 {
+		n += 1 + sovNamespace(uint64(m.CompactionAgeNanos))
+	}
+	if m.CompactedBlockSizeNanos != 0 {
+		n += 1 + sovNamespace(uint64(m.CompactedBlockSizeNanos))
+	}
 	return n
 }
 
-func (m *NamespaceOptions) Size() (n int) {
+func (m *AggregationOptions) Size() (n int) {
 	var l int
 	_ = l
-	if m.BootstrapEnabled {
+	if m.Enabled {
 		n += 2
 	}
-	if m.FlushEnabled {
-		n += 2
+	if m.ResolutionNanos != 0 {
+		n += 1 + sovNamespace(uint64(m.ResolutionNanos))
 	}
-	if m.WritesToCommitLog {
-		n += 2
+	l = len(m.SourceNamespace)
+	if l > 0 {
+		n += 1 + l + sovNamespace(uint64(l))
 	}
-	if m.CleanupEnabled {
-		n += 2
+	if len(m.AggregationTypes) > 0 {
+		for _, s := range m.AggregationTypes {
+			l = len(s)
+			n += 1 + l + sovNamespace(uint64(l))
+		}
 	}
-	if m.RepairEnabled {
-		n += 2
+	l = len(m.IdScheme)
+	if l > 0 {
+		n += 1 + l + sovNamespace(uint64(l))
+	}
+	return n
+}
+
+func (m *ColdTierOptions) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.FilePathPrefix)
+	if l > 0 {
+		n += 1 + l + sovNamespace(uint64(l))
+	}
+	if m.AgeNanos != 0 {
+		n += 1 + sovNamespace(uint64(m.AgeNanos))
+	}
+	return n
+}
+
+func (m *WriteClass) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Name)
+	if l > 0 {
+		n += 1 + l + sovNamespace(uint64(l))
+	}
+	l = len(m.TagName)
+	if l > 0 {
+		n += 1 + l + sovNamespace(uint64(l))
+	}
+	l = len(m.TagValuePattern)
+	if l > 0 {
+		n += 1 + l + sovNamespace(uint64(l))
+	}
+	if m.BufferPastNanos != 0 {
+		n += 1 + sovNamespace(uint64(m.BufferPastNanos))
+	}
+	if m.BufferFutureNanos != 0 {
+		n += 1 + sovNamespace(uint64(m.BufferFutureNanos))
+	}
+	return n
+}
+
+func (m *NamespaceOptions) Size() (n int) {
+	var l int
+	_ = l
+	if m.BootstrapEnabled {
+		n += 2
+	}
+	if m.FlushEnabled {
+		n += 2
+	}
+	if m.WritesToCommitLog {
+		n += 2
+	}
+	if m.CleanupEnabled {
+		n += 2
+	}
+	if m.RepairEnabled {
+		n += 2
 	}
 	if m.RetentionOptions != nil {
 		l = m.RetentionOptions.Size()
@@ -593,6 +1070,26 @@ func (m *NamespaceOptions) Size() (n int) {
 	if m.ColdWritesEnabled {
 		n += 2
 	}
+	if m.AggregationOptions != nil {
+		l = m.AggregationOptions.Size()
+		n += 1 + l + sovNamespace(uint64(l))
+	}
+	if m.ColdTierOptions != nil {
+		l = m.ColdTierOptions.Size()
+		n += 1 + l + sovNamespace(uint64(l))
+	}
+	if len(m.WriteClasses) > 0 {
+		for _, e := range m.WriteClasses {
+			l = e.Size()
+			n += 1 + l + sovNamespace(uint64(l))
+		}
+	}
+	if m.ValueSummariesEnabled {
+		n += 2
+	}
+	if m.HistogramsEnabled {
+		n += 2
+	}
 	return n
 }
 
@@ -880,6 +1377,73 @@ func (m *IndexOptions) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TokenizedFields", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNamespace
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthNamespace
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TokenizedFields = append(m.TokenizedFields, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CompactionAgeNanos", wireType)
+			}
+			m.CompactionAgeNanos = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNamespace
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.CompactionAgeNanos |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CompactedBlockSizeNanos", wireType)
+			}
+			m.CompactedBlockSizeNanos = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNamespace
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.CompactedBlockSizeNanos |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipNamespace(dAtA[iNdEx:])
@@ -901,7 +1465,7 @@ func (m *IndexOptions) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *NamespaceOptions) Unmarshal(dAtA []byte) error {
+func (m *AggregationOptions) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -924,15 +1488,15 @@ func (m *NamespaceOptions) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: NamespaceOptions: wiretype end group for non-group")
+			return fmt.Errorf("proto: AggregationOptions: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: NamespaceOptions: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: AggregationOptions: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field BootstrapEnabled", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Enabled", wireType)
 			}
 			var v int
 			for shift := uint(0); ; shift += 7 {
@@ -949,12 +1513,12 @@ func (m *NamespaceOptions) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
-			m.BootstrapEnabled = bool(v != 0)
+			m.Enabled = bool(v != 0)
 		case 2:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field FlushEnabled", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field ResolutionNanos", wireType)
 			}
-			var v int
+			m.ResolutionNanos = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowNamespace
@@ -964,17 +1528,16 @@ func (m *NamespaceOptions) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				v |= (int(b) & 0x7F) << shift
+				m.ResolutionNanos |= (int64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			m.FlushEnabled = bool(v != 0)
 		case 3:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field WritesToCommitLog", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SourceNamespace", wireType)
 			}
-			var v int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowNamespace
@@ -984,17 +1547,26 @@ func (m *NamespaceOptions) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				v |= (int(b) & 0x7F) << shift
+				stringLen |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			m.WritesToCommitLog = bool(v != 0)
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthNamespace
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.SourceNamespace = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		case 4:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field CleanupEnabled", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AggregationTypes", wireType)
 			}
-			var v int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowNamespace
@@ -1004,37 +1576,26 @@ func (m *NamespaceOptions) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				v |= (int(b) & 0x7F) << shift
+				stringLen |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			m.CleanupEnabled = bool(v != 0)
-		case 5:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field RepairEnabled", wireType)
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthNamespace
 			}
-			var v int
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowNamespace
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				v |= (int(b) & 0x7F) << shift
-				if b < 0x80 {
-					break
-				}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
 			}
-			m.RepairEnabled = bool(v != 0)
-		case 6:
+			m.AggregationTypes = append(m.AggregationTypes, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 5:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field RetentionOptions", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field IdScheme", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowNamespace
@@ -1044,50 +1605,76 @@ func (m *NamespaceOptions) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				stringLen |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthNamespace
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.RetentionOptions == nil {
-				m.RetentionOptions = &RetentionOptions{}
-			}
-			if err := m.RetentionOptions.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			m.IdScheme = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipNamespace(dAtA[iNdEx:])
+			if err != nil {
 				return err
 			}
-			iNdEx = postIndex
-		case 7:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field SnapshotEnabled", wireType)
+			if skippy < 0 {
+				return ErrInvalidLengthNamespace
 			}
-			var v int
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowNamespace
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				v |= (int(b) & 0x7F) << shift
-				if b < 0x80 {
-					break
-				}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
 			}
-			m.SnapshotEnabled = bool(v != 0)
-		case 8:
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ColdTierOptions) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowNamespace
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ColdTierOptions: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ColdTierOptions: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field IndexOptions", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field FilePathPrefix", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowNamespace
@@ -1097,30 +1684,26 @@ func (m *NamespaceOptions) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				stringLen |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthNamespace
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.IndexOptions == nil {
-				m.IndexOptions = &IndexOptions{}
-			}
-			if err := m.IndexOptions.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.FilePathPrefix = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 9:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field SchemaOptions", wireType)
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AgeNanos", wireType)
 			}
-			var msglen int
+			m.AgeNanos = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowNamespace
@@ -1130,7 +1713,437 @@ func (m *NamespaceOptions) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				m.AgeNanos |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipNamespace(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthNamespace
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *WriteClass) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowNamespace
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: WriteClass: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: WriteClass: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNamespace
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthNamespace
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Name = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TagName", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNamespace
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthNamespace
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TagName = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TagValuePattern", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNamespace
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthNamespace
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TagValuePattern = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BufferPastNanos", wireType)
+			}
+			m.BufferPastNanos = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNamespace
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.BufferPastNanos |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BufferFutureNanos", wireType)
+			}
+			m.BufferFutureNanos = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNamespace
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.BufferFutureNanos |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipNamespace(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthNamespace
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *NamespaceOptions) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowNamespace
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: NamespaceOptions: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: NamespaceOptions: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BootstrapEnabled", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNamespace
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.BootstrapEnabled = bool(v != 0)
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FlushEnabled", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNamespace
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.FlushEnabled = bool(v != 0)
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field WritesToCommitLog", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNamespace
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.WritesToCommitLog = bool(v != 0)
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CleanupEnabled", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNamespace
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.CleanupEnabled = bool(v != 0)
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RepairEnabled", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNamespace
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.RepairEnabled = bool(v != 0)
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RetentionOptions", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNamespace
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthNamespace
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.RetentionOptions == nil {
+				m.RetentionOptions = &RetentionOptions{}
+			}
+			if err := m.RetentionOptions.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SnapshotEnabled", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNamespace
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.SnapshotEnabled = bool(v != 0)
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field IndexOptions", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNamespace
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthNamespace
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.IndexOptions == nil {
+				m.IndexOptions = &IndexOptions{}
+			}
+			if err := m.IndexOptions.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SchemaOptions", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNamespace
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -1169,6 +2182,143 @@ func (m *NamespaceOptions) Unmarshal(dAtA []byte) error {
 				}
 			}
 			m.ColdWritesEnabled = bool(v != 0)
+		case 11:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AggregationOptions", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNamespace
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthNamespace
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.AggregationOptions == nil {
+				m.AggregationOptions = &AggregationOptions{}
+			}
+			if err := m.AggregationOptions.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 12:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ColdTierOptions", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNamespace
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthNamespace
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.ColdTierOptions == nil {
+				m.ColdTierOptions = &ColdTierOptions{}
+			}
+			if err := m.ColdTierOptions.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 13:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field WriteClasses", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNamespace
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthNamespace
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.WriteClasses = append(m.WriteClasses, &WriteClass{})
+			if err := m.WriteClasses[len(m.WriteClasses)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 14:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ValueSummariesEnabled", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNamespace
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.ValueSummariesEnabled = bool(v != 0)
+		case 15:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field HistogramsEnabled", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNamespace
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.HistogramsEnabled = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipNamespace(dAtA[iNdEx:])
@@ -1473,41 +2623,60 @@ func init() {
 }
 
 var fileDescriptorNamespace = []byte{
-	// 575 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x94, 0x51, 0x6b, 0xd3, 0x50,
-	0x14, 0xc7, 0x4d, 0xb3, 0xad, 0xed, 0x59, 0xe7, 0xe2, 0x45, 0xb0, 0x54, 0x28, 0xa3, 0x8a, 0x14,
-	0x91, 0x06, 0xdb, 0x17, 0x51, 0x18, 0xcc, 0xad, 0x0e, 0x41, 0x6a, 0xb9, 0x13, 0x84, 0xbd, 0xdd,
-	0x24, 0xa7, 0x6d, 0x58, 0x92, 0x1b, 0xee, 0xbd, 0xd1, 0xd5, 0xcf, 0xe0, 0x83, 0xdf, 0xc3, 0x2f,
-	0xe2, 0xa3, 0x1f, 0x41, 0xea, 0xd7, 0xf0, 0x41, 0x72, 0x63, 0xba, 0x24, 0x1d, 0x32, 0xf6, 0x52,
-	0x6e, 0xff, 0xe7, 0x77, 0xce, 0xb9, 0x3d, 0xff, 0x73, 0x0b, 0xa7, 0x73, 0x5f, 0x2d, 0x12, 0x67,
-	0xe0, 0xf2, 0xd0, 0x0e, 0x47, 0x9e, 0x63, 0x87, 0x23, 0x5b, 0x0a, 0xd7, 0xf6, 0x9c, 0x88, 0x7b,
-	0x68, 0xcf, 0x31, 0x42, 0xc1, 0x14, 0x7a, 0x76, 0x2c, 0xb8, 0xe2, 0x76, 0xc4, 0x42, 0x94, 0x31,
-	0x73, 0xf1, 0xea, 0x34, 0xd0, 0x11, 0xd2, 0x5c, 0x0b, 0x9d, 0x93, 0xdb, 0xd6, 0x94, 0xee, 0x02,
-	0x43, 0x96, 0x15, 0xec, 0x7d, 0x35, 0xc1, 0xa2, 0xa8, 0x30, 0x52, 0x3e, 0x8f, 0xde, 0xc7, 0xe9,
-	0xa7, 0x24, 0x43, 0xb8, 0x2f, 0x72, 0x6d, 0x8a, 0xc2, 0xe7, 0xde, 0x84, 0x45, 0x5c, 0xb6, 0x8d,
-	0x03, 0xa3, 0x6f, 0xd2, 0x6b, 0x63, 0xe4, 0x09, 0xdc, 0x75, 0x02, 0xee, 0x5e, 0x9c, 0xf9, 0x5f,
-	0x30, 0xa3, 0x6b, 0x9a, 0xae, 0xa8, 0xe4, 0x19, 0xdc, 0x73, 0x92, 0xd9, 0x0c, 0xc5, 0x9b, 0x44,
-	0x25, 0xe2, 0x1f, 0x6a, 0x6a, 0x74, 0x33, 0x40, 0xfa, 0xb0, 0x9f, 0x89, 0x53, 0x26, 0x55, 0xc6,
-	0x6e, 0x69, 0xb6, 0x2a, 0x6b, 0x32, 0xed, 0x74, 0xc2, 0x14, 0x1b, 0x5f, 0xc6, 0xbe, 0x58, 0xb6,
-	0xb7, 0x0f, 0x8c, 0x7e, 0x83, 0x56, 0x65, 0x72, 0x0e, 0xfd, 0x8a, 0x74, 0x34, 0x53, 0x28, 0x26,
-	0x5c, 0x1d, 0xb9, 0x2e, 0x4a, 0x59, 0xfc, 0xc5, 0x3b, 0xba, 0xd9, 0x8d, 0x79, 0x72, 0x08, 0x9d,
-	0x99, 0xbe, 0x3e, 0xbd, 0x6e, 0x7e, 0x75, 0x5d, 0xed, 0x3f, 0x44, 0x6f, 0x0a, 0xad, 0xb7, 0x91,
-	0x87, 0x97, 0xb9, 0x13, 0x6d, 0xa8, 0x63, 0xc4, 0x9c, 0x00, 0x3d, 0x3d, 0xfc, 0x06, 0xcd, 0xbf,
-	0xde, 0x74, 0xde, 0xbd, 0x3f, 0x26, 0x58, 0x93, 0xdc, 0xfb, 0xbc, 0xec, 0x53, 0xb0, 0x1c, 0xce,
-	0x95, 0x54, 0x82, 0xc5, 0xe3, 0x52, 0xfd, 0x0d, 0x9d, 0xf4, 0xa0, 0x35, 0x0b, 0x12, 0xb9, 0xc8,
-	0xb9, 0x9a, 0xe6, 0x4a, 0x5a, 0x6a, 0xea, 0x67, 0xe1, 0x2b, 0x94, 0x1f, 0xf8, 0x31, 0x0f, 0x43,
-	0x5f, 0xbd, 0xe3, 0x73, 0x6d, 0x6a, 0x83, 0x6e, 0x06, 0xd2, 0xab, 0xbb, 0x01, 0xb2, 0x28, 0x59,
-	0xf7, 0xde, 0xd2, 0x68, 0x45, 0x25, 0x8f, 0x61, 0x4f, 0x60, 0xcc, 0x7c, 0x91, 0x63, 0x99, 0xa1,
-	0x65, 0x91, 0x9c, 0x82, 0x25, 0x2a, 0x0b, 0xac, 0x6d, 0xdb, 0x1d, 0x3e, 0x1c, 0x5c, 0x3d, 0x9f,
-	0xea, 0x8e, 0xd3, 0x8d, 0xa4, 0x74, 0x83, 0x64, 0xc4, 0x62, 0xb9, 0xe0, 0x2a, 0x6f, 0x58, 0xcf,
-	0x36, 0xa8, 0x22, 0x93, 0x57, 0xd0, 0xf2, 0x0b, 0x2e, 0xb5, 0x1b, 0xba, 0xdd, 0x83, 0x42, 0xbb,
-	0xa2, 0x89, 0xb4, 0x04, 0x93, 0x43, 0xd8, 0xcb, 0x5e, 0x60, 0x9e, 0xdd, 0xd4, 0xd9, 0xed, 0x42,
-	0xf6, 0x59, 0x31, 0x4e, 0xcb, 0x78, 0x3a, 0x6b, 0x97, 0x07, 0xde, 0x47, 0x3d, 0xd6, 0xfc, 0xa2,
-	0x90, 0xcd, 0x7a, 0x23, 0xd0, 0xfb, 0x6e, 0x40, 0x83, 0xe2, 0xdc, 0x97, 0x4a, 0x2c, 0xc9, 0x31,
-	0xc0, 0xba, 0x49, 0xfa, 0x9a, 0xcd, 0xfe, 0xee, 0xf0, 0x51, 0x69, 0x48, 0x19, 0x38, 0x58, 0x2f,
-	0x8c, 0x1c, 0x47, 0x4a, 0x2c, 0x69, 0x21, 0xad, 0x73, 0x0e, 0xfb, 0x95, 0x30, 0xb1, 0xc0, 0xbc,
-	0xc0, 0xa5, 0xde, 0xa0, 0x26, 0x4d, 0x8f, 0xe4, 0x39, 0x6c, 0x7f, 0x62, 0x41, 0x82, 0x7a, 0x5b,
-	0xca, 0x4e, 0x54, 0x97, 0x91, 0x66, 0xe4, 0xcb, 0xda, 0x0b, 0xe3, 0xb5, 0xf5, 0x63, 0xd5, 0x35,
-	0x7e, 0xae, 0xba, 0xc6, 0xaf, 0x55, 0xd7, 0xf8, 0xf6, 0xbb, 0x7b, 0xc7, 0xd9, 0xd1, 0x7f, 0x53,
-	0xa3, 0xbf, 0x01, 0x00, 0x00, 0xff, 0xff, 0x60, 0xfa, 0xeb, 0x0c, 0x42, 0x05, 0x00, 0x00,
+	// 877 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0x03, 0x9d, 0x56, 0xcd, 0x6e, 0xdb, 0x46,
+	0x10, 0xae, 0x2c, 0xff, 0x48, 0x63, 0x39, 0x52, 0x17, 0x35, 0x22, 0x28, 0x48, 0x10, 0xa8, 0x45,
+	0x61, 0x14, 0x85, 0x84, 0x38, 0x39, 0x38, 0x29, 0x10, 0xc0, 0xb1, 0x93, 0x20, 0x40, 0x92, 0x1a,
+	0x6b, 0xb7, 0x05, 0x72, 0x5b, 0x91, 0x23, 0x8a, 0x30, 0xc9, 0x15, 0x76, 0x97, 0x8d, 0xdd, 0x67,
+	0xe8, 0xa1, 0xef, 0x91, 0x4b, 0x1f, 0xa0, 0x0f, 0x50, 0xf4, 0x94, 0x47, 0x08, 0x92, 0x3e, 0x48,
+	0x77, 0x97, 0x22, 0x45, 0x2e, 0xe5, 0x22, 0xc9, 0x41, 0x04, 0xf7, 0x9b, 0x6f, 0x76, 0x76, 0xbe,
+	0x9d, 0x19, 0x0a, 0x9e, 0x06, 0xa1, 0x9a, 0xa5, 0x93, 0x91, 0xc7, 0xe3, 0x71, 0x7c, 0xd7, 0x9f,
+	0xe8, 0xc7, 0x58, 0x0a, 0x6f, 0xec, 0x4f, 0x12, 0xee, 0xe3, 0x38, 0xc0, 0x04, 0x05, 0x53, 0xe8,
+	0x8f, 0xe7, 0x82, 0x2b, 0x3e, 0x4e, 0x58, 0x8c, 0x72, 0xce, 0x3c, 0x5c, 0xbe, 0x8d, 0xac, 0x85,
+	0xb4, 0x0b, 0x60, 0x70, 0xfc, 0xb9, 0x7b, 0x4a, 0x6f, 0x86, 0x31, 0xcb, 0x36, 0x1c, 0xfe, 0xde,
+	0x84, 0x1e, 0x45, 0x85, 0x89, 0x0a, 0x79, 0xf2, 0xe3, 0xdc, 0x3c, 0x25, 0xd9, 0x87, 0xaf, 0x44,
+	0x8e, 0x9d, 0xa0, 0x08, 0xb9, 0xff, 0x92, 0x25, 0x5c, 0xf6, 0x1b, 0xb7, 0x1b, 0x7b, 0x4d, 0xba,
+	0xd2, 0x46, 0xbe, 0x85, 0x6b, 0x93, 0x88, 0x7b, 0xe7, 0xa7, 0xe1, 0x6f, 0x98, 0xb1, 0xd7, 0x2c,
+	0xdb, 0x41, 0xc9, 0xf7, 0xf0, 0xe5, 0x24, 0x9d, 0x4e, 0x51, 0x3c, 0x49, 0x55, 0x2a, 0x16, 0xd4,
+	0xa6, 0xa5, 0xd6, 0x0d, 0x64, 0x0f, 0xba, 0x19, 0x78, 0xc2, 0xa4, 0xca, 0xb8, 0xeb, 0x96, 0xeb,
+	0xc2, 0x96, 0x69, 0x22, 0x1d, 0x33, 0xc5, 0x1e, 0x5f, 0xcc, 0x43, 0x71, 0xd9, 0xdf, 0xd0, 0xcc,
+	0x16, 0x75, 0x61, 0xf2, 0x0a, 0xf6, 0x1c, 0xe8, 0x70, 0xaa, 0x50, 0xbc, 0xe4, 0xea, 0xd0, 0xf3,
+	0x50, 0xca, 0x72, 0xc6, 0x9b, 0x36, 0xd8, 0x47, 0xf3, 0xc9, 0x43, 0x18, 0x4c, 0xed, 0xf1, 0xe9,
+	0x2a, 0xfd, 0xb6, 0xec, 0x6e, 0xff, 0xc3, 0x18, 0xfe, 0xdb, 0x80, 0xce, 0xb3, 0xc4, 0xc7, 0x8b,
+	0xfc, 0x2a, 0xfa, 0xb0, 0x85, 0x09, 0x9b, 0x44, 0xe8, 0x5b, 0xf5, 0x5b, 0x34, 0x5f, 0x7e, 0xb4,
+	0xe0, 0x5a, 0x18, 0xc5, 0xcf, 0x31, 0xd1, 0x80, 0xff, 0x24, 0xc4, 0xc8, 0x37, 0x72, 0x37, 0xf7,
+	0xda, 0xd4, 0x85, 0xc9, 0x08, 0x88, 0x2e, 0x26, 0x5d, 0x23, 0x26, 0xf4, 0x61, 0x80, 0x65, 0xbd,
+	0x57, 0x58, 0xc8, 0x01, 0x5c, 0x5f, 0xa0, 0xe8, 0x3f, 0xaa, 0x1e, 0x65, 0xc3, 0x3a, 0x5d, 0x65,
+	0x1e, 0xfe, 0xd3, 0x00, 0x72, 0x18, 0x04, 0x02, 0x03, 0x56, 0xae, 0xbb, 0xab, 0x93, 0xd5, 0x49,
+	0x08, 0x94, 0x3c, 0x4a, 0x0d, 0xb1, 0x9c, 0xad, 0x0b, 0x1b, 0xa6, 0xe4, 0xa9, 0xf0, 0x74, 0xa4,
+	0x45, 0xc1, 0xdb, 0xea, 0xd2, 0xe9, 0x3a, 0x30, 0xf9, 0x0e, 0x7a, 0x6c, 0x79, 0x86, 0xb3, 0xcb,
+	0x39, 0x9a, 0x64, 0x8d, 0x32, 0x35, 0x9c, 0x0c, 0xa0, 0x15, 0xfa, 0xa7, 0xa6, 0x71, 0xd0, 0xe6,
+	0xd6, 0xa6, 0xc5, 0x7a, 0xf8, 0x13, 0x74, 0x8f, 0x78, 0xe4, 0x9f, 0x85, 0x28, 0xf2, 0x44, 0xf4,
+	0xdd, 0x4c, 0xc3, 0x08, 0x4f, 0x98, 0x9a, 0x9d, 0x08, 0x9c, 0x86, 0x17, 0x36, 0x9f, 0x36, 0x75,
+	0x50, 0xb3, 0x2d, 0x0b, 0x2a, 0xb7, 0x57, 0xac, 0x87, 0x7f, 0x35, 0x00, 0x7e, 0x11, 0xa1, 0xc2,
+	0xa3, 0x88, 0x49, 0x49, 0x08, 0xac, 0x9b, 0x16, 0x5e, 0x6c, 0x64, 0xdf, 0x8d, 0x5e, 0x8a, 0x05,
+	0x26, 0x23, 0xeb, 0xdd, 0xa6, 0xf9, 0xd2, 0x5e, 0x3a, 0x0b, 0x7e, 0x66, 0x51, 0x6a, 0xc2, 0xe9,
+	0x6a, 0x4d, 0x72, 0x15, 0x1c, 0xf8, 0x13, 0x3a, 0x6c, 0x65, 0xe7, 0x6e, 0x5c, 0xd1, 0xb9, 0xc3,
+	0x3f, 0x37, 0xa1, 0x57, 0x68, 0x9d, 0xeb, 0xa2, 0x25, 0x9f, 0x70, 0xae, 0xa4, 0x12, 0x6c, 0xfe,
+	0xb8, 0x72, 0xd3, 0x35, 0x9c, 0x0c, 0xa1, 0x33, 0x8d, 0x52, 0x39, 0xcb, 0x79, 0x6b, 0x96, 0x57,
+	0xc1, 0xcc, 0x91, 0x5e, 0x1b, 0x89, 0xe4, 0x19, 0x3f, 0xe2, 0x71, 0x1c, 0xaa, 0xe7, 0x3c, 0xb0,
+	0x89, 0xb6, 0x68, 0xdd, 0x60, 0x6e, 0xc5, 0x8b, 0x90, 0x25, 0x69, 0x11, 0x7b, 0xdd, 0x52, 0x1d,
+	0x94, 0x7c, 0x03, 0x3b, 0x02, 0xe7, 0x2c, 0x14, 0x39, 0x2d, 0x1b, 0x24, 0x55, 0x90, 0x3c, 0x85,
+	0x9e, 0x70, 0x06, 0xa7, 0x1d, 0x17, 0xdb, 0xfb, 0x37, 0x46, 0xcb, 0xb1, 0xed, 0xce, 0x56, 0x5a,
+	0x73, 0xb2, 0x15, 0x9b, 0xb0, 0xb9, 0x9c, 0x71, 0x95, 0x07, 0xdc, 0xca, 0x26, 0x97, 0x03, 0x93,
+	0x1f, 0xa0, 0x13, 0x96, 0x86, 0x43, 0xbf, 0x65, 0xc3, 0x5d, 0x2f, 0x85, 0x2b, 0xcf, 0x0e, 0x5a,
+	0x21, 0xeb, 0xd1, 0xb4, 0x93, 0x4d, 0xfe, 0xdc, 0xbb, 0x6d, 0xbd, 0xfb, 0x25, 0xef, 0xd3, 0xb2,
+	0x9d, 0x56, 0xe9, 0x46, 0x6b, 0x4f, 0x97, 0xb9, 0x2d, 0x49, 0x99, 0x1f, 0x14, 0x32, 0xad, 0x6b,
+	0x06, 0xf2, 0x02, 0x08, 0xab, 0x35, 0x78, 0x7f, 0xdb, 0x86, 0xbc, 0x59, 0x0a, 0x59, 0x9f, 0x02,
+	0x74, 0x85, 0x23, 0x39, 0x86, 0xae, 0x57, 0xed, 0xb1, 0x7e, 0xc7, 0xee, 0x35, 0x28, 0xed, 0xe5,
+	0x74, 0x21, 0x75, 0x5d, 0xc8, 0x7d, 0xe8, 0xbc, 0x2e, 0x3a, 0x4a, 0x77, 0xfb, 0x8e, 0xee, 0xf6,
+	0xed, 0xfd, 0xdd, 0xd2, 0x16, 0xcb, 0x86, 0xa3, 0x15, 0x2a, 0xb9, 0x07, 0xbb, 0xbf, 0x9a, 0xb6,
+	0x39, 0x4d, 0xe3, 0x98, 0x89, 0x70, 0xa9, 0xc0, 0x35, 0xab, 0xc0, 0x6a, 0xa3, 0xd1, 0x6c, 0x16,
+	0x4a, 0xc5, 0x03, 0xc1, 0xe2, 0xc2, 0xa3, 0x9b, 0x69, 0x56, 0x33, 0x0c, 0xdf, 0x34, 0xa0, 0x45,
+	0x31, 0xd0, 0xb8, 0xfe, 0x4a, 0x1d, 0x01, 0x14, 0xc7, 0x32, 0x5f, 0x5e, 0x73, 0xd2, 0xaf, 0x2b,
+	0x85, 0x95, 0x11, 0x47, 0x45, 0x93, 0xe9, 0x7d, 0xf4, 0x9a, 0x96, 0xdc, 0x06, 0xaf, 0xa0, 0xeb,
+	0x98, 0x49, 0x0f, 0x9a, 0xe7, 0x78, 0xb9, 0x18, 0x23, 0xe6, 0x95, 0xdc, 0x81, 0x0d, 0x7b, 0x7a,
+	0xdb, 0x61, 0xd5, 0xea, 0x75, 0x1b, 0x98, 0x66, 0xcc, 0x07, 0x6b, 0x07, 0x8d, 0x47, 0xbd, 0xbf,
+	0xdf, 0xdf, 0x6a, 0xbc, 0xd5, 0xbf, 0x77, 0xfa, 0xf7, 0xc7, 0x87, 0x5b, 0x5f, 0x4c, 0x36, 0xed,
+	0x5f, 0x8a, 0xbb, 0xff, 0x01, 0x71, 0x36, 0x27, 0x58, 0xee, 0x08, 0x00, 0x00,
 }
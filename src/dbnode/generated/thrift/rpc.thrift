@@ -29,7 +29,8 @@ enum TimeType {
 
 enum ErrorType {
 	INTERNAL_ERROR,
-	BAD_REQUEST
+	BAD_REQUEST,
+	RESOURCE_EXHAUSTED
 }
 
 exception Error {
@@ -63,6 +64,9 @@ service Node {
 	void writeTaggedBatchRawV2(1: WriteTaggedBatchRawV2Request req) throws (1: WriteBatchRawErrors err)
 	void repair() throws (1: Error err)
 	TruncateResult truncate(1: TruncateRequest req) throws (1: Error err)
+	DeleteTaggedResult deleteTagged(1: DeleteTaggedRequest req) throws (1: Error err)
+	BackupResult backup(1: BackupRequest req) throws (1: Error err)
+	IndexCardinalityResult indexCardinality(1: IndexCardinalityRequest req) throws (1: Error err)
 
 	// Management endpoints
 	NodeHealthResult health() throws (1: Error err)
@@ -164,11 +168,34 @@ struct FetchTaggedRequest {
 	5: required bool fetchData
 	6: optional i64 limit
 	7: optional TimeType rangeTimeType = TimeType.UNIX_SECONDS
+	8: optional binary pageToken
+	9: optional ValueFilter valueFilter
+	10: optional bool dictionaryEncodedTags
+}
+
+enum ValueFilterType {
+	EQUAL,
+	NOT_EQUAL,
+	GREATER,
+	GREATER_EQUAL,
+	LESS,
+	LESS_EQUAL
+}
+
+// ValueFilter skips series whose values in the query range are known not to
+// satisfy the comparison, it only prunes results and is not a substitute
+// for applying the comparison to the returned values.
+struct ValueFilter {
+	1: required ValueFilterType type
+	2: required double value
+	3: optional bool latest
 }
 
 struct FetchTaggedResult {
 	1: required list<FetchTaggedIDResult> elements
 	2: required bool exhaustive
+	3: optional binary nextPageToken
+	4: optional binary tagDictionary
 }
 
 struct FetchTaggedIDResult {
@@ -379,11 +406,13 @@ struct AggregateQueryRawRequest {
 	6: optional list<binary> tagNameFilter
 	7: optional AggregateQueryType aggregateQueryType = AggregateQueryType.AGGREGATE_BY_TAG_NAME_VALUE
 	8: optional TimeType rangeType = TimeType.UNIX_SECONDS
+	9: optional binary pageToken
 }
 
 struct AggregateQueryRawResult {
 	1: required list<AggregateQueryRawResultTagNameElement> results
 	2: required bool exhaustive
+	3: optional binary nextPageToken
 }
 
 struct AggregateQueryRawResultTagNameElement {
@@ -481,3 +510,57 @@ struct Query {
   6: optional AllQuery         all
   7: optional FieldQuery       field
 }
+
+struct DeleteTaggedRequest {
+	1: required binary nameSpace
+	2: required binary query
+	3: required i64 rangeStart
+	4: required i64 rangeEnd
+	5: optional TimeType rangeTimeType = TimeType.UNIX_SECONDS
+}
+
+struct DeleteTaggedResult {
+	1: required i64 numSeries
+}
+
+struct BackupRequest {
+	1: required binary nameSpace
+	2: required string backupId
+	3: required string destination
+}
+
+struct BackupResult {
+	1: required i64 numFiles
+	2: required i64 numBytes
+}
+
+struct IndexCardinalityRequest {
+	1: required binary nameSpace
+	2: required i64 rangeStart
+	3: required i64 rangeEnd
+	4: optional list<binary> tagNameFilter
+	5: optional i64 limit
+	6: optional TimeType rangeTimeType = TimeType.UNIX_SECONDS
+	7: optional binary query
+	8: optional binary matchPartial
+	9: optional i32 matchMaxEdits
+	10: optional bool matchTagNames
+}
+
+struct IndexCardinalityResult {
+	1: required i64 numSeries
+	2: required list<IndexCardinalityTagName> tagNames
+	3: required list<IndexCardinalityTag> tags
+}
+
+struct IndexCardinalityTagName {
+	1: required binary name
+	2: required i64 numValues
+	3: required i64 numSeries
+}
+
+struct IndexCardinalityTag {
+	1: required binary name
+	2: required binary value
+	3: required i64 numSeries
+}
@@ -210,6 +210,21 @@ func (mr *MockTChanNodeMockRecorder) AggregateRaw(ctx, req interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AggregateRaw", reflect.TypeOf((*MockTChanNode)(nil).AggregateRaw), ctx, req)
 }
 
+// Backup mocks base method
+func (m *MockTChanNode) Backup(ctx thrift.Context, req *BackupRequest) (*BackupResult_, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Backup", ctx, req)
+	ret0, _ := ret[0].(*BackupResult_)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Backup indicates an expected call of Backup
+func (mr *MockTChanNodeMockRecorder) Backup(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Backup", reflect.TypeOf((*MockTChanNode)(nil).Backup), ctx, req)
+}
+
 // Bootstrapped mocks base method
 func (m *MockTChanNode) Bootstrapped(ctx thrift.Context) (*NodeBootstrappedResult_, error) {
 	m.ctrl.T.Helper()
@@ -240,6 +255,21 @@ func (mr *MockTChanNodeMockRecorder) BootstrappedInPlacementOrNoPlacement(ctx in
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BootstrappedInPlacementOrNoPlacement", reflect.TypeOf((*MockTChanNode)(nil).BootstrappedInPlacementOrNoPlacement), ctx)
 }
 
+// DeleteTagged mocks base method
+func (m *MockTChanNode) DeleteTagged(ctx thrift.Context, req *DeleteTaggedRequest) (*DeleteTaggedResult_, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTagged", ctx, req)
+	ret0, _ := ret[0].(*DeleteTaggedResult_)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteTagged indicates an expected call of DeleteTagged
+func (mr *MockTChanNodeMockRecorder) DeleteTagged(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTagged", reflect.TypeOf((*MockTChanNode)(nil).DeleteTagged), ctx, req)
+}
+
 // Fetch mocks base method
 func (m *MockTChanNode) Fetch(ctx thrift.Context, req *FetchRequest) (*FetchResult_, error) {
 	m.ctrl.T.Helper()
@@ -405,6 +435,21 @@ func (mr *MockTChanNodeMockRecorder) Health(ctx interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Health", reflect.TypeOf((*MockTChanNode)(nil).Health), ctx)
 }
 
+// IndexCardinality mocks base method
+func (m *MockTChanNode) IndexCardinality(ctx thrift.Context, req *IndexCardinalityRequest) (*IndexCardinalityResult_, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IndexCardinality", ctx, req)
+	ret0, _ := ret[0].(*IndexCardinalityResult_)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IndexCardinality indicates an expected call of IndexCardinality
+func (mr *MockTChanNodeMockRecorder) IndexCardinality(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IndexCardinality", reflect.TypeOf((*MockTChanNode)(nil).IndexCardinality), ctx, req)
+}
+
 // Query mocks base method
 func (m *MockTChanNode) Query(ctx thrift.Context, req *QueryRequest) (*QueryResult_, error) {
 	m.ctrl.T.Helper()
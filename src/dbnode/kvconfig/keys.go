@@ -33,6 +33,10 @@ const (
 	// configuration specifying a hard limit for a cluster new series insertions.
 	ClusterNewSeriesInsertLimitKey = "m3db.node.cluster-new-series-insert-limit"
 
+	// QuotasKey is the KV config key for the runtime configuration
+	// specifying the per namespace and per tenant quotas as YAML.
+	QuotasKey = "m3db.node.quotas"
+
 	// ClientBootstrapConsistencyLevel is the KV config key for the runtime
 	// configuration specifying the client bootstrap consistency level
 	ClientBootstrapConsistencyLevel = "m3db.client.bootstrap-consistency-level"
@@ -0,0 +1,143 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package namespace
+
+import (
+	"time"
+
+	"github.com/m3db/m3/src/metrics/aggregation"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/x/ident"
+)
+
+const (
+	// AggregationTypeTagName is the name of the tag added to rolled up series
+	// to distinguish the series of each aggregation type of an aggregated
+	// namespace.
+	AggregationTypeTagName = "__rollup_type__"
+)
+
+var (
+	// defaultAggregationEnabled disables aggregation by default.
+	defaultAggregationEnabled = false
+
+	// defaultAggregationTypes are the aggregation types used when none
+	// are specified.
+	defaultAggregationTypes = aggregation.Types{aggregation.Last}
+
+	// defaultAggregationIDScheme is the scheme used to generate the IDs of
+	// rolled up series when none is specified.
+	defaultAggregationIDScheme = models.TypeQuoted
+)
+
+type aggregationOpts struct {
+	enabled          bool
+	resolution       time.Duration
+	sourceNamespace  ident.ID
+	aggregationTypes aggregation.Types
+	idScheme         models.IDSchemeType
+}
+
+// NewAggregationOptions returns a new AggregationOptions.
+func NewAggregationOptions() AggregationOptions {
+	return &aggregationOpts{
+		enabled:          defaultAggregationEnabled,
+		aggregationTypes: defaultAggregationTypes,
+		idScheme:         defaultAggregationIDScheme,
+	}
+}
+
+func (a *aggregationOpts) Equal(value AggregationOptions) bool {
+	if a.Enabled() != value.Enabled() ||
+		a.Resolution() != value.Resolution() ||
+		a.IDScheme() != value.IDScheme() {
+		return false
+	}
+
+	ourSource, theirSource := a.SourceNamespace(), value.SourceNamespace()
+	if (ourSource == nil) != (theirSource == nil) {
+		return false
+	}
+	if ourSource != nil && !ourSource.Equal(theirSource) {
+		return false
+	}
+
+	ourTypes, theirTypes := a.AggregationTypes(), value.AggregationTypes()
+	if len(ourTypes) != len(theirTypes) {
+		return false
+	}
+	for i := range ourTypes {
+		if ourTypes[i] != theirTypes[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (a *aggregationOpts) SetEnabled(value bool) AggregationOptions {
+	ao := *a
+	ao.enabled = value
+	return &ao
+}
+
+func (a *aggregationOpts) Enabled() bool {
+	return a.enabled
+}
+
+func (a *aggregationOpts) SetResolution(value time.Duration) AggregationOptions {
+	ao := *a
+	ao.resolution = value
+	return &ao
+}
+
+func (a *aggregationOpts) Resolution() time.Duration {
+	return a.resolution
+}
+
+func (a *aggregationOpts) SetSourceNamespace(value ident.ID) AggregationOptions {
+	ao := *a
+	ao.sourceNamespace = value
+	return &ao
+}
+
+func (a *aggregationOpts) SourceNamespace() ident.ID {
+	return a.sourceNamespace
+}
+
+func (a *aggregationOpts) SetAggregationTypes(value aggregation.Types) AggregationOptions {
+	ao := *a
+	ao.aggregationTypes = value
+	return &ao
+}
+
+func (a *aggregationOpts) AggregationTypes() aggregation.Types {
+	return a.aggregationTypes
+}
+
+func (a *aggregationOpts) SetIDScheme(value models.IDSchemeType) AggregationOptions {
+	ao := *a
+	ao.idScheme = value
+	return &ao
+}
+
+func (a *aggregationOpts) IDScheme() models.IDSchemeType {
+	return a.idScheme
+}
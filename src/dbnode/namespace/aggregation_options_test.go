@@ -0,0 +1,72 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package namespace
+
+import (
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/metrics/aggregation"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/x/ident"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAggregationOptionsEqual(t *testing.T) {
+	opts := NewAggregationOptions()
+	require.True(t, opts.Equal(opts.SetEnabled(false)))
+	require.False(t, opts.SetEnabled(true).Equal(opts.SetEnabled(false)))
+	require.False(t, opts.SetResolution(time.Minute).Equal(
+		opts.SetResolution(time.Hour)))
+	require.False(t, opts.SetSourceNamespace(ident.StringID("a")).Equal(opts))
+	require.False(t, opts.SetSourceNamespace(ident.StringID("a")).Equal(
+		opts.SetSourceNamespace(ident.StringID("b"))))
+	require.True(t, opts.SetSourceNamespace(ident.StringID("a")).Equal(
+		opts.SetSourceNamespace(ident.StringID("a"))))
+	require.False(t, opts.SetAggregationTypes(aggregation.Types{aggregation.Max}).Equal(
+		opts.SetAggregationTypes(aggregation.Types{aggregation.Min})))
+	require.False(t, opts.SetIDScheme(models.TypeQuoted).Equal(
+		opts.SetIDScheme(models.TypePrependMeta)))
+}
+
+func TestAggregationOptionsDefaults(t *testing.T) {
+	opts := NewAggregationOptions()
+	require.False(t, opts.Enabled())
+	require.Nil(t, opts.SourceNamespace())
+	require.Equal(t, aggregation.Types{aggregation.Last}, opts.AggregationTypes())
+	require.Equal(t, models.TypeQuoted, opts.IDScheme())
+}
+
+func TestAggregationOptionsSetters(t *testing.T) {
+	opts := NewAggregationOptions().
+		SetEnabled(true).
+		SetResolution(time.Minute).
+		SetSourceNamespace(ident.StringID("raw")).
+		SetAggregationTypes(aggregation.Types{aggregation.Sum, aggregation.Max}).
+		SetIDScheme(models.TypePrependMeta)
+	require.True(t, opts.Enabled())
+	require.Equal(t, time.Minute, opts.Resolution())
+	require.Equal(t, "raw", opts.SourceNamespace().String())
+	require.Equal(t, aggregation.Types{aggregation.Sum, aggregation.Max},
+		opts.AggregationTypes())
+	require.Equal(t, models.TypePrependMeta, opts.IDScheme())
+}
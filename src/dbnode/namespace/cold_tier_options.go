@@ -0,0 +1,72 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package namespace
+
+import (
+	"time"
+)
+
+const (
+	// defaultColdTierAge is the age after which blocks are moved to the cold
+	// tier when none is specified.
+	defaultColdTierAge = 48 * time.Hour
+)
+
+type coldTierOpts struct {
+	filePathPrefix string
+	age            time.Duration
+}
+
+// NewColdTierOptions returns a new ColdTierOptions.
+func NewColdTierOptions() ColdTierOptions {
+	return &coldTierOpts{
+		age: defaultColdTierAge,
+	}
+}
+
+func (c *coldTierOpts) Equal(value ColdTierOptions) bool {
+	return c.FilePathPrefix() == value.FilePathPrefix() &&
+		c.Age() == value.Age()
+}
+
+func (c *coldTierOpts) Enabled() bool {
+	return c.filePathPrefix != ""
+}
+
+func (c *coldTierOpts) SetFilePathPrefix(value string) ColdTierOptions {
+	co := *c
+	co.filePathPrefix = value
+	return &co
+}
+
+func (c *coldTierOpts) FilePathPrefix() string {
+	return c.filePathPrefix
+}
+
+func (c *coldTierOpts) SetAge(value time.Duration) ColdTierOptions {
+	co := *c
+	co.age = value
+	return &co
+}
+
+func (c *coldTierOpts) Age() time.Duration {
+	return c.age
+}
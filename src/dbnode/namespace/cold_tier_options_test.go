@@ -0,0 +1,46 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package namespace
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestColdTierOptionsEqual(t *testing.T) {
+	opts := NewColdTierOptions()
+	require.True(t, opts.Equal(NewColdTierOptions()))
+	require.False(t, opts.SetFilePathPrefix("/var/lib/m3db-cold").Equal(opts))
+	require.False(t, opts.SetAge(time.Hour).Equal(opts.SetAge(2*time.Hour)))
+}
+
+func TestColdTierOptionsEnabled(t *testing.T) {
+	opts := NewColdTierOptions()
+	require.False(t, opts.Enabled())
+	require.Equal(t, defaultColdTierAge, opts.Age())
+
+	opts = opts.SetFilePathPrefix("/var/lib/m3db-cold").SetAge(time.Hour)
+	require.True(t, opts.Enabled())
+	require.Equal(t, "/var/lib/m3db-cold", opts.FilePathPrefix())
+	require.Equal(t, time.Hour, opts.Age())
+}
@@ -25,6 +25,8 @@ import (
 	"time"
 
 	"github.com/m3db/m3/src/dbnode/retention"
+	"github.com/m3db/m3/src/metrics/aggregation"
+	"github.com/m3db/m3/src/query/models"
 	"github.com/m3db/m3/src/x/ident"
 )
 
@@ -48,15 +50,20 @@ func (m *MapConfiguration) Map() (Map, error) {
 
 // MetadataConfiguration is the configuration for a single namespace
 type MetadataConfiguration struct {
-	ID                string                  `yaml:"id" validate:"nonzero"`
-	BootstrapEnabled  *bool                   `yaml:"bootstrapEnabled"`
-	FlushEnabled      *bool                   `yaml:"flushEnabled"`
-	WritesToCommitLog *bool                   `yaml:"writesToCommitLog"`
-	CleanupEnabled    *bool                   `yaml:"cleanupEnabled"`
-	RepairEnabled     *bool                   `yaml:"repairEnabled"`
-	ColdWritesEnabled *bool                   `yaml:"coldWritesEnabled"`
-	Retention         retention.Configuration `yaml:"retention" validate:"nonzero"`
-	Index             IndexConfiguration      `yaml:"index"`
+	ID                    string                    `yaml:"id" validate:"nonzero"`
+	BootstrapEnabled      *bool                     `yaml:"bootstrapEnabled"`
+	FlushEnabled          *bool                     `yaml:"flushEnabled"`
+	WritesToCommitLog     *bool                     `yaml:"writesToCommitLog"`
+	CleanupEnabled        *bool                     `yaml:"cleanupEnabled"`
+	RepairEnabled         *bool                     `yaml:"repairEnabled"`
+	ColdWritesEnabled     *bool                     `yaml:"coldWritesEnabled"`
+	ValueSummariesEnabled *bool                     `yaml:"valueSummariesEnabled"`
+	HistogramsEnabled     *bool                     `yaml:"histogramsEnabled"`
+	Retention             retention.Configuration   `yaml:"retention" validate:"nonzero"`
+	Index                 IndexConfiguration        `yaml:"index"`
+	Aggregation           *AggregationConfiguration `yaml:"aggregation"`
+	ColdTier              *ColdTierConfiguration    `yaml:"coldTier"`
+	WriteClasses          []WriteClassConfiguration `yaml:"writeClasses"`
 }
 
 // Metadata returns a Metadata corresponding to the receiver struct
@@ -84,18 +91,103 @@ func (mc *MetadataConfiguration) Metadata() (Metadata, error) {
 	if v := mc.ColdWritesEnabled; v != nil {
 		opts = opts.SetColdWritesEnabled(*v)
 	}
+	if v := mc.ValueSummariesEnabled; v != nil {
+		opts = opts.SetValueSummariesEnabled(*v)
+	}
+	if v := mc.HistogramsEnabled; v != nil {
+		opts = opts.SetHistogramsEnabled(*v)
+	}
+	if v := mc.Aggregation; v != nil {
+		opts = opts.SetAggregationOptions(v.Options())
+	}
+	if v := mc.ColdTier; v != nil {
+		opts = opts.SetColdTierOptions(v.Options())
+	}
+	if len(mc.WriteClasses) > 0 {
+		classes := make([]WriteClass, 0, len(mc.WriteClasses))
+		for _, wc := range mc.WriteClasses {
+			class, err := wc.WriteClass()
+			if err != nil {
+				return nil, err
+			}
+			classes = append(classes, class)
+		}
+		opts = opts.SetWriteClasses(classes)
+	}
 	return NewMetadata(ident.StringID(mc.ID), opts)
 }
 
 // IndexConfiguration controls the knobs to tweak indexing configuration.
 type IndexConfiguration struct {
-	Enabled   bool          `yaml:"enabled" validate:"nonzero"`
-	BlockSize time.Duration `yaml:"blockSize" validate:"nonzero"`
+	Enabled            bool          `yaml:"enabled" validate:"nonzero"`
+	BlockSize          time.Duration `yaml:"blockSize" validate:"nonzero"`
+	TokenizedFields    []string      `yaml:"tokenizedFields"`
+	CompactionAge      time.Duration `yaml:"compactionAge"`
+	CompactedBlockSize time.Duration `yaml:"compactedBlockSize"`
 }
 
 // Options returns the IndexOptions corresponding to the receiver struct.
 func (ic *IndexConfiguration) Options() IndexOptions {
 	return NewIndexOptions().
 		SetEnabled(ic.Enabled).
-		SetBlockSize(ic.BlockSize)
+		SetBlockSize(ic.BlockSize).
+		SetTokenizedFields(ic.TokenizedFields).
+		SetCompactionAge(ic.CompactionAge).
+		SetCompactedBlockSize(ic.CompactedBlockSize)
+}
+
+// AggregationConfiguration controls the knobs to configure a namespace as an
+// aggregated namespace that rolls up the data of a source namespace.
+type AggregationConfiguration struct {
+	Resolution      time.Duration        `yaml:"resolution" validate:"nonzero"`
+	SourceNamespace string               `yaml:"sourceNamespace" validate:"nonzero"`
+	Types           aggregation.Types    `yaml:"types"`
+	IDScheme        *models.IDSchemeType `yaml:"idScheme"`
+}
+
+// Options returns the AggregationOptions corresponding to the receiver struct.
+func (ac *AggregationConfiguration) Options() AggregationOptions {
+	opts := NewAggregationOptions().
+		SetEnabled(true).
+		SetResolution(ac.Resolution).
+		SetSourceNamespace(ident.StringID(ac.SourceNamespace))
+	if len(ac.Types) > 0 {
+		opts = opts.SetAggregationTypes(ac.Types)
+	}
+	if v := ac.IDScheme; v != nil {
+		opts = opts.SetIDScheme(*v)
+	}
+	return opts
+}
+
+// ColdTierConfiguration controls the knobs to configure the cold storage tier
+// of a namespace.
+type ColdTierConfiguration struct {
+	FilePathPrefix string         `yaml:"filePathPrefix" validate:"nonzero"`
+	Age            *time.Duration `yaml:"age"`
+}
+
+// Options returns the ColdTierOptions corresponding to the receiver struct.
+func (cc *ColdTierConfiguration) Options() ColdTierOptions {
+	opts := NewColdTierOptions().SetFilePathPrefix(cc.FilePathPrefix)
+	if v := cc.Age; v != nil {
+		opts = opts.SetAge(*v)
+	}
+	return opts
+}
+
+// WriteClassConfiguration controls the knobs to configure a write class of a
+// namespace.
+type WriteClassConfiguration struct {
+	Name            string        `yaml:"name" validate:"nonzero"`
+	TagName         string        `yaml:"tagName" validate:"nonzero"`
+	TagValuePattern string        `yaml:"tagValuePattern"`
+	BufferPast      time.Duration `yaml:"bufferPast"`
+	BufferFuture    time.Duration `yaml:"bufferFuture"`
+}
+
+// WriteClass returns the WriteClass corresponding to the receiver struct.
+func (wc *WriteClassConfiguration) WriteClass() (WriteClass, error) {
+	return NewWriteClass(wc.Name, wc.TagName, wc.TagValuePattern,
+		wc.BufferPast, wc.BufferFuture)
 }
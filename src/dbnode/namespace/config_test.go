@@ -166,6 +166,10 @@ metadatas:
     index:
       enabled: true
       blockSize: 24h
+      tokenizedFields:
+        - name
+      compactionAge: 48h
+      compactedBlockSize: 240h
 `)
 
 	var conf MapConfiguration
@@ -224,6 +228,9 @@ metadatas:
 	require.Equal(t, true, opts.RepairEnabled())
 	require.Equal(t, true, opts.IndexOptions().Enabled())
 	require.Equal(t, 24*time.Hour, opts.IndexOptions().BlockSize())
+	require.Equal(t, []string{"name"}, opts.IndexOptions().TokenizedFields())
+	require.Equal(t, 48*time.Hour, opts.IndexOptions().CompactionAge())
+	require.Equal(t, 240*time.Hour, opts.IndexOptions().CompactedBlockSize())
 	testRetentionOpts = retention.NewOptions().
 		SetRetentionPeriod(960 * time.Hour).
 		SetBlockSize(12 * time.Hour).
@@ -232,3 +239,37 @@ metadatas:
 	require.True(t, testRetentionOpts.Equal(opts.RetentionOptions()))
 
 }
+
+func TestMetadataConfigWriteClasses(t *testing.T) {
+	yamlBytes := []byte(`
+id: "iot"
+retention:
+  retentionPeriod: 48h
+  blockSize: 2h
+  bufferFuture: 10m
+  bufferPast: 10m
+writeClasses:
+  - name: devices
+    tagName: source
+    tagValuePattern: "device-.*"
+    bufferPast: 90m
+    bufferFuture: 10m
+`)
+
+	var conf MetadataConfiguration
+	require.NoError(t, yaml.Unmarshal(yamlBytes, &conf))
+
+	md, err := conf.Metadata()
+	require.NoError(t, err)
+	classes := md.Options().WriteClasses()
+	require.Len(t, classes, 1)
+	require.Equal(t, "devices", classes[0].Name())
+	require.Equal(t, "source", classes[0].TagName())
+	require.Equal(t, "device-.*", classes[0].TagValuePattern())
+	require.Equal(t, 90*time.Minute, classes[0].BufferPast())
+	require.Equal(t, 10*time.Minute, classes[0].BufferFuture())
+
+	conf.WriteClasses[0].TagValuePattern = "device-("
+	_, err = conf.Metadata()
+	require.Error(t, err)
+}
@@ -22,10 +22,13 @@ package namespace
 
 import (
 	"errors"
+	"fmt"
 	"time"
 
 	nsproto "github.com/m3db/m3/src/dbnode/generated/proto/namespace"
 	"github.com/m3db/m3/src/dbnode/retention"
+	"github.com/m3db/m3/src/metrics/aggregation"
+	"github.com/m3db/m3/src/query/models"
 	"github.com/m3db/m3/src/x/ident"
 	xtime "github.com/m3db/m3/src/x/time"
 )
@@ -74,11 +77,104 @@ func ToIndexOptions(
 	}
 
 	iopts = iopts.SetEnabled(io.Enabled).
-		SetBlockSize(fromNanos(io.BlockSizeNanos))
+		SetBlockSize(fromNanos(io.BlockSizeNanos)).
+		SetTokenizedFields(io.TokenizedFields).
+		SetCompactionAge(fromNanos(io.CompactionAgeNanos)).
+		SetCompactedBlockSize(fromNanos(io.CompactedBlockSizeNanos))
 
 	return iopts, nil
 }
 
+// ToAggregationOptions converts nsproto.AggregationOptions to AggregationOptions
+func ToAggregationOptions(
+	ao *nsproto.AggregationOptions,
+) (AggregationOptions, error) {
+	aopts := NewAggregationOptions().SetEnabled(false)
+	if ao == nil {
+		return aopts, nil
+	}
+
+	aggTypes := make(aggregation.Types, 0, len(ao.AggregationTypes))
+	for _, str := range ao.AggregationTypes {
+		aggType, err := aggregation.ParseType(str)
+		if err != nil {
+			return nil, err
+		}
+		aggTypes = append(aggTypes, aggType)
+	}
+	if len(aggTypes) == 0 {
+		aggTypes = defaultAggregationTypes
+	}
+
+	aopts = aopts.SetEnabled(ao.Enabled).
+		SetResolution(fromNanos(ao.ResolutionNanos)).
+		SetAggregationTypes(aggTypes)
+	if ao.SourceNamespace != "" {
+		aopts = aopts.SetSourceNamespace(ident.StringID(ao.SourceNamespace))
+	}
+	if ao.IdScheme != "" {
+		idScheme, err := toIDScheme(ao.IdScheme)
+		if err != nil {
+			return nil, err
+		}
+		aopts = aopts.SetIDScheme(idScheme)
+	}
+
+	return aopts, nil
+}
+
+func toIDScheme(str string) (models.IDSchemeType, error) {
+	for _, idScheme := range []models.IDSchemeType{
+		models.TypeLegacy,
+		models.TypeQuoted,
+		models.TypePrependMeta,
+	} {
+		if str == idScheme.String() {
+			return idScheme, nil
+		}
+	}
+	return models.TypeDefault, fmt.Errorf("invalid aggregation id scheme: %s", str)
+}
+
+// ToColdTierOptions converts nsproto.ColdTierOptions to ColdTierOptions
+func ToColdTierOptions(
+	co *nsproto.ColdTierOptions,
+) ColdTierOptions {
+	copts := NewColdTierOptions()
+	if co == nil {
+		return copts
+	}
+
+	copts = copts.SetFilePathPrefix(co.FilePathPrefix)
+	if co.AgeNanos > 0 {
+		copts = copts.SetAge(fromNanos(co.AgeNanos))
+	}
+	return copts
+}
+
+// ToWriteClasses converts nsproto.WriteClass values to WriteClass values
+func ToWriteClasses(
+	wcs []*nsproto.WriteClass,
+) ([]WriteClass, error) {
+	if len(wcs) == 0 {
+		return nil, nil
+	}
+
+	classes := make([]WriteClass, 0, len(wcs))
+	for _, wc := range wcs {
+		if wc == nil {
+			return nil, errWriteClassNil
+		}
+		class, err := NewWriteClass(wc.Name, wc.TagName, wc.TagValuePattern,
+			fromNanos(wc.BufferPastNanos), fromNanos(wc.BufferFutureNanos))
+		if err != nil {
+			return nil, err
+		}
+		classes = append(classes, class)
+	}
+	return classes, nil
+}
+
 // ToMetadata converts nsproto.Options to Metadata
 func ToMetadata(
 	id string,
@@ -103,6 +199,16 @@ func ToMetadata(
 		return nil, err
 	}
 
+	aopts, err := ToAggregationOptions(opts.AggregationOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	classes, err := ToWriteClasses(opts.WriteClasses)
+	if err != nil {
+		return nil, err
+	}
+
 	mopts := NewOptions().
 		SetBootstrapEnabled(opts.BootstrapEnabled).
 		SetFlushEnabled(opts.FlushEnabled).
@@ -113,7 +219,12 @@ func ToMetadata(
 		SetSchemaHistory(sr).
 		SetRetentionOptions(ropts).
 		SetIndexOptions(iopts).
-		SetColdWritesEnabled(opts.ColdWritesEnabled)
+		SetColdWritesEnabled(opts.ColdWritesEnabled).
+		SetValueSummariesEnabled(opts.ValueSummariesEnabled).
+		SetHistogramsEnabled(opts.HistogramsEnabled).
+		SetAggregationOptions(aopts).
+		SetColdTierOptions(ToColdTierOptions(opts.ColdTierOptions)).
+		SetWriteClasses(classes)
 
 	return NewMetadata(ident.StringID(id), mopts)
 }
@@ -167,9 +278,73 @@ func OptionsToProto(opts Options) *nsproto.NamespaceOptions {
 			BlockDataExpiryAfterNotAccessPeriodNanos: ropts.BlockDataExpiryAfterNotAccessedPeriod().Nanoseconds(),
 		},
 		IndexOptions: &nsproto.IndexOptions{
-			Enabled:        iopts.Enabled(),
-			BlockSizeNanos: iopts.BlockSize().Nanoseconds(),
+			Enabled:                 iopts.Enabled(),
+			BlockSizeNanos:          iopts.BlockSize().Nanoseconds(),
+			TokenizedFields:         iopts.TokenizedFields(),
+			CompactionAgeNanos:      iopts.CompactionAge().Nanoseconds(),
+			CompactedBlockSizeNanos: iopts.CompactedBlockSize().Nanoseconds(),
 		},
-		ColdWritesEnabled: opts.ColdWritesEnabled(),
+		ColdWritesEnabled:     opts.ColdWritesEnabled(),
+		AggregationOptions:    aggregationOptionsToProto(opts.AggregationOptions()),
+		ColdTierOptions:       coldTierOptionsToProto(opts.ColdTierOptions()),
+		WriteClasses:          writeClassesToProto(opts.WriteClasses()),
+		ValueSummariesEnabled: opts.ValueSummariesEnabled(),
+		HistogramsEnabled:     opts.HistogramsEnabled(),
+	}
+}
+
+func aggregationOptionsToProto(aopts AggregationOptions) *nsproto.AggregationOptions {
+	// Only aggregated namespaces carry aggregation options so that the
+	// serialized options of other namespaces are unchanged.
+	if !aopts.Enabled() {
+		return nil
+	}
+
+	var sourceNamespace string
+	if source := aopts.SourceNamespace(); source != nil {
+		sourceNamespace = source.String()
+	}
+	aggTypes := make([]string, 0, len(aopts.AggregationTypes()))
+	for _, aggType := range aopts.AggregationTypes() {
+		aggTypes = append(aggTypes, aggType.String())
+	}
+
+	return &nsproto.AggregationOptions{
+		Enabled:          aopts.Enabled(),
+		ResolutionNanos:  aopts.Resolution().Nanoseconds(),
+		SourceNamespace:  sourceNamespace,
+		AggregationTypes: aggTypes,
+		IdScheme:         aopts.IDScheme().String(),
+	}
+}
+
+func coldTierOptionsToProto(copts ColdTierOptions) *nsproto.ColdTierOptions {
+	// Only namespaces with a cold tier carry cold tier options so that the
+	// serialized options of other namespaces are unchanged.
+	if !copts.Enabled() {
+		return nil
+	}
+
+	return &nsproto.ColdTierOptions{
+		FilePathPrefix: copts.FilePathPrefix(),
+		AgeNanos:       copts.Age().Nanoseconds(),
+	}
+}
+
+func writeClassesToProto(classes []WriteClass) []*nsproto.WriteClass {
+	if len(classes) == 0 {
+		return nil
+	}
+
+	wcs := make([]*nsproto.WriteClass, 0, len(classes))
+	for _, class := range classes {
+		wcs = append(wcs, &nsproto.WriteClass{
+			Name:              class.Name(),
+			TagName:           class.TagName(),
+			TagValuePattern:   class.TagValuePattern(),
+			BufferPastNanos:   class.BufferPast().Nanoseconds(),
+			BufferFutureNanos: class.BufferFuture().Nanoseconds(),
+		})
 	}
+	return wcs
 }
@@ -27,6 +27,8 @@ import (
 	nsproto "github.com/m3db/m3/src/dbnode/generated/proto/namespace"
 	"github.com/m3db/m3/src/dbnode/retention"
 	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/metrics/aggregation"
+	"github.com/m3db/m3/src/query/models"
 	"github.com/m3db/m3/src/x/ident"
 
 	"github.com/stretchr/testify/require"
@@ -40,8 +42,11 @@ var (
 	}
 
 	validIndexOpts = nsproto.IndexOptions{
-		Enabled:        true,
-		BlockSizeNanos: toNanos(600), // 10h
+		Enabled:                 true,
+		BlockSizeNanos:          toNanos(600), // 10h
+		TokenizedFields:         []string{"name"},
+		CompactionAgeNanos:      toNanos(240),  // 4h
+		CompactedBlockSizeNanos: toNanos(1200), // 20h
 	}
 
 	validRetentionOpts = nsproto.RetentionOptions{
@@ -271,6 +276,112 @@ func TestFromProtoSnapshotEnabled(t *testing.T) {
 	require.Equal(t, !namespace.NewOptions().SnapshotEnabled(), md.Options().SnapshotEnabled())
 }
 
+func TestValueSummariesEnabledRoundTrip(t *testing.T) {
+	opts := namespace.NewOptions().SetValueSummariesEnabled(true)
+	protoOpts := namespace.OptionsToProto(opts)
+	require.True(t, protoOpts.ValueSummariesEnabled)
+
+	md, err := namespace.ToMetadata("ns1", protoOpts)
+	require.NoError(t, err)
+	require.True(t, md.Options().ValueSummariesEnabled())
+}
+
+func TestHistogramsEnabledRoundTrip(t *testing.T) {
+	opts := namespace.NewOptions().SetHistogramsEnabled(true)
+	protoOpts := namespace.OptionsToProto(opts)
+	require.True(t, protoOpts.HistogramsEnabled)
+
+	md, err := namespace.ToMetadata("ns1", protoOpts)
+	require.NoError(t, err)
+	require.True(t, md.Options().HistogramsEnabled())
+}
+
+func TestAggregationOptionsRoundTrip(t *testing.T) {
+	aggOpts := namespace.NewAggregationOptions().
+		SetEnabled(true).
+		SetResolution(time.Minute).
+		SetSourceNamespace(ident.StringID("raw")).
+		SetAggregationTypes(aggregation.Types{aggregation.Max, aggregation.Sum}).
+		SetIDScheme(models.TypePrependMeta)
+	raw, err := namespace.NewMetadata(ident.StringID("raw"), namespace.NewOptions())
+	require.NoError(t, err)
+	agg, err := namespace.NewMetadata(ident.StringID("agg"),
+		namespace.NewOptions().SetAggregationOptions(aggOpts))
+	require.NoError(t, err)
+	nsMap, err := namespace.NewMap([]namespace.Metadata{raw, agg})
+	require.NoError(t, err)
+
+	reg := namespace.ToProto(nsMap)
+	require.Nil(t, reg.Namespaces["raw"].AggregationOptions)
+	require.Equal(t, &nsproto.AggregationOptions{
+		Enabled:          true,
+		ResolutionNanos:  int64(time.Minute),
+		SourceNamespace:  "raw",
+		AggregationTypes: []string{"Max", "Sum"},
+		IdScheme:         "prepend_meta",
+	}, reg.Namespaces["agg"].AggregationOptions)
+
+	nsMap, err = namespace.FromProto(*reg)
+	require.NoError(t, err)
+	md, err := nsMap.Get(ident.StringID("agg"))
+	require.NoError(t, err)
+	require.True(t, aggOpts.Equal(md.Options().AggregationOptions()))
+}
+
+func TestColdTierOptionsRoundTrip(t *testing.T) {
+	coldTierOpts := namespace.NewColdTierOptions().
+		SetFilePathPrefix("/var/lib/m3db-cold").
+		SetAge(24 * time.Hour)
+	hot, err := namespace.NewMetadata(ident.StringID("hot"), namespace.NewOptions())
+	require.NoError(t, err)
+	tiered, err := namespace.NewMetadata(ident.StringID("tiered"),
+		namespace.NewOptions().SetColdTierOptions(coldTierOpts))
+	require.NoError(t, err)
+	nsMap, err := namespace.NewMap([]namespace.Metadata{hot, tiered})
+	require.NoError(t, err)
+
+	reg := namespace.ToProto(nsMap)
+	require.Nil(t, reg.Namespaces["hot"].ColdTierOptions)
+	require.Equal(t, &nsproto.ColdTierOptions{
+		FilePathPrefix: "/var/lib/m3db-cold",
+		AgeNanos:       int64(24 * time.Hour),
+	}, reg.Namespaces["tiered"].ColdTierOptions)
+
+	nsMap, err = namespace.FromProto(*reg)
+	require.NoError(t, err)
+	md, err := nsMap.Get(ident.StringID("tiered"))
+	require.NoError(t, err)
+	require.True(t, coldTierOpts.Equal(md.Options().ColdTierOptions()))
+}
+
+func TestToAggregationOptionsInvalidType(t *testing.T) {
+	_, err := namespace.ToAggregationOptions(&nsproto.AggregationOptions{
+		Enabled:          true,
+		ResolutionNanos:  int64(time.Minute),
+		SourceNamespace:  "raw",
+		AggregationTypes: []string{"NotAType"},
+	})
+	require.Error(t, err)
+}
+
+func TestToAggregationOptionsIDScheme(t *testing.T) {
+	aggOpts, err := namespace.ToAggregationOptions(&nsproto.AggregationOptions{
+		Enabled:         true,
+		ResolutionNanos: int64(time.Minute),
+		SourceNamespace: "raw",
+	})
+	require.NoError(t, err)
+	require.Equal(t, models.TypeQuoted, aggOpts.IDScheme())
+
+	_, err = namespace.ToAggregationOptions(&nsproto.AggregationOptions{
+		Enabled:         true,
+		ResolutionNanos: int64(time.Minute),
+		SourceNamespace: "raw",
+		IdScheme:        "graphite",
+	})
+	require.Error(t, err)
+}
+
 func assertEqualMetadata(t *testing.T, name string, expected nsproto.NamespaceOptions, observed namespace.Metadata) {
 	require.Equal(t, name, observed.ID().String())
 	opts := observed.Options()
@@ -286,6 +397,14 @@ func assertEqualMetadata(t *testing.T, name string, expected nsproto.NamespaceOp
 	require.True(t, expectedSchemaReg.Equal(observed.Options().SchemaHistory()))
 
 	assertEqualRetentions(t, *expected.RetentionOptions, opts.RetentionOptions())
+	if expected.IndexOptions != nil {
+		require.Equal(t, expected.IndexOptions.TokenizedFields,
+			opts.IndexOptions().TokenizedFields())
+		require.Equal(t, expected.IndexOptions.CompactionAgeNanos,
+			opts.IndexOptions().CompactionAge().Nanoseconds())
+		require.Equal(t, expected.IndexOptions.CompactedBlockSizeNanos,
+			opts.IndexOptions().CompactedBlockSize().Nanoseconds())
+	}
 }
 
 func assertEqualRetentions(t *testing.T, expected nsproto.RetentionOptions, observed retention.Options) {
@@ -297,3 +416,35 @@ func assertEqualRetentions(t *testing.T, expected nsproto.RetentionOptions, obse
 	require.Equal(t, expected.BlockDataExpiryAfterNotAccessPeriodNanos,
 		observed.BlockDataExpiryAfterNotAccessedPeriod().Nanoseconds())
 }
+
+func TestWriteClassesRoundTrip(t *testing.T) {
+	iot, err := namespace.NewWriteClass("iot", "source", "device-.*",
+		6*time.Hour, time.Minute)
+	require.NoError(t, err)
+	plain, err := namespace.NewMetadata(ident.StringID("plain"), namespace.NewOptions())
+	require.NoError(t, err)
+	classed, err := namespace.NewMetadata(ident.StringID("classed"),
+		namespace.NewOptions().SetWriteClasses([]namespace.WriteClass{iot}))
+	require.NoError(t, err)
+	nsMap, err := namespace.NewMap([]namespace.Metadata{plain, classed})
+	require.NoError(t, err)
+
+	reg := namespace.ToProto(nsMap)
+	require.Nil(t, reg.Namespaces["plain"].WriteClasses)
+	require.Equal(t, []*nsproto.WriteClass{
+		{
+			Name:              "iot",
+			TagName:           "source",
+			TagValuePattern:   "device-.*",
+			BufferPastNanos:   int64(6 * time.Hour),
+			BufferFutureNanos: int64(time.Minute),
+		},
+	}, reg.Namespaces["classed"].WriteClasses)
+
+	nsMap, err = namespace.FromProto(*reg)
+	require.NoError(t, err)
+	md, err := nsMap.Get(ident.StringID("classed"))
+	require.NoError(t, err)
+	require.Len(t, md.Options().WriteClasses(), 1)
+	require.True(t, iot.Equal(md.Options().WriteClasses()[0]))
+}
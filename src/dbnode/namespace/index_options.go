@@ -33,8 +33,11 @@ var (
 )
 
 type indexOpts struct {
-	enabled   bool
-	blockSize time.Duration
+	enabled            bool
+	blockSize          time.Duration
+	tokenizedFields    []string
+	compactionAge      time.Duration
+	compactedBlockSize time.Duration
 }
 
 // NewIndexOptions returns a new IndexOptions.
@@ -47,7 +50,10 @@ func NewIndexOptions() IndexOptions {
 
 func (i *indexOpts) Equal(value IndexOptions) bool {
 	return i.Enabled() == value.Enabled() &&
-		i.BlockSize() == value.BlockSize()
+		i.BlockSize() == value.BlockSize() &&
+		stringsEqual(i.TokenizedFields(), value.TokenizedFields()) &&
+		i.CompactionAge() == value.CompactionAge() &&
+		i.CompactedBlockSize() == value.CompactedBlockSize()
 }
 
 func (i *indexOpts) SetEnabled(value bool) IndexOptions {
@@ -69,3 +75,45 @@ func (i *indexOpts) SetBlockSize(value time.Duration) IndexOptions {
 func (i *indexOpts) BlockSize() time.Duration {
 	return i.blockSize
 }
+
+func (i *indexOpts) SetTokenizedFields(value []string) IndexOptions {
+	io := *i
+	io.tokenizedFields = value
+	return &io
+}
+
+func (i *indexOpts) TokenizedFields() []string {
+	return i.tokenizedFields
+}
+
+func (i *indexOpts) SetCompactionAge(value time.Duration) IndexOptions {
+	io := *i
+	io.compactionAge = value
+	return &io
+}
+
+func (i *indexOpts) CompactionAge() time.Duration {
+	return i.compactionAge
+}
+
+func (i *indexOpts) SetCompactedBlockSize(value time.Duration) IndexOptions {
+	io := *i
+	io.compactedBlockSize = value
+	return &io
+}
+
+func (i *indexOpts) CompactedBlockSize() time.Duration {
+	return i.compactedBlockSize
+}
+
+func stringsEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
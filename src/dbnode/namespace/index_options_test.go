@@ -33,6 +33,11 @@ func TestIndexOptionsEqual(t *testing.T) {
 	require.False(t, opts.SetEnabled(true).Equal(opts.SetEnabled(false)))
 	require.False(t, opts.SetBlockSize(time.Hour).Equal(
 		opts.SetBlockSize(time.Hour*2)))
+	require.True(t, opts.SetTokenizedFields([]string{"name"}).Equal(
+		opts.SetTokenizedFields([]string{"name"})))
+	require.False(t, opts.SetTokenizedFields([]string{"name"}).Equal(
+		opts.SetTokenizedFields([]string{"host"})))
+	require.False(t, opts.SetTokenizedFields([]string{"name"}).Equal(opts))
 }
 
 func TestIndexOptionsEnabled(t *testing.T) {
@@ -45,3 +50,20 @@ func TestIndexOptionsBlockSize(t *testing.T) {
 	opts := NewIndexOptions()
 	require.Equal(t, time.Hour, opts.SetBlockSize(time.Hour).BlockSize())
 }
+
+func TestIndexOptionsCompaction(t *testing.T) {
+	opts := NewIndexOptions().
+		SetCompactionAge(time.Hour).
+		SetCompactedBlockSize(24 * time.Hour)
+	require.Equal(t, time.Hour, opts.CompactionAge())
+	require.Equal(t, 24*time.Hour, opts.CompactedBlockSize())
+	require.False(t, opts.Equal(NewIndexOptions()))
+	require.False(t, opts.Equal(opts.SetCompactionAge(2*time.Hour)))
+}
+
+func TestIndexOptionsTokenizedFields(t *testing.T) {
+	opts := NewIndexOptions()
+	require.Empty(t, opts.TokenizedFields())
+	require.Equal(t, []string{"name"},
+		opts.SetTokenizedFields([]string{"name"}).TokenizedFields())
+}
@@ -67,6 +67,16 @@ func NewMap(metadatas []Metadata) (Map, error) {
 		return nil, err
 	}
 
+	for _, m := range nsMetadatas {
+		if err := validateAggregatedNamespace(m, ns); err != nil {
+			multiErr = multiErr.Add(err)
+		}
+	}
+
+	if err := multiErr.FinalError(); err != nil {
+		return nil, err
+	}
+
 	return &nsMap{
 		namespaces: ns,
 		ids:        ids,
@@ -74,6 +84,41 @@ func NewMap(metadatas []Metadata) (Map, error) {
 	}, nil
 }
 
+// validateAggregatedNamespace ensures an aggregated namespace rolls up data
+// from a namespace that exists and whose blocks fit evenly in its own blocks.
+func validateAggregatedNamespace(m Metadata, ns *metadataMap) error {
+	aggOpts := m.Options().AggregationOptions()
+	if !aggOpts.Enabled() {
+		return nil
+	}
+
+	source, ok := ns.Get(aggOpts.SourceNamespace())
+	if !ok {
+		return fmt.Errorf("aggregated namespace %s source namespace %s not found",
+			m.ID().String(), aggOpts.SourceNamespace().String())
+	}
+	if source.Options().AggregationOptions().Enabled() {
+		return fmt.Errorf("aggregated namespace %s source namespace %s must not be aggregated",
+			m.ID().String(), source.ID().String())
+	}
+	// Blocks are rolled up once all of their source blocks are warm flushed,
+	// the volumes of later cold writes to the source would never be rolled up.
+	if source.Options().ColdWritesEnabled() {
+		return fmt.Errorf("aggregated namespace %s source namespace %s must not enable cold writes",
+			m.ID().String(), source.ID().String())
+	}
+
+	var (
+		blockSize       = m.Options().RetentionOptions().BlockSize()
+		sourceBlockSize = source.Options().RetentionOptions().BlockSize()
+	)
+	if blockSize%sourceBlockSize != 0 {
+		return fmt.Errorf("aggregated namespace %s block size %s must be a multiple of source namespace block size %s",
+			m.ID().String(), blockSize.String(), sourceBlockSize.String())
+	}
+	return nil
+}
+
 func (r *nsMap) Get(namespace ident.ID) (Metadata, error) {
 	metadata, ok := r.namespaces.Get(namespace)
 	if !ok {
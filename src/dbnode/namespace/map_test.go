@@ -22,6 +22,7 @@ package namespace
 
 import (
 	"testing"
+	"time"
 
 	"github.com/m3db/m3/src/x/ident"
 
@@ -124,3 +125,51 @@ func TestMapValidateDuplicateID(t *testing.T) {
 	_, err = NewMap(metadatas)
 	require.Error(t, err)
 }
+
+func TestMapValidateAggregatedNamespace(t *testing.T) {
+	var (
+		rawID   = ident.StringID("raw")
+		aggID   = ident.StringID("agg")
+		aggOpts = NewAggregationOptions().
+			SetEnabled(true).
+			SetResolution(time.Minute).
+			SetSourceNamespace(rawID)
+		rawOpts = NewOptions()
+	)
+	raw, err := NewMetadata(rawID, rawOpts)
+	require.NoError(t, err)
+	agg, err := NewMetadata(aggID, NewOptions().
+		SetRetentionOptions(rawOpts.RetentionOptions().SetBlockSize(4*time.Hour)).
+		SetAggregationOptions(aggOpts))
+	require.NoError(t, err)
+
+	_, err = NewMap([]Metadata{raw, agg})
+	require.NoError(t, err)
+
+	// Source namespace must exist.
+	_, err = NewMap([]Metadata{agg})
+	require.Error(t, err)
+
+	// Block size must be a multiple of the source block size.
+	agg, err = NewMetadata(aggID, NewOptions().
+		SetRetentionOptions(rawOpts.RetentionOptions().SetBlockSize(3*time.Hour)).
+		SetAggregationOptions(aggOpts))
+	require.NoError(t, err)
+	_, err = NewMap([]Metadata{raw, agg})
+	require.Error(t, err)
+
+	// Source namespace must not itself be aggregated.
+	agg, err = NewMetadata(aggID, NewOptions().SetAggregationOptions(aggOpts))
+	require.NoError(t, err)
+	chained, err := NewMetadata(ident.StringID("chained"), NewOptions().
+		SetAggregationOptions(aggOpts.SetSourceNamespace(aggID)))
+	require.NoError(t, err)
+	_, err = NewMap([]Metadata{raw, agg, chained})
+	require.Error(t, err)
+
+	// Source namespace must not accept cold writes.
+	coldRaw, err := NewMetadata(rawID, rawOpts.SetColdWritesEnabled(true))
+	require.NoError(t, err)
+	_, err = NewMap([]Metadata{coldRaw, agg})
+	require.Error(t, err)
+}
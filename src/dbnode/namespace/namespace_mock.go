@@ -30,6 +30,8 @@ import (
 
 	"github.com/m3db/m3/src/cluster/client"
 	"github.com/m3db/m3/src/dbnode/retention"
+	"github.com/m3db/m3/src/metrics/aggregation"
+	"github.com/m3db/m3/src/query/models"
 	"github.com/m3db/m3/src/x/close"
 	"github.com/m3db/m3/src/x/ident"
 	"github.com/m3db/m3/src/x/instrument"
@@ -284,6 +286,62 @@ func (mr *MockOptionsMockRecorder) ColdWritesEnabled() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ColdWritesEnabled", reflect.TypeOf((*MockOptions)(nil).ColdWritesEnabled))
 }
 
+// SetValueSummariesEnabled mocks base method
+func (m *MockOptions) SetValueSummariesEnabled(value bool) Options {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetValueSummariesEnabled", value)
+	ret0, _ := ret[0].(Options)
+	return ret0
+}
+
+// SetValueSummariesEnabled indicates an expected call of SetValueSummariesEnabled
+func (mr *MockOptionsMockRecorder) SetValueSummariesEnabled(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetValueSummariesEnabled", reflect.TypeOf((*MockOptions)(nil).SetValueSummariesEnabled), value)
+}
+
+// ValueSummariesEnabled mocks base method
+func (m *MockOptions) ValueSummariesEnabled() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValueSummariesEnabled")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// ValueSummariesEnabled indicates an expected call of ValueSummariesEnabled
+func (mr *MockOptionsMockRecorder) ValueSummariesEnabled() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValueSummariesEnabled", reflect.TypeOf((*MockOptions)(nil).ValueSummariesEnabled))
+}
+
+// SetHistogramsEnabled mocks base method
+func (m *MockOptions) SetHistogramsEnabled(value bool) Options {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetHistogramsEnabled", value)
+	ret0, _ := ret[0].(Options)
+	return ret0
+}
+
+// SetHistogramsEnabled indicates an expected call of SetHistogramsEnabled
+func (mr *MockOptionsMockRecorder) SetHistogramsEnabled(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetHistogramsEnabled", reflect.TypeOf((*MockOptions)(nil).SetHistogramsEnabled), value)
+}
+
+// HistogramsEnabled mocks base method
+func (m *MockOptions) HistogramsEnabled() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HistogramsEnabled")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// HistogramsEnabled indicates an expected call of HistogramsEnabled
+func (mr *MockOptionsMockRecorder) HistogramsEnabled() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HistogramsEnabled", reflect.TypeOf((*MockOptions)(nil).HistogramsEnabled))
+}
+
 // SetRetentionOptions mocks base method
 func (m *MockOptions) SetRetentionOptions(value retention.Options) Options {
 	m.ctrl.T.Helper()
@@ -368,6 +426,90 @@ func (mr *MockOptionsMockRecorder) SchemaHistory() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SchemaHistory", reflect.TypeOf((*MockOptions)(nil).SchemaHistory))
 }
 
+// SetAggregationOptions mocks base method
+func (m *MockOptions) SetAggregationOptions(value AggregationOptions) Options {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAggregationOptions", value)
+	ret0, _ := ret[0].(Options)
+	return ret0
+}
+
+// SetAggregationOptions indicates an expected call of SetAggregationOptions
+func (mr *MockOptionsMockRecorder) SetAggregationOptions(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAggregationOptions", reflect.TypeOf((*MockOptions)(nil).SetAggregationOptions), value)
+}
+
+// AggregationOptions mocks base method
+func (m *MockOptions) AggregationOptions() AggregationOptions {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AggregationOptions")
+	ret0, _ := ret[0].(AggregationOptions)
+	return ret0
+}
+
+// AggregationOptions indicates an expected call of AggregationOptions
+func (mr *MockOptionsMockRecorder) AggregationOptions() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AggregationOptions", reflect.TypeOf((*MockOptions)(nil).AggregationOptions))
+}
+
+// SetColdTierOptions mocks base method
+func (m *MockOptions) SetColdTierOptions(value ColdTierOptions) Options {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetColdTierOptions", value)
+	ret0, _ := ret[0].(Options)
+	return ret0
+}
+
+// SetColdTierOptions indicates an expected call of SetColdTierOptions
+func (mr *MockOptionsMockRecorder) SetColdTierOptions(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetColdTierOptions", reflect.TypeOf((*MockOptions)(nil).SetColdTierOptions), value)
+}
+
+// ColdTierOptions mocks base method
+func (m *MockOptions) ColdTierOptions() ColdTierOptions {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ColdTierOptions")
+	ret0, _ := ret[0].(ColdTierOptions)
+	return ret0
+}
+
+// ColdTierOptions indicates an expected call of ColdTierOptions
+func (mr *MockOptionsMockRecorder) ColdTierOptions() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ColdTierOptions", reflect.TypeOf((*MockOptions)(nil).ColdTierOptions))
+}
+
+// SetWriteClasses mocks base method
+func (m *MockOptions) SetWriteClasses(value []WriteClass) Options {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetWriteClasses", value)
+	ret0, _ := ret[0].(Options)
+	return ret0
+}
+
+// SetWriteClasses indicates an expected call of SetWriteClasses
+func (mr *MockOptionsMockRecorder) SetWriteClasses(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetWriteClasses", reflect.TypeOf((*MockOptions)(nil).SetWriteClasses), value)
+}
+
+// WriteClasses mocks base method
+func (m *MockOptions) WriteClasses() []WriteClass {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WriteClasses")
+	ret0, _ := ret[0].([]WriteClass)
+	return ret0
+}
+
+// WriteClasses indicates an expected call of WriteClasses
+func (mr *MockOptionsMockRecorder) WriteClasses() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteClasses", reflect.TypeOf((*MockOptions)(nil).WriteClasses))
+}
+
 // MockIndexOptions is a mock of IndexOptions interface
 type MockIndexOptions struct {
 	ctrl     *gomock.Controller
@@ -461,6 +603,495 @@ func (mr *MockIndexOptionsMockRecorder) BlockSize() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockSize", reflect.TypeOf((*MockIndexOptions)(nil).BlockSize))
 }
 
+// SetTokenizedFields mocks base method
+func (m *MockIndexOptions) SetTokenizedFields(value []string) IndexOptions {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetTokenizedFields", value)
+	ret0, _ := ret[0].(IndexOptions)
+	return ret0
+}
+
+// SetTokenizedFields indicates an expected call of SetTokenizedFields
+func (mr *MockIndexOptionsMockRecorder) SetTokenizedFields(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTokenizedFields", reflect.TypeOf((*MockIndexOptions)(nil).SetTokenizedFields), value)
+}
+
+// TokenizedFields mocks base method
+func (m *MockIndexOptions) TokenizedFields() []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TokenizedFields")
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// TokenizedFields indicates an expected call of TokenizedFields
+func (mr *MockIndexOptionsMockRecorder) TokenizedFields() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TokenizedFields", reflect.TypeOf((*MockIndexOptions)(nil).TokenizedFields))
+}
+
+// SetCompactionAge mocks base method
+func (m *MockIndexOptions) SetCompactionAge(value time.Duration) IndexOptions {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetCompactionAge", value)
+	ret0, _ := ret[0].(IndexOptions)
+	return ret0
+}
+
+// SetCompactionAge indicates an expected call of SetCompactionAge
+func (mr *MockIndexOptionsMockRecorder) SetCompactionAge(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCompactionAge", reflect.TypeOf((*MockIndexOptions)(nil).SetCompactionAge), value)
+}
+
+// CompactionAge mocks base method
+func (m *MockIndexOptions) CompactionAge() time.Duration {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompactionAge")
+	ret0, _ := ret[0].(time.Duration)
+	return ret0
+}
+
+// CompactionAge indicates an expected call of CompactionAge
+func (mr *MockIndexOptionsMockRecorder) CompactionAge() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompactionAge", reflect.TypeOf((*MockIndexOptions)(nil).CompactionAge))
+}
+
+// SetCompactedBlockSize mocks base method
+func (m *MockIndexOptions) SetCompactedBlockSize(value time.Duration) IndexOptions {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetCompactedBlockSize", value)
+	ret0, _ := ret[0].(IndexOptions)
+	return ret0
+}
+
+// SetCompactedBlockSize indicates an expected call of SetCompactedBlockSize
+func (mr *MockIndexOptionsMockRecorder) SetCompactedBlockSize(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCompactedBlockSize", reflect.TypeOf((*MockIndexOptions)(nil).SetCompactedBlockSize), value)
+}
+
+// CompactedBlockSize mocks base method
+func (m *MockIndexOptions) CompactedBlockSize() time.Duration {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompactedBlockSize")
+	ret0, _ := ret[0].(time.Duration)
+	return ret0
+}
+
+// CompactedBlockSize indicates an expected call of CompactedBlockSize
+func (mr *MockIndexOptionsMockRecorder) CompactedBlockSize() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompactedBlockSize", reflect.TypeOf((*MockIndexOptions)(nil).CompactedBlockSize))
+}
+
+// MockAggregationOptions is a mock of AggregationOptions interface
+type MockAggregationOptions struct {
+	ctrl     *gomock.Controller
+	recorder *MockAggregationOptionsMockRecorder
+}
+
+// MockAggregationOptionsMockRecorder is the mock recorder for MockAggregationOptions
+type MockAggregationOptionsMockRecorder struct {
+	mock *MockAggregationOptions
+}
+
+// NewMockAggregationOptions creates a new mock instance
+func NewMockAggregationOptions(ctrl *gomock.Controller) *MockAggregationOptions {
+	mock := &MockAggregationOptions{ctrl: ctrl}
+	mock.recorder = &MockAggregationOptionsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockAggregationOptions) EXPECT() *MockAggregationOptionsMockRecorder {
+	return m.recorder
+}
+
+// Equal mocks base method
+func (m *MockAggregationOptions) Equal(value AggregationOptions) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Equal", value)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Equal indicates an expected call of Equal
+func (mr *MockAggregationOptionsMockRecorder) Equal(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Equal", reflect.TypeOf((*MockAggregationOptions)(nil).Equal), value)
+}
+
+// SetEnabled mocks base method
+func (m *MockAggregationOptions) SetEnabled(value bool) AggregationOptions {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetEnabled", value)
+	ret0, _ := ret[0].(AggregationOptions)
+	return ret0
+}
+
+// SetEnabled indicates an expected call of SetEnabled
+func (mr *MockAggregationOptionsMockRecorder) SetEnabled(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetEnabled", reflect.TypeOf((*MockAggregationOptions)(nil).SetEnabled), value)
+}
+
+// Enabled mocks base method
+func (m *MockAggregationOptions) Enabled() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Enabled")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Enabled indicates an expected call of Enabled
+func (mr *MockAggregationOptionsMockRecorder) Enabled() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Enabled", reflect.TypeOf((*MockAggregationOptions)(nil).Enabled))
+}
+
+// SetResolution mocks base method
+func (m *MockAggregationOptions) SetResolution(value time.Duration) AggregationOptions {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetResolution", value)
+	ret0, _ := ret[0].(AggregationOptions)
+	return ret0
+}
+
+// SetResolution indicates an expected call of SetResolution
+func (mr *MockAggregationOptionsMockRecorder) SetResolution(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetResolution", reflect.TypeOf((*MockAggregationOptions)(nil).SetResolution), value)
+}
+
+// Resolution mocks base method
+func (m *MockAggregationOptions) Resolution() time.Duration {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Resolution")
+	ret0, _ := ret[0].(time.Duration)
+	return ret0
+}
+
+// Resolution indicates an expected call of Resolution
+func (mr *MockAggregationOptionsMockRecorder) Resolution() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resolution", reflect.TypeOf((*MockAggregationOptions)(nil).Resolution))
+}
+
+// SetSourceNamespace mocks base method
+func (m *MockAggregationOptions) SetSourceNamespace(value ident.ID) AggregationOptions {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetSourceNamespace", value)
+	ret0, _ := ret[0].(AggregationOptions)
+	return ret0
+}
+
+// SetSourceNamespace indicates an expected call of SetSourceNamespace
+func (mr *MockAggregationOptionsMockRecorder) SetSourceNamespace(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSourceNamespace", reflect.TypeOf((*MockAggregationOptions)(nil).SetSourceNamespace), value)
+}
+
+// SourceNamespace mocks base method
+func (m *MockAggregationOptions) SourceNamespace() ident.ID {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SourceNamespace")
+	ret0, _ := ret[0].(ident.ID)
+	return ret0
+}
+
+// SourceNamespace indicates an expected call of SourceNamespace
+func (mr *MockAggregationOptionsMockRecorder) SourceNamespace() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SourceNamespace", reflect.TypeOf((*MockAggregationOptions)(nil).SourceNamespace))
+}
+
+// SetAggregationTypes mocks base method
+func (m *MockAggregationOptions) SetAggregationTypes(value aggregation.Types) AggregationOptions {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAggregationTypes", value)
+	ret0, _ := ret[0].(AggregationOptions)
+	return ret0
+}
+
+// SetAggregationTypes indicates an expected call of SetAggregationTypes
+func (mr *MockAggregationOptionsMockRecorder) SetAggregationTypes(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAggregationTypes", reflect.TypeOf((*MockAggregationOptions)(nil).SetAggregationTypes), value)
+}
+
+// AggregationTypes mocks base method
+func (m *MockAggregationOptions) AggregationTypes() aggregation.Types {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AggregationTypes")
+	ret0, _ := ret[0].(aggregation.Types)
+	return ret0
+}
+
+// AggregationTypes indicates an expected call of AggregationTypes
+func (mr *MockAggregationOptionsMockRecorder) AggregationTypes() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AggregationTypes", reflect.TypeOf((*MockAggregationOptions)(nil).AggregationTypes))
+}
+
+// SetIDScheme mocks base method
+func (m *MockAggregationOptions) SetIDScheme(value models.IDSchemeType) AggregationOptions {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetIDScheme", value)
+	ret0, _ := ret[0].(AggregationOptions)
+	return ret0
+}
+
+// SetIDScheme indicates an expected call of SetIDScheme
+func (mr *MockAggregationOptionsMockRecorder) SetIDScheme(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetIDScheme", reflect.TypeOf((*MockAggregationOptions)(nil).SetIDScheme), value)
+}
+
+// IDScheme mocks base method
+func (m *MockAggregationOptions) IDScheme() models.IDSchemeType {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IDScheme")
+	ret0, _ := ret[0].(models.IDSchemeType)
+	return ret0
+}
+
+// IDScheme indicates an expected call of IDScheme
+func (mr *MockAggregationOptionsMockRecorder) IDScheme() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IDScheme", reflect.TypeOf((*MockAggregationOptions)(nil).IDScheme))
+}
+
+// MockColdTierOptions is a mock of ColdTierOptions interface
+type MockColdTierOptions struct {
+	ctrl     *gomock.Controller
+	recorder *MockColdTierOptionsMockRecorder
+}
+
+// MockColdTierOptionsMockRecorder is the mock recorder for MockColdTierOptions
+type MockColdTierOptionsMockRecorder struct {
+	mock *MockColdTierOptions
+}
+
+// NewMockColdTierOptions creates a new mock instance
+func NewMockColdTierOptions(ctrl *gomock.Controller) *MockColdTierOptions {
+	mock := &MockColdTierOptions{ctrl: ctrl}
+	mock.recorder = &MockColdTierOptionsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockColdTierOptions) EXPECT() *MockColdTierOptionsMockRecorder {
+	return m.recorder
+}
+
+// Equal mocks base method
+func (m *MockColdTierOptions) Equal(value ColdTierOptions) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Equal", value)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Equal indicates an expected call of Equal
+func (mr *MockColdTierOptionsMockRecorder) Equal(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Equal", reflect.TypeOf((*MockColdTierOptions)(nil).Equal), value)
+}
+
+// Enabled mocks base method
+func (m *MockColdTierOptions) Enabled() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Enabled")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Enabled indicates an expected call of Enabled
+func (mr *MockColdTierOptionsMockRecorder) Enabled() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Enabled", reflect.TypeOf((*MockColdTierOptions)(nil).Enabled))
+}
+
+// SetFilePathPrefix mocks base method
+func (m *MockColdTierOptions) SetFilePathPrefix(value string) ColdTierOptions {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetFilePathPrefix", value)
+	ret0, _ := ret[0].(ColdTierOptions)
+	return ret0
+}
+
+// SetFilePathPrefix indicates an expected call of SetFilePathPrefix
+func (mr *MockColdTierOptionsMockRecorder) SetFilePathPrefix(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetFilePathPrefix", reflect.TypeOf((*MockColdTierOptions)(nil).SetFilePathPrefix), value)
+}
+
+// FilePathPrefix mocks base method
+func (m *MockColdTierOptions) FilePathPrefix() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FilePathPrefix")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// FilePathPrefix indicates an expected call of FilePathPrefix
+func (mr *MockColdTierOptionsMockRecorder) FilePathPrefix() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FilePathPrefix", reflect.TypeOf((*MockColdTierOptions)(nil).FilePathPrefix))
+}
+
+// SetAge mocks base method
+func (m *MockColdTierOptions) SetAge(value time.Duration) ColdTierOptions {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAge", value)
+	ret0, _ := ret[0].(ColdTierOptions)
+	return ret0
+}
+
+// SetAge indicates an expected call of SetAge
+func (mr *MockColdTierOptionsMockRecorder) SetAge(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAge", reflect.TypeOf((*MockColdTierOptions)(nil).SetAge), value)
+}
+
+// Age mocks base method
+func (m *MockColdTierOptions) Age() time.Duration {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Age")
+	ret0, _ := ret[0].(time.Duration)
+	return ret0
+}
+
+// Age indicates an expected call of Age
+func (mr *MockColdTierOptionsMockRecorder) Age() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Age", reflect.TypeOf((*MockColdTierOptions)(nil).Age))
+}
+
+// MockWriteClass is a mock of WriteClass interface
+type MockWriteClass struct {
+	ctrl     *gomock.Controller
+	recorder *MockWriteClassMockRecorder
+}
+
+// MockWriteClassMockRecorder is the mock recorder for MockWriteClass
+type MockWriteClassMockRecorder struct {
+	mock *MockWriteClass
+}
+
+// NewMockWriteClass creates a new mock instance
+func NewMockWriteClass(ctrl *gomock.Controller) *MockWriteClass {
+	mock := &MockWriteClass{ctrl: ctrl}
+	mock.recorder = &MockWriteClassMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockWriteClass) EXPECT() *MockWriteClassMockRecorder {
+	return m.recorder
+}
+
+// Equal mocks base method
+func (m *MockWriteClass) Equal(value WriteClass) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Equal", value)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Equal indicates an expected call of Equal
+func (mr *MockWriteClassMockRecorder) Equal(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Equal", reflect.TypeOf((*MockWriteClass)(nil).Equal), value)
+}
+
+// Name mocks base method
+func (m *MockWriteClass) Name() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Name")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Name indicates an expected call of Name
+func (mr *MockWriteClassMockRecorder) Name() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Name", reflect.TypeOf((*MockWriteClass)(nil).Name))
+}
+
+// TagName mocks base method
+func (m *MockWriteClass) TagName() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TagName")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// TagName indicates an expected call of TagName
+func (mr *MockWriteClassMockRecorder) TagName() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TagName", reflect.TypeOf((*MockWriteClass)(nil).TagName))
+}
+
+// TagValuePattern mocks base method
+func (m *MockWriteClass) TagValuePattern() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TagValuePattern")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// TagValuePattern indicates an expected call of TagValuePattern
+func (mr *MockWriteClassMockRecorder) TagValuePattern() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TagValuePattern", reflect.TypeOf((*MockWriteClass)(nil).TagValuePattern))
+}
+
+// BufferPast mocks base method
+func (m *MockWriteClass) BufferPast() time.Duration {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BufferPast")
+	ret0, _ := ret[0].(time.Duration)
+	return ret0
+}
+
+// BufferPast indicates an expected call of BufferPast
+func (mr *MockWriteClassMockRecorder) BufferPast() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BufferPast", reflect.TypeOf((*MockWriteClass)(nil).BufferPast))
+}
+
+// BufferFuture mocks base method
+func (m *MockWriteClass) BufferFuture() time.Duration {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BufferFuture")
+	ret0, _ := ret[0].(time.Duration)
+	return ret0
+}
+
+// BufferFuture indicates an expected call of BufferFuture
+func (mr *MockWriteClassMockRecorder) BufferFuture() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BufferFuture", reflect.TypeOf((*MockWriteClass)(nil).BufferFuture))
+}
+
+// Matches mocks base method
+func (m *MockWriteClass) Matches(tags ident.Tags) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Matches", tags)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Matches indicates an expected call of Matches
+func (mr *MockWriteClassMockRecorder) Matches(tags interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Matches", reflect.TypeOf((*MockWriteClass)(nil).Matches), tags)
+}
+
 // MockSchemaDescr is a mock of SchemaDescr interface
 type MockSchemaDescr struct {
 	ctrl     *gomock.Controller
@@ -22,6 +22,7 @@ package namespace
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/m3db/m3/src/dbnode/retention"
 )
@@ -47,25 +48,47 @@ const (
 
 	// Namespace with cold writes disabled by default.
 	defaultColdWritesEnabled = false
+
+	// Namespace without series value summaries by default.
+	defaultValueSummariesEnabled = false
+
+	// Namespace without native histograms by default.
+	defaultHistogramsEnabled = false
 )
 
 var (
 	errIndexBlockSizePositive                       = errors.New("index block size must positive")
 	errIndexBlockSizeTooLarge                       = errors.New("index block size needs to be <= namespace retention period")
 	errIndexBlockSizeMustBeAMultipleOfDataBlockSize = errors.New("index block size must be a multiple of data block size")
+	errIndexCompactionAgeNegative                   = errors.New("index compaction age must not be negative")
+	errIndexCompactedBlockSizeNegative              = errors.New("index compacted block size must not be negative")
+	errIndexCompactedBlockSizeTooLarge              = errors.New("index compacted block size needs to be <= namespace retention period")
+	errIndexCompactedBlockSizeMustBeAMultiple       = errors.New("index compacted block size must be a larger multiple of index block size")
+	errAggregationResolutionPositive                = errors.New("aggregation resolution must be positive")
+	errAggregationResolutionMustDivideBlockSize     = errors.New("data block size must be a multiple of aggregation resolution")
+	errAggregationSourceNamespaceEmpty              = errors.New("aggregation source namespace must be set")
+	errAggregationTypesEmpty                        = errors.New("aggregation types must be set")
+	errColdTierAgePositive                          = errors.New("cold tier age must be positive")
+	errColdTierAgeTooLarge                          = errors.New("cold tier age needs to be < namespace retention period")
+	errWriteClassNil                                = errors.New("write class must not be nil")
 )
 
 type options struct {
-	bootstrapEnabled  bool
-	flushEnabled      bool
-	snapshotEnabled   bool
-	writesToCommitLog bool
-	cleanupEnabled    bool
-	repairEnabled     bool
-	coldWritesEnabled bool
-	retentionOpts     retention.Options
-	indexOpts         IndexOptions
-	schemaHis         SchemaHistory
+	bootstrapEnabled      bool
+	flushEnabled          bool
+	snapshotEnabled       bool
+	writesToCommitLog     bool
+	cleanupEnabled        bool
+	repairEnabled         bool
+	coldWritesEnabled     bool
+	valueSummariesEnabled bool
+	histogramsEnabled     bool
+	retentionOpts         retention.Options
+	indexOpts             IndexOptions
+	schemaHis             SchemaHistory
+	aggregationOpts       AggregationOptions
+	coldTierOpts          ColdTierOptions
+	writeClasses          []WriteClass
 }
 
 // NewSchemaHistory returns an empty schema history.
@@ -76,16 +99,20 @@ func NewSchemaHistory() SchemaHistory {
 // NewOptions creates a new namespace options
 func NewOptions() Options {
 	return &options{
-		bootstrapEnabled:  defaultBootstrapEnabled,
-		flushEnabled:      defaultFlushEnabled,
-		snapshotEnabled:   defaultSnapshotEnabled,
-		writesToCommitLog: defaultWritesToCommitLog,
-		cleanupEnabled:    defaultCleanupEnabled,
-		repairEnabled:     defaultRepairEnabled,
-		coldWritesEnabled: defaultColdWritesEnabled,
-		retentionOpts:     retention.NewOptions(),
-		indexOpts:         NewIndexOptions(),
-		schemaHis:         NewSchemaHistory(),
+		bootstrapEnabled:      defaultBootstrapEnabled,
+		flushEnabled:          defaultFlushEnabled,
+		snapshotEnabled:       defaultSnapshotEnabled,
+		writesToCommitLog:     defaultWritesToCommitLog,
+		cleanupEnabled:        defaultCleanupEnabled,
+		repairEnabled:         defaultRepairEnabled,
+		coldWritesEnabled:     defaultColdWritesEnabled,
+		valueSummariesEnabled: defaultValueSummariesEnabled,
+		histogramsEnabled:     defaultHistogramsEnabled,
+		retentionOpts:         retention.NewOptions(),
+		indexOpts:             NewIndexOptions(),
+		schemaHis:             NewSchemaHistory(),
+		aggregationOpts:       NewAggregationOptions(),
+		coldTierOpts:          NewColdTierOptions(),
 	}
 }
 
@@ -93,6 +120,15 @@ func (o *options) Validate() error {
 	if err := o.retentionOpts.Validate(); err != nil {
 		return err
 	}
+	if err := o.validateAggregationOptions(); err != nil {
+		return err
+	}
+	if err := o.validateColdTierOptions(); err != nil {
+		return err
+	}
+	if err := o.validateWriteClasses(); err != nil {
+		return err
+	}
 	if !o.indexOpts.Enabled() {
 		return nil
 	}
@@ -111,6 +147,100 @@ func (o *options) Validate() error {
 	if indexBlockSize%dataBlockSize != 0 {
 		return errIndexBlockSizeMustBeAMultipleOfDataBlockSize
 	}
+	return o.validateIndexCompactionOptions(retention, indexBlockSize)
+}
+
+func (o *options) validateIndexCompactionOptions(
+	retention time.Duration,
+	indexBlockSize time.Duration,
+) error {
+	compactedBlockSize := o.indexOpts.CompactedBlockSize()
+	if o.indexOpts.CompactionAge() < 0 {
+		return errIndexCompactionAgeNegative
+	}
+	if compactedBlockSize < 0 {
+		return errIndexCompactedBlockSizeNegative
+	}
+	if compactedBlockSize == 0 {
+		return nil
+	}
+	if retention < compactedBlockSize {
+		return errIndexCompactedBlockSizeTooLarge
+	}
+	if compactedBlockSize <= indexBlockSize || compactedBlockSize%indexBlockSize != 0 {
+		return errIndexCompactedBlockSizeMustBeAMultiple
+	}
+	return nil
+}
+
+func (o *options) validateAggregationOptions() error {
+	if !o.aggregationOpts.Enabled() {
+		return nil
+	}
+	var (
+		resolution    = o.aggregationOpts.Resolution()
+		dataBlockSize = o.retentionOpts.BlockSize()
+		source        = o.aggregationOpts.SourceNamespace()
+		aggTypes      = o.aggregationOpts.AggregationTypes()
+	)
+	if resolution <= 0 {
+		return errAggregationResolutionPositive
+	}
+	if dataBlockSize%resolution != 0 {
+		return errAggregationResolutionMustDivideBlockSize
+	}
+	if source == nil || len(source.Bytes()) == 0 {
+		return errAggregationSourceNamespaceEmpty
+	}
+	if len(aggTypes) == 0 {
+		return errAggregationTypesEmpty
+	}
+	for _, aggType := range aggTypes {
+		if !aggType.IsValidForGauge() {
+			return fmt.Errorf("aggregation type %s is not supported for rollups", aggType.String())
+		}
+	}
+	if err := o.aggregationOpts.IDScheme().Validate(); err != nil {
+		return fmt.Errorf("aggregation id scheme is invalid: %v", err)
+	}
+	return nil
+}
+
+func (o *options) validateColdTierOptions() error {
+	if !o.coldTierOpts.Enabled() {
+		return nil
+	}
+	age := o.coldTierOpts.Age()
+	if age <= 0 {
+		return errColdTierAgePositive
+	}
+	if age >= o.retentionOpts.RetentionPeriod() {
+		return errColdTierAgeTooLarge
+	}
+	return nil
+}
+
+func (o *options) validateWriteClasses() error {
+	names := make(map[string]struct{}, len(o.writeClasses))
+	for _, class := range o.writeClasses {
+		if class == nil {
+			return errWriteClassNil
+		}
+		name := class.Name()
+		if _, ok := names[name]; ok {
+			return fmt.Errorf("duplicate write class %s", name)
+		}
+		names[name] = struct{}{}
+
+		// A write class must satisfy the same buffer constraints as the
+		// namespace retention options.
+		ropts := o.retentionOpts.
+			SetBufferPast(class.BufferPast()).
+			SetBufferFuture(class.BufferFuture())
+		if err := ropts.Validate(); err != nil {
+			return fmt.Errorf("invalid write class %s: %v", name, err)
+		}
+	}
 	return nil
 }
 
@@ -122,9 +252,14 @@ func (o *options) Equal(value Options) bool {
 		o.cleanupEnabled == value.CleanupEnabled() &&
 		o.repairEnabled == value.RepairEnabled() &&
 		o.coldWritesEnabled == value.ColdWritesEnabled() &&
+		o.valueSummariesEnabled == value.ValueSummariesEnabled() &&
+		o.histogramsEnabled == value.HistogramsEnabled() &&
 		o.retentionOpts.Equal(value.RetentionOptions()) &&
 		o.indexOpts.Equal(value.IndexOptions()) &&
-		o.schemaHis.Equal(value.SchemaHistory())
+		o.schemaHis.Equal(value.SchemaHistory()) &&
+		o.aggregationOpts.Equal(value.AggregationOptions()) &&
+		o.coldTierOpts.Equal(value.ColdTierOptions()) &&
+		writeClassesEqual(o.writeClasses, value.WriteClasses())
 }
 
 func (o *options) SetBootstrapEnabled(value bool) Options {
@@ -197,6 +332,26 @@ func (o *options) ColdWritesEnabled() bool {
 	return o.coldWritesEnabled
 }
 
+func (o *options) SetValueSummariesEnabled(value bool) Options {
+	opts := *o
+	opts.valueSummariesEnabled = value
+	return &opts
+}
+
+func (o *options) ValueSummariesEnabled() bool {
+	return o.valueSummariesEnabled
+}
+
+func (o *options) SetHistogramsEnabled(value bool) Options {
+	opts := *o
+	opts.histogramsEnabled = value
+	return &opts
+}
+
+func (o *options) HistogramsEnabled() bool {
+	return o.histogramsEnabled
+}
+
 func (o *options) SetRetentionOptions(value retention.Options) Options {
 	opts := *o
 	opts.retentionOpts = value
@@ -226,3 +381,33 @@ func (o *options) SetSchemaHistory(value SchemaHistory) Options {
 func (o *options) SchemaHistory() SchemaHistory {
 	return o.schemaHis
 }
+
+func (o *options) SetAggregationOptions(value AggregationOptions) Options {
+	opts := *o
+	opts.aggregationOpts = value
+	return &opts
+}
+
+func (o *options) AggregationOptions() AggregationOptions {
+	return o.aggregationOpts
+}
+
+func (o *options) SetColdTierOptions(value ColdTierOptions) Options {
+	opts := *o
+	opts.coldTierOpts = value
+	return &opts
+}
+
+func (o *options) ColdTierOptions() ColdTierOptions {
+	return o.coldTierOpts
+}
+
+func (o *options) SetWriteClasses(value []WriteClass) Options {
+	opts := *o
+	opts.writeClasses = value
+	return &opts
+}
+
+func (o *options) WriteClasses() []WriteClass {
+	return o.writeClasses
+}
@@ -26,6 +26,9 @@ import (
 	"time"
 
 	"github.com/m3db/m3/src/dbnode/retention"
+	"github.com/m3db/m3/src/metrics/aggregation"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/x/ident"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
@@ -100,6 +103,8 @@ func TestOptionsValidate(t *testing.T) {
 		SetIndexOptions(iOpts)
 
 	iOpts.EXPECT().Enabled().Return(true).AnyTimes()
+	iOpts.EXPECT().CompactionAge().Return(time.Duration(0)).AnyTimes()
+	iOpts.EXPECT().CompactedBlockSize().Return(time.Duration(0)).AnyTimes()
 
 	rOpts.EXPECT().Validate().Return(nil)
 	rOpts.EXPECT().RetentionPeriod().Return(time.Hour)
@@ -165,6 +170,67 @@ func TestOptionsValidateBlockSizePositive(t *testing.T) {
 	require.Error(t, o1.Validate())
 }
 
+func TestOptionsValidateIndexCompaction(t *testing.T) {
+	opts := NewOptions().
+		SetRetentionOptions(retention.NewOptions().
+			SetRetentionPeriod(48 * time.Hour).
+			SetBlockSize(2 * time.Hour)).
+		SetIndexOptions(NewIndexOptions().
+			SetEnabled(true).
+			SetBlockSize(2 * time.Hour))
+
+	tests := []struct {
+		name      string
+		indexOpts IndexOptions
+		expectErr error
+	}{
+		{
+			name:      "disabled",
+			indexOpts: opts.IndexOptions(),
+		},
+		{
+			name: "enabled",
+			indexOpts: opts.IndexOptions().
+				SetCompactionAge(6 * time.Hour).
+				SetCompactedBlockSize(24 * time.Hour),
+		},
+		{
+			name: "negative age",
+			indexOpts: opts.IndexOptions().
+				SetCompactionAge(-time.Hour).
+				SetCompactedBlockSize(24 * time.Hour),
+			expectErr: errIndexCompactionAgeNegative,
+		},
+		{
+			name:      "negative block size",
+			indexOpts: opts.IndexOptions().SetCompactedBlockSize(-24 * time.Hour),
+			expectErr: errIndexCompactedBlockSizeNegative,
+		},
+		{
+			name:      "block size larger than retention",
+			indexOpts: opts.IndexOptions().SetCompactedBlockSize(72 * time.Hour),
+			expectErr: errIndexCompactedBlockSizeTooLarge,
+		},
+		{
+			name:      "block size not a multiple",
+			indexOpts: opts.IndexOptions().SetCompactedBlockSize(5 * time.Hour),
+			expectErr: errIndexCompactedBlockSizeMustBeAMultiple,
+		},
+		{
+			name:      "block size not larger",
+			indexOpts: opts.IndexOptions().SetCompactedBlockSize(2 * time.Hour),
+			expectErr: errIndexCompactedBlockSizeMustBeAMultiple,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := opts.SetIndexOptions(test.indexOpts).Validate()
+			require.Equal(t, test.expectErr, err)
+		})
+	}
+}
+
 func TestOptionsValidateNoIndexing(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -180,3 +246,59 @@ func TestOptionsValidateNoIndexing(t *testing.T) {
 	rOpts.EXPECT().Validate().Return(nil)
 	require.NoError(t, o1.Validate())
 }
+
+func TestOptionsValidateAggregation(t *testing.T) {
+	aggOpts := NewAggregationOptions().
+		SetEnabled(true).
+		SetResolution(time.Minute).
+		SetSourceNamespace(ident.StringID("raw"))
+	opts := NewOptions().SetAggregationOptions(aggOpts)
+	require.NoError(t, opts.Validate())
+
+	require.Error(t, opts.SetAggregationOptions(
+		aggOpts.SetResolution(0)).Validate())
+	require.Error(t, opts.SetAggregationOptions(
+		aggOpts.SetResolution(7*time.Minute)).Validate())
+	require.Error(t, opts.SetAggregationOptions(
+		aggOpts.SetSourceNamespace(nil)).Validate())
+	require.Error(t, opts.SetAggregationOptions(
+		aggOpts.SetAggregationTypes(nil)).Validate())
+	require.Error(t, opts.SetAggregationOptions(
+		aggOpts.SetAggregationTypes(aggregation.Types{aggregation.P99})).Validate())
+	require.Error(t, opts.SetAggregationOptions(
+		aggOpts.SetIDScheme(models.TypeDefault)).Validate())
+}
+
+func TestOptionsValidateColdTier(t *testing.T) {
+	coldTierOpts := NewColdTierOptions().
+		SetFilePathPrefix("/var/lib/m3db-cold").
+		SetAge(24 * time.Hour)
+	opts := NewOptions().SetColdTierOptions(coldTierOpts)
+	require.NoError(t, opts.Validate())
+
+	require.Error(t, opts.SetColdTierOptions(
+		coldTierOpts.SetAge(0)).Validate())
+	require.Error(t, opts.SetColdTierOptions(
+		coldTierOpts.SetAge(opts.RetentionOptions().RetentionPeriod())).Validate())
+	require.NoError(t, opts.SetColdTierOptions(
+		coldTierOpts.SetFilePathPrefix("").SetAge(0)).Validate())
+}
+
+func TestOptionsValidateWriteClasses(t *testing.T) {
+	iot, err := NewWriteClass("iot", "source", "device-.*", time.Hour, time.Minute)
+	require.NoError(t, err)
+	opts := NewOptions().SetWriteClasses([]WriteClass{iot})
+	require.NoError(t, opts.Validate())
+
+	dup, err := NewWriteClass("iot", "app", "sensor", time.Hour, time.Minute)
+	require.NoError(t, err)
+	require.Error(t, opts.SetWriteClasses([]WriteClass{iot, dup}).Validate())
+
+	blockSize := opts.RetentionOptions().BlockSize()
+	tooLarge, err := NewWriteClass("late", "source", "device-.*", blockSize, 0)
+	require.NoError(t, err)
+	require.Error(t, opts.SetWriteClasses([]WriteClass{tooLarge}).Validate())
+
+	require.False(t, opts.Equal(NewOptions()))
+	require.True(t, opts.Equal(NewOptions().SetWriteClasses([]WriteClass{iot})))
+}
@@ -25,6 +25,8 @@ import (
 
 	"github.com/m3db/m3/src/cluster/client"
 	"github.com/m3db/m3/src/dbnode/retention"
+	"github.com/m3db/m3/src/metrics/aggregation"
+	"github.com/m3db/m3/src/query/models"
 	"github.com/m3db/m3/src/x/ident"
 	"github.com/m3db/m3/src/x/instrument"
 	xclose "github.com/m3db/m3/src/x/close"
@@ -80,6 +82,24 @@ type Options interface {
 	// ColdWritesEnabled returns whether cold writes are enabled for this namespace.
 	ColdWritesEnabled() bool
 
+	// SetValueSummariesEnabled sets whether the series of this namespace keep
+	// a summary of their values for each block so that queries can filter
+	// series by value.
+	SetValueSummariesEnabled(value bool) Options
+
+	// ValueSummariesEnabled returns whether the series of this namespace keep
+	// a summary of their values for each block.
+	ValueSummariesEnabled() bool
+
+	// SetHistogramsEnabled sets whether the series of this namespace are
+	// encoded with the histogram encoding so that their datapoints can hold
+	// native histograms.
+	SetHistogramsEnabled(value bool) Options
+
+	// HistogramsEnabled returns whether the series of this namespace are
+	// encoded with the histogram encoding.
+	HistogramsEnabled() bool
+
 	// SetRetentionOptions sets the retention options for this namespace
 	SetRetentionOptions(value retention.Options) Options
 
@@ -97,6 +117,25 @@ type Options interface {
 
 	// SchemaHistory returns the schema registry for this namespace.
 	SchemaHistory() SchemaHistory
+
+	// SetAggregationOptions sets the AggregationOptions.
+	SetAggregationOptions(value AggregationOptions) Options
+
+	// AggregationOptions returns the AggregationOptions.
+	AggregationOptions() AggregationOptions
+
+	// SetColdTierOptions sets the ColdTierOptions.
+	SetColdTierOptions(value ColdTierOptions) Options
+
+	// ColdTierOptions returns the ColdTierOptions.
+	ColdTierOptions() ColdTierOptions
+
+	// SetWriteClasses sets the write classes, series belong to the first
+	// write class that matches them.
+	SetWriteClasses(value []WriteClass) Options
+
+	// WriteClasses returns the write classes.
+	WriteClasses() []WriteClass
 }
 
 // IndexOptions controls the indexing options for a namespace.
@@ -115,6 +154,124 @@ type IndexOptions interface {
 
 	// BlockSize returns the block size.
 	BlockSize() time.Duration
+
+	// SetTokenizedFields sets the names of the tags whose values are split
+	// into tokens that can be searched with match queries.
+	SetTokenizedFields(value []string) IndexOptions
+
+	// TokenizedFields returns the names of the tags whose values are split
+	// into tokens that can be searched with match queries.
+	TokenizedFields() []string
+
+	// SetCompactionAge sets the age past which sealed index blocks are
+	// compacted into blocks of the compacted block size.
+	SetCompactionAge(value time.Duration) IndexOptions
+
+	// CompactionAge returns the age past which sealed index blocks are
+	// compacted into blocks of the compacted block size.
+	CompactionAge() time.Duration
+
+	// SetCompactedBlockSize sets the block size of compacted index blocks,
+	// zero disables the compaction of index blocks.
+	SetCompactedBlockSize(value time.Duration) IndexOptions
+
+	// CompactedBlockSize returns the block size of compacted index blocks,
+	// zero disables the compaction of index blocks.
+	CompactedBlockSize() time.Duration
+}
+
+// AggregationOptions controls the aggregation options for a namespace, an
+// aggregated namespace holds rollups of the data flushed by its source
+// namespace rather than data written to it directly.
+type AggregationOptions interface {
+	// Equal returns true if the provide value is equal to this one.
+	Equal(value AggregationOptions) bool
+
+	// SetEnabled sets whether the namespace is an aggregated namespace.
+	SetEnabled(value bool) AggregationOptions
+
+	// Enabled returns whether the namespace is an aggregated namespace.
+	Enabled() bool
+
+	// SetResolution sets the resolution of the rolled up datapoints.
+	SetResolution(value time.Duration) AggregationOptions
+
+	// Resolution returns the resolution of the rolled up datapoints.
+	Resolution() time.Duration
+
+	// SetSourceNamespace sets the namespace whose data is rolled up.
+	SetSourceNamespace(value ident.ID) AggregationOptions
+
+	// SourceNamespace returns the namespace whose data is rolled up.
+	SourceNamespace() ident.ID
+
+	// SetAggregationTypes sets the aggregation types used to roll up data.
+	SetAggregationTypes(value aggregation.Types) AggregationOptions
+
+	// AggregationTypes returns the aggregation types used to roll up data.
+	AggregationTypes() aggregation.Types
+
+	// SetIDScheme sets the scheme used to generate the IDs of rolled up
+	// series from their tags.
+	SetIDScheme(value models.IDSchemeType) AggregationOptions
+
+	// IDScheme returns the scheme used to generate the IDs of rolled up
+	// series from their tags.
+	IDScheme() models.IDSchemeType
+}
+
+// ColdTierOptions controls the cold storage tier of a namespace, flushed
+// data filesets of blocks older than the configured age are moved from the
+// primary file path prefix to the file path prefix of the cold tier.
+type ColdTierOptions interface {
+	// Equal returns true if the provide value is equal to this one.
+	Equal(value ColdTierOptions) bool
+
+	// Enabled returns whether the cold tier is enabled, i.e. whether a file
+	// path prefix is set for the cold tier.
+	Enabled() bool
+
+	// SetFilePathPrefix sets the file path prefix of the cold tier.
+	SetFilePathPrefix(value string) ColdTierOptions
+
+	// FilePathPrefix returns the file path prefix of the cold tier.
+	FilePathPrefix() string
+
+	// SetAge sets the age after which blocks are moved to the cold tier.
+	SetAge(value time.Duration) ColdTierOptions
+
+	// Age returns the age after which blocks are moved to the cold tier.
+	Age() time.Duration
+}
+
+// WriteClass is a class of series of a namespace, selected by a tag matcher,
+// that accept warm writes within their own buffer past and buffer future
+// rather than the ones of the namespace retention options. This lets series
+// that are routinely written late land in warm buffer buckets without cold
+// writes being enabled for every series of the namespace.
+type WriteClass interface {
+	// Equal returns true if the provide value is equal to this one.
+	Equal(value WriteClass) bool
+
+	// Name returns the name of the write class.
+	Name() string
+
+	// TagName returns the name of the tag matched by the write class.
+	TagName() string
+
+	// TagValuePattern returns the regular expression that the value of the
+	// tag must fully match for a series to belong to the write class.
+	TagValuePattern() string
+
+	// BufferPast returns the buffer past of series of the write class.
+	BufferPast() time.Duration
+
+	// BufferFuture returns the buffer future of series of the write class.
+	BufferFuture() time.Duration
+
+	// Matches returns whether the series with the given tags belongs to the
+	// write class.
+	Matches(tags ident.Tags) bool
 }
 
 // SchemaDescr describes the schema for a complex type value.
@@ -0,0 +1,149 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package namespace
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/retention"
+	"github.com/m3db/m3/src/x/ident"
+)
+
+var (
+	errWriteClassNameEmpty    = errors.New("write class name must be set")
+	errWriteClassTagNameEmpty = errors.New("write class tag name must be set")
+)
+
+type writeClass struct {
+	name            string
+	tagName         []byte
+	tagValuePattern string
+	tagValueRegexp  *regexp.Regexp
+	bufferPast      time.Duration
+	bufferFuture    time.Duration
+}
+
+// NewWriteClass returns a new WriteClass matching series with a value for
+// the tag name that fully matches the tag value pattern regular expression.
+func NewWriteClass(
+	name string,
+	tagName string,
+	tagValuePattern string,
+	bufferPast time.Duration,
+	bufferFuture time.Duration,
+) (WriteClass, error) {
+	if name == "" {
+		return nil, errWriteClassNameEmpty
+	}
+	if tagName == "" {
+		return nil, errWriteClassTagNameEmpty
+	}
+	re, err := regexp.Compile("^(?:" + tagValuePattern + ")$")
+	if err != nil {
+		return nil, fmt.Errorf("invalid tag value pattern for write class %s: %v",
+			name, err)
+	}
+	return &writeClass{
+		name:            name,
+		tagName:         []byte(tagName),
+		tagValuePattern: tagValuePattern,
+		tagValueRegexp:  re,
+		bufferPast:      bufferPast,
+		bufferFuture:    bufferFuture,
+	}, nil
+}
+
+func (c *writeClass) Equal(value WriteClass) bool {
+	return c.Name() == value.Name() &&
+		c.TagName() == value.TagName() &&
+		c.TagValuePattern() == value.TagValuePattern() &&
+		c.BufferPast() == value.BufferPast() &&
+		c.BufferFuture() == value.BufferFuture()
+}
+
+func (c *writeClass) Name() string {
+	return c.name
+}
+
+func (c *writeClass) TagName() string {
+	return string(c.tagName)
+}
+
+func (c *writeClass) TagValuePattern() string {
+	return c.tagValuePattern
+}
+
+func (c *writeClass) BufferPast() time.Duration {
+	return c.bufferPast
+}
+
+func (c *writeClass) BufferFuture() time.Duration {
+	return c.bufferFuture
+}
+
+func (c *writeClass) Matches(tags ident.Tags) bool {
+	for _, tag := range tags.Values() {
+		if bytes.Equal(tag.Name.Bytes(), c.tagName) {
+			return c.tagValueRegexp.Match(tag.Value.Bytes())
+		}
+	}
+	return false
+}
+
+// WarmWriteRetentionOptions returns the retention options of a namespace with
+// the buffer past and buffer future widened to the largest ones of any of its
+// write classes, these bound when warm writes for a block can still arrive and
+// so when the block can be flushed.
+func WarmWriteRetentionOptions(opts Options) retention.Options {
+	var (
+		ropts        = opts.RetentionOptions()
+		bufferPast   = ropts.BufferPast()
+		bufferFuture = ropts.BufferFuture()
+	)
+	for _, class := range opts.WriteClasses() {
+		if v := class.BufferPast(); v > bufferPast {
+			bufferPast = v
+		}
+		if v := class.BufferFuture(); v > bufferFuture {
+			bufferFuture = v
+		}
+	}
+	if bufferPast == ropts.BufferPast() && bufferFuture == ropts.BufferFuture() {
+		return ropts
+	}
+	return ropts.SetBufferPast(bufferPast).SetBufferFuture(bufferFuture)
+}
+
+func writeClassesEqual(a, b []WriteClass) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].Equal(b[i]) {
+			return false
+		}
+	}
+	return true
+}
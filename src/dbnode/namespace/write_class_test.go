@@ -0,0 +1,68 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package namespace
+
+import (
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/x/ident"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewWriteClassInvalid(t *testing.T) {
+	_, err := NewWriteClass("", "source", "device", time.Hour, 0)
+	require.Error(t, err)
+	_, err = NewWriteClass("iot", "", "device", time.Hour, 0)
+	require.Error(t, err)
+	_, err = NewWriteClass("iot", "source", "device-(", time.Hour, 0)
+	require.Error(t, err)
+}
+
+func TestWriteClassMatches(t *testing.T) {
+	iot, err := NewWriteClass("iot", "source", "device-[0-9]+", time.Hour, 0)
+	require.NoError(t, err)
+
+	tags := func(name, value string) ident.Tags {
+		return ident.NewTags(ident.StringTag(name, value))
+	}
+	require.True(t, iot.Matches(tags("source", "device-42")))
+	require.False(t, iot.Matches(tags("source", "my-device-42")))
+	require.False(t, iot.Matches(tags("source", "device-42-gateway")))
+	require.False(t, iot.Matches(tags("host", "device-42")))
+}
+
+func TestWarmWriteRetentionOptions(t *testing.T) {
+	opts := NewOptions()
+	require.Equal(t, opts.RetentionOptions(), WarmWriteRetentionOptions(opts))
+
+	iot, err := NewWriteClass("iot", "source", "device-.*", time.Hour, 0)
+	require.NoError(t, err)
+	future, err := NewWriteClass("future", "source", "scheduled", 0, 30*time.Minute)
+	require.NoError(t, err)
+	opts = opts.SetWriteClasses([]WriteClass{iot, future})
+
+	ropts := WarmWriteRetentionOptions(opts)
+	require.Equal(t, time.Hour, ropts.BufferPast())
+	require.Equal(t, 30*time.Minute, ropts.BufferFuture())
+	require.Equal(t, opts.RetentionOptions().BlockSize(), ropts.BlockSize())
+}
@@ -28,6 +28,7 @@ import (
 	"github.com/m3db/m3/src/dbnode/digest"
 	"github.com/m3db/m3/src/dbnode/generated/thrift/rpc"
 	tterrors "github.com/m3db/m3/src/dbnode/network/server/tchannelthrift/errors"
+	dberrors "github.com/m3db/m3/src/dbnode/storage/errors"
 	"github.com/m3db/m3/src/dbnode/storage/index"
 	"github.com/m3db/m3/src/dbnode/x/xio"
 	"github.com/m3db/m3/src/dbnode/x/xpool"
@@ -40,9 +41,10 @@ import (
 )
 
 var (
-	errUnknownTimeType  = errors.New("unknown time type")
-	errUnknownUnit      = errors.New("unknown unit")
-	errNilTaggedRequest = errors.New("nil write tagged request")
+	errUnknownTimeType        = errors.New("unknown time type")
+	errUnknownUnit            = errors.New("unknown unit")
+	errNilTaggedRequest       = errors.New("nil write tagged request")
+	errUnknownValueFilterType = errors.New("unknown value filter type")
 
 	timeZero time.Time
 )
@@ -190,6 +192,9 @@ func ToRPCError(err error) *rpc.Error {
 	if xerrors.IsInvalidParams(err) {
 		return tterrors.NewBadRequestError(err)
 	}
+	if dberrors.IsQuotaExceededError(err) {
+		return tterrors.NewResourceExhaustedError(err)
+	}
 	return tterrors.NewInternalError(err)
 }
 
@@ -223,6 +228,19 @@ func FromRPCFetchTaggedRequest(
 	if l := req.Limit; l != nil {
 		opts.Limit = int(*l)
 	}
+	if req.IsSetPageToken() {
+		opts.PageToken = index.PageToken(req.PageToken)
+		if _, err := opts.PageCursor(); err != nil {
+			return nil, index.Query{}, index.QueryOptions{}, false, err
+		}
+	}
+	if req.IsSetValueFilter() {
+		filter, err := FromRPCValueFilter(req.ValueFilter)
+		if err != nil {
+			return nil, index.Query{}, index.QueryOptions{}, false, err
+		}
+		opts.ValueFilter = &filter
+	}
 
 	q, err := idx.Unmarshal(req.Query)
 	if err != nil {
@@ -239,6 +257,33 @@ func FromRPCFetchTaggedRequest(
 	return ns, index.Query{Query: q}, opts, req.FetchData, nil
 }
 
+// FromRPCDeleteTaggedRequest converts the rpc request type for DeleteTaggedRequest into corresponding Go types.
+func FromRPCDeleteTaggedRequest(
+	req *rpc.DeleteTaggedRequest,
+) (ident.ID, index.Query, index.QueryOptions, error) {
+	start, rangeStartErr := ToTime(req.RangeStart, req.RangeTimeType)
+	if rangeStartErr != nil {
+		return nil, index.Query{}, index.QueryOptions{}, rangeStartErr
+	}
+
+	end, rangeEndErr := ToTime(req.RangeEnd, req.RangeTimeType)
+	if rangeEndErr != nil {
+		return nil, index.Query{}, index.QueryOptions{}, rangeEndErr
+	}
+
+	q, err := idx.Unmarshal(req.Query)
+	if err != nil {
+		return nil, index.Query{}, index.QueryOptions{}, err
+	}
+
+	opts := index.QueryOptions{
+		StartInclusive: start,
+		EndExclusive:   end,
+	}
+	ns := ident.StringID(string(req.NameSpace))
+	return ns, index.Query{Query: q}, opts, nil
+}
+
 // ToRPCFetchTaggedRequest converts the Go `client/` types into rpc request type for FetchTaggedRequest.
 func ToRPCFetchTaggedRequest(
 	ns ident.ID,
@@ -273,10 +318,76 @@ func ToRPCFetchTaggedRequest(
 		l := int64(opts.Limit)
 		request.Limit = &l
 	}
+	if opts.Paginated() {
+		request.PageToken = opts.PageToken
+	}
+	if opts.ValueFilter != nil {
+		filter, err := ToRPCValueFilter(*opts.ValueFilter)
+		if err != nil {
+			return rpc.FetchTaggedRequest{}, err
+		}
+		request.ValueFilter = filter
+	}
 
 	return request, nil
 }
 
+// FromRPCValueFilter converts the rpc value filter into an index value filter.
+func FromRPCValueFilter(filter *rpc.ValueFilter) (index.ValueFilter, error) {
+	var filterType index.ValueFilterType
+	switch filter.Type {
+	case rpc.ValueFilterType_EQUAL:
+		filterType = index.ValueFilterEqual
+	case rpc.ValueFilterType_NOT_EQUAL:
+		filterType = index.ValueFilterNotEqual
+	case rpc.ValueFilterType_GREATER:
+		filterType = index.ValueFilterGreater
+	case rpc.ValueFilterType_GREATER_EQUAL:
+		filterType = index.ValueFilterGreaterEqual
+	case rpc.ValueFilterType_LESS:
+		filterType = index.ValueFilterLess
+	case rpc.ValueFilterType_LESS_EQUAL:
+		filterType = index.ValueFilterLessEqual
+	default:
+		return index.ValueFilter{}, xerrors.NewInvalidParamsError(errUnknownValueFilterType)
+	}
+	return index.ValueFilter{
+		Type:   filterType,
+		Value:  filter.Value,
+		Latest: filter.GetLatest(),
+	}, nil
+}
+
+// ToRPCValueFilter converts an index value filter into the rpc value filter.
+func ToRPCValueFilter(filter index.ValueFilter) (*rpc.ValueFilter, error) {
+	var filterType rpc.ValueFilterType
+	switch filter.Type {
+	case index.ValueFilterEqual:
+		filterType = rpc.ValueFilterType_EQUAL
+	case index.ValueFilterNotEqual:
+		filterType = rpc.ValueFilterType_NOT_EQUAL
+	case index.ValueFilterGreater:
+		filterType = rpc.ValueFilterType_GREATER
+	case index.ValueFilterGreaterEqual:
+		filterType = rpc.ValueFilterType_GREATER_EQUAL
+	case index.ValueFilterLess:
+		filterType = rpc.ValueFilterType_LESS
+	case index.ValueFilterLessEqual:
+		filterType = rpc.ValueFilterType_LESS_EQUAL
+	default:
+		return nil, errUnknownValueFilterType
+	}
+	result := &rpc.ValueFilter{
+		Type:  filterType,
+		Value: filter.Value,
+	}
+	if filter.Latest {
+		latest := true
+		result.Latest = &latest
+	}
+	return result, nil
+}
+
 // FromRPCAggregateQueryRequest converts the rpc request type for AggregateRawQueryRequest into corresponding Go API types.
 func FromRPCAggregateQueryRequest(
 	req *rpc.AggregateQueryRequest,
@@ -345,6 +456,12 @@ func FromRPCAggregateQueryRawRequest(
 	if l := req.Limit; l != nil {
 		opts.Limit = int(*l)
 	}
+	if req.IsSetPageToken() {
+		opts.PageToken = index.PageToken(req.PageToken)
+		if _, err := opts.PageCursor(); err != nil {
+			return nil, index.Query{}, index.AggregationOptions{}, err
+		}
+	}
 
 	query, err := idx.Unmarshal(req.Query)
 	if err != nil {
@@ -394,6 +511,9 @@ func ToRPCAggregateQueryRawRequest(
 		l := int64(opts.Limit)
 		request.Limit = &l
 	}
+	if opts.Paginated() {
+		request.PageToken = opts.PageToken
+	}
 
 	query, queryErr := idx.Marshal(q.Query)
 	if queryErr != nil {
@@ -418,6 +538,160 @@ func ToRPCAggregateQueryRawRequest(
 	return request, nil
 }
 
+// FromRPCIndexCardinalityRequest converts the rpc request type for IndexCardinalityRequest into corresponding Go API types.
+func FromRPCIndexCardinalityRequest(
+	req *rpc.IndexCardinalityRequest,
+) (ident.ID, index.CardinalityOptions, error) {
+	start, rangeStartErr := ToTime(req.RangeStart, req.RangeTimeType)
+	if rangeStartErr != nil {
+		return nil, index.CardinalityOptions{}, rangeStartErr
+	}
+
+	end, rangeEndErr := ToTime(req.RangeEnd, req.RangeTimeType)
+	if rangeEndErr != nil {
+		return nil, index.CardinalityOptions{}, rangeEndErr
+	}
+
+	opts := index.CardinalityOptions{
+		StartInclusive: start,
+		EndExclusive:   end,
+	}
+	if l := req.Limit; l != nil {
+		opts.Limit = int(*l)
+	}
+	if len(req.TagNameFilter) > 0 {
+		opts.FieldFilter = index.AggregateFieldFilter(req.TagNameFilter)
+	}
+	if len(req.Query) > 0 {
+		q, err := idx.Unmarshal(req.Query)
+		if err != nil {
+			return nil, index.CardinalityOptions{}, err
+		}
+		opts.Query = &index.Query{Query: q}
+	}
+	opts.Match = index.CardinalityMatch{
+		Partial:    req.MatchPartial,
+		MaxEdits:   int(req.GetMatchMaxEdits()),
+		FieldNames: req.GetMatchTagNames(),
+	}
+	if err := opts.Match.Validate(); err != nil {
+		return nil, index.CardinalityOptions{}, err
+	}
+
+	ns := ident.StringID(string(req.NameSpace))
+	return ns, opts, nil
+}
+
+// ToRPCIndexCardinalityRequest converts the Go `client/` types into rpc request type for IndexCardinalityRequest.
+func ToRPCIndexCardinalityRequest(
+	ns ident.ID,
+	opts index.CardinalityOptions,
+) (rpc.IndexCardinalityRequest, error) {
+	rangeStart, tsErr := ToValue(opts.StartInclusive, fetchTaggedTimeType)
+	if tsErr != nil {
+		return rpc.IndexCardinalityRequest{}, tsErr
+	}
+
+	rangeEnd, tsErr := ToValue(opts.EndExclusive, fetchTaggedTimeType)
+	if tsErr != nil {
+		return rpc.IndexCardinalityRequest{}, tsErr
+	}
+
+	request := rpc.IndexCardinalityRequest{
+		NameSpace:     ns.Bytes(),
+		RangeStart:    rangeStart,
+		RangeEnd:      rangeEnd,
+		RangeTimeType: fetchTaggedTimeType,
+	}
+
+	if opts.Limit > 0 {
+		l := int64(opts.Limit)
+		request.Limit = &l
+	}
+
+	if len(opts.FieldFilter) > 0 {
+		filters := make([][]byte, 0, len(opts.FieldFilter))
+		for _, f := range opts.FieldFilter {
+			copied := append([]byte(nil), f...)
+			filters = append(filters, copied)
+		}
+		request.TagNameFilter = filters
+	}
+
+	if opts.Query != nil {
+		query, err := idx.Marshal(opts.Query.Query)
+		if err != nil {
+			return rpc.IndexCardinalityRequest{}, err
+		}
+		request.Query = query
+	}
+
+	if len(opts.Match.Partial) > 0 {
+		request.MatchPartial = opts.Match.Partial
+		if opts.Match.MaxEdits > 0 {
+			maxEdits := int32(opts.Match.MaxEdits)
+			request.MatchMaxEdits = &maxEdits
+		}
+		if opts.Match.FieldNames {
+			fieldNames := true
+			request.MatchTagNames = &fieldNames
+		}
+	}
+
+	return request, nil
+}
+
+// ToRPCIndexCardinalityResult converts the cardinality results into the rpc result type.
+func ToRPCIndexCardinalityResult(
+	results index.CardinalityResults,
+) *rpc.IndexCardinalityResult_ {
+	res := rpc.NewIndexCardinalityResult_()
+	res.NumSeries = results.NumSeries
+	res.TagNames = make([]*rpc.IndexCardinalityTagName, 0, len(results.Fields))
+	for _, f := range results.Fields {
+		res.TagNames = append(res.TagNames, &rpc.IndexCardinalityTagName{
+			Name:      f.Field,
+			NumValues: f.NumValues,
+			NumSeries: f.NumSeries,
+		})
+	}
+	res.Tags = make([]*rpc.IndexCardinalityTag, 0, len(results.Terms))
+	for _, t := range results.Terms {
+		res.Tags = append(res.Tags, &rpc.IndexCardinalityTag{
+			Name:      t.Field,
+			Value:     t.Term,
+			NumSeries: t.NumSeries,
+		})
+	}
+	return res
+}
+
+// FromRPCIndexCardinalityResult converts the rpc result type into cardinality results.
+func FromRPCIndexCardinalityResult(
+	res *rpc.IndexCardinalityResult_,
+) index.CardinalityResults {
+	results := index.CardinalityResults{
+		NumSeries: res.NumSeries,
+		Fields:    make([]index.FieldCardinality, 0, len(res.TagNames)),
+		Terms:     make([]index.TermCardinality, 0, len(res.Tags)),
+	}
+	for _, tagName := range res.TagNames {
+		results.Fields = append(results.Fields, index.FieldCardinality{
+			Field:     tagName.Name,
+			NumValues: tagName.NumValues,
+			NumSeries: tagName.NumSeries,
+		})
+	}
+	for _, tag := range res.Tags {
+		results.Terms = append(results.Terms, index.TermCardinality{
+			Field:     tag.Name,
+			Term:      tag.Value,
+			NumSeries: tag.NumSeries,
+		})
+	}
+	return results
+}
+
 // ToTagsIter returns a tag iterator over the given request.
 func ToTagsIter(r *rpc.WriteTaggedRequest) (ident.TagIterator, error) {
 	if r == nil {
@@ -165,6 +165,67 @@ func TestConvertFetchTaggedRequest(t *testing.T) {
 	}
 }
 
+func TestConvertFetchTaggedRequestValueFilter(t *testing.T) {
+	q, _ := termQueryTestCase(t)
+	opts := index.QueryOptions{
+		StartInclusive: time.Now().Add(-time.Hour),
+		EndExclusive:   time.Now(),
+		ValueFilter: &index.ValueFilter{
+			Type:   index.ValueFilterGreaterEqual,
+			Value:  0.5,
+			Latest: true,
+		},
+	}
+
+	req, err := convert.ToRPCFetchTaggedRequest(ident.StringID("abc"), index.Query{Query: q}, opts, true)
+	require.NoError(t, err)
+	require.True(t, req.IsSetValueFilter())
+	require.Equal(t, rpc.ValueFilterType_GREATER_EQUAL, req.ValueFilter.Type)
+	require.Equal(t, 0.5, req.ValueFilter.Value)
+	require.True(t, req.ValueFilter.GetLatest())
+
+	_, _, observedOpts, _, err := convert.FromRPCFetchTaggedRequest(&req, nil)
+	require.NoError(t, err)
+	require.Equal(t, opts.ValueFilter, observedOpts.ValueFilter)
+
+	req.ValueFilter.Type = rpc.ValueFilterType(100)
+	_, _, _, _, err = convert.FromRPCFetchTaggedRequest(&req, nil)
+	require.Error(t, err)
+}
+
+func TestConvertIndexCardinalityRequest(t *testing.T) {
+	q, _ := termQueryTestCase(t)
+	opts := index.CardinalityOptions{
+		StartInclusive: time.Now().Add(-time.Hour),
+		EndExclusive:   time.Now(),
+		Limit:          10,
+		Query:          &index.Query{Query: q},
+		Match: index.CardinalityMatch{
+			Partial:    []byte("ho"),
+			MaxEdits:   1,
+			FieldNames: true,
+		},
+	}
+
+	req, err := convert.ToRPCIndexCardinalityRequest(ident.StringID("abc"), opts)
+	require.NoError(t, err)
+	require.Equal(t, []byte("ho"), req.MatchPartial)
+	require.Equal(t, int32(1), req.GetMatchMaxEdits())
+	require.True(t, req.GetMatchTagNames())
+
+	ns, observedOpts, err := convert.FromRPCIndexCardinalityRequest(&req)
+	require.NoError(t, err)
+	require.Equal(t, "abc", ns.String())
+	require.True(t, index.NewQueryMatcher(*opts.Query).Matches(*observedOpts.Query))
+	require.Equal(t, opts.Match, observedOpts.Match)
+	require.Equal(t, opts.Limit, observedOpts.Limit)
+
+	maxEdits := int32(3)
+	req.MatchMaxEdits = &maxEdits
+	_, _, err = convert.FromRPCIndexCardinalityRequest(&req)
+	require.Error(t, err)
+}
+
 func TestConvertAggregateRawQueryRequest(t *testing.T) {
 	ns := ident.StringID("abc")
 	opts := index.AggregationOptions{
@@ -33,8 +33,10 @@ import (
 	"github.com/m3db/m3/src/dbnode/network/server/tchannelthrift"
 	"github.com/m3db/m3/src/dbnode/network/server/tchannelthrift/convert"
 	tterrors "github.com/m3db/m3/src/dbnode/network/server/tchannelthrift/errors"
+	"github.com/m3db/m3/src/dbnode/persist/fs/backup"
 	"github.com/m3db/m3/src/dbnode/storage"
 	"github.com/m3db/m3/src/dbnode/storage/block"
+	dberrors "github.com/m3db/m3/src/dbnode/storage/errors"
 	"github.com/m3db/m3/src/dbnode/storage/index"
 	"github.com/m3db/m3/src/dbnode/tracepoint"
 	"github.com/m3db/m3/src/dbnode/ts"
@@ -95,6 +97,9 @@ var (
 	// errNotImplemented raised when attempting to execute an un-implemented method
 	errNotImplemented = errors.New("method is not implemented")
 
+	// errBackupIDRequired is raised when a backup is requested without an ID.
+	errBackupIDRequired = errors.New("backup ID is required")
+
 	// errHealthNotSet is raised when server health data structure is not set.
 	errHealthNotSet = errors.New("server health not set")
 )
@@ -109,6 +114,9 @@ type serviceMetrics struct {
 	fetchBlocksMetadata     instrument.MethodMetrics
 	repair                  instrument.MethodMetrics
 	truncate                instrument.MethodMetrics
+	deleteTagged            instrument.MethodMetrics
+	backup                  instrument.MethodMetrics
+	indexCardinality        instrument.MethodMetrics
 	fetchBatchRawRPCS       tally.Counter
 	fetchBatchRaw           instrument.BatchMethodMetrics
 	writeBatchRawRPCs       tally.Counter
@@ -129,6 +137,9 @@ func newServiceMetrics(scope tally.Scope, samplingRate float64) serviceMetrics {
 		fetchBlocksMetadata:     instrument.NewMethodMetrics(scope, "fetchBlocksMetadata", samplingRate),
 		repair:                  instrument.NewMethodMetrics(scope, "repair", samplingRate),
 		truncate:                instrument.NewMethodMetrics(scope, "truncate", samplingRate),
+		deleteTagged:            instrument.NewMethodMetrics(scope, "deleteTagged", samplingRate),
+		backup:                  instrument.NewMethodMetrics(scope, "backup", samplingRate),
+		indexCardinality:        instrument.NewMethodMetrics(scope, "indexCardinality", samplingRate),
 		fetchBatchRawRPCS:       scope.Counter("fetchBatchRaw-rpcs"),
 		fetchBatchRaw:           instrument.NewBatchMethodMetrics(scope, "fetchBatchRaw", samplingRate),
 		writeBatchRawRPCs:       scope.Counter("writeBatchRaw-rpcs"),
@@ -626,8 +637,9 @@ func (s *service) fetchTagged(ctx context.Context, db storage.Database, req *rpc
 
 	results := queryResult.Results
 	response := &rpc.FetchTaggedResult_{
-		Exhaustive: queryResult.Exhaustive,
-		Elements:   make([]*rpc.FetchTaggedIDResult_, 0, results.Size()),
+		Exhaustive:    queryResult.Exhaustive,
+		Elements:      make([]*rpc.FetchTaggedIDResult_, 0, results.Size()),
+		NextPageToken: queryResult.NextPageToken,
 	}
 	nsID := results.Namespace()
 	nsIDBytes := nsID.Bytes()
@@ -639,9 +651,19 @@ func (s *service) fetchTagged(ctx context.Context, db storage.Database, req *rpc
 	if fetchData {
 		encodedDataResults = make([][][]xio.BlockReader, results.Size())
 	}
-	if err := s.fetchReadEncoded(ctx, db, response, results, nsID, nsIDBytes, callStart, opts, fetchData, encodedDataResults); err != nil {
+	// NB: when the client supports it reference tags against a dictionary
+	// sent once with the response, rather than repeating the tag names and
+	// values of every series.
+	var tagDict *serialize.TagDictionaryBuilder
+	if req.GetDictionaryEncodedTags() {
+		tagDict = serialize.NewTagDictionaryBuilder(serialize.NewTagEncoderOptions())
+	}
+	if err := s.fetchReadEncoded(ctx, db, response, results, nsID, nsIDBytes, callStart, opts, fetchData, tagDict, encodedDataResults); err != nil {
 		return nil, err
 	}
+	if tagDict != nil {
+		response.TagDictionary = tagDict.Dictionary()
+	}
 
 	// Step 2: If fetching data read the results of the asynchronuous block readers.
 	if fetchData {
@@ -661,6 +683,7 @@ func (s *service) fetchReadEncoded(ctx context.Context,
 	callStart time.Time,
 	opts index.QueryOptions,
 	fetchData bool,
+	tagDict *serialize.TagDictionaryBuilder,
 	encodedDataResults [][][]xio.BlockReader,
 ) error {
 	ctx, sp, sampled := ctx.StartSampledTraceSpan(tracepoint.FetchReadEncoded)
@@ -679,9 +702,7 @@ func (s *service) fetchReadEncoded(ctx context.Context,
 
 		tsID := entry.Key()
 		tags := entry.Value()
-		enc := s.pools.tagEncoder.Get()
-		ctx.RegisterFinalizer(enc)
-		encodedTags, err := s.encodeTags(enc, tags)
+		encodedTags, err := s.encodeFetchTaggedTags(ctx, tagDict, tags)
 		if err != nil { // This is an invariant, should never happen
 			s.metrics.fetchTagged.ReportError(s.nowFn().Sub(callStart))
 			return tterrors.NewInternalError(err)
@@ -690,7 +711,7 @@ func (s *service) fetchReadEncoded(ctx context.Context,
 		elem := &rpc.FetchTaggedIDResult_{
 			NameSpace:   nsIDBytes,
 			ID:          tsID.Bytes(),
-			EncodedTags: encodedTags.Bytes(),
+			EncodedTags: encodedTags,
 		}
 		response.Elements = append(response.Elements, elem)
 		if !fetchData {
@@ -804,7 +825,8 @@ func (s *service) AggregateRaw(tctx thrift.Context, req *rpc.AggregateQueryRawRe
 	}
 
 	response := &rpc.AggregateQueryRawResult_{
-		Exhaustive: queryResult.Exhaustive,
+		Exhaustive:    queryResult.Exhaustive,
+		NextPageToken: queryResult.NextPageToken,
 	}
 	results := queryResult.Results
 	for _, entry := range results.Map().Iter() {
@@ -849,6 +871,33 @@ func (s *service) encodeTags(
 	return encodedTags, nil
 }
 
+func (s *service) encodeFetchTaggedTags(
+	ctx context.Context,
+	tagDict *serialize.TagDictionaryBuilder,
+	tags ident.TagIterator,
+) ([]byte, error) {
+	if tagDict == nil {
+		enc := s.pools.tagEncoder.Get()
+		ctx.RegisterFinalizer(enc)
+		encodedTags, err := s.encodeTags(enc, tags)
+		if err != nil {
+			return nil, err
+		}
+		return encodedTags.Bytes(), nil
+	}
+
+	encodedTags, err := tagDict.Encode(tags)
+	if err != nil {
+		// should never happen
+		err = xerrors.NewRenamedError(err, fmt.Errorf("unable to encode tags against dictionary"))
+		instrument.EmitAndLogInvariantViolation(s.opts.InstrumentOptions(), func(l *zap.Logger) {
+			l.Error(err.Error())
+		})
+		return nil, err
+	}
+	return encodedTags, nil
+}
+
 func (s *service) FetchBatchRaw(tctx thrift.Context, req *rpc.FetchBatchRawRequest) (*rpc.FetchBatchRawResult_, error) {
 	s.metrics.fetchBatchRawRPCS.Inc(1)
 	db, err := s.startReadRPCWithDB()
@@ -1777,6 +1826,102 @@ func (s *service) Truncate(tctx thrift.Context, req *rpc.TruncateRequest) (r *rp
 	return res, nil
 }
 
+func (s *service) DeleteTagged(tctx thrift.Context, req *rpc.DeleteTaggedRequest) (*rpc.DeleteTaggedResult_, error) {
+	db, err := s.startRPCWithDB()
+	if err != nil {
+		return nil, err
+	}
+
+	callStart := s.nowFn()
+	ctx := tchannelthrift.Context(tctx)
+
+	ns, query, opts, err := convert.FromRPCDeleteTaggedRequest(req)
+	if err != nil {
+		s.metrics.deleteTagged.ReportError(s.nowFn().Sub(callStart))
+		return nil, tterrors.NewBadRequestError(err)
+	}
+
+	deleted, err := db.DeleteTagged(ctx, ns, query, opts)
+	if err != nil {
+		s.metrics.deleteTagged.ReportError(s.nowFn().Sub(callStart))
+		return nil, convert.ToRPCError(err)
+	}
+
+	res := rpc.NewDeleteTaggedResult_()
+	res.NumSeries = deleted
+
+	s.metrics.deleteTagged.ReportSuccess(s.nowFn().Sub(callStart))
+
+	return res, nil
+}
+
+func (s *service) Backup(tctx thrift.Context, req *rpc.BackupRequest) (*rpc.BackupResult_, error) {
+	db, err := s.startRPCWithDB()
+	if err != nil {
+		return nil, err
+	}
+
+	callStart := s.nowFn()
+	ctx := tchannelthrift.Context(tctx)
+
+	if req.BackupId == "" {
+		s.metrics.backup.ReportError(s.nowFn().Sub(callStart))
+		return nil, tterrors.NewBadRequestError(errBackupIDRequired)
+	}
+
+	// NB: backups are written by the node so the destination is restricted
+	// to the configured roots, rather than any path the node can write to.
+	store, err := backup.NewBlobStoreWithinRoots(req.Destination,
+		s.opts.BackupAllowedDestinations())
+	if err != nil {
+		s.metrics.backup.ReportError(s.nowFn().Sub(callStart))
+		return nil, tterrors.NewBadRequestError(err)
+	}
+
+	manifest, err := db.Backup(s.newID(ctx, req.NameSpace), req.BackupId, store)
+	if err != nil {
+		s.metrics.backup.ReportError(s.nowFn().Sub(callStart))
+		return nil, convert.ToRPCError(err)
+	}
+
+	res := rpc.NewBackupResult_()
+	res.NumFiles = int64(len(manifest.Files))
+	for _, file := range manifest.Files {
+		res.NumBytes += file.Size
+	}
+
+	s.metrics.backup.ReportSuccess(s.nowFn().Sub(callStart))
+
+	return res, nil
+}
+
+func (s *service) IndexCardinality(tctx thrift.Context, req *rpc.IndexCardinalityRequest) (*rpc.IndexCardinalityResult_, error) {
+	db, err := s.startReadRPCWithDB()
+	if err != nil {
+		return nil, err
+	}
+	defer s.readRPCCompleted()
+
+	callStart := s.nowFn()
+	ctx := tchannelthrift.Context(tctx)
+
+	ns, opts, err := convert.FromRPCIndexCardinalityRequest(req)
+	if err != nil {
+		s.metrics.indexCardinality.ReportError(s.nowFn().Sub(callStart))
+		return nil, tterrors.NewBadRequestError(err)
+	}
+
+	results, err := db.IndexCardinality(ctx, ns, opts)
+	if err != nil {
+		s.metrics.indexCardinality.ReportError(s.nowFn().Sub(callStart))
+		return nil, convert.ToRPCError(err)
+	}
+
+	s.metrics.indexCardinality.ReportSuccess(s.nowFn().Sub(callStart))
+
+	return convert.ToRPCIndexCardinalityResult(results), nil
+}
+
 func (s *service) GetPersistRateLimit(
 	ctx thrift.Context,
 ) (*rpc.NodePersistRateLimitResult_, error) {
@@ -2288,6 +2433,14 @@ func (r *writeBatchPooledReq) HandleError(index int, err error) {
 		return
 	}
 
+	if dberrors.IsQuotaExceededError(err) {
+		r.retryableErrors++
+		r.errs = append(
+			r.errs,
+			tterrors.NewResourceExhaustedWriteBatchRawError(index, err))
+		return
+	}
+
 	r.retryableErrors++
 	r.errs = append(
 		r.errs,
@@ -35,6 +35,7 @@ import (
 	"github.com/m3db/m3/src/dbnode/network/server/tchannelthrift"
 	"github.com/m3db/m3/src/dbnode/network/server/tchannelthrift/convert"
 	tterrors "github.com/m3db/m3/src/dbnode/network/server/tchannelthrift/errors"
+	"github.com/m3db/m3/src/dbnode/persist/fs/backup"
 	"github.com/m3db/m3/src/dbnode/runtime"
 	"github.com/m3db/m3/src/dbnode/storage"
 	"github.com/m3db/m3/src/dbnode/storage/block"
@@ -1790,6 +1791,93 @@ func TestServiceFetchTaggedNoData(t *testing.T) {
 	}
 }
 
+func TestServiceFetchTaggedDictionaryEncodedTags(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := storage.NewMockDatabase(ctrl)
+	mockDB.EXPECT().Options().Return(testStorageOpts).AnyTimes()
+	mockDB.EXPECT().IsOverloaded().Return(false)
+
+	service := NewService(mockDB, testTChannelThriftOptions).(*service)
+
+	tctx, _ := tchannelthrift.NewContext(time.Minute)
+	ctx := tchannelthrift.Context(tctx)
+	defer ctx.Close()
+
+	start := time.Now().Add(-2 * time.Hour)
+	end := start.Add(2 * time.Hour)
+
+	start, end = start.Truncate(time.Second), end.Truncate(time.Second)
+	nsID := "metrics"
+
+	req, err := idx.NewRegexpQuery([]byte("city"), []byte("n.*"))
+	require.NoError(t, err)
+	qry := index.Query{Query: req}
+
+	resMap := index.NewQueryResults(ident.StringID(nsID),
+		index.QueryResultsOptions{}, testIndexOptions)
+	resMap.Map().Set(ident.StringID("foo"), ident.NewTagsIterator(ident.NewTags(
+		ident.StringTag("city", "nyc"),
+		ident.StringTag("host", "a"),
+	)))
+	resMap.Map().Set(ident.StringID("bar"), ident.NewTagsIterator(ident.NewTags(
+		ident.StringTag("city", "nyc"),
+		ident.StringTag("host", "b"),
+	)))
+	mockDB.EXPECT().QueryIDs(
+		ctx,
+		ident.NewIDMatcher(nsID),
+		index.NewQueryMatcher(qry),
+		index.QueryOptions{
+			StartInclusive: start,
+			EndExclusive:   end,
+		}).Return(index.QueryResult{Results: resMap, Exhaustive: true}, nil)
+
+	startNanos, err := convert.ToValue(start, rpc.TimeType_UNIX_NANOSECONDS)
+	require.NoError(t, err)
+	endNanos, err := convert.ToValue(end, rpc.TimeType_UNIX_NANOSECONDS)
+	require.NoError(t, err)
+	data, err := idx.Marshal(req)
+	require.NoError(t, err)
+	dictionaryEncodedTags := true
+	r, err := service.FetchTagged(tctx, &rpc.FetchTaggedRequest{
+		NameSpace:             []byte(nsID),
+		Query:                 data,
+		RangeStart:            startNanos,
+		RangeEnd:              endNanos,
+		FetchData:             false,
+		DictionaryEncodedTags: &dictionaryEncodedTags,
+	})
+	require.NoError(t, err)
+
+	decodeOpts := serialize.NewTagDecoderOptions()
+	dict, _, err := serialize.DecodeTagDictionary(r.TagDictionary,
+		decodeOpts.TagSerializationLimits())
+	require.NoError(t, err)
+	// city, nyc, host, a, b are each held once.
+	require.Equal(t, 5, dict.Len())
+
+	// sort to order results to make test deterministic.
+	sort.Slice(r.Elements, func(i, j int) bool {
+		return bytes.Compare(r.Elements[i].ID, r.Elements[j].ID) < 0
+	})
+	expected := map[string]ident.Tags{
+		"bar": ident.NewTags(ident.StringTag("city", "nyc"), ident.StringTag("host", "b")),
+		"foo": ident.NewTags(ident.StringTag("city", "nyc"), ident.StringTag("host", "a")),
+	}
+	require.Equal(t, len(expected), len(r.Elements))
+	for _, elem := range r.Elements {
+		require.True(t, serialize.IsDictionaryEncodedTags(elem.EncodedTags))
+
+		dec := serialize.NewTagDictionaryDecoder(dict, decodeOpts)
+		dec.Reset(checked.NewBytes(elem.EncodedTags, nil))
+		require.True(t, ident.NewTagIterMatcher(
+			ident.NewTagsIterator(expected[string(elem.ID)])).Matches(dec))
+		dec.Close()
+	}
+}
+
 func TestServiceFetchTaggedErrs(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -2771,6 +2859,172 @@ func TestServiceTruncate(t *testing.T) {
 	assert.Equal(t, truncated, r.NumSeries)
 }
 
+func TestServiceDeleteTagged(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := storage.NewMockDatabase(ctrl)
+	mockDB.EXPECT().Options().Return(testStorageOpts).AnyTimes()
+	mockDB.EXPECT().IsOverloaded().Return(false)
+
+	service := NewService(mockDB, testTChannelThriftOptions).(*service)
+
+	tctx, _ := tchannelthrift.NewContext(time.Minute)
+	ctx := tchannelthrift.Context(tctx)
+	defer ctx.Close()
+
+	var (
+		nsID    = "metrics"
+		start   = time.Now().Add(-2 * time.Hour).Truncate(time.Second)
+		end     = start.Add(2 * time.Hour)
+		deleted = int64(42)
+	)
+
+	req, err := idx.NewRegexpQuery([]byte("foo"), []byte("b.*"))
+	require.NoError(t, err)
+	qry := index.Query{Query: req}
+
+	mockDB.EXPECT().DeleteTagged(
+		gomock.Any(),
+		ident.NewIDMatcher(nsID),
+		index.NewQueryMatcher(qry),
+		index.QueryOptions{
+			StartInclusive: start,
+			EndExclusive:   end,
+		}).Return(deleted, nil)
+
+	data, err := idx.Marshal(req)
+	require.NoError(t, err)
+	r, err := service.DeleteTagged(tctx, &rpc.DeleteTaggedRequest{
+		NameSpace:     []byte(nsID),
+		Query:         data,
+		RangeStart:    start.Unix(),
+		RangeEnd:      end.Unix(),
+		RangeTimeType: rpc.TimeType_UNIX_SECONDS,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, deleted, r.NumSeries)
+}
+
+func TestServiceIndexCardinality(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := storage.NewMockDatabase(ctrl)
+	mockDB.EXPECT().Options().Return(testStorageOpts).AnyTimes()
+	mockDB.EXPECT().IsOverloaded().Return(false)
+
+	service := NewService(mockDB, testTChannelThriftOptions).(*service)
+
+	tctx, _ := tchannelthrift.NewContext(time.Minute)
+	ctx := tchannelthrift.Context(tctx)
+	defer ctx.Close()
+
+	var (
+		nsID  = "metrics"
+		start = time.Now().Add(-2 * time.Hour).Truncate(time.Second)
+		end   = start.Add(2 * time.Hour)
+		limit = int64(10)
+	)
+
+	mockDB.EXPECT().IndexCardinality(
+		gomock.Any(),
+		ident.NewIDMatcher(nsID),
+		index.CardinalityOptions{
+			StartInclusive: start,
+			EndExclusive:   end,
+			FieldFilter:    index.AggregateFieldFilter{[]byte("city")},
+			Limit:          int(limit),
+		}).Return(index.CardinalityResults{
+		NumSeries: 3,
+		Fields: []index.FieldCardinality{
+			{Field: []byte("city"), NumValues: 2, NumSeries: 3},
+		},
+		Terms: []index.TermCardinality{
+			{Field: []byte("city"), Term: []byte("nyc"), NumSeries: 2},
+			{Field: []byte("city"), Term: []byte("sf"), NumSeries: 1},
+		},
+	}, nil)
+
+	r, err := service.IndexCardinality(tctx, &rpc.IndexCardinalityRequest{
+		NameSpace:     []byte(nsID),
+		RangeStart:    start.Unix(),
+		RangeEnd:      end.Unix(),
+		TagNameFilter: [][]byte{[]byte("city")},
+		Limit:         &limit,
+		RangeTimeType: rpc.TimeType_UNIX_SECONDS,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &rpc.IndexCardinalityResult_{
+		NumSeries: 3,
+		TagNames: []*rpc.IndexCardinalityTagName{
+			{Name: []byte("city"), NumValues: 2, NumSeries: 3},
+		},
+		Tags: []*rpc.IndexCardinalityTag{
+			{Name: []byte("city"), Value: []byte("nyc"), NumSeries: 2},
+			{Name: []byte("city"), Value: []byte("sf"), NumSeries: 1},
+		},
+	}, r)
+}
+
+func TestServiceBackup(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := storage.NewMockDatabase(ctrl)
+	mockDB.EXPECT().Options().Return(testStorageOpts).AnyTimes()
+	mockDB.EXPECT().IsOverloaded().Return(false).AnyTimes()
+
+	opts := testTChannelThriftOptions.
+		SetBackupAllowedDestinations([]string{"/var/backups"})
+	service := NewService(mockDB, opts).(*service)
+
+	tctx, _ := tchannelthrift.NewContext(time.Minute)
+	ctx := tchannelthrift.Context(tctx)
+	defer ctx.Close()
+
+	nsID := "metrics"
+	mockDB.EXPECT().Backup(ident.NewIDMatcher(nsID), "backup-1", gomock.Any()).
+		Return(backup.Manifest{
+			Files: []backup.ManifestFile{{Size: 10}, {Size: 32}},
+		}, nil)
+
+	r, err := service.Backup(tctx, &rpc.BackupRequest{
+		NameSpace:   []byte(nsID),
+		BackupId:    "backup-1",
+		Destination: "file:///var/backups",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), r.NumFiles)
+	assert.Equal(t, int64(42), r.NumBytes)
+
+	_, err = service.Backup(tctx, &rpc.BackupRequest{
+		NameSpace:   []byte(nsID),
+		BackupId:    "backup-2",
+		Destination: "s3://bucket/backups",
+	})
+	require.Error(t, err)
+	rpcErr, ok := err.(*rpc.Error)
+	require.True(t, ok)
+	require.True(t, tterrors.IsBadRequestError(rpcErr))
+
+	// Destinations outside of the allowed roots are rejected.
+	for _, destination := range []string{
+		"/etc",
+		"file:///var/backups/../../etc",
+	} {
+		_, err = service.Backup(tctx, &rpc.BackupRequest{
+			NameSpace:   []byte(nsID),
+			BackupId:    "backup-3",
+			Destination: destination,
+		})
+		require.Error(t, err)
+		rpcErr, ok = err.(*rpc.Error)
+		require.True(t, ok)
+		require.True(t, tterrors.IsBadRequestError(rpcErr))
+	}
+}
+
 func TestServiceSetPersistRateLimit(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
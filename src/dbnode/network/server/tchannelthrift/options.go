@@ -42,6 +42,7 @@ type options struct {
 	checkedBytesWrapperPool     xpool.CheckedBytesWrapperPool
 	maxOutstandingWriteRequests int
 	maxOutstandingReadRequests  int
+	backupAllowedDestinations   []string
 }
 
 // NewOptions creates new options
@@ -193,3 +194,13 @@ func (o *options) SetMaxOutstandingReadRequests(value int) Options {
 func (o *options) MaxOutstandingReadRequests() int {
 	return o.maxOutstandingReadRequests
 }
+
+func (o *options) SetBackupAllowedDestinations(value []string) Options {
+	opts := *o
+	opts.backupAllowedDestinations = value
+	return &opts
+}
+
+func (o *options) BackupAllowedDestinations() []string {
+	return o.backupAllowedDestinations
+}
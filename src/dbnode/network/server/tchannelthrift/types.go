@@ -100,4 +100,12 @@ type Options interface {
 	// MaxOutstandingReadRequests returns the maxinum number of allowed
 	// outstanding read requests.
 	MaxOutstandingReadRequests() int
+
+	// SetBackupAllowedDestinations sets the root directories that backups
+	// may be written beneath.
+	SetBackupAllowedDestinations(value []string) Options
+
+	// BackupAllowedDestinations returns the root directories that backups
+	// may be written beneath.
+	BackupAllowedDestinations() []string
 }
@@ -0,0 +1,487 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package backup
+
+import (
+	"archive/tar"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/adler32"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+
+	"github.com/pborman/uuid"
+)
+
+const (
+	archiveKeyName  = "archive.tar"
+	manifestKeyName = "manifest.json"
+	stagingDirName  = "backup"
+
+	// checkpointFileSuffix matches the suffix of fileset checkpoint files,
+	// which are archived after the rest of their fileset so that a restore
+	// only ever produces complete filesets.
+	checkpointFileSuffix = "-checkpoint.db"
+
+	// maxStageAttempts is the number of times the filesets are collected and
+	// staged again when files are removed, e.g. by a cleanup, while staging.
+	maxStageAttempts = 3
+)
+
+var (
+	errEmptyBackupID      = errors.New("backup ID must not be empty")
+	errInvalidBackupID    = errors.New("backup ID must not contain path separators")
+	errEmptyStagingDir    = errors.New("backup staging directory must not be empty")
+	errFilesRemovedStaged = errors.New("filesets were repeatedly removed while staging backup")
+)
+
+// StagingDirPath returns the directory beneath the file path prefix that the
+// files of a backup are staged in while the backup is taken.
+func StagingDirPath(filePathPrefix string, backupID string) string {
+	return filepath.Join(filePathPrefix, stagingDirName, backupID)
+}
+
+// ArchiveKey returns the blob store key of the archive of a backup.
+func ArchiveKey(backupID string) string {
+	return path.Join(backupID, archiveKeyName)
+}
+
+// ManifestKey returns the blob store key of the manifest of a backup.
+func ManifestKey(backupID string) string {
+	return path.Join(backupID, manifestKeyName)
+}
+
+type archiveFile struct {
+	absolutePath string
+	relativePath string
+	// prefix is the index of the file path prefix the file is beneath.
+	prefix int
+}
+
+type archiveResult struct {
+	files []ManifestFile
+	err   error
+}
+
+// Backup archives the flushed data filesets, index filesets and a snapshot
+// of the source namespace to the blob store under the given backup ID, it
+// stages the files and then archives them.
+func Backup(
+	store BlobStore,
+	backupID string,
+	src Source,
+	createdAt time.Time,
+) (Manifest, error) {
+	staged, err := Stage(backupID, src)
+	if err != nil {
+		return Manifest{}, err
+	}
+	return staged.Archive(store, createdAt)
+}
+
+// Staged is a backup whose files have been linked into the staging directory
+// of its source, it must be archived or closed to remove the staged files.
+type Staged struct {
+	backupID   string
+	src        Source
+	files      []archiveFile
+	snapshotID string
+}
+
+// Stage collects the flushed data filesets, index filesets and snapshot of
+// the source namespace and links them into the staging directory of the
+// source. Only filesets with a complete checkpoint are staged, once staged
+// file operations on the source can resume while the backup is archived.
+func Stage(backupID string, src Source) (*Staged, error) {
+	if backupID == "" {
+		return nil, errEmptyBackupID
+	}
+	if strings.ContainsAny(backupID, `/\`) || backupID == "." || backupID == ".." {
+		return nil, errInvalidBackupID
+	}
+	if src.StagingDir == "" {
+		return nil, errEmptyStagingDir
+	}
+
+	files, snapshotID, err := stage(src)
+	if err != nil {
+		os.RemoveAll(src.StagingDir)
+		return nil, fmt.Errorf("unable to stage backup: %v", err)
+	}
+	return &Staged{
+		backupID:   backupID,
+		src:        src,
+		files:      files,
+		snapshotID: snapshotID,
+	}, nil
+}
+
+// Archive writes the staged files to the blob store followed by the manifest
+// of the backup, it removes the staged files once done.
+func (s *Staged) Archive(store BlobStore, createdAt time.Time) (Manifest, error) {
+	defer s.Close()
+
+	// Stream the archive straight into the blob store rather than staging
+	// it on local disk.
+	var (
+		pr, pw   = io.Pipe()
+		resultCh = make(chan archiveResult, 1)
+	)
+	go func() {
+		manifestFiles, err := writeArchive(pw, s.files)
+		pw.CloseWithError(err)
+		resultCh <- archiveResult{files: manifestFiles, err: err}
+	}()
+
+	putErr := store.Put(ArchiveKey(s.backupID), pr)
+	// Unblock the archive writer in case the blob store returned before
+	// consuming the whole archive.
+	pr.Close()
+	result := <-resultCh
+	if result.err != nil {
+		return Manifest{}, fmt.Errorf("unable to write backup archive: %v", result.err)
+	}
+	if putErr != nil {
+		return Manifest{}, fmt.Errorf("unable to store backup archive: %v", putErr)
+	}
+
+	manifest := Manifest{
+		BackupID:   s.backupID,
+		Namespace:  s.src.Namespace.String(),
+		Shards:     s.src.Shards,
+		SnapshotID: s.snapshotID,
+		CreatedAt:  createdAt,
+		Files:      result.files,
+	}
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		return Manifest{}, err
+	}
+	// The manifest is written last, its presence marks a complete backup.
+	if err := store.Put(ManifestKey(s.backupID), strings.NewReader(string(data))); err != nil {
+		return Manifest{}, fmt.Errorf("unable to store backup manifest: %v", err)
+	}
+	return manifest, nil
+}
+
+// Close removes the staged files.
+func (s *Staged) Close() error {
+	return os.RemoveAll(s.src.StagingDir)
+}
+
+type collector struct {
+	prefixes []string
+	files    []archiveFile
+}
+
+func (c *collector) addFile(filePath string) error {
+	for i, prefix := range c.prefixes {
+		rel, err := filepath.Rel(prefix, filePath)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			continue
+		}
+		c.files = append(c.files, archiveFile{
+			absolutePath: filePath,
+			relativePath: filepath.ToSlash(rel),
+			prefix:       i,
+		})
+		return nil
+	}
+	return fmt.Errorf("file %s is not beneath a file path prefix", filePath)
+}
+
+func (c *collector) addFileSet(fileset fs.FileSetFile) error {
+	var checkpoints []string
+	for _, filePath := range fileset.AbsoluteFilepaths {
+		if strings.HasSuffix(filePath, checkpointFileSuffix) {
+			checkpoints = append(checkpoints, filePath)
+			continue
+		}
+		if err := c.addFile(filePath); err != nil {
+			return err
+		}
+	}
+	for _, filePath := range checkpoints {
+		if err := c.addFile(filePath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// stage collects the files to back up and links them into the staging
+// directory so that they remain readable if they are removed from the file
+// path prefix, it collects the files again if any are removed meanwhile.
+func stage(src Source) ([]archiveFile, string, error) {
+	for attempt := 0; attempt < maxStageAttempts; attempt++ {
+		if err := os.RemoveAll(src.StagingDir); err != nil {
+			return nil, "", err
+		}
+
+		files, snapshotID, err := collect(src)
+		if err != nil {
+			return nil, "", err
+		}
+
+		staged, err := linkFiles(files, src.StagingDir)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, "", err
+		}
+		return staged, snapshotID, nil
+	}
+	return nil, "", errFilesRemovedStaged
+}
+
+func linkFiles(files []archiveFile, stagingDir string) ([]archiveFile, error) {
+	staged := make([]archiveFile, 0, len(files))
+	for _, file := range files {
+		stagedPath := filepath.Join(stagingDir, strconv.Itoa(file.prefix),
+			filepath.FromSlash(file.relativePath))
+		if err := os.MkdirAll(filepath.Dir(stagedPath), 0755); err != nil {
+			return nil, err
+		}
+		if err := linkFile(file.absolutePath, stagedPath); err != nil {
+			return nil, err
+		}
+		file.absolutePath = stagedPath
+		staged = append(staged, file)
+	}
+	return staged, nil
+}
+
+func linkFile(src, dst string) error {
+	err := os.Link(src, dst)
+	linkErr, ok := err.(*os.LinkError)
+	if !ok || linkErr.Err != syscall.EXDEV {
+		return err
+	}
+
+	// Files on another device, such as the cold tier, can not be linked
+	// and are copied instead.
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+func collect(src Source) ([]archiveFile, string, error) {
+	c := &collector{prefixes: []string{src.FilePathPrefix}}
+	if src.ColdTierFilePathPrefix != "" {
+		c.prefixes = append(c.prefixes, src.ColdTierFilePathPrefix)
+	}
+
+	for _, shard := range src.Shards {
+		primary, err := fs.DataFiles(src.FilePathPrefix, src.Namespace, shard)
+		if err != nil {
+			return nil, "", err
+		}
+		var cold fs.FileSetFilesSlice
+		if src.ColdTierFilePathPrefix != "" {
+			cold, err = fs.DataFiles(src.ColdTierFilePathPrefix, src.Namespace, shard)
+			if err != nil {
+				return nil, "", err
+			}
+		}
+
+		// Only the latest complete volume of each block is required to
+		// bootstrap, superseded volumes are left for cleanup.
+		filesets := fs.MergeTieredDataFileSets(primary, cold)
+		for i, fileset := range filesets {
+			if i+1 < len(filesets) &&
+				filesets[i+1].ID.BlockStart.Equal(fileset.ID.BlockStart) {
+				continue
+			}
+			latest, ok := filesets.LatestVolumeForBlock(fileset.ID.BlockStart)
+			if !ok {
+				continue
+			}
+			if err := c.addFileSet(latest); err != nil {
+				return nil, "", err
+			}
+		}
+
+		tombstonesFilePath := fs.TombstonesFilePath(src.FilePathPrefix, src.Namespace, shard)
+		exists, err := fs.FileExists(tombstonesFilePath)
+		if err != nil {
+			return nil, "", err
+		}
+		if exists {
+			if err := c.addFile(tombstonesFilePath); err != nil {
+				return nil, "", err
+			}
+		}
+	}
+
+	indexFileSets, err := fs.IndexFiles(src.FilePathPrefix, src.Namespace)
+	if err != nil {
+		return nil, "", err
+	}
+	for _, fileset := range indexFileSets {
+		if !fileset.HasCompleteCheckpointFile() {
+			continue
+		}
+		if err := c.addFileSet(fileset); err != nil {
+			return nil, "", err
+		}
+	}
+
+	snapshotID, err := c.addSnapshot(src)
+	if err != nil {
+		return nil, "", err
+	}
+	return c.files, snapshotID, nil
+}
+
+// addSnapshot adds the snapshot of the source, or the latest snapshot if the
+// source does not specify one.
+func (c *collector) addSnapshot(src Source) (string, error) {
+	metadatas, _, err := fs.SortedSnapshotMetadataFiles(
+		src.FsOptions.SetFilePathPrefix(src.FilePathPrefix))
+	if err != nil {
+		return "", err
+	}
+
+	var (
+		snapshotMetadata fs.SnapshotMetadata
+		found            bool
+	)
+	if src.SnapshotID == "" {
+		if len(metadatas) == 0 {
+			return "", nil
+		}
+		snapshotMetadata, found = metadatas[len(metadatas)-1], true
+	} else {
+		snapshotID := uuid.Parse(src.SnapshotID)
+		for _, metadata := range metadatas {
+			if uuid.Equal(metadata.ID.UUID, snapshotID) {
+				snapshotMetadata, found = metadata, true
+			}
+		}
+	}
+	if !found {
+		return "", fmt.Errorf("snapshot %s not found", src.SnapshotID)
+	}
+
+	for _, shard := range src.Shards {
+		snapshots, err := fs.SnapshotFiles(src.FilePathPrefix, src.Namespace, shard)
+		if err != nil {
+			return "", err
+		}
+		for _, snapshot := range snapshots {
+			if !snapshot.HasCompleteCheckpointFile() {
+				continue
+			}
+			_, snapshotID, err := snapshot.SnapshotTimeAndID()
+			if err != nil {
+				return "", err
+			}
+			if !uuid.Equal(snapshotID, snapshotMetadata.ID.UUID) {
+				continue
+			}
+			if err := c.addFileSet(snapshot); err != nil {
+				return "", err
+			}
+		}
+	}
+
+	// The snapshot metadata is added after the snapshot filesets, with its
+	// checkpoint file last.
+	for _, filePath := range snapshotMetadata.AbsoluteFilepaths() {
+		if err := c.addFile(filePath); err != nil {
+			return "", err
+		}
+	}
+	return snapshotMetadata.ID.UUID.String(), nil
+}
+
+func writeArchive(w io.Writer, files []archiveFile) ([]ManifestFile, error) {
+	var (
+		tw            = tar.NewWriter(w)
+		manifestFiles = make([]ManifestFile, 0, len(files))
+	)
+	for _, file := range files {
+		manifestFile, err := writeArchiveFile(tw, file)
+		if err != nil {
+			return nil, err
+		}
+		manifestFiles = append(manifestFiles, manifestFile)
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	return manifestFiles, nil
+}
+
+func writeArchiveFile(tw *tar.Writer, file archiveFile) (ManifestFile, error) {
+	fd, err := os.Open(file.absolutePath)
+	if err != nil {
+		return ManifestFile{}, err
+	}
+	defer fd.Close()
+
+	info, err := fd.Stat()
+	if err != nil {
+		return ManifestFile{}, err
+	}
+
+	header := &tar.Header{
+		Name:    file.relativePath,
+		Mode:    int64(info.Mode().Perm()),
+		Size:    info.Size(),
+		ModTime: info.ModTime(),
+	}
+	if err := tw.WriteHeader(header); err != nil {
+		return ManifestFile{}, err
+	}
+
+	checksum := adler32.New()
+	if _, err := io.Copy(io.MultiWriter(tw, checksum), fd); err != nil {
+		return ManifestFile{}, err
+	}
+	return ManifestFile{
+		Path:     file.relativePath,
+		Size:     info.Size(),
+		Checksum: checksum.Sum32(),
+	}, nil
+}
@@ -0,0 +1,181 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package backup
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/x/checked"
+	"github.com/m3db/m3/src/x/ident"
+
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testBlockSize = 2 * time.Hour
+)
+
+var (
+	testNamespace = ident.StringID("testns")
+	testStart     = time.Unix(0, 0).Add(10 * testBlockSize)
+)
+
+func writeTestFileSet(
+	t *testing.T,
+	filePathPrefix string,
+	shard uint32,
+	blockStart time.Time,
+	volume int,
+	numSeries int,
+) {
+	w, err := fs.NewWriter(fs.NewOptions().SetFilePathPrefix(filePathPrefix))
+	require.NoError(t, err)
+	require.NoError(t, w.Open(fs.DataWriterOpenOptions{
+		BlockSize: testBlockSize,
+		Identifier: fs.FileSetFileIdentifier{
+			Namespace:   testNamespace,
+			Shard:       shard,
+			BlockStart:  blockStart,
+			VolumeIndex: volume,
+		},
+	}))
+	data := checked.NewBytes([]byte("somedata"), nil)
+	data.IncRef()
+	defer data.DecRef()
+	for i := 0; i < numSeries; i++ {
+		id := ident.StringID(fmt.Sprintf("series.%d", i))
+		require.NoError(t, w.Write(id, ident.Tags{}, data, 1234))
+	}
+	require.NoError(t, w.Close())
+}
+
+func TestBackupAndRestore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "backup")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	var (
+		filePathPrefix         = filepath.Join(dir, "data")
+		coldTierFilePathPrefix = filepath.Join(dir, "cold")
+		restoreFilePathPrefix  = filepath.Join(dir, "restore")
+		store                  = NewFileSystemBlobStore(filepath.Join(dir, "store"))
+		createdAt              = time.Unix(1000, 0).UTC()
+	)
+
+	// Shard 0 has a superseded volume that should not be backed up and
+	// shard 1 has a block in the cold tier.
+	writeTestFileSet(t, filePathPrefix, 0, testStart, 0, 1)
+	writeTestFileSet(t, filePathPrefix, 0, testStart, 1, 2)
+	writeTestFileSet(t, coldTierFilePathPrefix, 1, testStart.Add(-testBlockSize), 0, 3)
+
+	src := Source{
+		FilePathPrefix:         filePathPrefix,
+		ColdTierFilePathPrefix: coldTierFilePathPrefix,
+		Namespace:              testNamespace,
+		Shards:                 []uint32{0, 1},
+		FsOptions:              fs.NewOptions(),
+		StagingDir:             filepath.Join(dir, "staging"),
+	}
+	manifest, err := Backup(store, "backup-1", src, createdAt)
+	require.NoError(t, err)
+
+	// The staged links are removed once the backup completes.
+	_, err = os.Stat(src.StagingDir)
+	require.True(t, os.IsNotExist(err))
+	require.Equal(t, testNamespace.String(), manifest.Namespace)
+	require.Equal(t, createdAt, manifest.CreatedAt)
+	require.Equal(t, "", manifest.SnapshotID)
+
+	// Checkpoint files are archived after the rest of their fileset.
+	require.True(t, len(manifest.Files) > 0)
+	last := manifest.Files[len(manifest.Files)-1]
+	require.True(t, strings.HasSuffix(last.Path, checkpointFileSuffix))
+
+	read, err := ReadManifest(store, "backup-1")
+	require.NoError(t, err)
+	require.Equal(t, manifest, read)
+
+	restored, err := Restore(store, "backup-1",
+		fs.NewOptions().SetFilePathPrefix(restoreFilePathPrefix))
+	require.NoError(t, err)
+	require.Equal(t, manifest, restored)
+
+	filesets, err := fs.DataFiles(restoreFilePathPrefix, testNamespace, 0)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(filesets))
+	require.Equal(t, 1, filesets[0].ID.VolumeIndex)
+	require.True(t, filesets[0].HasCompleteCheckpointFile())
+
+	results := fs.ReadInfoFiles(restoreFilePathPrefix, testNamespace, 1, 16, nil)
+	require.Equal(t, 1, len(results))
+	require.NoError(t, results[0].Err.Error())
+	require.Equal(t, int64(3), results[0].Info.Entries)
+
+	// Restoring over existing files is refused.
+	_, err = Restore(store, "backup-1",
+		fs.NewOptions().SetFilePathPrefix(restoreFilePathPrefix))
+	require.Error(t, err)
+}
+
+func TestStageUnknownSnapshot(t *testing.T) {
+	dir, err := ioutil.TempDir("", "backup")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	filePathPrefix := filepath.Join(dir, "data")
+	writeTestFileSet(t, filePathPrefix, 0, testStart, 0, 1)
+
+	src := Source{
+		FilePathPrefix: filePathPrefix,
+		Namespace:      testNamespace,
+		Shards:         []uint32{0},
+		SnapshotID:     uuid.NewUUID().String(),
+		FsOptions:      fs.NewOptions(),
+		StagingDir:     filepath.Join(dir, "staging"),
+	}
+	_, err = Stage("backup-1", src)
+	require.Error(t, err)
+
+	// The staged links are removed when staging fails.
+	_, err = os.Stat(src.StagingDir)
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestRestoreIncompleteBackup(t *testing.T) {
+	dir, err := ioutil.TempDir("", "backup")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	store := NewFileSystemBlobStore(dir)
+	require.NoError(t, store.Put(ArchiveKey("backup-1"), strings.NewReader("")))
+
+	_, err = Restore(store, "backup-1",
+		fs.NewOptions().SetFilePathPrefix(filepath.Join(dir, "restore")))
+	require.Error(t, err)
+}
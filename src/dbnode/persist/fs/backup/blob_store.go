@@ -0,0 +1,221 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package backup
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	fileScheme = "file"
+)
+
+var (
+	errEmptyBlobStoreKey = errors.New("blob store key must not be empty")
+)
+
+// NewBlobStore returns a blob store for the given destination, which is
+// either a bare local path or a URL. Only the "file" scheme is currently
+// supported.
+func NewBlobStore(destination string) (BlobStore, error) {
+	root, err := destinationPath(destination)
+	if err != nil {
+		return nil, err
+	}
+	return NewFileSystemBlobStore(root), nil
+}
+
+// NewBlobStoreWithinRoots returns a blob store for the given destination as
+// NewBlobStore does, the destination must be beneath one of the given root
+// directories.
+func NewBlobStoreWithinRoots(destination string, roots []string) (BlobStore, error) {
+	root, err := destinationPath(destination)
+	if err != nil {
+		return nil, err
+	}
+	if !filepath.IsAbs(root) {
+		return nil, fmt.Errorf("backup destination must be an absolute path: %s", destination)
+	}
+
+	resolved, err := resolvePath(root)
+	if err != nil {
+		return nil, err
+	}
+	for _, allowed := range roots {
+		if allowed == "" {
+			continue
+		}
+		allowedResolved, err := resolvePath(allowed)
+		if err != nil {
+			return nil, err
+		}
+		rel, err := filepath.Rel(allowedResolved, resolved)
+		if err != nil || rel == ".." ||
+			strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		return NewFileSystemBlobStore(root), nil
+	}
+	return nil, fmt.Errorf("backup destination is not beneath an allowed root: %s", destination)
+}
+
+func destinationPath(destination string) (string, error) {
+	if destination == "" {
+		return "", errors.New("backup destination must not be empty")
+	}
+
+	u, err := url.Parse(destination)
+	if err != nil || u.Scheme == "" {
+		return destination, nil
+	}
+
+	switch u.Scheme {
+	case fileScheme:
+		return u.Path, nil
+	default:
+		return "", fmt.Errorf("unsupported backup destination scheme: %s", u.Scheme)
+	}
+}
+
+// resolvePath returns the absolute path with symlinks of the longest
+// existing ancestor of the path resolved, so that a destination can not
+// escape a root through a symlink.
+func resolvePath(p string) (string, error) {
+	p, err := filepath.Abs(p)
+	if err != nil {
+		return "", err
+	}
+
+	var missing []string
+	for {
+		resolved, err := filepath.EvalSymlinks(p)
+		if err == nil {
+			return filepath.Join(append([]string{resolved}, missing...)...), nil
+		}
+		if !os.IsNotExist(err) {
+			return "", err
+		}
+		parent := filepath.Dir(p)
+		if parent == p {
+			return filepath.Join(append([]string{p}, missing...)...), nil
+		}
+		missing = append([]string{filepath.Base(p)}, missing...)
+		p = parent
+	}
+}
+
+type fileSystemBlobStore struct {
+	root string
+}
+
+// NewFileSystemBlobStore returns a blob store that persists each key as a
+// file beneath the given root directory.
+func NewFileSystemBlobStore(root string) BlobStore {
+	return &fileSystemBlobStore{root: root}
+}
+
+func (s *fileSystemBlobStore) Put(key string, r io.Reader) error {
+	filePath, err := s.filePath(key)
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(filePath)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	// Write to a temporary file and rename so that readers never observe
+	// a partially written blob.
+	tmp, err := ioutil.TempFile(dir, "."+filepath.Base(filePath))
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), filePath)
+}
+
+func (s *fileSystemBlobStore) Get(key string) (io.ReadCloser, error) {
+	filePath, err := s.filePath(key)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(filePath)
+}
+
+func (s *fileSystemBlobStore) List(prefix string) ([]string, error) {
+	var keys []string
+	err := filepath.Walk(s.root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if info.IsDir() || strings.HasPrefix(info.Name(), ".") {
+			return nil
+		}
+		rel, err := filepath.Rel(s.root, path)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(keys)
+	return keys, nil
+}
+
+func (s *fileSystemBlobStore) filePath(key string) (string, error) {
+	if key == "" {
+		return "", errEmptyBlobStoreKey
+	}
+	cleaned := filepath.Clean("/" + key)
+	return filepath.Join(s.root, filepath.FromSlash(cleaned)), nil
+}
@@ -0,0 +1,115 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package backup
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewBlobStore(t *testing.T) {
+	_, err := NewBlobStore("/var/backups")
+	require.NoError(t, err)
+
+	_, err = NewBlobStore("file:///var/backups")
+	require.NoError(t, err)
+
+	_, err = NewBlobStore("s3://bucket/backups")
+	require.Error(t, err)
+
+	_, err = NewBlobStore("")
+	require.Error(t, err)
+}
+
+func TestNewBlobStoreWithinRoots(t *testing.T) {
+	dir, err := ioutil.TempDir("", "blobstore")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	var (
+		root    = filepath.Join(dir, "backups")
+		outside = filepath.Join(dir, "outside")
+		roots   = []string{root}
+	)
+	require.NoError(t, os.MkdirAll(root, 0755))
+	require.NoError(t, os.MkdirAll(outside, 0755))
+	require.NoError(t, os.Symlink(outside, filepath.Join(root, "link")))
+
+	_, err = NewBlobStoreWithinRoots(filepath.Join(root, "nightly"), roots)
+	require.NoError(t, err)
+
+	_, err = NewBlobStoreWithinRoots("file://"+filepath.Join(root, "nightly"), roots)
+	require.NoError(t, err)
+
+	_, err = NewBlobStoreWithinRoots(outside, roots)
+	require.Error(t, err)
+
+	_, err = NewBlobStoreWithinRoots(filepath.Join(root, "..", "outside"), roots)
+	require.Error(t, err)
+
+	_, err = NewBlobStoreWithinRoots(filepath.Join(root, "link", "nightly"), roots)
+	require.Error(t, err)
+
+	_, err = NewBlobStoreWithinRoots("backups", roots)
+	require.Error(t, err)
+
+	_, err = NewBlobStoreWithinRoots(filepath.Join(root, "nightly"), nil)
+	require.Error(t, err)
+}
+
+func TestFileSystemBlobStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "blobstore")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	store := NewFileSystemBlobStore(dir)
+	require.NoError(t, store.Put("a/one", strings.NewReader("1")))
+	require.NoError(t, store.Put("a/two", strings.NewReader("2")))
+	require.NoError(t, store.Put("b/one", strings.NewReader("3")))
+	require.NoError(t, store.Put("a/one", strings.NewReader("4")))
+
+	// Keys can not escape the root of the store.
+	require.NoError(t, store.Put("../escape", strings.NewReader("5")))
+	_, err = os.Stat(filepath.Join(dir, "escape"))
+	require.NoError(t, err)
+
+	r, err := store.Get("a/one")
+	require.NoError(t, err)
+	data, err := ioutil.ReadAll(r)
+	require.NoError(t, err)
+	require.NoError(t, r.Close())
+	require.Equal(t, "4", string(data))
+
+	keys, err := store.List("a/")
+	require.NoError(t, err)
+	require.Equal(t, []string{"a/one", "a/two"}, keys)
+
+	_, err = store.Get("missing")
+	require.Error(t, err)
+
+	_, err = store.Get("")
+	require.Error(t, err)
+}
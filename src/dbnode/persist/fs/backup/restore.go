@@ -0,0 +1,141 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package backup
+
+import (
+	"archive/tar"
+	"encoding/json"
+	"fmt"
+	"hash/adler32"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+)
+
+// ReadManifest reads the manifest of a backup, returning an error if the
+// backup does not exist or was never completed.
+func ReadManifest(store BlobStore, backupID string) (Manifest, error) {
+	if backupID == "" {
+		return Manifest{}, errEmptyBackupID
+	}
+
+	r, err := store.Get(ManifestKey(backupID))
+	if err != nil {
+		return Manifest{}, fmt.Errorf("unable to read manifest of backup %s: %v", backupID, err)
+	}
+	defer r.Close()
+
+	var manifest Manifest
+	if err := json.NewDecoder(r).Decode(&manifest); err != nil {
+		return Manifest{}, fmt.Errorf("unable to decode manifest of backup %s: %v", backupID, err)
+	}
+	return manifest, nil
+}
+
+// Restore extracts a backup into the file path prefix of the given options,
+// verifying the size and checksum of every file against the manifest. Files
+// are laid out exactly as the filesystem bootstrapper expects them, data
+// filesets that were held in a cold tier are restored into the primary tier.
+// Restore refuses to overwrite existing files.
+func Restore(store BlobStore, backupID string, opts fs.Options) (Manifest, error) {
+	manifest, err := ReadManifest(store, backupID)
+	if err != nil {
+		return Manifest{}, err
+	}
+
+	expected := make(map[string]ManifestFile, len(manifest.Files))
+	for _, file := range manifest.Files {
+		expected[file.Path] = file
+	}
+
+	r, err := store.Get(ArchiveKey(backupID))
+	if err != nil {
+		return Manifest{}, fmt.Errorf("unable to read archive of backup %s: %v", backupID, err)
+	}
+	defer r.Close()
+
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return Manifest{}, err
+		}
+
+		file, ok := expected[header.Name]
+		if !ok {
+			return Manifest{}, fmt.Errorf("archive file %s is not in the manifest", header.Name)
+		}
+		if err := restoreFile(tr, file, opts); err != nil {
+			return Manifest{}, err
+		}
+		delete(expected, header.Name)
+	}
+
+	if len(expected) > 0 {
+		return Manifest{}, fmt.Errorf("archive is missing %d files from the manifest", len(expected))
+	}
+	return manifest, nil
+}
+
+func restoreFile(r io.Reader, file ManifestFile, opts fs.Options) error {
+	rel := filepath.FromSlash(file.Path)
+	if filepath.IsAbs(rel) || strings.HasPrefix(filepath.Clean(rel), "..") {
+		return fmt.Errorf("archive file %s is outside of the file path prefix", file.Path)
+	}
+
+	filePath := filepath.Join(opts.FilePathPrefix(), rel)
+	if err := os.MkdirAll(filepath.Dir(filePath), opts.NewDirectoryMode()); err != nil {
+		return err
+	}
+
+	fd, err := os.OpenFile(filePath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, opts.NewFileMode())
+	if err != nil {
+		return err
+	}
+
+	checksum := adler32.New()
+	size, err := io.Copy(io.MultiWriter(fd, checksum), r)
+	if err != nil {
+		fd.Close()
+		return err
+	}
+	if err := fd.Sync(); err != nil {
+		fd.Close()
+		return err
+	}
+	if err := fd.Close(); err != nil {
+		return err
+	}
+
+	if size != file.Size || checksum.Sum32() != file.Checksum {
+		// Remove the corrupt file so that a partial restore never looks
+		// like a valid fileset to the bootstrapper.
+		os.Remove(filePath)
+		return fmt.Errorf("archive file %s does not match its manifest checksum", file.Path)
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package backup
+
+import (
+	"io"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/x/ident"
+)
+
+// BlobStore is a minimal object store used to persist backups.
+type BlobStore interface {
+	// Put writes the contents of the reader to the given key, replacing
+	// any existing contents.
+	Put(key string, r io.Reader) error
+
+	// Get returns a reader for the contents of the given key.
+	Get(key string) (io.ReadCloser, error)
+
+	// List returns all the keys that begin with the given prefix in
+	// lexicographical order.
+	List(prefix string) ([]string, error)
+}
+
+// Source describes the filesets of a namespace to back up.
+type Source struct {
+	// FilePathPrefix is the primary file path prefix of the node.
+	FilePathPrefix string
+
+	// ColdTierFilePathPrefix is the cold tier file path prefix of the
+	// namespace, if any.
+	ColdTierFilePathPrefix string
+
+	// Namespace is the namespace to back up.
+	Namespace ident.ID
+
+	// Shards are the shards of the namespace to back up.
+	Shards []uint32
+
+	// SnapshotID is the ID of the snapshot to back up, the latest snapshot
+	// is backed up if it is empty.
+	SnapshotID string
+
+	// FsOptions are the fileset options used to read snapshot metadata.
+	FsOptions fs.Options
+
+	// StagingDir is a directory on the same device as the file path prefix
+	// that the files are linked into while they are archived, it is removed
+	// once the backup completes.
+	StagingDir string
+}
+
+// Manifest describes the contents of a backup, it is written once the
+// archive has been fully written so its presence marks a complete backup.
+type Manifest struct {
+	BackupID   string         `json:"backupID"`
+	Namespace  string         `json:"namespace"`
+	Shards     []uint32       `json:"shards"`
+	SnapshotID string         `json:"snapshotID,omitempty"`
+	CreatedAt  time.Time      `json:"createdAt"`
+	Files      []ManifestFile `json:"files"`
+}
+
+// ManifestFile is a single file contained in a backup archive.
+type ManifestFile struct {
+	// Path is the path of the file relative to the file path prefix.
+	Path string `json:"path"`
+
+	// Size is the size of the file in bytes.
+	Size int64 `json:"size"`
+
+	// Checksum is the adler32 checksum of the contents of the file.
+	Checksum uint32 `json:"checksum"`
+}
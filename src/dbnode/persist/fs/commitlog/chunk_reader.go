@@ -41,9 +41,16 @@ type chunkReader struct {
 	buffer    *bufio.Reader
 	remaining int
 	charBuff  []byte
+
+	compression     CompressionType
+	decompressed    []byte
+	decompressedPos int
 }
 
 func newChunkReader(bufferLen int) *chunkReader {
+	// Compressed chunks can be larger than the flush size when the data
+	// is not compressible, size the buffer so they can be peeked.
+	bufferLen = CompressionSnappy.maxCompressedLen(bufferLen)
 	return &chunkReader{
 		buffer:   bufio.NewReaderSize(nil, bufferLen),
 		charBuff: make([]byte, 1),
@@ -54,6 +61,15 @@ func (r *chunkReader) reset(fd *os.File) {
 	r.fd = fd
 	r.buffer.Reset(fd)
 	r.remaining = 0
+	r.compression = CompressionNone
+	r.decompressed = r.decompressed[:0]
+	r.decompressedPos = 0
+}
+
+// setCompression sets the compression of the chunks that follow the
+// current chunk.
+func (r *chunkReader) setCompression(value CompressionType) {
+	r.compression = value
 }
 
 func (r *chunkReader) readHeader() error {
@@ -90,12 +106,37 @@ func (r *chunkReader) readHeader() error {
 		return errCommitLogReaderChunkSizeChecksumMismatch
 	}
 
-	// Set remaining data to be consumed
-	r.remaining = int(size)
+	if r.compression == CompressionNone {
+		// Set remaining data to be consumed
+		r.remaining = int(size)
+		return nil
+	}
+
+	decompressed, err := r.compression.decompress(r.decompressed, data)
+	if err != nil {
+		return err
+	}
+	if _, err := r.buffer.Discard(int(size)); err != nil {
+		return err
+	}
+
+	// Set remaining decompressed data to be consumed
+	r.decompressed = decompressed
+	r.decompressedPos = 0
+	r.remaining = len(decompressed)
 
 	return nil
 }
 
+func (r *chunkReader) readChunk(p []byte) (int, error) {
+	if r.compression == CompressionNone {
+		return r.buffer.Read(p)
+	}
+	n := copy(p, r.decompressed[r.decompressedPos:])
+	r.decompressedPos += n
+	return n, nil
+}
+
 func (r *chunkReader) Read(p []byte) (int, error) {
 	size := len(p)
 	read := 0
@@ -103,7 +144,7 @@ func (r *chunkReader) Read(p []byte) (int, error) {
 	if r.remaining < size {
 		// Copy any remaining
 		if r.remaining > 0 {
-			n, err := r.buffer.Read(p[:r.remaining])
+			n, err := r.readChunk(p[:r.remaining])
 			r.remaining -= n
 			read += n
 			if err != nil {
@@ -125,7 +166,7 @@ func (r *chunkReader) Read(p []byte) (int, error) {
 		return read, err
 	}
 
-	n, err := r.buffer.Read(p)
+	n, err := r.readChunk(p)
 	r.remaining -= n
 	read += n
 	return read, err
@@ -446,6 +446,34 @@ func (mr *MockOptionsMockRecorder) Strategy() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Strategy", reflect.TypeOf((*MockOptions)(nil).Strategy))
 }
 
+// SetCompressionType mocks base method
+func (m *MockOptions) SetCompressionType(value CompressionType) Options {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetCompressionType", value)
+	ret0, _ := ret[0].(Options)
+	return ret0
+}
+
+// SetCompressionType indicates an expected call of SetCompressionType
+func (mr *MockOptionsMockRecorder) SetCompressionType(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCompressionType", reflect.TypeOf((*MockOptions)(nil).SetCompressionType), value)
+}
+
+// CompressionType mocks base method
+func (m *MockOptions) CompressionType() CompressionType {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompressionType")
+	ret0, _ := ret[0].(CompressionType)
+	return ret0
+}
+
+// CompressionType indicates an expected call of CompressionType
+func (mr *MockOptionsMockRecorder) CompressionType() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompressionType", reflect.TypeOf((*MockOptions)(nil).CompressionType))
+}
+
 // SetFlushInterval mocks base method
 func (m *MockOptions) SetFlushInterval(value time.Duration) Options {
 	m.ctrl.T.Helper()
@@ -68,6 +68,7 @@ type overrides struct {
 	flushInterval    *time.Duration
 	backlogQueueSize *int
 	strategy         Strategy
+	compression      CompressionType
 }
 
 var testOpts = NewOptions().
@@ -108,7 +109,8 @@ func newTestOptions(
 		opts = opts.SetBacklogQueueSize(*overrides.backlogQueueSize)
 	}
 
-	opts = opts.SetStrategy(overrides.strategy)
+	opts = opts.SetStrategy(overrides.strategy).
+		SetCompressionType(overrides.compression)
 
 	return opts, scope
 }
@@ -373,6 +375,33 @@ func TestCommitLogWrite(t *testing.T) {
 	assertCommitLogWritesByIterating(t, commitLog, writes)
 }
 
+func TestCommitLogWriteCompressed(t *testing.T) {
+	opts, scope := newTestOptions(t, overrides{
+		strategy:    StrategyWriteWait,
+		compression: CompressionSnappy,
+	})
+	defer cleanup(t, opts)
+
+	commitLog := newTestCommitLog(t, opts)
+
+	writes := []testWrite{
+		{testSeries(0, "foo.bar", ident.NewTags(ident.StringTag("name1", "val1")), 127), time.Now(), 123.456, xtime.Second, []byte{1, 2, 3}, nil},
+		{testSeries(1, "foo.baz", ident.NewTags(ident.StringTag("name2", "val2")), 150), time.Now(), 456.789, xtime.Second, nil, nil},
+		{testSeries(0, "foo.bar", ident.NewTags(ident.StringTag("name1", "val1")), 127), time.Now(), 789.123, xtime.Second, nil, nil},
+	}
+
+	// Call write sync
+	writeCommitLogs(t, scope, commitLog, writes).Wait()
+
+	// Close the commit log and consequently flush
+	require.NoError(t, commitLog.Close())
+
+	// Assert writes occurred by reading the commit log, the reader discovers
+	// the compression from the log info so it does not need to be configured.
+	commitLog.opts = commitLog.opts.SetCompressionType(CompressionNone)
+	assertCommitLogWritesByIterating(t, commitLog, writes)
+}
+
 func TestReadCommitLogMissingMetadata(t *testing.T) {
 	readConc := 4
 	// Make sure we're not leaking goroutines
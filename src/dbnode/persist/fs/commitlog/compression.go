@@ -0,0 +1,171 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package commitlog
+
+import (
+	"fmt"
+
+	"github.com/m3db/m3/src/dbnode/persist/schema"
+
+	"github.com/golang/snappy"
+)
+
+const (
+	// chunkVersionUncompressed is the chunk framing of commit logs written
+	// before the chunk version was recorded, all chunks are uncompressed.
+	chunkVersionUncompressed = 1
+
+	// chunkVersionCompressed is the chunk framing where the first chunk
+	// holds only the log info and the compression type recorded in the log
+	// info is applied to the payload of every chunk that follows, the chunk
+	// checksums are calculated over the compressed payload.
+	chunkVersionCompressed = 2
+
+	// currentChunkVersion is the chunk framing version written.
+	currentChunkVersion = chunkVersionCompressed
+)
+
+// CompressionType describes the compression applied to commit log chunks.
+type CompressionType uint8
+
+const (
+	// CompressionNone indicates that commit log chunks are not compressed.
+	CompressionNone CompressionType = iota
+
+	// CompressionSnappy indicates that commit log chunks are compressed
+	// with the snappy block format.
+	CompressionSnappy
+)
+
+var validCompressionTypes = []CompressionType{
+	CompressionNone,
+	CompressionSnappy,
+}
+
+// ValidCompressionTypes returns the valid commit log compression types.
+func ValidCompressionTypes() []CompressionType {
+	src := validCompressionTypes
+	result := make([]CompressionType, len(src))
+	copy(result, src)
+	return result
+}
+
+// Validate validates that the compression type is valid.
+func (t CompressionType) Validate() error {
+	for _, valid := range validCompressionTypes {
+		if t == valid {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid commit log compression type: '%v' valid types are: %v",
+		t, validCompressionTypes)
+}
+
+func (t CompressionType) String() string {
+	switch t {
+	case CompressionNone:
+		return "none"
+	case CompressionSnappy:
+		return "snappy"
+	default:
+		return "unknown"
+	}
+}
+
+// UnmarshalYAML unmarshals a stored compression type.
+func (t *CompressionType) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var str string
+	if err := unmarshal(&str); err != nil {
+		return err
+	}
+	if str == "" {
+		*t = CompressionNone
+		return nil
+	}
+	for _, valid := range validCompressionTypes {
+		if str == valid.String() {
+			*t = valid
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid commit log compression type: '%s' valid types are: %v",
+		str, validCompressionTypes)
+}
+
+// MarshalYAML returns the YAML representation of the compression type.
+func (t CompressionType) MarshalYAML() (interface{}, error) {
+	return t.String(), nil
+}
+
+// maxCompressedLen returns the maximum length of a chunk payload of the
+// given length after compression.
+func (t CompressionType) maxCompressedLen(n int) int {
+	switch t {
+	case CompressionSnappy:
+		return snappy.MaxEncodedLen(n)
+	default:
+		return n
+	}
+}
+
+// compress compresses src into dst, growing dst if required, and returns
+// the compressed bytes.
+func (t CompressionType) compress(dst, src []byte) ([]byte, error) {
+	switch t {
+	case CompressionNone:
+		return append(dst[:0], src...), nil
+	case CompressionSnappy:
+		return snappy.Encode(dst[:cap(dst)], src), nil
+	default:
+		return nil, t.Validate()
+	}
+}
+
+// decompress decompresses src into dst, growing dst if required, and
+// returns the decompressed bytes.
+func (t CompressionType) decompress(dst, src []byte) ([]byte, error) {
+	switch t {
+	case CompressionNone:
+		return append(dst[:0], src...), nil
+	case CompressionSnappy:
+		return snappy.Decode(dst[:cap(dst)], src)
+	default:
+		return nil, t.Validate()
+	}
+}
+
+// chunkCompression returns the compression applied to the chunks that
+// follow the chunk holding the log info.
+func chunkCompression(info schema.LogInfo) (CompressionType, error) {
+	switch info.ChunkVersion {
+	case 0, chunkVersionUncompressed:
+		return CompressionNone, nil
+	case chunkVersionCompressed:
+		compression := CompressionType(info.ChunkCompression)
+		if err := compression.Validate(); err != nil {
+			return CompressionNone, err
+		}
+		return compression, nil
+	default:
+		return CompressionNone, fmt.Errorf(
+			"unsupported commit log chunk version: %d", info.ChunkVersion)
+	}
+}
@@ -0,0 +1,120 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package commitlog
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/m3db/m3/src/dbnode/persist/schema"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	yaml "gopkg.in/yaml.v2"
+)
+
+func TestCompressionTypeValidate(t *testing.T) {
+	for _, value := range ValidCompressionTypes() {
+		assert.NoError(t, value.Validate())
+	}
+	assert.Error(t, CompressionType(42).Validate())
+}
+
+func TestCompressionTypeUnmarshalYAML(t *testing.T) {
+	type config struct {
+		Type CompressionType `yaml:"type"`
+	}
+
+	for _, value := range ValidCompressionTypes() {
+		str := fmt.Sprintf("type: %s\n", value.String())
+
+		var cfg config
+		require.NoError(t, yaml.Unmarshal([]byte(str), &cfg))
+		assert.Equal(t, value, cfg.Type)
+
+		marshalled, err := yaml.Marshal(cfg)
+		require.NoError(t, err)
+		assert.Equal(t, str, string(marshalled))
+	}
+
+	var cfg config
+	require.Error(t, yaml.Unmarshal([]byte("type: not_a_known_type\n"), &cfg))
+
+	cfg = config{}
+	require.NoError(t, yaml.Unmarshal([]byte(""), &cfg))
+	assert.Equal(t, CompressionNone, cfg.Type)
+}
+
+func TestCompressionTypeRoundtrip(t *testing.T) {
+	data := bytes.Repeat([]byte("commit log chunk data "), 512)
+	for _, value := range ValidCompressionTypes() {
+		compressed, err := value.compress(nil, data)
+		require.NoError(t, err)
+		require.True(t, len(compressed) <= value.maxCompressedLen(len(data)))
+
+		decompressed, err := value.decompress(nil, compressed)
+		require.NoError(t, err)
+		require.Equal(t, data, decompressed)
+	}
+}
+
+func TestChunkCompression(t *testing.T) {
+	tests := []struct {
+		info     schema.LogInfo
+		expected CompressionType
+		err      bool
+	}{
+		{info: schema.LogInfo{}, expected: CompressionNone},
+		{
+			info: schema.LogInfo{
+				ChunkVersion:     chunkVersionUncompressed,
+				ChunkCompression: int64(CompressionSnappy),
+			},
+			expected: CompressionNone,
+		},
+		{
+			info: schema.LogInfo{
+				ChunkVersion:     chunkVersionCompressed,
+				ChunkCompression: int64(CompressionSnappy),
+			},
+			expected: CompressionSnappy,
+		},
+		{
+			info: schema.LogInfo{
+				ChunkVersion:     chunkVersionCompressed,
+				ChunkCompression: 42,
+			},
+			err: true,
+		},
+		{info: schema.LogInfo{ChunkVersion: currentChunkVersion + 1}, err: true},
+	}
+
+	for _, test := range tests {
+		compression, err := chunkCompression(test.info)
+		if test.err {
+			require.Error(t, err)
+			continue
+		}
+		require.NoError(t, err)
+		require.Equal(t, test.expected, compression)
+	}
+}
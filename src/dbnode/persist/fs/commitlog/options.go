@@ -37,6 +37,9 @@ const (
 	// defaultStrategy is the default commit log write strategy
 	defaultStrategy = StrategyWriteBehind
 
+	// defaultCompressionType is the default commit log compression type
+	defaultCompressionType = CompressionNone
+
 	// defaultFlushInterval is the default commit log flush interval
 	defaultFlushInterval = time.Second
 
@@ -74,6 +77,7 @@ type options struct {
 	blockSize               time.Duration
 	fsOpts                  fs.Options
 	strategy                Strategy
+	compressionType         CompressionType
 	flushSize               int
 	flushInterval           time.Duration
 	backlogQueueSize        int
@@ -91,6 +95,7 @@ func NewOptions() Options {
 		blockSize:               defaultBlockSize,
 		fsOpts:                  fs.NewOptions(),
 		strategy:                defaultStrategy,
+		compressionType:         defaultCompressionType,
 		flushSize:               defaultFlushSize,
 		flushInterval:           defaultFlushInterval,
 		backlogQueueSize:        defaultBacklogQueueSize,
@@ -118,6 +123,10 @@ func (o *options) Validate() error {
 		return errReadConcurrencyPositive
 	}
 
+	if err := o.CompressionType().Validate(); err != nil {
+		return err
+	}
+
 	if float64(o.BacklogQueueSize())/float64(o.BacklogQueueChannelSize()) > MaximumQueueSizeQueueChannelSizeRatio {
 		return fmt.Errorf(
 			"BacklogQueueSize / BacklogQueueChannelSize ratio must be at most: %f, but was: %f",
@@ -177,6 +186,16 @@ func (o *options) Strategy() Strategy {
 	return o.strategy
 }
 
+func (o *options) SetCompressionType(value CompressionType) Options {
+	opts := *o
+	opts.compressionType = value
+	return &opts
+}
+
+func (o *options) CompressionType() CompressionType {
+	return o.compressionType
+}
+
 func (o *options) SetFlushSize(value int) Options {
 	opts := *o
 	opts.flushSize = value
@@ -700,6 +700,10 @@ func (c *corruptingChunkWriter) reset(f xos.File) {
 		f, c.corruptionProbability, c.seed)
 }
 
+func (c *corruptingChunkWriter) setCompression(value CompressionType) {
+	c.chunkWriter.setCompression(value)
+}
+
 func (c *corruptingChunkWriter) Write(p []byte) (int, error) {
 	return c.chunkWriter.Write(p)
 }
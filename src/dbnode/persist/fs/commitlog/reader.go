@@ -134,6 +134,13 @@ func (r *reader) Open(filePath string) (int64, error) {
 		return 0, err
 	}
 
+	compression, err := chunkCompression(info)
+	if err != nil {
+		r.Close()
+		return 0, err
+	}
+	r.chunkReader.setCompression(compression)
+
 	r.fileReadID = commitLogFileReadCounter.Inc()
 
 	index := info.Index
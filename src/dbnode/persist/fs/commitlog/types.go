@@ -177,6 +177,12 @@ type Options interface {
 	// Strategy returns the strategy.
 	Strategy() Strategy
 
+	// SetCompressionType sets the compression applied to commit log chunks.
+	SetCompressionType(value CompressionType) Options
+
+	// CompressionType returns the compression applied to commit log chunks.
+	CompressionType() CompressionType
+
 	// SetFlushInterval sets the flush interval.
 	SetFlushInterval(value time.Duration) Options
 
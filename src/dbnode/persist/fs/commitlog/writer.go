@@ -89,6 +89,7 @@ type chunkWriter interface {
 	io.Writer
 
 	reset(f xos.File)
+	setCompression(value CompressionType)
 	close() error
 	isOpen() bool
 	sync() error
@@ -111,6 +112,7 @@ type writer struct {
 	metadataEncoderBuff []byte
 	tagEncoder          serialize.TagEncoder
 	tagSliceIter        ident.TagsIterator
+	compression         CompressionType
 	opts                Options
 }
 
@@ -135,6 +137,7 @@ func newCommitLogWriter(
 		metadataEncoderBuff: make([]byte, 0, defaultEncoderBuffSize),
 		tagEncoder:          opts.FilesystemOptions().TagEncoderPool().Get(),
 		tagSliceIter:        ident.NewTagsIterator(ident.Tags{}),
+		compression:         opts.CompressionType(),
 		opts:                opts,
 	}
 }
@@ -163,7 +166,9 @@ func (w *writer) Open() (persist.CommitLogFile, error) {
 		return persist.CommitLogFile{}, err
 	}
 	logInfo := schema.LogInfo{
-		Index: int64(index),
+		Index:            int64(index),
+		ChunkVersion:     currentChunkVersion,
+		ChunkCompression: int64(w.compression),
 	}
 	w.logEncoder.Reset()
 	if err := w.logEncoder.EncodeLogInfo(logInfo); err != nil {
@@ -175,12 +180,23 @@ func (w *writer) Open() (persist.CommitLogFile, error) {
 	}
 
 	w.chunkWriter.reset(fd)
+	w.chunkWriter.setCompression(CompressionNone)
 	w.buffer.Reset(w.chunkWriter)
 	if err := w.write(w.logEncoder.Bytes()); err != nil {
 		w.Close()
 		return persist.CommitLogFile{}, err
 	}
 
+	if w.compression != CompressionNone {
+		// The log info is written to its own uncompressed chunk so that
+		// readers can discover the compression of the chunks that follow.
+		if err := w.buffer.Flush(); err != nil {
+			w.Close()
+			return persist.CommitLogFile{}, err
+		}
+		w.chunkWriter.setCompression(w.compression)
+	}
+
 	return persist.CommitLogFile{
 		FilePath: filePath,
 		Index:    int64(index),
@@ -317,10 +333,12 @@ func (w *writer) write(data []byte) error {
 }
 
 type fsChunkWriter struct {
-	fd      xos.File
-	flushFn flushFn
-	buff    []byte
-	fsync   bool
+	fd          xos.File
+	flushFn     flushFn
+	buff        []byte
+	compressed  []byte
+	compression CompressionType
+	fsync       bool
 }
 
 func newChunkWriter(flushFn flushFn, fsync bool) chunkWriter {
@@ -335,6 +353,10 @@ func (w *fsChunkWriter) reset(f xos.File) {
 	w.fd = f
 }
 
+func (w *fsChunkWriter) setCompression(value CompressionType) {
+	w.compression = value
+}
+
 func (w *fsChunkWriter) close() error {
 	err := w.fd.Close()
 	w.fd = nil
@@ -350,7 +372,18 @@ func (w *fsChunkWriter) sync() error {
 }
 
 func (w *fsChunkWriter) Write(p []byte) (int, error) {
-	size := len(p)
+	data := p
+	if w.compression != CompressionNone {
+		compressed, err := w.compression.compress(w.compressed, p)
+		if err != nil {
+			w.flushFn(err)
+			return 0, err
+		}
+		w.compressed = compressed
+		data = compressed
+	}
+
+	size := len(data)
 
 	sizeStart, sizeEnd :=
 		0, chunkHeaderSizeLen
@@ -364,7 +397,7 @@ func (w *fsChunkWriter) Write(p []byte) (int, error) {
 
 	// Calculate checksums
 	checksumSize := digest.Checksum(w.buff[sizeStart:sizeEnd])
-	checksumData := digest.Checksum(p)
+	checksumData := digest.Checksum(data)
 
 	// Write checksums
 	digest.
@@ -375,7 +408,7 @@ func (w *fsChunkWriter) Write(p []byte) (int, error) {
 		WriteDigest(checksumData)
 
 	// Combine buffers to reduce to a single syscall
-	w.buff = append(w.buff[:chunkHeaderLen], p...)
+	w.buff = append(w.buff[:chunkHeaderLen], data...)
 
 	// Write contents to file descriptor
 	n, err := w.fd.Write(w.buff)
@@ -384,6 +417,12 @@ func (w *fsChunkWriter) Write(p []byte) (int, error) {
 		return n, err
 	}
 
+	if w.compression != CompressionNone {
+		// Report the uncompressed length as written since the compressed
+		// chunk may be shorter than the data passed.
+		n = len(p)
+	}
+
 	// Fsync if required to
 	if w.fsync {
 		err = w.sync()
@@ -49,7 +49,8 @@ import (
 var (
 	timeZero time.Time
 
-	errSnapshotTimeAndIDZero = errors.New("tried to read snapshot time and ID of zero value")
+	errSnapshotTimeAndIDZero  = errors.New("tried to read snapshot time and ID of zero value")
+	errCheckpointFileNotFound = errors.New("fileset has no checkpoint file")
 )
 
 const (
@@ -174,6 +175,22 @@ func (f *FileSetFile) evalHasCompleteCheckpointFile() LazyEvalBool {
 	return EvalFalse
 }
 
+// CheckpointWrittenAt returns the time the checkpoint file of the fileset was
+// last modified, which is when the fileset was completely written.
+func (f FileSetFile) CheckpointWrittenAt() (time.Time, error) {
+	for _, fileName := range f.AbsoluteFilepaths {
+		if strings.Contains(fileName, checkpointFileSuffix) {
+			info, err := os.Stat(fileName)
+			if err != nil {
+				return timeZero, err
+			}
+			return info.ModTime(), nil
+		}
+	}
+
+	return timeZero, errCheckpointFileNotFound
+}
+
 // FileSetFilesSlice is a slice of FileSetFile
 type FileSetFilesSlice []FileSetFile
 
@@ -966,6 +983,18 @@ func SnapshotFiles(filePathPrefix string, namespace ident.ID, shard uint32) (Fil
 	})
 }
 
+// IndexFiles returns a slice of all the names for all the index fileset files
+// for a given namespace.
+func IndexFiles(filePathPrefix string, namespace ident.ID) (FileSetFilesSlice, error) {
+	return filesetFiles(filesetFilesSelector{
+		fileSetType:    persist.FileSetFlushType,
+		contentType:    persist.FileSetIndexContentType,
+		filePathPrefix: filePathPrefix,
+		namespace:      namespace,
+		pattern:        filesetFilePattern,
+	})
+}
+
 // IndexSnapshotFiles returns a slice of all the names for all the index fileset files
 // for a given namespace.
 func IndexSnapshotFiles(filePathPrefix string, namespace ident.ID) (FileSetFilesSlice, error) {
@@ -860,6 +860,31 @@ func TestSnapshotFileHasCompleteCheckpointFile(t *testing.T) {
 	require.Equal(t, false, f.HasCompleteCheckpointFile())
 }
 
+func TestFileSetFileCheckpointWrittenAt(t *testing.T) {
+	dir := createTempDir(t)
+	defer os.RemoveAll(dir)
+
+	checkpointFilePath := path.Join(dir, "123-checkpoint-0.db")
+	err := ioutil.WriteFile(checkpointFilePath, []byte{1, 2, 3}, defaultNewFileMode)
+	require.NoError(t, err)
+	writtenAt := time.Unix(1000, 0)
+	require.NoError(t, os.Chtimes(checkpointFilePath, writtenAt, writtenAt))
+
+	f := FileSetFile{
+		AbsoluteFilepaths: []string{path.Join(dir, "123-data-0.db"), checkpointFilePath},
+	}
+	actual, err := f.CheckpointWrittenAt()
+	require.NoError(t, err)
+	require.True(t, writtenAt.Equal(actual))
+
+	// Fails without a checkpoint file
+	f = FileSetFile{
+		AbsoluteFilepaths: []string{path.Join(dir, "123-data-0.db")},
+	}
+	_, err = f.CheckpointWrittenAt()
+	require.Equal(t, errCheckpointFileNotFound, err)
+}
+
 func TestSnapshotDirPath(t *testing.T) {
 	require.Equal(t, "prefix/snapshots", SnapshotDirPath("prefix"))
 }
@@ -31,6 +31,7 @@ const (
 	digestFileSuffix         = "digest"
 	checkpointFileSuffix     = "checkpoint"
 	metadataFileSuffix       = "metadata"
+	postingsCacheFileSuffix  = "postingscache"
 	filesetFilePrefix        = "fileset"
 	commitLogFilePrefix      = "commitlog"
 	segmentFileSetFilePrefix = "segment"
@@ -1190,6 +1190,21 @@ func (m *MockMergeWith) EXPECT() *MockMergeWithMockRecorder {
 	return m.recorder
 }
 
+// DeletedAt mocks base method
+func (m *MockMergeWith) DeletedAt(arg0 ident.ID) (time.Time, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletedAt", arg0)
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// DeletedAt indicates an expected call of DeletedAt
+func (mr *MockMergeWithMockRecorder) DeletedAt(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletedAt", reflect.TypeOf((*MockMergeWith)(nil).DeletedAt), arg0)
+}
+
 // ForEachRemaining mocks base method
 func (m *MockMergeWith) ForEachRemaining(arg0 context.Context, arg1 time0.UnixNano, arg2 ForEachRemainingFn, arg3 namespace.Context) error {
 	m.ctrl.T.Helper()
@@ -0,0 +1,296 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fs
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/m3db/m3/src/dbnode/digest"
+	m3ninxfs "github.com/m3db/m3/src/m3ninx/index/segment/fst"
+	"github.com/m3db/m3/src/m3ninx/postings"
+	"github.com/m3db/m3/src/m3ninx/postings/pilosa"
+)
+
+const (
+	postingsCacheFileVersion  = 1
+	postingsCacheChecksumSize = 4
+)
+
+var (
+	errPostingsCacheFileTooShort  = errors.New("postings cache file too short")
+	errPostingsCacheFileChecksum  = errors.New("postings cache file checksum mismatch")
+	errPostingsCacheFileVersion   = errors.New("postings cache file version unsupported")
+	errPostingsCacheFileTrailing  = errors.New("postings cache file has trailing bytes")
+	errPostingsCacheFileBadVarint = errors.New("postings cache file has an invalid varint")
+	errPostingsCacheFileBadLength = errors.New("postings cache file has an invalid length")
+	errPostingsCacheFileQueryType = errors.New("postings cache file has an unknown query type")
+)
+
+// IndexPostingsCacheQueryType is the type of query a persisted postings list
+// was resolved for.
+type IndexPostingsCacheQueryType uint8
+
+const (
+	// IndexPostingsCacheRegexpQuery is a postings list resolved for a regexp query.
+	IndexPostingsCacheRegexpQuery IndexPostingsCacheQueryType = iota
+	// IndexPostingsCacheTermQuery is a postings list resolved for a term query.
+	IndexPostingsCacheTermQuery
+	// IndexPostingsCacheFieldQuery is a postings list resolved for a field query.
+	IndexPostingsCacheFieldQuery
+)
+
+func (t IndexPostingsCacheQueryType) validate() error {
+	switch t {
+	case IndexPostingsCacheRegexpQuery, IndexPostingsCacheTermQuery,
+		IndexPostingsCacheFieldQuery:
+		return nil
+	}
+	return errPostingsCacheFileQueryType
+}
+
+// IndexPostingsCacheEntry is a postings list resolved against a segment of an
+// index fileset volume that is persisted alongside the volume.
+type IndexPostingsCacheEntry struct {
+	SegmentIndex int
+	QueryType    IndexPostingsCacheQueryType
+	Field        []byte
+	Pattern      []byte
+	PostingsList postings.List
+}
+
+// IndexFileSetSegment is a segment read from an index fileset volume, it
+// knows which volume it was read from and carries the postings lists that
+// were persisted for it alongside the volume.
+type IndexFileSetSegment struct {
+	m3ninxfs.Segment
+
+	fileSetID      FileSetFileIdentifier
+	segmentIndex   int
+	cachedPostings []IndexPostingsCacheEntry
+}
+
+// NewIndexFileSetSegment returns a new index fileset segment.
+func NewIndexFileSetSegment(
+	seg m3ninxfs.Segment,
+	fileSetID FileSetFileIdentifier,
+	segmentIndex int,
+	cachedPostings []IndexPostingsCacheEntry,
+) *IndexFileSetSegment {
+	return &IndexFileSetSegment{
+		Segment:        seg,
+		fileSetID:      fileSetID,
+		segmentIndex:   segmentIndex,
+		cachedPostings: cachedPostings,
+	}
+}
+
+// FileSetID returns the identifier of the index fileset volume the segment
+// was read from.
+func (s *IndexFileSetSegment) FileSetID() FileSetFileIdentifier {
+	return s.fileSetID
+}
+
+// SegmentIndex returns the index of the segment within its index fileset volume.
+func (s *IndexFileSetSegment) SegmentIndex() int {
+	return s.segmentIndex
+}
+
+// TakeCachedPostings returns the postings lists persisted for the segment and
+// releases the segment's references to them.
+func (s *IndexFileSetSegment) TakeCachedPostings() []IndexPostingsCacheEntry {
+	cachedPostings := s.cachedPostings
+	s.cachedPostings = nil
+	return cachedPostings
+}
+
+// WriteIndexPostingsCache writes the postings cache file of an index fileset
+// volume, replacing any postings cache file previously written for it.
+func WriteIndexPostingsCache(
+	opts Options,
+	id FileSetFileIdentifier,
+	entries []IndexPostingsCacheEntry,
+) error {
+	var (
+		buf     bytes.Buffer
+		scratch [binary.MaxVarintLen64]byte
+		encoder = pilosa.NewEncoder()
+	)
+	writeUvarint := func(v uint64) {
+		n := binary.PutUvarint(scratch[:], v)
+		buf.Write(scratch[:n])
+	}
+	writeBytes := func(b []byte) {
+		writeUvarint(uint64(len(b)))
+		buf.Write(b)
+	}
+
+	writeUvarint(postingsCacheFileVersion)
+	writeUvarint(uint64(len(entries)))
+	for _, entry := range entries {
+		if err := entry.QueryType.validate(); err != nil {
+			return err
+		}
+
+		pl, err := encoder.Encode(entry.PostingsList)
+		if err != nil {
+			return err
+		}
+
+		writeUvarint(uint64(entry.SegmentIndex))
+		writeUvarint(uint64(entry.QueryType))
+		writeBytes(entry.Field)
+		writeBytes(entry.Pattern)
+		writeBytes(pl)
+	}
+
+	var checksum [postingsCacheChecksumSize]byte
+	binary.BigEndian.PutUint32(checksum[:], digest.Checksum(buf.Bytes()))
+	buf.Write(checksum[:])
+
+	dir := NamespaceIndexDataDirPath(opts.FilePathPrefix(), id.Namespace)
+	if err := os.MkdirAll(dir, opts.NewDirectoryMode()); err != nil {
+		return err
+	}
+
+	// NB: the file is not part of the fileset's checkpoint and digests, a
+	// partially written file is detected by its checksum when read instead.
+	return ioutil.WriteFile(indexPostingsCacheFilePath(opts.FilePathPrefix(), id),
+		buf.Bytes(), opts.NewFileMode())
+}
+
+// ReadIndexPostingsCache reads the postings cache file of an index fileset
+// volume, it returns no entries if the volume has no postings cache file.
+func ReadIndexPostingsCache(
+	opts Options,
+	id FileSetFileIdentifier,
+) ([]IndexPostingsCacheEntry, error) {
+	data, err := ioutil.ReadFile(indexPostingsCacheFilePath(opts.FilePathPrefix(), id))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) < postingsCacheChecksumSize {
+		return nil, errPostingsCacheFileTooShort
+	}
+	data, checksum := data[:len(data)-postingsCacheChecksumSize],
+		data[len(data)-postingsCacheChecksumSize:]
+	if digest.Checksum(data) != binary.BigEndian.Uint32(checksum) {
+		return nil, errPostingsCacheFileChecksum
+	}
+
+	d := postingsCacheDecoder{data: data}
+	if version := d.uvarint(); d.err == nil && version != postingsCacheFileVersion {
+		return nil, fmt.Errorf("%v: %d", errPostingsCacheFileVersion, version)
+	}
+
+	numEntries := d.uvarint()
+	if d.err != nil {
+		return nil, d.err
+	}
+	if numEntries > uint64(len(d.data)) {
+		// Every entry takes at least one byte.
+		return nil, errPostingsCacheFileBadLength
+	}
+
+	entries := make([]IndexPostingsCacheEntry, 0, numEntries)
+	for i := uint64(0); i < numEntries; i++ {
+		var (
+			segmentIndex = d.uvarint()
+			queryType    = IndexPostingsCacheQueryType(d.uvarint())
+			field        = d.bytes()
+			pattern      = d.bytes()
+			pl           = d.bytes()
+		)
+		if d.err != nil {
+			return nil, d.err
+		}
+		if err := queryType.validate(); err != nil {
+			return nil, err
+		}
+
+		postingsList, err := pilosa.Unmarshal(pl)
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, IndexPostingsCacheEntry{
+			SegmentIndex: int(segmentIndex),
+			QueryType:    queryType,
+			Field:        field,
+			Pattern:      pattern,
+			PostingsList: postingsList,
+		})
+	}
+
+	if len(d.data) != 0 {
+		return nil, errPostingsCacheFileTrailing
+	}
+
+	return entries, nil
+}
+
+func indexPostingsCacheFilePath(prefix string, id FileSetFileIdentifier) string {
+	dir := NamespaceIndexDataDirPath(prefix, id.Namespace)
+	return filesetPathFromTimeAndIndex(dir, id.BlockStart, id.VolumeIndex,
+		postingsCacheFileSuffix)
+}
+
+type postingsCacheDecoder struct {
+	data []byte
+	err  error
+}
+
+func (d *postingsCacheDecoder) uvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+
+	v, n := binary.Uvarint(d.data)
+	if n <= 0 {
+		d.err = errPostingsCacheFileBadVarint
+		return 0
+	}
+	d.data = d.data[n:]
+	return v
+}
+
+func (d *postingsCacheDecoder) bytes() []byte {
+	size := d.uvarint()
+	if d.err != nil {
+		return nil
+	}
+
+	if size > uint64(len(d.data)) {
+		d.err = errPostingsCacheFileBadLength
+		return nil
+	}
+	b := d.data[:size]
+	d.data = d.data[size:]
+	return b
+}
@@ -0,0 +1,142 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fs
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/m3ninx/postings"
+	"github.com/m3db/m3/src/m3ninx/postings/roaring"
+	"github.com/m3db/m3/src/x/ident"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestPostingsList(t *testing.T, ids ...postings.ID) postings.List {
+	pl := roaring.NewPostingsList()
+	for _, id := range ids {
+		require.NoError(t, pl.Insert(id))
+	}
+	return pl
+}
+
+func TestIndexPostingsCacheReadWrite(t *testing.T) {
+	dir := createTempDir(t)
+	defer os.RemoveAll(dir)
+
+	var (
+		opts = testDefaultOpts.SetFilePathPrefix(dir)
+		id   = FileSetFileIdentifier{
+			FileSetContentType: persist.FileSetIndexContentType,
+			Namespace:          ident.StringID("metrics"),
+			BlockStart:         time.Unix(7200, 0),
+			VolumeIndex:        1,
+		}
+	)
+
+	// Volumes without a postings cache file have no entries.
+	entries, err := ReadIndexPostingsCache(opts, id)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+
+	written := []IndexPostingsCacheEntry{
+		{
+			SegmentIndex: 0,
+			QueryType:    IndexPostingsCacheRegexpQuery,
+			Field:        []byte("city"),
+			Pattern:      []byte("new.*"),
+			PostingsList: newTestPostingsList(t, 1, 5, 9),
+		},
+		{
+			SegmentIndex: 1,
+			QueryType:    IndexPostingsCacheTermQuery,
+			Field:        []byte("city"),
+			Pattern:      []byte("nyc"),
+			PostingsList: newTestPostingsList(t, 3),
+		},
+		{
+			SegmentIndex: 1,
+			QueryType:    IndexPostingsCacheFieldQuery,
+			Field:        []byte("dc"),
+			PostingsList: newTestPostingsList(t),
+		},
+	}
+	require.NoError(t, WriteIndexPostingsCache(opts, id, written))
+
+	entries, err = ReadIndexPostingsCache(opts, id)
+	require.NoError(t, err)
+	require.Len(t, entries, len(written))
+	for i, entry := range entries {
+		require.Equal(t, written[i].SegmentIndex, entry.SegmentIndex)
+		require.Equal(t, written[i].QueryType, entry.QueryType)
+		require.Equal(t, string(written[i].Field), string(entry.Field))
+		require.Equal(t, string(written[i].Pattern), string(entry.Pattern))
+		require.True(t, written[i].PostingsList.Equal(entry.PostingsList))
+	}
+
+	// Other volumes of the block are unaffected.
+	otherID := id
+	otherID.VolumeIndex = 0
+	entries, err = ReadIndexPostingsCache(opts, otherID)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
+
+func TestIndexPostingsCacheReadCorrupt(t *testing.T) {
+	dir := createTempDir(t)
+	defer os.RemoveAll(dir)
+
+	var (
+		opts = testDefaultOpts.SetFilePathPrefix(dir)
+		id   = FileSetFileIdentifier{
+			FileSetContentType: persist.FileSetIndexContentType,
+			Namespace:          ident.StringID("metrics"),
+			BlockStart:         time.Unix(7200, 0),
+		}
+	)
+	require.NoError(t, WriteIndexPostingsCache(opts, id, []IndexPostingsCacheEntry{
+		{
+			QueryType:    IndexPostingsCacheTermQuery,
+			Field:        []byte("city"),
+			Pattern:      []byte("nyc"),
+			PostingsList: newTestPostingsList(t, 3),
+		},
+	}))
+
+	path := indexPostingsCacheFilePath(dir, id)
+	data, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+
+	// Truncated files fail the checksum.
+	require.NoError(t, ioutil.WriteFile(path, data[:len(data)-1], opts.NewFileMode()))
+	_, err = ReadIndexPostingsCache(opts, id)
+	require.Error(t, err)
+
+	// As do files with flipped bits.
+	data[0] ^= 0xff
+	require.NoError(t, ioutil.WriteFile(path, data, opts.NewFileMode()))
+	_, err = ReadIndexPostingsCache(opts, id)
+	require.Equal(t, errPostingsCacheFileChecksum, err)
+}
@@ -24,8 +24,11 @@ import (
 	"errors"
 	"io"
 
+	"github.com/m3db/m3/src/dbnode/persist"
 	"github.com/m3db/m3/src/m3ninx/index/segment"
 	m3ninxpersist "github.com/m3db/m3/src/m3ninx/persist"
+
+	"go.uber.org/zap"
 )
 
 var (
@@ -47,7 +50,9 @@ type ReadIndexSegmentsOptions struct {
 	newPersistentSegmentFn newPersistentSegmentFn
 }
 
-// ReadIndexSegments will read a set of segments.
+// ReadIndexSegments will read a set of segments. The segments are returned as
+// IndexFileSetSegments carrying the postings lists persisted for them alongside
+// the volume, if any.
 func ReadIndexSegments(
 	opts ReadIndexSegmentsOptions,
 ) ([]segment.Segment, error) {
@@ -92,6 +97,7 @@ func ReadIndexSegments(
 	}
 	segments = make([]segment.Segment, 0, reader.SegmentFileSets())
 
+	cachedPostings := readIndexPostingsCacheBySegment(fsOpts, readerOpts)
 	for {
 		fileset, err := reader.ReadSegmentFileSet()
 		if err == io.EOF {
@@ -107,7 +113,9 @@ func ReadIndexSegments(
 			return nil, err
 		}
 
-		segments = append(segments, seg)
+		segmentIndex := len(segments)
+		segments = append(segments, NewIndexFileSetSegment(seg,
+			readerOpts.Identifier, segmentIndex, cachedPostings[segmentIndex]))
 	}
 
 	// Indicate we don't need the defer() above to release any resources, as we are
@@ -115,3 +123,32 @@ func ReadIndexSegments(
 	success = true
 	return segments, nil
 }
+
+// readIndexPostingsCacheBySegment returns the postings lists persisted alongside
+// an index fileset volume grouped by segment. The postings cache is an optimization
+// so failing to read it is logged rather than failing the read of the segments.
+func readIndexPostingsCacheBySegment(
+	opts Options,
+	readerOpts IndexReaderOpenOptions,
+) map[int][]IndexPostingsCacheEntry {
+	if readerOpts.FileSetType != persist.FileSetFlushType {
+		// Only the postings lists of flushed volumes are persisted.
+		return nil
+	}
+
+	entries, err := ReadIndexPostingsCache(opts, readerOpts.Identifier)
+	if err != nil {
+		opts.InstrumentOptions().Logger().Warn("unable to read index postings cache",
+			zap.Stringer("namespace", readerOpts.Identifier.Namespace),
+			zap.Time("blockStart", readerOpts.Identifier.BlockStart),
+			zap.Int("volumeIndex", readerOpts.Identifier.VolumeIndex),
+			zap.Error(err))
+		return nil
+	}
+
+	bySegment := make(map[int][]IndexPostingsCacheEntry)
+	for _, entry := range entries {
+		bySegment[entry.SegmentIndex] = append(bySegment[entry.SegmentIndex], entry)
+	}
+	return bySegment
+}
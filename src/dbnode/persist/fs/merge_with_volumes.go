@@ -0,0 +1,321 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fs
+
+import (
+	"errors"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/storage/index/convert"
+	"github.com/m3db/m3/src/dbnode/ts"
+	"github.com/m3db/m3/src/dbnode/x/xio"
+	"github.com/m3db/m3/src/x/checked"
+	"github.com/m3db/m3/src/x/context"
+	xerrors "github.com/m3db/m3/src/x/errors"
+	"github.com/m3db/m3/src/x/ident"
+	"github.com/m3db/m3/src/x/pool"
+	xtime "github.com/m3db/m3/src/x/time"
+)
+
+var errMergeWithVolumesNoVolumes = errors.New("no volumes to merge with")
+
+// VolumesMergeWith is a MergeWith whose merge target is the data of other
+// volumes of a block, it must be closed once the merge is done.
+type VolumesMergeWith interface {
+	MergeWith
+	io.Closer
+}
+
+// NewVolumesMergeWithFn is the function to call to get a new VolumesMergeWith.
+type NewVolumesMergeWithFn func(opts VolumesMergeWithOptions) (VolumesMergeWith, error)
+
+// VolumesMergeWithOptions are the options for a VolumesMergeWith.
+type VolumesMergeWithOptions struct {
+	// Volumes are the volumes of the block to merge with.
+	Volumes FileSetFilesSlice
+	// BlockSize is the block size of the namespace.
+	BlockSize time.Duration
+	// BytesPool is the pool used to read data from the volumes.
+	BytesPool pool.CheckedBytesPool
+	// IdentifierPool is the pool used for the IDs and tags of the series.
+	IdentifierPool ident.Pool
+	// DeletedAtFn returns the time a series was deleted, if it has been.
+	DeletedAtFn func(seriesID ident.ID) (time.Time, bool)
+	// Options are the filesystem options.
+	Options Options
+}
+
+type mergeVolume struct {
+	file      FileSetFile
+	writtenAt time.Time
+	seeker    DataFileSetSeeker
+	resources ReusableSeekerResources
+}
+
+// volumesMergeWith implements VolumesMergeWith. Merging the oldest volume of
+// a block with the newer volumes of the block compacts all of them into a
+// single volume, since the data of the newer volumes is read last it takes
+// precedence over data of older volumes for the same timestamps.
+type volumesMergeWith struct {
+	opts    VolumesMergeWithOptions
+	volumes []mergeVolume
+	handled map[string]struct{}
+
+	// IDs and tags passed to ForEachRemaining must remain valid until the
+	// merge is done.
+	idsToFinalize  []ident.ID
+	tagsToFinalize []ident.Tags
+}
+
+// NewVolumesMergeWith returns a new VolumesMergeWith that merges with the
+// given volumes of a block, which are read from in order of volume index.
+func NewVolumesMergeWith(opts VolumesMergeWithOptions) (VolumesMergeWith, error) {
+	if len(opts.Volumes) == 0 {
+		return nil, errMergeWithVolumesNoVolumes
+	}
+
+	files := append(FileSetFilesSlice(nil), opts.Volumes...)
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].ID.VolumeIndex < files[j].ID.VolumeIndex
+	})
+
+	m := &volumesMergeWith{
+		opts:    opts,
+		volumes: make([]mergeVolume, 0, len(files)),
+		handled: make(map[string]struct{}),
+	}
+	for _, file := range files {
+		writtenAt, err := file.CheckpointWrittenAt()
+		if err != nil {
+			m.Close()
+			return nil, err
+		}
+
+		var (
+			fsOpts = opts.Options
+			seeker = NewSeeker(file.filePathPrefix, fsOpts.DataReaderBufferSize(),
+				fsOpts.InfoReaderBufferSize(), opts.BytesPool, false, fsOpts)
+			resources = NewReusableSeekerResources(fsOpts)
+		)
+		if err := seeker.Open(file.ID.Namespace, file.ID.Shard,
+			file.ID.BlockStart, file.ID.VolumeIndex, resources); err != nil {
+			m.Close()
+			return nil, err
+		}
+
+		m.volumes = append(m.volumes, mergeVolume{
+			file:      file,
+			writtenAt: writtenAt,
+			seeker:    seeker,
+			resources: resources,
+		})
+	}
+
+	return m, nil
+}
+
+func (m *volumesMergeWith) Read(
+	ctx context.Context,
+	seriesID ident.ID,
+	blockStart xtime.UnixNano,
+	nsCtx namespace.Context,
+) ([]xio.BlockReader, bool, error) {
+	m.handled[seriesID.String()] = struct{}{}
+	blocks, err := m.seekBlocks(ctx, seriesID, blockStart, m.volumes, nil)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return blocks, len(blocks) > 0, nil
+}
+
+func (m *volumesMergeWith) ForEachRemaining(
+	ctx context.Context,
+	blockStart xtime.UnixNano,
+	fn ForEachRemainingFn,
+	nsCtx namespace.Context,
+) error {
+	// Series that are not in the volume being merged are read from the volume
+	// they first appear in and sought in the newer volumes.
+	for i := range m.volumes {
+		if err := m.forEachRemainingInVolume(ctx, blockStart, i, fn); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *volumesMergeWith) forEachRemainingInVolume(
+	ctx context.Context,
+	blockStart xtime.UnixNano,
+	i int,
+	fn ForEachRemainingFn,
+) (err error) {
+	volume := m.volumes[i]
+	readerOpts := m.opts.Options.SetFilePathPrefix(volume.file.filePathPrefix)
+	reader, err := NewReader(m.opts.BytesPool, readerOpts)
+	if err != nil {
+		return err
+	}
+	if err := reader.Open(DataReaderOpenOptions{
+		Identifier:  volume.file.ID,
+		FileSetType: persist.FileSetFlushType,
+	}); err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := reader.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	for {
+		id, tagsIter, data, _, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if _, ok := m.handled[id.String()]; ok {
+			id.Finalize()
+			tagsIter.Close()
+			data.Finalize()
+			continue
+		}
+		m.handled[id.String()] = struct{}{}
+		m.idsToFinalize = append(m.idsToFinalize, id)
+
+		tags, err := convert.TagsFromTagsIter(id, tagsIter, m.opts.IdentifierPool)
+		tagsIter.Close()
+		if err != nil {
+			data.Finalize()
+			return err
+		}
+		m.tagsToFinalize = append(m.tagsToFinalize, tags)
+
+		var blocks []xio.BlockReader
+		if m.includeVolume(id, volume) {
+			blocks = append(blocks, m.blockReader(ctx, data, blockStart))
+		} else {
+			data.Finalize()
+		}
+
+		blocks, err = m.seekBlocks(ctx, id, blockStart, m.volumes[i+1:], blocks)
+		if err != nil {
+			return err
+		}
+		if len(blocks) == 0 {
+			continue
+		}
+
+		if err := fn(id, tags, blocks); err != nil {
+			return err
+		}
+	}
+}
+
+// seekBlocks appends the data of the series in each of the given volumes.
+func (m *volumesMergeWith) seekBlocks(
+	ctx context.Context,
+	seriesID ident.ID,
+	blockStart xtime.UnixNano,
+	volumes []mergeVolume,
+	blocks []xio.BlockReader,
+) ([]xio.BlockReader, error) {
+	for _, volume := range volumes {
+		if !m.includeVolume(seriesID, volume) {
+			continue
+		}
+
+		bloomFilter := volume.seeker.ConcurrentIDBloomFilter()
+		if !bloomFilter.Test(seriesID.Bytes()) {
+			continue
+		}
+
+		data, err := volume.seeker.SeekByID(seriesID, volume.resources)
+		if err == errSeekIDNotFound {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		blocks = append(blocks, m.blockReader(ctx, data, blockStart))
+	}
+
+	return blocks, nil
+}
+
+// includeVolume returns whether data of the series in the volume should be
+// merged, data of a series deleted after the volume was written is dropped
+// since merges only drop the data of deleted series written before then.
+func (m *volumesMergeWith) includeVolume(seriesID ident.ID, volume mergeVolume) bool {
+	deletedAt, ok := m.DeletedAt(seriesID)
+	return !ok || deletedAt.Before(volume.writtenAt)
+}
+
+func (m *volumesMergeWith) blockReader(
+	ctx context.Context,
+	data checked.Bytes,
+	blockStart xtime.UnixNano,
+) xio.BlockReader {
+	reader := xio.NewSegmentReader(ts.NewSegment(data, nil, ts.FinalizeHead))
+	// The data is finalized once the merger has persisted the series.
+	ctx.RegisterFinalizer(reader)
+	return xio.BlockReader{
+		SegmentReader: reader,
+		Start:         blockStart.ToTime(),
+		BlockSize:     m.opts.BlockSize,
+	}
+}
+
+func (m *volumesMergeWith) DeletedAt(seriesID ident.ID) (time.Time, bool) {
+	if m.opts.DeletedAtFn == nil {
+		return time.Time{}, false
+	}
+	return m.opts.DeletedAtFn(seriesID)
+}
+
+func (m *volumesMergeWith) Close() error {
+	multiErr := xerrors.NewMultiError()
+	for _, volume := range m.volumes {
+		multiErr = multiErr.Add(volume.seeker.Close())
+	}
+	m.volumes = nil
+
+	for _, id := range m.idsToFinalize {
+		id.Finalize()
+	}
+	m.idsToFinalize = nil
+	for _, tags := range m.tagsToFinalize {
+		tags.Finalize()
+	}
+	m.tagsToFinalize = nil
+
+	return multiErr.FinalError()
+}
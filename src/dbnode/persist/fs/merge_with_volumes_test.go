@@ -0,0 +1,161 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fs
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/digest"
+	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/ts"
+	"github.com/m3db/m3/src/x/ident"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTestVolume(
+	t *testing.T,
+	filePathPrefix string,
+	volume int,
+	data *checkedBytesMap,
+) {
+	w := newTestWriter(t, filePathPrefix)
+	err := w.Open(DataWriterOpenOptions{
+		Identifier: FileSetFileIdentifier{
+			Namespace:   testNs1ID,
+			BlockStart:  startTime,
+			VolumeIndex: volume,
+		},
+		BlockSize:   testBlockSize,
+		FileSetType: persist.FileSetFlushType,
+	})
+	require.NoError(t, err)
+
+	for _, entry := range data.Iter() {
+		bytes := entry.Value().Bytes()
+		require.NoError(t, w.Write(entry.Key(), ident.Tags{},
+			bytesRefd(bytes), digest.Checksum(bytes)))
+	}
+	require.NoError(t, w.Close())
+}
+
+func TestMergeWithVolumes(t *testing.T) {
+	dir := createTempDir(t)
+	defer os.RemoveAll(dir)
+
+	// Volumes are written in order, the data of newer volumes takes
+	// precedence and id3 is deleted after all of the volumes were written.
+	volumes := []*checkedBytesMap{
+		newCheckedBytesByIDMap(newCheckedBytesByIDMapOptions{}),
+		newCheckedBytesByIDMap(newCheckedBytesByIDMapOptions{}),
+		newCheckedBytesByIDMap(newCheckedBytesByIDMapOptions{}),
+	}
+	volumes[0].Set(id0, datapointsToCheckedBytes(t, []ts.Datapoint{
+		{Timestamp: startTime.Add(0 * time.Second), Value: 0},
+		{Timestamp: startTime.Add(1 * time.Second), Value: 1},
+	}))
+	volumes[0].Set(id1, datapointsToCheckedBytes(t, []ts.Datapoint{
+		{Timestamp: startTime.Add(2 * time.Second), Value: 2},
+	}))
+	volumes[1].Set(id0, datapointsToCheckedBytes(t, []ts.Datapoint{
+		{Timestamp: startTime.Add(1 * time.Second), Value: 10},
+		{Timestamp: startTime.Add(3 * time.Second), Value: 3},
+	}))
+	volumes[1].Set(id2, datapointsToCheckedBytes(t, []ts.Datapoint{
+		{Timestamp: startTime.Add(4 * time.Second), Value: 4},
+	}))
+	volumes[2].Set(id2, datapointsToCheckedBytes(t, []ts.Datapoint{
+		{Timestamp: startTime.Add(5 * time.Second), Value: 5},
+	}))
+	volumes[2].Set(id3, datapointsToCheckedBytes(t, []ts.Datapoint{
+		{Timestamp: startTime.Add(6 * time.Second), Value: 6},
+	}))
+	for i, data := range volumes {
+		writeTestVolume(t, dir, i, data)
+	}
+
+	expected := newCheckedBytesByIDMap(newCheckedBytesByIDMapOptions{})
+	expected.Set(id0, datapointsToCheckedBytes(t, []ts.Datapoint{
+		{Timestamp: startTime.Add(0 * time.Second), Value: 0},
+		{Timestamp: startTime.Add(1 * time.Second), Value: 10},
+		{Timestamp: startTime.Add(3 * time.Second), Value: 3},
+	}))
+	expected.Set(id1, datapointsToCheckedBytes(t, []ts.Datapoint{
+		{Timestamp: startTime.Add(2 * time.Second), Value: 2},
+	}))
+	expected.Set(id2, datapointsToCheckedBytes(t, []ts.Datapoint{
+		{Timestamp: startTime.Add(4 * time.Second), Value: 4},
+		{Timestamp: startTime.Add(5 * time.Second), Value: 5},
+	}))
+
+	files, err := DataFiles(dir, testNs1ID, 0)
+	require.NoError(t, err)
+	require.Equal(t, 3, len(files))
+
+	fsOpts := testDefaultOpts.SetFilePathPrefix(dir)
+	mergeWith, err := NewVolumesMergeWith(VolumesMergeWithOptions{
+		Volumes:        files[1:],
+		BlockSize:      blockSize,
+		BytesPool:      testBytesPool,
+		IdentifierPool: identPool,
+		DeletedAtFn: func(id ident.ID) (time.Time, bool) {
+			if id.Equal(id3) {
+				return startTime.Add(blockSize), true
+			}
+			return time.Time{}, false
+		},
+		Options: fsOpts,
+	})
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, mergeWith.Close())
+	}()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var persisted []persistedData
+	preparer := persist.NewMockFlushPreparer(ctrl)
+	preparer.EXPECT().PrepareData(gomock.Any()).Return(
+		persist.PreparedDataPersist{
+			Persist: func(id ident.ID, tags ident.Tags, segment ts.Segment, checksum uint32) error {
+				persisted = append(persisted, persistedData{
+					id:      id,
+					segment: segment.Clone(nil),
+				})
+				return nil
+			},
+			Close: func() error { return nil },
+		}, nil)
+
+	reader, err := NewReader(testBytesPool, fsOpts)
+	require.NoError(t, err)
+	merger := NewMerger(reader, 0, srPool, multiIterPool, identPool,
+		encoderPool, contextPool, namespace.NewOptions(), time.Now)
+	err = merger.Merge(files[0].ID, mergeWith, 3, preparer, namespace.Context{})
+	require.NoError(t, err)
+
+	assertPersistedAsExpected(t, persisted, expected)
+}
@@ -24,10 +24,12 @@ import (
 	"io"
 	"time"
 
+	"github.com/m3db/m3/src/dbnode/clock"
 	"github.com/m3db/m3/src/dbnode/digest"
 	"github.com/m3db/m3/src/dbnode/encoding"
 	"github.com/m3db/m3/src/dbnode/namespace"
 	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/retention"
 	"github.com/m3db/m3/src/dbnode/storage/index/convert"
 	"github.com/m3db/m3/src/dbnode/ts"
 	"github.com/m3db/m3/src/dbnode/x/xio"
@@ -46,6 +48,7 @@ type merger struct {
 	encoderPool    encoding.EncoderPool
 	contextPool    context.Pool
 	nsOpts         namespace.Options
+	nowFn          clock.NowFn
 }
 
 // NewMerger returns a new Merger. This implementation is in charge of merging
@@ -57,6 +60,9 @@ type merger struct {
 // persisted since it just uses the flushPreparer that is passed in. Further,
 // it does not signal to the database of the existence of the newly persisted
 // data, nor does it clean up the original fileset.
+//
+// Series with a TTL (see retention.SeriesTTLTagName) that has expired for the
+// whole block are dropped from the merged data.
 func NewMerger(
 	reader DataFileSetReader,
 	blockAllocSize int,
@@ -66,6 +72,7 @@ func NewMerger(
 	encoderPool encoding.EncoderPool,
 	contextPool context.Pool,
 	nsOpts namespace.Options,
+	nowFn clock.NowFn,
 ) Merger {
 	return &merger{
 		reader:         reader,
@@ -76,6 +83,7 @@ func NewMerger(
 		encoderPool:    encoderPool,
 		contextPool:    contextPool,
 		nsOpts:         nsOpts,
+		nowFn:          nowFn,
 	}
 }
 
@@ -104,6 +112,7 @@ func (m *merger) Merge(
 		volume     = fileID.VolumeIndex
 		blockSize  = nsOpts.RetentionOptions().BlockSize()
 		blockStart = xtime.ToUnixNano(startTime)
+		now        = m.nowFn()
 		openOpts   = DataReaderOpenOptions{
 			Identifier: FileSetFileIdentifier{
 				Namespace:   nsID,
@@ -111,7 +120,8 @@ func (m *merger) Merge(
 				BlockStart:  startTime,
 				VolumeIndex: volume,
 			},
-			FileSetType: persist.FileSetFlushType,
+			FileSetType:            persist.FileSetFlushType,
+			ColdTierFilePathPrefix: nsOpts.ColdTierOptions().FilePathPrefix(),
 		}
 	)
 
@@ -202,8 +212,20 @@ func (m *merger) Merge(
 		}
 		idsToFinalize = append(idsToFinalize, id)
 
+		// If the series was deleted then the data on disk written before the
+		// deletion is dropped, if the deletion happened within this block the
+		// data is filtered by timestamp instead of dropped.
+		deleted, deletedFrom := m.seriesBlockDeleted(mergeWith, id, startTime, blockSize)
+
+		// If the TTL of the series has expired for the block then all of its
+		// data for the block is dropped, the merge target is still read from
+		// so that the series is not handled again in the second stage.
+		expired := m.seriesBlockExpired(tagsIter, startTime, blockSize, now)
+
 		segmentReaders = segmentReaders[:0]
-		segmentReaders = append(segmentReaders, segmentReaderFromData(data, segReader))
+		if !deleted && !expired {
+			segmentReaders = append(segmentReaders, segmentReaderFromData(data, segReader))
+		}
 
 		// Check if this series is in memory (and thus requires merging).
 		ctx.Reset()
@@ -211,9 +233,15 @@ func (m *merger) Merge(
 		if err != nil {
 			return err
 		}
-		if hasInMemoryData {
+		if hasInMemoryData && !expired {
 			segmentReaders = appendBlockReadersToSegmentReaders(segmentReaders, mergeWithData)
 		}
+		if len(segmentReaders) == 0 {
+			tagsIter.Close()
+			// NB(r): Make sure to use BlockingCloseReset so can reuse the context.
+			ctx.BlockingCloseReset()
+			continue
+		}
 
 		// tagsIter is never nil. These tags will be valid as long as the IDs
 		// are valid, and the IDs are valid for the duration of the file writing.
@@ -227,7 +255,7 @@ func (m *merger) Merge(
 		// In the special (but common) case that we're just copying the series data from the old file
 		// into the new one without merging or adding any additional data we can avoid recalculating
 		// the checksum.
-		if len(segmentReaders) == 1 && hasInMemoryData == false {
+		if len(segmentReaders) == 1 && hasInMemoryData == false && deletedFrom.IsZero() {
 			segment, err := segmentReaders[0].Segment()
 			if err != nil {
 				return err
@@ -237,7 +265,8 @@ func (m *merger) Merge(
 				return err
 			}
 		} else {
-			if err := persistSegmentReaders(id, tags, segmentReaders, iterResources, prepared.Persist); err != nil {
+			if err := persistSegmentReadersFrom(id, tags, segmentReaders, iterResources,
+				deletedFrom, prepared.Persist); err != nil {
 				return err
 			}
 		}
@@ -253,6 +282,11 @@ func (m *merger) Merge(
 	err = mergeWith.ForEachRemaining(
 		ctx, blockStart,
 		func(id ident.ID, tags ident.Tags, mergeWithData []xio.BlockReader) error {
+			if m.seriesBlockExpired(ident.NewTagsIterator(tags), startTime, blockSize, now) {
+				// NB(r): Make sure to use BlockingCloseReset so can reuse the context.
+				ctx.BlockingCloseReset()
+				return nil
+			}
 			segmentReaders = segmentReaders[:0]
 			segmentReaders = appendBlockReadersToSegmentReaders(segmentReaders, mergeWithData)
 			err := persistSegmentReaders(id, tags, segmentReaders, iterResources, prepared.Persist)
@@ -273,6 +307,42 @@ func (m *merger) Merge(
 	return prepared.Close()
 }
 
+// seriesBlockDeleted returns whether all the data of the series for the block
+// has been deleted, or otherwise the time before which datapoints of the block
+// have been deleted if the series was deleted within the block.
+func (m *merger) seriesBlockDeleted(
+	mergeWith MergeWith,
+	id ident.ID,
+	blockStart time.Time,
+	blockSize time.Duration,
+) (bool, time.Time) {
+	deletedAt, ok := mergeWith.DeletedAt(id)
+	if !ok || !deletedAt.After(blockStart) {
+		return false, time.Time{}
+	}
+	if !deletedAt.Before(blockStart.Add(blockSize)) {
+		return true, time.Time{}
+	}
+	return false, deletedAt
+}
+
+// seriesBlockExpired returns whether the series with the given tags has a TTL
+// that has expired for the whole block.
+func (m *merger) seriesBlockExpired(
+	tags ident.TagIterator,
+	blockStart time.Time,
+	blockSize time.Duration,
+	now time.Time,
+) bool {
+	ttl, ok, err := retention.SeriesTTL(tags)
+	if err != nil || !ok {
+		// NB: Series with an invalid TTL are rejected on write, so this can
+		// only be data written before the TTL tag was reserved which is kept.
+		return false
+	}
+	return retention.SeriesBlockExpired(ttl, blockStart, blockSize, now)
+}
+
 func appendBlockReadersToSegmentReaders(segReaders []xio.SegmentReader, brs []xio.BlockReader) []xio.SegmentReader {
 	for _, br := range brs {
 		segReaders = append(segReaders, br.SegmentReader)
@@ -295,16 +365,29 @@ func persistSegmentReaders(
 	segReaders []xio.SegmentReader,
 	ir iterResources,
 	persistFn persist.DataFn,
+) error {
+	return persistSegmentReadersFrom(id, tags, segReaders, ir, time.Time{}, persistFn)
+}
+
+// persistSegmentReadersFrom persists the merged segment readers, dropping any
+// datapoints before the given time if it is set.
+func persistSegmentReadersFrom(
+	id ident.ID,
+	tags ident.Tags,
+	segReaders []xio.SegmentReader,
+	ir iterResources,
+	from time.Time,
+	persistFn persist.DataFn,
 ) error {
 	if len(segReaders) == 0 {
 		return nil
 	}
 
-	if len(segReaders) == 1 {
+	if len(segReaders) == 1 && from.IsZero() {
 		return persistSegmentReader(id, tags, segReaders[0], persistFn)
 	}
 
-	return persistIter(id, tags, segReaders, ir, persistFn)
+	return persistIter(id, tags, segReaders, ir, from, persistFn)
 }
 
 func persistIter(
@@ -312,6 +395,7 @@ func persistIter(
 	tags ident.Tags,
 	segReaders []xio.SegmentReader,
 	ir iterResources,
+	from time.Time,
 	persistFn persist.DataFn,
 ) error {
 	it := ir.multiIter
@@ -319,7 +403,11 @@ func persistIter(
 	encoder := ir.encoderPool.Get()
 	encoder.Reset(ir.blockStart, ir.blockAllocSize, ir.schema)
 	for it.Next() {
-		if err := encoder.Encode(it.Current()); err != nil {
+		dp, unit, annotation := it.Current()
+		if dp.Timestamp.Before(from) {
+			continue
+		}
+		if err := encoder.Encode(dp, unit, annotation); err != nil {
 			encoder.Close()
 			return err
 		}
@@ -328,6 +416,11 @@ func persistIter(
 		encoder.Close()
 		return err
 	}
+	if encoder.NumEncoded() == 0 {
+		// All the datapoints of the series were deleted.
+		encoder.Close()
+		return nil
+	}
 
 	segment := encoder.Discard()
 	return persistSegment(id, tags, segment, persistFn)
@@ -30,6 +30,7 @@ import (
 	"github.com/m3db/m3/src/dbnode/encoding/m3tsz"
 	"github.com/m3db/m3/src/dbnode/namespace"
 	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/retention"
 	"github.com/m3db/m3/src/dbnode/ts"
 	"github.com/m3db/m3/src/dbnode/x/xio"
 	"github.com/m3db/m3/src/x/checked"
@@ -43,6 +44,7 @@ import (
 
 const (
 	blockSize = time.Hour
+	seriesTTL = time.Hour
 )
 
 var (
@@ -425,15 +427,142 @@ func TestMergeWithNoData(t *testing.T) {
 	testMergeWith(t, diskData, mergeTargetData, expected)
 }
 
+func TestMergeWithDeletedSeries(t *testing.T) {
+	// This test scenario is when series on disk have been deleted, only data
+	// for deleted series in the merge target should be persisted.
+	diskData := newCheckedBytesByIDMap(newCheckedBytesByIDMapOptions{})
+	diskData.Set(id0, datapointsToCheckedBytes(t, []ts.Datapoint{
+		{Timestamp: startTime.Add(0 * time.Second), Value: 0},
+		{Timestamp: startTime.Add(1 * time.Second), Value: 1},
+	}))
+	diskData.Set(id1, datapointsToCheckedBytes(t, []ts.Datapoint{
+		{Timestamp: startTime.Add(2 * time.Second), Value: 2},
+		{Timestamp: startTime.Add(3 * time.Second), Value: 3},
+	}))
+	diskData.Set(id2, datapointsToCheckedBytes(t, []ts.Datapoint{
+		{Timestamp: startTime.Add(4 * time.Second), Value: 4},
+	}))
+
+	mergeTargetData := newCheckedBytesByIDMap(newCheckedBytesByIDMapOptions{})
+	mergeTargetData.Set(id1, datapointsToCheckedBytes(t, []ts.Datapoint{
+		{Timestamp: startTime.Add(5 * time.Second), Value: 5},
+	}))
+
+	expected := newCheckedBytesByIDMap(newCheckedBytesByIDMapOptions{})
+	expected.Set(id1, datapointsToCheckedBytes(t, []ts.Datapoint{
+		{Timestamp: startTime.Add(5 * time.Second), Value: 5},
+	}))
+	expected.Set(id2, datapointsToCheckedBytes(t, []ts.Datapoint{
+		{Timestamp: startTime.Add(4 * time.Second), Value: 4},
+	}))
+
+	deleted := map[string]time.Time{
+		id0.String(): startTime.Add(blockSize),
+		id1.String(): startTime.Add(blockSize),
+	}
+	testMergeWithDeleted(t, diskData, mergeTargetData, expected, deleted)
+}
+
+func TestMergeWithSeriesDeletedWithinBlock(t *testing.T) {
+	// This test scenario is when series have been deleted within the block,
+	// only data at or after the deletion time should be persisted.
+	diskData := newCheckedBytesByIDMap(newCheckedBytesByIDMapOptions{})
+	diskData.Set(id0, datapointsToCheckedBytes(t, []ts.Datapoint{
+		{Timestamp: startTime.Add(0 * time.Second), Value: 0},
+		{Timestamp: startTime.Add(1 * time.Second), Value: 1},
+	}))
+	diskData.Set(id1, datapointsToCheckedBytes(t, []ts.Datapoint{
+		{Timestamp: startTime.Add(2 * time.Second), Value: 2},
+		{Timestamp: startTime.Add(3 * time.Second), Value: 3},
+		{Timestamp: startTime.Add(4 * time.Second), Value: 4},
+	}))
+
+	mergeTargetData := newCheckedBytesByIDMap(newCheckedBytesByIDMapOptions{})
+	mergeTargetData.Set(id0, datapointsToCheckedBytes(t, []ts.Datapoint{
+		{Timestamp: startTime.Add(5 * time.Second), Value: 5},
+	}))
+
+	expected := newCheckedBytesByIDMap(newCheckedBytesByIDMapOptions{})
+	expected.Set(id0, datapointsToCheckedBytes(t, []ts.Datapoint{
+		{Timestamp: startTime.Add(5 * time.Second), Value: 5},
+	}))
+	expected.Set(id1, datapointsToCheckedBytes(t, []ts.Datapoint{
+		{Timestamp: startTime.Add(3 * time.Second), Value: 3},
+		{Timestamp: startTime.Add(4 * time.Second), Value: 4},
+	}))
+
+	deleted := map[string]time.Time{
+		id0.String(): startTime.Add(5 * time.Second),
+		id1.String(): startTime.Add(3 * time.Second),
+	}
+	testMergeWithDeleted(t, diskData, mergeTargetData, expected, deleted)
+}
+
+func TestMergeWithExpiredSeries(t *testing.T) {
+	// This test scenario is when series have a TTL that has expired for the
+	// block, none of the data for expired series should be persisted.
+	diskData := newCheckedBytesByIDMap(newCheckedBytesByIDMapOptions{})
+	diskData.Set(id0, datapointsToCheckedBytes(t, []ts.Datapoint{
+		{Timestamp: startTime.Add(0 * time.Second), Value: 0},
+		{Timestamp: startTime.Add(1 * time.Second), Value: 1},
+	}))
+	diskData.Set(id1, datapointsToCheckedBytes(t, []ts.Datapoint{
+		{Timestamp: startTime.Add(2 * time.Second), Value: 2},
+	}))
+
+	mergeTargetData := newCheckedBytesByIDMap(newCheckedBytesByIDMapOptions{})
+	mergeTargetData.Set(id0, datapointsToCheckedBytes(t, []ts.Datapoint{
+		{Timestamp: startTime.Add(3 * time.Second), Value: 3},
+	}))
+	mergeTargetData.Set(id2, datapointsToCheckedBytes(t, []ts.Datapoint{
+		{Timestamp: startTime.Add(4 * time.Second), Value: 4},
+	}))
+	mergeTargetData.Set(id3, datapointsToCheckedBytes(t, []ts.Datapoint{
+		{Timestamp: startTime.Add(5 * time.Second), Value: 5},
+	}))
+
+	expected := newCheckedBytesByIDMap(newCheckedBytesByIDMapOptions{})
+	expected.Set(id1, datapointsToCheckedBytes(t, []ts.Datapoint{
+		{Timestamp: startTime.Add(2 * time.Second), Value: 2},
+	}))
+	expected.Set(id3, datapointsToCheckedBytes(t, []ts.Datapoint{
+		{Timestamp: startTime.Add(5 * time.Second), Value: 5},
+	}))
+
+	expired := []ident.ID{id0, id2}
+	testMergeWithDeletedAndExpired(t, diskData, mergeTargetData, expected, nil, expired)
+}
+
 func testMergeWith(
 	t *testing.T,
 	diskData *checkedBytesMap,
 	mergeTargetData *checkedBytesMap,
 	expectedData *checkedBytesMap,
+) {
+	testMergeWithDeleted(t, diskData, mergeTargetData, expectedData, nil)
+}
+
+func testMergeWithDeleted(
+	t *testing.T,
+	diskData *checkedBytesMap,
+	mergeTargetData *checkedBytesMap,
+	expectedData *checkedBytesMap,
+	deleted map[string]time.Time,
+) {
+	testMergeWithDeletedAndExpired(t, diskData, mergeTargetData, expectedData, deleted, nil)
+}
+
+func testMergeWithDeletedAndExpired(
+	t *testing.T,
+	diskData *checkedBytesMap,
+	mergeTargetData *checkedBytesMap,
+	expectedData *checkedBytesMap,
+	deleted map[string]time.Time,
+	expired []ident.ID,
 ) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
-	reader := mockReaderFromData(ctrl, diskData)
+	reader := mockReaderFromData(ctrl, diskData, expired)
 
 	var persisted []persistedData
 	preparer := persist.NewMockFlushPreparer(ctrl)
@@ -454,14 +583,17 @@ func testMergeWith(
 	nsCtx := namespace.Context{}
 
 	nsOpts := namespace.NewOptions()
+	nowFn := func() time.Time {
+		return startTime.Add(blockSize).Add(seriesTTL)
+	}
 	merger := NewMerger(reader, 0, srPool, multiIterPool,
-		identPool, encoderPool, contextPool, nsOpts)
+		identPool, encoderPool, contextPool, nsOpts, nowFn)
 	fsID := FileSetFileIdentifier{
 		Namespace:  ident.StringID("test-ns"),
 		Shard:      uint32(8),
 		BlockStart: startTime,
 	}
-	mergeWith := mockMergeWithFromData(t, ctrl, diskData, mergeTargetData)
+	mergeWith := mockMergeWithFromData(t, ctrl, diskData, mergeTargetData, deleted, expired)
 	err := merger.Merge(fsID, mergeWith, 1, preparer, nsCtx)
 	require.NoError(t, err)
 
@@ -517,18 +649,19 @@ func datapointsToCheckedBytes(t *testing.T, dps []ts.Datapoint) checked.Bytes {
 func mockReaderFromData(
 	ctrl *gomock.Controller,
 	diskData *checkedBytesMap,
+	expired []ident.ID,
 ) *MockDataFileSetReader {
 	reader := NewMockDataFileSetReader(ctrl)
 	reader.EXPECT().Open(gomock.Any()).Return(nil)
 	reader.EXPECT().Entries().Return(diskData.Len()).Times(2)
 	reader.EXPECT().Close().Return(nil)
-	tagIter := ident.NewTagsIterator(ident.NewTags(ident.StringTag("tag-key0", "tag-val0")))
 	fakeChecksum := uint32(42)
 
 	var inOrderCalls []*gomock.Call
 	for _, val := range diskData.Iter() {
 		id := val.Key()
 		data := val.Value()
+		tagIter := ident.NewTagsIterator(testMergeTags(id, expired))
 		inOrderCalls = append(inOrderCalls,
 			reader.EXPECT().Read().Return(id, tagIter, data, fakeChecksum, nil))
 	}
@@ -545,6 +678,8 @@ func mockMergeWithFromData(
 	ctrl *gomock.Controller,
 	diskData *checkedBytesMap,
 	mergeTargetData *checkedBytesMap,
+	deleted map[string]time.Time,
+	expired []ident.ID,
 ) *MockMergeWith {
 	mergeWith := NewMockMergeWith(ctrl)
 
@@ -558,6 +693,9 @@ func mockMergeWithFromData(
 	for _, val := range diskData.Iter() {
 		id := val.Key()
 
+		deletedAt, isDeleted := deleted[id.String()]
+		mergeWith.EXPECT().DeletedAt(id).Return(deletedAt, isDeleted)
+
 		if mergeTargetData.Contains(id) {
 			data, ok := mergeTargetData.Get(id)
 			require.True(t, ok)
@@ -588,7 +726,7 @@ func mockMergeWithFromData(
 				if ok {
 					segReader := srPool.Get()
 					br := []xio.BlockReader{blockReaderFromData(data, segReader, startTime, blockSize)}
-					fn(id, ident.Tags{}, br)
+					fn(id, testMergeTags(id, expired), br)
 				}
 			}
 		})
@@ -596,6 +734,19 @@ func mockMergeWithFromData(
 	return mergeWith
 }
 
+// testMergeTags returns the tags of a series, with a TTL tag for the series
+// that are expired.
+func testMergeTags(id ident.ID, expired []ident.ID) ident.Tags {
+	for _, expiredID := range expired {
+		if expiredID.Equal(id) {
+			return ident.NewTags(
+				ident.StringTag("tag-key0", "tag-val0"),
+				ident.StringTag(retention.SeriesTTLTagName, seriesTTL.String()))
+		}
+	}
+	return ident.NewTags(ident.StringTag("tag-key0", "tag-val0"))
+}
+
 type persistedData struct {
 	id      ident.ID
 	segment ts.Segment
@@ -404,7 +404,7 @@ func (dec *Decoder) decodeIndexSummary() (schema.IndexSummary, IndexSummaryToken
 }
 
 func (dec *Decoder) decodeLogInfo() schema.LogInfo {
-	numFieldsToSkip, _, ok := dec.checkNumFieldsFor(logInfoType, checkNumFieldsOptions{})
+	numFieldsToSkip, actual, ok := dec.checkNumFieldsFor(logInfoType, checkNumFieldsOptions{})
 	if !ok {
 		return emptyLogInfo
 	}
@@ -415,6 +415,12 @@ func (dec *Decoder) decodeLogInfo() schema.LogInfo {
 	logInfo.DeprecatedDoNotUseDuration = dec.decodeVarint()
 
 	logInfo.Index = dec.decodeVarint()
+
+	// Logs written before the chunk format was recorded only have 3 fields.
+	if actual >= 5 {
+		logInfo.ChunkVersion = dec.decodeVarint()
+		logInfo.ChunkCompression = dec.decodeVarint()
+	}
 	dec.skip(numFieldsToSkip)
 	if dec.err != nil {
 		return emptyLogInfo
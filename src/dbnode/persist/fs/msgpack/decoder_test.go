@@ -23,6 +23,8 @@ package msgpack
 import (
 	"testing"
 
+	"github.com/m3db/m3/src/dbnode/persist/schema"
+
 	"github.com/stretchr/testify/require"
 )
 
@@ -155,6 +157,27 @@ func TestDecodeLogInfoMoreFieldsThanExpected(t *testing.T) {
 	require.Equal(t, testLogInfo, res)
 }
 
+func TestDecodeLogInfoWithoutChunkFormat(t *testing.T) {
+	var (
+		enc = NewEncoder()
+		dec = NewDecoder(nil)
+	)
+
+	// Encode the log info as written before the chunk format was recorded.
+	enc.encodeNumObjectFieldsForFn = testGenEncodeNumObjectFieldsForFn(enc, logInfoType, -2)
+	enc.encodeRootObject(logInfoVersion, logInfoType)
+	enc.encodeNumObjectFieldsForFn(logInfoType)
+	enc.encodeVarintFn(testLogInfo.DeprecatedDoNotUseStart)
+	enc.encodeVarintFn(testLogInfo.DeprecatedDoNotUseDuration)
+	enc.encodeVarintFn(testLogInfo.Index)
+	require.NoError(t, enc.err)
+
+	dec.Reset(NewByteDecoderStream(enc.Bytes()))
+	res, err := dec.DecodeLogInfo()
+	require.NoError(t, err)
+	require.Equal(t, schema.LogInfo{Index: testLogInfo.Index}, res)
+}
+
 func TestDecodeLogEntryMoreFieldsThanExpected(t *testing.T) {
 	var (
 		enc = NewEncoder()
@@ -294,6 +294,9 @@ func (enc *Encoder) encodeLogInfo(info schema.LogInfo) {
 	enc.encodeVarintFn(info.DeprecatedDoNotUseDuration)
 
 	enc.encodeVarintFn(info.Index)
+
+	enc.encodeVarintFn(info.ChunkVersion)
+	enc.encodeVarintFn(info.ChunkCompression)
 }
 
 func (enc *Encoder) encodeLogEntry(entry schema.LogEntry) {
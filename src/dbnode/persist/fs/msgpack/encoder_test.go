@@ -113,6 +113,8 @@ func testExpectedResultForLogInfo(t *testing.T, logInfo schema.LogInfo) []interf
 		logInfo.DeprecatedDoNotUseStart,
 		logInfo.DeprecatedDoNotUseDuration,
 		logInfo.Index,
+		logInfo.ChunkVersion,
+		logInfo.ChunkCompression,
 	}
 }
 
@@ -66,7 +66,9 @@ var (
 	}
 
 	testLogInfo = schema.LogInfo{
-		Index: 234,
+		Index:            234,
+		ChunkVersion:     2,
+		ChunkCompression: 1,
 	}
 
 	testLogEntry = schema.LogEntry{
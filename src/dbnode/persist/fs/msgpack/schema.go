@@ -104,7 +104,7 @@ const (
 	currNumIndexBloomFilterInfoFields = 2
 	currNumIndexEntryFields           = 6
 	currNumIndexSummaryFields         = 3
-	currNumLogInfoFields              = 3
+	currNumLogInfoFields              = 5
 	currNumLogEntryFields             = 7
 	currNumLogMetadataFields          = 3
 )
@@ -96,6 +96,7 @@ type options struct {
 	tagEncoderPool                       serialize.TagEncoderPool
 	tagDecoderPool                       serialize.TagDecoderPool
 	fstOptions                           fst.Options
+	fstWriterOptions                     fst.WriterOptions
 	forceIndexSummariesMmapMemory        bool
 	forceBloomFilterMmapMemory           bool
 	mmapEnableHugePages                  bool
@@ -356,6 +357,16 @@ func (o *options) FSTOptions() fst.Options {
 	return o.fstOptions
 }
 
+func (o *options) SetFSTWriterOptions(value fst.WriterOptions) Options {
+	opts := *o
+	opts.fstWriterOptions = value
+	return &opts
+}
+
+func (o *options) FSTWriterOptions() fst.WriterOptions {
+	return o.fstWriterOptions
+}
+
 func (o *options) SetMmapReporter(mmapReporter mmap.Reporter) Options {
 	opts := *o
 	opts.mmapReporter = mmapReporter
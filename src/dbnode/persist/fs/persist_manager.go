@@ -165,7 +165,8 @@ func NewPersistManager(opts Options) (persist.Manager, error) {
 	if err != nil {
 		return nil, err
 	}
-	segmentWriter, err := m3ninxpersist.NewMutableSegmentFileSetWriter()
+	segmentWriter, err := m3ninxpersist.NewMutableSegmentFileSetWriter(
+		opts.FSTWriterOptions())
 	if err != nil {
 		return nil, err
 	}
@@ -268,6 +269,9 @@ func (pm *persistManager) PrepareIndex(opts persist.IndexPrepareOptions) (persis
 		VolumeIndex:        volumeIndex,
 	}
 	blockSize := nsMetadata.Options().IndexOptions().BlockSize()
+	if opts.BlockSize > 0 {
+		blockSize = opts.BlockSize
+	}
 	idxWriterOpts := IndexWriterOpenOptions{
 		BlockSize:   blockSize,
 		FileSetType: opts.FileSetType,
@@ -403,6 +403,42 @@ func TestPersistenceManagerPrepareIndexOpenError(t *testing.T) {
 	require.Nil(t, prepared.Close)
 }
 
+func TestPersistenceManagerPrepareIndexBlockSize(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	pm, writer, segWriter, _ := testIndexPersistManager(t, ctrl)
+	defer os.RemoveAll(pm.filePathPrefix)
+
+	blockStart := time.Unix(1000, 0)
+	expectedErr := errors.New("foo")
+
+	writerOpts := xtest.CmpMatcher(IndexWriterOpenOptions{
+		Identifier: FileSetFileIdentifier{
+			FileSetContentType: persist.FileSetIndexContentType,
+			Namespace:          testNs1ID,
+			BlockStart:         blockStart,
+		},
+		BlockSize: 4 * testBlockSize,
+	}, m3test.IdentTransformer)
+	writer.EXPECT().Open(writerOpts).Return(expectedErr)
+
+	flush, err := pm.StartIndexPersist()
+	require.NoError(t, err)
+
+	defer func() {
+		segWriter.EXPECT().Reset(nil)
+		assert.NoError(t, flush.DoneIndex())
+	}()
+
+	_, err = flush.PrepareIndex(persist.IndexPrepareOptions{
+		NamespaceMetadata: testNs1Metadata(t),
+		BlockStart:        blockStart,
+		BlockSize:         4 * testBlockSize,
+	})
+	require.Equal(t, expectedErr, err)
+}
+
 func TestPersistenceManagerPrepareIndexSuccess(t *testing.T) {
 	ctrl := gomock.NewController(xtest.Reporter{T: t})
 	defer ctrl.Finish()
@@ -468,7 +504,10 @@ func TestPersistenceManagerPrepareIndexSuccess(t *testing.T) {
 	segs, err := prepared.Close()
 	require.NoError(t, err)
 	require.Len(t, segs, 1)
-	require.Equal(t, fsSeg, segs[0])
+	fileSetSeg, ok := segs[0].(*IndexFileSetSegment)
+	require.True(t, ok)
+	require.Equal(t, fsSeg, fileSetSeg.Segment)
+	require.Equal(t, 0, fileSetSeg.SegmentIndex())
 }
 
 func TestPersistenceManagerNoRateLimit(t *testing.T) {
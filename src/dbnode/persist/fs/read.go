@@ -161,7 +161,13 @@ func (r *reader) Open(opts DataReaderOpenOptions) error {
 		indexFilepath = filesetPathFromTimeAndIndex(shardDir, blockStart, volumeIndex, indexFileSuffix)
 		dataFilepath = filesetPathFromTimeAndIndex(shardDir, blockStart, volumeIndex, dataFileSuffix)
 	case persist.FileSetFlushType:
-		shardDir = ShardDataDirPath(r.filePathPrefix, namespace, shard)
+		var filePathPrefix string
+		filePathPrefix, err = DataFileSetFilePathPrefix(r.filePathPrefix,
+			opts.ColdTierFilePathPrefix, namespace, shard, blockStart, volumeIndex)
+		if err != nil {
+			return err
+		}
+		shardDir = ShardDataDirPath(filePathPrefix, namespace, shard)
 
 		isLegacy := false
 		if volumeIndex == 0 {
@@ -736,8 +736,15 @@ func (m *seekerManager) newOpenSeeker(
 	blockStart time.Time,
 	volume int,
 ) (DataFileSetSeeker, error) {
+	// The fileset may have been moved to the cold tier of the namespace.
+	coldTierFilePathPrefix := m.namespaceMetadata.Options().ColdTierOptions().FilePathPrefix()
+	filePathPrefix, err := DataFileSetFilePathPrefix(m.filePathPrefix,
+		coldTierFilePathPrefix, m.namespace, shard, blockStart, volume)
+	if err != nil {
+		return nil, err
+	}
 	exists, err := DataFileSetExists(
-		m.filePathPrefix, m.namespace, shard, blockStart, volume)
+		filePathPrefix, m.namespace, shard, blockStart, volume)
 	if err != nil {
 		return nil, err
 	}
@@ -752,7 +759,7 @@ func (m *seekerManager) newOpenSeeker(
 	defer m.unreadBuf.Unlock()
 
 	seekerIface := NewSeeker(
-		m.filePathPrefix,
+		filePathPrefix,
 		m.opts.DataReaderBufferSize(),
 		m.opts.InfoReaderBufferSize(),
 		m.bytesPool,
@@ -0,0 +1,243 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fs
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/persist/fs/msgpack"
+	"github.com/m3db/m3/src/x/ident"
+	xtime "github.com/m3db/m3/src/x/time"
+)
+
+// DataFileSetFilePathPrefix returns the file path prefix of the storage tier
+// that holds the complete data fileset for the given volume. The primary tier
+// is preferred and the cold tier is only checked when its file path prefix is
+// set, if the fileset exists in neither tier the primary file path prefix is
+// returned.
+func DataFileSetFilePathPrefix(
+	filePathPrefix string,
+	coldTierFilePathPrefix string,
+	namespace ident.ID,
+	shard uint32,
+	blockStart time.Time,
+	volume int,
+) (string, error) {
+	if coldTierFilePathPrefix == "" {
+		return filePathPrefix, nil
+	}
+
+	exists, err := DataFileSetExists(filePathPrefix, namespace, shard, blockStart, volume)
+	if err != nil {
+		return "", err
+	}
+	if exists {
+		return filePathPrefix, nil
+	}
+
+	exists, err = DataFileSetExists(coldTierFilePathPrefix, namespace, shard, blockStart, volume)
+	if err != nil {
+		return "", err
+	}
+	if exists {
+		return coldTierFilePathPrefix, nil
+	}
+	return filePathPrefix, nil
+}
+
+// MergeTieredDataFileSets merges the data filesets found in the primary and
+// cold storage tiers, a volume present in both tiers (i.e. one that was being
+// moved) is only returned once for the primary tier.
+func MergeTieredDataFileSets(
+	primary FileSetFilesSlice,
+	cold FileSetFilesSlice,
+) FileSetFilesSlice {
+	if len(cold) == 0 {
+		return primary
+	}
+
+	type volumeKey struct {
+		blockStart xtime.UnixNano
+		volume     int
+	}
+	inPrimary := make(map[volumeKey]struct{}, len(primary))
+	for _, fileset := range primary {
+		inPrimary[volumeKey{
+			blockStart: xtime.ToUnixNano(fileset.ID.BlockStart),
+			volume:     fileset.ID.VolumeIndex,
+		}] = struct{}{}
+	}
+
+	merged := make(FileSetFilesSlice, 0, len(primary)+len(cold))
+	merged = append(merged, primary...)
+	for _, fileset := range cold {
+		key := volumeKey{
+			blockStart: xtime.ToUnixNano(fileset.ID.BlockStart),
+			volume:     fileset.ID.VolumeIndex,
+		}
+		if _, ok := inPrimary[key]; ok {
+			continue
+		}
+		merged = append(merged, fileset)
+	}
+	merged.sortByTimeAndVolumeIndexAscending()
+	return merged
+}
+
+// ReadTieredInfoFiles reads all the valid info entries of both the primary and
+// cold storage tiers, a volume present in both tiers is only returned once for
+// the primary tier. The cold tier is only read when its file path prefix is set.
+func ReadTieredInfoFiles(
+	filePathPrefix string,
+	coldTierFilePathPrefix string,
+	namespace ident.ID,
+	shard uint32,
+	readerBufferSize int,
+	decodingOpts msgpack.DecodingOptions,
+) []ReadInfoFileResult {
+	results := ReadInfoFiles(filePathPrefix, namespace, shard,
+		readerBufferSize, decodingOpts)
+	if coldTierFilePathPrefix == "" {
+		return results
+	}
+
+	type volumeKey struct {
+		blockStart int64
+		volume     int
+	}
+	inPrimary := make(map[volumeKey]struct{}, len(results))
+	for _, result := range results {
+		if result.Err.Error() != nil {
+			continue
+		}
+		inPrimary[volumeKey{
+			blockStart: result.Info.BlockStart,
+			volume:     result.Info.VolumeIndex,
+		}] = struct{}{}
+	}
+
+	coldResults := ReadInfoFiles(coldTierFilePathPrefix, namespace, shard,
+		readerBufferSize, decodingOpts)
+	for _, result := range coldResults {
+		if result.Err.Error() == nil {
+			key := volumeKey{
+				blockStart: result.Info.BlockStart,
+				volume:     result.Info.VolumeIndex,
+			}
+			if _, ok := inPrimary[key]; ok {
+				continue
+			}
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// MoveDataFileSet moves the files of a complete data fileset to the shard
+// directory under another file path prefix. The files are copied and synced
+// with the checkpoint file written last so that the fileset only becomes
+// complete in the destination once all of its files are durable, the source
+// files are then removed with the checkpoint file removed first.
+func MoveDataFileSet(
+	fileset FileSetFile,
+	dstFilePathPrefix string,
+	opts Options,
+) error {
+	dstDir := ShardDataDirPath(dstFilePathPrefix, fileset.ID.Namespace,
+		fileset.ID.Shard)
+	if err := os.MkdirAll(dstDir, opts.NewDirectoryMode()); err != nil {
+		return err
+	}
+
+	var (
+		checkpointFilePath string
+		filePaths          = make([]string, 0, len(fileset.AbsoluteFilepaths))
+	)
+	for _, filePath := range fileset.AbsoluteFilepaths {
+		if isCheckpointFilePath(filePath) {
+			checkpointFilePath = filePath
+			continue
+		}
+		filePaths = append(filePaths, filePath)
+	}
+	if checkpointFilePath == "" {
+		return fmt.Errorf("unable to move fileset without checkpoint file: %v",
+			fileset.ID)
+	}
+
+	for _, filePath := range append(filePaths, checkpointFilePath) {
+		dstPath := path.Join(dstDir, filepath.Base(filePath))
+		if err := copyFileAndSync(filePath, dstPath, opts.NewFileMode()); err != nil {
+			return err
+		}
+	}
+
+	// Sync the destination directory to make sure the new files are persisted
+	// before the source files are removed.
+	dir, err := os.Open(dstDir)
+	if err != nil {
+		return err
+	}
+	if err := dir.Sync(); err != nil {
+		dir.Close()
+		return err
+	}
+	if err := dir.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Remove(checkpointFilePath); err != nil {
+		return err
+	}
+	return DeleteFiles(filePaths)
+}
+
+func isCheckpointFilePath(filePath string) bool {
+	return strings.HasSuffix(filePath, separator+checkpointFileSuffix+fileSuffix)
+}
+
+func copyFileAndSync(srcPath string, dstPath string, perm os.FileMode) error {
+	src, err := os.Open(srcPath)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := OpenWritable(dstPath, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		return err
+	}
+	if err := dst.Sync(); err != nil {
+		dst.Close()
+		return err
+	}
+	return dst.Close()
+}
@@ -0,0 +1,173 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/persist"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMoveDataFileSetReadFromColdTier(t *testing.T) {
+	dir := createTempDir(t)
+	defer os.RemoveAll(dir)
+
+	var (
+		filePathPrefix         = filepath.Join(dir, "hot")
+		coldTierFilePathPrefix = filepath.Join(dir, "cold")
+		entries                = []testEntry{
+			{"foo", nil, []byte{1, 2, 3}},
+			{"bar", map[string]string{"baz": "qux"}, []byte{4, 5, 6}},
+		}
+	)
+	w := newTestWriter(t, filePathPrefix)
+	writeTestData(t, w, 0, testWriterStart, entries, persist.FileSetFlushType)
+
+	filesets, err := DataFiles(filePathPrefix, testNs1ID, 0)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(filesets))
+	require.NoError(t, MoveDataFileSet(filesets[0], coldTierFilePathPrefix, testDefaultOpts))
+
+	filesets, err = DataFiles(filePathPrefix, testNs1ID, 0)
+	require.NoError(t, err)
+	require.Equal(t, 0, len(filesets))
+	filesets, err = DataFiles(coldTierFilePathPrefix, testNs1ID, 0)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(filesets))
+	require.True(t, filesets[0].HasCompleteCheckpointFile())
+
+	prefix, err := DataFileSetFilePathPrefix(filePathPrefix, coldTierFilePathPrefix,
+		testNs1ID, 0, testWriterStart, 0)
+	require.NoError(t, err)
+	require.Equal(t, coldTierFilePathPrefix, prefix)
+
+	results := ReadTieredInfoFiles(filePathPrefix, coldTierFilePathPrefix,
+		testNs1ID, 0, 16, nil)
+	require.Equal(t, 1, len(results))
+	require.NoError(t, results[0].Err.Error())
+	require.Equal(t, int64(len(entries)), results[0].Info.Entries)
+
+	r := newTestReader(t, filePathPrefix)
+	openOpts := DataReaderOpenOptions{
+		Identifier: FileSetFileIdentifier{
+			Namespace:  testNs1ID,
+			Shard:      0,
+			BlockStart: testWriterStart,
+		},
+		FileSetType: persist.FileSetFlushType,
+	}
+	require.Error(t, r.Open(openOpts))
+
+	openOpts.ColdTierFilePathPrefix = coldTierFilePathPrefix
+	require.NoError(t, r.Open(openOpts))
+	require.Equal(t, len(entries), r.Entries())
+	for i := range entries {
+		id, tags, data, _, err := r.Read()
+		require.NoError(t, err)
+
+		data.IncRef()
+		require.Equal(t, entries[i].id, id.String())
+		require.Equal(t, entries[i].data, data.Bytes())
+
+		id.Finalize()
+		tags.Close()
+		data.DecRef()
+		data.Finalize()
+	}
+	require.NoError(t, r.Close())
+}
+
+func TestDataFileSetFilePathPrefixPrefersPrimaryTier(t *testing.T) {
+	dir := createTempDir(t)
+	defer os.RemoveAll(dir)
+
+	var (
+		filePathPrefix         = filepath.Join(dir, "hot")
+		coldTierFilePathPrefix = filepath.Join(dir, "cold")
+		entries                = []testEntry{{"foo", nil, []byte{1, 2, 3}}}
+	)
+	for _, prefix := range []string{filePathPrefix, coldTierFilePathPrefix} {
+		w := newTestWriter(t, prefix)
+		writeTestData(t, w, 0, testWriterStart, entries, persist.FileSetFlushType)
+	}
+
+	prefix, err := DataFileSetFilePathPrefix(filePathPrefix, coldTierFilePathPrefix,
+		testNs1ID, 0, testWriterStart, 0)
+	require.NoError(t, err)
+	require.Equal(t, filePathPrefix, prefix)
+
+	// Volumes that exist in neither tier resolve to the primary tier.
+	prefix, err = DataFileSetFilePathPrefix(filePathPrefix, coldTierFilePathPrefix,
+		testNs1ID, 0, testWriterStart, 1)
+	require.NoError(t, err)
+	require.Equal(t, filePathPrefix, prefix)
+
+	results := ReadTieredInfoFiles(filePathPrefix, coldTierFilePathPrefix,
+		testNs1ID, 0, 16, nil)
+	require.Equal(t, 1, len(results))
+}
+
+func TestMergeTieredDataFileSets(t *testing.T) {
+	var (
+		start   = time.Unix(0, 0)
+		fileset = func(blockStart time.Time, volume int, prefix string) FileSetFile {
+			return NewFileSetFile(FileSetFileIdentifier{
+				Namespace:   testNs1ID,
+				BlockStart:  blockStart,
+				VolumeIndex: volume,
+			}, prefix)
+		}
+		primary = FileSetFilesSlice{
+			fileset(start.Add(2*time.Hour), 0, "hot"),
+			fileset(start.Add(4*time.Hour), 0, "hot"),
+		}
+		cold = FileSetFilesSlice{
+			fileset(start, 0, "cold"),
+			fileset(start, 1, "cold"),
+			fileset(start.Add(2*time.Hour), 0, "cold"),
+		}
+	)
+
+	merged := MergeTieredDataFileSets(primary, cold)
+	require.Equal(t, 4, len(merged))
+	expected := []struct {
+		blockStart time.Time
+		volume     int
+		prefix     string
+	}{
+		{start, 0, "cold"},
+		{start, 1, "cold"},
+		{start.Add(2 * time.Hour), 0, "hot"},
+		{start.Add(4 * time.Hour), 0, "hot"},
+	}
+	for i, e := range expected {
+		require.True(t, e.blockStart.Equal(merged[i].ID.BlockStart))
+		require.Equal(t, e.volume, merged[i].ID.VolumeIndex)
+		require.Equal(t, e.prefix, merged[i].filePathPrefix)
+	}
+
+	require.Equal(t, primary, MergeTieredDataFileSets(primary, nil))
+}
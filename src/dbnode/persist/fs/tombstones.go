@@ -0,0 +1,319 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fs
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/digest"
+	"github.com/m3db/m3/src/x/ident"
+)
+
+const (
+	tombstonesFileName    = "tombstones" + fileSuffix
+	tombstonesTmpFileName = tombstonesFileName + ".tmp"
+
+	tombstonesFileVersion = 1
+)
+
+var (
+	errTombstonesFileTooShort       = errors.New("tombstones file too short")
+	errTombstonesFileChecksum       = errors.New("tombstones file checksum mismatch")
+	errTombstonesFileVersionUnknown = errors.New("tombstones file version unknown")
+)
+
+// Tombstones is the set of series that have been deleted from a shard. Each
+// tombstone records the time the series was deleted, all datapoints with a
+// timestamp before that time are considered deleted.
+type Tombstones struct {
+	sync.RWMutex
+	deletedAt map[string]int64
+}
+
+// NewTombstones returns a new empty set of tombstones.
+func NewTombstones() *Tombstones {
+	return &Tombstones{deletedAt: make(map[string]int64)}
+}
+
+// Add records a tombstone for the series, if the series already has a
+// tombstone the latest deletion time is retained.
+func (t *Tombstones) Add(id ident.ID, deletedAt time.Time) {
+	nanos := deletedAt.UnixNano()
+	t.Lock()
+	if existing, ok := t.deletedAt[id.String()]; !ok || existing < nanos {
+		t.deletedAt[id.String()] = nanos
+	}
+	t.Unlock()
+}
+
+// DeletedAt returns the time the series was deleted and whether it has a
+// tombstone at all.
+func (t *Tombstones) DeletedAt(id ident.ID) (time.Time, bool) {
+	t.RLock()
+	if len(t.deletedAt) == 0 {
+		t.RUnlock()
+		return time.Time{}, false
+	}
+	nanos, ok := t.deletedAt[string(id.Bytes())]
+	t.RUnlock()
+	if !ok {
+		return time.Time{}, false
+	}
+	return time.Unix(0, nanos), true
+}
+
+// DatapointDeleted returns whether a datapoint for the series at the given
+// timestamp has been deleted.
+func (t *Tombstones) DatapointDeleted(id ident.ID, timestamp time.Time) bool {
+	deletedAt, ok := t.DeletedAt(id)
+	return ok && timestamp.Before(deletedAt)
+}
+
+// BlockDeleted returns whether all the data of a persisted block for the
+// series has been deleted, that is the block ends at or before the time the
+// series was deleted.
+func (t *Tombstones) BlockDeleted(
+	id ident.ID,
+	blockStart time.Time,
+	blockSize time.Duration,
+) bool {
+	deletedAt, ok := t.DeletedAt(id)
+	return ok && !blockStart.Add(blockSize).After(deletedAt)
+}
+
+// DeletedWithinBlock returns the time the series was deleted if it falls
+// within the given block, in which case the datapoints of the block before
+// that time are deleted and must be filtered out of any persisted data.
+func (t *Tombstones) DeletedWithinBlock(
+	id ident.ID,
+	blockStart time.Time,
+	blockSize time.Duration,
+) (time.Time, bool) {
+	deletedAt, ok := t.DeletedAt(id)
+	if !ok || !deletedAt.After(blockStart) ||
+		!deletedAt.Before(blockStart.Add(blockSize)) {
+		return time.Time{}, false
+	}
+	return deletedAt, true
+}
+
+// Len returns the number of tombstones.
+func (t *Tombstones) Len() int {
+	t.RLock()
+	l := len(t.deletedAt)
+	t.RUnlock()
+	return l
+}
+
+// ExpireBefore removes tombstones that no longer cover any data, that is the
+// series was deleted at or before the given earliest retained block start,
+// and returns the number of tombstones removed.
+func (t *Tombstones) ExpireBefore(
+	earliestToRetain time.Time,
+	blockSize time.Duration,
+) int {
+	earliest := earliestToRetain.Truncate(blockSize).UnixNano()
+	removed := 0
+	t.Lock()
+	for id, nanos := range t.deletedAt {
+		if nanos <= earliest {
+			delete(t.deletedAt, id)
+			removed++
+		}
+	}
+	t.Unlock()
+	return removed
+}
+
+// ForEach calls the provided function for each tombstone.
+func (t *Tombstones) ForEach(fn func(id ident.ID, deletedAt time.Time)) {
+	t.RLock()
+	for id, nanos := range t.deletedAt {
+		fn(ident.StringID(id), time.Unix(0, nanos))
+	}
+	t.RUnlock()
+}
+
+// TombstonesFilePath returns the path of the tombstones file for a shard.
+func TombstonesFilePath(prefix string, namespace ident.ID, shard uint32) string {
+	return path.Join(ShardDataDirPath(prefix, namespace, shard), tombstonesFileName)
+}
+
+// ReadTombstones reads the tombstones persisted for a shard, returning an
+// empty set if none have been persisted.
+func ReadTombstones(
+	prefix string,
+	namespace ident.ID,
+	shard uint32,
+) (*Tombstones, error) {
+	filePath := TombstonesFilePath(prefix, namespace, shard)
+	data, err := ioutil.ReadFile(filePath)
+	if os.IsNotExist(err) {
+		return NewTombstones(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	t, err := decodeTombstones(data)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read tombstones file %s: %v", filePath, err)
+	}
+	return t, nil
+}
+
+// WriteTombstones atomically persists the tombstones for a shard, replacing
+// any tombstones previously persisted.
+func WriteTombstones(
+	opts Options,
+	namespace ident.ID,
+	shard uint32,
+	tombstones *Tombstones,
+) error {
+	var (
+		prefix   = opts.FilePathPrefix()
+		shardDir = ShardDataDirPath(prefix, namespace, shard)
+		tmpPath  = path.Join(shardDir, tombstonesTmpFileName)
+		filePath = path.Join(shardDir, tombstonesFileName)
+	)
+	if err := os.MkdirAll(shardDir, opts.NewDirectoryMode()); err != nil {
+		return err
+	}
+
+	if err := writeFileAndSync(tmpPath, encodeTombstones(tombstones),
+		opts.NewFileMode()); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, filePath); err != nil {
+		return err
+	}
+
+	// Sync the parent directory to make sure the rename is persisted.
+	dir, err := os.Open(shardDir)
+	if err != nil {
+		return err
+	}
+	if err := dir.Sync(); err != nil {
+		dir.Close()
+		return err
+	}
+	return dir.Close()
+}
+
+func writeFileAndSync(filePath string, data []byte, perm os.FileMode) error {
+	fd, err := OpenWritable(filePath, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := fd.Write(data); err != nil {
+		fd.Close()
+		return err
+	}
+	if err := fd.Sync(); err != nil {
+		fd.Close()
+		return err
+	}
+	return fd.Close()
+}
+
+// encodeTombstones encodes tombstones as a version followed by a count and
+// each ID and deletion time, then a trailing checksum of all preceding bytes.
+func encodeTombstones(t *Tombstones) []byte {
+	var (
+		buf     bytes.Buffer
+		scratch [binary.MaxVarintLen64]byte
+	)
+	writeUvarint := func(v uint64) {
+		n := binary.PutUvarint(scratch[:], v)
+		buf.Write(scratch[:n])
+	}
+
+	t.RLock()
+	writeUvarint(tombstonesFileVersion)
+	writeUvarint(uint64(len(t.deletedAt)))
+	for id, nanos := range t.deletedAt {
+		writeUvarint(uint64(len(id)))
+		buf.WriteString(id)
+		n := binary.PutVarint(scratch[:], nanos)
+		buf.Write(scratch[:n])
+	}
+	t.RUnlock()
+
+	var checksum [digest.DigestLenBytes]byte
+	binary.LittleEndian.PutUint32(checksum[:], digest.Checksum(buf.Bytes()))
+	buf.Write(checksum[:])
+	return buf.Bytes()
+}
+
+func decodeTombstones(data []byte) (*Tombstones, error) {
+	if len(data) < digest.DigestLenBytes {
+		return nil, errTombstonesFileTooShort
+	}
+	var (
+		body     = data[:len(data)-digest.DigestLenBytes]
+		expected = binary.LittleEndian.Uint32(data[len(data)-digest.DigestLenBytes:])
+	)
+	if digest.Checksum(body) != expected {
+		return nil, errTombstonesFileChecksum
+	}
+
+	r := bytes.NewReader(body)
+	version, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, err
+	}
+	if version != tombstonesFileVersion {
+		return nil, errTombstonesFileVersionUnknown
+	}
+	count, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, err
+	}
+
+	t := NewTombstones()
+	for i := uint64(0); i < count; i++ {
+		idLen, err := binary.ReadUvarint(r)
+		if err != nil {
+			return nil, err
+		}
+		if idLen > uint64(r.Len()) {
+			return nil, errTombstonesFileTooShort
+		}
+		id := make([]byte, int(idLen))
+		if _, err := r.Read(id); err != nil {
+			return nil, err
+		}
+		nanos, err := binary.ReadVarint(r)
+		if err != nil {
+			return nil, err
+		}
+		t.deletedAt[string(id)] = nanos
+	}
+	return t, nil
+}
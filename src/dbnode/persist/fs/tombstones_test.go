@@ -0,0 +1,150 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fs
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/x/ident"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTombstonesBlockAndDatapointDeleted(t *testing.T) {
+	var (
+		blockSize = 2 * time.Hour
+		deletedAt = time.Unix(0, 0).Add(5 * time.Hour)
+		id        = ident.StringID("foo")
+		tomb      = NewTombstones()
+	)
+	tomb.Add(id, deletedAt)
+	// Older deletion time should not override the newer one.
+	tomb.Add(id, deletedAt.Add(-time.Hour))
+
+	at, ok := tomb.DeletedAt(id)
+	require.True(t, ok)
+	require.True(t, at.Equal(deletedAt))
+
+	require.True(t, tomb.DatapointDeleted(id, deletedAt.Add(-time.Second)))
+	require.False(t, tomb.DatapointDeleted(id, deletedAt))
+	require.False(t, tomb.DatapointDeleted(ident.StringID("bar"), deletedAt.Add(-time.Second)))
+
+	require.True(t, tomb.BlockDeleted(id, time.Unix(0, 0).Add(2*time.Hour), blockSize))
+	require.False(t, tomb.BlockDeleted(id, time.Unix(0, 0).Add(4*time.Hour), blockSize))
+	require.False(t, tomb.BlockDeleted(id, time.Unix(0, 0).Add(6*time.Hour), blockSize))
+
+	// Only the block containing the deletion time is partially deleted.
+	_, ok = tomb.DeletedWithinBlock(id, time.Unix(0, 0).Add(2*time.Hour), blockSize)
+	require.False(t, ok)
+	at, ok = tomb.DeletedWithinBlock(id, time.Unix(0, 0).Add(4*time.Hour), blockSize)
+	require.True(t, ok)
+	require.True(t, at.Equal(deletedAt))
+	_, ok = tomb.DeletedWithinBlock(id, time.Unix(0, 0).Add(6*time.Hour), blockSize)
+	require.False(t, ok)
+
+	// A deletion at a block boundary deletes the whole preceding block.
+	boundary := time.Unix(0, 0).Add(8 * time.Hour)
+	tomb.Add(ident.StringID("bar"), boundary)
+	require.True(t, tomb.BlockDeleted(ident.StringID("bar"), boundary.Add(-blockSize), blockSize))
+	_, ok = tomb.DeletedWithinBlock(ident.StringID("bar"), boundary, blockSize)
+	require.False(t, ok)
+}
+
+func TestTombstonesExpireBefore(t *testing.T) {
+	var (
+		blockSize = 2 * time.Hour
+		start     = time.Unix(0, 0)
+		tomb      = NewTombstones()
+	)
+	tomb.Add(ident.StringID("foo"), start.Add(3*time.Hour))
+	tomb.Add(ident.StringID("bar"), start.Add(7*time.Hour))
+
+	require.Equal(t, 0, tomb.ExpireBefore(start.Add(time.Hour), blockSize))
+	// The block containing the deletion time is still retained.
+	require.Equal(t, 0, tomb.ExpireBefore(start.Add(2*time.Hour), blockSize))
+	require.Equal(t, 1, tomb.ExpireBefore(start.Add(4*time.Hour), blockSize))
+	require.Equal(t, 1, tomb.Len())
+
+	_, ok := tomb.DeletedAt(ident.StringID("bar"))
+	require.True(t, ok)
+}
+
+func TestTombstonesWriteAndRead(t *testing.T) {
+	var (
+		dir            = createTempDir(t)
+		filePathPrefix = filepath.Join(dir, "")
+		opts           = testDefaultOpts.SetFilePathPrefix(filePathPrefix)
+		nsID           = ident.StringID("testns")
+		shard          = uint32(3)
+		deletedAt      = time.Unix(0, 0).Add(time.Hour)
+	)
+	defer os.RemoveAll(dir)
+
+	// Reading tombstones that were never written returns an empty set.
+	read, err := ReadTombstones(filePathPrefix, nsID, shard)
+	require.NoError(t, err)
+	require.Equal(t, 0, read.Len())
+
+	tomb := NewTombstones()
+	tomb.Add(ident.StringID("foo"), deletedAt)
+	tomb.Add(ident.StringID("bar"), deletedAt.Add(time.Minute))
+	require.NoError(t, WriteTombstones(opts, nsID, shard, tomb))
+
+	read, err = ReadTombstones(filePathPrefix, nsID, shard)
+	require.NoError(t, err)
+	require.Equal(t, 2, read.Len())
+
+	at, ok := read.DeletedAt(ident.StringID("foo"))
+	require.True(t, ok)
+	require.True(t, at.Equal(deletedAt))
+
+	at, ok = read.DeletedAt(ident.StringID("bar"))
+	require.True(t, ok)
+	require.True(t, at.Equal(deletedAt.Add(time.Minute)))
+}
+
+func TestTombstonesReadCorrupt(t *testing.T) {
+	var (
+		dir            = createTempDir(t)
+		filePathPrefix = filepath.Join(dir, "")
+		opts           = testDefaultOpts.SetFilePathPrefix(filePathPrefix)
+		nsID           = ident.StringID("testns")
+		shard          = uint32(0)
+	)
+	defer os.RemoveAll(dir)
+
+	tomb := NewTombstones()
+	tomb.Add(ident.StringID("foo"), time.Unix(0, 0))
+	require.NoError(t, WriteTombstones(opts, nsID, shard, tomb))
+
+	filePath := TombstonesFilePath(filePathPrefix, nsID, shard)
+	data, err := ioutil.ReadFile(filePath)
+	require.NoError(t, err)
+	data[1]++
+	require.NoError(t, ioutil.WriteFile(filePath, data, 0666))
+
+	_, err = ReadTombstones(filePathPrefix, nsID, shard)
+	require.Error(t, err)
+}
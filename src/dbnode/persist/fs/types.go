@@ -116,6 +116,10 @@ type DataFileSetReaderStatus struct {
 type DataReaderOpenOptions struct {
 	Identifier  FileSetFileIdentifier
 	FileSetType persist.FileSetType
+	// ColdTierFilePathPrefix is the file path prefix of the cold storage tier
+	// of the namespace, if set flush filesets that are not found in the
+	// primary tier are read from the cold tier.
+	ColdTierFilePathPrefix string
 }
 
 // DataFileSetReader provides an unsynchronized reader for a TSDB file set
@@ -467,6 +471,14 @@ type Options interface {
 	// FSTOptions returns the fst options.
 	FSTOptions() fst.Options
 
+	// SetFSTWriterOptions sets the fst writer options used to write index
+	// segments.
+	SetFSTWriterOptions(value fst.WriterOptions) Options
+
+	// FSTWriterOptions returns the fst writer options used to write index
+	// segments.
+	FSTWriterOptions() fst.WriterOptions
+
 	// SetMmapReporter sets the mmap reporter.
 	SetMmapReporter(mmapReporter mmap.Reporter) Options
 
@@ -533,6 +545,10 @@ type MergeWith interface {
 		fn ForEachRemainingFn,
 		nsCtx namespace.Context,
 	) error
+
+	// DeletedAt returns the time the series was deleted, if it has been, data
+	// persisted on disk for the series before that time should not be merged.
+	DeletedAt(seriesID ident.ID) (time.Time, bool)
 }
 
 // Merger is in charge of merging filesets with some target MergeWith interface.
@@ -557,4 +573,5 @@ type NewMergerFn func(
 	encoderPool encoding.EncoderPool,
 	contextPool context.Pool,
 	nsOpts namespace.Options,
+	nowFn clock.NowFn,
 ) Merger
@@ -79,6 +79,13 @@ type LogInfo struct {
 	DeprecatedDoNotUseDuration int64
 
 	Index int64
+
+	// ChunkVersion is the version of the chunk framing of the commit log,
+	// logs written before the version was recorded decode it as zero.
+	ChunkVersion int64
+	// ChunkCompression is the compression applied to the chunks that
+	// follow the chunk holding the log info.
+	ChunkCompression int64
 }
 
 // LogEntry stores per-entry data in a commit log
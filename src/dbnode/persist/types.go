@@ -153,6 +153,9 @@ type IndexPrepareOptions struct {
 	BlockStart        time.Time
 	FileSetType       FileSetType
 	Shards            map[uint32]struct{}
+	// BlockSize overrides the index block size of the namespace recorded
+	// by the fileset, e.g. for filesets of compacted index blocks.
+	BlockSize time.Duration
 }
 
 // DataPrepareSnapshotOptions is the options struct for the Prepare method that contains
@@ -0,0 +1,86 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package retention
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/m3db/m3/src/x/ident"
+)
+
+const (
+	// SeriesTTLTagName is the name of the reserved tag that sets a TTL for a
+	// series shorter than the retention period of its namespace, its value is
+	// a duration such as "6h".
+	SeriesTTLTagName = "__ttl__"
+)
+
+var seriesTTLTagNameBytes = []byte(SeriesTTLTagName)
+
+// IsSeriesTTLTagName returns whether the tag name is the series TTL tag name.
+func IsSeriesTTLTagName(name []byte) bool {
+	return bytes.Equal(name, seriesTTLTagNameBytes)
+}
+
+// ParseSeriesTTL parses the value of a series TTL tag.
+func ParseSeriesTTL(value []byte) (time.Duration, error) {
+	ttl, err := time.ParseDuration(string(value))
+	if err != nil {
+		return 0, fmt.Errorf("invalid series ttl %s: %v", value, err)
+	}
+	if ttl <= 0 {
+		return 0, fmt.Errorf("invalid series ttl %s: must be positive", value)
+	}
+	return ttl, nil
+}
+
+// SeriesTTL returns the TTL of the series with the given tags and whether it
+// has one, the tag iterator is duplicated so that it is not advanced.
+func SeriesTTL(tags ident.TagIterator) (time.Duration, bool, error) {
+	iter := tags.Duplicate()
+	defer iter.Close()
+
+	for iter.Next() {
+		tag := iter.Current()
+		if !IsSeriesTTLTagName(tag.Name.Bytes()) {
+			continue
+		}
+		ttl, err := ParseSeriesTTL(tag.Value.Bytes())
+		if err != nil {
+			return 0, false, err
+		}
+		return ttl, true, nil
+	}
+	return 0, false, iter.Err()
+}
+
+// SeriesBlockExpired returns whether all the data of a block of a series with
+// the given TTL has expired at the given time.
+func SeriesBlockExpired(
+	ttl time.Duration,
+	blockStart time.Time,
+	blockSize time.Duration,
+	t time.Time,
+) bool {
+	return !blockStart.Add(blockSize).Add(ttl).After(t)
+}
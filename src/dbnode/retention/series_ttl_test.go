@@ -0,0 +1,71 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package retention
+
+import (
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/x/ident"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeriesTTL(t *testing.T) {
+	tags := ident.NewTagsIterator(ident.NewTags(
+		ident.StringTag("host", "a"),
+		ident.StringTag(SeriesTTLTagName, "6h"),
+	))
+	ttl, ok, err := SeriesTTL(tags)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, 6*time.Hour, ttl)
+
+	// The iterator is not advanced.
+	require.Equal(t, 0, tags.CurrentIndex())
+	require.Equal(t, 2, tags.Remaining())
+
+	_, ok, err = SeriesTTL(ident.NewTagsIterator(ident.NewTags(
+		ident.StringTag("host", "a"),
+	)))
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	for _, value := range []string{"6", "-6h", "0s"} {
+		_, _, err = SeriesTTL(ident.NewTagsIterator(ident.NewTags(
+			ident.StringTag(SeriesTTLTagName, value),
+		)))
+		require.Error(t, err)
+	}
+}
+
+func TestSeriesBlockExpired(t *testing.T) {
+	var (
+		blockSize  = 2 * time.Hour
+		blockStart = time.Unix(0, 0).Add(10 * blockSize)
+		blockEnd   = blockStart.Add(blockSize)
+	)
+	require.False(t, SeriesBlockExpired(time.Hour, blockStart, blockSize, blockEnd))
+	require.False(t, SeriesBlockExpired(time.Hour, blockStart, blockSize,
+		blockEnd.Add(time.Hour-time.Nanosecond)))
+	require.True(t, SeriesBlockExpired(time.Hour, blockStart, blockSize,
+		blockEnd.Add(time.Hour)))
+}
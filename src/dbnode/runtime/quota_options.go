@@ -0,0 +1,114 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package runtime
+
+import (
+	"fmt"
+)
+
+type quotaOptions struct {
+	tenantTag          string
+	namespaceQuotas    map[string]Quota
+	tenantQuotas       map[string]Quota
+	defaultTenantQuota Quota
+}
+
+// NewQuotaOptions creates a new set of quota options that enforce no quotas.
+func NewQuotaOptions() QuotaOptions {
+	return &quotaOptions{}
+}
+
+func (o *quotaOptions) Validate() error {
+	for ns, quota := range o.namespaceQuotas {
+		if err := validateQuota(quota); err != nil {
+			return fmt.Errorf("invalid quota for namespace %s: %v", ns, err)
+		}
+	}
+	for tenant, quota := range o.tenantQuotas {
+		if err := validateQuota(quota); err != nil {
+			return fmt.Errorf("invalid quota for tenant %s: %v", tenant, err)
+		}
+	}
+	if err := validateQuota(o.defaultTenantQuota); err != nil {
+		return fmt.Errorf("invalid default tenant quota: %v", err)
+	}
+	return nil
+}
+
+func validateQuota(q Quota) error {
+	if q.DatapointsPerSecond < 0 {
+		return fmt.Errorf("datapoints per second cannot be negative: %d", q.DatapointsPerSecond)
+	}
+	if q.NewSeriesPerSecond < 0 {
+		return fmt.Errorf("new series per second cannot be negative: %d", q.NewSeriesPerSecond)
+	}
+	if q.MaxSeries < 0 {
+		return fmt.Errorf("max series cannot be negative: %d", q.MaxSeries)
+	}
+	if q.MaxQueryDocs < 0 {
+		return fmt.Errorf("max query docs cannot be negative: %d", q.MaxQueryDocs)
+	}
+	if q.MaxAggregateQueryTerms < 0 {
+		return fmt.Errorf("max aggregate query terms cannot be negative: %d",
+			q.MaxAggregateQueryTerms)
+	}
+	return nil
+}
+
+func (o *quotaOptions) SetTenantTag(value string) QuotaOptions {
+	opts := *o
+	opts.tenantTag = value
+	return &opts
+}
+
+func (o *quotaOptions) TenantTag() string {
+	return o.tenantTag
+}
+
+func (o *quotaOptions) SetNamespaceQuotas(value map[string]Quota) QuotaOptions {
+	opts := *o
+	opts.namespaceQuotas = value
+	return &opts
+}
+
+func (o *quotaOptions) NamespaceQuotas() map[string]Quota {
+	return o.namespaceQuotas
+}
+
+func (o *quotaOptions) SetTenantQuotas(value map[string]Quota) QuotaOptions {
+	opts := *o
+	opts.tenantQuotas = value
+	return &opts
+}
+
+func (o *quotaOptions) TenantQuotas() map[string]Quota {
+	return o.tenantQuotas
+}
+
+func (o *quotaOptions) SetDefaultTenantQuota(value Quota) QuotaOptions {
+	opts := *o
+	opts.defaultTenantQuota = value
+	return &opts
+}
+
+func (o *quotaOptions) DefaultTenantQuota() Quota {
+	return o.defaultTenantQuota
+}
@@ -0,0 +1,54 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package runtime
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuotaOptionsValidate(t *testing.T) {
+	opts := NewQuotaOptions()
+	assert.NoError(t, opts.Validate())
+
+	opts = opts.
+		SetTenantTag("team").
+		SetNamespaceQuotas(map[string]Quota{
+			"metrics": {DatapointsPerSecond: 1000, MaxSeries: 100},
+		}).
+		SetTenantQuotas(map[string]Quota{
+			"infra": {NewSeriesPerSecond: 10, MaxQueryDocs: 500},
+		})
+	assert.NoError(t, opts.Validate())
+	assert.Equal(t, "team", opts.TenantTag())
+	assert.Equal(t, int64(100), opts.NamespaceQuotas()["metrics"].MaxSeries)
+	assert.True(t, opts.DefaultTenantQuota().IsZero())
+
+	invalid := opts.SetTenantQuotas(map[string]Quota{
+		"infra": {MaxSeries: -1},
+	})
+	assert.Error(t, invalid.Validate())
+	assert.Error(t, NewOptions().SetQuotaOptions(invalid).Validate())
+
+	invalid = opts.SetDefaultTenantQuota(Quota{DatapointsPerSecond: -1})
+	assert.Error(t, invalid.Validate())
+}
@@ -408,6 +408,183 @@ func (mr *MockOptionsMockRecorder) IndexDefaultQueryTimeout() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IndexDefaultQueryTimeout", reflect.TypeOf((*MockOptions)(nil).IndexDefaultQueryTimeout))
 }
 
+// SetQuotaOptions mocks base method
+func (m *MockOptions) SetQuotaOptions(value QuotaOptions) Options {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetQuotaOptions", value)
+	ret0, _ := ret[0].(Options)
+	return ret0
+}
+
+// SetQuotaOptions indicates an expected call of SetQuotaOptions
+func (mr *MockOptionsMockRecorder) SetQuotaOptions(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetQuotaOptions", reflect.TypeOf((*MockOptions)(nil).SetQuotaOptions), value)
+}
+
+// QuotaOptions mocks base method
+func (m *MockOptions) QuotaOptions() QuotaOptions {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QuotaOptions")
+	ret0, _ := ret[0].(QuotaOptions)
+	return ret0
+}
+
+// QuotaOptions indicates an expected call of QuotaOptions
+func (mr *MockOptionsMockRecorder) QuotaOptions() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QuotaOptions", reflect.TypeOf((*MockOptions)(nil).QuotaOptions))
+}
+
+// MockQuotaOptions is a mock of QuotaOptions interface
+type MockQuotaOptions struct {
+	ctrl     *gomock.Controller
+	recorder *MockQuotaOptionsMockRecorder
+}
+
+// MockQuotaOptionsMockRecorder is the mock recorder for MockQuotaOptions
+type MockQuotaOptionsMockRecorder struct {
+	mock *MockQuotaOptions
+}
+
+// NewMockQuotaOptions creates a new mock instance
+func NewMockQuotaOptions(ctrl *gomock.Controller) *MockQuotaOptions {
+	mock := &MockQuotaOptions{ctrl: ctrl}
+	mock.recorder = &MockQuotaOptionsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockQuotaOptions) EXPECT() *MockQuotaOptionsMockRecorder {
+	return m.recorder
+}
+
+// Validate mocks base method
+func (m *MockQuotaOptions) Validate() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Validate")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Validate indicates an expected call of Validate
+func (mr *MockQuotaOptionsMockRecorder) Validate() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Validate", reflect.TypeOf((*MockQuotaOptions)(nil).Validate))
+}
+
+// SetTenantTag mocks base method
+func (m *MockQuotaOptions) SetTenantTag(value string) QuotaOptions {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetTenantTag", value)
+	ret0, _ := ret[0].(QuotaOptions)
+	return ret0
+}
+
+// SetTenantTag indicates an expected call of SetTenantTag
+func (mr *MockQuotaOptionsMockRecorder) SetTenantTag(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTenantTag", reflect.TypeOf((*MockQuotaOptions)(nil).SetTenantTag), value)
+}
+
+// TenantTag mocks base method
+func (m *MockQuotaOptions) TenantTag() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TenantTag")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// TenantTag indicates an expected call of TenantTag
+func (mr *MockQuotaOptionsMockRecorder) TenantTag() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TenantTag", reflect.TypeOf((*MockQuotaOptions)(nil).TenantTag))
+}
+
+// SetNamespaceQuotas mocks base method
+func (m *MockQuotaOptions) SetNamespaceQuotas(value map[string]Quota) QuotaOptions {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetNamespaceQuotas", value)
+	ret0, _ := ret[0].(QuotaOptions)
+	return ret0
+}
+
+// SetNamespaceQuotas indicates an expected call of SetNamespaceQuotas
+func (mr *MockQuotaOptionsMockRecorder) SetNamespaceQuotas(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetNamespaceQuotas", reflect.TypeOf((*MockQuotaOptions)(nil).SetNamespaceQuotas), value)
+}
+
+// NamespaceQuotas mocks base method
+func (m *MockQuotaOptions) NamespaceQuotas() map[string]Quota {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NamespaceQuotas")
+	ret0, _ := ret[0].(map[string]Quota)
+	return ret0
+}
+
+// NamespaceQuotas indicates an expected call of NamespaceQuotas
+func (mr *MockQuotaOptionsMockRecorder) NamespaceQuotas() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NamespaceQuotas", reflect.TypeOf((*MockQuotaOptions)(nil).NamespaceQuotas))
+}
+
+// SetTenantQuotas mocks base method
+func (m *MockQuotaOptions) SetTenantQuotas(value map[string]Quota) QuotaOptions {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetTenantQuotas", value)
+	ret0, _ := ret[0].(QuotaOptions)
+	return ret0
+}
+
+// SetTenantQuotas indicates an expected call of SetTenantQuotas
+func (mr *MockQuotaOptionsMockRecorder) SetTenantQuotas(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTenantQuotas", reflect.TypeOf((*MockQuotaOptions)(nil).SetTenantQuotas), value)
+}
+
+// TenantQuotas mocks base method
+func (m *MockQuotaOptions) TenantQuotas() map[string]Quota {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TenantQuotas")
+	ret0, _ := ret[0].(map[string]Quota)
+	return ret0
+}
+
+// TenantQuotas indicates an expected call of TenantQuotas
+func (mr *MockQuotaOptionsMockRecorder) TenantQuotas() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TenantQuotas", reflect.TypeOf((*MockQuotaOptions)(nil).TenantQuotas))
+}
+
+// SetDefaultTenantQuota mocks base method
+func (m *MockQuotaOptions) SetDefaultTenantQuota(value Quota) QuotaOptions {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetDefaultTenantQuota", value)
+	ret0, _ := ret[0].(QuotaOptions)
+	return ret0
+}
+
+// SetDefaultTenantQuota indicates an expected call of SetDefaultTenantQuota
+func (mr *MockQuotaOptionsMockRecorder) SetDefaultTenantQuota(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDefaultTenantQuota", reflect.TypeOf((*MockQuotaOptions)(nil).SetDefaultTenantQuota), value)
+}
+
+// DefaultTenantQuota mocks base method
+func (m *MockQuotaOptions) DefaultTenantQuota() Quota {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DefaultTenantQuota")
+	ret0, _ := ret[0].(Quota)
+	return ret0
+}
+
+// DefaultTenantQuota indicates an expected call of DefaultTenantQuota
+func (mr *MockQuotaOptionsMockRecorder) DefaultTenantQuota() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DefaultTenantQuota", reflect.TypeOf((*MockQuotaOptions)(nil).DefaultTenantQuota))
+}
+
 // MockOptionsManager is a mock of OptionsManager interface
 type MockOptionsManager struct {
 	ctrl     *gomock.Controller
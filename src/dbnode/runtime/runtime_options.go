@@ -75,6 +75,7 @@ type options struct {
 	clientReadConsistencyLevel           topology.ReadConsistencyLevel
 	clientWriteConsistencyLevel          topology.ConsistencyLevel
 	indexDefaultQueryTimeout             time.Duration
+	quotaOpts                            QuotaOptions
 }
 
 // NewOptions creates a new set of runtime options with defaults
@@ -92,6 +93,7 @@ func NewOptions() Options {
 		clientReadConsistencyLevel:           DefaultReadConsistencyLevel,
 		clientWriteConsistencyLevel:          DefaultWriteConsistencyLevel,
 		indexDefaultQueryTimeout:             DefaultIndexDefaultQueryTimeout,
+		quotaOpts:                            NewQuotaOptions(),
 	}
 }
 
@@ -117,7 +119,7 @@ func (o *options) Validate() error {
 
 	// tickMinimumInterval can be zero if user desires
 
-	return nil
+	return o.quotaOpts.Validate()
 }
 
 func (o *options) SetPersistRateLimitOptions(value ratelimit.Options) Options {
@@ -239,3 +241,13 @@ func (o *options) SetIndexDefaultQueryTimeout(value time.Duration) Options {
 func (o *options) IndexDefaultQueryTimeout() time.Duration {
 	return o.indexDefaultQueryTimeout
 }
+
+func (o *options) SetQuotaOptions(value QuotaOptions) Options {
+	opts := *o
+	opts.quotaOpts = value
+	return &opts
+}
+
+func (o *options) QuotaOptions() QuotaOptions {
+	return o.quotaOpts
+}
@@ -168,6 +168,75 @@ type Options interface {
 	// IndexDefaultQueryTimeout is the hard timeout value to use if none is
 	// specified for a specific query, zero specifies to use no timeout at all.
 	IndexDefaultQueryTimeout() time.Duration
+
+	// SetQuotaOptions sets the per namespace and per tenant quota options.
+	SetQuotaOptions(value QuotaOptions) Options
+
+	// QuotaOptions returns the per namespace and per tenant quota options.
+	QuotaOptions() QuotaOptions
+}
+
+// Quota is a set of limits enforced for a namespace or a tenant, a zero
+// limit specifies that the limit is not enforced.
+type Quota struct {
+	// DatapointsPerSecond is the number of datapoints that can be written
+	// per second.
+	DatapointsPerSecond int64
+
+	// NewSeriesPerSecond is the number of new series that can be inserted
+	// per second.
+	NewSeriesPerSecond int64
+
+	// MaxSeries is the number of series that can be held in memory.
+	MaxSeries int64
+
+	// MaxQueryDocs is the number of index documents a single query can match.
+	MaxQueryDocs int64
+
+	// MaxAggregateQueryTerms is the number of terms a single aggregate query
+	// can return.
+	MaxAggregateQueryTerms int64
+}
+
+// IsZero returns whether no limits are enforced by the quota.
+func (q Quota) IsZero() bool {
+	return q == Quota{}
+}
+
+// QuotaOptions is a set of per namespace and per tenant quotas, the tenant
+// of a series is the value of its tenant tag. Tenant quotas are enforced
+// separately within each namespace.
+type QuotaOptions interface {
+	// Validate will validate the quota options are valid.
+	Validate() error
+
+	// SetTenantTag sets the name of the tag that identifies the tenant of a
+	// series, tenant quotas are not enforced if it is empty.
+	SetTenantTag(value string) QuotaOptions
+
+	// TenantTag returns the name of the tag that identifies the tenant of a
+	// series, tenant quotas are not enforced if it is empty.
+	TenantTag() string
+
+	// SetNamespaceQuotas sets the quotas by namespace ID.
+	SetNamespaceQuotas(value map[string]Quota) QuotaOptions
+
+	// NamespaceQuotas returns the quotas by namespace ID.
+	NamespaceQuotas() map[string]Quota
+
+	// SetTenantQuotas sets the quotas by tenant.
+	SetTenantQuotas(value map[string]Quota) QuotaOptions
+
+	// TenantQuotas returns the quotas by tenant.
+	TenantQuotas() map[string]Quota
+
+	// SetDefaultTenantQuota sets the quota for tenants without a quota of
+	// their own.
+	SetDefaultTenantQuota(value Quota) QuotaOptions
+
+	// DefaultTenantQuota returns the quota for tenants without a quota of
+	// their own.
+	DefaultTenantQuota() Quota
 }
 
 // OptionsManager updates and supplies runtime options.
@@ -44,6 +44,7 @@ import (
 	queryconfig "github.com/m3db/m3/src/cmd/services/m3query/config"
 	"github.com/m3db/m3/src/dbnode/client"
 	"github.com/m3db/m3/src/dbnode/encoding"
+	"github.com/m3db/m3/src/dbnode/encoding/histogram"
 	"github.com/m3db/m3/src/dbnode/encoding/m3tsz"
 	"github.com/m3db/m3/src/dbnode/encoding/proto"
 	"github.com/m3db/m3/src/dbnode/environment"
@@ -70,6 +71,7 @@ import (
 	xtchannel "github.com/m3db/m3/src/dbnode/x/tchannel"
 	"github.com/m3db/m3/src/dbnode/x/xio"
 	"github.com/m3db/m3/src/dbnode/x/xpool"
+	"github.com/m3db/m3/src/m3ninx/index/segment/fst"
 	"github.com/m3db/m3/src/m3ninx/postings"
 	"github.com/m3db/m3/src/m3ninx/postings/roaring"
 	"github.com/m3db/m3/src/query/api/v1/handler/placement"
@@ -92,6 +94,7 @@ import (
 	"github.com/uber-go/tally"
 	"go.etcd.io/etcd/embed"
 	"go.uber.org/zap"
+	yaml "gopkg.in/yaml.v2"
 )
 
 const (
@@ -379,6 +382,7 @@ func Run(runOpts RunOptions) {
 	var (
 		plCacheConfig  = cfg.Cache.PostingsListConfiguration()
 		plCacheSize    = plCacheConfig.SizeOrDefault()
+		plCachePersist = plCacheConfig.PersistOrDefault()
 		plCacheOptions = index.PostingsListCacheOptions{
 			InstrumentOptions: opts.InstrumentOptions().
 				SetMetricsScope(scope.SubScope("postings-list-cache")),
@@ -399,8 +403,11 @@ func Run(runOpts RunOptions) {
 	indexOpts = indexOpts.SetInsertMode(insertMode).
 		SetPostingsListCache(postingsListCache).
 		SetReadThroughSegmentOptions(index.ReadThroughSegmentOptions{
-			CacheRegexp: plCacheConfig.CacheRegexpOrDefault(),
-			CacheTerms:  plCacheConfig.CacheTermsOrDefault(),
+			CacheRegexp:            plCacheConfig.CacheRegexpOrDefault(),
+			CacheTerms:             plCacheConfig.CacheTermsOrDefault(),
+			PersistCache:           plCachePersist.Enabled,
+			PersistCacheMaxEntries: plCachePersist.MaxEntriesPerSegmentOrDefault(),
+			PersistCacheInterval:   plCachePersist.IntervalOrDefault(),
 		}).
 		SetMmapReporter(mmapReporter)
 	opts = opts.SetIndexOptions(indexOpts)
@@ -412,6 +419,15 @@ func Run(runOpts RunOptions) {
 			SetTickMinimumInterval(tick.MinimumInterval)
 	}
 
+	quotaOpts := m3dbruntime.NewQuotaOptions()
+	if quotas := cfg.Quotas; quotas != nil {
+		quotaOpts, err = quotas.NewQuotaOptions()
+		if err != nil {
+			logger.Fatal("could not create quota options", zap.Error(err))
+		}
+		runtimeOpts = runtimeOpts.SetQuotaOptions(quotaOpts)
+	}
+
 	runtimeOptsMgr := m3dbruntime.NewOptionsManager()
 	if err := runtimeOptsMgr.Update(runtimeOpts); err != nil {
 		logger.Fatal("could not set initial runtime options", zap.Error(err))
@@ -459,6 +475,9 @@ func Run(runOpts RunOptions) {
 		SetForceIndexSummariesMmapMemory(cfg.Filesystem.ForceIndexSummariesMmapMemoryOrDefault()).
 		SetForceBloomFilterMmapMemory(cfg.Filesystem.ForceBloomFilterMmapMemoryOrDefault()).
 		SetIndexBloomFilterFalsePositivePercent(cfg.Filesystem.BloomFilterFalsePositivePercentOrDefault()).
+		SetFSTWriterOptions(fst.WriterOptions{
+			DocumentsDictionary: cfg.Index.DictionaryEncodedDocuments,
+		}).
 		SetMmapReporter(mmapReporter)
 
 	var commitLogQueueSize int
@@ -502,6 +521,7 @@ func Run(runOpts RunOptions) {
 		SetStrategy(commitlog.StrategyWriteBehind).
 		SetFlushSize(cfg.CommitLog.FlushMaxBytes).
 		SetFlushInterval(cfg.CommitLog.FlushEvery).
+		SetCompressionType(cfg.CommitLog.Compression).
 		SetBacklogQueueSize(commitLogQueueSize).
 		SetBacklogQueueChannelSize(commitLogQueueChannelSize))
 
@@ -589,6 +609,9 @@ func Run(runOpts RunOptions) {
 		SetCheckedBytesWrapperPool(opts.CheckedBytesWrapperPool()).
 		SetMaxOutstandingWriteRequests(cfg.Limits.MaxOutstandingWriteRequests).
 		SetMaxOutstandingReadRequests(cfg.Limits.MaxOutstandingReadRequests)
+	if cfg.Backup != nil {
+		ttopts = ttopts.SetBackupAllowedDestinations(cfg.Backup.AllowedDestinations)
+	}
 
 	// Start servers before constructing the DB so orchestration tools can check health endpoints
 	// before topology is set.
@@ -824,6 +847,8 @@ func Run(runOpts RunOptions) {
 			}
 		})
 
+	kvWatchQuotas(syncCfg.KVStore, logger, runtimeOptsMgr, quotaOpts)
+
 	// Start the cluster services now that the M3DB client is available.
 	tchannelthriftClusterClose, err := ttcluster.NewServer(m3dbClient,
 		cfg.ClusterListenAddress, contextPool, tchannelOpts).ListenAndServe()
@@ -1072,6 +1097,32 @@ func kvWatchClientConsistencyLevels(
 		})
 }
 
+func kvWatchQuotas(
+	store kv.Store,
+	logger *zap.Logger,
+	runtimeOptsMgr m3dbruntime.OptionsManager,
+	defaultQuotaOpts m3dbruntime.QuotaOptions,
+) {
+	kvWatchStringValue(store, logger,
+		kvconfig.QuotasKey,
+		func(value string) error {
+			var cfg config.QuotasConfiguration
+			if err := yaml.Unmarshal([]byte(value), &cfg); err != nil {
+				return err
+			}
+			quotaOpts, err := cfg.NewQuotaOptions()
+			if err != nil {
+				return err
+			}
+			return runtimeOptsMgr.Update(runtimeOptsMgr.Get().
+				SetQuotaOptions(quotaOpts))
+		},
+		func() error {
+			return runtimeOptsMgr.Update(runtimeOptsMgr.Get().
+				SetQuotaOptions(defaultQuotaOpts))
+		})
+}
+
 func kvWatchStringValue(
 	store kv.Store,
 	logger *zap.Logger,
@@ -1396,11 +1447,29 @@ func withEncodingAndPoolingOptions(
 		return m3tsz.NewEncoder(time.Time{}, nil, m3tsz.DefaultIntOptimizationEnabled, encodingOpts)
 	})
 
+	// Histogram encoders are used by the namespaces with histograms enabled,
+	// which is not supported with the proto encoding since proto iterators
+	// cannot read histogram streams.
+	var histogramEncoderPool encoding.EncoderPool
+	if cfg.Proto == nil || !cfg.Proto.Enabled {
+		histogramEncoderPool = encoding.NewEncoderPool(
+			poolOptions(
+				policy.HistogramEncoderPool,
+				scope.SubScope("histogram-encoder-pool")))
+		// NB: Histogram encoders return themselves to their own pool.
+		histogramEncodingOpts := encodingOpts.SetEncoderPool(histogramEncoderPool)
+		histogramEncoderPool.Init(func() encoding.Encoder {
+			return histogram.NewEncoder(time.Time{}, histogramEncodingOpts)
+		})
+	}
+
 	iteratorPool.Init(func(r io.Reader, descr namespace.SchemaDescr) encoding.ReaderIterator {
 		if cfg.Proto != nil && cfg.Proto.Enabled {
 			return proto.NewIterator(r, descr, encodingOpts)
 		}
-		return m3tsz.NewReaderIterator(r, m3tsz.DefaultIntOptimizationEnabled, encodingOpts)
+		// NB: The histogram iterator also reads M3TSZ streams so that series
+		// of namespaces with and without histograms enabled can be read.
+		return histogram.NewIterator(r, descr, encodingOpts)
 	})
 
 	multiIteratorPool.Init(func(r io.Reader, descr namespace.SchemaDescr) encoding.ReaderIterator {
@@ -1424,6 +1493,7 @@ func withEncodingAndPoolingOptions(
 		SetBytesPool(bytesPool).
 		SetContextPool(contextPool).
 		SetEncoderPool(encoderPool).
+		SetHistogramEncoderPool(histogramEncoderPool).
 		SetReaderIteratorPool(iteratorPool).
 		SetMultiReaderIteratorPool(multiIteratorPool).
 		SetIdentifierPool(identifierPool).
@@ -742,6 +742,13 @@ func (s *commitLogSource) bootstrapShardBlockSnapshot(
 		nsCtx      = namespace.NewContextFrom(ns)
 	)
 
+	// Series deleted after the snapshot was taken must not have the snapshot
+	// data loaded back, the snapshot was taken before the tombstone existed.
+	tombstones, err := fs.ReadTombstones(fsOpts.FilePathPrefix(), ns.ID(), shard)
+	if err != nil {
+		return err
+	}
+
 	// Bootstrap the snapshot file.
 	reader, err := s.newReaderFn(bytesPool, fsOpts)
 	if err != nil {
@@ -785,6 +792,15 @@ func (s *commitLogSource) bootstrapShardBlockSnapshot(
 			break
 		}
 
+		deletedAt, deleted := tombstones.DeletedAt(id)
+		if deleted && mostRecentCompleteSnapshot.CachedSnapshotTime.Before(deletedAt) {
+			id.Finalize()
+			tags.Close()
+			data.DecRef()
+			data.Finalize()
+			continue
+		}
+
 		dbBlock := blocksPool.Get()
 		dbBlock.Reset(blockStart, blockSize,
 			ts.NewSegment(data, nil, ts.FinalizeHead), nsCtx)
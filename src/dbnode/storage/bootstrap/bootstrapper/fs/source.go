@@ -212,13 +212,13 @@ func (s *fileSystemSource) availability(
 ) (result.ShardTimeRanges, error) {
 	result := make(map[uint32]xtime.Ranges, len(shardsTimeRanges))
 	for shard, ranges := range shardsTimeRanges {
-		result[shard] = s.shardAvailability(md.ID(), shard, ranges)
+		result[shard] = s.shardAvailability(md, shard, ranges)
 	}
 	return result, nil
 }
 
 func (s *fileSystemSource) shardAvailability(
-	namespace ident.ID,
+	md namespace.Metadata,
 	shard uint32,
 	targetRangesForShard xtime.Ranges,
 ) xtime.Ranges {
@@ -226,8 +226,11 @@ func (s *fileSystemSource) shardAvailability(
 		return xtime.Ranges{}
 	}
 
-	readInfoFilesResults := fs.ReadInfoFiles(s.fsopts.FilePathPrefix(),
-		namespace, shard, s.fsopts.InfoReaderBufferSize(), s.fsopts.DecodingOptions())
+	// Filesets of blocks that were moved to the cold tier of the namespace
+	// are available as well.
+	coldTierFilePathPrefix := md.Options().ColdTierOptions().FilePathPrefix()
+	readInfoFilesResults := fs.ReadTieredInfoFiles(s.fsopts.FilePathPrefix(), coldTierFilePathPrefix,
+		md.ID(), shard, s.fsopts.InfoReaderBufferSize(), s.fsopts.DecodingOptions())
 
 	var tr xtime.Ranges
 	for i := 0; i < len(readInfoFilesResults); i++ {
@@ -235,7 +238,7 @@ func (s *fileSystemSource) shardAvailability(
 		if err := result.Err.Error(); err != nil {
 			s.log.Error("unable to read info files in shardAvailability",
 				zap.Uint32("shard", shard),
-				zap.Stringer("namespace", namespace),
+				zap.Stringer("namespace", md.ID()),
 				zap.Error(err),
 				zap.Any("targetRangesForShard", targetRangesForShard),
 				zap.String("filepath", result.Err.Filepath()),
@@ -270,6 +273,8 @@ func (s *fileSystemSource) bootstrapFromReaders(
 		// NB(bodu): Since we are re-using the same builder for all bootstrapped index blocks,
 		// it is not thread safe and requires reset after every processed index block.
 		s.builder.Builder().Reset(0)
+		s.builder.Builder().SetFieldAnalyzers(
+			index.NewFieldAnalyzers(ns.Options().IndexOptions()))
 
 		s.loadShardReadersDataIntoShardResult(run, ns, accumulator,
 			runOpts, runResult, resultOpts, timeWindowReaders, readerPool)
@@ -721,7 +726,7 @@ func (s *fileSystemSource) bootstrapDataRunResultFromAvailability(
 		if ranges.IsEmpty() {
 			continue
 		}
-		availability := s.shardAvailability(md.ID(), shard, ranges)
+		availability := s.shardAvailability(md, shard, ranges)
 		remaining := ranges.RemoveRanges(availability)
 		if !remaining.IsEmpty() {
 			unfulfilled.AddRanges(result.ShardTimeRanges{
@@ -750,6 +755,22 @@ func (s *fileSystemSource) bootstrapFromIndexPersistedBlocks(
 	infoFiles := fs.ReadIndexInfoFiles(s.fsopts.FilePathPrefix(), ns.ID(),
 		s.fsopts.InfoReaderBufferSize())
 
+	// Blocks compacted from adjacent blocks supersede any filesets of the
+	// blocks they were compacted from that were not yet removed.
+	var compactedRanges xtime.Ranges
+	for _, infoFile := range infoFiles {
+		if infoFile.Err.Error() != nil {
+			continue
+		}
+		if blockSize := time.Duration(infoFile.Info.BlockSize); blockSize > indexBlockSize {
+			start := xtime.UnixNano(infoFile.Info.BlockStart).ToTime()
+			compactedRanges = compactedRanges.AddRange(xtime.Range{
+				Start: start,
+				End:   start.Add(blockSize),
+			})
+		}
+	}
+
 	for _, infoFile := range infoFiles {
 		if err := infoFile.Err.Error(); err != nil {
 			s.log.Error("unable to read index info file",
@@ -763,10 +784,20 @@ func (s *fileSystemSource) bootstrapFromIndexPersistedBlocks(
 
 		info := infoFile.Info
 		indexBlockStart := xtime.UnixNano(info.BlockStart).ToTime()
+		blockSize := indexBlockSize
+		if size := time.Duration(info.BlockSize); size > indexBlockSize {
+			blockSize = size
+		}
 		indexBlockRange := xtime.Range{
 			Start: indexBlockStart,
-			End:   indexBlockStart.Add(indexBlockSize),
+			End:   indexBlockStart.Add(blockSize),
+		}
+		if blockSize == indexBlockSize &&
+			compactedRanges.Overlaps(indexBlockRange) {
+			// Already contained in a compacted block.
+			continue
 		}
+
 		willFulfill := result.ShardTimeRanges{}
 		for _, shard := range info.Shards {
 			tr, ok := shardsTimeRanges[shard]
@@ -823,6 +854,9 @@ func (s *fileSystemSource) bootstrapFromIndexPersistedBlocks(
 		}
 		indexBlock := result.NewIndexBlock(indexBlockStart, persistedSegments,
 			segmentsFulfilled)
+		if blockSize != indexBlockSize {
+			indexBlock = indexBlock.SetBlockSize(blockSize)
+		}
 		// NB(r): Don't need to call MarkFulfilled on the IndexResults here
 		// as we've already passed the ranges fulfilled to the block that
 		// we place in the IndexResuts with the call to Add(...).
@@ -676,6 +676,8 @@ func (s *peersSource) readIndex(
 		// NB(bodu): Since we are re-using the same builder for all bootstrapped index blocks,
 		// it is not thread safe and requires reset after every processed index block.
 		s.builder.Builder().Reset(0)
+		s.builder.Builder().SetFieldAnalyzers(
+			index.NewFieldAnalyzers(ns.Options().IndexOptions()))
 
 		// NB(bodu): This is fetching the data for all shards for a block of time.
 		remainingRanges, timesWithErrors := s.processReaders(
@@ -278,7 +278,7 @@ func BuildBootstrapIndexSegment(
 
 	compactor.Lock()
 	defer compactor.Unlock()
-	seg, err := compactor.Compactor.CompactUsingBuilder(builder, nil, mmap.ReporterOptions{
+	seg, err := compactor.Compactor.CompactUsingBuilder(builder, nil, nil, mmap.ReporterOptions{
 		Context: mmap.Context{
 			Name: mmapBootstrapIndexName,
 		},
@@ -111,7 +111,8 @@ func newShardReaders(
 	tr xtime.Ranges,
 	logger *zap.Logger,
 ) ShardReaders {
-	readInfoFilesResults := fs.ReadInfoFiles(fsOpts.FilePathPrefix(),
+	coldTierFilePathPrefix := ns.Options().ColdTierOptions().FilePathPrefix()
+	readInfoFilesResults := fs.ReadTieredInfoFiles(fsOpts.FilePathPrefix(), coldTierFilePathPrefix,
 		ns.ID(), shard, fsOpts.InfoReaderBufferSize(), fsOpts.DecodingOptions())
 	if len(readInfoFilesResults) == 0 {
 		// No readers.
@@ -159,6 +160,7 @@ func newShardReaders(
 				Shard:      shard,
 				BlockStart: blockStart,
 			},
+			ColdTierFilePathPrefix: coldTierFilePathPrefix,
 		}
 		if err := r.Open(openOpts); err != nil {
 			logger.Error("unable to open fileset files",
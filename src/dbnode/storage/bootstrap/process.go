@@ -161,7 +161,7 @@ func (b bootstrapProcess) Run(
 		Namespaces: NewNamespacesMap(NamespacesMapOptions{}),
 	}
 	for _, namespace := range namespaces {
-		ropts := namespace.Metadata.Options().RetentionOptions()
+		ropts := warmWriteRetentionOptions(namespace.Metadata)
 		idxopts := namespace.Metadata.Options().IndexOptions()
 		dataRanges := b.targetRangesForData(at, ropts)
 		indexRanges := b.targetRangesForIndex(at, ropts, idxopts)
@@ -304,6 +304,13 @@ func (b bootstrapProcess) logBootstrapResult(
 	b.log.Info("bootstrap range completed", logFields...)
 }
 
+// warmWriteRetentionOptions returns the retention options that bound which
+// blocks of the namespace can still receive warm writes, blocks that can are
+// bootstrapped without persistence so that they are later warm flushed.
+func warmWriteRetentionOptions(md namespace.Metadata) retention.Options {
+	return namespace.WarmWriteRetentionOptions(md.Options())
+}
+
 func (b bootstrapProcess) targetRangesForData(
 	at time.Time,
 	ropts retention.Options,
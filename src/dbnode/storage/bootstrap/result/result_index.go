@@ -255,6 +255,20 @@ func (b IndexBlock) BlockStart() time.Time {
 	return b.blockStart
 }
 
+// BlockSize returns the block size of a block compacted from adjacent
+// blocks, or zero for a block of the namespace index block size.
+func (b IndexBlock) BlockSize() time.Duration {
+	return b.blockSize
+}
+
+// SetBlockSize returns the index block with the block size of a block
+// compacted from adjacent blocks.
+func (b IndexBlock) SetBlockSize(value time.Duration) IndexBlock {
+	r := b
+	r.blockSize = value
+	return r
+}
+
 // Segments returns the segments.
 func (b IndexBlock) Segments() []segment.Segment {
 	return b.segments
@@ -270,6 +284,10 @@ func (b IndexBlock) Fulfilled() ShardTimeRanges {
 // as they see necessary.
 func (b IndexBlock) Merged(other IndexBlock) IndexBlock {
 	r := b
+	if other.blockSize > r.blockSize {
+		// The merged block covers the largest of the blocks.
+		r.blockSize = other.blockSize
+	}
 	if len(other.segments) > 0 {
 		r.segments = append(r.segments, other.segments...)
 	}
@@ -98,6 +98,21 @@ func TestIndexResultAdd(t *testing.T) {
 	require.Equal(t, testRanges, results.Unfulfilled())
 }
 
+func TestIndexBlockMergedBlockSize(t *testing.T) {
+	t0 := time.Now().Truncate(4 * time.Hour)
+	compacted := NewIndexBlock(t0, nil,
+		NewShardTimeRanges(t0, t0.Add(4*time.Hour), 1)).SetBlockSize(4 * time.Hour)
+	block := NewIndexBlock(t0, nil, NewShardTimeRanges(t0, t0.Add(time.Hour), 2))
+	require.Equal(t, time.Duration(0), block.BlockSize())
+
+	merged := block.Merged(compacted)
+	require.Equal(t, 4*time.Hour, merged.BlockSize())
+	require.Equal(t, 4*time.Hour, compacted.Merged(block).BlockSize())
+	expected := NewShardTimeRanges(t0, t0.Add(4*time.Hour), 1)
+	expected.AddRanges(NewShardTimeRanges(t0, t0.Add(time.Hour), 2))
+	require.True(t, merged.Fulfilled().Equal(expected))
+}
+
 func TestShardTimeRangesToUnfulfilledIndexResult(t *testing.T) {
 	str := ShardTimeRanges{
 		0: xtime.NewRanges(xtime.Range{
@@ -70,6 +70,7 @@ type IndexBuilder struct {
 // IndexBlock contains the bootstrap data structures for an index block.
 type IndexBlock struct {
 	blockStart time.Time
+	blockSize  time.Duration
 	segments   []segment.Segment
 	fulfilled  ShardTimeRanges
 }
@@ -82,12 +82,16 @@ type cleanupManagerMetrics struct {
 	deletedCommitlogFile        tally.Counter
 	deletedSnapshotFile         tally.Counter
 	deletedSnapshotMetadataFile tally.Counter
+	movedToColdTierFileSet      tally.Counter
+	seriesTTLRewrittenBlock     tally.Counter
 }
 
 func newCleanupManagerMetrics(scope tally.Scope) cleanupManagerMetrics {
 	clScope := scope.SubScope("commitlog")
 	sScope := scope.SubScope("snapshot")
 	smScope := scope.SubScope("snapshot-metadata")
+	ctScope := scope.SubScope("cold-tier")
+	ttlScope := scope.SubScope("series-ttl")
 	return cleanupManagerMetrics{
 		status:                      scope.Gauge("cleanup"),
 		corruptCommitlogFile:        clScope.Counter("corrupt"),
@@ -96,6 +100,8 @@ func newCleanupManagerMetrics(scope tally.Scope) cleanupManagerMetrics {
 		deletedCommitlogFile:        clScope.Counter("deleted"),
 		deletedSnapshotFile:         sScope.Counter("deleted"),
 		deletedSnapshotMetadataFile: smScope.Counter("deleted"),
+		movedToColdTierFileSet:      ctScope.Counter("moved"),
+		seriesTTLRewrittenBlock:     ttlScope.Counter("rewritten-blocks"),
 	}
 }
 
@@ -139,6 +145,11 @@ func (m *cleanupManager) Cleanup(t time.Time) error {
 			"encountered errors when cleaning up data files for %v: %v", t, err))
 	}
 
+	if err := m.dropExpiredSeries(); err != nil {
+		multiErr = multiErr.Add(fmt.Errorf(
+			"encountered errors when dropping expired series for %v: %v", t, err))
+	}
+
 	if err := m.cleanupExpiredIndexFiles(t); err != nil {
 		multiErr = multiErr.Add(fmt.Errorf(
 			"encountered errors when cleaning up index files for %v: %v", t, err))
@@ -198,16 +209,19 @@ func (m *cleanupManager) deleteInactiveNamespaceFiles() error {
 // deleteInactiveDataFiles will delete data files for shards that the node no longer owns
 // which can occur in the case of topology changes
 func (m *cleanupManager) deleteInactiveDataFiles() error {
-	return m.deleteInactiveDataFileSetFiles(fs.NamespaceDataDirPath)
+	return m.deleteInactiveDataFileSetFiles(fs.NamespaceDataDirPath, true)
 }
 
 // deleteInactiveDataSnapshotFiles will delete snapshot files for shards that the node no longer owns
 // which can occur in the case of topology changes
 func (m *cleanupManager) deleteInactiveDataSnapshotFiles() error {
-	return m.deleteInactiveDataFileSetFiles(fs.NamespaceSnapshotsDirPath)
+	return m.deleteInactiveDataFileSetFiles(fs.NamespaceSnapshotsDirPath, false)
 }
 
-func (m *cleanupManager) deleteInactiveDataFileSetFiles(filesetFilesDirPathFn func(string, ident.ID) string) error {
+func (m *cleanupManager) deleteInactiveDataFileSetFiles(
+	filesetFilesDirPathFn func(string, ident.ID) string,
+	includeColdTier bool,
+) error {
 	multiErr := xerrors.NewMultiError()
 	filePathPrefix := m.database.Options().CommitLogOptions().FilesystemOptions().FilePathPrefix()
 	namespaces, err := m.database.GetOwnedNamespaces()
@@ -222,6 +236,11 @@ func (m *cleanupManager) deleteInactiveDataFileSetFiles(filesetFilesDirPathFn fu
 			activeShards = append(activeShards, shard)
 		}
 		multiErr = multiErr.Add(m.deleteInactiveDirectoriesFn(namespaceDirPath, activeShards))
+
+		if coldTierOpts := n.Options().ColdTierOptions(); includeColdTier && coldTierOpts.Enabled() {
+			coldTierDirPath := filesetFilesDirPathFn(coldTierOpts.FilePathPrefix(), n.ID())
+			multiErr = multiErr.Add(m.deleteInactiveDirectoriesFn(coldTierDirPath, activeShards))
+		}
 	}
 
 	return multiErr.FinalError()
@@ -241,10 +260,46 @@ func (m *cleanupManager) cleanupDataFiles(t time.Time) error {
 		shards := n.GetOwnedShards()
 		multiErr = multiErr.Add(m.cleanupExpiredNamespaceDataFiles(earliestToRetain, shards))
 		multiErr = multiErr.Add(m.cleanupCompactedNamespaceDataFiles(shards))
+		if coldTierOpts := n.Options().ColdTierOptions(); coldTierOpts.Enabled() {
+			endBefore := t.Add(-coldTierOpts.Age())
+			multiErr = multiErr.Add(m.moveNamespaceDataFilesToColdTier(endBefore, shards))
+		}
 	}
 	return multiErr.FinalError()
 }
 
+// dropExpiredSeries rewrites the blocks that contain data of series whose TTL
+// has expired so that their data is removed from disk.
+func (m *cleanupManager) dropExpiredSeries() error {
+	namespaces, err := m.database.GetOwnedNamespaces()
+	if err != nil {
+		return err
+	}
+
+	// Rewritten blocks are written through a flush persist so that they are
+	// subject to the same rate limit as flushes.
+	flushPersist, err := m.opts.PersistManager().StartFlushPersist()
+	if err != nil {
+		return err
+	}
+
+	multiErr := xerrors.NewMultiError()
+	for _, n := range namespaces {
+		if !n.Options().CleanupEnabled() {
+			continue
+		}
+		numBlocks, err := n.DropExpiredSeries(flushPersist)
+		multiErr = multiErr.Add(err)
+		m.metrics.seriesTTLRewrittenBlock.Inc(int64(numBlocks))
+	}
+
+	if err := flushPersist.DoneFlush(); err != nil {
+		multiErr = multiErr.Add(err)
+	}
+
+	return multiErr.FinalError()
+}
+
 func (m *cleanupManager) cleanupExpiredIndexFiles(t time.Time) error {
 	namespaces, err := m.database.GetOwnedNamespaces()
 	if err != nil {
@@ -287,6 +342,19 @@ func (m *cleanupManager) cleanupCompactedNamespaceDataFiles(shards []databaseSha
 	return multiErr.FinalError()
 }
 
+func (m *cleanupManager) moveNamespaceDataFilesToColdTier(endBefore time.Time, shards []databaseShard) error {
+	multiErr := xerrors.NewMultiError()
+	for _, shard := range shards {
+		moved, err := shard.MoveFileSetsToColdTier(endBefore)
+		if err != nil {
+			multiErr = multiErr.Add(err)
+		}
+		m.metrics.movedToColdTierFileSet.Inc(int64(moved))
+	}
+
+	return multiErr.FinalError()
+}
+
 // The goal of the cleanupSnapshotsAndCommitlogs function is to delete all snapshots files, snapshot metadata
 // files, and commitlog files except for those that are currently required for recovery from a node failure.
 // According to the snapshotting / commitlog rotation logic, the files that are required for a complete
@@ -296,6 +296,7 @@ func TestCleanupManagerCleanupCommitlogsAndSnapshots(t *testing.T) {
 				ns.EXPECT().ID().Return(ident.StringID(fmt.Sprintf("ns%d", i))).AnyTimes()
 				ns.EXPECT().Options().Return(nsOpts).AnyTimes()
 				ns.EXPECT().NeedsFlush(gomock.Any(), gomock.Any()).Return(false, nil).AnyTimes()
+				ns.EXPECT().DropExpiredSeries(gomock.Any()).Return(0, nil).AnyTimes()
 				ns.EXPECT().GetOwnedShards().Return(shards).AnyTimes()
 				namespaces = append(namespaces, ns)
 			}
@@ -348,6 +349,7 @@ func TestCleanupManagerNamespaceCleanup(t *testing.T) {
 	ns.EXPECT().ID().Return(ident.StringID("ns")).AnyTimes()
 	ns.EXPECT().Options().Return(nsOpts).AnyTimes()
 	ns.EXPECT().NeedsFlush(gomock.Any(), gomock.Any()).Return(false, nil).AnyTimes()
+	ns.EXPECT().DropExpiredSeries(gomock.Any()).Return(0, nil).AnyTimes()
 	ns.EXPECT().GetOwnedShards().Return(nil).AnyTimes()
 
 	idx := NewMocknamespaceIndex(ctrl)
@@ -412,6 +414,7 @@ func TestCleanupDataAndSnapshotFileSetFiles(t *testing.T) {
 	ns.EXPECT().GetOwnedShards().Return([]databaseShard{shard}).AnyTimes()
 	ns.EXPECT().ID().Return(ident.StringID("nsID")).AnyTimes()
 	ns.EXPECT().NeedsFlush(gomock.Any(), gomock.Any()).Return(false, nil).AnyTimes()
+	ns.EXPECT().DropExpiredSeries(gomock.Any()).Return(0, nil).AnyTimes()
 	namespaces := []databaseNamespace{ns}
 
 	db := newMockdatabase(ctrl, namespaces...)
@@ -421,6 +424,73 @@ func TestCleanupDataAndSnapshotFileSetFiles(t *testing.T) {
 	require.NoError(t, mgr.Cleanup(ts))
 }
 
+func TestCleanupDataFileSetFilesMovesToColdTier(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ts := timeFor(36000)
+
+	coldTierAge := 2 * time.Hour
+	nsOpts := namespaceOptions.SetColdTierOptions(namespace.NewColdTierOptions().
+		SetFilePathPrefix("cold").
+		SetAge(coldTierAge))
+	ns := NewMockdatabaseNamespace(ctrl)
+	ns.EXPECT().Options().Return(nsOpts).AnyTimes()
+
+	shard := NewMockdatabaseShard(ctrl)
+	expectedEarliestToRetain := retention.FlushTimeStart(ns.Options().RetentionOptions(), ts)
+	shard.EXPECT().CleanupExpiredFileSets(expectedEarliestToRetain).Return(nil)
+	shard.EXPECT().CleanupCompactedFileSets().Return(nil)
+	shard.EXPECT().MoveFileSetsToColdTier(ts.Add(-coldTierAge)).Return(2, nil)
+	shard.EXPECT().ID().Return(uint32(0)).AnyTimes()
+	ns.EXPECT().GetOwnedShards().Return([]databaseShard{shard}).AnyTimes()
+	ns.EXPECT().ID().Return(ident.StringID("nsID")).AnyTimes()
+	ns.EXPECT().NeedsFlush(gomock.Any(), gomock.Any()).Return(false, nil).AnyTimes()
+	ns.EXPECT().DropExpiredSeries(gomock.Any()).Return(0, nil).AnyTimes()
+	namespaces := []databaseNamespace{ns}
+
+	db := newMockdatabase(ctrl, namespaces...)
+	db.EXPECT().GetOwnedNamespaces().Return(namespaces, nil).AnyTimes()
+	scope := tally.NewTestScope("", nil)
+	mgr := newCleanupManager(db, newNoopFakeActiveLogs(), scope).(*cleanupManager)
+
+	var inactiveDirPaths []string
+	mgr.deleteInactiveDirectoriesFn = func(parentDirPath string, _ []string) error {
+		inactiveDirPaths = append(inactiveDirPaths, parentDirPath)
+		return nil
+	}
+
+	require.NoError(t, mgr.Cleanup(ts))
+	require.Contains(t, inactiveDirPaths, fs.NamespaceDataDirPath("cold", ident.StringID("nsID")))
+	require.NotContains(t, inactiveDirPaths, fs.NamespaceSnapshotsDirPath("cold", ident.StringID("nsID")))
+
+	counters := scope.Snapshot().Counters()
+	require.Equal(t, int64(2), counters["cold-tier.moved+"].Value())
+}
+
+func TestCleanupManagerDropsExpiredSeries(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ts := timeFor(36000)
+
+	ns := NewMockdatabaseNamespace(ctrl)
+	ns.EXPECT().Options().Return(namespaceOptions).AnyTimes()
+	ns.EXPECT().GetOwnedShards().Return(nil).AnyTimes()
+	ns.EXPECT().ID().Return(ident.StringID("nsID")).AnyTimes()
+	ns.EXPECT().NeedsFlush(gomock.Any(), gomock.Any()).Return(false, nil).AnyTimes()
+	ns.EXPECT().DropExpiredSeries(gomock.Any()).Return(3, nil)
+	namespaces := []databaseNamespace{ns}
+
+	db := newMockdatabase(ctrl, namespaces...)
+	db.EXPECT().GetOwnedNamespaces().Return(namespaces, nil).AnyTimes()
+	scope := tally.NewTestScope("", nil)
+	mgr := newCleanupManager(db, newNoopFakeActiveLogs(), scope).(*cleanupManager)
+
+	require.NoError(t, mgr.Cleanup(ts))
+
+	counters := scope.Snapshot().Counters()
+	require.Equal(t, int64(3), counters["series-ttl.rewritten-blocks+"].Value())
+}
+
 type deleteInactiveDirectoriesCall struct {
 	parentDirPath  string
 	activeDirNames []string
@@ -0,0 +1,217 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"os"
+	"sync"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/clock"
+	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/dbnode/ratelimit"
+	xerrors "github.com/m3db/m3/src/x/errors"
+
+	"github.com/uber-go/tally"
+)
+
+const compactionBytesPerMegabit = 1024 * 1024 / 8
+
+// fileSetCompactionResult is the result of compacting the filesets of a
+// shard or namespace.
+type fileSetCompactionResult struct {
+	numBlocks      int
+	numVolumes     int
+	bytesReclaimed int64
+}
+
+func (r *fileSetCompactionResult) add(other fileSetCompactionResult) {
+	r.numBlocks += other.numBlocks
+	r.numVolumes += other.numVolumes
+	r.bytesReclaimed += other.bytesReclaimed
+}
+
+// compactionRateLimiter limits the rate at which volumes are read by a
+// compaction, after each compacted block it sleeps for long enough that the
+// bytes read since the compaction started stay within the configured limit.
+type compactionRateLimiter struct {
+	opts    ratelimit.Options
+	nowFn   clock.NowFn
+	sleepFn func(time.Duration)
+
+	start     time.Time
+	bytesRead int64
+}
+
+func newCompactionRateLimiter(
+	opts ratelimit.Options,
+	nowFn clock.NowFn,
+) *compactionRateLimiter {
+	return &compactionRateLimiter{
+		opts:    opts,
+		nowFn:   nowFn,
+		sleepFn: time.Sleep,
+		start:   nowFn(),
+	}
+}
+
+// Limit accounts for the bytes read compacting a block and sleeps if the
+// compaction is running ahead of the rate limit, a nil limiter never sleeps.
+func (l *compactionRateLimiter) Limit(bytesRead int64) {
+	if l == nil {
+		return
+	}
+
+	rateLimitMbps := l.opts.LimitMbps()
+	if !l.opts.LimitEnabled() || rateLimitMbps <= 0.0 {
+		return
+	}
+
+	l.bytesRead += bytesRead
+	target := time.Duration(float64(time.Second) * float64(l.bytesRead) /
+		(rateLimitMbps * compactionBytesPerMegabit))
+	if elapsed := l.nowFn().Sub(l.start); elapsed < target {
+		l.sleepFn(target - elapsed)
+	}
+}
+
+type compactionManager struct {
+	sync.RWMutex
+
+	database database
+	opts     Options
+	pm       persist.Manager
+
+	compactionInProgress bool
+	metrics              compactionManagerMetrics
+}
+
+type compactionManagerMetrics struct {
+	status         tally.Gauge
+	blocks         tally.Counter
+	volumes        tally.Counter
+	bytesReclaimed tally.Counter
+	errors         tally.Counter
+}
+
+func newCompactionManagerMetrics(scope tally.Scope) compactionManagerMetrics {
+	cScope := scope.SubScope("compaction")
+	return compactionManagerMetrics{
+		status:         scope.Gauge("compaction"),
+		blocks:         cScope.Counter("blocks"),
+		volumes:        cScope.Counter("volumes"),
+		bytesReclaimed: cScope.Counter("bytes-reclaimed"),
+		errors:         cScope.Counter("errors"),
+	}
+}
+
+func newCompactionManager(
+	database database,
+	scope tally.Scope,
+) databaseCompactionManager {
+	opts := database.Options()
+	return &compactionManager{
+		database: database,
+		opts:     opts,
+		pm:       opts.PersistManager(),
+		metrics:  newCompactionManagerMetrics(scope),
+	}
+}
+
+func (m *compactionManager) Compact(t time.Time) error {
+	if m.opts.CompactionMinVolumes() <= 0 {
+		return nil
+	}
+
+	m.Lock()
+	m.compactionInProgress = true
+	m.Unlock()
+
+	defer func() {
+		m.Lock()
+		m.compactionInProgress = false
+		m.Unlock()
+	}()
+
+	namespaces, err := m.database.GetOwnedNamespaces()
+	if err != nil {
+		return err
+	}
+
+	// Compacted volumes are written through a flush persist so that writing
+	// them is subject to the same rate limit as flushes, reading the volumes
+	// being compacted is limited separately.
+	flushPersist, err := m.pm.StartFlushPersist()
+	if err != nil {
+		return err
+	}
+
+	var (
+		limiter = newCompactionRateLimiter(m.opts.CompactionRateLimitOptions(),
+			m.opts.ClockOptions().NowFn())
+		multiErr = xerrors.NewMultiError()
+	)
+	for _, ns := range namespaces {
+		result, err := ns.CompactFileSets(flushPersist, limiter)
+		if err != nil {
+			m.metrics.errors.Inc(1)
+			multiErr = multiErr.Add(err)
+		}
+		m.metrics.blocks.Inc(int64(result.numBlocks))
+		m.metrics.volumes.Inc(int64(result.numVolumes))
+		m.metrics.bytesReclaimed.Inc(result.bytesReclaimed)
+	}
+
+	if err := flushPersist.DoneFlush(); err != nil {
+		multiErr = multiErr.Add(err)
+	}
+
+	return multiErr.FinalError()
+}
+
+func (m *compactionManager) Report() {
+	m.RLock()
+	compactionInProgress := m.compactionInProgress
+	m.RUnlock()
+
+	if compactionInProgress {
+		m.metrics.status.Update(1)
+	} else {
+		m.metrics.status.Update(0)
+	}
+}
+
+// fileSetsSize returns the total size in bytes of the files of the filesets.
+func fileSetsSize(filesets fs.FileSetFilesSlice) (int64, error) {
+	var size int64
+	for _, filePath := range filesets.Filepaths() {
+		info, err := os.Stat(filePath)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return 0, err
+		}
+		size += info.Size()
+	}
+	return size, nil
+}
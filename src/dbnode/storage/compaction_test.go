@@ -0,0 +1,106 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/ratelimit"
+	"github.com/m3db/m3/src/x/ident"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
+)
+
+func TestCompactionManagerCompact(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ns := NewMockdatabaseNamespace(ctrl)
+	ns.EXPECT().ID().Return(ident.StringID("ns")).AnyTimes()
+	ns.EXPECT().CompactFileSets(gomock.Any(), gomock.Any()).Return(fileSetCompactionResult{
+		numBlocks:      2,
+		numVolumes:     8,
+		bytesReclaimed: 1024,
+	}, nil)
+
+	flushPersist := persist.NewMockFlushPreparer(ctrl)
+	flushPersist.EXPECT().DoneFlush().Return(nil)
+	pm := persist.NewMockManager(ctrl)
+	pm.EXPECT().StartFlushPersist().Return(flushPersist, nil)
+
+	db := newMockdatabase(ctrl, ns)
+	scope := tally.NewTestScope("", nil)
+	mgr := newCompactionManager(db, scope).(*compactionManager)
+	mgr.pm = pm
+
+	require.NoError(t, mgr.Compact(time.Now()))
+
+	counters := scope.Snapshot().Counters()
+	require.Equal(t, int64(2), counters["compaction.blocks+"].Value())
+	require.Equal(t, int64(8), counters["compaction.volumes+"].Value())
+	require.Equal(t, int64(1024), counters["compaction.bytes-reclaimed+"].Value())
+}
+
+func TestCompactionManagerCompactDisabled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	db := NewMockdatabase(ctrl)
+	db.EXPECT().Options().Return(DefaultTestOptions().SetCompactionMinVolumes(0)).AnyTimes()
+	mgr := newCompactionManager(db, tally.NoopScope)
+
+	require.NoError(t, mgr.Compact(time.Now()))
+}
+
+func TestCompactionRateLimiterLimit(t *testing.T) {
+	start := time.Now()
+	now := start
+	nowFn := func() time.Time {
+		return now
+	}
+
+	opts := ratelimit.NewOptions().
+		SetLimitEnabled(true).
+		SetLimitMbps(8)
+	limiter := newCompactionRateLimiter(opts, nowFn)
+	var slept []time.Duration
+	limiter.sleepFn = func(d time.Duration) {
+		slept = append(slept, d)
+		now = now.Add(d)
+	}
+
+	// 1MiB is read in a second at 8Mbps.
+	limiter.Limit(1024 * 1024)
+	require.Equal(t, []time.Duration{time.Second}, slept)
+
+	// No sleep is required once reading took longer than the limit.
+	now = now.Add(3 * time.Second)
+	limiter.Limit(1024 * 1024)
+	require.Equal(t, []time.Duration{time.Second}, slept)
+
+	// A nil limiter never sleeps.
+	var nilLimiter *compactionRateLimiter
+	nilLimiter.Limit(1024 * 1024)
+}
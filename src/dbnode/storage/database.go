@@ -30,6 +30,7 @@ import (
 
 	"github.com/m3db/m3/src/dbnode/clock"
 	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/dbnode/persist/fs/backup"
 	"github.com/m3db/m3/src/dbnode/persist/fs/commitlog"
 	"github.com/m3db/m3/src/dbnode/sharding"
 	"github.com/m3db/m3/src/dbnode/storage/block"
@@ -70,6 +71,9 @@ var (
 	// errDatabaseIsClosed raised when trying to perform an action that requires an open database.
 	errDatabaseIsClosed = errors.New("database is closed")
 
+	// errDatabaseNotBootstrappedToBackup raised when trying to backup a database that is not bootstrapped.
+	errDatabaseNotBootstrappedToBackup = errors.New("database is not yet bootstrapped to backup")
+
 	// errWriterDoesNotImplementWriteBatch is raised when the provided ts.BatchWriter does not implement
 	// ts.WriteBatch.
 	errWriterDoesNotImplementWriteBatch = errors.New("provided writer does not implement ts.WriteBatch")
@@ -795,6 +799,31 @@ func (d *db) AggregateQuery(
 	return n.AggregateQuery(ctx, query, aggResultOpts)
 }
 
+func (d *db) IndexCardinality(
+	ctx context.Context,
+	namespace ident.ID,
+	opts index.CardinalityOptions,
+) (index.CardinalityResults, error) {
+	ctx, sp, sampled := ctx.StartSampledTraceSpan(tracepoint.DBIndexCardinality)
+	if sampled {
+		sp.LogFields(
+			opentracinglog.String("namespace", namespace.String()),
+			opentracinglog.Int("limit", opts.Limit),
+			xopentracing.Time("start", opts.StartInclusive),
+			xopentracing.Time("end", opts.EndExclusive),
+		)
+	}
+	defer sp.Finish()
+
+	n, err := d.namespaceFor(namespace)
+	if err != nil {
+		d.metrics.unknownNamespaceQueryIDs.Inc(1)
+		return index.CardinalityResults{}, err
+	}
+
+	return n.IndexCardinality(ctx, opts)
+}
+
 func (d *db) ReadEncoded(
 	ctx context.Context,
 	namespace ident.ID,
@@ -955,6 +984,76 @@ func (d *db) Truncate(namespace ident.ID) (int64, error) {
 	return n.Truncate()
 }
 
+func (d *db) DeleteTagged(
+	ctx context.Context,
+	namespace ident.ID,
+	query index.Query,
+	opts index.QueryOptions,
+) (int64, error) {
+	n, err := d.namespaceFor(namespace)
+	if err != nil {
+		return 0, err
+	}
+	return n.DeleteTagged(ctx, query, opts)
+}
+
+func (d *db) Backup(
+	namespace ident.ID,
+	backupID string,
+	store backup.BlobStore,
+) (backup.Manifest, error) {
+	n, err := d.namespaceFor(namespace)
+	if err != nil {
+		return backup.Manifest{}, err
+	}
+	if !d.IsBootstrapped() {
+		return backup.Manifest{}, errDatabaseNotBootstrappedToBackup
+	}
+
+	startTime := d.nowFn()
+	staged, err := d.stageBackup(n, backupID, startTime)
+	if err != nil {
+		return backup.Manifest{}, err
+	}
+	return staged.Archive(store, startTime)
+}
+
+// stageBackup snapshots the database and stages the filesets of the
+// namespace covered by the snapshot, file operations are paused until the
+// files are staged so that they are not cleaned up, compacted or moved.
+func (d *db) stageBackup(
+	n databaseNamespace,
+	backupID string,
+	startTime time.Time,
+) (*backup.Staged, error) {
+	d.mediator.DisableFileOps()
+	defer d.mediator.EnableFileOps()
+
+	// Snapshotting rotates the commit log so that the backup is consistent
+	// across all shards with the writes acknowledged up until now.
+	snapshotID, err := d.mediator.Snapshot(startTime)
+	if err != nil {
+		return nil, fmt.Errorf("unable to snapshot before backup: %v", err)
+	}
+
+	ownedShards := n.GetOwnedShards()
+	shards := make([]uint32, 0, len(ownedShards))
+	for _, shard := range ownedShards {
+		shards = append(shards, shard.ID())
+	}
+
+	fsOpts := d.opts.CommitLogOptions().FilesystemOptions()
+	return backup.Stage(backupID, backup.Source{
+		FilePathPrefix:         fsOpts.FilePathPrefix(),
+		ColdTierFilePathPrefix: n.Options().ColdTierOptions().FilePathPrefix(),
+		Namespace:              n.ID(),
+		Shards:                 shards,
+		SnapshotID:             snapshotID.String(),
+		FsOptions:              fsOpts,
+		StagingDir:             backup.StagingDirPath(fsOpts.FilePathPrefix(), backupID),
+	})
+}
+
 func (d *db) IsOverloaded() bool {
 	queueSize := float64(d.commitLog.QueueLength())
 	queueCapacity := float64(d.opts.CommitLogOptions().BacklogQueueSize())
@@ -24,6 +24,9 @@ import (
 	stdlibctx "context"
 	"errors"
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"sort"
 	"sync"
 	"testing"
@@ -32,6 +35,9 @@ import (
 	"github.com/m3db/m3/src/cluster/shard"
 	"github.com/m3db/m3/src/dbnode/client"
 	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/dbnode/persist/fs/backup"
 	"github.com/m3db/m3/src/dbnode/persist/fs/commitlog"
 	"github.com/m3db/m3/src/dbnode/retention"
 	"github.com/m3db/m3/src/dbnode/sharding"
@@ -58,6 +64,7 @@ import (
 	"github.com/m3db/m3/src/dbnode/testdata/prototest"
 	opentracing "github.com/opentracing/opentracing-go"
 	"github.com/opentracing/opentracing-go/mocktracer"
+	"github.com/pborman/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/uber-go/tally"
@@ -1268,3 +1275,75 @@ func TestDatabaseIsOverloaded(t *testing.T) {
 	mockCL.EXPECT().QueueLength().Return(int64(90))
 	require.Equal(t, true, d.IsOverloaded())
 }
+
+func TestDatabaseBackup(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	d, mapCh, _ := defaultTestDatabase(t, ctrl, Bootstrapped)
+	defer func() {
+		close(mapCh)
+	}()
+
+	dir, err := ioutil.TempDir("", "testdb")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	clOpts := d.opts.CommitLogOptions()
+	d.opts = d.opts.SetCommitLogOptions(clOpts.SetFilesystemOptions(
+		clOpts.FilesystemOptions().SetFilePathPrefix(filepath.Join(dir, "data"))))
+
+	mediator := NewMockdatabaseMediator(ctrl)
+	mediator.EXPECT().IsBootstrapped().Return(true).AnyTimes()
+	d.mediator = mediator
+
+	ns := dbAddNewMockNamespace(ctrl, d, "testns")
+	mockShard := NewMockdatabaseShard(ctrl)
+	mockShard.EXPECT().ID().Return(uint32(0)).AnyTimes()
+	ns.EXPECT().GetOwnedShards().Return([]databaseShard{mockShard}).AnyTimes()
+	ns.EXPECT().Options().Return(namespace.NewOptions()).AnyTimes()
+
+	// The snapshot taken for the backup is the one that is staged.
+	snapshotID := uuid.NewUUID()
+	writer := fs.NewSnapshotMetadataWriter(d.opts.CommitLogOptions().FilesystemOptions())
+	require.NoError(t, writer.Write(fs.SnapshotMetadataWriteArgs{
+		ID: fs.SnapshotMetadataIdentifier{
+			Index: 0,
+			UUID:  snapshotID,
+		},
+		CommitlogIdentifier: persist.CommitLogFile{
+			FilePath: "commitlog",
+			Index:    1,
+		},
+	}))
+
+	gomock.InOrder(
+		mediator.EXPECT().DisableFileOps(),
+		mediator.EXPECT().Snapshot(gomock.Any()).Return(snapshotID, nil),
+		mediator.EXPECT().EnableFileOps(),
+	)
+
+	store := backup.NewFileSystemBlobStore(filepath.Join(dir, "store"))
+	manifest, err := d.Backup(ident.StringID("testns"), "backup-1", store)
+	require.NoError(t, err)
+	require.Equal(t, "testns", manifest.Namespace)
+	require.Equal(t, []uint32{0}, manifest.Shards)
+	require.Equal(t, snapshotID.String(), manifest.SnapshotID)
+
+	read, err := backup.ReadManifest(store, "backup-1")
+	require.NoError(t, err)
+	require.Equal(t, manifest.BackupID, read.BackupID)
+
+	// The staged files are removed once the backup is archived.
+	_, err = os.Stat(backup.StagingDirPath(filepath.Join(dir, "data"), "backup-1"))
+	require.True(t, os.IsNotExist(err))
+
+	// File operations are re-enabled even if the snapshot fails.
+	gomock.InOrder(
+		mediator.EXPECT().DisableFileOps(),
+		mediator.EXPECT().Snapshot(gomock.Any()).Return(nil, errors.New("snapshot failed")),
+		mediator.EXPECT().EnableFileOps(),
+	)
+	_, err = d.Backup(ident.StringID("testns"), "backup-2", store)
+	require.Error(t, err)
+}
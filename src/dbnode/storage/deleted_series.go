@@ -0,0 +1,194 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/dbnode/storage/block"
+	"github.com/m3db/m3/src/dbnode/storage/series"
+	"github.com/m3db/m3/src/dbnode/ts"
+	"github.com/m3db/m3/src/dbnode/x/xio"
+	"github.com/m3db/m3/src/x/context"
+	xtime "github.com/m3db/m3/src/x/time"
+)
+
+// deletedSeries wraps a series that has a tombstone so that data checked out
+// for writing by bootstrappers (commit log, filesystem and peers) that was
+// deleted is dropped instead of being loaded back into the series.
+type deletedSeries struct {
+	series.DatabaseSeries
+
+	deletedAt time.Time
+	opts      series.Options
+	nsCtx     namespace.Context
+}
+
+func newDeletedSeries(
+	s series.DatabaseSeries,
+	deletedAt time.Time,
+	opts series.Options,
+	nsCtx namespace.Context,
+) series.DatabaseSeries {
+	return &deletedSeries{
+		DatabaseSeries: s,
+		deletedAt:      deletedAt,
+		opts:           opts,
+		nsCtx:          nsCtx,
+	}
+}
+
+func (s *deletedSeries) Write(
+	ctx context.Context,
+	timestamp time.Time,
+	value float64,
+	unit xtime.Unit,
+	annotation []byte,
+	wOpts series.WriteOptions,
+) (bool, error) {
+	if timestamp.Before(s.deletedAt) {
+		return false, nil
+	}
+	return s.DatabaseSeries.Write(ctx, timestamp, value, unit, annotation, wOpts)
+}
+
+func (s *deletedSeries) LoadBlock(
+	b block.DatabaseBlock,
+	writeType series.WriteType,
+) error {
+	if !b.StartTime().Add(b.BlockSize()).After(s.deletedAt) {
+		b.Close()
+		return nil
+	}
+	if b.StartTime().Before(s.deletedAt) {
+		remaining, err := filterDeletedBlock(b, s.deletedAt, s.opts, s.nsCtx)
+		if err != nil || !remaining {
+			b.Close()
+			return err
+		}
+	}
+	return s.DatabaseSeries.LoadBlock(b, writeType)
+}
+
+// filterDeletedBlock resets the block to only hold the datapoints at or after
+// the deletion time and returns whether any datapoints remain.
+func filterDeletedBlock(
+	b block.DatabaseBlock,
+	deletedAt time.Time,
+	opts series.Options,
+	nsCtx namespace.Context,
+) (bool, error) {
+	ctx := opts.ContextPool().Get()
+	br, err := b.Stream(ctx)
+	if err != nil || br.IsEmpty() {
+		ctx.Close()
+		return false, err
+	}
+
+	segment, ok, err := encodeDatapointsFrom([]xio.SegmentReader{br.SegmentReader},
+		b.StartTime(), b.BlockSize(), deletedAt, opts, nsCtx)
+	// NB: the stream must be released before the block is reset.
+	ctx.BlockingClose()
+	if err != nil || !ok {
+		return false, err
+	}
+
+	b.Reset(b.StartTime(), b.BlockSize(), segment, nsCtx)
+	return true, nil
+}
+
+// filterDeletedBlockReaders returns the readers of a block with the datapoints
+// before the deletion time filtered out if the deletion time falls within the
+// block, the readers are returned unchanged otherwise.
+func filterDeletedBlockReaders(
+	ctx context.Context,
+	brs []xio.BlockReader,
+	deletedAt time.Time,
+	opts series.Options,
+	nsCtx namespace.Context,
+) ([]xio.BlockReader, error) {
+	if len(brs) == 0 {
+		return brs, nil
+	}
+
+	start, blockSize := brs[0].Start, brs[0].BlockSize
+	if !deletedAt.After(start) || !deletedAt.Before(start.Add(blockSize)) {
+		return brs, nil
+	}
+
+	readers := make([]xio.SegmentReader, 0, len(brs))
+	for _, br := range brs {
+		readers = append(readers, br.SegmentReader)
+	}
+	segment, ok, err := encodeDatapointsFrom(readers, start, blockSize,
+		deletedAt, opts, nsCtx)
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	reader := xio.NewSegmentReader(segment)
+	ctx.RegisterFinalizer(reader)
+	return []xio.BlockReader{{
+		SegmentReader: reader,
+		Start:         start,
+		BlockSize:     blockSize,
+	}}, nil
+}
+
+// encodeDatapointsFrom merges the readers of a block and encodes the datapoints
+// at or after the given time into a new segment, returning false if there are
+// no such datapoints.
+func encodeDatapointsFrom(
+	readers []xio.SegmentReader,
+	blockStart time.Time,
+	blockSize time.Duration,
+	from time.Time,
+	opts series.Options,
+	nsCtx namespace.Context,
+) (ts.Segment, bool, error) {
+	iter := opts.MultiReaderIteratorPool().Get()
+	iter.Reset(readers, blockStart, blockSize, nsCtx.Schema)
+	defer iter.Close()
+
+	encoder := opts.EncoderPool().Get()
+	encoder.Reset(blockStart, opts.DatabaseBlockOptions().DatabaseBlockAllocSize(),
+		nsCtx.Schema)
+	for iter.Next() {
+		dp, unit, annotation := iter.Current()
+		if dp.Timestamp.Before(from) {
+			continue
+		}
+		if err := encoder.Encode(dp, unit, annotation); err != nil {
+			encoder.Close()
+			return ts.Segment{}, false, err
+		}
+	}
+	if err := iter.Err(); err != nil {
+		encoder.Close()
+		return ts.Segment{}, false, err
+	}
+	if encoder.NumEncoded() == 0 {
+		encoder.Close()
+		return ts.Segment{}, false, nil
+	}
+	return encoder.Discard(), true, nil
+}
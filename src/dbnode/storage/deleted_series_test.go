@@ -0,0 +1,93 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/dbnode/ts"
+	"github.com/m3db/m3/src/dbnode/x/xio"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilterDeletedBlockReaders(t *testing.T) {
+	var (
+		opts      = DefaultTestOptions()
+		ctx       = opts.ContextPool().Get()
+		blockSize = time.Hour
+		start     = time.Now().Truncate(blockSize)
+		nsCtx     = namespace.Context{}
+	)
+	defer ctx.Close()
+
+	newBlockReaders := func() []xio.BlockReader {
+		encoder := opts.EncoderPool().Get()
+		encoder.Reset(start, 0, nil)
+		for i := 0; i < 4; i++ {
+			dp := ts.Datapoint{
+				Timestamp: start.Add(time.Duration(i) * time.Minute),
+				Value:     float64(i),
+			}
+			require.NoError(t, encoder.Encode(dp, xtime.Second, nil))
+		}
+		return []xio.BlockReader{{
+			SegmentReader: xio.NewSegmentReader(encoder.Discard()),
+			Start:         start,
+			BlockSize:     blockSize,
+		}}
+	}
+
+	// Deletion outside of the block leaves the readers unchanged.
+	brs := newBlockReaders()
+	filtered, err := filterDeletedBlockReaders(ctx, brs, start,
+		opts.SeriesOptions(), nsCtx)
+	require.NoError(t, err)
+	require.Equal(t, brs, filtered)
+
+	// Deletion within the block drops the datapoints before it.
+	filtered, err = filterDeletedBlockReaders(ctx, newBlockReaders(),
+		start.Add(2*time.Minute), opts.SeriesOptions(), nsCtx)
+	require.NoError(t, err)
+	require.Len(t, filtered, 1)
+	require.True(t, start.Equal(filtered[0].Start))
+
+	iter := opts.ReaderIteratorPool().Get()
+	defer iter.Close()
+	iter.Reset(filtered[0].SegmentReader, nil)
+
+	var values []float64
+	for iter.Next() {
+		dp, _, _ := iter.Current()
+		values = append(values, dp.Value)
+	}
+	require.NoError(t, iter.Err())
+	require.Equal(t, []float64{2, 3}, values)
+
+	// Deletion after all datapoints of the block drops the block.
+	filtered, err = filterDeletedBlockReaders(ctx, newBlockReaders(),
+		start.Add(10*time.Minute), opts.SeriesOptions(), nsCtx)
+	require.NoError(t, err)
+	require.Len(t, filtered, 0)
+}
@@ -63,3 +63,29 @@ func IsUnknownNamespaceError(err error) bool {
 	_, ok := nsErr.(unknownNamespace)
 	return ok
 }
+
+// NewQuotaExceededError returns a new retryable error indicating that a
+// namespace or tenant quota has been exceeded.
+func NewQuotaExceededError(scope, name, limit string) error {
+	return xerrors.NewRetryableError(quotaExceeded{scope: scope, name: name, limit: limit})
+}
+
+type quotaExceeded struct {
+	scope string
+	name  string
+	limit string
+}
+
+func (e quotaExceeded) Error() string {
+	return fmt.Sprintf("quota exceeded: %s %s exceeded %s", e.scope, e.name, e.limit)
+}
+
+// IsQuotaExceededError returns true if this is a quota exceeded error.
+func IsQuotaExceededError(err error) bool {
+	quotaErr := xerrors.GetInnerRetryableError(err)
+	if quotaErr == nil {
+		return false
+	}
+	_, ok := quotaErr.(quotaExceeded)
+	return ok
+}
@@ -24,6 +24,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+
+	xerrors "github.com/m3db/m3/src/x/errors"
 )
 
 func TestUnknownNamespaceError(t *testing.T) {
@@ -31,3 +33,11 @@ func TestUnknownNamespaceError(t *testing.T) {
 	require.Equal(t, "unknown namespace: ns", err.Error())
 	require.True(t, IsUnknownNamespaceError(err))
 }
+
+func TestQuotaExceededError(t *testing.T) {
+	err := NewQuotaExceededError("tenant", "foo", "max series")
+	require.Equal(t, "quota exceeded: tenant foo exceeded max series", err.Error())
+	require.True(t, IsQuotaExceededError(err))
+	require.True(t, xerrors.IsRetryableError(err))
+	require.False(t, IsQuotaExceededError(NewUnknownNamespaceError("ns")))
+}
@@ -26,6 +26,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/m3db/m3/src/dbnode/namespace"
 	"github.com/m3db/m3/src/dbnode/persist"
 	"github.com/m3db/m3/src/dbnode/persist/fs/commitlog"
 	"github.com/m3db/m3/src/dbnode/retention"
@@ -165,7 +166,7 @@ func (m *flushManager) Flush(startTime time.Time) error {
 		// value by however many bytes had been tracked when the cold flush began.
 		memTracker.DecPendingLoadedBytes()
 
-		if err = m.dataSnapshot(namespaces, startTime, rotatedCommitlogID); err != nil {
+		if err = m.dataSnapshot(namespaces, startTime, uuid.NewUUID(), rotatedCommitlogID); err != nil {
 			multiErr = multiErr.Add(err)
 		}
 	} else {
@@ -179,6 +180,44 @@ func (m *flushManager) Flush(startTime time.Time) error {
 	return multiErr.FinalError()
 }
 
+func (m *flushManager) Snapshot(startTime time.Time) (uuid.UUID, error) {
+	m.Lock()
+	if m.state != flushManagerIdle {
+		m.Unlock()
+		return nil, errFlushOperationsInProgress
+	}
+	m.state = flushManagerNotIdle
+	m.Unlock()
+
+	defer m.setState(flushManagerIdle)
+
+	namespaces, err := m.database.GetOwnedNamespaces()
+	if err != nil {
+		return nil, err
+	}
+
+	rotatedCommitlogID, err := m.commitlog.RotateLogs()
+	if err != nil {
+		return nil, fmt.Errorf("error rotating commitlog before snapshot: %v", err)
+	}
+
+	// Snapshot metadata covers the commit logs of all namespaces, so as in
+	// Flush all namespaces are cold flushed and then snapshotted, otherwise
+	// the commit log cleanup could remove writes that were not persisted.
+	memTracker := m.opts.MemoryTracker()
+	memTracker.MarkLoadedAsPending()
+	if err := m.dataColdFlush(namespaces); err != nil {
+		return nil, err
+	}
+	memTracker.DecPendingLoadedBytes()
+
+	snapshotID := uuid.NewUUID()
+	if err := m.dataSnapshot(namespaces, startTime, snapshotID, rotatedCommitlogID); err != nil {
+		return nil, err
+	}
+	return snapshotID, nil
+}
+
 func (m *flushManager) dataWarmFlush(
 	namespaces []databaseNamespace,
 	startTime time.Time,
@@ -189,8 +228,18 @@ func (m *flushManager) dataWarmFlush(
 	}
 
 	m.setState(flushManagerFlushInProgress)
-	multiErr := xerrors.NewMultiError()
+	var (
+		multiErr   = xerrors.NewMultiError()
+		aggregated []databaseNamespace
+	)
 	for _, ns := range namespaces {
+		// Aggregated namespaces are rolled up from the data flushed by their
+		// source namespaces so they are flushed once all others have been.
+		if ns.Options().AggregationOptions().Enabled() {
+			aggregated = append(aggregated, ns)
+			continue
+		}
+
 		// Flush first because we will only snapshot if there are no outstanding flushes.
 		flushTimes, err := m.namespaceFlushTimes(ns, startTime)
 		if err != nil {
@@ -203,6 +252,12 @@ func (m *flushManager) dataWarmFlush(
 		}
 	}
 
+	for _, ns := range aggregated {
+		if err := m.rollupNamespace(ns, namespaces, startTime, flushPersist); err != nil {
+			multiErr = multiErr.Add(err)
+		}
+	}
+
 	err = flushPersist.DoneFlush()
 	if err != nil {
 		multiErr = multiErr.Add(err)
@@ -238,10 +293,9 @@ func (m *flushManager) dataColdFlush(
 func (m *flushManager) dataSnapshot(
 	namespaces []databaseNamespace,
 	startTime time.Time,
+	snapshotID uuid.UUID,
 	rotatedCommitlogID persist.CommitLogFile,
 ) error {
-	snapshotID := uuid.NewUUID()
-
 	snapshotPersist, err := m.pm.StartSnapshotPersist(snapshotID)
 	if err != nil {
 		return err
@@ -357,7 +411,9 @@ func (m *flushManager) flushRange(rOpts retention.Options, t time.Time) (time.Ti
 
 func (m *flushManager) namespaceFlushTimes(ns databaseNamespace, curr time.Time) ([]time.Time, error) {
 	var (
-		rOpts            = ns.Options().RetentionOptions()
+		// NB: Blocks can only be flushed once warm writes for them can no
+		// longer arrive for any of the write classes of the namespace.
+		rOpts            = namespace.WarmWriteRetentionOptions(ns.Options())
 		blockSize        = rOpts.BlockSize()
 		earliest, latest = m.flushRange(rOpts, curr)
 	)
@@ -376,7 +432,7 @@ func (m *flushManager) namespaceFlushTimes(ns databaseNamespace, curr time.Time)
 
 func (m *flushManager) namespaceSnapshotTimes(ns databaseNamespace, curr time.Time) ([]time.Time, error) {
 	var (
-		rOpts     = ns.Options().RetentionOptions()
+		rOpts     = namespace.WarmWriteRetentionOptions(ns.Options())
 		blockSize = rOpts.BlockSize()
 		// Earliest possible snapshottable block is the earliest possible flushable
 		// blockStart which is the first block in the retention period.
@@ -422,6 +478,43 @@ func (m *flushManager) flushNamespaceWithTimes(
 	return multiErr.FinalError()
 }
 
+// rollupNamespace rolls up the flushed data of the source namespace of an
+// aggregated namespace for each of its flushable blocks.
+func (m *flushManager) rollupNamespace(
+	ns databaseNamespace,
+	namespaces []databaseNamespace,
+	startTime time.Time,
+	flushPreparer persist.FlushPreparer,
+) error {
+	sourceID := ns.Options().AggregationOptions().SourceNamespace()
+	var source databaseNamespace
+	for _, candidate := range namespaces {
+		if candidate.ID().Equal(sourceID) {
+			source = candidate
+			break
+		}
+	}
+	if source == nil {
+		return fmt.Errorf("namespace %s failed to rollup data: source namespace %s not owned",
+			ns.ID().String(), sourceID.String())
+	}
+
+	flushTimes, err := m.namespaceFlushTimes(ns, startTime)
+	if err != nil {
+		return err
+	}
+
+	multiErr := xerrors.NewMultiError()
+	for _, t := range flushTimes {
+		if err := ns.RollupFlush(t, source, flushPreparer); err != nil {
+			detailedErr := fmt.Errorf("namespace %s failed to rollup data: %v",
+				ns.ID().String(), err)
+			multiErr = multiErr.Add(detailedErr)
+		}
+	}
+	return multiErr.FinalError()
+}
+
 func (m *flushManager) LastSuccessfulSnapshotStartTime() (time.Time, bool) {
 	return m.lastSuccessfulSnapshotStartTime, !m.lastSuccessfulSnapshotStartTime.IsZero()
 }
@@ -409,6 +409,57 @@ func TestFlushManagerNamespaceIndexingEnabled(t *testing.T) {
 	require.NoError(t, fm.Flush(now))
 }
 
+func TestFlushManagerRollupAfterSourceFlush(t *testing.T) {
+	ctrl := gomock.NewController(xtest.Reporter{T: t})
+	defer ctrl.Finish()
+
+	var (
+		sourceID = ident.StringID("raw")
+		aggOpts  = namespace.NewAggregationOptions().
+				SetEnabled(true).
+				SetResolution(time.Minute).
+				SetSourceNamespace(sourceID)
+		sourceFlushed bool
+	)
+
+	aggNs := NewMockdatabaseNamespace(ctrl)
+	aggNs.EXPECT().Options().Return(namespace.NewOptions().
+		SetAggregationOptions(aggOpts)).AnyTimes()
+	aggNs.EXPECT().ID().Return(ident.StringID("agg")).AnyTimes()
+	aggNs.EXPECT().NeedsFlush(gomock.Any(), gomock.Any()).Return(true, nil).AnyTimes()
+
+	sourceNs := NewMockdatabaseNamespace(ctrl)
+	sourceNs.EXPECT().Options().Return(namespace.NewOptions()).AnyTimes()
+	sourceNs.EXPECT().ID().Return(sourceID).AnyTimes()
+	sourceNs.EXPECT().NeedsFlush(gomock.Any(), gomock.Any()).Return(true, nil).AnyTimes()
+	sourceNs.EXPECT().WarmFlush(gomock.Any(), gomock.Any()).Do(
+		func(_ time.Time, _ persist.FlushPreparer) {
+			sourceFlushed = true
+		}).Return(nil).MinTimes(1)
+
+	aggNs.EXPECT().RollupFlush(gomock.Any(), sourceNs, gomock.Any()).Do(
+		func(_ time.Time, _ databaseNamespace, _ persist.FlushPreparer) {
+			require.True(t, sourceFlushed)
+		}).Return(nil).MinTimes(1)
+
+	mockFlushPersist := persist.NewMockFlushPreparer(ctrl)
+	mockFlushPersist.EXPECT().DoneFlush().Return(nil)
+	mockPersistManager := persist.NewMockManager(ctrl)
+	mockPersistManager.EXPECT().StartFlushPersist().Return(mockFlushPersist, nil)
+
+	testOpts := DefaultTestOptions().SetPersistManager(mockPersistManager)
+	db := newMockdatabase(ctrl)
+	db.EXPECT().Options().Return(testOpts).AnyTimes()
+
+	cl := commitlog.NewMockCommitLog(ctrl)
+	fm := newFlushManager(db, cl, tally.NoopScope).(*flushManager)
+	fm.pm = mockPersistManager
+
+	now := time.Unix(86400*2, 0)
+	namespaces := []databaseNamespace{aggNs, sourceNs}
+	require.NoError(t, fm.dataWarmFlush(namespaces, now))
+}
+
 func TestFlushManagerFlushTimeStart(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -571,6 +622,40 @@ func TestFlushManagerFlushSnapshot(t *testing.T) {
 	require.Equal(t, now, lastSuccessfulSnapshot)
 }
 
+func TestFlushManagerSnapshot(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fm, ns1, ns2, _ := newMultipleFlushManagerNeedsFlush(t, ctrl)
+	now := time.Now()
+
+	// All namespaces are cold flushed and snapshotted, without warm flushes.
+	for _, ns := range []*MockdatabaseNamespace{ns1, ns2} {
+		rOpts := ns.Options().RetentionOptions()
+		blockSize := rOpts.BlockSize()
+		bufferFuture := rOpts.BufferFuture()
+
+		ns.EXPECT().ColdFlush(gomock.Any())
+
+		start := retention.FlushTimeStart(rOpts, now)
+		snapshotEnd := now.Add(bufferFuture).Truncate(blockSize)
+		num := numIntervals(start, snapshotEnd, blockSize)
+		for i := 0; i < num; i++ {
+			st := start.Add(time.Duration(i) * blockSize)
+			ns.EXPECT().NeedsFlush(st, st).Return(true, nil)
+			ns.EXPECT().Snapshot(st, now, gomock.Any())
+		}
+	}
+
+	snapshotID, err := fm.Snapshot(now)
+	require.NoError(t, err)
+	require.NotNil(t, snapshotID)
+
+	lastSuccessfulSnapshot, ok := fm.LastSuccessfulSnapshotStartTime()
+	require.True(t, ok)
+	require.Equal(t, now, lastSuccessfulSnapshot)
+}
+
 type timesInOrder []time.Time
 
 func (a timesInOrder) Len() int           { return len(a) }
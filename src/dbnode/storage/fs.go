@@ -85,6 +85,7 @@ const (
 type fileSystemManager struct {
 	databaseFlushManager
 	databaseCleanupManager
+	databaseCompactionManager
 	sync.RWMutex
 
 	log      *zap.Logger
@@ -103,15 +104,17 @@ func newFileSystemManager(
 	scope := instrumentOpts.MetricsScope().SubScope("fs")
 	fm := newFlushManager(database, commitLog, scope)
 	cm := newCleanupManager(database, commitLog, scope)
+	cpm := newCompactionManager(database, scope)
 
 	return &fileSystemManager{
-		databaseFlushManager:   fm,
-		databaseCleanupManager: cm,
-		log:                    instrumentOpts.Logger(),
-		database:               database,
-		opts:                   opts,
-		status:                 fileOpNotStarted,
-		enabled:                true,
+		databaseFlushManager:      fm,
+		databaseCleanupManager:    cm,
+		databaseCompactionManager: cpm,
+		log:                       instrumentOpts.Logger(),
+		database:                  database,
+		opts:                      opts,
+		status:                    fileOpNotStarted,
+		enabled:                   true,
 	}
 }
 
@@ -152,6 +155,7 @@ func (m *fileSystemManager) Run(
 	m.Unlock()
 
 	// NB(xichen): perform data cleanup and flushing sequentially to minimize the impact of disk seeks.
+	// Compaction runs last so that it never competes with flushes for the persist manager.
 	flushFn := func() {
 		if err := m.Cleanup(t); err != nil {
 			m.log.Error("error when cleaning up data", zap.Time("time", t), zap.Error(err))
@@ -159,6 +163,9 @@ func (m *fileSystemManager) Run(
 		if err := m.Flush(t); err != nil {
 			m.log.Error("error when flushing data", zap.Time("time", t), zap.Error(err))
 		}
+		if err := m.Compact(t); err != nil {
+			m.log.Error("error when compacting data", zap.Time("time", t), zap.Error(err))
+		}
 		m.Lock()
 		m.status = fileOpNotStarted
 		m.Unlock()
@@ -175,6 +182,7 @@ func (m *fileSystemManager) Run(
 func (m *fileSystemManager) Report() {
 	m.databaseCleanupManager.Report()
 	m.databaseFlushManager.Report()
+	m.databaseCompactionManager.Report()
 }
 
 func (m *fileSystemManager) shouldRunWithLock() bool {
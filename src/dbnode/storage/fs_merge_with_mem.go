@@ -21,6 +21,8 @@
 package storage
 
 import (
+	"time"
+
 	"github.com/m3db/m3/src/dbnode/namespace"
 	"github.com/m3db/m3/src/dbnode/persist/fs"
 	"github.com/m3db/m3/src/dbnode/storage/series"
@@ -148,3 +150,7 @@ func (m *fsMergeWithMem) ForEachRemaining(
 
 	return nil
 }
+
+func (m *fsMergeWithMem) DeletedAt(seriesID ident.ID) (time.Time, bool) {
+	return m.shard.SeriesDeletedAt(seriesID)
+}
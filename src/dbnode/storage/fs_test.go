@@ -78,15 +78,18 @@ func TestFileSystemManagerRun(t *testing.T) {
 
 	fm := NewMockdatabaseFlushManager(ctrl)
 	cm := NewMockdatabaseCleanupManager(ctrl)
+	cpm := NewMockdatabaseCompactionManager(ctrl)
 	fsm := newFileSystemManager(database, nil, DefaultTestOptions())
 	mgr := fsm.(*fileSystemManager)
 	mgr.databaseFlushManager = fm
 	mgr.databaseCleanupManager = cm
+	mgr.databaseCompactionManager = cpm
 
 	ts := time.Now()
 	gomock.InOrder(
 		cm.EXPECT().Cleanup(ts).Return(errors.New("foo")),
 		fm.EXPECT().Flush(ts).Return(errors.New("bar")),
+		cpm.EXPECT().Compact(ts).Return(errors.New("baz")),
 	)
 
 	mgr.Run(ts, syncRun, noForce)
@@ -26,6 +26,7 @@ import (
 	"sort"
 	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/m3db/bitset"
@@ -48,6 +49,7 @@ import (
 	m3ninxindex "github.com/m3db/m3/src/m3ninx/index"
 	"github.com/m3db/m3/src/m3ninx/index/segment"
 	"github.com/m3db/m3/src/m3ninx/index/segment/builder"
+	"github.com/m3db/m3/src/m3ninx/index/segment/mem"
 	xclose "github.com/m3db/m3/src/x/close"
 	"github.com/m3db/m3/src/x/context"
 	xerrors "github.com/m3db/m3/src/x/errors"
@@ -81,6 +83,8 @@ const (
 
 var (
 	allQuery = idx.NewAllQuery()
+
+	seriesTTLTagName = []byte(retention.SeriesTTLTagName)
 )
 
 // nolint: maligned
@@ -96,8 +100,11 @@ type nsIndex struct {
 	bufferPast            time.Duration
 	bufferFuture          time.Duration
 	coldWritesEnabled     bool
+	compactionAge         time.Duration
+	compactedBlockSize    time.Duration
 
 	indexFilesetsBeforeFn indexFilesetsBeforeFn
+	indexFilesetsAtFn     indexFilesetsAtFn
 	deleteFilesFn         deleteFilesFn
 
 	newBlockFn          newBlockFn
@@ -123,6 +130,69 @@ type nsIndex struct {
 	// forwardIndexDice determines if an incoming index write should be dual
 	// written to the next block.
 	forwardIndexDice forwardIndexDice
+
+	// seriesDeleted is set when the namespace holds series tombstones so
+	// that deleted series are removed from index segments as they are
+	// compacted or flushed, and not returned by queries until then.
+	seriesDeleted *seriesDeletedFilter
+}
+
+// seriesDeletedFilter is guarded by its own lock rather than the index state
+// lock since it is called by blocks when compacting segments.
+type seriesDeletedFilter struct {
+	sync.RWMutex
+	fn func(ident.ID) bool
+}
+
+func (f *seriesDeletedFilter) set(fn func(ident.ID) bool) {
+	f.Lock()
+	f.fn = fn
+	f.Unlock()
+}
+
+func (f *seriesDeletedFilter) get() func(ident.ID) bool {
+	f.RLock()
+	fn := f.fn
+	f.RUnlock()
+	return fn
+}
+
+// Contains returns whether the document is not for a deleted series and
+// should be retained.
+func (f *seriesDeletedFilter) Contains(d doc.Document) bool {
+	fn := f.get()
+	if fn == nil {
+		return true
+	}
+	return !fn(ident.BytesID(d.ID))
+}
+
+// blockReads counts the inflight reads of the blocks of the index, along with
+// the tasks of those reads which can outlive a query that timed out. A count
+// is only acquired with the index state lock held and is replaced whenever
+// blocks are replaced by a compacted block, so the replaced blocks are no
+// longer read once the count they were replaced with drops to zero.
+type blockReads struct {
+	inflight int64
+}
+
+func (r *blockReads) acquire() {
+	atomic.AddInt64(&r.inflight, 1)
+}
+
+func (r *blockReads) release() {
+	atomic.AddInt64(&r.inflight, -1)
+}
+
+func (r *blockReads) done() bool {
+	return atomic.LoadInt64(&r.inflight) == 0
+}
+
+// pendingCloseBlock is a block replaced by a compacted block along with the
+// reads which could still be reading it.
+type pendingCloseBlock struct {
+	block index.Block
+	reads *blockReads
 }
 
 type nsIndexState struct {
@@ -144,6 +214,18 @@ type nsIndexState struct {
 	blocksByTime map[xtime.UnixNano]index.Block
 	latestBlock  index.Block
 
+	// blocksPendingClose are the blocks replaced by a block compacted from
+	// them, they are closed on the first tick after the queries which already
+	// hold a reference to them have completed.
+	blocksPendingClose []pendingCloseBlock
+
+	// blockReads counts the inflight reads of the current blocks.
+	blockReads *blockReads
+
+	// lastPostingsListCachePersist is when the cached postings lists of the
+	// sealed blocks were last persisted alongside their index filesets.
+	lastPostingsListCachePersist time.Time
+
 	// NB: `blockStartsDescOrder` contains the keys from the map `blocksByTime` in reverse
 	// chronological order. This is used at query time to enforce determinism about results
 	// returned.
@@ -152,6 +234,10 @@ type nsIndexState struct {
 	// shardsFilterID is set every time the shards change to correctly
 	// only return IDs that this node owns.
 	shardsFilterID func(ident.ID) bool
+
+	// seriesValueFn is set when the namespace keeps value summaries so that
+	// queries with a value filter can skip series that cannot match.
+	seriesValueFn func(ident.ID, index.QueryOptions) bool
 }
 
 // NB: nsIndexRuntimeOptions does not contain its own mutex as some of the variables
@@ -178,6 +264,11 @@ type indexFilesetsBeforeFn func(dir string,
 	exclusiveTime time.Time,
 ) ([]string, error)
 
+type indexFilesetsAtFn func(dir string,
+	nsID ident.ID,
+	blockStart time.Time,
+) (fs.FileSetFilesSlice, error)
+
 type newNamespaceIndexOpts struct {
 	md              namespace.Metadata
 	shardSet        sharding.ShardSet
@@ -264,11 +355,23 @@ func newNamespaceIndexWithOptions(
 		newIndexQueueFn = newIndexOpts.newIndexQueueFn
 		newBlockFn      = newIndexOpts.newBlockFn
 		runtimeOptsMgr  = newIndexOpts.opts.RuntimeOptionsManager()
+		// Index writes of series of any write class of the namespace must be
+		// accepted and blocks sealed only once none can arrive anymore.
+		warmWriteRetentionOpts = namespace.WarmWriteRetentionOptions(nsMD.Options())
 	)
 	if err := indexOpts.Validate(); err != nil {
 		return nil, err
 	}
 
+	// Tokenize the values of the tokenized fields of the namespace in
+	// every segment built for it.
+	storageOpts := newIndexOpts.opts
+	if analyzers := index.NewFieldAnalyzers(nsMD.Options().IndexOptions()); analyzers != nil {
+		indexOpts = indexOpts.SetSegmentBuilderOptions(
+			indexOpts.SegmentBuilderOptions().SetFieldAnalyzers(analyzers))
+		storageOpts = storageOpts.SetIndexOptions(indexOpts)
+	}
+
 	scope := instrumentOpts.MetricsScope().
 		SubScope("dbindex").
 		Tagged(map[string]string{
@@ -285,24 +388,30 @@ func newNamespaceIndexWithOptions(
 				insertMode: indexOpts.InsertMode(), // FOLLOWUP(prateek): wire to allow this to be tweaked at runtime
 			},
 			blocksByTime: make(map[xtime.UnixNano]index.Block),
+			blockReads:   &blockReads{},
 		},
 
 		nowFn:                 nowFn,
 		blockSize:             nsMD.Options().IndexOptions().BlockSize(),
 		retentionPeriod:       nsMD.Options().RetentionOptions().RetentionPeriod(),
 		futureRetentionPeriod: nsMD.Options().RetentionOptions().FutureRetentionPeriod(),
-		bufferPast:            nsMD.Options().RetentionOptions().BufferPast(),
-		bufferFuture:          nsMD.Options().RetentionOptions().BufferFuture(),
+		bufferPast:            warmWriteRetentionOpts.BufferPast(),
+		bufferFuture:          warmWriteRetentionOpts.BufferFuture(),
 		coldWritesEnabled:     nsMD.Options().ColdWritesEnabled(),
+		compactionAge:         nsMD.Options().IndexOptions().CompactionAge(),
+		compactedBlockSize:    nsMD.Options().IndexOptions().CompactedBlockSize(),
 
 		indexFilesetsBeforeFn: fs.IndexFileSetsBefore,
+		indexFilesetsAtFn:     fs.IndexFileSetsAt,
 		deleteFilesFn:         fs.DeleteFiles,
 
 		newBlockFn: newBlockFn,
-		opts:       newIndexOpts.opts,
+		opts:       storageOpts,
 		logger:     indexOpts.InstrumentOptions().Logger(),
 		nsMetadata: nsMD,
 
+		seriesDeleted: &seriesDeletedFilter{},
+
 		resultsPool:          indexOpts.QueryResultsPool(),
 		aggregateResultsPool: indexOpts.AggregateResultsPool(),
 
@@ -647,6 +756,48 @@ func (i *nsIndex) writeBatchForBlockStart(
 	}
 }
 
+func (i *nsIndex) WriteRollup(
+	shard uint32,
+	blockStart, blockEnd time.Time,
+	docs []doc.Document,
+) error {
+	if len(docs) == 0 {
+		return nil
+	}
+
+	block, err := i.ensureBlockPresent(blockStart.Truncate(i.blockSize))
+	if err != nil {
+		return err
+	}
+
+	// NB: the index blocks covering rolled up data are usually sealed by the
+	// time the data is rolled up so the documents are added to the block as a
+	// segment fulfilling the shard for the data block, the same way flushed
+	// and bootstrapped segments are, rather than written to it.
+	seg, err := mem.NewSegment(0, i.opts.IndexOptions().MemSegmentOptions())
+	if err != nil {
+		return err
+	}
+	err = seg.InsertBatch(m3ninxindex.Batch{
+		Docs:                docs,
+		AllowPartialUpdates: true,
+	})
+	if partialErr, ok := err.(*m3ninxindex.BatchPartialError); ok {
+		err = partialErr.FilterDuplicateIDErrors()
+	}
+	if err == nil {
+		err = seg.Seal()
+	}
+	if err != nil {
+		seg.Close()
+		return err
+	}
+
+	fulfilled := result.NewShardTimeRanges(blockStart, blockEnd, shard)
+	return block.AddResults(result.NewIndexBlock(block.StartTime(),
+		[]segment.Segment{seg}, fulfilled))
+}
+
 // Bootstrap bootstraps the index with the provide blocks.
 func (i *nsIndex) Bootstrap(
 	bootstrapResults result.IndexResults,
@@ -668,14 +819,25 @@ func (i *nsIndex) Bootstrap(
 		i.state.Unlock()
 	}()
 
+	// Allocate the blocks compacted from adjacent blocks first so that the
+	// results of the blocks they cover are added to them.
+	blockResults := make([]result.IndexBlock, 0, len(bootstrapResults))
+	for _, blockResult := range bootstrapResults {
+		blockResults = append(blockResults, blockResult)
+	}
+	sort.Slice(blockResults, func(a, b int) bool {
+		return blockResults[a].BlockSize() > blockResults[b].BlockSize()
+	})
+
 	var multiErr xerrors.MultiError
-	for blockStart, blockResults := range bootstrapResults {
-		block, err := i.ensureBlockPresentWithRLock(blockStart.ToTime())
+	for _, blockResult := range blockResults {
+		block, err := i.ensureBlockPresentWithSizeWithRLock(
+			blockResult.BlockStart(), blockResult.BlockSize())
 		if err != nil { // should never happen
 			multiErr = multiErr.Add(i.unableToAllocBlockInvariantError(err))
 			continue
 		}
-		if err := block.AddResults(blockResults); err != nil {
+		if err := block.AddResults(blockResult); err != nil {
 			multiErr = multiErr.Add(err)
 		}
 	}
@@ -705,15 +867,32 @@ func (i *nsIndex) Tick(c context.Cancellable, startTime time.Time) (namespaceInd
 
 	result.NumBlocks = int64(len(i.state.blocksByTime))
 
-	var multiErr xerrors.MultiError
+	var (
+		multiErr     xerrors.MultiError
+		pendingClose = i.state.blocksPendingClose[:0]
+	)
+	for _, pending := range i.state.blocksPendingClose {
+		if !pending.reads.done() {
+			// Still read by a query, close on a later tick.
+			pendingClose = append(pendingClose, pending)
+			continue
+		}
+		multiErr = multiErr.Add(pending.block.Close())
+	}
+	for j := len(pendingClose); j < len(i.state.blocksPendingClose); j++ {
+		i.state.blocksPendingClose[j] = pendingCloseBlock{}
+	}
+	i.state.blocksPendingClose = pendingClose
+
 	for blockStart, block := range i.state.blocksByTime {
 		if c.IsCancelled() {
 			multiErr = multiErr.Add(errDbIndexTerminatingTickCancellation)
 			return result, multiErr.FinalError()
 		}
 
-		// drop any blocks past the retention period
-		if blockStart.ToTime().Before(earliestBlockStartToRetain) {
+		// drop any blocks past the retention period, compacted blocks are
+		// only dropped once all of the blocks they hold are past it
+		if !block.EndTime().After(earliestBlockStartToRetain) {
 			multiErr = multiErr.Add(block.Close())
 			delete(i.state.blocksByTime, blockStart)
 			result.NumBlocksEvicted++
@@ -783,9 +962,49 @@ func (i *nsIndex) Flush(
 		}
 	}
 	i.metrics.BlocksEvictedMutableSegments.Inc(int64(evicted))
+
+	i.compactBlocks(flush, shards)
+	i.persistPostingsListCaches()
 	return nil
 }
 
+// persistPostingsListCaches writes the most recently used cached postings lists
+// of the sealed blocks alongside their index filesets, at most once per persist
+// interval, so that the cache is warm right after a restart.
+func (i *nsIndex) persistPostingsListCaches() {
+	indexOpts := i.opts.IndexOptions()
+	readThroughOpts := indexOpts.ReadThroughSegmentOptions()
+	if !readThroughOpts.PersistCache || indexOpts.PostingsListCache() == nil {
+		return
+	}
+
+	now := i.nowFn()
+	i.state.Lock()
+	if now.Sub(i.state.lastPostingsListCachePersist) < readThroughOpts.PersistCacheInterval {
+		i.state.Unlock()
+		return
+	}
+	i.state.lastPostingsListCachePersist = now
+	blocks := make([]index.Block, 0, len(i.state.blocksByTime))
+	for _, block := range i.state.blocksByTime {
+		if block.IsSealed() {
+			blocks = append(blocks, block)
+		}
+	}
+	i.state.Unlock()
+
+	fsOpts := i.opts.CommitLogOptions().FilesystemOptions()
+	for _, block := range blocks {
+		if err := block.PersistPostingsListCache(fsOpts); err != nil {
+			i.metrics.PostingsListCachePersistErrors.Inc(1)
+			i.logger.Warn("unable to persist postings list cache of index block",
+				zap.Time("blockStart", block.StartTime()),
+				zap.Error(err),
+			)
+		}
+	}
+}
+
 func (i *nsIndex) flushableBlocks(
 	shards []databaseShard,
 ) ([]index.Block, error) {
@@ -913,6 +1132,11 @@ func (i *nsIndex) flushBlockSegment(
 			}
 
 			for _, result := range results.Results() {
+				if shard.SeriesDeleted(result.ID) {
+					// Deleted series are removed from the index when flushed.
+					continue
+				}
+
 				doc, err := convert.FromMetricIter(result.ID, result.Tags)
 				if err != nil {
 					return err
@@ -952,11 +1176,140 @@ func (i *nsIndex) AssignShardSet(shardSet sharding.ShardSet) {
 	i.state.Unlock()
 }
 
-func (i *nsIndex) shardsFilterID() func(id ident.ID) bool {
+func (i *nsIndex) AssignSeriesDeletedFilter(fn func(id ident.ID) bool) {
+	i.seriesDeleted.set(fn)
+}
+
+func (i *nsIndex) AssignSeriesValueFilter(fn func(id ident.ID, opts index.QueryOptions) bool) {
+	i.state.Lock()
+	i.state.seriesValueFn = fn
+	i.state.Unlock()
+}
+
+// queryFilterID returns the filter to apply to query results, it excludes IDs
+// for shards this node does not own as well as series that have been deleted.
+func (i *nsIndex) queryFilterID() (func(id ident.ID) bool, bool) {
+	i.state.RLock()
+	shardsFn := i.state.shardsFilterID
+	i.state.RUnlock()
+	deletedFn := i.seriesDeleted.get()
+	if deletedFn == nil {
+		return shardsFn, false
+	}
+	if shardsFn == nil {
+		return func(id ident.ID) bool {
+			return !deletedFn(id)
+		}, true
+	}
+	return func(id ident.ID) bool {
+		return shardsFn(id) && !deletedFn(id)
+	}, true
+}
+
+// queryValueFilterID returns the filter to apply to query results extended
+// to exclude series that cannot satisfy the value filter of the query.
+func (i *nsIndex) queryValueFilterID(opts index.QueryOptions) func(id ident.ID) bool {
+	filterID, _ := i.queryFilterID()
+	if opts.ValueFilter == nil {
+		return filterID
+	}
+
 	i.state.RLock()
-	v := i.state.shardsFilterID
+	valueFn := i.state.seriesValueFn
 	i.state.RUnlock()
-	return v
+	if valueFn == nil {
+		return filterID
+	}
+	if filterID == nil {
+		return func(id ident.ID) bool {
+			return valueFn(id, opts)
+		}
+	}
+	return func(id ident.ID) bool {
+		return filterID(id) && valueFn(id, opts)
+	}
+}
+
+// querySeriesTTLFilter returns the filter to apply to query results that
+// excludes series with a TTL that has expired for the whole query range.
+func (i *nsIndex) querySeriesTTLFilter(opts index.QueryOptions) func(d doc.Document) bool {
+	var (
+		now      = i.nowFn()
+		queryDur = opts.EndExclusive.Sub(opts.StartInclusive)
+	)
+	return func(d doc.Document) bool {
+		for _, f := range d.Fields {
+			if !retention.IsSeriesTTLTagName(f.Name) {
+				continue
+			}
+			ttl, err := retention.ParseSeriesTTL(f.Value)
+			if err != nil {
+				return true
+			}
+			return !retention.SeriesBlockExpired(ttl, opts.StartInclusive, queryDur, now)
+		}
+		return true
+	}
+}
+
+// seriesTTLExpired returns whether the TTL of any series indexed within the
+// query range has expired for the whole query range.
+func (i *nsIndex) seriesTTLExpired(
+	ctx context.Context,
+	opts index.QueryOptions,
+) (bool, error) {
+	now := i.nowFn()
+	if !opts.EndExclusive.Before(now) {
+		// TTLs are positive so none can have expired yet.
+		return false, nil
+	}
+
+	ttls, err := i.SeriesTTLs(ctx, opts.StartInclusive, opts.EndExclusive)
+	if err != nil {
+		return false, err
+	}
+	queryDur := opts.EndExclusive.Sub(opts.StartInclusive)
+	for _, ttl := range ttls {
+		if retention.SeriesBlockExpired(ttl, opts.StartInclusive, queryDur, now) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// SeriesTTLs returns the distinct TTLs of the series indexed within the given
+// range, aggregated from the terms of the series TTL tag.
+func (i *nsIndex) SeriesTTLs(
+	ctx context.Context,
+	start, end time.Time,
+) ([]time.Duration, error) {
+	results := i.aggregateResultsPool.Get()
+	results.Reset(i.nsMetadata.ID(), index.AggregateResultsOptions{
+		Type:        index.AggregateTagNamesAndValues,
+		FieldFilter: index.AggregateFieldFilter{seriesTTLTagName},
+	})
+	ctx.RegisterFinalizer(results)
+
+	opts := index.QueryOptions{StartInclusive: start, EndExclusive: end}
+	query := index.Query{Query: idx.NewFieldQuery(seriesTTLTagName)}
+	_, err := i.query(ctx, query, results, opts, i.execBlockAggregateQueryFn, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var ttls []time.Duration
+	for _, entry := range results.Map().Iter() {
+		values := entry.Value()
+		for _, value := range values.Map().Iter() {
+			ttl, err := retention.ParseSeriesTTL(value.Key().Bytes())
+			if err != nil {
+				// Series with an invalid TTL are never written.
+				continue
+			}
+			ttls = append(ttls, ttl)
+		}
+	}
+	return ttls, nil
 }
 
 func (i *nsIndex) Query(
@@ -977,12 +1330,26 @@ func (i *nsIndex) Query(
 	defer sp.Finish()
 
 	// Get results and set the namespace ID and size limit.
+	filterID := i.queryValueFilterID(opts)
 	results := i.resultsPool.Get()
 	results.Reset(i.nsMetadata.ID(), index.QueryResultsOptions{
-		SizeLimit: opts.Limit,
-		FilterID:  i.shardsFilterID(),
+		SizeLimit:      opts.Limit,
+		FilterID:       filterID,
+		FilterDocument: i.querySeriesTTLFilter(opts),
 	})
 	ctx.RegisterFinalizer(results)
+	if opts.Paginated() {
+		next, err := i.queryPage(ctx, query, results, opts, logFields)
+		if err != nil {
+			sp.LogFields(opentracinglog.Error(err))
+			return index.QueryResult{}, err
+		}
+		return index.QueryResult{
+			Results:       results,
+			Exhaustive:    next == nil,
+			NextPageToken: next,
+		}, nil
+	}
 	exhaustive, err := i.query(ctx, query, results, opts, i.execBlockQueryFn, logFields)
 	if err != nil {
 		sp.LogFields(opentracinglog.Error(err))
@@ -1014,24 +1381,55 @@ func (i *nsIndex) AggregateQuery(
 	// Get results and set the filters, namespace ID and size limit.
 	results := i.aggregateResultsPool.Get()
 	aopts := index.AggregateResultsOptions{
-		SizeLimit:   opts.Limit,
-		FieldFilter: opts.FieldFilter,
-		Type:        opts.Type,
+		SizeLimit:      opts.Limit,
+		FieldFilter:    opts.FieldFilter,
+		Type:           opts.Type,
+		FilterDocument: i.querySeriesTTLFilter(opts.QueryOptions),
 	}
 	ctx.RegisterFinalizer(results)
+	// NB: deleted series are filtered out by ID when the aggregation is
+	// computed from the matching documents, otherwise they are removed
+	// from the terms of the index as its segments are compacted or flushed.
+	filterID, hasDeleted := i.queryFilterID()
+	if hasDeleted {
+		aopts.FilterID = filterID
+	}
 	// use appropriate fn to query underlying blocks.
 	// default to block.Query()
 	fn := i.execBlockQueryFn
-	// use block.Aggregate() when possible
-	if query.Equal(allQuery) {
-		fn = i.execBlockAggregateQueryFn
-	}
 	field, isField := idx.FieldQuery(query.Query)
 	if isField {
-		fn = i.execBlockAggregateQueryFn
 		aopts.FieldFilter = aopts.FieldFilter.AddIfMissing(field)
 	}
+	// use block.Aggregate() when possible, field terms cannot be filtered
+	// by the TTL of the series they belong to so the documents are read
+	// when the TTL of some series has expired over the query range.
+	if !opts.Paginated() && (query.Equal(allQuery) || isField) {
+		expired, err := i.seriesTTLExpired(ctx, opts.QueryOptions)
+		if err != nil {
+			return index.AggregateQueryResult{}, err
+		}
+		if !expired {
+			fn = i.execBlockAggregateQueryFn
+		}
+	}
 	aopts.FieldFilter = aopts.FieldFilter.SortAndDedupe()
+	if opts.Paginated() {
+		// NB: pages are read from documents so that they can be resumed, the
+		// size limit is not enforced by the results so that no value of a
+		// document counted as read by the page is dropped.
+		aopts.SizeLimit = 0
+		results.Reset(i.nsMetadata.ID(), aopts)
+		next, err := i.queryPage(ctx, query, results, opts.QueryOptions, logFields)
+		if err != nil {
+			return index.AggregateQueryResult{}, err
+		}
+		return index.AggregateQueryResult{
+			Results:       results,
+			Exhaustive:    next == nil,
+			NextPageToken: next,
+		}, nil
+	}
 	results.Reset(i.nsMetadata.ID(), aopts)
 	exhaustive, err := i.query(ctx, query, results, opts.QueryOptions, fn, logFields)
 	if err != nil {
@@ -1043,6 +1441,70 @@ func (i *nsIndex) AggregateQuery(
 	}, nil
 }
 
+func (i *nsIndex) Cardinality(
+	ctx context.Context,
+	opts index.CardinalityOptions,
+) (index.CardinalityResults, error) {
+	ctx, sp := ctx.StartTraceSpan(tracepoint.NSIdxCardinality)
+	sp.LogFields(
+		opentracinglog.String("namespace", i.nsMetadata.ID().String()),
+		opentracinglog.Int("limit", opts.Limit),
+		xopentracing.Time("queryStart", opts.StartInclusive),
+		xopentracing.Time("queryEnd", opts.EndExclusive),
+	)
+	defer sp.Finish()
+
+	if err := opts.Match.Validate(); err != nil {
+		sp.LogFields(opentracinglog.Error(err))
+		return index.CardinalityResults{}, err
+	}
+
+	// The filtered fields iterator requires the filter in order.
+	opts.FieldFilter = opts.FieldFilter.SortAndDedupe()
+
+	i.state.RLock()
+	if !i.isOpenWithRLock() {
+		i.state.RUnlock()
+		return index.CardinalityResults{}, errDbIndexUnableToQueryClosed
+	}
+
+	// Track this as an inflight query that needs to finish
+	// when the index is closed.
+	i.queriesWg.Add(1)
+	defer i.queriesWg.Done()
+
+	blocks, err := i.blocksForQueryWithRLock(xtime.NewRanges(xtime.Range{
+		Start: opts.StartInclusive,
+		End:   opts.EndExclusive,
+	}))
+
+	// Hold the blocks until they have been read, same as queries.
+	reads := i.state.blockReads
+	reads.acquire()
+	defer reads.release()
+
+	// Release the lock before reading the blocks, same as queries.
+	i.state.RUnlock()
+
+	if err != nil {
+		sp.LogFields(opentracinglog.Error(err))
+		return index.CardinalityResults{}, err
+	}
+
+	cancellable := resource.NewCancellableLifetime()
+	defer cancellable.Cancel()
+
+	acc := index.NewCardinalityAccumulator()
+	for _, block := range blocks {
+		if err := block.Cardinality(cancellable, opts, acc); err != nil {
+			sp.LogFields(opentracinglog.Error(err))
+			return index.CardinalityResults{}, err
+		}
+	}
+
+	return acc.MatchResults(opts.Match, opts.Limit), nil
+}
+
 func (i *nsIndex) query(
 	ctx context.Context,
 	query index.Query,
@@ -1098,13 +1560,17 @@ func (i *nsIndex) queryWithSpan(
 		End:   opts.EndExclusive,
 	}))
 
+	// Hold the blocks while the lock is held so that blocks replaced by a
+	// compacted block are not closed before the query has read them, each
+	// task holds them too since it can still be reading once a query that
+	// timed out returns.
+	reads := i.state.blockReads
+	reads.acquire()
+	defer reads.release()
+
 	// Can now release the lock and execute the query without holding the lock.
 	i.state.RUnlock()
 
-	if err != nil {
-		return false, err
-	}
-
 	var (
 		// State contains concurrent mutable state for async execution below.
 		state = asyncQueryExecState{
@@ -1114,6 +1580,10 @@ func (i *nsIndex) queryWithSpan(
 		wg       sync.WaitGroup
 	)
 
+	if err != nil {
+		return false, err
+	}
+
 	// Create a cancellable lifetime and cancel it at end of this method so that
 	// no child async task modifies the result after this method returns.
 	cancellable := resource.NewCancellableLifetime()
@@ -1143,8 +1613,10 @@ func (i *nsIndex) queryWithSpan(
 		if applyTimeout := timeout > 0; !applyTimeout {
 			// No timeout, just wait blockingly for a worker.
 			wg.Add(1)
+			reads.acquire()
 			i.queryWorkersPool.Go(func() {
 				execBlockFn(ctx, cancellable, block, query, opts, &state, results, logFields)
+				reads.release()
 				wg.Done()
 			})
 			continue
@@ -1154,13 +1626,16 @@ func (i *nsIndex) queryWithSpan(
 		var timedOut bool
 		if timeLeft := deadline.Sub(i.nowFn()); timeLeft > 0 {
 			wg.Add(1)
+			reads.acquire()
 			timedOut := !i.queryWorkersPool.GoWithTimeout(func() {
 				execBlockFn(ctx, cancellable, block, query, opts, &state, results, logFields)
+				reads.release()
 				wg.Done()
 			}, timeLeft)
 
 			if timedOut {
 				// Did not launch task, need to ensure don't wait for it.
+				reads.release()
 				wg.Done()
 			}
 		} else {
@@ -1220,6 +1695,158 @@ func (i *nsIndex) queryWithSpan(
 	return exhaustive, nil
 }
 
+// queryPage reads a single page of a paginated query and returns the token
+// to resume the query from, or nil once the query is exhausted. A page holds
+// the matching series with the smallest IDs after the cursor across every
+// block, so that the pages of every replica end at the same series.
+func (i *nsIndex) queryPage(
+	ctx context.Context,
+	query index.Query,
+	results index.BaseResults,
+	opts index.QueryOptions,
+	logFields []opentracinglog.Field,
+) (index.PageToken, error) {
+	ctx, sp := ctx.StartTraceSpan(tracepoint.NSIdxQueryPage)
+	sp.LogFields(logFields...)
+	defer sp.Finish()
+
+	next, err := i.queryPageWithSpan(ctx, query, results, opts, logFields)
+	if err != nil {
+		sp.LogFields(opentracinglog.Error(err))
+	}
+
+	return next, err
+}
+
+func (i *nsIndex) queryPageWithSpan(
+	ctx context.Context,
+	query index.Query,
+	results index.BaseResults,
+	opts index.QueryOptions,
+	logFields []opentracinglog.Field,
+) (index.PageToken, error) {
+	// Capture start before needing to acquire lock.
+	start := i.nowFn()
+
+	i.state.RLock()
+	if !i.isOpenWithRLock() {
+		i.state.RUnlock()
+		return nil, errDbIndexUnableToQueryClosed
+	}
+
+	// Track this as an inflight query that needs to finish
+	// when the index is closed.
+	i.queriesWg.Add(1)
+	defer i.queriesWg.Done()
+
+	// Enact overrides for query options
+	opts = i.overriddenOptsForQueryWithRLock(opts)
+	timeout := i.timeoutForQueryWithRLock(ctx)
+
+	blocks, err := i.blocksForQueryWithRLock(xtime.NewRanges(xtime.Range{
+		Start: opts.StartInclusive,
+		End:   opts.EndExclusive,
+	}))
+
+	// Hold the blocks while the lock is held so that blocks replaced by a
+	// compacted block are not closed before the query has read them. The
+	// blocks are read one at a time so none is read once this returns.
+	reads := i.state.blockReads
+	reads.acquire()
+	defer reads.release()
+
+	// Can now release the lock and execute the query without holding the lock.
+	i.state.RUnlock()
+
+	if err != nil {
+		return nil, err
+	}
+
+	cursor, err := opts.PageCursor()
+	if err != nil {
+		return nil, xerrors.NewInvalidParamsError(err)
+	}
+
+	// Create a cancellable lifetime and cancel it at end of this method so that
+	// no child async task modifies the result after this method returns.
+	cancellable := resource.NewCancellableLifetime()
+	defer cancellable.Cancel()
+
+	var (
+		page     = index.NewPageResults(cursor, opts.Limit)
+		deadline = start.Add(timeout)
+	)
+	for _, block := range blocks {
+		if cursor.BlockFinished(xtime.ToUnixNano(block.StartTime())) {
+			// NB: the previous pages have read every matching series of the block.
+			continue
+		}
+
+		var (
+			blockErr error
+			wg       sync.WaitGroup
+		)
+		// NB: blocks are read one at a time since the page is not safe for
+		// concurrent use.
+		wg.Add(1)
+		task := func() {
+			blockErr = i.execBlockQueryPage(ctx, cancellable, block, query,
+				page, logFields)
+			wg.Done()
+		}
+		if applyTimeout := timeout > 0; !applyTimeout {
+			// No timeout, just wait blockingly for a worker.
+			i.queryWorkersPool.Go(task)
+		} else if timeLeft := deadline.Sub(i.nowFn()); timeLeft <= 0 ||
+			!i.queryWorkersPool.GoWithTimeout(task, timeLeft) {
+			return nil, fmt.Errorf("index query timed out: %s", timeout.String())
+		}
+		wg.Wait()
+
+		if blockErr == index.ErrUnableToQueryBlockClosed {
+			// NB(r): Because we query this block outside of the results lock, it's
+			// possible this block may get closed if it slides out of retention, in
+			// that case those results are no longer considered valid and outside of
+			// retention regardless, so this is a non-issue.
+			continue
+		}
+		if blockErr != nil {
+			return nil, blockErr
+		}
+	}
+
+	// NB: every block has been read so no task can modify the page any longer.
+	if _, err := results.AddDocuments(page.Documents()); err != nil {
+		return nil, err
+	}
+
+	return page.NextPageToken(), nil
+}
+
+func (i *nsIndex) execBlockQueryPage(
+	ctx context.Context,
+	cancellable *resource.CancellableLifetime,
+	block index.Block,
+	query index.Query,
+	page *index.PageResults,
+	logFields []opentracinglog.Field,
+) error {
+	logFields = append(logFields,
+		xopentracing.Time("blockStart", block.StartTime()),
+		xopentracing.Time("blockEnd", block.EndTime()),
+	)
+
+	ctx, sp := ctx.StartTraceSpan(tracepoint.NSIdxBlockQuery)
+	sp.LogFields(logFields...)
+	defer sp.Finish()
+
+	err := block.QueryPage(ctx, cancellable, query, page, logFields)
+	if err != nil && err != index.ErrUnableToQueryBlockClosed {
+		sp.LogFields(opentracinglog.Error(err))
+	}
+	return err
+}
+
 func (i *nsIndex) execBlockQueryFn(
 	ctx context.Context,
 	cancellable *resource.CancellableLifetime,
@@ -1373,6 +2000,16 @@ func (i *nsIndex) ensureBlockPresent(blockStart time.Time) (index.Block, error)
 // blockStart, allocating one if it does not. It returns the desired block, or
 // error if it's unable to do so.
 func (i *nsIndex) ensureBlockPresentWithRLock(blockStart time.Time) (index.Block, error) {
+	return i.ensureBlockPresentWithSizeWithRLock(blockStart, 0)
+}
+
+// ensureBlockPresentWithSizeWithRLock is ensureBlockPresentWithRLock for
+// blocks of a block size other than the index block size, a zero block size
+// is the index block size.
+func (i *nsIndex) ensureBlockPresentWithSizeWithRLock(
+	blockStart time.Time,
+	blockSize time.Duration,
+) (index.Block, error) {
 	// check if the current latest block matches the required block, this
 	// is the usual path and can short circuit the rest of the logic in this
 	// function in most cases.
@@ -1387,6 +2024,11 @@ func (i *nsIndex) ensureBlockPresentWithRLock(blockStart time.Time) (index.Block
 		return block, nil
 	}
 
+	// check if held by a block compacted from adjacent blocks.
+	if block, ok := i.compactedBlockWithRLock(blockStart); ok {
+		return block, nil
+	}
+
 	// i.e. block start does not exist, so we have to alloc.
 	// we release the RLock (the function is called with this lock), and acquire
 	// the write lock to do the extra allocation.
@@ -1404,10 +2046,16 @@ func (i *nsIndex) ensureBlockPresentWithRLock(blockStart time.Time) (index.Block
 	if block, ok := i.state.blocksByTime[blockStartNanos]; ok {
 		return block, nil
 	}
+	if block, ok := i.compactedBlockWithRLock(blockStart); ok {
+		return block, nil
+	}
 
 	// ok now we know for sure we have to alloc
 	block, err := i.newBlockFn(blockStart, i.nsMetadata,
-		index.BlockOptions{}, i.opts.IndexOptions())
+		index.BlockOptions{
+			BlockSize:       blockSize,
+			DocumentsFilter: i.seriesDeleted,
+		}, i.opts.IndexOptions())
 	if err != nil { // unable to allocate the block, should never happen.
 		return nil, i.unableToAllocBlockInvariantError(err)
 	}
@@ -1420,6 +2068,20 @@ func (i *nsIndex) ensureBlockPresentWithRLock(blockStart time.Time) (index.Block
 	return block, nil
 }
 
+// compactedBlockWithRLock returns the block compacted from adjacent blocks
+// that holds the block starting at the given time, if any.
+func (i *nsIndex) compactedBlockWithRLock(blockStart time.Time) (index.Block, bool) {
+	if i.compactedBlockSize <= 0 {
+		return nil, false
+	}
+	compactedStart := xtime.ToUnixNano(blockStart.Truncate(i.compactedBlockSize))
+	block, ok := i.state.blocksByTime[compactedStart]
+	if !ok || !block.EndTime().After(blockStart) {
+		return nil, false
+	}
+	return block, true
+}
+
 func (i *nsIndex) updateBlockStartsWithLock() {
 	// update ordered blockStarts slice
 	var (
@@ -1497,13 +2159,18 @@ func (i *nsIndex) Close() error {
 	var multiErr xerrors.MultiError
 	multiErr = multiErr.Add(i.state.insertQueue.Stop())
 
-	blocks := make([]index.Block, 0, len(i.state.blocksByTime))
+	blocks := make([]index.Block, 0, len(i.state.blocksByTime)+
+		len(i.state.blocksPendingClose))
 	for _, block := range i.state.blocksByTime {
 		blocks = append(blocks, block)
 	}
+	for _, pending := range i.state.blocksPendingClose {
+		blocks = append(blocks, pending.block)
+	}
 
 	i.state.latestBlock = nil
 	i.state.blocksByTime = nil
+	i.state.blocksPendingClose = nil
 	i.state.blockStartsDescOrder = nil
 
 	if i.runtimeOptsListener != nil {
@@ -1544,13 +2211,16 @@ func (i *nsIndex) unableToAllocBlockInvariantError(err error) error {
 }
 
 type nsIndexMetrics struct {
-	AsyncInsertSuccess           tally.Counter
-	AsyncInsertErrors            tally.Counter
-	InsertAfterClose             tally.Counter
-	QueryAfterClose              tally.Counter
-	InsertEndToEndLatency        tally.Timer
-	BlocksEvictedMutableSegments tally.Counter
-	BlockMetrics                 nsIndexBlocksMetrics
+	AsyncInsertSuccess             tally.Counter
+	AsyncInsertErrors              tally.Counter
+	InsertAfterClose               tally.Counter
+	QueryAfterClose                tally.Counter
+	InsertEndToEndLatency          tally.Timer
+	BlocksEvictedMutableSegments   tally.Counter
+	BlocksCompacted                tally.Counter
+	BlockCompactionErrors          tally.Counter
+	PostingsListCachePersistErrors tally.Counter
+	BlockMetrics                   nsIndexBlocksMetrics
 }
 
 func newNamespaceIndexMetrics(
@@ -1574,7 +2244,14 @@ func newNamespaceIndexMetrics(
 			scope.Timer("insert-end-to-end-latency"),
 			iopts.MetricsSamplingRate()),
 		BlocksEvictedMutableSegments: scope.Counter("blocks-evicted-mutable-segments"),
-		BlockMetrics:                 newNamespaceIndexBlocksMetrics(opts, blocksScope),
+		BlocksCompacted:              scope.Counter("blocks-compacted"),
+		BlockCompactionErrors: scope.Tagged(map[string]string{
+			"error_type": "block-compaction",
+		}).Counter("index-error"),
+		PostingsListCachePersistErrors: scope.Tagged(map[string]string{
+			"error_type": "postings-list-cache-persist",
+		}).Counter("index-error"),
+		BlockMetrics: newNamespaceIndexBlocksMetrics(opts, blocksScope),
 	}
 }
 
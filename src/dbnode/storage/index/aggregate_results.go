@@ -151,6 +151,15 @@ func (r *aggregatedResults) addDocumentsBatchWithLock(
 	batch []doc.Document,
 ) error {
 	for _, doc := range batch {
+		if r.aggregateOpts.FilterID != nil &&
+			!r.aggregateOpts.FilterID(ident.BytesID(doc.ID)) {
+			continue
+		}
+		if r.aggregateOpts.FilterDocument != nil &&
+			!r.aggregateOpts.FilterDocument(doc) {
+			continue
+		}
+
 		switch r.aggregateOpts.Type {
 		case AggregateTagNamesAndValues:
 			if err := r.addDocumentWithLock(doc); err != nil {
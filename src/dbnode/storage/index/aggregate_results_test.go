@@ -112,6 +112,25 @@ func TestAggResultsTermOnlyInsertIdempotency(t *testing.T) {
 	testAggResultsInsertIdempotency(t, res)
 }
 
+func TestAggResultsInsertFilterDocument(t *testing.T) {
+	res := NewAggregateResults(nil, AggregateResultsOptions{
+		FilterDocument: func(d doc.Document) bool {
+			return !bytes.Equal(d.Fields[0].Value, []byte("baz"))
+		},
+	}, testOpts)
+	size, err := res.AddDocuments([]doc.Document{
+		genDoc("foo", "bar"),
+		genDoc("foo", "baz"),
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, size)
+
+	values, ok := res.Map().Get(ident.StringID("foo"))
+	require.True(t, ok)
+	require.Equal(t, 1, values.Size())
+	require.True(t, values.Map().Contains(ident.StringID("bar")))
+}
+
 func TestInvalidAggregateType(t *testing.T) {
 	res := NewAggregateResults(nil, AggregateResultsOptions{
 		Type: 100,
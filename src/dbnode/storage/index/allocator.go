@@ -21,7 +21,9 @@
 package index
 
 import (
+	"github.com/m3db/m3/src/dbnode/namespace"
 	"github.com/m3db/m3/src/dbnode/storage/bootstrap/result"
+	"github.com/m3db/m3/src/m3ninx/doc"
 	"github.com/m3db/m3/src/m3ninx/index/segment"
 	"github.com/m3db/m3/src/m3ninx/index/segment/builder"
 )
@@ -35,3 +37,9 @@ func NewBootstrapResultDocumentsBuilderAllocator(
 		return builder.NewBuilderFromDocuments(opts.SegmentBuilderOptions())
 	}
 }
+
+// NewFieldAnalyzers returns the analyzers that tokenize the tag values of
+// the tokenized fields of a namespace.
+func NewFieldAnalyzers(opts namespace.IndexOptions) doc.FieldAnalyzers {
+	return doc.NewFieldAnalyzers(opts.TokenizedFields(), doc.NewWordAnalyzer())
+}
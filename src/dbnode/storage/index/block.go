@@ -28,6 +28,7 @@ import (
 	"time"
 
 	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/dbnode/persist/fs"
 	"github.com/m3db/m3/src/dbnode/storage/bootstrap/result"
 	"github.com/m3db/m3/src/dbnode/storage/index/compaction"
 	"github.com/m3db/m3/src/dbnode/storage/index/segments"
@@ -37,6 +38,7 @@ import (
 	"github.com/m3db/m3/src/m3ninx/index/segment"
 	"github.com/m3db/m3/src/m3ninx/index/segment/builder"
 	"github.com/m3db/m3/src/m3ninx/index/segment/fst"
+	"github.com/m3db/m3/src/m3ninx/postings"
 	"github.com/m3db/m3/src/m3ninx/search"
 	"github.com/m3db/m3/src/m3ninx/search/executor"
 	"github.com/m3db/m3/src/x/context"
@@ -65,6 +67,8 @@ var (
 	errUnableToBootstrapBlockClosed            = errors.New("unable to bootstrap, block is closed")
 	errUnableToTickBlockClosed                 = errors.New("unable to tick, block is closed")
 	errBlockAlreadyClosed                      = errors.New("unable to close, block already closed")
+	errUnableToPersistCacheBlockClosed         = errors.New("unable to persist postings list cache, block is closed")
+	errUnableToGetPersistedSegmentsMutable     = errors.New("unable to get persisted segments, block has mutable segments")
 	errForegroundCompactorNoPlan               = errors.New("index foreground compactor failed to generate a plan")
 	errForegroundCompactorBadPlanFirstTask     = errors.New("index foreground compactor generated plan without mutable segment in first task")
 	errForegroundCompactorBadPlanSecondaryTask = errors.New("index foreground compactor generated plan with mutable segment a secondary task")
@@ -130,6 +134,12 @@ type block struct {
 	logger  *zap.Logger
 }
 
+// unwrappableSegment is a segment wrapping another segment, e.g. to hold
+// bootstrap metadata.
+type unwrappableSegment interface {
+	Unwrap() segment.Segment
+}
+
 type blockMetrics struct {
 	rotateActiveSegment                tally.Counter
 	rotateActiveSegmentAge             tally.Timer
@@ -182,6 +192,12 @@ type blockShardRangesSegments struct {
 type BlockOptions struct {
 	ForegroundCompactorMmapDocsData bool
 	BackgroundCompactorMmapDocsData bool
+	// BlockSize overrides the index block size of the namespace, e.g. for
+	// blocks compacted from adjacent blocks.
+	BlockSize time.Duration
+	// DocumentsFilter if set removes documents it does not contain when
+	// segments of the block are compacted, e.g. for deleted series.
+	DocumentsFilter segment.DocumentsFilter
 }
 
 // NewBlock returns a new Block, representing a complete reverse index for the
@@ -193,12 +209,16 @@ func NewBlock(
 	indexOpts Options,
 ) (Block, error) {
 	blockSize := md.Options().IndexOptions().BlockSize()
+	if opts.BlockSize > 0 {
+		blockSize = opts.BlockSize
+	}
 	iopts := indexOpts.InstrumentOptions()
 	b := &block{
 		state:      blockStateOpen,
 		blockStart: blockStart,
 		blockEnd:   blockStart.Add(blockSize),
 		blockSize:  blockSize,
+		blockOpts:  opts,
 		opts:       indexOpts,
 		iopts:      iopts,
 		nsMD:       md,
@@ -361,12 +381,13 @@ func (b *block) backgroundCompactWithTask(
 	}
 
 	start := time.Now()
-	compacted, err := b.compact.backgroundCompactor.Compact(segments, mmap.ReporterOptions{
-		Context: mmap.Context{
-			Name: mmapIndexBlockName,
-		},
-		Reporter: b.opts.MmapReporter(),
-	})
+	compacted, err := b.compact.backgroundCompactor.Compact(segments,
+		b.blockOpts.DocumentsFilter, mmap.ReporterOptions{
+			Context: mmap.Context{
+				Name: mmapIndexBlockName,
+			},
+			Reporter: b.opts.MmapReporter(),
+		})
 	took := time.Since(start)
 	b.metrics.backgroundCompactionTaskRunLatency.Record(took)
 
@@ -664,12 +685,13 @@ func (b *block) foregroundCompactWithTask(
 	}
 
 	start := time.Now()
-	compacted, err := b.compact.foregroundCompactor.CompactUsingBuilder(builder, segments, mmap.ReporterOptions{
-		Context: mmap.Context{
-			Name: mmapIndexBlockName,
-		},
-		Reporter: b.opts.MmapReporter(),
-	})
+	compacted, err := b.compact.foregroundCompactor.CompactUsingBuilder(builder, segments,
+		b.blockOpts.DocumentsFilter, mmap.ReporterOptions{
+			Context: mmap.Context{
+				Name: mmapIndexBlockName,
+			},
+			Reporter: b.opts.MmapReporter(),
+		})
 	took := time.Since(start)
 	b.metrics.foregroundCompactionTaskRunLatency.Record(took)
 
@@ -830,42 +852,11 @@ func (b *block) queryWithSpan(
 		return false, ErrUnableToQueryBlockClosed
 	}
 
-	exec, err := b.newExecutorFn()
+	iter, err := b.executeQueryWithRLock(ctx, cancellable, query)
 	if err != nil {
 		return false, err
 	}
 
-	// Make sure if we don't register to close the executor later
-	// that we close it before returning.
-	execCloseRegistered := false
-	defer func() {
-		if !execCloseRegistered {
-			b.closeExecutorAsync(exec)
-		}
-	}()
-
-	// FOLLOWUP(prateek): push down QueryOptions to restrict results
-	iter, err := exec.Execute(query.Query.SearchQuery())
-	if err != nil {
-		return false, err
-	}
-
-	// Register the executor to close when context closes
-	// so can avoid copying the results into the map and just take
-	// references to it.
-	// NB(r): Needs to still be a valid query otherwise
-	// the context could be invalid because the caller early returned
-	// which means it can't be used for finalization any longer.
-	valid := cancellable.TryCheckout()
-	if !valid {
-		return false, errCancelledQuery
-	}
-	execCloseRegistered = true // Make sure to not locally close it.
-	ctx.RegisterFinalizer(resource.FinalizerFn(func() {
-		b.closeExecutorAsync(exec)
-	}))
-	cancellable.ReleaseCheckout()
-
 	var (
 		iterCloser = safeCloser{closable: iter}
 		size       = results.Size()
@@ -918,6 +909,199 @@ func (b *block) queryWithSpan(
 	return exhaustive, nil
 }
 
+func (b *block) QueryPage(
+	ctx context.Context,
+	cancellable *resource.CancellableLifetime,
+	query Query,
+	page *PageResults,
+	logFields []opentracinglog.Field,
+) error {
+	ctx, sp := ctx.StartTraceSpan(tracepoint.BlockQueryPage)
+	sp.LogFields(logFields...)
+	defer sp.Finish()
+
+	err := b.queryPageWithSpan(ctx, cancellable, query, page)
+	if err != nil {
+		sp.LogFields(opentracinglog.Error(err))
+	}
+
+	return err
+}
+
+func (b *block) queryPageWithSpan(
+	ctx context.Context,
+	cancellable *resource.CancellableLifetime,
+	query Query,
+	page *PageResults,
+) error {
+	b.RLock()
+	defer b.RUnlock()
+
+	if b.state == blockStateClosed {
+		return ErrUnableToQueryBlockClosed
+	}
+
+	iter, err := b.executePageQueryWithRLock(ctx, cancellable, query,
+		page.cursor.AfterID)
+	if err != nil {
+		return err
+	}
+
+	var (
+		iterCloser = safeCloser{closable: iter}
+		docsPool   = b.opts.DocumentArrayPool()
+		batch      = docsPool.Get()
+		batchSize  = cap(batch)
+		lastID     []byte
+		rejected   bool
+	)
+	if batchSize == 0 {
+		batchSize = defaultQueryDocsBatchSize
+	}
+
+	// Register local data structures that need closing.
+	defer func() {
+		iterCloser.Close()
+		docsPool.Put(batch)
+	}()
+
+	// NB: the documents are in increasing order of their IDs after the cursor,
+	// so the block stops reading documents once the page rejects one.
+	for iter.Next() {
+		d := iter.Current()
+		if page.Rejects(d.ID) {
+			rejected = true
+			break
+		}
+
+		lastID = d.ID
+		batch = append(batch, d)
+		if len(batch) < batchSize {
+			continue
+		}
+
+		batch, err = b.addPageResults(cancellable, page, batch)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Add last batch to the page if remaining.
+	if len(batch) > 0 {
+		batch, err = b.addPageResults(cancellable, page, batch)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := iter.Err(); err != nil {
+		return err
+	}
+
+	// NB: sealed blocks take no more writes, so once every one of their
+	// matching documents has been offered to the page they can be skipped by
+	// the following pages.
+	if !rejected && b.state == blockStateSealed {
+		page.FinishBlock(xtime.ToUnixNano(b.blockStart), lastID)
+	}
+	return iterCloser.Close()
+}
+
+func (b *block) addPageResults(
+	cancellable *resource.CancellableLifetime,
+	page *PageResults,
+	batch []doc.Document,
+) ([]doc.Document, error) {
+	// checkout the lifetime of the query before adding to the page.
+	queryValid := cancellable.TryCheckout()
+	if !queryValid {
+		// query not valid any longer, do not add to the page and return early.
+		return batch, errCancelledQuery
+	}
+
+	page.AddDocuments(batch)
+
+	// immediately release the checkout on the lifetime of query.
+	cancellable.ReleaseCheckout()
+
+	// reset batch.
+	var emptyDoc doc.Document
+	for i := range batch {
+		batch[i] = emptyDoc
+	}
+	return batch[:0], nil
+}
+
+// executeQueryWithRLock executes the query against the segments of the
+// block, the executor is closed once the context is closed so that the
+// results can reference the documents without copying them.
+func (b *block) executeQueryWithRLock(
+	ctx context.Context,
+	cancellable *resource.CancellableLifetime,
+	query Query,
+) (doc.Iterator, error) {
+	return b.executeWithRLock(ctx, cancellable, func(exec search.Executor) (doc.Iterator, error) {
+		// FOLLOWUP(prateek): push down QueryOptions to restrict results
+		return exec.Execute(query.Query.SearchQuery())
+	})
+}
+
+// executePageQueryWithRLock executes the query for the documents after the
+// cursor in increasing order of their IDs.
+func (b *block) executePageQueryWithRLock(
+	ctx context.Context,
+	cancellable *resource.CancellableLifetime,
+	query Query,
+	afterID []byte,
+) (doc.Iterator, error) {
+	return b.executeWithRLock(ctx, cancellable, func(exec search.Executor) (doc.Iterator, error) {
+		return exec.ExecuteAfter(query.Query.SearchQuery(), afterID)
+	})
+}
+
+func (b *block) executeWithRLock(
+	ctx context.Context,
+	cancellable *resource.CancellableLifetime,
+	execute func(exec search.Executor) (doc.Iterator, error),
+) (doc.Iterator, error) {
+	exec, err := b.newExecutorFn()
+	if err != nil {
+		return nil, err
+	}
+
+	// Make sure if we don't register to close the executor later
+	// that we close it before returning.
+	execCloseRegistered := false
+	defer func() {
+		if !execCloseRegistered {
+			b.closeExecutorAsync(exec)
+		}
+	}()
+
+	iter, err := execute(exec)
+	if err != nil {
+		return nil, err
+	}
+
+	// Register the executor to close when context closes
+	// so can avoid copying the results into the map and just take
+	// references to it.
+	// NB(r): Needs to still be a valid query otherwise
+	// the context could be invalid because the caller early returned
+	// which means it can't be used for finalization any longer.
+	valid := cancellable.TryCheckout()
+	if !valid {
+		return nil, errCancelledQuery
+	}
+	execCloseRegistered = true // Make sure to not locally close it.
+	ctx.RegisterFinalizer(resource.FinalizerFn(func() {
+		b.closeExecutorAsync(exec)
+	}))
+	cancellable.ReleaseCheckout()
+
+	return iter, nil
+}
+
 func (b *block) closeExecutorAsync(exec search.Executor) {
 	// Note: This only happens if closing the readers isn't clean.
 	if err := exec.Close(); err != nil {
@@ -998,7 +1182,8 @@ func (b *block) aggregateWithSpan(
 		iterateTerms: iterateTerms,
 		allowFn: func(field []byte) bool {
 			// skip any field names that we shouldn't allow.
-			if bytes.Equal(field, doc.IDReservedFieldName) {
+			if bytes.Equal(field, doc.IDReservedFieldName) ||
+				doc.IsTokenizedFieldName(field) {
 				return false
 			}
 			return aggOpts.FieldFilter.Allow(field)
@@ -1187,6 +1372,128 @@ func (b *block) addAggregateResults(
 	return batch, size, nil
 }
 
+// Cardinality accumulates the number of distinct values and series of the
+// fields and terms indexed by the block. Only the lengths of postings lists
+// are used, no documents are retrieved from the segments.
+func (b *block) Cardinality(
+	cancellable *resource.CancellableLifetime,
+	opts CardinalityOptions,
+	acc *CardinalityAccumulator,
+) error {
+	b.RLock()
+	defer b.RUnlock()
+
+	if b.state == blockStateClosed {
+		return ErrUnableToQueryBlockClosed
+	}
+
+	matcher, err := opts.Match.termsMatcher()
+	if err != nil {
+		return err
+	}
+
+	blockAcc := NewCardinalityAccumulator()
+	for _, s := range b.segmentsWithRLock() {
+		// checkout the lifetime of the query before reading each segment.
+		if !cancellable.TryCheckout() {
+			return errCancelledQuery
+		}
+		err := addSegmentCardinality(s, opts, matcher, blockAcc)
+		cancellable.ReleaseCheckout()
+		if err != nil {
+			return err
+		}
+	}
+
+	acc.Merge(blockAcc)
+	return nil
+}
+
+func addSegmentCardinality(
+	s segment.Segment,
+	opts CardinalityOptions,
+	matcher m3ninxindex.TermsMatcher,
+	acc *CardinalityAccumulator,
+) error {
+	var (
+		fieldsIter segment.FieldsIterator
+		err        error
+	)
+	if len(opts.FieldFilter) == 0 {
+		fieldsIter, err = s.FieldsIterable().Fields()
+	} else {
+		fieldsIter, err = newFilterFieldsIterator(s, opts.FieldFilter)
+	}
+	if err != nil {
+		return err
+	}
+
+	// scope is the postings list of the series matching the query, if any,
+	// it must only be used before the reader is closed.
+	var scope postings.List
+	if opts.Query != nil {
+		reader, err := s.Reader()
+		if err != nil {
+			return xerrors.FirstError(err, fieldsIter.Close())
+		}
+		defer reader.Close()
+
+		searcher, err := opts.Query.SearchQuery().Searcher()
+		if err != nil {
+			return xerrors.FirstError(err, fieldsIter.Close())
+		}
+		scope, err = searcher.Search(reader)
+		if err != nil {
+			return xerrors.FirstError(err, fieldsIter.Close())
+		}
+		acc.addSeries(int64(scope.Len()))
+	} else {
+		acc.addSeries(s.Size())
+	}
+
+	for fieldsIter.Next() {
+		field := fieldsIter.Current()
+		// skip the reserved ID field, it has one term per series, and the
+		// tokens of tokenized fields which are not tags of any series.
+		if bytes.Equal(field, doc.IDReservedFieldName) ||
+			doc.IsTokenizedFieldName(field) {
+			continue
+		}
+		if matcher != nil && opts.Match.FieldNames && !matcher.MatchTerm(field) {
+			continue
+		}
+
+		var termsIter segment.TermsIterator
+		if matcher != nil && !opts.Match.FieldNames {
+			termsIter, err = newMatchingTermsIterator(s.TermsIterable(), field, matcher)
+		} else {
+			termsIter, err = s.TermsIterable().Terms(field)
+		}
+		if err != nil {
+			return xerrors.FirstError(err, fieldsIter.Close())
+		}
+		for termsIter.Next() {
+			term, postingsList := termsIter.Current()
+			numSeries := postingsList.Len()
+			if scope != nil {
+				numSeries, err = intersectionLen(postingsList, scope)
+				if err != nil {
+					break
+				}
+				if numSeries == 0 {
+					continue
+				}
+			}
+			acc.addTerm(field, term, int64(numSeries))
+		}
+		if err := xerrors.FirstError(err, termsIter.Err(), termsIter.Close()); err != nil {
+			return xerrors.FirstError(err, fieldsIter.Close())
+		}
+	}
+
+	return xerrors.FirstError(fieldsIter.Err(), fieldsIter.Close())
+}
+
 func (b *block) AddResults(
 	results result.IndexBlock,
 ) error {
@@ -1215,8 +1522,15 @@ func (b *block) AddResults(
 		readThroughOpts = b.opts.ReadThroughSegmentOptions()
 		segments        = results.Segments()
 	)
+	// Emit the cache hits and misses of the segments tagged by namespace.
+	readThroughOpts.InstrumentOptions = b.iopts
 	readThroughSegments := make([]segment.Segment, 0, len(segments))
 	for _, seg := range segments {
+		if wrapped, ok := seg.(unwrappableSegment); ok {
+			// Bootstrapped segments wrap the segments read from disk.
+			seg = wrapped.Unwrap()
+		}
+
 		readThroughSeg := seg
 		if immSeg, ok := seg.(segment.ImmutableSegment); ok {
 			// only wrap the immutable segments with a read through cache.
@@ -1398,6 +1712,39 @@ func (b *block) NeedsMutableSegmentsEvicted() bool {
 	return anyMutableSegmentNeedsEviction
 }
 
+func (b *block) PersistedSegments() ([]segment.Segment, error) {
+	b.RLock()
+	defer b.RUnlock()
+	if b.state != blockStateSealed {
+		return nil, fmt.Errorf("unable to get persisted segments, block must be sealed, found: %v", b.state)
+	}
+
+	for _, seg := range b.foregroundSegments {
+		if seg.Segment().Size() > 0 {
+			return nil, errUnableToGetPersistedSegmentsMutable
+		}
+	}
+	for _, seg := range b.backgroundSegments {
+		if seg.Segment().Size() > 0 {
+			return nil, errUnableToGetPersistedSegmentsMutable
+		}
+	}
+
+	var segments []segment.Segment
+	for _, group := range b.shardRangesSegments {
+		for _, seg := range group.segments {
+			if mutableSeg, ok := seg.(segment.MutableSegment); ok {
+				if mutableSeg.Size() > 0 {
+					return nil, errUnableToGetPersistedSegmentsMutable
+				}
+				continue
+			}
+			segments = append(segments, seg)
+		}
+	}
+	return segments, nil
+}
+
 func (b *block) EvictMutableSegments() error {
 	b.Lock()
 	defer b.Unlock()
@@ -1435,6 +1782,46 @@ func (b *block) EvictMutableSegments() error {
 	return multiErr.FinalError()
 }
 
+func (b *block) PersistPostingsListCache(fsOpts fs.Options) error {
+	// NB: the read lock prevents the segments, and hence the postings lists
+	// pointing into their mmap'd regions, from being closed while written.
+	b.RLock()
+	defer b.RUnlock()
+	if b.state == blockStateClosed {
+		return errUnableToPersistCacheBlockClosed
+	}
+
+	var (
+		maxEntries = b.opts.ReadThroughSegmentOptions().PersistCacheMaxEntries
+		volumes    = make(map[int]fs.FileSetFileIdentifier)
+		entries    = make(map[int][]fs.IndexPostingsCacheEntry)
+	)
+	for _, group := range b.shardRangesSegments {
+		for _, seg := range group.segments {
+			readThroughSeg, ok := seg.(*ReadThroughSegment)
+			if !ok {
+				continue
+			}
+
+			id, segEntries, ok := readThroughSeg.persistableCachedPostings(maxEntries)
+			if !ok {
+				continue
+			}
+			volumes[id.VolumeIndex] = id
+			entries[id.VolumeIndex] = append(entries[id.VolumeIndex], segEntries...)
+		}
+	}
+
+	// Volumes without any cached postings lists are written too so that
+	// postings lists persisted previously are no longer loaded.
+	multiErr := xerrors.NewMultiError()
+	for volumeIndex, id := range volumes {
+		err := fs.WriteIndexPostingsCache(fsOpts, id, entries[volumeIndex])
+		multiErr = multiErr.Add(err)
+	}
+	return multiErr.FinalError()
+}
+
 func (b *block) Close() error {
 	b.Lock()
 	defer b.Unlock()
@@ -23,10 +23,15 @@ package index
 import (
 	stdlibctx "context"
 	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
 	"testing"
 	"time"
 
 	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/persist/fs"
 	"github.com/m3db/m3/src/dbnode/retention"
 	"github.com/m3db/m3/src/dbnode/storage/bootstrap/result"
 	"github.com/m3db/m3/src/dbnode/storage/index/compaction"
@@ -35,7 +40,9 @@ import (
 	"github.com/m3db/m3/src/m3ninx/idx"
 	"github.com/m3db/m3/src/m3ninx/index"
 	"github.com/m3db/m3/src/m3ninx/index/segment"
+	"github.com/m3db/m3/src/m3ninx/index/segment/fst"
 	"github.com/m3db/m3/src/m3ninx/index/segment/mem"
+	"github.com/m3db/m3/src/m3ninx/postings/roaring"
 	"github.com/m3db/m3/src/m3ninx/search"
 	"github.com/m3db/m3/src/x/context"
 	"github.com/m3db/m3/src/x/ident"
@@ -715,6 +722,208 @@ func TestBlockMockQueryLimitExhaustive(t *testing.T) {
 	ctx.BlockingClose()
 }
 
+func TestBlockMockQueryPage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	testMD := newTestNSMetadata(t)
+	start := time.Now().Truncate(time.Hour)
+	blk, err := NewBlock(start, testMD, BlockOptions{}, testOpts)
+	require.NoError(t, err)
+
+	b, ok := blk.(*block)
+	require.True(t, ok)
+
+	exec := search.NewMockExecutor(ctrl)
+	b.newExecutorFn = func() (search.Executor, error) {
+		return exec, nil
+	}
+
+	dIter := doc.NewMockIterator(ctrl)
+	gomock.InOrder(
+		exec.EXPECT().ExecuteAfter(gomock.Any(), testDoc3().ID).Return(dIter, nil),
+		dIter.EXPECT().Next().Return(true),
+		dIter.EXPECT().Current().Return(testDoc1()),
+		dIter.EXPECT().Next().Return(true),
+		dIter.EXPECT().Current().Return(testDoc2()),
+		dIter.EXPECT().Next().Return(false),
+		dIter.EXPECT().Err().Return(nil),
+		dIter.EXPECT().Close().Return(nil),
+		exec.EXPECT().Close().Return(nil),
+	)
+	page := NewPageResults(QueryCursor{AfterID: testDoc3().ID}, 1)
+
+	ctx := context.NewContext()
+
+	err = b.QueryPage(ctx, resource.NewCancellableLifetime(), defaultQuery,
+		page, emptyLogFields)
+	require.NoError(t, err)
+
+	docs := page.Documents()
+	require.Equal(t, 1, len(docs))
+	require.Equal(t, testDoc1().ID, docs[0].ID)
+
+	cursor, err := page.NextPageToken().QueryCursor()
+	require.NoError(t, err)
+	require.Equal(t, testDoc1().ID, cursor.AfterID)
+
+	// NB(r): Make sure to call finalizers blockingly (to finish
+	// the expected close calls)
+	ctx.BlockingClose()
+}
+
+func TestBlockMockQueryPageExhausted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	testMD := newTestNSMetadata(t)
+	start := time.Now().Truncate(time.Hour)
+	blk, err := NewBlock(start, testMD, BlockOptions{}, testOpts)
+	require.NoError(t, err)
+
+	b, ok := blk.(*block)
+	require.True(t, ok)
+
+	exec := search.NewMockExecutor(ctrl)
+	b.newExecutorFn = func() (search.Executor, error) {
+		return exec, nil
+	}
+
+	dIter := doc.NewMockIterator(ctrl)
+	gomock.InOrder(
+		exec.EXPECT().ExecuteAfter(gomock.Any(), gomock.Nil()).Return(dIter, nil),
+		dIter.EXPECT().Next().Return(true),
+		dIter.EXPECT().Current().Return(testDoc1()),
+		dIter.EXPECT().Next().Return(false),
+		dIter.EXPECT().Err().Return(nil),
+		dIter.EXPECT().Close().Return(nil),
+		exec.EXPECT().Close().Return(nil),
+	)
+	page := NewPageResults(QueryCursor{}, 2)
+
+	ctx := context.NewContext()
+
+	err = b.QueryPage(ctx, resource.NewCancellableLifetime(), defaultQuery,
+		page, emptyLogFields)
+	require.NoError(t, err)
+
+	docs := page.Documents()
+	require.Equal(t, 1, len(docs))
+	require.Equal(t, testDoc1().ID, docs[0].ID)
+	require.Nil(t, page.NextPageToken())
+
+	// NB(r): Make sure to call finalizers blockingly (to finish
+	// the expected close calls)
+	ctx.BlockingClose()
+}
+
+func TestBlockMockQueryPageStopsOnceRejected(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	testMD := newTestNSMetadata(t)
+	start := time.Now().Truncate(time.Hour)
+	blk, err := NewBlock(start, testMD, BlockOptions{}, testOpts)
+	require.NoError(t, err)
+
+	b, ok := blk.(*block)
+	require.True(t, ok)
+	require.NoError(t, b.Seal())
+
+	exec := search.NewMockExecutor(ctrl)
+	b.newExecutorFn = func() (search.Executor, error) {
+		return exec, nil
+	}
+
+	// NB: the page is already full with a smaller ID, so the block stops
+	// reading documents at the first one.
+	dIter := doc.NewMockIterator(ctrl)
+	gomock.InOrder(
+		exec.EXPECT().ExecuteAfter(gomock.Any(), testDoc3().ID).Return(dIter, nil),
+		dIter.EXPECT().Next().Return(true),
+		dIter.EXPECT().Current().Return(testDoc2()),
+		dIter.EXPECT().Err().Return(nil),
+		dIter.EXPECT().Close().Return(nil),
+		exec.EXPECT().Close().Return(nil),
+	)
+	page := NewPageResults(QueryCursor{AfterID: testDoc3().ID}, 1)
+	page.AddDocuments([]doc.Document{testDoc1()})
+
+	ctx := context.NewContext()
+
+	err = b.QueryPage(ctx, resource.NewCancellableLifetime(), defaultQuery,
+		page, emptyLogFields)
+	require.NoError(t, err)
+
+	docs := page.Documents()
+	require.Equal(t, 1, len(docs))
+	require.Equal(t, testDoc1().ID, docs[0].ID)
+
+	// The block is not finished since it holds documents for the next page.
+	cursor, err := page.NextPageToken().QueryCursor()
+	require.NoError(t, err)
+	require.Equal(t, testDoc1().ID, cursor.AfterID)
+	require.Empty(t, cursor.FinishedBlocks)
+
+	// NB(r): Make sure to call finalizers blockingly (to finish
+	// the expected close calls)
+	ctx.BlockingClose()
+}
+
+func TestBlockMockQueryPageFinishesSealedBlock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	testMD := newTestNSMetadata(t)
+	start := time.Now().Truncate(time.Hour)
+	blk, err := NewBlock(start, testMD, BlockOptions{}, testOpts)
+	require.NoError(t, err)
+
+	b, ok := blk.(*block)
+	require.True(t, ok)
+	require.NoError(t, b.Seal())
+
+	exec := search.NewMockExecutor(ctrl)
+	b.newExecutorFn = func() (search.Executor, error) {
+		return exec, nil
+	}
+
+	dIter := doc.NewMockIterator(ctrl)
+	gomock.InOrder(
+		exec.EXPECT().ExecuteAfter(gomock.Any(), gomock.Nil()).Return(dIter, nil),
+		dIter.EXPECT().Next().Return(true),
+		dIter.EXPECT().Current().Return(testDoc3()),
+		dIter.EXPECT().Next().Return(false),
+		dIter.EXPECT().Err().Return(nil),
+		dIter.EXPECT().Close().Return(nil),
+		exec.EXPECT().Close().Return(nil),
+	)
+	page := NewPageResults(QueryCursor{}, 1)
+
+	ctx := context.NewContext()
+
+	err = b.QueryPage(ctx, resource.NewCancellableLifetime(), defaultQuery,
+		page, emptyLogFields)
+	require.NoError(t, err)
+
+	// Another block fills the page past the documents of the block.
+	page.AddDocuments([]doc.Document{testDoc1()})
+
+	docs := page.Documents()
+	require.Equal(t, 1, len(docs))
+	require.Equal(t, testDoc3().ID, docs[0].ID)
+
+	cursor, err := page.NextPageToken().QueryCursor()
+	require.NoError(t, err)
+	require.Equal(t, testDoc3().ID, cursor.AfterID)
+	require.Equal(t, []xtime.UnixNano{xtime.ToUnixNano(start)},
+		cursor.FinishedBlocks)
+
+	// NB(r): Make sure to call finalizers blockingly (to finish
+	// the expected close calls)
+	ctx.BlockingClose()
+}
+
 func TestBlockMockQueryMergeResultsMapLimit(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -1116,6 +1325,56 @@ func TestBlockNeedsMutableSegmentsEvictedMutableSegments(t *testing.T) {
 	require.True(t, b.NeedsMutableSegmentsEvicted())
 }
 
+func TestBlockPersistedSegments(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	testMD := newTestNSMetadata(t)
+	start := time.Now().Truncate(time.Hour)
+	blk, err := NewBlock(start, testMD, BlockOptions{}, testOpts)
+	require.NoError(t, err)
+
+	// Must be sealed.
+	_, err = blk.PersistedSegments()
+	require.Error(t, err)
+	require.NoError(t, blk.Seal())
+
+	seg1 := segment.NewMockMutableSegment(ctrl)
+	seg1.EXPECT().Size().Return(int64(0)).AnyTimes()
+	seg2 := segment.NewMockImmutableSegment(ctrl)
+	require.NoError(t, blk.AddResults(
+		result.NewIndexBlock(start, []segment.Segment{seg1, seg2},
+			result.NewShardTimeRanges(start, start.Add(time.Hour), 1, 2, 3))))
+
+	segs, err := blk.PersistedSegments()
+	require.NoError(t, err)
+	require.Len(t, segs, 1)
+	require.Equal(t, seg2, segs[0].(*ReadThroughSegment).segment)
+
+	// Mutable segments must have been evicted.
+	seg3 := segment.NewMockMutableSegment(ctrl)
+	seg3.EXPECT().Size().Return(int64(1)).AnyTimes()
+	require.NoError(t, blk.AddResults(
+		result.NewIndexBlock(start, []segment.Segment{seg3},
+			result.NewShardTimeRanges(start, start.Add(time.Hour), 4))))
+	_, err = blk.PersistedSegments()
+	require.Error(t, err)
+}
+
+func TestBlockOptionsBlockSize(t *testing.T) {
+	testMD := newTestNSMetadata(t)
+	start := time.Now().Truncate(4 * time.Hour)
+	blk, err := NewBlock(start, testMD, BlockOptions{
+		BlockSize: 4 * time.Hour,
+	}, testOpts)
+	require.NoError(t, err)
+	require.Equal(t, start.Add(4*time.Hour), blk.EndTime())
+
+	// Results spanning the compacted block size are accepted.
+	require.NoError(t, blk.AddResults(result.NewIndexBlock(start, nil,
+		result.NewShardTimeRanges(start, start.Add(4*time.Hour), 1))))
+}
+
 func TestBlockEvictMutableSegmentsSimple(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -1881,6 +2140,198 @@ func TestBlockE2EInsertAggregate(t *testing.T) {
 	require.Equal(t, tracepoint.BlockAggregate, spans[2].OperationName)
 }
 
+func TestBlockE2EInsertCardinality(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	blockSize := time.Hour
+
+	testMD := newTestNSMetadata(t)
+	now := time.Now()
+	blockStart := now.Truncate(blockSize)
+
+	nowNotBlockStartAligned := now.
+		Truncate(blockSize).
+		Add(time.Minute)
+
+	blk, err := NewBlock(blockStart, testMD, BlockOptions{}, testOpts)
+	require.NoError(t, err)
+
+	batch := NewWriteBatch(WriteBatchOptions{
+		IndexBlockSize: blockSize,
+	})
+	for _, d := range []doc.Document{testDoc1(), testDoc2(), testDoc3()} {
+		h := NewMockOnIndexSeries(ctrl)
+		h.EXPECT().OnIndexFinalize(xtime.ToUnixNano(blockStart))
+		h.EXPECT().OnIndexSuccess(xtime.ToUnixNano(blockStart))
+		batch.Append(WriteBatchEntry{
+			Timestamp:     nowNotBlockStartAligned,
+			OnIndexSeries: h,
+		}, d)
+	}
+
+	res, err := blk.WriteBatch(batch)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), res.NumSuccess)
+
+	acc := NewCardinalityAccumulator()
+	require.NoError(t, blk.Cardinality(resource.NewCancellableLifetime(),
+		CardinalityOptions{}, acc))
+	require.Equal(t, CardinalityResults{
+		NumSeries: 3,
+		Fields: []FieldCardinality{
+			{Field: []byte("bar"), NumValues: 2, NumSeries: 3},
+			{Field: []byte("some"), NumValues: 2, NumSeries: 2},
+		},
+		Terms: []TermCardinality{
+			{Field: []byte("bar"), Term: []byte("baz"), NumSeries: 2},
+			{Field: []byte("bar"), Term: []byte("qux"), NumSeries: 1},
+			{Field: []byte("some"), Term: []byte("more"), NumSeries: 1},
+			{Field: []byte("some"), Term: []byte("other"), NumSeries: 1},
+		},
+	}, acc.Results(0))
+
+	acc = NewCardinalityAccumulator()
+	require.NoError(t, blk.Cardinality(resource.NewCancellableLifetime(),
+		CardinalityOptions{FieldFilter: AggregateFieldFilter{[]byte("some")}}, acc))
+	require.Equal(t, CardinalityResults{
+		NumSeries: 3,
+		Fields: []FieldCardinality{
+			{Field: []byte("some"), NumValues: 2, NumSeries: 2},
+		},
+		Terms: []TermCardinality{
+			{Field: []byte("some"), Term: []byte("more"), NumSeries: 1},
+		},
+	}, acc.Results(1))
+
+	acc = NewCardinalityAccumulator()
+	require.NoError(t, blk.Cardinality(resource.NewCancellableLifetime(),
+		CardinalityOptions{
+			Query: &Query{idx.NewFieldQuery([]byte("some"))},
+			Match: CardinalityMatch{Partial: []byte("ba")},
+		}, acc))
+	require.Equal(t, CardinalityResults{
+		NumSeries: 2,
+		Fields: []FieldCardinality{
+			{Field: []byte("bar"), NumValues: 1, NumSeries: 1},
+		},
+		Terms: []TermCardinality{
+			{Field: []byte("bar"), Term: []byte("baz"), NumSeries: 1},
+		},
+	}, acc.Results(0))
+
+	acc = NewCardinalityAccumulator()
+	require.NoError(t, blk.Cardinality(resource.NewCancellableLifetime(),
+		CardinalityOptions{
+			Match: CardinalityMatch{Partial: []byte("sme"), MaxEdits: 1, FieldNames: true},
+		}, acc))
+	require.Equal(t, CardinalityResults{
+		NumSeries: 3,
+		Fields: []FieldCardinality{
+			{Field: []byte("some"), NumValues: 2, NumSeries: 2},
+		},
+		Terms: []TermCardinality{
+			{Field: []byte("some"), Term: []byte("more"), NumSeries: 1},
+			{Field: []byte("some"), Term: []byte("other"), NumSeries: 1},
+		},
+	}, acc.Results(0))
+
+	require.Error(t, blk.Cardinality(resource.NewCancellableLifetime(),
+		CardinalityOptions{Match: CardinalityMatch{Partial: []byte("sme"), MaxEdits: 3}},
+		NewCardinalityAccumulator()))
+
+	require.NoError(t, blk.Close())
+	require.Error(t, blk.Cardinality(resource.NewCancellableLifetime(),
+		CardinalityOptions{}, NewCardinalityAccumulator()))
+}
+
+func TestBlockE2EInsertMatchQuery(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	blockSize := time.Hour
+
+	testMD := newTestNSMetadata(t)
+	now := time.Now()
+	blockStart := now.Truncate(blockSize)
+
+	nowNotBlockStartAligned := now.
+		Truncate(blockSize).
+		Add(time.Minute)
+
+	testOpts := testOpts.SetSegmentBuilderOptions(
+		testOpts.SegmentBuilderOptions().SetFieldAnalyzers(NewFieldAnalyzers(
+			namespace.NewIndexOptions().SetTokenizedFields([]string{"name"}))))
+	blk, err := NewBlock(blockStart, testMD, BlockOptions{}, testOpts)
+	require.NoError(t, err)
+
+	batch := NewWriteBatch(WriteBatchOptions{
+		IndexBlockSize: blockSize,
+	})
+	for _, d := range []doc.Document{
+		{ID: []byte("foo"), Fields: []doc.Field{
+			{Name: []byte("name"), Value: []byte("http_requests_total")},
+		}},
+		{ID: []byte("bar"), Fields: []doc.Field{
+			{Name: []byte("name"), Value: []byte("http_errors_total")},
+		}},
+	} {
+		h := NewMockOnIndexSeries(ctrl)
+		h.EXPECT().OnIndexFinalize(xtime.ToUnixNano(blockStart))
+		h.EXPECT().OnIndexSuccess(xtime.ToUnixNano(blockStart))
+		batch.Append(WriteBatchEntry{
+			Timestamp:     nowNotBlockStartAligned,
+			OnIndexSeries: h,
+		}, d)
+	}
+
+	res, err := blk.WriteBatch(batch)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), res.NumSuccess)
+
+	for _, test := range []struct {
+		token    string
+		expected []string
+	}{
+		{token: "http", expected: []string{"bar", "foo"}},
+		{token: "requests", expected: []string{"foo"}},
+		{token: "http_requests_total", expected: []string{}},
+	} {
+		results := NewQueryResults(nil, QueryResultsOptions{}, testOpts)
+		q := idx.NewMatchQuery([]byte("name"), []byte(test.token))
+		exhaustive, err := blk.Query(context.NewContext(),
+			resource.NewCancellableLifetime(), Query{q}, QueryOptions{},
+			results, emptyLogFields)
+		require.NoError(t, err)
+		require.True(t, exhaustive)
+
+		ids := []string{}
+		for _, entry := range results.Map().Iter() {
+			ids = append(ids, entry.Key().String())
+		}
+		sort.Strings(ids)
+		require.Equal(t, test.expected, ids)
+	}
+
+	// Exact matches on the tokenized field keep working.
+	results := NewQueryResults(nil, QueryResultsOptions{}, testOpts)
+	q := idx.NewTermQuery([]byte("name"), []byte("http_requests_total"))
+	_, err = blk.Query(context.NewContext(), resource.NewCancellableLifetime(),
+		Query{q}, QueryOptions{}, results, emptyLogFields)
+	require.NoError(t, err)
+	require.Equal(t, 1, results.Size())
+
+	// The tokens are not reported as tags.
+	acc := NewCardinalityAccumulator()
+	require.NoError(t, blk.Cardinality(resource.NewCancellableLifetime(),
+		CardinalityOptions{}, acc))
+	require.Equal(t, []FieldCardinality{
+		{Field: []byte("name"), NumValues: 2, NumSeries: 2},
+	}, acc.Results(0).Fields)
+
+	require.NoError(t, blk.Close())
+}
+
 func assertAggregateResultsMapEquals(t *testing.T, expected map[string][]string, observed AggregateResults) {
 	aggResultsMap := observed.Map()
 	// ensure `expected` contained in `observed`
@@ -1983,3 +2434,91 @@ func testDoc3() doc.Document {
 		},
 	}
 }
+
+func TestBlockPersistPostingsListCache(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dir, err := ioutil.TempDir("", "testdir")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	cache, stopReporting, err := NewPostingsListCache(10, testPostingListCacheOptions)
+	require.NoError(t, err)
+	defer stopReporting()
+
+	var (
+		testMD = newTestNSMetadata(t)
+		start  = time.Now().Truncate(time.Hour)
+		opts   = testOpts.
+			SetPostingsListCache(cache).
+			SetReadThroughSegmentOptions(defaultReadThroughSegmentOptions)
+		fsOpts    = fs.NewOptions().SetFilePathPrefix(dir)
+		fileSetID = fs.FileSetFileIdentifier{
+			FileSetContentType: persist.FileSetIndexContentType,
+			Namespace:          testMD.ID(),
+			BlockStart:         start,
+			VolumeIndex:        1,
+		}
+		field, term = []byte("city"), []byte("nyc")
+		pl          = roaring.NewPostingsList()
+	)
+	require.NoError(t, pl.Insert(3))
+
+	addFileSetSegment := func(
+		seg *fst.MockSegment,
+		cachedPostings []fs.IndexPostingsCacheEntry,
+	) (Block, index.Reader) {
+		blk, err := NewBlock(start, testMD, BlockOptions{}, opts)
+		require.NoError(t, err)
+		require.NoError(t, blk.AddResults(result.NewIndexBlock(start,
+			[]segment.Segment{fs.NewIndexFileSetSegment(seg, fileSetID, 0, cachedPostings)},
+			result.NewShardTimeRanges(start, start.Add(time.Hour), 1, 2, 3))))
+
+		b, ok := blk.(*block)
+		require.True(t, ok)
+		require.Equal(t, 1, len(b.shardRangesSegments))
+		reader, err := b.shardRangesSegments[0].segments[0].Reader()
+		require.NoError(t, err)
+		return blk, reader
+	}
+
+	// Resolve a postings list of a segment read from an index fileset.
+	seg := fst.NewMockSegment(ctrl)
+	segReader := index.NewMockReader(ctrl)
+	seg.EXPECT().Reader().Return(segReader, nil)
+	segReader.EXPECT().MatchTerm(field, term).Return(pl, nil)
+	seg.EXPECT().Close().Return(nil)
+
+	blk, reader := addFileSetSegment(seg, nil)
+	resolved, err := reader.MatchTerm(field, term)
+	require.NoError(t, err)
+	require.True(t, pl.Equal(resolved))
+
+	require.NoError(t, blk.PersistPostingsListCache(fsOpts))
+	require.NoError(t, blk.Close())
+
+	entries, err := fs.ReadIndexPostingsCache(fsOpts, fileSetID)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(entries))
+	require.Equal(t, 0, entries[0].SegmentIndex)
+	require.Equal(t, fs.IndexPostingsCacheTermQuery, entries[0].QueryType)
+	require.Equal(t, field, entries[0].Field)
+	require.Equal(t, term, entries[0].Pattern)
+	require.True(t, pl.Equal(entries[0].PostingsList))
+
+	// The persisted postings list is served from the cache once the segment
+	// is read again, i.e. the reader does not expect any match calls.
+	seg = fst.NewMockSegment(ctrl)
+	seg.EXPECT().Reader().Return(index.NewMockReader(ctrl), nil)
+	seg.EXPECT().Close().Return(nil)
+
+	blk, reader = addFileSetSegment(seg, entries)
+	resolved, err = reader.MatchTerm(field, term)
+	require.NoError(t, err)
+	require.True(t, pl.Equal(resolved))
+	require.NoError(t, blk.Close())
+
+	// Closed blocks cannot be persisted.
+	require.Error(t, blk.PersistPostingsListCache(fsOpts))
+}
@@ -0,0 +1,353 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package index
+
+import (
+	"bytes"
+	"errors"
+	"sort"
+	"time"
+
+	m3ninxindex "github.com/m3db/m3/src/m3ninx/index"
+	"github.com/m3db/m3/src/m3ninx/index/segment"
+	"github.com/m3db/m3/src/m3ninx/postings"
+)
+
+var errCardinalityMatchMaxEdits = errors.New("cardinality match max edits out of range")
+
+// CardinalityOptions enables users to specify constraints on
+// cardinality queries.
+type CardinalityOptions struct {
+	StartInclusive time.Time
+	EndExclusive   time.Time
+
+	// FieldFilter restricts the statistics to the given fields, all
+	// fields are included when empty.
+	FieldFilter AggregateFieldFilter
+
+	// Limit is the number of top fields and top terms returned, all
+	// fields and terms are returned when zero.
+	Limit int
+
+	// Query restricts the statistics to the series matching the query, all
+	// series are included when nil.
+	Query *Query
+
+	// Match restricts the statistics to the field names or the terms
+	// completing a partial string.
+	Match CardinalityMatch
+}
+
+// CardinalityMatch matches the field names or terms completing a partial
+// string, either by prefix or within a number of edits of a prefix.
+type CardinalityMatch struct {
+	// Partial is the partial field name or term, nothing is filtered
+	// when empty.
+	Partial []byte
+
+	// MaxEdits is the number of byte insertions, deletions or substitutions
+	// allowed between Partial and a prefix of a matching field name or term,
+	// only prefixes of Partial match when zero.
+	MaxEdits int
+
+	// FieldNames applies the match to field names instead of terms, even
+	// with an empty Partial field names are then ranked by series.
+	FieldNames bool
+}
+
+// Validate validates the match.
+func (m CardinalityMatch) Validate() error {
+	if m.MaxEdits < 0 || m.MaxEdits > m3ninxindex.MaxFuzzyEdits {
+		return errCardinalityMatchMaxEdits
+	}
+	return nil
+}
+
+// termsMatcher returns the matcher of the partial string, or nil if
+// nothing is filtered.
+func (m CardinalityMatch) termsMatcher() (m3ninxindex.TermsMatcher, error) {
+	if len(m.Partial) == 0 {
+		return nil, nil
+	}
+	return m3ninxindex.NewFuzzyPrefixTermsMatcher(m.Partial, m.MaxEdits)
+}
+
+// CardinalityResults is the result of a cardinality query.
+type CardinalityResults struct {
+	// NumSeries is the number of indexed series.
+	NumSeries int64
+
+	// Fields are the fields with the most distinct values, in
+	// descending order of distinct values.
+	Fields []FieldCardinality
+
+	// Terms are the label pairs with the largest postings lists, in
+	// descending order of series.
+	Terms []TermCardinality
+}
+
+// FieldCardinality is the cardinality of a single field.
+type FieldCardinality struct {
+	Field     []byte
+	NumValues int64
+	NumSeries int64
+}
+
+// TermCardinality is the cardinality of a single label pair.
+type TermCardinality struct {
+	Field     []byte
+	Term      []byte
+	NumSeries int64
+}
+
+// CardinalityAccumulator accumulates the number of series per label pair
+// from the postings lists of index segments.
+//
+// Within a block a series is indexed by a single segment so the postings
+// list lengths of the block's segments are summed. Across blocks the same
+// series is indexed by every block it was written to, so the maximum
+// number of series seen in any single block is kept, which makes the
+// results a lower bound when series churn between blocks.
+type CardinalityAccumulator struct {
+	numSeries int64
+	fields    map[string]map[string]int64
+}
+
+// NewCardinalityAccumulator returns a new cardinality accumulator.
+func NewCardinalityAccumulator() *CardinalityAccumulator {
+	return &CardinalityAccumulator{
+		fields: make(map[string]map[string]int64),
+	}
+}
+
+func (a *CardinalityAccumulator) addSeries(numSeries int64) {
+	a.numSeries += numSeries
+}
+
+func (a *CardinalityAccumulator) addTerm(field, term []byte, numSeries int64) {
+	terms, ok := a.fields[string(field)]
+	if !ok {
+		terms = make(map[string]int64)
+		a.fields[string(field)] = terms
+	}
+	terms[string(term)] += numSeries
+}
+
+// Merge merges the statistics of another block into the accumulator.
+func (a *CardinalityAccumulator) Merge(other *CardinalityAccumulator) {
+	if other.numSeries > a.numSeries {
+		a.numSeries = other.numSeries
+	}
+	for field, otherTerms := range other.fields {
+		terms, ok := a.fields[field]
+		if !ok {
+			terms = make(map[string]int64, len(otherTerms))
+			a.fields[field] = terms
+		}
+		for term, numSeries := range otherTerms {
+			if numSeries > terms[term] {
+				terms[term] = numSeries
+			}
+		}
+	}
+}
+
+// Results returns the accumulated statistics limited to the top limit
+// fields and terms, or all fields and terms if limit is zero.
+func (a *CardinalityAccumulator) Results(limit int) CardinalityResults {
+	return a.MatchResults(CardinalityMatch{}, limit)
+}
+
+// MatchResults returns the accumulated statistics ranked as completions of
+// the match, see RankCardinalityResults.
+func (a *CardinalityAccumulator) MatchResults(
+	match CardinalityMatch,
+	limit int,
+) CardinalityResults {
+	var (
+		fields = make([]FieldCardinality, 0, len(a.fields))
+		terms  []TermCardinality
+	)
+	for field, fieldTerms := range a.fields {
+		fieldCardinality := FieldCardinality{
+			Field:     []byte(field),
+			NumValues: int64(len(fieldTerms)),
+		}
+		for term, numSeries := range fieldTerms {
+			fieldCardinality.NumSeries += numSeries
+			terms = append(terms, TermCardinality{
+				Field:     fieldCardinality.Field,
+				Term:      []byte(term),
+				NumSeries: numSeries,
+			})
+		}
+		fields = append(fields, fieldCardinality)
+	}
+
+	return RankCardinalityResults(CardinalityResults{
+		NumSeries: a.numSeries,
+		Fields:    fields,
+		Terms:     terms,
+	}, match, limit)
+}
+
+// RankCardinalityResults sorts the fields and terms of the results and
+// limits them to the top limit, or keeps all of them if limit is zero.
+//
+// Fields are ranked by distinct values and terms by series, unless they
+// are the target of the match: field names when matching field names and
+// terms otherwise. Those rank as completions of the partial string, the
+// ones starting with it before fuzzy matches and then by series.
+func RankCardinalityResults(
+	results CardinalityResults,
+	match CardinalityMatch,
+	limit int,
+) CardinalityResults {
+	if match.FieldNames {
+		sortFieldCompletions(results.Fields, match.Partial)
+	} else {
+		SortFieldCardinalities(results.Fields)
+	}
+	if !match.FieldNames && len(match.Partial) > 0 {
+		sortTermCompletions(results.Terms, match.Partial)
+	} else {
+		SortTermCardinalities(results.Terms)
+	}
+
+	if limit > 0 && len(results.Fields) > limit {
+		results.Fields = results.Fields[:limit]
+	}
+	if limit > 0 && len(results.Terms) > limit {
+		results.Terms = results.Terms[:limit]
+	}
+	return results
+}
+
+// SortFieldCardinalities sorts fields in descending order of distinct
+// values, breaking ties by field name.
+func SortFieldCardinalities(fields []FieldCardinality) {
+	sort.Slice(fields, func(i, j int) bool {
+		if fields[i].NumValues != fields[j].NumValues {
+			return fields[i].NumValues > fields[j].NumValues
+		}
+		return bytes.Compare(fields[i].Field, fields[j].Field) < 0
+	})
+}
+
+// SortTermCardinalities sorts terms in descending order of series,
+// breaking ties by field name and then term.
+func SortTermCardinalities(terms []TermCardinality) {
+	sort.Slice(terms, func(i, j int) bool {
+		if terms[i].NumSeries != terms[j].NumSeries {
+			return terms[i].NumSeries > terms[j].NumSeries
+		}
+		if c := bytes.Compare(terms[i].Field, terms[j].Field); c != 0 {
+			return c < 0
+		}
+		return bytes.Compare(terms[i].Term, terms[j].Term) < 0
+	})
+}
+
+func sortFieldCompletions(fields []FieldCardinality, partial []byte) {
+	sort.Slice(fields, func(i, j int) bool {
+		iPrefix := bytes.HasPrefix(fields[i].Field, partial)
+		if jPrefix := bytes.HasPrefix(fields[j].Field, partial); iPrefix != jPrefix {
+			return iPrefix
+		}
+		if fields[i].NumSeries != fields[j].NumSeries {
+			return fields[i].NumSeries > fields[j].NumSeries
+		}
+		return bytes.Compare(fields[i].Field, fields[j].Field) < 0
+	})
+}
+
+func sortTermCompletions(terms []TermCardinality, partial []byte) {
+	sort.Slice(terms, func(i, j int) bool {
+		iPrefix := bytes.HasPrefix(terms[i].Term, partial)
+		if jPrefix := bytes.HasPrefix(terms[j].Term, partial); iPrefix != jPrefix {
+			return iPrefix
+		}
+		if terms[i].NumSeries != terms[j].NumSeries {
+			return terms[i].NumSeries > terms[j].NumSeries
+		}
+		if c := bytes.Compare(terms[i].Field, terms[j].Field); c != 0 {
+			return c < 0
+		}
+		return bytes.Compare(terms[i].Term, terms[j].Term) < 0
+	})
+}
+
+// intersectionLen returns the number of IDs in both postings lists without
+// materializing the intersection.
+func intersectionLen(a, b postings.List) (int, error) {
+	if a.Len() > b.Len() {
+		a, b = b, a
+	}
+	var (
+		n    int
+		iter = a.Iterator()
+	)
+	for iter.Next() {
+		if b.Contains(iter.Current()) {
+			n++
+		}
+	}
+	if err := iter.Err(); err != nil {
+		iter.Close()
+		return 0, err
+	}
+	return n, iter.Close()
+}
+
+// newMatchingTermsIterator returns an iterator over the terms of the field
+// matched by the matcher, the terms dictionary is walked directly when the
+// segment supports it.
+func newMatchingTermsIterator(
+	iterable segment.TermsIterable,
+	field []byte,
+	matcher m3ninxindex.TermsMatcher,
+) (segment.TermsIterator, error) {
+	if matching, ok := iterable.(segment.MatchingTermsIterable); ok {
+		return matching.MatchingTerms(field, matcher)
+	}
+	iter, err := iterable.Terms(field)
+	if err != nil {
+		return nil, err
+	}
+	return &matchingTermsIterator{TermsIterator: iter, matcher: matcher}, nil
+}
+
+// matchingTermsIterator filters the terms of segments which do not support
+// walking only the matching terms.
+type matchingTermsIterator struct {
+	segment.TermsIterator
+	matcher m3ninxindex.TermsMatcher
+}
+
+func (i *matchingTermsIterator) Next() bool {
+	for i.TermsIterator.Next() {
+		term, _ := i.TermsIterator.Current()
+		if i.matcher.MatchTerm(term) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,110 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package index
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCardinalityAccumulatorMergeKeepsMaxAcrossBlocks(t *testing.T) {
+	first := NewCardinalityAccumulator()
+	first.addSeries(3)
+	first.addTerm([]byte("city"), []byte("nyc"), 2)
+	first.addTerm([]byte("city"), []byte("sf"), 1)
+
+	second := NewCardinalityAccumulator()
+	second.addSeries(2)
+	second.addTerm([]byte("city"), []byte("nyc"), 1)
+	second.addTerm([]byte("city"), []byte("nyc"), 2)
+	second.addTerm([]byte("host"), []byte("a"), 1)
+
+	acc := NewCardinalityAccumulator()
+	acc.Merge(first)
+	acc.Merge(second)
+
+	require.Equal(t, CardinalityResults{
+		NumSeries: 3,
+		Fields: []FieldCardinality{
+			{Field: []byte("city"), NumValues: 2, NumSeries: 4},
+			{Field: []byte("host"), NumValues: 1, NumSeries: 1},
+		},
+		Terms: []TermCardinality{
+			{Field: []byte("city"), Term: []byte("nyc"), NumSeries: 3},
+			{Field: []byte("city"), Term: []byte("sf"), NumSeries: 1},
+			{Field: []byte("host"), Term: []byte("a"), NumSeries: 1},
+		},
+	}, acc.Results(0))
+
+	results := acc.Results(1)
+	require.Len(t, results.Fields, 1)
+	require.Equal(t, "city", string(results.Fields[0].Field))
+	require.Len(t, results.Terms, 1)
+	require.Equal(t, "nyc", string(results.Terms[0].Term))
+}
+
+func TestCardinalityMatchValidate(t *testing.T) {
+	require.NoError(t, CardinalityMatch{}.Validate())
+	require.NoError(t, CardinalityMatch{Partial: []byte("ho"), MaxEdits: 2}.Validate())
+	require.Error(t, CardinalityMatch{Partial: []byte("ho"), MaxEdits: -1}.Validate())
+	require.Error(t, CardinalityMatch{Partial: []byte("ho"), MaxEdits: 3}.Validate())
+}
+
+func TestRankCardinalityResultsCompletions(t *testing.T) {
+	newResults := func() CardinalityResults {
+		return CardinalityResults{
+			NumSeries: 10,
+			Fields: []FieldCardinality{
+				{Field: []byte("city"), NumValues: 3, NumSeries: 4},
+				{Field: []byte("host"), NumValues: 2, NumSeries: 2},
+				{Field: []byte("hostname"), NumValues: 1, NumSeries: 1},
+				{Field: []byte("ghost"), NumValues: 1, NumSeries: 8},
+			},
+			Terms: []TermCardinality{
+				{Field: []byte("city"), Term: []byte("la"), NumSeries: 1},
+				{Field: []byte("city"), Term: []byte("nyc"), NumSeries: 2},
+				{Field: []byte("city"), Term: []byte("lax"), NumSeries: 1},
+				{Field: []byte("city"), Term: []byte("sf"), NumSeries: 3},
+			},
+		}
+	}
+
+	// Prefix matches of the field names first, then fuzzy ones by series.
+	results := RankCardinalityResults(newResults(),
+		CardinalityMatch{Partial: []byte("host"), MaxEdits: 1, FieldNames: true}, 3)
+	require.Equal(t, []FieldCardinality{
+		{Field: []byte("host"), NumValues: 2, NumSeries: 2},
+		{Field: []byte("hostname"), NumValues: 1, NumSeries: 1},
+		{Field: []byte("ghost"), NumValues: 1, NumSeries: 8},
+	}, results.Fields)
+	require.Equal(t, "sf", string(results.Terms[0].Term))
+
+	// Prefix matches of the terms first, then fuzzy ones by series.
+	results = RankCardinalityResults(newResults(),
+		CardinalityMatch{Partial: []byte("la"), MaxEdits: 1}, 3)
+	require.Equal(t, "city", string(results.Fields[0].Field))
+	require.Equal(t, []TermCardinality{
+		{Field: []byte("city"), Term: []byte("la"), NumSeries: 1},
+		{Field: []byte("city"), Term: []byte("lax"), NumSeries: 1},
+		{Field: []byte("city"), Term: []byte("sf"), NumSeries: 3},
+	}, results.Terms)
+}
@@ -0,0 +1,96 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package compaction
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	xtime "github.com/m3db/m3/src/x/time"
+)
+
+var (
+	errBlockSizePositive          = errors.New("block size must be positive")
+	errCompactedBlockSizeMultiple = errors.New("compacted block size must be a larger multiple of block size")
+)
+
+// NewBlockPlan returns the tasks which compact the given index blocks into
+// blocks of the compacted block size. Blocks are only compacted together
+// once the whole compacted block is past the latest block end, and a
+// compacted block is only planned when it merges at least two blocks.
+func NewBlockPlan(blockStarts []time.Time, opts BlockPlannerOptions) ([]BlockTask, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	byCompactedStart := make(map[xtime.UnixNano][]time.Time)
+	for _, blockStart := range blockStarts {
+		if !blockStart.Truncate(opts.BlockSize).Equal(blockStart) {
+			// Not a block of the block size, e.g. an already compacted block.
+			continue
+		}
+
+		compactedStart := blockStart.Truncate(opts.CompactedBlockSize)
+		compactedEnd := compactedStart.Add(opts.CompactedBlockSize)
+		if compactedStart.Before(opts.EarliestBlockStart) ||
+			compactedEnd.After(opts.LatestBlockEnd) {
+			continue
+		}
+
+		key := xtime.ToUnixNano(compactedStart)
+		byCompactedStart[key] = append(byCompactedStart[key], blockStart)
+	}
+
+	tasks := make([]BlockTask, 0, len(byCompactedStart))
+	for compactedStart, starts := range byCompactedStart {
+		if len(starts) < 2 {
+			// Nothing to gain from rewriting a single block.
+			continue
+		}
+
+		sort.Slice(starts, func(i, j int) bool {
+			return starts[i].Before(starts[j])
+		})
+		tasks = append(tasks, BlockTask{
+			BlockStart:  compactedStart.ToTime(),
+			BlockStarts: starts,
+		})
+	}
+
+	// Compact the oldest blocks first.
+	sort.Slice(tasks, func(i, j int) bool {
+		return tasks[i].BlockStart.Before(tasks[j].BlockStart)
+	})
+	return tasks, nil
+}
+
+// Validate ensures the receiver BlockPlannerOptions specify valid values
+// for each of the knobs.
+func (o BlockPlannerOptions) Validate() error {
+	if o.BlockSize <= 0 {
+		return errBlockSizePositive
+	}
+	if o.CompactedBlockSize <= o.BlockSize || o.CompactedBlockSize%o.BlockSize != 0 {
+		return errCompactedBlockSizeMultiple
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package compaction
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewBlockPlan(t *testing.T) {
+	var (
+		start = time.Unix(0, 0).Add(100 * 24 * time.Hour)
+		opts  = BlockPlannerOptions{
+			BlockSize:          2 * time.Hour,
+			CompactedBlockSize: 24 * time.Hour,
+			EarliestBlockStart: start,
+			LatestBlockEnd:     start.Add(48 * time.Hour),
+		}
+		day = 24 * time.Hour
+	)
+
+	tasks, err := NewBlockPlan([]time.Time{
+		// Before the earliest block start.
+		start.Add(-4 * time.Hour),
+		start.Add(-2 * time.Hour),
+		// First day, out of order.
+		start.Add(4 * time.Hour),
+		start,
+		start.Add(22 * time.Hour),
+		// Second day with a single block.
+		start.Add(day + 2*time.Hour),
+		// Third day is not entirely before the latest block end.
+		start.Add(2 * day),
+		start.Add(2*day + 2*time.Hour),
+		// Not aligned to the block size.
+		start.Add(time.Hour),
+	}, opts)
+	require.NoError(t, err)
+	require.Equal(t, []BlockTask{
+		{
+			BlockStart: start,
+			BlockStarts: []time.Time{
+				start,
+				start.Add(4 * time.Hour),
+				start.Add(22 * time.Hour),
+			},
+		},
+	}, tasks)
+}
+
+func TestNewBlockPlanOrderedByBlockStart(t *testing.T) {
+	var (
+		start = time.Unix(0, 0).Add(100 * 24 * time.Hour)
+		opts  = BlockPlannerOptions{
+			BlockSize:          time.Hour,
+			CompactedBlockSize: 2 * time.Hour,
+			LatestBlockEnd:     start.Add(6 * time.Hour),
+		}
+	)
+
+	tasks, err := NewBlockPlan([]time.Time{
+		start.Add(5 * time.Hour),
+		start.Add(4 * time.Hour),
+		start.Add(time.Hour),
+		start,
+	}, opts)
+	require.NoError(t, err)
+	require.Len(t, tasks, 2)
+	require.Equal(t, start, tasks[0].BlockStart)
+	require.Equal(t, start.Add(4*time.Hour), tasks[1].BlockStart)
+}
+
+func TestBlockPlannerOptionsValidate(t *testing.T) {
+	require.Error(t, BlockPlannerOptions{}.Validate())
+	require.Error(t, BlockPlannerOptions{
+		BlockSize:          2 * time.Hour,
+		CompactedBlockSize: 2 * time.Hour,
+	}.Validate())
+	require.Error(t, BlockPlannerOptions{
+		BlockSize:          2 * time.Hour,
+		CompactedBlockSize: 5 * time.Hour,
+	}.Validate())
+	require.NoError(t, BlockPlannerOptions{
+		BlockSize:          2 * time.Hour,
+		CompactedBlockSize: 24 * time.Hour,
+	}.Validate())
+
+	_, err := NewBlockPlan(nil, BlockPlannerOptions{})
+	require.Error(t, err)
+}
@@ -101,10 +101,12 @@ func NewCompactor(
 // converted into an FST segment, otherwise an intermediary mutable segment
 // (reused by the compactor between runs) is used to combine all the segments
 // together first before compacting into an FST segment.
+// Documents not contained by the filter, if one is provided, are removed.
 // Note: This is not thread safe and only a single compaction may happen at a
 // time.
 func (c *Compactor) Compact(
 	segs []segment.Segment,
+	filter segment.DocumentsFilter,
 	reporterOptions mmap.ReporterOptions,
 ) (segment.Segment, error) {
 	c.Lock()
@@ -115,6 +117,7 @@ func (c *Compactor) Compact(
 	}
 
 	c.builder.Reset(0)
+	c.builder.SetFilter(filter)
 	if err := c.builder.AddSegments(segs); err != nil {
 		return nil, err
 	}
@@ -122,10 +125,13 @@ func (c *Compactor) Compact(
 	return c.compactFromBuilderWithLock(c.builder, reporterOptions)
 }
 
-// CompactUsingBuilder compacts segments together using a provided segment builder.
+// CompactUsingBuilder compacts segments together using a provided segment builder,
+// documents from the segments not contained by the filter, if one is provided,
+// are removed.
 func (c *Compactor) CompactUsingBuilder(
 	builder segment.DocumentsBuilder,
 	segs []segment.Segment,
+	filter segment.DocumentsFilter,
 	reporterOptions mmap.ReporterOptions,
 ) (segment.Segment, error) {
 	// NB(r): Ensure only single compaction happens at a time since the buffers are
@@ -199,7 +205,11 @@ func (c *Compactor) CompactUsingBuilder(
 		}
 
 		for iter.Next() {
-			batch = append(batch, iter.Current())
+			d := iter.Current()
+			if filter != nil && !filter.Contains(d) {
+				continue
+			}
+			batch = append(batch, d)
 			if len(batch) < c.docsMaxBatch {
 				continue
 			}
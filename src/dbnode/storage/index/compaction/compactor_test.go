@@ -21,6 +21,7 @@
 package compaction
 
 import (
+	"bytes"
 	"fmt"
 	"testing"
 
@@ -96,7 +97,7 @@ func TestCompactorSingleMutableSegment(t *testing.T) {
 
 	compacted, err := compactor.Compact([]segment.Segment{
 		mustSeal(t, seg),
-	}, mmap.ReporterOptions{})
+	}, nil, mmap.ReporterOptions{})
 	require.NoError(t, err)
 
 	assertContents(t, compacted, testDocuments)
@@ -122,7 +123,7 @@ func TestCompactorSingleMutableSegmentWithMmapDocsData(t *testing.T) {
 
 	compacted, err := compactor.Compact([]segment.Segment{
 		mustSeal(t, seg),
-	}, mmap.ReporterOptions{})
+	}, nil, mmap.ReporterOptions{})
 	require.NoError(t, err)
 
 	assertContents(t, compacted, testDocuments)
@@ -150,7 +151,7 @@ func TestCompactorManySegments(t *testing.T) {
 	compacted, err := compactor.Compact([]segment.Segment{
 		mustSeal(t, seg1),
 		mustSeal(t, seg2),
-	}, mmap.ReporterOptions{})
+	}, nil, mmap.ReporterOptions{})
 	require.NoError(t, err)
 
 	assertContents(t, compacted, testDocuments)
@@ -181,7 +182,7 @@ func TestCompactorCompactDuplicateIDsNoError(t *testing.T) {
 	compacted, err := compactor.Compact([]segment.Segment{
 		mustSeal(t, seg1),
 		mustSeal(t, seg2),
-	}, mmap.ReporterOptions{})
+	}, nil, mmap.ReporterOptions{})
 	require.NoError(t, err)
 
 	assertContents(t, compacted, testDocuments)
@@ -189,6 +190,37 @@ func TestCompactorCompactDuplicateIDsNoError(t *testing.T) {
 	require.NoError(t, compactor.Close())
 }
 
+func TestCompactorCompactWithFilter(t *testing.T) {
+	seg1, err := mem.NewSegment(0, testMemSegmentOptions)
+	require.NoError(t, err)
+
+	_, err = seg1.Insert(testDocuments[0])
+	require.NoError(t, err)
+
+	seg2, err := mem.NewSegment(0, testMemSegmentOptions)
+	require.NoError(t, err)
+
+	_, err = seg2.Insert(testDocuments[1])
+	require.NoError(t, err)
+
+	compactor, err := NewCompactor(testDocsPool, testDocsMaxBatch,
+		testBuilderSegmentOptions, testFSTSegmentOptions, CompactorOptions{})
+	require.NoError(t, err)
+
+	filter := segment.DocumentsFilterFn(func(d doc.Document) bool {
+		return !bytes.Equal(d.ID, testDocuments[0].ID)
+	})
+	compacted, err := compactor.Compact([]segment.Segment{
+		mustSeal(t, seg1),
+		mustSeal(t, seg2),
+	}, filter, mmap.ReporterOptions{})
+	require.NoError(t, err)
+
+	assertContents(t, compacted, testDocuments[1:])
+
+	require.NoError(t, compactor.Close())
+}
+
 func assertContents(t *testing.T, seg segment.Segment, docs []doc.Document) {
 	// Ensure has contents
 	require.Equal(t, int64(len(docs)), seg.Size())
@@ -91,3 +91,28 @@ type Level struct {
 	MinSizeInclusive int64
 	MaxSizeExclusive int64
 }
+
+// BlockTask identifies adjacent index blocks to compact into a single block
+// of a coarser block size.
+type BlockTask struct {
+	// BlockStart is the start of the compacted block.
+	BlockStart time.Time
+	// BlockStarts are the starts of the blocks to compact in ascending order.
+	BlockStarts []time.Time
+}
+
+// BlockPlannerOptions are the knobs to tweak the planning of index block
+// compactions.
+type BlockPlannerOptions struct {
+	// BlockSize is the size of the blocks to compact.
+	BlockSize time.Duration
+	// CompactedBlockSize is the size of the compacted blocks, it must be a
+	// larger multiple of the block size.
+	CompactedBlockSize time.Duration
+	// EarliestBlockStart is the earliest start of a compacted block, so that
+	// compacted blocks do not outlive the retention of the blocks they hold.
+	EarliestBlockStart time.Time
+	// LatestBlockEnd is the latest end of a compacted block, so that only
+	// blocks older than the compaction age are compacted.
+	LatestBlockEnd time.Time
+}
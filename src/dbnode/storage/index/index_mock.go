@@ -29,6 +29,7 @@ import (
 	"time"
 
 	"github.com/m3db/m3/src/dbnode/clock"
+	"github.com/m3db/m3/src/dbnode/persist/fs"
 	"github.com/m3db/m3/src/dbnode/storage/bootstrap/result"
 	"github.com/m3db/m3/src/dbnode/storage/index/compaction"
 	"github.com/m3db/m3/src/m3ninx/doc"
@@ -699,6 +700,20 @@ func (mr *MockBlockMockRecorder) Query(ctx, cancellable, query, opts, results, l
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Query", reflect.TypeOf((*MockBlock)(nil).Query), ctx, cancellable, query, opts, results, logFields)
 }
 
+// QueryPage mocks base method
+func (m *MockBlock) QueryPage(ctx context.Context, cancellable *resource.CancellableLifetime, query Query, page *PageResults, logFields []log.Field) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueryPage", ctx, cancellable, query, page, logFields)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// QueryPage indicates an expected call of QueryPage
+func (mr *MockBlockMockRecorder) QueryPage(ctx, cancellable, query, page, logFields interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryPage", reflect.TypeOf((*MockBlock)(nil).QueryPage), ctx, cancellable, query, page, logFields)
+}
+
 // Aggregate mocks base method
 func (m *MockBlock) Aggregate(ctx context.Context, cancellable *resource.CancellableLifetime, opts QueryOptions, results AggregateResults, logFields []log.Field) (bool, error) {
 	m.ctrl.T.Helper()
@@ -714,6 +729,20 @@ func (mr *MockBlockMockRecorder) Aggregate(ctx, cancellable, opts, results, logF
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Aggregate", reflect.TypeOf((*MockBlock)(nil).Aggregate), ctx, cancellable, opts, results, logFields)
 }
 
+// Cardinality mocks base method
+func (m *MockBlock) Cardinality(cancellable *resource.CancellableLifetime, opts CardinalityOptions, acc *CardinalityAccumulator) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Cardinality", cancellable, opts, acc)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Cardinality indicates an expected call of Cardinality
+func (mr *MockBlockMockRecorder) Cardinality(cancellable, opts, acc interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cardinality", reflect.TypeOf((*MockBlock)(nil).Cardinality), cancellable, opts, acc)
+}
+
 // AddResults mocks base method
 func (m *MockBlock) AddResults(results result.IndexBlock) error {
 	m.ctrl.T.Helper()
@@ -813,6 +842,35 @@ func (mr *MockBlockMockRecorder) EvictMutableSegments() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EvictMutableSegments", reflect.TypeOf((*MockBlock)(nil).EvictMutableSegments))
 }
 
+// PersistedSegments mocks base method
+func (m *MockBlock) PersistedSegments() ([]segment.Segment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PersistedSegments")
+	ret0, _ := ret[0].([]segment.Segment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PersistedSegments indicates an expected call of PersistedSegments
+func (mr *MockBlockMockRecorder) PersistedSegments() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PersistedSegments", reflect.TypeOf((*MockBlock)(nil).PersistedSegments))
+}
+
+// PersistPostingsListCache mocks base method
+func (m *MockBlock) PersistPostingsListCache(fsOpts fs.Options) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PersistPostingsListCache", fsOpts)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PersistPostingsListCache indicates an expected call of PersistPostingsListCache
+func (mr *MockBlockMockRecorder) PersistPostingsListCache(fsOpts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PersistPostingsListCache", reflect.TypeOf((*MockBlock)(nil).PersistPostingsListCache), fsOpts)
+}
+
 // Close mocks base method
 func (m *MockBlock) Close() error {
 	m.ctrl.T.Helper()
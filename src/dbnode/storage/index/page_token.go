@@ -0,0 +1,302 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package index
+
+import (
+	"bytes"
+	"container/heap"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/m3db/m3/src/m3ninx/doc"
+	xtime "github.com/m3db/m3/src/x/time"
+)
+
+const (
+	// pageTokenVersion is the version of page tokens which carry the blocks
+	// that were finished by the previous pages.
+	pageTokenVersion = 2
+	// pageTokenVersionNoBlocks is the version of page tokens which only carry
+	// the last ID of the previous page.
+	pageTokenVersionNoBlocks = 1
+)
+
+var (
+	errPageTokenBadVarint  = errors.New("page token has bad varint")
+	errPageTokenBadLength  = errors.New("page token has bad length")
+	errPageTokenTrailing   = errors.New("page token has trailing bytes")
+	errPaginatedQueryLimit = errors.New("paginated queries require a limit")
+)
+
+// PageToken is an opaque token that resumes a paginated query from where
+// the previous page ended.
+type PageToken []byte
+
+// FirstPageToken returns the token that requests the first page of a
+// paginated query.
+func FirstPageToken() PageToken {
+	return QueryCursor{}.PageToken()
+}
+
+// QueryCursor is the position of a paginated query. The pages of a query hold
+// the matching series in increasing order of their IDs, so the cursor is the
+// last ID of the previous page. Every node resumes a query from the same
+// cursor, which lets the pages of the replicas of a shard be merged.
+type QueryCursor struct {
+	// AfterID is the last series ID of the previous page, the zero value
+	// requests the first page.
+	AfterID []byte
+	// FinishedBlocks are the starts of the blocks whose matching series all
+	// sort before or at AfterID, the following pages skip them.
+	FinishedBlocks []xtime.UnixNano
+}
+
+// PageToken returns the page token that resumes a query from the cursor.
+func (c QueryCursor) PageToken() PageToken {
+	buf := make([]byte, (3+len(c.FinishedBlocks))*binary.MaxVarintLen64+len(c.AfterID))
+	n := binary.PutUvarint(buf, pageTokenVersion)
+	n += binary.PutUvarint(buf[n:], uint64(len(c.AfterID)))
+	n += copy(buf[n:], c.AfterID)
+	n += binary.PutUvarint(buf[n:], uint64(len(c.FinishedBlocks)))
+	for _, blockStart := range c.FinishedBlocks {
+		n += binary.PutVarint(buf[n:], int64(blockStart))
+	}
+	return PageToken(buf[:n])
+}
+
+// Includes returns whether a series ID belongs to the pages after the cursor.
+func (c QueryCursor) Includes(id []byte) bool {
+	return bytes.Compare(id, c.AfterID) > 0
+}
+
+// BlockFinished returns whether the block has no matching series after the
+// cursor.
+func (c QueryCursor) BlockFinished(blockStart xtime.UnixNano) bool {
+	for _, finished := range c.FinishedBlocks {
+		if finished == blockStart {
+			return true
+		}
+	}
+	return false
+}
+
+// QueryCursor decodes the cursor the page token resumes a query from.
+func (t PageToken) QueryCursor() (QueryCursor, error) {
+	buf := []byte(t)
+	version, n := binary.Uvarint(buf)
+	if n <= 0 {
+		return QueryCursor{}, errPageTokenBadVarint
+	}
+	if version != pageTokenVersion && version != pageTokenVersionNoBlocks {
+		return QueryCursor{}, fmt.Errorf("page token has unknown version: %d", version)
+	}
+	buf = buf[n:]
+
+	length, n := binary.Uvarint(buf)
+	if n <= 0 {
+		return QueryCursor{}, errPageTokenBadVarint
+	}
+	buf = buf[n:]
+	if uint64(len(buf)) < length {
+		return QueryCursor{}, errPageTokenBadLength
+	}
+
+	var cursor QueryCursor
+	if length > 0 {
+		cursor.AfterID = append([]byte(nil), buf[:length]...)
+	}
+	buf = buf[length:]
+
+	if version == pageTokenVersion {
+		numBlocks, n := binary.Uvarint(buf)
+		if n <= 0 {
+			return QueryCursor{}, errPageTokenBadVarint
+		}
+		buf = buf[n:]
+		// NB: every block start takes at least a byte so the number of blocks
+		// is bounded by the token before allocating.
+		if uint64(len(buf)) < numBlocks {
+			return QueryCursor{}, errPageTokenBadLength
+		}
+		if numBlocks > 0 {
+			cursor.FinishedBlocks = make([]xtime.UnixNano, 0, numBlocks)
+		}
+		for i := uint64(0); i < numBlocks; i++ {
+			blockStart, n := binary.Varint(buf)
+			if n <= 0 {
+				return QueryCursor{}, errPageTokenBadVarint
+			}
+			buf = buf[n:]
+			cursor.FinishedBlocks = append(cursor.FinishedBlocks,
+				xtime.UnixNano(blockStart))
+		}
+	}
+
+	if len(buf) != 0 {
+		return QueryCursor{}, errPageTokenTrailing
+	}
+	return cursor, nil
+}
+
+// PageCursor returns the cursor the paginated query resumes from.
+func (o QueryOptions) PageCursor() (QueryCursor, error) {
+	if o.Limit <= 0 {
+		return QueryCursor{}, errPaginatedQueryLimit
+	}
+	return o.PageToken.QueryCursor()
+}
+
+// PageResults collects the documents of a single page of a paginated query, it
+// keeps the documents with the smallest IDs after the cursor of the page. It is
+// not safe for concurrent use.
+type PageResults struct {
+	cursor QueryCursor
+	limit  int
+	docs   pageDocsHeap
+	ids    map[string]struct{}
+	more   bool
+	blocks []pageBlock
+}
+
+// pageBlock is a block which offered every one of its matching documents
+// after the cursor to the page.
+type pageBlock struct {
+	start  xtime.UnixNano
+	lastID []byte
+}
+
+// NewPageResults returns a new page of at most limit documents after the cursor.
+func NewPageResults(cursor QueryCursor, limit int) *PageResults {
+	return &PageResults{
+		cursor: cursor,
+		limit:  limit,
+		ids:    make(map[string]struct{}),
+	}
+}
+
+// AddDocuments adds the documents after the cursor to the page, replacing the
+// documents with the largest IDs once the page is full. Documents which are
+// already in the page are skipped.
+func (r *PageResults) AddDocuments(batch []doc.Document) {
+	for _, d := range batch {
+		r.addDocument(d)
+	}
+}
+
+func (r *PageResults) addDocument(d doc.Document) {
+	if !r.cursor.Includes(d.ID) {
+		return
+	}
+	if _, ok := r.ids[string(d.ID)]; ok {
+		return
+	}
+
+	if len(r.docs) >= r.limit {
+		// NB: the page is full so there is at least one more page.
+		r.more = true
+		if len(r.docs) == 0 || bytes.Compare(d.ID, r.docs[0].ID) > 0 {
+			return
+		}
+
+		evicted := heap.Pop(&r.docs).(doc.Document)
+		delete(r.ids, string(evicted.ID))
+	}
+
+	heap.Push(&r.docs, d)
+	r.ids[string(d.ID)] = struct{}{}
+}
+
+// Rejects returns whether a document can no longer be added to the page since
+// the page is full and the document sorts after every document of the page.
+// Blocks offer their documents in increasing order of their IDs, so once a
+// document is rejected every following document of the block is as well.
+func (r *PageResults) Rejects(id []byte) bool {
+	if len(r.docs) == 0 || len(r.docs) < r.limit || bytes.Compare(id, r.docs[0].ID) <= 0 {
+		return false
+	}
+	// NB: the page is full so there is at least one more page.
+	r.more = true
+	return true
+}
+
+// FinishBlock records that the block offered every one of its matching
+// documents after the cursor to the page, the last of which has the given ID
+// or none if the ID is nil. The block is skipped by the following pages if
+// none of its documents are left for them.
+func (r *PageResults) FinishBlock(blockStart xtime.UnixNano, lastID []byte) {
+	r.blocks = append(r.blocks, pageBlock{
+		start:  blockStart,
+		lastID: append([]byte(nil), lastID...),
+	})
+}
+
+// Documents returns the documents of the page in increasing order of their IDs.
+func (r *PageResults) Documents() []doc.Document {
+	docs := make([]doc.Document, len(r.docs))
+	copy(docs, r.docs)
+	sort.Slice(docs, func(i, j int) bool {
+		return bytes.Compare(docs[i].ID, docs[j].ID) < 0
+	})
+	return docs
+}
+
+// NextPageToken returns the token to resume the query from after the page, it
+// is nil once the query has been exhausted.
+func (r *PageResults) NextPageToken() PageToken {
+	if !r.more || len(r.docs) == 0 {
+		return nil
+	}
+
+	next := QueryCursor{
+		AfterID:        r.docs[0].ID,
+		FinishedBlocks: append([]xtime.UnixNano(nil), r.cursor.FinishedBlocks...),
+	}
+	for _, block := range r.blocks {
+		// NB: documents of a block may have been evicted by documents of
+		// later blocks, the block is only finished if the page kept them.
+		if bytes.Compare(block.lastID, next.AfterID) <= 0 {
+			next.FinishedBlocks = append(next.FinishedBlocks, block.start)
+		}
+	}
+	return next.PageToken()
+}
+
+// pageDocsHeap is a max heap of documents ordered by their IDs.
+type pageDocsHeap []doc.Document
+
+func (h pageDocsHeap) Len() int           { return len(h) }
+func (h pageDocsHeap) Less(i, j int) bool { return bytes.Compare(h[i].ID, h[j].ID) > 0 }
+func (h pageDocsHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *pageDocsHeap) Push(x interface{}) {
+	*h = append(*h, x.(doc.Document))
+}
+
+func (h *pageDocsHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	d := old[n-1]
+	old[n-1] = doc.Document{}
+	*h = old[:n-1]
+	return d
+}
@@ -0,0 +1,173 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package index
+
+import (
+	"testing"
+
+	"github.com/m3db/m3/src/m3ninx/doc"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryCursorPageTokenRoundTrip(t *testing.T) {
+	cursor := QueryCursor{AfterID: []byte("foo")}
+
+	decoded, err := cursor.PageToken().QueryCursor()
+	require.NoError(t, err)
+	require.Equal(t, cursor.AfterID, decoded.AfterID)
+	require.Nil(t, decoded.FinishedBlocks)
+
+	cursor.FinishedBlocks = []xtime.UnixNano{0, 7200, -7200}
+	decoded, err = cursor.PageToken().QueryCursor()
+	require.NoError(t, err)
+	require.Equal(t, cursor, decoded)
+	require.True(t, decoded.BlockFinished(7200))
+	require.False(t, decoded.BlockFinished(14400))
+}
+
+func TestPageTokenWithoutBlocks(t *testing.T) {
+	// version 1 tokens only carry the last ID of the previous page.
+	decoded, err := PageToken{1, 3, 'f', 'o', 'o'}.QueryCursor()
+	require.NoError(t, err)
+	require.Equal(t, QueryCursor{AfterID: []byte("foo")}, decoded)
+}
+
+func TestFirstPageToken(t *testing.T) {
+	cursor, err := FirstPageToken().QueryCursor()
+	require.NoError(t, err)
+	require.Nil(t, cursor.AfterID)
+	require.True(t, cursor.Includes([]byte("a")))
+}
+
+func TestPageTokenInvalid(t *testing.T) {
+	token := QueryCursor{AfterID: []byte("foo")}.PageToken()
+
+	_, err := token[:len(token)-1].QueryCursor()
+	require.Error(t, err)
+
+	_, err = append(token, 0).QueryCursor()
+	require.Error(t, err)
+
+	_, err = PageToken{3, 0}.QueryCursor()
+	require.Error(t, err)
+
+	// the number of finished blocks exceeds the remaining bytes.
+	_, err = PageToken{2, 0, 5, 0}.QueryCursor()
+	require.Error(t, err)
+}
+
+func TestQueryCursorIncludes(t *testing.T) {
+	cursor := QueryCursor{AfterID: []byte("b")}
+	require.False(t, cursor.Includes([]byte("a")))
+	require.False(t, cursor.Includes([]byte("b")))
+	require.True(t, cursor.Includes([]byte("ba")))
+	require.True(t, cursor.Includes([]byte("c")))
+}
+
+func TestPageResultsKeepsSmallestIDs(t *testing.T) {
+	page := NewPageResults(QueryCursor{AfterID: []byte("b")}, 2)
+	page.AddDocuments([]doc.Document{
+		{ID: []byte("e")},
+		{ID: []byte("a")},
+		{ID: []byte("d")},
+		{ID: []byte("b")},
+	})
+	page.AddDocuments([]doc.Document{
+		{ID: []byte("d")},
+		{ID: []byte("c")},
+	})
+
+	var ids []string
+	for _, d := range page.Documents() {
+		ids = append(ids, string(d.ID))
+	}
+	require.Equal(t, []string{"c", "d"}, ids)
+
+	cursor, err := page.NextPageToken().QueryCursor()
+	require.NoError(t, err)
+	require.Equal(t, []byte("d"), cursor.AfterID)
+}
+
+func TestPageResultsExhausted(t *testing.T) {
+	page := NewPageResults(QueryCursor{AfterID: []byte("b")}, 2)
+	page.AddDocuments([]doc.Document{
+		{ID: []byte("c")},
+		{ID: []byte("a")},
+		{ID: []byte("c")},
+	})
+
+	require.Equal(t, 1, len(page.Documents()))
+	require.Nil(t, page.NextPageToken())
+}
+
+func TestPageResultsRejects(t *testing.T) {
+	page := NewPageResults(QueryCursor{}, 2)
+	require.False(t, page.Rejects([]byte("z")))
+
+	page.AddDocuments([]doc.Document{
+		{ID: []byte("b")},
+		{ID: []byte("d")},
+	})
+	require.False(t, page.Rejects([]byte("c")))
+	require.False(t, page.Rejects([]byte("d")))
+	require.True(t, page.Rejects([]byte("e")))
+
+	cursor, err := page.NextPageToken().QueryCursor()
+	require.NoError(t, err)
+	require.Equal(t, []byte("d"), cursor.AfterID)
+}
+
+func TestPageResultsFinishedBlocks(t *testing.T) {
+	cursor := QueryCursor{
+		AfterID:        []byte("a"),
+		FinishedBlocks: []xtime.UnixNano{1},
+	}
+	page := NewPageResults(cursor, 2)
+
+	page.AddDocuments([]doc.Document{{ID: []byte("b")}})
+	page.FinishBlock(2, []byte("b"))
+	page.FinishBlock(3, nil)
+	page.AddDocuments([]doc.Document{{ID: []byte("e")}})
+	page.FinishBlock(4, []byte("e"))
+
+	// the document of block 4 is evicted, so block 4 is not finished.
+	page.AddDocuments([]doc.Document{{ID: []byte("c")}})
+	page.FinishBlock(5, []byte("c"))
+
+	next, err := page.NextPageToken().QueryCursor()
+	require.NoError(t, err)
+	require.Equal(t, []byte("c"), next.AfterID)
+	require.Equal(t, []xtime.UnixNano{1, 2, 3, 5}, next.FinishedBlocks)
+}
+
+func TestQueryOptionsPageCursorRequiresLimit(t *testing.T) {
+	opts := QueryOptions{PageToken: FirstPageToken()}
+	require.True(t, opts.Paginated())
+
+	_, err := opts.PageCursor()
+	require.Error(t, err)
+
+	opts.Limit = 10
+	_, err = opts.PageCursor()
+	require.NoError(t, err)
+}
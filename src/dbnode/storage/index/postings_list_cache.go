@@ -178,6 +178,38 @@ func (q *PostingsListCache) put(
 	q.emitCachePutMetrics(patternType)
 }
 
+// PostingsListCacheEntry is a cached postings list and the query it was
+// resolved for.
+type PostingsListCacheEntry struct {
+	Field        string
+	Pattern      string
+	PatternType  PatternType
+	PostingsList postings.List
+}
+
+// SegmentEntries returns up to limit of the postings lists cached for the
+// specified segment, most recently used first. A limit of zero returns all
+// the postings lists cached for the segment.
+func (q *PostingsListCache) SegmentEntries(
+	segmentUUID uuid.UUID,
+	limit int,
+) []PostingsListCacheEntry {
+	q.Lock()
+	entries := q.lru.SegmentEntries(segmentUUID, limit)
+	q.Unlock()
+
+	results := make([]PostingsListCacheEntry, 0, len(entries))
+	for _, ent := range entries {
+		results = append(results, PostingsListCacheEntry{
+			Field:        ent.key.field,
+			Pattern:      ent.key.pattern,
+			PatternType:  ent.key.patternType,
+			PostingsList: ent.postingsList,
+		})
+	}
+	return results
+}
+
 // PurgeSegment removes all postings lists associated with the specified
 // segment from the cache.
 func (q *PostingsListCache) PurgeSegment(segmentUUID uuid.UUID) {
@@ -185,6 +185,27 @@ func (c *postingsListLRU) PurgeSegment(segmentUUID uuid.UUID) {
 	}
 }
 
+// SegmentEntries returns up to limit of the entries of the segment, most
+// recently used first. A limit of zero returns all the entries of the segment.
+func (c *postingsListLRU) SegmentEntries(segmentUUID uuid.UUID, limit int) []entry {
+	uuidEntries, ok := c.items[segmentUUID.Array()]
+	if !ok {
+		return nil
+	}
+
+	n := len(uuidEntries)
+	if limit > 0 && limit < n {
+		n = limit
+	}
+	entries := make([]entry, 0, n)
+	for e := c.evictList.Front(); e != nil && len(entries) < n; e = e.Next() {
+		if ent := e.Value.(*entry); uuid.Equal(ent.uuid, segmentUUID) {
+			entries = append(entries, *ent)
+		}
+	}
+	return entries
+}
+
 // Len returns the number of items in the cache.
 func (c *postingsListLRU) Len() int {
 	return c.evictList.Len()
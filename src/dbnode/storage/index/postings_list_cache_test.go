@@ -188,6 +188,38 @@ func TestPurgeSegment(t *testing.T) {
 	}
 }
 
+func TestSegmentEntries(t *testing.T) {
+	plCache, stopReporting, err := NewPostingsListCache(10, testPostingListCacheOptions)
+	require.NoError(t, err)
+	defer stopReporting()
+
+	var (
+		segmentUUID = uuid.NewUUID()
+		otherUUID   = uuid.NewUUID()
+		pl          = roaring.NewPostingsList()
+	)
+	plCache.PutTerm(segmentUUID, "city", "nyc", pl)
+	plCache.PutRegexp(otherUUID, "city", "new.*", pl)
+	plCache.PutRegexp(segmentUUID, "city", "new.*", pl)
+	plCache.PutField(segmentUUID, "dc", pl)
+
+	// Most recently used first.
+	_, ok := plCache.GetTerm(segmentUUID, "city", "nyc")
+	require.True(t, ok)
+	require.Equal(t, []PostingsListCacheEntry{
+		{Field: "city", Pattern: "nyc", PatternType: PatternTypeTerm, PostingsList: pl},
+		{Field: "dc", PatternType: PatternTypeField, PostingsList: pl},
+		{Field: "city", Pattern: "new.*", PatternType: PatternTypeRegexp, PostingsList: pl},
+	}, plCache.SegmentEntries(segmentUUID, 0))
+
+	// Limited to the most recently used.
+	require.Equal(t, []PostingsListCacheEntry{
+		{Field: "city", Pattern: "nyc", PatternType: PatternTypeTerm, PostingsList: pl},
+	}, plCache.SegmentEntries(segmentUUID, 1))
+
+	require.Empty(t, plCache.SegmentEntries(uuid.NewUUID(), 0))
+}
+
 func TestEverthingInsertedCanBeRetrieved(t *testing.T) {
 	plCache, stopReporting, err := NewPostingsListCache(len(testPlEntries), testPostingListCacheOptions)
 	require.NoError(t, err)
@@ -23,18 +23,24 @@ package index
 import (
 	"errors"
 	"sync"
+	"time"
 
+	"github.com/m3db/m3/src/dbnode/persist/fs"
 	"github.com/m3db/m3/src/m3ninx/doc"
 	"github.com/m3db/m3/src/m3ninx/index"
 	"github.com/m3db/m3/src/m3ninx/index/segment"
 	"github.com/m3db/m3/src/m3ninx/postings"
+	"github.com/m3db/m3/src/x/instrument"
 
 	"github.com/pborman/uuid"
+	"github.com/uber-go/tally"
 )
 
 var (
 	errCantGetReaderFromClosedSegment = errors.New("cant get reader from closed segment")
 	errCantCloseClosedSegment         = errors.New("cant close closed segment")
+	errReaderDoesNotProvideStats      = errors.New("reader does not provide stats")
+	errReaderDoesNotProvideOrderedIDs = errors.New("reader does not provide ordered ids")
 )
 
 // Ensure FST segment implements ImmutableSegment so can be casted upwards
@@ -48,15 +54,21 @@ var _ segment.ImmutableSegment = (*ReadThroughSegment)(nil)
 // segments mmap'd region. As a result, the close method of the ReadThroughSegment
 // will make sure that the cache is purged of all the segments postings lists before
 // the segment itself is closed.
+//
+// Segments read from an index fileset volume additionally seed the cache with the
+// postings lists persisted alongside the volume, and can return their most recently
+// used postings lists to be persisted.
 type ReadThroughSegment struct {
 	sync.RWMutex
 
-	segment segment.ImmutableSegment
+	segment        segment.ImmutableSegment
+	fileSetSegment *fs.IndexFileSetSegment
 
 	uuid              uuid.UUID
 	postingsListCache *PostingsListCache
 
-	opts ReadThroughSegmentOptions
+	opts    ReadThroughSegmentOptions
+	metrics *readThroughSegmentMetrics
 
 	closed bool
 }
@@ -68,6 +80,19 @@ type ReadThroughSegmentOptions struct {
 	CacheRegexp bool
 	// Whether the postings list for term queries should be cached.
 	CacheTerms bool
+
+	// Whether the most recently used postings lists of segments read from index
+	// filesets should be persisted alongside the filesets and loaded at bootstrap.
+	PersistCache bool
+	// The max number of postings lists persisted per segment, zero persists all
+	// the postings lists cached for the segment.
+	PersistCacheMaxEntries int
+	// The interval at which the postings lists are persisted.
+	PersistCacheInterval time.Duration
+
+	// InstrumentOptions are used to emit the cache hits and misses of the
+	// segment by query type.
+	InstrumentOptions instrument.Options
 }
 
 // NewReadThroughSegment creates a new read through segment.
@@ -76,12 +101,102 @@ func NewReadThroughSegment(
 	cache *PostingsListCache,
 	opts ReadThroughSegmentOptions,
 ) segment.Segment {
-	return &ReadThroughSegment{
+	iopts := opts.InstrumentOptions
+	if iopts == nil {
+		iopts = instrument.NewOptions()
+	}
+
+	r := &ReadThroughSegment{
 		segment:           seg,
 		opts:              opts,
+		metrics:           newReadThroughSegmentMetrics(iopts.MetricsScope()),
 		uuid:              uuid.NewUUID(),
 		postingsListCache: cache,
 	}
+	if fileSetSegment, ok := seg.(*fs.IndexFileSetSegment); ok {
+		r.fileSetSegment = fileSetSegment
+		r.loadPersistedCache()
+	}
+	return r
+}
+
+// loadPersistedCache seeds the cache with the postings lists persisted for
+// the segment alongside its index fileset volume.
+func (r *ReadThroughSegment) loadPersistedCache() {
+	// NB: always take the persisted postings lists so that the segment does
+	// not hold on to them once they are evicted from the cache.
+	entries := r.fileSetSegment.TakeCachedPostings()
+	if r.postingsListCache == nil {
+		return
+	}
+
+	for _, entry := range entries {
+		var (
+			field   = string(entry.Field)
+			pattern = string(entry.Pattern)
+		)
+		switch entry.QueryType {
+		case fs.IndexPostingsCacheRegexpQuery:
+			if !r.opts.CacheRegexp {
+				continue
+			}
+			r.postingsListCache.PutRegexp(r.uuid, field, pattern, entry.PostingsList)
+			r.metrics.regexp.loaded.Inc(1)
+		case fs.IndexPostingsCacheTermQuery:
+			if !r.opts.CacheTerms {
+				continue
+			}
+			r.postingsListCache.PutTerm(r.uuid, field, pattern, entry.PostingsList)
+			r.metrics.term.loaded.Inc(1)
+		case fs.IndexPostingsCacheFieldQuery:
+			if !r.opts.CacheTerms {
+				continue
+			}
+			r.postingsListCache.PutField(r.uuid, field, entry.PostingsList)
+			r.metrics.field.loaded.Inc(1)
+		}
+	}
+}
+
+// persistableCachedPostings returns the identifier of the index fileset volume
+// the segment was read from and the most recently used postings lists cached
+// for the segment, or false if the segment was not read from an index fileset.
+// The postings lists are only valid until the segment is closed.
+func (r *ReadThroughSegment) persistableCachedPostings(
+	limit int,
+) (fs.FileSetFileIdentifier, []fs.IndexPostingsCacheEntry, bool) {
+	r.RLock()
+	defer r.RUnlock()
+	if r.closed || r.fileSetSegment == nil || r.postingsListCache == nil {
+		return fs.FileSetFileIdentifier{}, nil, false
+	}
+
+	var (
+		cached       = r.postingsListCache.SegmentEntries(r.uuid, limit)
+		segmentIndex = r.fileSetSegment.SegmentIndex()
+		entries      = make([]fs.IndexPostingsCacheEntry, 0, len(cached))
+	)
+	for _, entry := range cached {
+		var queryType fs.IndexPostingsCacheQueryType
+		switch entry.PatternType {
+		case PatternTypeRegexp:
+			queryType = fs.IndexPostingsCacheRegexpQuery
+		case PatternTypeTerm:
+			queryType = fs.IndexPostingsCacheTermQuery
+		case PatternTypeField:
+			queryType = fs.IndexPostingsCacheFieldQuery
+		default:
+			continue
+		}
+		entries = append(entries, fs.IndexPostingsCacheEntry{
+			SegmentIndex: segmentIndex,
+			QueryType:    queryType,
+			Field:        []byte(entry.Field),
+			Pattern:      []byte(entry.Pattern),
+			PostingsList: entry.PostingsList,
+		})
+	}
+	return r.fileSetSegment.FileSetID(), entries, true
 }
 
 // Reader returns a read through reader for the read through segment.
@@ -97,7 +212,7 @@ func (r *ReadThroughSegment) Reader() (index.Reader, error) {
 		return nil, err
 	}
 	return newReadThroughSegmentReader(
-		reader, r.uuid, r.postingsListCache, r.opts), nil
+		reader, r.uuid, r.postingsListCache, r.opts, r.metrics), nil
 }
 
 // Close purges all entries in the cache associated with this segment,
@@ -161,6 +276,7 @@ type readThroughSegmentReader struct {
 	// to be explicitly supported by the read through cache.
 	reader            index.Reader
 	opts              ReadThroughSegmentOptions
+	metrics           *readThroughSegmentMetrics
 	uuid              uuid.UUID
 	postingsListCache *PostingsListCache
 }
@@ -170,10 +286,12 @@ func newReadThroughSegmentReader(
 	uuid uuid.UUID,
 	cache *PostingsListCache,
 	opts ReadThroughSegmentOptions,
+	metrics *readThroughSegmentMetrics,
 ) index.Reader {
 	return &readThroughSegmentReader{
 		reader:            reader,
 		opts:              opts,
+		metrics:           metrics,
 		uuid:              uuid,
 		postingsListCache: cache,
 	}
@@ -193,6 +311,7 @@ func (s *readThroughSegmentReader) MatchRegexp(
 	fieldStr := string(field)
 	patternStr := c.FSTSyntax.String()
 	pl, ok := s.postingsListCache.GetRegexp(s.uuid, fieldStr, patternStr)
+	s.metrics.regexp.emitGet(ok)
 	if ok {
 		return pl, nil
 	}
@@ -217,6 +336,7 @@ func (s *readThroughSegmentReader) MatchTerm(
 	fieldStr := string(field)
 	patternStr := string(term)
 	pl, ok := s.postingsListCache.GetTerm(s.uuid, fieldStr, patternStr)
+	s.metrics.term.emitGet(ok)
 	if ok {
 		return pl, nil
 	}
@@ -238,6 +358,7 @@ func (s *readThroughSegmentReader) MatchField(field []byte) (postings.List, erro
 	// TODO(rartoul): Would be nice to not allocate strings here.
 	fieldStr := string(field)
 	pl, ok := s.postingsListCache.GetField(s.uuid, fieldStr)
+	s.metrics.field.emitGet(ok)
 	if ok {
 		return pl, nil
 	}
@@ -249,6 +370,15 @@ func (s *readThroughSegmentReader) MatchField(field []byte) (postings.List, erro
 	return pl, err
 }
 
+// MatchTerms is a pass through call, the terms matchers have no stable
+// cache key so their postings lists are not cached.
+func (s *readThroughSegmentReader) MatchTerms(
+	field []byte,
+	m index.TermsMatcher,
+) (postings.List, error) {
+	return s.reader.MatchTerms(field, m)
+}
+
 // MatchAll is a pass through call, since there's no postings list to cache.
 // NB(r): The postings list returned by match all is just an iterator
 // from zero to the maximum document number indexed by the segment and as such
@@ -272,7 +402,88 @@ func (s *readThroughSegmentReader) Docs(pl postings.List) (doc.Iterator, error)
 	return s.reader.Docs(pl)
 }
 
+// NumDocs is a pass through call, since stats are not cached.
+func (s *readThroughSegmentReader) NumDocs() (int, error) {
+	r, ok := s.reader.(index.StatsReader)
+	if !ok {
+		return 0, errReaderDoesNotProvideStats
+	}
+	return r.NumDocs()
+}
+
+// NumTerms is a pass through call, since stats are not cached.
+func (s *readThroughSegmentReader) NumTerms(field []byte) (int, error) {
+	r, ok := s.reader.(index.StatsReader)
+	if !ok {
+		return 0, errReaderDoesNotProvideStats
+	}
+	return r.NumTerms(field)
+}
+
+// TermCardinality is a pass through call, since stats are not cached.
+func (s *readThroughSegmentReader) TermCardinality(
+	field []byte, term []byte,
+) (int, error) {
+	r, ok := s.reader.(index.StatsReader)
+	if !ok {
+		return 0, errReaderDoesNotProvideStats
+	}
+	return r.TermCardinality(field, term)
+}
+
+// IDsAfter is a pass through call, since there's no postings list to cache.
+func (s *readThroughSegmentReader) IDsAfter(after []byte) (index.IDIterator, error) {
+	r, ok := s.reader.(index.OrderedIDsReader)
+	if !ok {
+		return nil, errReaderDoesNotProvideOrderedIDs
+	}
+	return r.IDsAfter(after)
+}
+
 // Close is a pass through call.
 func (s *readThroughSegmentReader) Close() error {
 	return s.reader.Close()
 }
+
+type readThroughSegmentMetrics struct {
+	regexp *readThroughSegmentQueryMetrics
+	term   *readThroughSegmentQueryMetrics
+	field  *readThroughSegmentQueryMetrics
+}
+
+func newReadThroughSegmentMetrics(scope tally.Scope) *readThroughSegmentMetrics {
+	scope = scope.SubScope("postings-list-cache")
+	return &readThroughSegmentMetrics{
+		regexp: newReadThroughSegmentQueryMetrics(scope.Tagged(map[string]string{
+			"query_type": "regexp",
+		})),
+		term: newReadThroughSegmentQueryMetrics(scope.Tagged(map[string]string{
+			"query_type": "term",
+		})),
+		field: newReadThroughSegmentQueryMetrics(scope.Tagged(map[string]string{
+			"query_type": "field",
+		})),
+	}
+}
+
+type readThroughSegmentQueryMetrics struct {
+	hits   tally.Counter
+	misses tally.Counter
+	loaded tally.Counter
+}
+
+func newReadThroughSegmentQueryMetrics(scope tally.Scope) *readThroughSegmentQueryMetrics {
+	return &readThroughSegmentQueryMetrics{
+		hits:   scope.Counter("hits"),
+		misses: scope.Counter("misses"),
+		loaded: scope.Counter("loaded"),
+	}
+}
+
+func (m *readThroughSegmentQueryMetrics) emitGet(hit bool) {
+	if hit {
+		m.hits.Inc(1)
+	} else {
+		m.misses.Inc(1)
+	}
+}
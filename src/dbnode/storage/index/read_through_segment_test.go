@@ -24,6 +24,7 @@ import (
 	"regexp/syntax"
 	"testing"
 
+	"github.com/m3db/m3/src/dbnode/persist/fs"
 	"github.com/m3db/m3/src/m3ninx/index"
 	"github.com/m3db/m3/src/m3ninx/index/segment/fst"
 	"github.com/m3db/m3/src/m3ninx/postings/roaring"
@@ -413,3 +414,66 @@ func TestCloseNoCache(t *testing.T) {
 	require.NoError(t, err)
 	require.True(t, readThrough.(*ReadThroughSegment).closed)
 }
+
+func TestReadThroughSegmentLoadsPersistedCache(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cache, stopReporting, err := NewPostingsListCache(10, testPostingListCacheOptions)
+	require.NoError(t, err)
+	defer stopReporting()
+
+	pl := roaring.NewPostingsList()
+	require.NoError(t, pl.Insert(1))
+
+	fileSetID := fs.FileSetFileIdentifier{VolumeIndex: 2}
+	segment := fs.NewIndexFileSetSegment(fst.NewMockSegment(ctrl), fileSetID, 3,
+		[]fs.IndexPostingsCacheEntry{
+			{
+				SegmentIndex: 3,
+				QueryType:    fs.IndexPostingsCacheRegexpQuery,
+				Field:        []byte("city"),
+				Pattern:      []byte("new.*"),
+				PostingsList: pl,
+			},
+			{
+				SegmentIndex: 3,
+				QueryType:    fs.IndexPostingsCacheTermQuery,
+				Field:        []byte("city"),
+				Pattern:      []byte("nyc"),
+				PostingsList: pl,
+			},
+		})
+
+	// Only the postings lists of the cached query types are loaded.
+	readThroughSeg, ok := NewReadThroughSegment(segment, cache, ReadThroughSegmentOptions{
+		CacheRegexp: false,
+		CacheTerms:  true,
+	}).(*ReadThroughSegment)
+	require.True(t, ok)
+	require.Empty(t, segment.TakeCachedPostings())
+
+	_, ok = cache.GetRegexp(readThroughSeg.uuid, "city", "new.*")
+	require.False(t, ok)
+	cached, ok := cache.GetTerm(readThroughSeg.uuid, "city", "nyc")
+	require.True(t, ok)
+	require.True(t, pl.Equal(cached))
+
+	id, entries, ok := readThroughSeg.persistableCachedPostings(0)
+	require.True(t, ok)
+	require.Equal(t, fileSetID, id)
+	require.Equal(t, []fs.IndexPostingsCacheEntry{
+		{
+			SegmentIndex: 3,
+			QueryType:    fs.IndexPostingsCacheTermQuery,
+			Field:        []byte("city"),
+			Pattern:      []byte("nyc"),
+			PostingsList: pl,
+		},
+	}, entries)
+
+	// Segments not read from index filesets have nothing to persist.
+	_, _, ok = NewReadThroughSegment(fst.NewMockSegment(ctrl), cache,
+		defaultReadThroughSegmentOptions).(*ReadThroughSegment).persistableCachedPostings(0)
+	require.False(t, ok)
+}
@@ -134,6 +134,9 @@ func (r *results) addDocumentWithLock(
 	if r.opts.FilterID != nil && !r.opts.FilterID(tsID) {
 		return false, r.resultsMap.Len(), nil
 	}
+	if r.opts.FilterDocument != nil && !r.opts.FilterDocument(d) {
+		return false, r.resultsMap.Len(), nil
+	}
 
 	// check if it already exists in the map.
 	if r.resultsMap.Contains(tsID) {
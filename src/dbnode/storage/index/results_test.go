@@ -109,6 +109,25 @@ func TestResultsInsertContains(t *testing.T) {
 	require.Equal(t, 0, tags.Remaining())
 }
 
+func TestResultsInsertFilterDocument(t *testing.T) {
+	res := NewQueryResults(nil, QueryResultsOptions{
+		FilterDocument: func(d doc.Document) bool {
+			return len(d.Fields) == 0
+		},
+	}, testOpts)
+	dValid := doc.Document{ID: []byte("abc")}
+	dFiltered := doc.Document{ID: []byte("def"),
+		Fields: doc.Fields{
+			doc.Field{Name: []byte("foo"), Value: []byte("bar")},
+		}}
+	size, err := res.AddDocuments([]doc.Document{dValid, dFiltered})
+	require.NoError(t, err)
+	require.Equal(t, 1, size)
+
+	require.True(t, res.Map().Contains(ident.StringID("abc")))
+	require.False(t, res.Map().Contains(ident.StringID("def")))
+}
+
 func TestResultsInsertDoesNotCopy(t *testing.T) {
 	res := NewQueryResults(nil, QueryResultsOptions{}, testOpts)
 	dValid := doc.Document{ID: []byte("abc"), Fields: []doc.Field{
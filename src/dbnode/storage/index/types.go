@@ -26,6 +26,7 @@ import (
 	"time"
 
 	"github.com/m3db/m3/src/dbnode/clock"
+	"github.com/m3db/m3/src/dbnode/persist/fs"
 	"github.com/m3db/m3/src/dbnode/storage/bootstrap/result"
 	"github.com/m3db/m3/src/dbnode/storage/index/compaction"
 	"github.com/m3db/m3/src/m3ninx/doc"
@@ -80,6 +81,21 @@ type QueryOptions struct {
 	StartInclusive time.Time
 	EndExclusive   time.Time
 	Limit          int
+
+	// PageToken, when set, restricts the query to a single page of at most
+	// Limit results, the ones with the smallest IDs after the last ID of
+	// the page that returned it.
+	PageToken PageToken
+
+	// ValueFilter, when set, skips series whose values in the query range
+	// are known not to satisfy it. It only prunes results, series without
+	// a complete value summary are always returned.
+	ValueFilter *ValueFilter
+}
+
+// Paginated returns whether the query options request a single page.
+func (o QueryOptions) Paginated() bool {
+	return o.PageToken != nil
 }
 
 // LimitExceeded returns whether a given size exceeds the limit
@@ -99,12 +115,20 @@ type AggregationOptions struct {
 type QueryResult struct {
 	Results    QueryResults
 	Exhaustive bool
+
+	// NextPageToken resumes a paginated query, it is nil once the
+	// query has been exhausted.
+	NextPageToken PageToken
 }
 
 // AggregateQueryResult is the collection of results for an aggregate query.
 type AggregateQueryResult struct {
 	Results    AggregateResults
 	Exhaustive bool
+
+	// NextPageToken resumes a paginated query, it is nil once the
+	// query has been exhausted.
+	NextPageToken PageToken
 }
 
 // BaseResults is a collection of basic results for a generic query, it is
@@ -157,6 +181,10 @@ type QueryResultsOptions struct {
 	// NB(r): This is used to filter out results from shards the DB node
 	// node no longer owns but is still included in index segments.
 	FilterID func(id ident.ID) bool
+
+	// FilterDocument, if provided, can be used to filter out unwanted
+	// documents from the query results, it is applied after FilterID.
+	FilterDocument func(d doc.Document) bool
 }
 
 // QueryResultsAllocator allocates QueryResults types.
@@ -221,6 +249,17 @@ type AggregateResultsOptions struct {
 
 	// FieldFilter is an optional param to filter aggregate values.
 	FieldFilter AggregateFieldFilter
+
+	// FilterID, if provided, can be used to filter out documents with
+	// unwanted IDs before their fields are aggregated.
+	// NB: This is only applied to documents, aggregating directly from
+	// segment field terms cannot be filtered by ID.
+	FilterID func(id ident.ID) bool
+
+	// FilterDocument, if provided, can be used to filter out unwanted
+	// documents before their fields are aggregated, it is applied after
+	// FilterID and like it only to documents.
+	FilterDocument func(d doc.Document) bool
 }
 
 // AggregateResultsAllocator allocates AggregateResults types.
@@ -314,6 +353,17 @@ type Block interface {
 		logFields []opentracinglog.Field,
 	) (exhaustive bool, err error)
 
+	// QueryPage resolves the given query into known IDs one page at a time,
+	// it adds the matching documents of the block after the cursor of the page
+	// in increasing order of their IDs until the page rejects one.
+	QueryPage(
+		ctx context.Context,
+		cancellable *resource.CancellableLifetime,
+		query Query,
+		page *PageResults,
+		logFields []opentracinglog.Field,
+	) error
+
 	// Aggregate aggregates known tag names/values.
 	// NB(prateek): different from aggregating by means of Query, as we can
 	// avoid going to documents, relying purely on the indexed FSTs.
@@ -325,6 +375,14 @@ type Block interface {
 		logFields []opentracinglog.Field,
 	) (exhaustive bool, err error)
 
+	// Cardinality accumulates statistics of the fields and terms indexed
+	// by the block, computed from postings lists without going to documents.
+	Cardinality(
+		cancellable *resource.CancellableLifetime,
+		opts CardinalityOptions,
+		acc *CardinalityAccumulator,
+	) error
+
 	// AddResults adds bootstrap results to the block.
 	AddResults(results result.IndexBlock) error
 
@@ -353,6 +411,16 @@ type Block interface {
 	// data the mutable segments should have held at this time.
 	EvictMutableSegments() error
 
+	// PersistedSegments returns the segments of a sealed block read from or
+	// written to index filesets, once all of its mutable segments have been
+	// evicted. The segments are only valid until the block is closed.
+	PersistedSegments() ([]segment.Segment, error)
+
+	// PersistPostingsListCache writes the most recently used cached postings lists
+	// of the segments read from index filesets alongside those filesets, so that
+	// they are loaded into the cache when the filesets are bootstrapped.
+	PersistPostingsListCache(fsOpts fs.Options) error
+
 	// Close will release any held resources and close the Block.
 	Close() error
 }
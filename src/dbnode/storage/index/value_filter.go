@@ -0,0 +1,85 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package index
+
+import "math"
+
+// ValueFilterType is the comparison a value filter applies.
+type ValueFilterType uint8
+
+const (
+	// ValueFilterEqual matches values equal to the filter value.
+	ValueFilterEqual ValueFilterType = iota
+	// ValueFilterNotEqual matches values not equal to the filter value.
+	ValueFilterNotEqual
+	// ValueFilterGreater matches values greater than the filter value.
+	ValueFilterGreater
+	// ValueFilterGreaterEqual matches values greater than or equal to the
+	// filter value.
+	ValueFilterGreaterEqual
+	// ValueFilterLess matches values less than the filter value.
+	ValueFilterLess
+	// ValueFilterLessEqual matches values less than or equal to the filter
+	// value.
+	ValueFilterLessEqual
+)
+
+// ValueFilter filters series by comparing their values to a scalar.
+type ValueFilter struct {
+	Type  ValueFilterType
+	Value float64
+
+	// Latest compares only the latest value of a series in the query range,
+	// otherwise a series matches if any of its values in the range match.
+	Latest bool
+}
+
+// Matches returns whether a single value satisfies the filter.
+func (f ValueFilter) Matches(value float64) bool {
+	if math.IsNaN(value) {
+		return false
+	}
+	return f.MatchesRange(value, value)
+}
+
+// MatchesRange returns whether any value within [min, max] may satisfy the
+// filter, an empty range where min is greater than max never matches.
+func (f ValueFilter) MatchesRange(min, max float64) bool {
+	if min > max {
+		return false
+	}
+	switch f.Type {
+	case ValueFilterEqual:
+		return min <= f.Value && f.Value <= max
+	case ValueFilterNotEqual:
+		return !(min == f.Value && max == f.Value)
+	case ValueFilterGreater:
+		return max > f.Value
+	case ValueFilterGreaterEqual:
+		return max >= f.Value
+	case ValueFilterLess:
+		return min < f.Value
+	case ValueFilterLessEqual:
+		return min <= f.Value
+	}
+	// Unknown comparisons never prune.
+	return true
+}
@@ -0,0 +1,63 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package index
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValueFilterMatchesRange(t *testing.T) {
+	tests := []struct {
+		filterType ValueFilterType
+		min, max   float64
+		expected   bool
+	}{
+		{ValueFilterEqual, 1, 3, true},
+		{ValueFilterEqual, 3, 4, false},
+		{ValueFilterNotEqual, 2, 2, false},
+		{ValueFilterNotEqual, 1, 2, true},
+		{ValueFilterGreater, 1, 2, false},
+		{ValueFilterGreater, 1, 3, true},
+		{ValueFilterGreaterEqual, 1, 2, true},
+		{ValueFilterGreaterEqual, 0, 1, false},
+		{ValueFilterLess, 2, 3, false},
+		{ValueFilterLess, 1, 3, true},
+		{ValueFilterLessEqual, 2, 3, true},
+		{ValueFilterLessEqual, 3, 4, false},
+		{ValueFilterGreater, math.Inf(1), math.Inf(-1), false},
+	}
+
+	for _, test := range tests {
+		filter := ValueFilter{Type: test.filterType, Value: 2}
+		require.Equal(t, test.expected, filter.MatchesRange(test.min, test.max),
+			"type=%d, min=%f, max=%f", test.filterType, test.min, test.max)
+	}
+}
+
+func TestValueFilterMatches(t *testing.T) {
+	filter := ValueFilter{Type: ValueFilterGreater, Value: 2}
+	require.True(t, filter.Matches(3))
+	require.False(t, filter.Matches(2))
+	require.False(t, filter.Matches(math.NaN()))
+}
@@ -22,6 +22,7 @@ package storage
 
 import (
 	stdlibctx "context"
+	"errors"
 	"fmt"
 	"sync"
 	"testing"
@@ -29,6 +30,7 @@ import (
 
 	"github.com/m3db/m3/src/cluster/shard"
 	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/dbnode/retention"
 	"github.com/m3db/m3/src/dbnode/sharding"
 	"github.com/m3db/m3/src/dbnode/storage/bootstrap/result"
 	"github.com/m3db/m3/src/dbnode/storage/index"
@@ -37,11 +39,13 @@ import (
 	"github.com/m3db/m3/src/m3ninx/index/segment"
 	"github.com/m3db/m3/src/x/context"
 	"github.com/m3db/m3/src/x/ident"
+	"github.com/m3db/m3/src/x/resource"
 	xtest "github.com/m3db/m3/src/x/test"
 	xtime "github.com/m3db/m3/src/x/time"
 
 	"github.com/golang/mock/gomock"
 	opentracing "github.com/opentracing/opentracing-go"
+	opentracinglog "github.com/opentracing/opentracing-go/log"
 	"github.com/opentracing/opentracing-go/mocktracer"
 	"github.com/stretchr/testify/require"
 )
@@ -403,6 +407,7 @@ func TestNamespaceIndexTickExpire(t *testing.T) {
 	b0 := index.NewMockBlock(ctrl)
 	b0.EXPECT().Stats(gomock.Any()).Return(nil).AnyTimes()
 	b0.EXPECT().StartTime().Return(t0).AnyTimes()
+	b0.EXPECT().EndTime().Return(t0.Add(blockSize)).AnyTimes()
 	newBlockFn := func(
 		ts time.Time,
 		md namespace.Metadata,
@@ -452,6 +457,7 @@ func TestNamespaceIndexTick(t *testing.T) {
 	b0.EXPECT().Stats(gomock.Any()).Return(nil).AnyTimes()
 	b0.EXPECT().Close().Return(nil)
 	b0.EXPECT().StartTime().Return(t0).AnyTimes()
+	b0.EXPECT().EndTime().Return(t0.Add(blockSize)).AnyTimes()
 	newBlockFn := func(
 		ts time.Time,
 		md namespace.Metadata,
@@ -827,6 +833,79 @@ func TestNamespaceIndexBlockAggregateQuery(t *testing.T) {
 	require.Len(t, spans, 11)
 }
 
+func TestNamespaceIndexBlockCardinality(t *testing.T) {
+	ctrl := gomock.NewController(xtest.Reporter{T: t})
+	defer ctrl.Finish()
+
+	retention := 2 * time.Hour
+	blockSize := time.Hour
+	now := time.Now().Truncate(blockSize).Add(10 * time.Minute)
+	t0 := now.Truncate(blockSize)
+	t1 := t0.Add(1 * blockSize)
+	t2 := t1.Add(1 * blockSize)
+	opts := DefaultTestOptions()
+	opts = opts.SetClockOptions(opts.ClockOptions().SetNowFn(func() time.Time {
+		return now
+	}))
+
+	b0 := index.NewMockBlock(ctrl)
+	b0.EXPECT().Stats(gomock.Any()).Return(nil).AnyTimes()
+	b0.EXPECT().Close().Return(nil)
+	b0.EXPECT().StartTime().Return(t0).AnyTimes()
+	b0.EXPECT().EndTime().Return(t0.Add(blockSize)).AnyTimes()
+	b1 := index.NewMockBlock(ctrl)
+	b1.EXPECT().Stats(gomock.Any()).Return(nil).AnyTimes()
+	b1.EXPECT().Close().Return(nil)
+	b1.EXPECT().StartTime().Return(t1).AnyTimes()
+	b1.EXPECT().EndTime().Return(t1.Add(blockSize)).AnyTimes()
+	newBlockFn := func(
+		ts time.Time,
+		md namespace.Metadata,
+		_ index.BlockOptions,
+		io index.Options,
+	) (index.Block, error) {
+		if ts.Equal(t0) {
+			return b0, nil
+		}
+		if ts.Equal(t1) {
+			return b1, nil
+		}
+		panic("should never get here")
+	}
+	md := testNamespaceMetadata(blockSize, retention)
+	idx, err := newNamespaceIndexWithNewBlockFn(md, testShardSet, newBlockFn, opts)
+	require.NoError(t, err)
+
+	defer func() {
+		require.NoError(t, idx.Close())
+	}()
+
+	ctx := context.NewContext()
+	defer ctx.Close()
+
+	// only reads the blocks in the time range
+	cOpts := index.CardinalityOptions{
+		StartInclusive: t0,
+		EndExclusive:   now.Add(time.Minute),
+		Limit:          10,
+	}
+	b0.EXPECT().Cardinality(gomock.Any(), cOpts, gomock.Any()).Return(nil)
+	_, err = idx.Cardinality(ctx, cOpts)
+	require.NoError(t, err)
+
+	// reads multiple blocks if needed
+	cOpts.EndExclusive = t2.Add(time.Minute)
+	b0.EXPECT().Cardinality(gomock.Any(), cOpts, gomock.Any()).Return(nil)
+	b1.EXPECT().Cardinality(gomock.Any(), cOpts, gomock.Any()).Return(nil)
+	_, err = idx.Cardinality(ctx, cOpts)
+	require.NoError(t, err)
+
+	// returns block errors
+	b0.EXPECT().Cardinality(gomock.Any(), cOpts, gomock.Any()).Return(errors.New("boom"))
+	_, err = idx.Cardinality(ctx, cOpts)
+	require.Error(t, err)
+}
+
 func TestNamespaceIndexBlockAggregateQueryReleasingContext(t *testing.T) {
 	ctrl := gomock.NewController(xtest.Reporter{T: t})
 	defer ctrl.Finish()
@@ -929,7 +1008,7 @@ func TestNamespaceIndexBlockAggregateQueryAggPath(t *testing.T) {
 	defer ctrl.Finish()
 
 	queries := []idx.Query{idx.NewAllQuery(), idx.NewFieldQuery([]byte("field"))}
-	retention := 2 * time.Hour
+	retentionPeriod := 2 * time.Hour
 	blockSize := time.Hour
 	now := time.Now().Truncate(blockSize).Add(10 * time.Minute)
 	t0 := now.Truncate(blockSize)
@@ -970,7 +1049,7 @@ func TestNamespaceIndexBlockAggregateQueryAggPath(t *testing.T) {
 		}
 		panic("should never get here")
 	}
-	md := testNamespaceMetadata(blockSize, retention)
+	md := testNamespaceMetadata(blockSize, retentionPeriod)
 	idx, err := newNamespaceIndexWithNewBlockFn(md, testShardSet, newBlockFn, opts)
 	require.NoError(t, err)
 
@@ -1023,9 +1102,34 @@ func TestNamespaceIndexBlockAggregateQueryAggPath(t *testing.T) {
 			StartInclusive: t0,
 			EndExclusive:   t0.Add(time.Minute),
 		}
+		// NB: the TTLs of the series are aggregated first since the query
+		// range has ended.
+		b0.EXPECT().Aggregate(ctx, gomock.Any(), qOpts, gomock.Any(), gomock.Any()).Return(true, nil)
 		b0.EXPECT().Aggregate(ctx, gomock.Any(), qOpts, gomock.Any(), gomock.Any()).Return(false, nil)
 		aggOpts = index.AggregationOptions{QueryOptions: qOpts}
 		_, err = idx.AggregateQuery(ctx, q, aggOpts)
 		require.NoError(t, err)
 	}
+
+	// reads the documents once the TTL of some series has expired over
+	// the query range.
+	q := index.Query{Query: queries[0]}
+	b0.EXPECT().Aggregate(ctx, gomock.Any(), qOpts, gomock.Any(), gomock.Any()).DoAndReturn(
+		func(
+			_ context.Context,
+			_ *resource.CancellableLifetime,
+			_ index.QueryOptions,
+			results index.AggregateResults,
+			_ []opentracinglog.Field,
+		) (bool, error) {
+			results.AddFields([]index.AggregateResultsEntry{{
+				Field: ident.StringID(retention.SeriesTTLTagName),
+				Terms: []ident.ID{ident.StringID("5m")},
+			}})
+			return true, nil
+		})
+	b0.EXPECT().Query(ctx, gomock.Any(), q, qOpts, gomock.Any(), gomock.Any()).Return(true, nil)
+	aggOpts := index.AggregationOptions{QueryOptions: qOpts}
+	_, err = idx.AggregateQuery(ctx, q, aggOpts)
+	require.NoError(t, err)
 }
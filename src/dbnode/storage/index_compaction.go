@@ -0,0 +1,278 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"errors"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/retention"
+	"github.com/m3db/m3/src/dbnode/storage/bootstrap/result"
+	"github.com/m3db/m3/src/dbnode/storage/index"
+	"github.com/m3db/m3/src/dbnode/storage/index/compaction"
+	"github.com/m3db/m3/src/m3ninx/index/segment"
+	"github.com/m3db/m3/src/m3ninx/index/segment/builder"
+	xerrors "github.com/m3db/m3/src/x/errors"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"go.uber.org/zap"
+)
+
+var errDbIndexUnableToCompactClosed = errors.New("unable to compact database index blocks, already closed")
+
+// compactBlocks compacts the sealed and flushed blocks older than the
+// compaction age of the namespace into blocks of the compacted block size,
+// so that queries over older data search fewer blocks and segments.
+// Failures are only logged since the blocks remain queryable as they are.
+func (i *nsIndex) compactBlocks(
+	flush persist.IndexFlush,
+	shards []databaseShard,
+) {
+	if i.compactedBlockSize <= 0 || i.coldWritesEnabled {
+		// Blocks of namespaces with cold writes enabled can be written to
+		// for the whole retention period so are never compacted.
+		return
+	}
+
+	tasks, blocks, err := i.blockCompactionTasks()
+	if err != nil {
+		i.metrics.BlockCompactionErrors.Inc(1)
+		i.logger.Error("unable to plan index block compaction", zap.Error(err))
+		return
+	}
+
+	for _, task := range tasks {
+		taskBlocks := make([]index.Block, 0, len(task.BlockStarts))
+		for _, blockStart := range task.BlockStarts {
+			taskBlocks = append(taskBlocks, blocks[xtime.ToUnixNano(blockStart)])
+		}
+		if err := i.compactBlockTask(flush, shards, task, taskBlocks); err != nil {
+			i.metrics.BlockCompactionErrors.Inc(1)
+			i.logger.Error("unable to compact index blocks",
+				zap.Time("blockStart", task.BlockStart),
+				zap.Duration("blockSize", i.compactedBlockSize),
+				zap.Int("numBlocks", len(taskBlocks)),
+				zap.Error(err),
+			)
+			continue
+		}
+		i.metrics.BlocksCompacted.Inc(int64(len(taskBlocks)))
+	}
+}
+
+// blockCompactionTasks returns the tasks to compact the blocks held and the
+// blocks held by their block start.
+func (i *nsIndex) blockCompactionTasks() (
+	[]compaction.BlockTask,
+	map[xtime.UnixNano]index.Block,
+	error,
+) {
+	var (
+		now           = i.nowFn()
+		compactionAge = i.compactionAge
+	)
+	if compactionAge < i.bufferPast {
+		// Blocks can't be compacted while they are still written to.
+		compactionAge = i.bufferPast
+	}
+
+	i.state.RLock()
+	defer i.state.RUnlock()
+	if !i.isOpenWithRLock() {
+		return nil, nil, errDbIndexUnableToCompactClosed
+	}
+
+	var (
+		blockStarts = make([]time.Time, 0, len(i.state.blocksByTime))
+		blocks      = make(map[xtime.UnixNano]index.Block, len(i.state.blocksByTime))
+		// Compacted blocks that would hold a block that can't be compacted
+		// yet or an already compacted block.
+		skip = make(map[xtime.UnixNano]struct{})
+	)
+	for blockStart, block := range i.state.blocksByTime {
+		compactable := block.IsSealed() &&
+			!block.NeedsMutableSegmentsEvicted() &&
+			block.EndTime().Sub(block.StartTime()) == i.blockSize
+		if !compactable {
+			compactedStart := blockStart.ToTime().Truncate(i.compactedBlockSize)
+			skip[xtime.ToUnixNano(compactedStart)] = struct{}{}
+			continue
+		}
+		blockStarts = append(blockStarts, blockStart.ToTime())
+		blocks[blockStart] = block
+	}
+
+	tasks, err := compaction.NewBlockPlan(blockStarts, compaction.BlockPlannerOptions{
+		BlockSize:          i.blockSize,
+		CompactedBlockSize: i.compactedBlockSize,
+		EarliestBlockStart: retention.FlushTimeStartForRetentionPeriod(
+			i.retentionPeriod, i.blockSize, now),
+		LatestBlockEnd: now.Add(-compactionAge),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	compactable := tasks[:0]
+	for _, task := range tasks {
+		if _, ok := skip[xtime.ToUnixNano(task.BlockStart)]; ok {
+			continue
+		}
+		compactable = append(compactable, task)
+	}
+	return compactable, blocks, nil
+}
+
+// compactBlockTask persists a block compacted from the blocks of the task,
+// replaces them with it and then removes their filesets.
+func (i *nsIndex) compactBlockTask(
+	flush persist.IndexFlush,
+	shards []databaseShard,
+	task compaction.BlockTask,
+	blocks []index.Block,
+) error {
+	var (
+		fsOpts     = i.opts.CommitLogOptions().FilesystemOptions()
+		pathPrefix = fsOpts.FilePathPrefix()
+		nsID       = i.nsMetadata.ID()
+		files      []string
+	)
+	// Find the filesets to remove before the compacted block is persisted
+	// as it is persisted at the same block start as the first block.
+	for _, blockStart := range task.BlockStarts {
+		filesets, err := i.indexFilesetsAtFn(pathPrefix, nsID, blockStart)
+		if err != nil {
+			return err
+		}
+		for _, fileset := range filesets {
+			files = append(files, fileset.AbsoluteFilepaths...)
+		}
+	}
+
+	segments := make([]segment.Segment, 0, len(blocks))
+	for _, block := range blocks {
+		blockSegments, err := block.PersistedSegments()
+		if err != nil {
+			return err
+		}
+		segments = append(segments, blockSegments...)
+	}
+
+	immutableSegments, err := i.persistCompactedBlock(flush, shards,
+		task.BlockStart, segments)
+	if err != nil {
+		return err
+	}
+
+	blockEnd := task.BlockStart.Add(i.compactedBlockSize)
+	compacted, err := i.newBlockFn(task.BlockStart, i.nsMetadata,
+		index.BlockOptions{
+			BlockSize:       i.compactedBlockSize,
+			DocumentsFilter: i.seriesDeleted,
+		}, i.opts.IndexOptions())
+	if err != nil {
+		for _, seg := range immutableSegments {
+			seg.Close()
+		}
+		return err
+	}
+
+	fulfilled := result.NewShardTimeRanges(task.BlockStart, blockEnd,
+		dbShards(shards).IDs()...)
+	results := result.NewIndexBlock(task.BlockStart, immutableSegments, fulfilled).
+		SetBlockSize(i.compactedBlockSize)
+	if err := xerrors.FirstError(compacted.AddResults(results), compacted.Seal()); err != nil {
+		return xerrors.FirstError(err, compacted.Close())
+	}
+
+	i.state.Lock()
+	if !i.isOpenWithRLock() {
+		i.state.Unlock()
+		return xerrors.FirstError(errDbIndexUnableToCompactClosed, compacted.Close())
+	}
+	for _, blockStart := range task.BlockStarts {
+		delete(i.state.blocksByTime, xtime.ToUnixNano(blockStart))
+	}
+	i.state.blocksByTime[xtime.ToUnixNano(task.BlockStart)] = compacted
+	for _, block := range blocks {
+		i.state.blocksPendingClose = append(i.state.blocksPendingClose,
+			pendingCloseBlock{block: block, reads: i.state.blockReads})
+	}
+	// NB: queries from now on do not read the replaced blocks, so count them
+	// separately from the queries which could still be reading them.
+	i.state.blockReads = &blockReads{}
+	i.updateBlockStartsWithLock()
+	i.state.Unlock()
+
+	// The compacted block supersedes the filesets of the blocks it was
+	// compacted from, bootstrapping skips those left behind on failure.
+	return i.deleteFilesFn(files)
+}
+
+func (i *nsIndex) persistCompactedBlock(
+	flush persist.IndexFlush,
+	shards []databaseShard,
+	blockStart time.Time,
+	segments []segment.Segment,
+) ([]segment.Segment, error) {
+	allShards := make(map[uint32]struct{}, len(shards))
+	for _, shard := range shards {
+		allShards[shard.ID()] = struct{}{}
+	}
+
+	preparedPersist, err := flush.PrepareIndex(persist.IndexPrepareOptions{
+		NamespaceMetadata: i.nsMetadata,
+		BlockStart:        blockStart,
+		BlockSize:         i.compactedBlockSize,
+		FileSetType:       persist.FileSetFlushType,
+		Shards:            allShards,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var closed bool
+	defer func() {
+		if !closed {
+			persisted, _ := preparedPersist.Close()
+			// NB(r): Safe to for over a nil array so disregard error here.
+			for _, segment := range persisted {
+				segment.Close()
+			}
+		}
+	}()
+
+	compactor := builder.NewBuilderFromSegments(
+		i.opts.IndexOptions().SegmentBuilderOptions())
+	compactor.SetFilter(i.seriesDeleted)
+	if err := compactor.AddSegments(segments); err != nil {
+		return nil, err
+	}
+	if err := preparedPersist.Persist(compactor); err != nil {
+		return nil, err
+	}
+
+	closed = true
+
+	// Now return the immutable segments
+	return preparedPersist.Close()
+}
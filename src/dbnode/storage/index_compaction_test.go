@@ -0,0 +1,241 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/dbnode/storage/index"
+	"github.com/m3db/m3/src/m3ninx/index/segment"
+	"github.com/m3db/m3/src/x/context"
+	"github.com/m3db/m3/src/x/ident"
+	xtest "github.com/m3db/m3/src/x/test"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestCompactableBlock(
+	ctrl *gomock.Controller,
+	blockStart time.Time,
+	blockSize time.Duration,
+	needsEviction bool,
+) *index.MockBlock {
+	block := index.NewMockBlock(ctrl)
+	block.EXPECT().Stats(gomock.Any()).Return(nil).AnyTimes()
+	block.EXPECT().StartTime().Return(blockStart).AnyTimes()
+	block.EXPECT().EndTime().Return(blockStart.Add(blockSize)).AnyTimes()
+	block.EXPECT().IsSealed().Return(true).AnyTimes()
+	block.EXPECT().NeedsMutableSegmentsEvicted().Return(needsEviction).AnyTimes()
+	return block
+}
+
+func TestNamespaceIndexCompactBlocks(t *testing.T) {
+	ctrl := gomock.NewController(xtest.Reporter{T: t})
+	defer ctrl.Finish()
+
+	test := newTestIndex(t, ctrl)
+	idx := test.index.(*nsIndex)
+
+	compactedBlockSize := 4 * test.indexBlockSize
+	now := time.Now().Truncate(compactedBlockSize)
+	idx.nowFn = func() time.Time { return now.Add(test.indexBlockSize) }
+	idx.compactedBlockSize = compactedBlockSize
+
+	var (
+		compactedStart = now.Add(-compactedBlockSize)
+		firstStart     = compactedStart
+		secondStart    = compactedStart.Add(test.indexBlockSize)
+		first          = newTestCompactableBlock(ctrl, firstStart, test.indexBlockSize, false)
+		second         = newTestCompactableBlock(ctrl, secondStart, test.indexBlockSize, false)
+		compacted      = newTestCompactableBlock(ctrl, compactedStart, compactedBlockSize, false)
+	)
+	idx.state.blocksByTime[xtime.ToUnixNano(firstStart)] = first
+	idx.state.blocksByTime[xtime.ToUnixNano(secondStart)] = second
+
+	first.EXPECT().PersistedSegments().Return(nil, nil)
+	second.EXPECT().PersistedSegments().Return(nil, nil)
+
+	idx.indexFilesetsAtFn = func(
+		dir string,
+		nsID ident.ID,
+		blockStart time.Time,
+	) (fs.FileSetFilesSlice, error) {
+		return fs.FileSetFilesSlice{{
+			AbsoluteFilepaths: []string{fmt.Sprintf("%d", blockStart.UnixNano())},
+		}}, nil
+	}
+	var deleted []string
+	idx.deleteFilesFn = func(files []string) error {
+		deleted = append(deleted, files...)
+		return nil
+	}
+	idx.newBlockFn = func(
+		blockStart time.Time,
+		md namespace.Metadata,
+		opts index.BlockOptions,
+		indexOpts index.Options,
+	) (index.Block, error) {
+		require.True(t, compactedStart.Equal(blockStart))
+		require.Equal(t, compactedBlockSize, opts.BlockSize)
+		return compacted, nil
+	}
+
+	mockShard := NewMockdatabaseShard(ctrl)
+	mockShard.EXPECT().ID().Return(uint32(0)).AnyTimes()
+	shards := []databaseShard{mockShard}
+
+	persistCalled := false
+	mockFlush := persist.NewMockIndexFlush(ctrl)
+	mockFlush.EXPECT().PrepareIndex(xtest.CmpMatcher(persist.IndexPrepareOptions{
+		NamespaceMetadata: test.metadata,
+		BlockStart:        compactedStart,
+		BlockSize:         compactedBlockSize,
+		FileSetType:       persist.FileSetFlushType,
+		Shards:            map[uint32]struct{}{0: struct{}{}},
+	})).Return(persist.PreparedIndexPersist{
+		Persist: func(segment.Builder) error {
+			persistCalled = true
+			return nil
+		},
+		Close: func() ([]segment.Segment, error) {
+			return nil, nil
+		},
+	}, nil)
+
+	compacted.EXPECT().AddResults(gomock.Any()).Return(nil)
+	compacted.EXPECT().Seal().Return(nil)
+
+	idx.compactBlocks(mockFlush, shards)
+	require.True(t, persistCalled)
+	require.Equal(t, []string{
+		fmt.Sprintf("%d", firstStart.UnixNano()),
+		fmt.Sprintf("%d", secondStart.UnixNano()),
+	}, deleted)
+
+	// The compacted block replaces the blocks compacted into it.
+	idx.state.RLock()
+	block, ok := idx.state.blocksByTime[xtime.ToUnixNano(compactedStart)]
+	require.True(t, ok)
+	require.Equal(t, compacted, block)
+	_, ok = idx.state.blocksByTime[xtime.ToUnixNano(secondStart)]
+	require.False(t, ok)
+	require.Len(t, idx.state.blocksPendingClose, 2)
+	block, err := idx.ensureBlockPresentWithRLock(secondStart)
+	idx.state.RUnlock()
+	require.NoError(t, err)
+	require.Equal(t, compacted, block)
+
+	// Replaced blocks are closed with the index.
+	first.EXPECT().Close().Return(nil)
+	second.EXPECT().Close().Return(nil)
+	compacted.EXPECT().Close().Return(nil)
+	require.NoError(t, idx.Close())
+}
+
+func TestNamespaceIndexCompactBlocksSkipsUnflushedBlocks(t *testing.T) {
+	ctrl := gomock.NewController(xtest.Reporter{T: t})
+	defer ctrl.Finish()
+
+	test := newTestIndex(t, ctrl)
+	idx := test.index.(*nsIndex)
+
+	compactedBlockSize := 4 * test.indexBlockSize
+	now := time.Now().Truncate(compactedBlockSize)
+	idx.nowFn = func() time.Time { return now.Add(test.indexBlockSize) }
+	idx.compactedBlockSize = compactedBlockSize
+
+	var (
+		firstStart  = now.Add(-compactedBlockSize)
+		secondStart = firstStart.Add(test.indexBlockSize)
+		thirdStart  = secondStart.Add(test.indexBlockSize)
+		first       = newTestCompactableBlock(ctrl, firstStart, test.indexBlockSize, false)
+		second      = newTestCompactableBlock(ctrl, secondStart, test.indexBlockSize, false)
+		third       = newTestCompactableBlock(ctrl, thirdStart, test.indexBlockSize, true)
+	)
+	idx.state.blocksByTime[xtime.ToUnixNano(firstStart)] = first
+	idx.state.blocksByTime[xtime.ToUnixNano(secondStart)] = second
+	idx.state.blocksByTime[xtime.ToUnixNano(thirdStart)] = third
+
+	mockShard := NewMockdatabaseShard(ctrl)
+	mockShard.EXPECT().ID().Return(uint32(0)).AnyTimes()
+
+	// No compaction until all the blocks of the compacted block are flushed.
+	idx.compactBlocks(persist.NewMockIndexFlush(ctrl), []databaseShard{mockShard})
+	idx.state.RLock()
+	require.Len(t, idx.state.blocksByTime, 4)
+	require.Len(t, idx.state.blocksPendingClose, 0)
+	idx.state.RUnlock()
+
+	first.EXPECT().Close().Return(nil)
+	second.EXPECT().Close().Return(nil)
+	third.EXPECT().Close().Return(nil)
+	require.NoError(t, idx.Close())
+}
+
+func TestNamespaceIndexTickClosesReplacedBlocksOnceReleased(t *testing.T) {
+	ctrl := gomock.NewController(xtest.Reporter{T: t})
+	defer ctrl.Finish()
+
+	test := newTestIndex(t, ctrl)
+	idx := test.index.(*nsIndex)
+
+	now := time.Now()
+	replaced := newTestCompactableBlock(ctrl, now.Truncate(test.indexBlockSize),
+		test.indexBlockSize, false)
+
+	// A query still reads the replaced block so it is not closed.
+	idx.state.Lock()
+	reads := idx.state.blockReads
+	reads.acquire()
+	idx.state.blocksPendingClose = append(idx.state.blocksPendingClose,
+		pendingCloseBlock{block: replaced, reads: reads})
+	idx.state.blockReads = &blockReads{}
+	idx.state.Unlock()
+
+	// Queries started after the block was replaced do not hold it.
+	idx.state.RLock()
+	idx.state.blockReads.acquire()
+	idx.state.RUnlock()
+
+	_, err := idx.Tick(context.NewCancellable(), now)
+	require.NoError(t, err)
+	idx.state.RLock()
+	require.Len(t, idx.state.blocksPendingClose, 1)
+	idx.state.RUnlock()
+
+	// Closed on the first tick after the query releases it.
+	reads.release()
+	replaced.EXPECT().Close().Return(nil)
+	_, err = idx.Tick(context.NewCancellable(), now)
+	require.NoError(t, err)
+	idx.state.RLock()
+	require.Len(t, idx.state.blocksPendingClose, 0)
+	idx.state.RUnlock()
+
+	require.NoError(t, idx.Close())
+}
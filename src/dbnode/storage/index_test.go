@@ -29,7 +29,9 @@ import (
 	"github.com/m3db/m3/src/dbnode/persist"
 	"github.com/m3db/m3/src/dbnode/retention"
 	"github.com/m3db/m3/src/dbnode/storage/block"
+	"github.com/m3db/m3/src/dbnode/storage/bootstrap/result"
 	"github.com/m3db/m3/src/dbnode/storage/index"
+	"github.com/m3db/m3/src/m3ninx/doc"
 	"github.com/m3db/m3/src/m3ninx/idx"
 	"github.com/m3db/m3/src/m3ninx/index/segment"
 	"github.com/m3db/m3/src/x/context"
@@ -64,6 +66,35 @@ func TestNamespaceIndexCleanupExpiredFilesets(t *testing.T) {
 	require.NoError(t, idx.CleanupExpiredFileSets(now))
 }
 
+func TestNamespaceIndexQueryValueFilterID(t *testing.T) {
+	md := testNamespaceMetadata(time.Hour, time.Hour*8)
+	nsIdx, err := newNamespaceIndex(md, testShardSet, DefaultTestOptions())
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, nsIdx.Close())
+	}()
+
+	idx := nsIdx.(*nsIndex)
+	var calls int
+	idx.AssignSeriesValueFilter(func(id ident.ID, opts index.QueryOptions) bool {
+		calls++
+		return id.String() != "foo"
+	})
+
+	// Queries without a value filter never consult the series values.
+	if filterID := idx.queryValueFilterID(index.QueryOptions{}); filterID != nil {
+		filterID(ident.StringID("foo"))
+	}
+	require.Equal(t, 0, calls)
+
+	filterID := idx.queryValueFilterID(index.QueryOptions{
+		ValueFilter: &index.ValueFilter{Type: index.ValueFilterGreater, Value: 1},
+	})
+	require.NotNil(t, filterID)
+	require.False(t, filterID(ident.StringID("foo")))
+	require.Equal(t, 1, calls)
+}
+
 func TestNamespaceIndexCleanupExpiredFilesetsWithBlocks(t *testing.T) {
 	ctrl := gomock.NewController(xtest.Reporter{T: t})
 	defer ctrl.Finish()
@@ -159,6 +190,49 @@ func TestNamespaceIndexFlushSuccess(t *testing.T) {
 	require.True(t, persistClosed)
 }
 
+func TestNamespaceIndexPersistPostingsListCaches(t *testing.T) {
+	ctrl := gomock.NewController(xtest.Reporter{T: t})
+	defer ctrl.Finish()
+
+	test := newTestIndex(t, ctrl)
+
+	now := time.Now().Truncate(test.indexBlockSize)
+	idx := test.index.(*nsIndex)
+	idx.nowFn = func() time.Time { return now }
+	indexOpts := idx.opts.IndexOptions()
+	idx.opts = idx.opts.SetIndexOptions(indexOpts.SetReadThroughSegmentOptions(
+		index.ReadThroughSegmentOptions{
+			CacheRegexp:          true,
+			CacheTerms:           true,
+			PersistCache:         true,
+			PersistCacheInterval: time.Minute,
+		}))
+
+	defer func() {
+		require.NoError(t, idx.Close())
+	}()
+
+	sealedBlock := index.NewMockBlock(ctrl)
+	sealedBlockTime := now.Add(-2 * test.indexBlockSize)
+	sealedBlock.EXPECT().IsSealed().Return(true).AnyTimes()
+	sealedBlock.EXPECT().Close().Return(nil)
+	idx.state.blocksByTime[xtime.ToUnixNano(sealedBlockTime)] = sealedBlock
+
+	openBlock := index.NewMockBlock(ctrl)
+	openBlock.EXPECT().IsSealed().Return(false).AnyTimes()
+	openBlock.EXPECT().Close().Return(nil)
+	idx.state.blocksByTime[xtime.ToUnixNano(now.Add(-test.indexBlockSize))] = openBlock
+
+	// Only sealed blocks are persisted, at most once per interval.
+	sealedBlock.EXPECT().PersistPostingsListCache(gomock.Any()).Return(nil)
+	idx.persistPostingsListCaches()
+	idx.persistPostingsListCaches()
+
+	now = now.Add(time.Minute)
+	sealedBlock.EXPECT().PersistPostingsListCache(gomock.Any()).Return(fmt.Errorf("an error"))
+	idx.persistPostingsListCaches()
+}
+
 func TestNamespaceIndexFlushShardStateNotSuccess(t *testing.T) {
 	ctrl := gomock.NewController(xtest.Reporter{T: t})
 	defer ctrl.Finish()
@@ -319,6 +393,85 @@ func TestNamespaceIndexQueryNoMatchingBlocks(t *testing.T) {
 	assert.Equal(t, 0, aggResult.Results.Size())
 }
 
+func TestNamespaceIndexSeriesDeletedFilter(t *testing.T) {
+	ctrl := gomock.NewController(xtest.Reporter{T: t})
+	defer ctrl.Finish()
+
+	test := newTestIndex(t, ctrl)
+	idx := test.index.(*nsIndex)
+	defer func() {
+		require.NoError(t, idx.Close())
+	}()
+
+	var (
+		deleted = doc.Document{ID: []byte("deleted")}
+		live    = doc.Document{ID: []byte("live")}
+	)
+	require.True(t, idx.seriesDeleted.Contains(deleted))
+	require.True(t, idx.seriesDeleted.Contains(live))
+
+	idx.AssignSeriesDeletedFilter(func(id ident.ID) bool {
+		return id.String() == "deleted"
+	})
+	require.False(t, idx.seriesDeleted.Contains(deleted))
+	require.True(t, idx.seriesDeleted.Contains(live))
+
+	filterID, hasDeleted := idx.queryFilterID()
+	require.True(t, hasDeleted)
+	require.False(t, filterID(ident.StringID("deleted")))
+
+	idx.AssignSeriesDeletedFilter(nil)
+	require.True(t, idx.seriesDeleted.Contains(deleted))
+}
+
+func TestNamespaceIndexWriteRollup(t *testing.T) {
+	ctrl := gomock.NewController(xtest.Reporter{T: t})
+	defer ctrl.Finish()
+
+	test := newTestIndex(t, ctrl)
+	idx := test.index.(*nsIndex)
+	defer func() {
+		require.NoError(t, idx.Close())
+	}()
+
+	blockTime := time.Now().Truncate(test.indexBlockSize).Add(-2 * test.indexBlockSize)
+	mockBlock := index.NewMockBlock(ctrl)
+	mockBlock.EXPECT().Stats(gomock.Any()).Return(nil).AnyTimes()
+	mockBlock.EXPECT().StartTime().Return(blockTime).AnyTimes()
+	mockBlock.EXPECT().Close().Return(nil)
+	idx.state.blocksByTime[xtime.ToUnixNano(blockTime)] = mockBlock
+
+	// Roll up the second data block of the sealed index block.
+	var (
+		dataBlockStart = blockTime.Add(test.blockSize)
+		dataBlockEnd   = dataBlockStart.Add(test.blockSize)
+	)
+	mockBlock.EXPECT().AddResults(gomock.Any()).DoAndReturn(
+		func(results result.IndexBlock) error {
+			require.True(t, blockTime.Equal(results.BlockStart()))
+			require.True(t, results.Fulfilled().Equal(
+				result.NewShardTimeRanges(dataBlockStart, dataBlockEnd, 3)))
+			require.Len(t, results.Segments(), 1)
+
+			seg := results.Segments()[0]
+			require.Equal(t, int64(2), seg.Size())
+			ok, err := seg.ContainsID([]byte("foo"))
+			require.NoError(t, err)
+			require.True(t, ok)
+			return seg.Close()
+		})
+
+	docs := []doc.Document{
+		{ID: []byte("foo"), Fields: []doc.Field{{Name: []byte("name"), Value: []byte("foo")}}},
+		{ID: []byte("bar"), Fields: []doc.Field{{Name: []byte("name"), Value: []byte("bar")}}},
+		{ID: []byte("foo"), Fields: []doc.Field{{Name: []byte("name"), Value: []byte("foo")}}},
+	}
+	require.NoError(t, idx.WriteRollup(3, dataBlockStart, dataBlockEnd, docs))
+
+	// Nothing is added to the block without any rolled up series.
+	require.NoError(t, idx.WriteRollup(3, dataBlockStart, dataBlockEnd, nil))
+}
+
 type testIndex struct {
 	index          namespaceIndex
 	metadata       namespace.Metadata
@@ -58,6 +58,7 @@ import (
 var (
 	errNamespaceAlreadyClosed    = errors.New("namespace already closed")
 	errNamespaceIndexingDisabled = errors.New("namespace indexing is disabled")
+	errDeleteTaggedNotExhaustive = errors.New("delete query did not match all series exhaustively, no series were deleted")
 )
 
 type commitLogWriter interface {
@@ -128,6 +129,7 @@ type dbNamespace struct {
 	increasingIndex increasingIndex
 	commitLogWriter commitLogWriter
 	reverseIndex    namespaceIndex
+	quotas          *namespaceQuotas
 
 	tickWorkers            xsync.WorkerPool
 	tickWorkersConcurrency int
@@ -152,7 +154,10 @@ type databaseNamespaceIndexStatsLastTick struct {
 type databaseNamespaceMetrics struct {
 	bootstrap           instrument.MethodMetrics
 	flushWarmData       instrument.MethodMetrics
+	flushRollupData     instrument.MethodMetrics
 	flushColdData       instrument.MethodMetrics
+	compactFileSets     instrument.MethodMetrics
+	dropExpiredSeries   instrument.MethodMetrics
 	flushIndex          instrument.MethodMetrics
 	snapshot            instrument.MethodMetrics
 	write               instrument.MethodMetrics
@@ -162,6 +167,8 @@ type databaseNamespaceMetrics struct {
 	fetchBlocksMetadata instrument.MethodMetrics
 	queryIDs            instrument.MethodMetrics
 	aggregateQuery      instrument.MethodMetrics
+	indexCardinality    instrument.MethodMetrics
+	deleteTagged        instrument.MethodMetrics
 	unfulfilled         tally.Counter
 	bootstrapStart      tally.Counter
 	bootstrapEnd        tally.Counter
@@ -231,7 +238,10 @@ func newDatabaseNamespaceMetrics(scope tally.Scope, samplingRate float64) databa
 	return databaseNamespaceMetrics{
 		bootstrap:           instrument.NewMethodMetrics(scope, "bootstrap", samplingRate),
 		flushWarmData:       instrument.NewMethodMetrics(scope, "flushWarmData", samplingRate),
+		flushRollupData:     instrument.NewMethodMetrics(scope, "flushRollupData", samplingRate),
 		flushColdData:       instrument.NewMethodMetrics(scope, "flushColdData", samplingRate),
+		compactFileSets:     instrument.NewMethodMetrics(scope, "compactFileSets", samplingRate),
+		dropExpiredSeries:   instrument.NewMethodMetrics(scope, "dropExpiredSeries", samplingRate),
 		flushIndex:          instrument.NewMethodMetrics(scope, "flushIndex", samplingRate),
 		snapshot:            instrument.NewMethodMetrics(scope, "snapshot", samplingRate),
 		write:               instrument.NewMethodMetrics(scope, "write", overrideWriteSamplingRate),
@@ -241,6 +251,8 @@ func newDatabaseNamespaceMetrics(scope tally.Scope, samplingRate float64) databa
 		fetchBlocksMetadata: instrument.NewMethodMetrics(scope, "fetchBlocksMetadata", samplingRate),
 		queryIDs:            instrument.NewMethodMetrics(scope, "queryIDs", samplingRate),
 		aggregateQuery:      instrument.NewMethodMetrics(scope, "aggregateQuery", samplingRate),
+		indexCardinality:    instrument.NewMethodMetrics(scope, "indexCardinality", samplingRate),
+		deleteTagged:        instrument.NewMethodMetrics(scope, "deleteTagged", samplingRate),
 		unfulfilled:         scope.Counter("bootstrap.unfulfilled"),
 		bootstrapStart:      scope.Counter("bootstrap.start"),
 		bootstrapEnd:        scope.Counter("bootstrap.end"),
@@ -302,6 +314,20 @@ func newDatabaseNamespace(
 	iops = iops.SetLogger(logger)
 	opts = opts.SetInstrumentOptions(iops)
 
+	if nopts.HistogramsEnabled() {
+		histogramEncoderPool := opts.HistogramEncoderPool()
+		if histogramEncoderPool == nil {
+			return nil, fmt.Errorf(
+				"unable to create namespace %v, histograms are not supported",
+				id.String())
+		}
+		// NB: Only the encoders differ from other namespaces, reader
+		// iterators detect whether a stream is a histogram stream.
+		opts = opts.SetEncoderPool(histogramEncoderPool).
+			SetDatabaseBlockOptions(opts.DatabaseBlockOptions().
+				SetEncoderPool(histogramEncoderPool))
+	}
+
 	scope := iops.MetricsScope().SubScope("database").
 		Tagged(map[string]string{
 			"namespace": id.String(),
@@ -313,7 +339,8 @@ func newDatabaseNamespace(
 
 	seriesOpts := NewSeriesOptionsFromOptions(opts, nopts.RetentionOptions()).
 		SetStats(series.NewStats(scope)).
-		SetColdWritesEnabled(nopts.ColdWritesEnabled())
+		SetColdWritesEnabled(nopts.ColdWritesEnabled()).
+		SetValueSummariesEnabled(nopts.ValueSummariesEnabled())
 	if err := seriesOpts.Validate(); err != nil {
 		return nil, fmt.Errorf(
 			"unable to create namespace %v, invalid series options: %v",
@@ -347,6 +374,7 @@ func newDatabaseNamespace(
 		increasingIndex:        increasingIndex,
 		commitLogWriter:        commitLogWriter,
 		reverseIndex:           index,
+		quotas:                 newNamespaceQuotas(id, opts, scope),
 		tickWorkers:            tickWorkers,
 		tickWorkersConcurrency: tickWorkersConcurrency,
 		metrics:                newDatabaseNamespaceMetrics(scope, iops.MetricsSamplingRate()),
@@ -361,6 +389,9 @@ func newDatabaseNamespace(
 			metadata.ID().String(), err)
 	}
 	n.schemaListener = sl
+	if index != nil && nopts.ValueSummariesEnabled() {
+		index.AssignSeriesValueFilter(n.seriesValueMatches)
+	}
 	n.initShards(nopts.BootstrapEnabled())
 	go n.reportStatusLoop(opts.InstrumentOptions().ReportInterval())
 
@@ -478,7 +509,7 @@ func (n *dbNamespace) AssignShardSet(shardSet sharding.ShardSet) {
 		} else {
 			bootstrapEnabled := n.nopts.BootstrapEnabled()
 			n.shards[shard] = newDatabaseShard(metadata, shard, n.blockRetriever,
-				n.namespaceReaderMgr, n.increasingIndex, n.reverseIndex,
+				n.namespaceReaderMgr, n.increasingIndex, n.reverseIndex, n.quotas,
 				bootstrapEnabled, n.opts, n.seriesOpts)
 			n.metrics.shards.add.Inc(1)
 		}
@@ -529,6 +560,9 @@ func (n *dbNamespace) Tick(c context.Cancellable, startTime time.Time) error {
 	// Allow the reader cache to tick.
 	n.namespaceReaderMgr.tick()
 
+	// Prune the quota usage of idle tenants.
+	n.quotas.tick()
+
 	// Fetch the owned shards.
 	shards := n.GetOwnedShards()
 	if len(shards) == 0 {
@@ -567,6 +601,9 @@ func (n *dbNamespace) Tick(c context.Cancellable, startTime time.Time) error {
 
 	wg.Wait()
 
+	// Tombstones may have expired since the last tick.
+	n.updateSeriesDeletedFilter()
+
 	// Tick namespaceIndex if it exists.
 	var (
 		indexTickResults namespaceIndexTickResult
@@ -629,6 +666,10 @@ func (n *dbNamespace) Write(
 		n.metrics.write.ReportError(n.nowFn().Sub(callStart))
 		return ts.Series{}, false, err
 	}
+	if err := n.quotas.checkWrite(nil, 1); err != nil {
+		n.metrics.write.ReportError(n.nowFn().Sub(callStart))
+		return ts.Series{}, false, err
+	}
 	opts := series.WriteOptions{
 		TruncateType: n.opts.TruncateType(),
 		SchemaDesc:   nsCtx.Schema,
@@ -658,6 +699,10 @@ func (n *dbNamespace) WriteTagged(
 		n.metrics.writeTagged.ReportError(n.nowFn().Sub(callStart))
 		return ts.Series{}, false, err
 	}
+	if err := n.quotas.checkWrite(tags, 1); err != nil {
+		n.metrics.writeTagged.ReportError(n.nowFn().Sub(callStart))
+		return ts.Series{}, false, err
+	}
 	opts := series.WriteOptions{
 		TruncateType: n.opts.TruncateType(),
 		SchemaDesc:   nsCtx.Schema,
@@ -722,7 +767,17 @@ func (n *dbNamespace) QueryIDs(
 			xerrors.NewRetryableError(err)
 	}
 
+	maxDocs, maxDocsErr := n.quotas.queryDocsLimit(query)
+	if maxDocs > 0 && (opts.Limit <= 0 || int64(opts.Limit) > maxDocs) {
+		// Query one more than the quota to detect when it is exceeded.
+		opts.Limit = int(maxDocs) + 1
+	}
+
 	res, err := n.reverseIndex.Query(ctx, query, opts)
+	if err == nil && maxDocs > 0 && int64(res.Results.Size()) > maxDocs {
+		n.quotas.queryDocsExceeded()
+		res, err = index.QueryResult{}, maxDocsErr
+	}
 	if err != nil {
 		sp.LogFields(opentracinglog.Error(err))
 	}
@@ -748,11 +803,45 @@ func (n *dbNamespace) AggregateQuery(
 			xerrors.NewRetryableError(errIndexNotBootstrappedToRead)
 	}
 
+	// NB: aggregate queries can be resolved from the terms of the index
+	// without matching documents, so they are limited by the terms returned.
+	maxTerms, maxTermsErr := n.quotas.aggregateTermsLimit(query)
+	if maxTerms > 0 && (opts.Limit <= 0 || int64(opts.Limit) > maxTerms) {
+		// Query one more than the quota to detect when it is exceeded.
+		opts.Limit = int(maxTerms) + 1
+	}
+
 	res, err := n.reverseIndex.AggregateQuery(ctx, query, opts)
+	if err == nil && maxTerms > 0 && int64(res.Results.Size()) > maxTerms {
+		n.quotas.aggregateTermsExceeded()
+		res, err = index.AggregateQueryResult{}, maxTermsErr
+	}
 	n.metrics.aggregateQuery.ReportSuccessOrError(err, n.nowFn().Sub(callStart))
 	return res, err
 }
 
+func (n *dbNamespace) IndexCardinality(
+	ctx context.Context,
+	opts index.CardinalityOptions,
+) (index.CardinalityResults, error) {
+	callStart := n.nowFn()
+	if n.reverseIndex == nil { // only happens if indexing is enabled.
+		n.metrics.indexCardinality.ReportError(n.nowFn().Sub(callStart))
+		return index.CardinalityResults{}, errNamespaceIndexingDisabled
+	}
+
+	if n.reverseIndex.BootstrapsDone() < 1 {
+		// Similar to reading shard data, return not bootstrapped
+		n.metrics.indexCardinality.ReportError(n.nowFn().Sub(callStart))
+		return index.CardinalityResults{},
+			xerrors.NewRetryableError(errIndexNotBootstrappedToRead)
+	}
+
+	res, err := n.reverseIndex.Cardinality(ctx, opts)
+	n.metrics.indexCardinality.ReportSuccessOrError(err, n.nowFn().Sub(callStart))
+	return res, err
+}
+
 func (n *dbNamespace) PrepareBootstrap() ([]databaseShard, error) {
 	var (
 		wg           sync.WaitGroup
@@ -781,6 +870,9 @@ func (n *dbNamespace) PrepareBootstrap() ([]databaseShard, error) {
 		return nil, err
 	}
 
+	// Shards load their tombstones when preparing to bootstrap.
+	n.updateSeriesDeletedFilter()
+
 	return shards, nil
 }
 
@@ -1034,6 +1126,107 @@ func (n *dbNamespace) WarmFlush(
 	return res
 }
 
+func (n *dbNamespace) RollupFlush(
+	blockStart time.Time,
+	source databaseNamespace,
+	flushPersist persist.FlushPreparer,
+) error {
+	callStart := n.nowFn()
+
+	n.RLock()
+	if n.bootstrapState != Bootstrapped {
+		n.RUnlock()
+		n.metrics.flushRollupData.ReportError(n.nowFn().Sub(callStart))
+		return errNamespaceNotBootstrapped
+	}
+	nsCtx := n.nsContextWithRLock()
+	n.RUnlock()
+
+	if !n.nopts.FlushEnabled() || !n.nopts.AggregationOptions().Enabled() {
+		n.metrics.flushRollupData.ReportSuccess(n.nowFn().Sub(callStart))
+		return nil
+	}
+
+	// check if blockStart is aligned with the namespace's retention options
+	bs := n.nopts.RetentionOptions().BlockSize()
+	if t := blockStart.Truncate(bs); !blockStart.Equal(t) {
+		return fmt.Errorf("failed to rollup at time %v, not aligned to blockSize", blockStart.String())
+	}
+
+	sourceShards := make(map[uint32]databaseShard)
+	for _, shard := range source.GetOwnedShards() {
+		sourceShards[shard.ID()] = shard
+	}
+
+	var (
+		sourceMd        = source.Metadata()
+		sourceBlockSize = sourceMd.Options().RetentionOptions().BlockSize()
+		multiErr        = xerrors.NewMultiError()
+	)
+	for _, shard := range n.GetOwnedShards() {
+		if !shard.IsBootstrapped() {
+			n.log.
+				With(zap.Uint32("shard", shard.ID())).
+				Debug("skipping rollup flush due to shard not bootstrapped yet")
+			continue
+		}
+
+		flushState, err := shard.FlushState(blockStart)
+		if err != nil {
+			return err
+		}
+		// skip flushing if the shard has already rolled up data for the `blockStart`
+		if flushState.WarmStatus == fileOpSuccess {
+			continue
+		}
+
+		sourceShard, ok := sourceShards[shard.ID()]
+		if !ok {
+			continue
+		}
+		ready, err := sourceBlocksFlushed(sourceShard, blockStart, bs, sourceBlockSize)
+		if err != nil {
+			return err
+		}
+		if !ready {
+			// Rollup once all the source blocks have been flushed, the flush
+			// state is left untouched so the next flush attempts it again.
+			continue
+		}
+
+		// Continue with remaining shards if a shard fails to rollup its data.
+		if err := shard.RollupFlush(blockStart, sourceMd, flushPersist, nsCtx); err != nil {
+			detailedErr := fmt.Errorf("shard %d failed to rollup data: %v",
+				shard.ID(), err)
+			multiErr = multiErr.Add(detailedErr)
+		}
+	}
+
+	res := multiErr.FinalError()
+	n.metrics.flushRollupData.ReportSuccessOrError(res, n.nowFn().Sub(callStart))
+	return res
+}
+
+// sourceBlocksFlushed returns whether all the blocks of the source shard that
+// fall within the aggregated block have been warm flushed.
+func sourceBlocksFlushed(
+	sourceShard databaseShard,
+	blockStart time.Time,
+	blockSize time.Duration,
+	sourceBlockSize time.Duration,
+) (bool, error) {
+	for t := blockStart; t.Before(blockStart.Add(blockSize)); t = t.Add(sourceBlockSize) {
+		flushState, err := sourceShard.FlushState(t)
+		if err != nil {
+			return false, err
+		}
+		if flushState.WarmStatus != fileOpSuccess {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
 // idAndBlockStart is the composite key for the genny map used to keep track of
 // dirty series that need to be ColdFlushed.
 type idAndBlockStart struct {
@@ -1133,6 +1326,107 @@ func (n *dbNamespace) ColdFlush(flushPersist persist.FlushPreparer) error {
 	return res
 }
 
+func (n *dbNamespace) CompactFileSets(
+	flushPersist persist.FlushPreparer,
+	limiter *compactionRateLimiter,
+) (fileSetCompactionResult, error) {
+	var result fileSetCompactionResult
+	callStart := n.nowFn()
+
+	n.RLock()
+	if n.bootstrapState != Bootstrapped {
+		n.RUnlock()
+		n.metrics.compactFileSets.ReportError(n.nowFn().Sub(callStart))
+		return result, errNamespaceNotBootstrapped
+	}
+	nsCtx := n.nsContextWithRLock()
+	n.RUnlock()
+
+	// Only cold flushes write more than one volume per block.
+	if !n.nopts.ColdWritesEnabled() && !n.nopts.RepairEnabled() {
+		n.metrics.compactFileSets.ReportSuccess(n.nowFn().Sub(callStart))
+		return result, nil
+	}
+
+	fsReader, err := fs.NewReader(n.opts.BytesPool(), n.opts.CommitLogOptions().FilesystemOptions())
+	if err != nil {
+		n.metrics.compactFileSets.ReportError(n.nowFn().Sub(callStart))
+		return result, err
+	}
+
+	var (
+		minVolumes = n.opts.CompactionMinVolumes()
+		multiErr   = xerrors.NewMultiError()
+	)
+	for _, shard := range n.GetOwnedShards() {
+		shardResult, err := shard.CompactFileSets(flushPersist, fsReader, minVolumes, limiter, nsCtx)
+		result.add(shardResult)
+		if err != nil {
+			detailedErr := fmt.Errorf("shard %d failed to compact filesets: %v", shard.ID(), err)
+			multiErr = multiErr.Add(detailedErr)
+			// Continue with remaining shards.
+		}
+	}
+
+	res := multiErr.FinalError()
+	n.metrics.compactFileSets.ReportSuccessOrError(res, n.nowFn().Sub(callStart))
+	return result, res
+}
+
+func (n *dbNamespace) DropExpiredSeries(
+	flushPersist persist.FlushPreparer,
+) (int, error) {
+	callStart := n.nowFn()
+
+	n.RLock()
+	if n.bootstrapState != Bootstrapped {
+		n.RUnlock()
+		n.metrics.dropExpiredSeries.ReportError(n.nowFn().Sub(callStart))
+		return 0, errNamespaceNotBootstrapped
+	}
+	nsCtx := n.nsContextWithRLock()
+	n.RUnlock()
+
+	// The TTLs of the series are read from the index so that blocks written
+	// before a restart are rewritten once the TTLs of their series expire.
+	var ttls []time.Duration
+	if n.reverseIndex != nil {
+		ctx := n.opts.ContextPool().Get()
+		retentionPeriod := n.nopts.RetentionOptions().RetentionPeriod()
+		indexed, err := n.reverseIndex.SeriesTTLs(ctx, callStart.Add(-retentionPeriod), callStart)
+		ctx.Close()
+		if err != nil {
+			n.metrics.dropExpiredSeries.ReportError(n.nowFn().Sub(callStart))
+			return 0, err
+		}
+		ttls = indexed
+	}
+
+	fsReader, err := fs.NewReader(n.opts.BytesPool(), n.opts.CommitLogOptions().FilesystemOptions())
+	if err != nil {
+		n.metrics.dropExpiredSeries.ReportError(n.nowFn().Sub(callStart))
+		return 0, err
+	}
+
+	var (
+		numBlocks int
+		multiErr  = xerrors.NewMultiError()
+	)
+	for _, shard := range n.GetOwnedShards() {
+		shardBlocks, err := shard.DropExpiredSeries(flushPersist, fsReader, ttls, nsCtx)
+		numBlocks += shardBlocks
+		if err != nil {
+			detailedErr := fmt.Errorf("shard %d failed to drop expired series: %v", shard.ID(), err)
+			multiErr = multiErr.Add(detailedErr)
+			// Continue with remaining shards.
+		}
+	}
+
+	res := multiErr.FinalError()
+	n.metrics.dropExpiredSeries.ReportSuccessOrError(res, n.nowFn().Sub(callStart))
+	return numBlocks, res
+}
+
 func (n *dbNamespace) FlushIndex(flush persist.IndexFlush) error {
 	callStart := n.nowFn()
 	n.RLock()
@@ -1263,6 +1557,105 @@ func (n *dbNamespace) Truncate() (int64, error) {
 	return totalNumSeries, nil
 }
 
+func (n *dbNamespace) DeleteTagged(
+	ctx context.Context,
+	query index.Query,
+	opts index.QueryOptions,
+) (int64, error) {
+	callStart := n.nowFn()
+	// NB: a delete must cover every series matching the query, deleting only
+	// the series that fit within a limit would leave the rest behind.
+	opts.Limit = 0
+	result, err := n.QueryIDs(ctx, query, opts)
+	if err == nil && !result.Exhaustive {
+		err = xerrors.NewInvalidParamsError(errDeleteTaggedNotExhaustive)
+	}
+	if err != nil {
+		n.metrics.deleteTagged.ReportError(n.nowFn().Sub(callStart))
+		return 0, err
+	}
+
+	var (
+		deletedAt  = n.nowFn()
+		idsByShard = make(map[uint32][]ident.ID)
+		numSeries  int64
+	)
+	n.RLock()
+	for _, entry := range result.Results.Map().Iter() {
+		id := entry.Key()
+		shardID := n.shardSet.Lookup(id)
+		idsByShard[shardID] = append(idsByShard[shardID], id)
+		numSeries++
+	}
+	n.RUnlock()
+
+	multiErr := xerrors.NewMultiError()
+	for shardID, ids := range idsByShard {
+		shard, _, err := n.readableShardAt(shardID)
+		if err != nil {
+			multiErr = multiErr.Add(err)
+			continue
+		}
+		if err := shard.DeleteSeries(ids, deletedAt); err != nil {
+			multiErr = multiErr.Add(err)
+		}
+	}
+
+	n.updateSeriesDeletedFilter()
+
+	err = multiErr.FinalError()
+	n.metrics.deleteTagged.ReportSuccessOrError(err, n.nowFn().Sub(callStart))
+	if err != nil {
+		return 0, err
+	}
+	return numSeries, nil
+}
+
+// updateSeriesDeletedFilter enables filtering deleted series out of index
+// query results and index segments as they are compacted or flushed only
+// while any owned shard has series tombstones, since filtering requires a
+// lookup of the series per document.
+func (n *dbNamespace) updateSeriesDeletedFilter() {
+	idx := n.reverseIndex
+	if idx == nil {
+		return
+	}
+
+	hasDeleted := false
+	for _, shard := range n.GetOwnedShards() {
+		if shard.NumDeletedSeries() > 0 {
+			hasDeleted = true
+			break
+		}
+	}
+
+	if !hasDeleted {
+		idx.AssignSeriesDeletedFilter(nil)
+		return
+	}
+	idx.AssignSeriesDeletedFilter(n.seriesDeleted)
+}
+
+func (n *dbNamespace) seriesDeleted(id ident.ID) bool {
+	shard, _, err := n.readableShardFor(id)
+	if err != nil {
+		return false
+	}
+	return shard.SeriesDeleted(id)
+}
+
+func (n *dbNamespace) seriesValueMatches(id ident.ID, opts index.QueryOptions) bool {
+	if opts.ValueFilter == nil {
+		return true
+	}
+	shard, _, err := n.readableShardFor(id)
+	if err != nil {
+		return true
+	}
+	return shard.SeriesValueMatches(id, *opts.ValueFilter,
+		opts.StartInclusive, opts.EndExclusive)
+}
+
 func (n *dbNamespace) Repair(
 	repairer databaseShardRepairer,
 	tr xtime.Range,
@@ -1427,7 +1820,7 @@ func (n *dbNamespace) initShards(needBootstrap bool) {
 	dbShards := make([]databaseShard, n.shardSet.Max()+1)
 	for _, shard := range shards {
 		dbShards[shard] = newDatabaseShard(n.metadata, shard, n.blockRetriever,
-			n.namespaceReaderMgr, n.increasingIndex, n.reverseIndex,
+			n.namespaceReaderMgr, n.increasingIndex, n.reverseIndex, n.quotas,
 			needBootstrap, n.opts, n.seriesOpts)
 	}
 	n.shards = dbShards
@@ -1448,6 +1841,7 @@ func (n *dbNamespace) Close() error {
 	n.namespaceReaderMgr.close()
 	n.closeShards(shards, true)
 	close(n.shutdownCh)
+	n.quotas.Close()
 	if n.reverseIndex != nil {
 		return n.reverseIndex.Close()
 	}
@@ -195,7 +195,17 @@ func (m *namespaceReaderManager) filesetExistsAt(
 		return false, err
 	}
 
-	return m.filesetExistsFn(m.fsOpts.FilePathPrefix(),
+	exists, err := m.filesetExistsFn(m.fsOpts.FilePathPrefix(),
+		m.namespace.ID(), shard, blockStart, latestVolume)
+	if err != nil || exists {
+		return exists, err
+	}
+
+	coldTierOpts := m.namespace.Options().ColdTierOptions()
+	if !coldTierOpts.Enabled() {
+		return false, nil
+	}
+	return m.filesetExistsFn(coldTierOpts.FilePathPrefix(),
 		m.namespace.ID(), shard, blockStart, latestVolume)
 }
 
@@ -309,6 +319,7 @@ func (m *namespaceReaderManager) get(
 			BlockStart:  blockStart,
 			VolumeIndex: latestVolume,
 		},
+		ColdTierFilePathPrefix: m.namespace.Options().ColdTierOptions().FilePathPrefix(),
 	}
 	if err := reader.Open(openOpts); err != nil {
 		return nil, err
@@ -35,6 +35,7 @@ import (
 	"github.com/m3db/m3/src/dbnode/sharding"
 	"github.com/m3db/m3/src/dbnode/storage/bootstrap"
 	"github.com/m3db/m3/src/dbnode/storage/bootstrap/result"
+	dberrors "github.com/m3db/m3/src/dbnode/storage/errors"
 	"github.com/m3db/m3/src/dbnode/storage/index"
 	"github.com/m3db/m3/src/dbnode/storage/repair"
 	"github.com/m3db/m3/src/dbnode/storage/series"
@@ -143,6 +144,31 @@ func TestNamespaceName(t *testing.T) {
 	require.True(t, defaultTestNs1ID.Equal(ns.ID()))
 }
 
+func TestNamespaceHistogramsEnabled(t *testing.T) {
+	nopts := defaultTestNs1Opts.SetHistogramsEnabled(true)
+	ns, closer := newTestNamespaceWithIDOpts(t, defaultTestNs1ID, nopts)
+	defer closer()
+
+	histogramEncoderPool := ns.opts.HistogramEncoderPool()
+	require.NotNil(t, histogramEncoderPool)
+	require.True(t, histogramEncoderPool == ns.opts.EncoderPool())
+	require.True(t, histogramEncoderPool == ns.seriesOpts.EncoderPool())
+	require.True(t, histogramEncoderPool ==
+		ns.seriesOpts.DatabaseBlockOptions().EncoderPool())
+
+	// Namespaces with histograms enabled cannot be created without a
+	// histogram encoder pool.
+	metadata := newTestNamespaceMetadataWithIDOpts(t, defaultTestNs1ID, nopts)
+	shardSet, err := sharding.NewShardSet(testShardIDs, sharding.DefaultHashFn(1))
+	require.NoError(t, err)
+	dopts := DefaultTestOptions().
+		SetRuntimeOptionsManager(runtime.NewOptionsManager()).
+		SetHistogramEncoderPool(nil)
+	defer dopts.RuntimeOptionsManager().Close()
+	_, err = newDatabaseNamespace(metadata, shardSet, nil, nil, nil, dopts)
+	require.Error(t, err)
+}
+
 func TestNamespaceTick(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -237,6 +263,45 @@ func TestNamespaceWriteShardOwned(t *testing.T) {
 	}
 }
 
+func TestNamespaceWriteQuotaExceeded(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.NewContext()
+	defer ctx.Close()
+
+	ns, closer := newTestNamespace(t)
+	defer closer()
+
+	ns.quotas.SetRuntimeOptions(runtime.NewOptions().
+		SetQuotaOptions(runtime.NewQuotaOptions().
+			SetNamespaceQuotas(map[string]runtime.Quota{
+				ns.ID().String(): {DatapointsPerSecond: 1},
+			})))
+
+	var (
+		id   = ident.StringID("foo")
+		now  = time.Now()
+		opts = series.WriteOptions{TruncateType: series.TypeNone}
+	)
+	ns.quotas.nowFn = func() time.Time { return now }
+
+	shard := NewMockdatabaseShard(ctrl)
+	shard.EXPECT().Write(ctx, id, now, 0.0, xtime.Second, nil, opts).
+		Return(ts.Series{}, true, nil).Times(1)
+	ns.shards[testShardIDs[0].ID()] = shard
+
+	_, wasWritten, err := ns.Write(ctx, id, now, 0.0, xtime.Second, nil)
+	require.NoError(t, err)
+	require.True(t, wasWritten)
+
+	_, wasWritten, err = ns.Write(ctx, id, now, 0.0, xtime.Second, nil)
+	require.Error(t, err)
+	require.True(t, dberrors.IsQuotaExceededError(err))
+	require.True(t, xerrors.IsRetryableError(err))
+	require.False(t, wasWritten)
+}
+
 func TestNamespaceReadEncodedShardNotOwned(t *testing.T) {
 	ctx := context.NewContext()
 	defer ctx.Close()
@@ -1150,6 +1215,35 @@ func TestNamespaceIndexQuery(t *testing.T) {
 	assert.Equal(t, "root", spans[1].OperationName)
 }
 
+func TestNamespaceDeleteTaggedNotExhaustive(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	idx := NewMocknamespaceIndex(ctrl)
+	idx.EXPECT().BootstrapsDone().Return(uint(1))
+
+	ns, closer := newTestNamespaceWithIndex(t, idx)
+	defer closer()
+
+	ctx := context.NewContext()
+	defer ctx.Close()
+
+	query := index.Query{
+		Query: xidx.NewTermQuery([]byte("foo"), []byte("bar")),
+	}
+
+	// The caller's limit is not applied to deletes.
+	idx.EXPECT().Query(gomock.Any(), query, index.QueryOptions{}).
+		Return(index.QueryResult{Exhaustive: false}, nil)
+	deleted, err := ns.DeleteTagged(ctx, query, index.QueryOptions{Limit: 10})
+	require.Error(t, err)
+	require.True(t, xerrors.IsInvalidParams(err))
+	require.Equal(t, int64(0), deleted)
+
+	idx.EXPECT().Close().Return(nil)
+	require.NoError(t, ns.Close())
+}
+
 func TestNamespaceAggregateQuery(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -30,11 +30,13 @@ import (
 
 	"github.com/m3db/m3/src/dbnode/clock"
 	"github.com/m3db/m3/src/dbnode/encoding"
+	"github.com/m3db/m3/src/dbnode/encoding/histogram"
 	"github.com/m3db/m3/src/dbnode/encoding/m3tsz"
 	"github.com/m3db/m3/src/dbnode/namespace"
 	"github.com/m3db/m3/src/dbnode/persist"
 	"github.com/m3db/m3/src/dbnode/persist/fs"
 	"github.com/m3db/m3/src/dbnode/persist/fs/commitlog"
+	"github.com/m3db/m3/src/dbnode/ratelimit"
 	"github.com/m3db/m3/src/dbnode/retention"
 	m3dbruntime "github.com/m3db/m3/src/dbnode/runtime"
 	"github.com/m3db/m3/src/dbnode/storage/block"
@@ -75,6 +77,10 @@ const (
 	// defaultNumLoadedBytesLimit is the default limit (2GiB) for the number of outstanding loaded bytes that
 	// the memory tracker will allow.
 	defaultNumLoadedBytesLimit = 2 << 30
+
+	// defaultCompactionMinVolumes is the default minimum number of volumes on disk
+	// for a block before they are compacted into a single volume.
+	defaultCompactionMinVolumes = 4
 )
 
 var (
@@ -142,6 +148,7 @@ type options struct {
 	seriesPool                     series.DatabaseSeriesPool
 	bytesPool                      pool.CheckedBytesPool
 	encoderPool                    encoding.EncoderPool
+	histogramEncoderPool           encoding.EncoderPool
 	segmentReaderPool              xio.SegmentReaderPool
 	readerIteratorPool             encoding.ReaderIteratorPool
 	multiReaderIteratorPool        encoding.MultiReaderIteratorPool
@@ -158,6 +165,8 @@ type options struct {
 	blockLeaseManager              block.LeaseManager
 	memoryTracker                  MemoryTracker
 	mmapReporter                   mmap.Reporter
+	compactionMinVolumes           int
+	compactionRateLimitOpts        ratelimit.Options
 }
 
 // NewOptions creates a new set of storage options with defaults
@@ -228,6 +237,8 @@ func newOptions(poolOpts pool.ObjectPoolOptions) Options {
 		checkedBytesWrapperPool:        bytesWrapperPool,
 		schemaReg:                      namespace.NewSchemaRegistry(false, nil),
 		memoryTracker:                  NewMemoryTracker(NewMemoryTrackerOptions(defaultNumLoadedBytesLimit)),
+		compactionMinVolumes:           defaultCompactionMinVolumes,
+		compactionRateLimitOpts:        ratelimit.NewOptions(),
 	}
 	return o.SetEncodingM3TSZPooled()
 }
@@ -462,16 +473,25 @@ func (o *options) SetEncodingM3TSZPooled() Options {
 	})
 	opts.encoderPool = encoderPool
 
-	// initialize single reader iterator pool
+	// initialize histogram encoder pool
+	histogramEncoderPool := encoding.NewEncoderPool(opts.poolOpts)
+	histogramEncodingOpts := encodingOpts.SetEncoderPool(histogramEncoderPool)
+	histogramEncoderPool.Init(func() encoding.Encoder {
+		return histogram.NewEncoder(timeZero, histogramEncodingOpts)
+	})
+	opts.histogramEncoderPool = histogramEncoderPool
+
+	// initialize single reader iterator pool, the histogram iterator reads
+	// both histogram and M3TSZ streams
 	readerIteratorPool.Init(func(r io.Reader, descr namespace.SchemaDescr) encoding.ReaderIterator {
-		return m3tsz.NewReaderIterator(r, m3tsz.DefaultIntOptimizationEnabled, encodingOpts)
+		return histogram.NewIterator(r, descr, encodingOpts)
 	})
 	opts.readerIteratorPool = readerIteratorPool
 
 	// initialize multi reader iterator pool
 	multiReaderIteratorPool := encoding.NewMultiReaderIteratorPool(opts.poolOpts)
 	multiReaderIteratorPool.Init(func(r io.Reader, descr namespace.SchemaDescr) encoding.ReaderIterator {
-		return m3tsz.NewReaderIterator(r, m3tsz.DefaultIntOptimizationEnabled, encodingOpts)
+		return histogram.NewIterator(r, descr, encodingOpts)
 	})
 	opts.multiReaderIteratorPool = multiReaderIteratorPool
 
@@ -595,6 +615,16 @@ func (o *options) EncoderPool() encoding.EncoderPool {
 	return o.encoderPool
 }
 
+func (o *options) SetHistogramEncoderPool(value encoding.EncoderPool) Options {
+	opts := *o
+	opts.histogramEncoderPool = value
+	return &opts
+}
+
+func (o *options) HistogramEncoderPool() encoding.EncoderPool {
+	return o.histogramEncoderPool
+}
+
 func (o *options) SetSegmentReaderPool(value xio.SegmentReaderPool) Options {
 	opts := *o
 	opts.segmentReaderPool = value
@@ -746,6 +776,26 @@ func (o *options) MemoryTracker() MemoryTracker {
 	return o.memoryTracker
 }
 
+func (o *options) SetCompactionMinVolumes(value int) Options {
+	opts := *o
+	opts.compactionMinVolumes = value
+	return &opts
+}
+
+func (o *options) CompactionMinVolumes() int {
+	return o.compactionMinVolumes
+}
+
+func (o *options) SetCompactionRateLimitOptions(value ratelimit.Options) Options {
+	opts := *o
+	opts.compactionRateLimitOpts = value
+	return &opts
+}
+
+func (o *options) CompactionRateLimitOptions() ratelimit.Options {
+	return o.compactionRateLimitOpts
+}
+
 func (o *options) SetMmapReporter(mmapReporter mmap.Reporter) Options {
 	opts := *o
 	opts.mmapReporter = mmapReporter
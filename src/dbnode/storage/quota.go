@@ -0,0 +1,497 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/clock"
+	"github.com/m3db/m3/src/dbnode/runtime"
+	dberrors "github.com/m3db/m3/src/dbnode/storage/errors"
+	"github.com/m3db/m3/src/dbnode/storage/index"
+	"github.com/m3db/m3/src/m3ninx/generated/proto/querypb"
+	xclose "github.com/m3db/m3/src/x/close"
+	"github.com/m3db/m3/src/x/ident"
+
+	"github.com/uber-go/tally"
+)
+
+const (
+	quotaScopeNamespace = "namespace"
+	quotaScopeTenant    = "tenant"
+
+	quotaLimitDatapoints     = "datapoints per second"
+	quotaLimitNewSeries      = "new series per second"
+	quotaLimitMaxSeries      = "max series"
+	quotaLimitQueryDocs      = "max query docs"
+	quotaLimitAggregateTerms = "max aggregate query terms"
+
+	// tenantUsageIdleTimeout is how long the usage of a tenant without series
+	// is kept after its last write before it is pruned.
+	tenantUsageIdleTimeout = time.Minute
+)
+
+// quotaUsage tracks the usage of a namespace or a tenant within the current
+// one second window. The counters are updated atomically, the counts within
+// a window are approximate around the time the window rolls.
+type quotaUsage struct {
+	windowNanos int64
+	datapoints  int64
+	newSeries   int64
+	series      int64
+}
+
+func (u *quotaUsage) rollWindow(windowNanos int64) {
+	curr := atomic.LoadInt64(&u.windowNanos)
+	if curr < windowNanos &&
+		atomic.CompareAndSwapInt64(&u.windowNanos, curr, windowNanos) {
+		atomic.StoreInt64(&u.datapoints, 0)
+		atomic.StoreInt64(&u.newSeries, 0)
+	}
+}
+
+// tryConsume adds delta to the counter unless the counter would exceed the
+// limit, a zero limit is not enforced.
+func tryConsume(counter *int64, delta int64, limit int64) bool {
+	if atomic.AddInt64(counter, delta) > limit && limit > 0 {
+		atomic.AddInt64(counter, -delta)
+		return false
+	}
+	return true
+}
+
+type namespaceQuotasMetrics struct {
+	datapointsExceeded     tally.Counter
+	newSeriesExceeded      tally.Counter
+	maxSeriesExceeded      tally.Counter
+	queryDocsExceeded      tally.Counter
+	aggregateTermsExceeded tally.Counter
+}
+
+func newNamespaceQuotasMetrics(scope tally.Scope) namespaceQuotasMetrics {
+	exceededScope := scope.SubScope("quota")
+	return namespaceQuotasMetrics{
+		datapointsExceeded: exceededScope.Tagged(map[string]string{
+			"limit": "datapoints",
+		}).Counter("exceeded"),
+		newSeriesExceeded: exceededScope.Tagged(map[string]string{
+			"limit": "new-series",
+		}).Counter("exceeded"),
+		maxSeriesExceeded: exceededScope.Tagged(map[string]string{
+			"limit": "max-series",
+		}).Counter("exceeded"),
+		queryDocsExceeded: exceededScope.Tagged(map[string]string{
+			"limit": "query-docs",
+		}).Counter("exceeded"),
+		aggregateTermsExceeded: exceededScope.Tagged(map[string]string{
+			"limit": "aggregate-terms",
+		}).Counter("exceeded"),
+	}
+}
+
+// quotasConfig is a snapshot of the quotas of a namespace, it is replaced as
+// a whole when the runtime options change and never modified.
+type quotasConfig struct {
+	tenantTag          []byte
+	namespaceQuota     runtime.Quota
+	tenantQuotas       map[string]runtime.Quota
+	defaultTenantQuota runtime.Quota
+
+	// datapointsLimited and seriesLimited are set when any datapoints or
+	// series limit is enforced, writes skip the quotas entirely otherwise.
+	datapointsLimited bool
+	seriesLimited     bool
+}
+
+func newQuotasConfig(namespace string, opts runtime.QuotaOptions) *quotasConfig {
+	c := &quotasConfig{
+		tenantTag:          []byte(opts.TenantTag()),
+		namespaceQuota:     opts.NamespaceQuotas()[namespace],
+		tenantQuotas:       opts.TenantQuotas(),
+		defaultTenantQuota: opts.DefaultTenantQuota(),
+	}
+
+	quotas := []runtime.Quota{c.namespaceQuota}
+	if len(c.tenantTag) > 0 {
+		quotas = append(quotas, c.defaultTenantQuota)
+		for _, quota := range c.tenantQuotas {
+			quotas = append(quotas, quota)
+		}
+	}
+	for _, quota := range quotas {
+		c.datapointsLimited = c.datapointsLimited || quota.DatapointsPerSecond > 0
+		c.seriesLimited = c.seriesLimited || quota.NewSeriesPerSecond > 0 ||
+			quota.MaxSeries > 0
+	}
+	return c
+}
+
+func (c *quotasConfig) tenantQuota(tenant []byte) runtime.Quota {
+	if quota, ok := c.tenantQuotas[string(tenant)]; ok {
+		return quota
+	}
+	return c.defaultTenantQuota
+}
+
+// namespaceQuotas enforces the namespace quota and the tenant quotas of a
+// single namespace, it is updated dynamically from the runtime options.
+// The series counts are maintained as series are inserted into and removed
+// from the shards of the namespace, a nil namespaceQuotas enforces nothing.
+type namespaceQuotas struct {
+	namespace string
+	nowFn     clock.NowFn
+
+	// config holds the current *quotasConfig.
+	config atomic.Value
+
+	namespaceUsage quotaUsage
+
+	// tenantUsageLock only guards the tenant usage map, the usage of a tenant
+	// is updated atomically with the read lock held so that it is not pruned
+	// while it is updated.
+	tenantUsageLock sync.RWMutex
+	tenantUsage     map[string]*quotaUsage
+
+	runtimeOptsListener xclose.SimpleCloser
+	metrics             namespaceQuotasMetrics
+}
+
+func newNamespaceQuotas(
+	namespace ident.ID,
+	opts Options,
+	scope tally.Scope,
+) *namespaceQuotas {
+	q := &namespaceQuotas{
+		namespace:   namespace.String(),
+		nowFn:       opts.ClockOptions().NowFn(),
+		tenantUsage: make(map[string]*quotaUsage),
+		metrics:     newNamespaceQuotasMetrics(scope),
+	}
+	q.config.Store(newQuotasConfig(q.namespace, runtime.NewQuotaOptions()))
+	q.runtimeOptsListener = opts.RuntimeOptionsManager().RegisterListener(q)
+	return q
+}
+
+func (q *namespaceQuotas) SetRuntimeOptions(value runtime.Options) {
+	quotaOpts := value.QuotaOptions()
+	if quotaOpts == nil {
+		quotaOpts = runtime.NewQuotaOptions()
+	}
+	q.config.Store(newQuotasConfig(q.namespace, quotaOpts))
+}
+
+func (q *namespaceQuotas) currConfig() *quotasConfig {
+	return q.config.Load().(*quotasConfig)
+}
+
+// withTenantUsage calls fn with the usage of the tenant while holding the
+// read lock of the tenant usage map.
+func (q *namespaceQuotas) withTenantUsage(tenant []byte, fn func(usage *quotaUsage)) {
+	q.tenantUsageLock.RLock()
+	usage, ok := q.tenantUsage[string(tenant)]
+	if ok {
+		fn(usage)
+		q.tenantUsageLock.RUnlock()
+		return
+	}
+	q.tenantUsageLock.RUnlock()
+
+	q.tenantUsageLock.Lock()
+	usage, ok = q.tenantUsage[string(tenant)]
+	if !ok {
+		usage = &quotaUsage{}
+		q.tenantUsage[string(tenant)] = usage
+	}
+	fn(usage)
+	q.tenantUsageLock.Unlock()
+}
+
+// tenantFromTagsIter returns the value of the tenant tag, the iterator
+// passed is duplicated so its position is not modified.
+func (q *namespaceQuotas) tenantFromTagsIter(
+	tenantTag []byte,
+	tags ident.TagIterator,
+) []byte {
+	if len(tenantTag) == 0 || tags == nil {
+		return nil
+	}
+
+	iter := tags.Duplicate()
+	defer iter.Close()
+	for iter.Next() {
+		tag := iter.Current()
+		if bytes.Equal(tag.Name.Bytes(), tenantTag) {
+			return append([]byte(nil), tag.Value.Bytes()...)
+		}
+	}
+	return nil
+}
+
+func (q *namespaceQuotas) tenantFromTags(tenantTag []byte, tags ident.Tags) []byte {
+	if len(tenantTag) == 0 {
+		return nil
+	}
+	for _, tag := range tags.Values() {
+		if bytes.Equal(tag.Name.Bytes(), tenantTag) {
+			return tag.Value.Bytes()
+		}
+	}
+	return nil
+}
+
+func (q *namespaceQuotas) windowNanos() int64 {
+	return q.nowFn().Truncate(time.Second).UnixNano()
+}
+
+// checkWrite checks and consumes the datapoints per second quotas for the
+// given number of datapoints, tags may be nil for untagged writes.
+func (q *namespaceQuotas) checkWrite(tags ident.TagIterator, datapoints int64) error {
+	if q == nil {
+		return nil
+	}
+
+	config := q.currConfig()
+	if !config.datapointsLimited {
+		return nil
+	}
+
+	windowNanos := q.windowNanos()
+	q.namespaceUsage.rollWindow(windowNanos)
+	if !tryConsume(&q.namespaceUsage.datapoints, datapoints,
+		config.namespaceQuota.DatapointsPerSecond) {
+		q.metrics.datapointsExceeded.Inc(1)
+		return dberrors.NewQuotaExceededError(quotaScopeNamespace,
+			q.namespace, quotaLimitDatapoints)
+	}
+
+	tenant := q.tenantFromTagsIter(config.tenantTag, tags)
+	if len(tenant) == 0 {
+		return nil
+	}
+
+	var consumed bool
+	q.withTenantUsage(tenant, func(usage *quotaUsage) {
+		usage.rollWindow(windowNanos)
+		consumed = tryConsume(&usage.datapoints, datapoints,
+			config.tenantQuota(tenant).DatapointsPerSecond)
+	})
+	if !consumed {
+		atomic.AddInt64(&q.namespaceUsage.datapoints, -datapoints)
+		q.metrics.datapointsExceeded.Inc(1)
+		return dberrors.NewQuotaExceededError(quotaScopeTenant,
+			string(tenant), quotaLimitDatapoints)
+	}
+	return nil
+}
+
+// checkNewSeries checks and consumes the new series per second quotas and
+// checks the max series quotas before a new series is inserted.
+func (q *namespaceQuotas) checkNewSeries(tags ident.TagIterator) error {
+	if q == nil {
+		return nil
+	}
+
+	config := q.currConfig()
+	if !config.seriesLimited {
+		return nil
+	}
+
+	windowNanos := q.windowNanos()
+	q.namespaceUsage.rollWindow(windowNanos)
+	if err := q.consumeNewSeries(quotaScopeNamespace, q.namespace,
+		config.namespaceQuota, &q.namespaceUsage); err != nil {
+		return err
+	}
+
+	tenant := q.tenantFromTagsIter(config.tenantTag, tags)
+	if len(tenant) == 0 {
+		return nil
+	}
+
+	var err error
+	q.withTenantUsage(tenant, func(usage *quotaUsage) {
+		usage.rollWindow(windowNanos)
+		err = q.consumeNewSeries(quotaScopeTenant, string(tenant),
+			config.tenantQuota(tenant), usage)
+	})
+	if err != nil {
+		atomic.AddInt64(&q.namespaceUsage.newSeries, -1)
+		return err
+	}
+	return nil
+}
+
+func (q *namespaceQuotas) consumeNewSeries(
+	scope string,
+	name string,
+	quota runtime.Quota,
+	usage *quotaUsage,
+) error {
+	if !tryConsume(&usage.newSeries, 1, quota.NewSeriesPerSecond) {
+		q.metrics.newSeriesExceeded.Inc(1)
+		return dberrors.NewQuotaExceededError(scope, name, quotaLimitNewSeries)
+	}
+	if limit := quota.MaxSeries; limit > 0 && atomic.LoadInt64(&usage.series) >= limit {
+		atomic.AddInt64(&usage.newSeries, -1)
+		q.metrics.maxSeriesExceeded.Inc(1)
+		return dberrors.NewQuotaExceededError(scope, name, quotaLimitMaxSeries)
+	}
+	return nil
+}
+
+// seriesInserted is called when a series is inserted into a shard.
+func (q *namespaceQuotas) seriesInserted(tags ident.Tags) {
+	q.addSeries(tags, 1)
+}
+
+// seriesRemoved is called when a series is removed from a shard.
+func (q *namespaceQuotas) seriesRemoved(tags ident.Tags) {
+	q.addSeries(tags, -1)
+}
+
+// addSeries maintains the series counts even if no series limit is enforced
+// so that the counts are accurate once one is.
+func (q *namespaceQuotas) addSeries(tags ident.Tags, delta int64) {
+	if q == nil {
+		return
+	}
+
+	atomic.AddInt64(&q.namespaceUsage.series, delta)
+	tenant := q.tenantFromTags(q.currConfig().tenantTag, tags)
+	if len(tenant) == 0 {
+		return
+	}
+	q.withTenantUsage(tenant, func(usage *quotaUsage) {
+		atomic.AddInt64(&usage.series, delta)
+	})
+}
+
+// tick prunes the usage of tenants which hold no series and have not been
+// written to recently.
+func (q *namespaceQuotas) tick() {
+	if q == nil {
+		return
+	}
+
+	idleBeforeNanos := q.nowFn().Add(-tenantUsageIdleTimeout).UnixNano()
+	q.tenantUsageLock.Lock()
+	for tenant, usage := range q.tenantUsage {
+		if atomic.LoadInt64(&usage.series) == 0 &&
+			atomic.LoadInt64(&usage.windowNanos) < idleBeforeNanos {
+			delete(q.tenantUsage, tenant)
+		}
+	}
+	q.tenantUsageLock.Unlock()
+}
+
+// queryDocsLimit returns the max number of index documents the query can
+// match and the error to return if the query matches more documents, a zero
+// limit specifies that the query is not limited. The tenant of a query is
+// resolved from a term on the tenant tag at the top level of the query.
+func (q *namespaceQuotas) queryDocsLimit(query index.Query) (int64, error) {
+	return q.queryLimit(query, quotaLimitQueryDocs, func(quota runtime.Quota) int64 {
+		return quota.MaxQueryDocs
+	})
+}
+
+// aggregateTermsLimit returns the max number of terms an aggregate query can
+// return and the error to return if the query returns more terms, a zero
+// limit specifies that the query is not limited.
+func (q *namespaceQuotas) aggregateTermsLimit(query index.Query) (int64, error) {
+	return q.queryLimit(query, quotaLimitAggregateTerms, func(quota runtime.Quota) int64 {
+		return quota.MaxAggregateQueryTerms
+	})
+}
+
+func (q *namespaceQuotas) queryLimit(
+	query index.Query,
+	limitName string,
+	limitFn func(quota runtime.Quota) int64,
+) (int64, error) {
+	if q == nil {
+		return 0, nil
+	}
+
+	var (
+		config = q.currConfig()
+		tenant = q.tenantFromQuery(config.tenantTag, query)
+		limit  = limitFn(config.namespaceQuota)
+		scope  = quotaScopeNamespace
+		name   = q.namespace
+	)
+	if len(tenant) > 0 {
+		tenantLimit := limitFn(config.tenantQuota(tenant))
+		if tenantLimit > 0 && (limit == 0 || tenantLimit < limit) {
+			limit = tenantLimit
+			scope = quotaScopeTenant
+			name = string(tenant)
+		}
+	}
+	if limit == 0 {
+		return 0, nil
+	}
+	return limit, dberrors.NewQuotaExceededError(scope, name,
+		fmt.Sprintf("%s of %d", limitName, limit))
+}
+
+func (q *namespaceQuotas) tenantFromQuery(
+	tenantTag []byte,
+	query index.Query,
+) []byte {
+	if len(tenantTag) == 0 || query.Query.SearchQuery() == nil {
+		return nil
+	}
+
+	pb := query.Query.SearchQuery().ToProto()
+	queries := []*querypb.Query{pb}
+	if conj := pb.GetConjunction(); conj != nil {
+		queries = conj.GetQueries()
+	}
+	for _, sub := range queries {
+		term := sub.GetTerm()
+		if term != nil && bytes.Equal(term.GetField(), tenantTag) {
+			return term.GetTerm()
+		}
+	}
+	return nil
+}
+
+// queryDocsExceeded records that a query matched more documents than allowed.
+func (q *namespaceQuotas) queryDocsExceeded() {
+	q.metrics.queryDocsExceeded.Inc(1)
+}
+
+// aggregateTermsExceeded records that an aggregate query returned more terms
+// than allowed.
+func (q *namespaceQuotas) aggregateTermsExceeded() {
+	q.metrics.aggregateTermsExceeded.Inc(1)
+}
+
+func (q *namespaceQuotas) Close() {
+	if q == nil {
+		return
+	}
+	q.runtimeOptsListener.Close()
+}
@@ -0,0 +1,243 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/runtime"
+	dberrors "github.com/m3db/m3/src/dbnode/storage/errors"
+	"github.com/m3db/m3/src/dbnode/storage/index"
+	"github.com/m3db/m3/src/m3ninx/idx"
+	"github.com/m3db/m3/src/x/ident"
+
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
+)
+
+func newTestNamespaceQuotas(
+	t *testing.T,
+	quotaOpts runtime.QuotaOptions,
+) (*namespaceQuotas, *time.Time, closerFn) {
+	now := time.Now().Truncate(time.Second)
+	opts := DefaultTestOptions().
+		SetRuntimeOptionsManager(runtime.NewOptionsManager())
+	opts = opts.SetClockOptions(opts.ClockOptions().SetNowFn(func() time.Time {
+		return now
+	}))
+
+	q := newNamespaceQuotas(ident.StringID("ns"), opts, tally.NoopScope)
+	q.SetRuntimeOptions(runtime.NewOptions().SetQuotaOptions(quotaOpts))
+	return q, &now, func() {
+		q.Close()
+		opts.RuntimeOptionsManager().Close()
+	}
+}
+
+func newTestTenantTags(tenant string) ident.Tags {
+	return ident.NewTags(
+		ident.StringTag("tenant", tenant),
+		ident.StringTag("name", "cpu"),
+	)
+}
+
+func TestNamespaceQuotasNilEnforcesNothing(t *testing.T) {
+	var q *namespaceQuotas
+	require.NoError(t, q.checkWrite(nil, 1))
+	require.NoError(t, q.checkNewSeries(nil))
+	q.seriesInserted(ident.Tags{})
+	q.seriesRemoved(ident.Tags{})
+	limit, err := q.queryDocsLimit(index.Query{})
+	require.Equal(t, int64(0), limit)
+	require.NoError(t, err)
+	limit, err = q.aggregateTermsLimit(index.Query{})
+	require.Equal(t, int64(0), limit)
+	require.NoError(t, err)
+	q.tick()
+	q.Close()
+}
+
+func TestNamespaceQuotasWithoutLimitsSkipUsage(t *testing.T) {
+	q, _, closer := newTestNamespaceQuotas(t, runtime.NewQuotaOptions().
+		SetTenantTag("tenant").
+		SetNamespaceQuotas(map[string]runtime.Quota{
+			"ns": {MaxQueryDocs: 100},
+		}))
+	defer closer()
+
+	foo := ident.NewTagsIterator(newTestTenantTags("foo"))
+	require.NoError(t, q.checkWrite(foo, 1))
+	require.NoError(t, q.checkNewSeries(foo))
+	require.Equal(t, int64(0), q.namespaceUsage.datapoints)
+	require.Equal(t, int64(0), q.namespaceUsage.newSeries)
+	require.Equal(t, 0, len(q.tenantUsage))
+
+	// Series are still counted so that the counts are accurate once a series
+	// limit is set.
+	q.seriesInserted(newTestTenantTags("foo"))
+	require.Equal(t, int64(1), q.namespaceUsage.series)
+	require.Equal(t, int64(1), q.tenantUsage["foo"].series)
+}
+
+func TestNamespaceQuotasTickPrunesIdleTenants(t *testing.T) {
+	q, now, closer := newTestNamespaceQuotas(t, runtime.NewQuotaOptions().
+		SetTenantTag("tenant").
+		SetDefaultTenantQuota(runtime.Quota{DatapointsPerSecond: 10}))
+	defer closer()
+
+	fooTags := newTestTenantTags("foo")
+	require.NoError(t, q.checkWrite(ident.NewTagsIterator(fooTags), 1))
+	require.NoError(t, q.checkWrite(ident.NewTagsIterator(newTestTenantTags("bar")), 1))
+	q.seriesInserted(fooTags)
+
+	// Tenants written to recently are kept.
+	q.tick()
+	require.Equal(t, 2, len(q.tenantUsage))
+
+	// Idle tenants are pruned unless they still hold series.
+	*now = now.Add(tenantUsageIdleTimeout + time.Second)
+	q.tick()
+	require.Equal(t, 1, len(q.tenantUsage))
+	require.Equal(t, int64(1), q.tenantUsage["foo"].series)
+
+	q.seriesRemoved(fooTags)
+	q.tick()
+	require.Equal(t, 0, len(q.tenantUsage))
+}
+
+func TestNamespaceQuotasDatapointsPerSecond(t *testing.T) {
+	q, now, closer := newTestNamespaceQuotas(t, runtime.NewQuotaOptions().
+		SetTenantTag("tenant").
+		SetNamespaceQuotas(map[string]runtime.Quota{
+			"ns": {DatapointsPerSecond: 3},
+		}).
+		SetTenantQuotas(map[string]runtime.Quota{
+			"foo": {DatapointsPerSecond: 1},
+		}))
+	defer closer()
+
+	foo := ident.NewTagsIterator(newTestTenantTags("foo"))
+	bar := ident.NewTagsIterator(newTestTenantTags("bar"))
+
+	require.NoError(t, q.checkWrite(foo, 1))
+	err := q.checkWrite(foo, 1)
+	require.Error(t, err)
+	require.True(t, dberrors.IsQuotaExceededError(err))
+
+	// The iterator position is not modified by the check.
+	require.Equal(t, 0, foo.CurrentIndex())
+
+	require.NoError(t, q.checkWrite(bar, 1))
+	require.NoError(t, q.checkWrite(nil, 1))
+	err = q.checkWrite(bar, 1)
+	require.True(t, dberrors.IsQuotaExceededError(err))
+
+	// Quotas are reset in the next window.
+	*now = now.Add(time.Second)
+	require.NoError(t, q.checkWrite(foo, 1))
+	require.NoError(t, q.checkWrite(bar, 2))
+}
+
+func TestNamespaceQuotasNewSeriesAndMaxSeries(t *testing.T) {
+	q, now, closer := newTestNamespaceQuotas(t, runtime.NewQuotaOptions().
+		SetTenantTag("tenant").
+		SetNamespaceQuotas(map[string]runtime.Quota{
+			"ns": {NewSeriesPerSecond: 2},
+		}).
+		SetDefaultTenantQuota(runtime.Quota{MaxSeries: 1}))
+	defer closer()
+
+	fooTags := newTestTenantTags("foo")
+	foo := ident.NewTagsIterator(fooTags)
+	bar := ident.NewTagsIterator(newTestTenantTags("bar"))
+
+	require.NoError(t, q.checkNewSeries(foo))
+	q.seriesInserted(fooTags)
+
+	// The default tenant quota only allows a single series per tenant.
+	err := q.checkNewSeries(foo)
+	require.True(t, dberrors.IsQuotaExceededError(err))
+
+	require.NoError(t, q.checkNewSeries(bar))
+
+	// The namespace only allows two new series per second.
+	err = q.checkNewSeries(nil)
+	require.True(t, dberrors.IsQuotaExceededError(err))
+
+	*now = now.Add(time.Second)
+	q.seriesRemoved(fooTags)
+	require.NoError(t, q.checkNewSeries(foo))
+}
+
+func TestNamespaceQuotasQueryDocsLimit(t *testing.T) {
+	q, _, closer := newTestNamespaceQuotas(t, runtime.NewQuotaOptions().
+		SetTenantTag("tenant").
+		SetNamespaceQuotas(map[string]runtime.Quota{
+			"ns": {MaxQueryDocs: 100},
+		}).
+		SetTenantQuotas(map[string]runtime.Quota{
+			"foo": {MaxQueryDocs: 10},
+		}))
+	defer closer()
+
+	limit, err := q.queryDocsLimit(index.Query{
+		Query: idx.NewTermQuery([]byte("name"), []byte("cpu")),
+	})
+	require.Equal(t, int64(100), limit)
+	require.True(t, dberrors.IsQuotaExceededError(err))
+
+	limit, err = q.queryDocsLimit(index.Query{
+		Query: idx.NewConjunctionQuery(
+			idx.NewTermQuery([]byte("tenant"), []byte("foo")),
+			idx.NewTermQuery([]byte("name"), []byte("cpu")),
+		),
+	})
+	require.Equal(t, int64(10), limit)
+	require.True(t, dberrors.IsQuotaExceededError(err))
+}
+
+func TestNamespaceQuotasAggregateTermsLimit(t *testing.T) {
+	q, _, closer := newTestNamespaceQuotas(t, runtime.NewQuotaOptions().
+		SetTenantTag("tenant").
+		SetNamespaceQuotas(map[string]runtime.Quota{
+			"ns": {MaxQueryDocs: 100, MaxAggregateQueryTerms: 20},
+		}).
+		SetTenantQuotas(map[string]runtime.Quota{
+			"foo": {MaxAggregateQueryTerms: 5},
+		}))
+	defer closer()
+
+	limit, err := q.aggregateTermsLimit(index.Query{
+		Query: idx.NewTermQuery([]byte("name"), []byte("cpu")),
+	})
+	require.Equal(t, int64(20), limit)
+	require.True(t, dberrors.IsQuotaExceededError(err))
+
+	limit, err = q.aggregateTermsLimit(index.Query{
+		Query: idx.NewConjunctionQuery(
+			idx.NewTermQuery([]byte("tenant"), []byte("foo")),
+			idx.NewTermQuery([]byte("name"), []byte("cpu")),
+		),
+	})
+	require.Equal(t, int64(5), limit)
+	require.True(t, dberrors.IsQuotaExceededError(err))
+}
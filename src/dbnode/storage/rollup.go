@@ -0,0 +1,298 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"io"
+	"time"
+
+	raggregation "github.com/m3db/m3/src/aggregator/aggregation"
+	"github.com/m3db/m3/src/dbnode/digest"
+	"github.com/m3db/m3/src/dbnode/encoding"
+	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/dbnode/storage/index/convert"
+	"github.com/m3db/m3/src/dbnode/ts"
+	"github.com/m3db/m3/src/dbnode/x/xio"
+	"github.com/m3db/m3/src/metrics/aggregation"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/x/ident"
+	xtime "github.com/m3db/m3/src/x/time"
+)
+
+// rollupWindow holds the aggregated values of a series for a single
+// resolution window.
+type rollupWindow struct {
+	start time.Time
+	unit  xtime.Unit
+	gauge raggregation.Gauge
+}
+
+// rollupSeries holds the aggregated windows of a series, in ascending order.
+type rollupSeries struct {
+	id      ident.ID
+	tags    ident.Tags
+	windows []rollupWindow
+}
+
+// shardRollup downsamples the filesets of a source namespace shard into the
+// resolution windows of an aggregated namespace block.
+type shardRollup struct {
+	opts       Options
+	reader     fs.DataFileSetReader
+	resolution time.Duration
+	aggTypes   aggregation.Types
+	gaugeOpts  raggregation.Options
+	tagOpts    models.TagOptions
+
+	series     map[string]*rollupSeries
+	ordered    []*rollupSeries
+	toFinalize []ident.ID
+}
+
+func newShardRollup(
+	opts Options,
+	aggOpts namespace.AggregationOptions,
+) (*shardRollup, error) {
+	reader, err := fs.NewReader(opts.BytesPool(),
+		opts.CommitLogOptions().FilesystemOptions())
+	if err != nil {
+		return nil, err
+	}
+
+	gaugeOpts := raggregation.NewOptions()
+	gaugeOpts.ResetSetData(aggOpts.AggregationTypes())
+	return &shardRollup{
+		opts:       opts,
+		reader:     reader,
+		resolution: aggOpts.Resolution(),
+		aggTypes:   aggOpts.AggregationTypes(),
+		gaugeOpts:  gaugeOpts,
+		tagOpts:    models.NewTagOptions().SetIDSchemeType(aggOpts.IDScheme()),
+		series:     make(map[string]*rollupSeries),
+	}, nil
+}
+
+// addVolume reads the latest volume of the source fileset for the given
+// block start and aggregates all of its datapoints.
+func (r *shardRollup) addVolume(
+	source namespace.Metadata,
+	shard uint32,
+	blockStart time.Time,
+	nsCtx namespace.Context,
+) (err error) {
+	var (
+		filePathPrefix         = r.opts.CommitLogOptions().FilesystemOptions().FilePathPrefix()
+		coldTierFilePathPrefix = source.Options().ColdTierOptions().FilePathPrefix()
+	)
+	files, err := fs.DataFiles(filePathPrefix, source.ID(), shard)
+	if err != nil {
+		return err
+	}
+	if coldTierFilePathPrefix != "" {
+		coldFiles, err := fs.DataFiles(coldTierFilePathPrefix, source.ID(), shard)
+		if err != nil {
+			return err
+		}
+		files = fs.MergeTieredDataFileSets(files, coldFiles)
+	}
+	latest, ok := files.LatestVolumeForBlock(blockStart)
+	if !ok {
+		// Nothing was flushed for this block.
+		return nil
+	}
+
+	if err := r.reader.Open(fs.DataReaderOpenOptions{
+		Identifier: fs.FileSetFileIdentifier{
+			Namespace:   source.ID(),
+			Shard:       shard,
+			BlockStart:  blockStart,
+			VolumeIndex: latest.ID.VolumeIndex,
+		},
+		FileSetType:            persist.FileSetFlushType,
+		ColdTierFilePathPrefix: coldTierFilePathPrefix,
+	}); err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := r.reader.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	iter := r.opts.ReaderIteratorPool().Get()
+	defer iter.Close()
+
+	for {
+		id, tagsIter, data, _, err := r.reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		series, ok := r.series[id.String()]
+		if !ok {
+			tags, err := convert.TagsFromTagsIter(id, tagsIter, r.opts.IdentifierPool())
+			if err != nil {
+				tagsIter.Close()
+				id.Finalize()
+				return err
+			}
+			series = &rollupSeries{id: id, tags: tags}
+			r.series[id.String()] = series
+			r.ordered = append(r.ordered, series)
+		} else {
+			r.toFinalize = append(r.toFinalize, id)
+		}
+		tagsIter.Close()
+
+		segment := ts.NewSegment(data, nil, ts.FinalizeHead)
+		iter.Reset(xio.NewSegmentReader(segment), nsCtx.Schema)
+		err = r.addDatapoints(series, iter)
+		segment.Finalize()
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (r *shardRollup) addDatapoints(
+	series *rollupSeries,
+	iter encoding.ReaderIterator,
+) error {
+	for iter.Next() {
+		dp, unit, _ := iter.Current()
+		windowStart := dp.Timestamp.Truncate(r.resolution)
+		last := len(series.windows) - 1
+		if last < 0 || series.windows[last].start.Before(windowStart) {
+			series.windows = append(series.windows, rollupWindow{
+				start: windowStart,
+				gauge: raggregation.NewGauge(r.gaugeOpts),
+			})
+			last++
+		}
+		series.windows[last].unit = unit
+		series.windows[last].gauge.Update(dp.Value)
+	}
+	return iter.Err()
+}
+
+// persist writes one series per aggregation type for every series that was
+// rolled up, tagging each of them with the aggregation type so that adding
+// an aggregation type does not change the identity of existing series.
+func (r *shardRollup) persist(
+	blockStart time.Time,
+	persistFn persist.DataFn,
+	nsCtx namespace.Context,
+	onPersist func(id ident.ID, tags ident.Tags) error,
+) error {
+	var (
+		encoderPool = r.opts.EncoderPool()
+		allocSize   = r.opts.DatabaseBlockOptions().DatabaseBlockAllocSize()
+		persisted   = make(map[string]struct{}, len(r.ordered)*len(r.aggTypes))
+	)
+	for _, series := range r.ordered {
+		if len(series.tags.Values()) == 0 {
+			// The IDs of rolled up series are derived from their tags, series
+			// without tags cannot be told apart once rolled up.
+			continue
+		}
+		for _, aggType := range r.aggTypes {
+			id, tags := rollupSeriesIDAndTags(series, aggType, r.tagOpts)
+			if _, ok := persisted[id.String()]; ok {
+				// Source series whose IDs differ but whose tags are equal
+				// roll up into the same series, the first one wins.
+				continue
+			}
+			persisted[id.String()] = struct{}{}
+
+			encoder := encoderPool.Get()
+			encoder.Reset(blockStart, allocSize, nsCtx.Schema)
+			for _, w := range series.windows {
+				dp := ts.Datapoint{
+					Timestamp: w.start,
+					Value:     w.gauge.ValueOf(aggType),
+				}
+				if err := encoder.Encode(dp, w.unit, nil); err != nil {
+					encoder.Close()
+					return err
+				}
+			}
+
+			segment := encoder.Discard()
+			checksum := digest.SegmentChecksum(segment)
+			if err := persistFn(id, tags, segment, checksum); err != nil {
+				return err
+			}
+			if err := onPersist(id, tags); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// close finalizes the IDs and tags read from the source filesets, it must
+// only be called once the rolled up data has been persisted.
+func (r *shardRollup) close() {
+	for _, series := range r.ordered {
+		series.id.Finalize()
+		series.tags.Finalize()
+	}
+	for _, id := range r.toFinalize {
+		id.Finalize()
+	}
+	r.series = nil
+	r.ordered = nil
+	r.toFinalize = nil
+}
+
+// rollupSeriesIDAndTags returns the tags of the rolled up series of the
+// given aggregation type and its ID generated from the tags with the ID
+// scheme of the aggregated namespace.
+func rollupSeriesIDAndTags(
+	series *rollupSeries,
+	aggType aggregation.Type,
+	tagOpts models.TagOptions,
+) (ident.ID, ident.Tags) {
+	var (
+		typeName = aggType.String()
+		numTags  = len(series.tags.Values()) + 1
+		values   = make([]ident.Tag, 0, numTags)
+		tags     = models.NewTags(numTags, tagOpts)
+	)
+	for _, tag := range series.tags.Values() {
+		values = append(values, tag)
+		tags = tags.AddTagWithoutNormalizing(models.Tag{
+			Name:  tag.Name.Bytes(),
+			Value: tag.Value.Bytes(),
+		})
+	}
+	values = append(values, ident.StringTag(namespace.AggregationTypeTagName, typeName))
+	tags = tags.AddTag(models.Tag{
+		Name:  []byte(namespace.AggregationTypeTagName),
+		Value: []byte(typeName),
+	})
+	return ident.BytesID(tags.ID()), ident.NewTags(values...)
+}
@@ -0,0 +1,105 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/dbnode/ts"
+	"github.com/m3db/m3/src/dbnode/x/xio"
+	"github.com/m3db/m3/src/metrics/aggregation"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/x/ident"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestShardRollupAddDatapoints(t *testing.T) {
+	opts := DefaultTestOptions()
+	aggOpts := namespace.NewAggregationOptions().
+		SetEnabled(true).
+		SetResolution(time.Minute).
+		SetSourceNamespace(ident.StringID("raw")).
+		SetAggregationTypes(aggregation.Types{aggregation.Max, aggregation.Sum})
+	rollup, err := newShardRollup(opts, aggOpts)
+	require.NoError(t, err)
+	defer rollup.close()
+
+	start := time.Now().Truncate(time.Hour)
+	encoder := opts.EncoderPool().Get()
+	encoder.Reset(start, 0, nil)
+	for _, dp := range []ts.Datapoint{
+		{Timestamp: start, Value: 1},
+		{Timestamp: start.Add(10 * time.Second), Value: 3},
+		{Timestamp: start.Add(70 * time.Second), Value: 5},
+	} {
+		require.NoError(t, encoder.Encode(dp, xtime.Second, nil))
+	}
+	segment := encoder.Discard()
+
+	iter := opts.ReaderIteratorPool().Get()
+	defer iter.Close()
+	iter.Reset(xio.NewSegmentReader(segment), nil)
+
+	series := &rollupSeries{id: ident.StringID("foo")}
+	require.NoError(t, rollup.addDatapoints(series, iter))
+	require.Len(t, series.windows, 2)
+
+	require.True(t, start.Equal(series.windows[0].start))
+	require.Equal(t, 3.0, series.windows[0].gauge.ValueOf(aggregation.Max))
+	require.Equal(t, 4.0, series.windows[0].gauge.ValueOf(aggregation.Sum))
+
+	require.True(t, start.Add(time.Minute).Equal(series.windows[1].start))
+	require.Equal(t, 5.0, series.windows[1].gauge.ValueOf(aggregation.Max))
+	require.Equal(t, 5.0, series.windows[1].gauge.ValueOf(aggregation.Sum))
+}
+
+func TestRollupSeriesIDAndTags(t *testing.T) {
+	series := &rollupSeries{
+		id:   ident.StringID("foo"),
+		tags: ident.NewTags(ident.StringTag("name", "foo")),
+	}
+	tagOpts := models.NewTagOptions().SetIDSchemeType(models.TypeQuoted)
+	id, tags := rollupSeriesIDAndTags(series, aggregation.Max, tagOpts)
+	require.Equal(t, `{`+namespace.AggregationTypeTagName+`="Max",name="foo"}`, id.String())
+	require.Len(t, tags.Values(), 2)
+	require.Equal(t, "name", tags.Values()[0].Name.String())
+	require.Equal(t, namespace.AggregationTypeTagName, tags.Values()[1].Name.String())
+	require.Equal(t, "Max", tags.Values()[1].Value.String())
+}
+
+func TestRollupSeriesIDAndTagsIDScheme(t *testing.T) {
+	series := &rollupSeries{
+		id:   ident.StringID("foo"),
+		tags: ident.NewTags(ident.StringTag("name", "foo")),
+	}
+	tagOpts := models.NewTagOptions().SetIDSchemeType(models.TypeLegacy)
+	id, _ := rollupSeriesIDAndTags(series, aggregation.Last, tagOpts)
+	require.Equal(t, namespace.AggregationTypeTagName+"=Last,name=foo,", id.String())
+
+	// The source series ID does not affect the rolled up series ID.
+	series.id = ident.StringID("bar")
+	other, _ := rollupSeriesIDAndTags(series, aggregation.Last, tagOpts)
+	require.Equal(t, id.String(), other.String())
+}
@@ -44,6 +44,7 @@ type options struct {
 	identifierPool                ident.Pool
 	stats                         Stats
 	coldWritesEnabled             bool
+	valueSummariesEnabled         bool
 	bufferBucketPool              *BufferBucketPool
 	bufferBucketVersionsPool      *BufferBucketVersionsPool
 }
@@ -199,6 +200,16 @@ func (o *options) ColdWritesEnabled() bool {
 	return o.coldWritesEnabled
 }
 
+func (o *options) SetValueSummariesEnabled(value bool) Options {
+	opts := *o
+	opts.valueSummariesEnabled = value
+	return &opts
+}
+
+func (o *options) ValueSummariesEnabled() bool {
+	return o.valueSummariesEnabled
+}
+
 func (o *options) SetBufferBucketVersionsPool(value *BufferBucketVersionsPool) Options {
 	opts := *o
 	opts.bufferBucketVersionsPool = value
@@ -73,6 +73,7 @@ type dbSeries struct {
 	onRetrieveBlock             block.OnRetrieveBlock
 	blockOnEvictedFromWiredList block.OnEvictedFromWiredList
 	pool                        DatabaseSeriesPool
+	valueSummaries              valueSummaries
 }
 
 // NewDatabaseSeries creates a new database series.
@@ -139,6 +140,9 @@ func (s *dbSeries) Tick(blockStates ShardBlockStateSnapshot, nsCtx namespace.Con
 		return r, err
 	}
 	r.TickStatus = update.TickStatus
+	if s.opts.ValueSummariesEnabled() {
+		s.valueSummaries.expire(update.expireCutoff)
+	}
 	r.MadeExpiredBlocks, r.MadeUnwiredBlocks =
 		update.madeExpiredBlocks, update.madeUnwiredBlocks
 
@@ -154,6 +158,7 @@ type updateBlocksResult struct {
 	TickStatus
 	madeExpiredBlocks int
 	madeUnwiredBlocks int
+	expireCutoff      time.Time
 }
 
 func (s *dbSeries) updateBlocksWithLock(
@@ -259,6 +264,7 @@ func (s *dbSeries) updateBlocksWithLock(
 	bufferStats := s.buffer.Stats()
 	result.ActiveBlocks += bufferStats.wiredBlocks
 	result.WiredBlocks += bufferStats.wiredBlocks
+	result.expireCutoff = expireCutoff
 
 	return result, nil
 }
@@ -306,6 +312,11 @@ func (s *dbSeries) Write(
 	}
 
 	wasWritten, err := s.buffer.Write(ctx, timestamp, value, unit, annotation, wOpts)
+	if err == nil && wasWritten && s.opts.ValueSummariesEnabled() {
+		blockSize := s.opts.RetentionOptions().BlockSize()
+		s.valueSummaries.update(timestamp.Truncate(blockSize), timestamp,
+			value, s.opts.ColdWritesEnabled())
+	}
 	s.Unlock()
 	return wasWritten, err
 }
@@ -417,6 +428,9 @@ func (s *dbSeries) LoadBlock(
 
 	s.Lock()
 	s.buffer.Load(block, writeType)
+	if s.opts.ValueSummariesEnabled() {
+		s.valueSummaries.markIncomplete(block.StartTime())
+	}
 	s.Unlock()
 	return nil
 }
@@ -547,6 +561,18 @@ func (s *dbSeries) Snapshot(
 	return s.buffer.Snapshot(ctx, blockStart, s.id, s.tags, persistFn, nsCtx)
 }
 
+func (s *dbSeries) ValueSummaries(start, end time.Time) ([]ValueSummary, bool) {
+	s.RLock()
+	defer s.RUnlock()
+
+	if !s.opts.ValueSummariesEnabled() {
+		return nil, false
+	}
+	blockSize := s.opts.RetentionOptions().BlockSize()
+	summaries, complete := s.valueSummaries.summaries(start, end, blockSize)
+	return summaries, complete
+}
+
 func (s *dbSeries) ColdFlushBlockStarts(blockStates BootstrappedBlockStateSnapshot) OptimizedTimes {
 	s.RLock()
 	defer s.RUnlock()
@@ -578,6 +604,7 @@ func (s *dbSeries) Close() {
 	// back into the pool and be re-used.
 	s.buffer.Reset(databaseBufferResetOptions{Options: s.opts})
 	s.cachedBlocks.Reset()
+	s.valueSummaries.reset()
 
 	if s.pool != nil {
 		s.pool.Put(s)
@@ -607,6 +634,7 @@ func (s *dbSeries) Reset(opts DatabaseSeriesOptions) {
 	s.tags = opts.Tags
 	s.uniqueIndex = opts.UniqueIndex
 	s.cachedBlocks.Reset()
+	s.valueSummaries.reset()
 	s.buffer.Reset(databaseBufferResetOptions{
 		ID:             opts.ID,
 		BlockRetriever: opts.BlockRetriever,
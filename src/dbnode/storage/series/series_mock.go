@@ -298,6 +298,21 @@ func (mr *MockDatabaseSeriesMockRecorder) UniqueIndex() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UniqueIndex", reflect.TypeOf((*MockDatabaseSeries)(nil).UniqueIndex))
 }
 
+// ValueSummaries mocks base method
+func (m *MockDatabaseSeries) ValueSummaries(arg0, arg1 time.Time) ([]ValueSummary, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValueSummaries", arg0, arg1)
+	ret0, _ := ret[0].([]ValueSummary)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// ValueSummaries indicates an expected call of ValueSummaries
+func (mr *MockDatabaseSeriesMockRecorder) ValueSummaries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValueSummaries", reflect.TypeOf((*MockDatabaseSeries)(nil).ValueSummaries), arg0, arg1)
+}
+
 // WarmFlush mocks base method
 func (m *MockDatabaseSeries) WarmFlush(arg0 context.Context, arg1 time.Time, arg2 persist.DataFn, arg3 namespace.Context) (FlushOutcome, error) {
 	m.ctrl.T.Helper()
@@ -140,6 +140,11 @@ type DatabaseSeries interface {
 	// ColdFlushBlockStarts returns the block starts that need cold flushes.
 	ColdFlushBlockStarts(blockStates BootstrappedBlockStateSnapshot) OptimizedTimes
 
+	// ValueSummaries returns the value summaries of the blocks overlapping
+	// the given range and whether they cover every value of the series in
+	// that range.
+	ValueSummaries(start, end time.Time) ([]ValueSummary, bool)
+
 	// Close will close the series and if pooled returned to the pool.
 	Close()
 
@@ -341,6 +346,12 @@ type Options interface {
 	// ColdWritesEnabled returns whether cold writes are enabled.
 	ColdWritesEnabled() bool
 
+	// SetValueSummariesEnabled sets whether value summaries are kept per block.
+	SetValueSummariesEnabled(value bool) Options
+
+	// ValueSummariesEnabled returns whether value summaries are kept per block.
+	ValueSummariesEnabled() bool
+
 	// SetBufferBucketVersionsPool sets the BufferBucketVersionsPool.
 	SetBufferBucketVersionsPool(value *BufferBucketVersionsPool) Options
 
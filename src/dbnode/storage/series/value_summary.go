@@ -0,0 +1,149 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package series
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	xtime "github.com/m3db/m3/src/x/time"
+)
+
+// ValueSummary summarizes the values written to a series for a single block.
+type ValueSummary struct {
+	BlockStart    time.Time
+	Min           float64
+	Max           float64
+	Last          float64
+	LastTimestamp time.Time
+	// Incomplete is set when the block holds values that were not observed
+	// by the summary, for instance values loaded during bootstrap.
+	Incomplete bool
+}
+
+func newValueSummary(blockStart time.Time) *ValueSummary {
+	return &ValueSummary{
+		BlockStart: blockStart,
+		Min:        math.Inf(1),
+		Max:        math.Inf(-1),
+		Last:       math.NaN(),
+	}
+}
+
+func (s *ValueSummary) update(timestamp time.Time, value float64) {
+	if !timestamp.Before(s.LastTimestamp) {
+		s.Last = value
+		s.LastTimestamp = timestamp
+	}
+	if math.IsNaN(value) {
+		// NaN values are staleness markers and never match a comparison.
+		return
+	}
+	if value < s.Min {
+		s.Min = value
+	}
+	if value > s.Max {
+		s.Max = value
+	}
+}
+
+// valueSummaries holds the value summaries of a series, blocks from the first
+// block written to onwards are fully observed unless marked incomplete.
+type valueSummaries struct {
+	from   xtime.UnixNano
+	blocks map[xtime.UnixNano]*ValueSummary
+}
+
+func (s *valueSummaries) update(
+	blockStart time.Time,
+	timestamp time.Time,
+	value float64,
+	coldWritesEnabled bool,
+) {
+	if s.blocks == nil {
+		s.blocks = make(map[xtime.UnixNano]*ValueSummary)
+	}
+	start := xtime.ToUnixNano(blockStart)
+	summary, ok := s.blocks[start]
+	if !ok {
+		summary = newValueSummary(blockStart)
+		s.blocks[start] = summary
+	}
+	if s.from == 0 {
+		s.from = start
+		// With cold writes the first write may land in a block that already
+		// has values on disk.
+		summary.Incomplete = coldWritesEnabled
+	}
+	summary.update(timestamp, value)
+}
+
+func (s *valueSummaries) markIncomplete(blockStart time.Time) {
+	if s.blocks == nil {
+		s.blocks = make(map[xtime.UnixNano]*ValueSummary)
+	}
+	start := xtime.ToUnixNano(blockStart)
+	summary, ok := s.blocks[start]
+	if !ok {
+		summary = newValueSummary(blockStart)
+		s.blocks[start] = summary
+	}
+	summary.Incomplete = true
+}
+
+func (s *valueSummaries) expire(cutoff time.Time) {
+	for start := range s.blocks {
+		if start.ToTime().Before(cutoff) {
+			delete(s.blocks, start)
+		}
+	}
+}
+
+func (s *valueSummaries) reset() {
+	s.from = 0
+	s.blocks = nil
+}
+
+// summaries returns the summaries of the blocks overlapping [start, end) in
+// ascending block start order and whether they observed every value written
+// to the series in that range.
+func (s *valueSummaries) summaries(
+	start, end time.Time,
+	blockSize time.Duration,
+) ([]ValueSummary, bool) {
+	blockStart := start.Truncate(blockSize)
+	complete := s.from != 0 && !blockStart.Before(s.from.ToTime())
+	var result []ValueSummary
+	for t, summary := range s.blocks {
+		if t.ToTime().Before(blockStart) || !t.ToTime().Before(end) {
+			continue
+		}
+		if summary.Incomplete {
+			complete = false
+		}
+		result = append(result, *summary)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].BlockStart.Before(result[j].BlockStart)
+	})
+	return result, complete
+}
@@ -0,0 +1,117 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package series
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/x/ident"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValueSummariesCompleteFromFirstWrite(t *testing.T) {
+	var (
+		blockSize = 2 * time.Hour
+		start     = time.Now().Truncate(blockSize)
+		summaries valueSummaries
+	)
+	summaries.update(start, start.Add(time.Minute), 5, false)
+	summaries.update(start, start.Add(2*time.Minute), math.NaN(), false)
+	summaries.update(start.Add(blockSize), start.Add(blockSize), -1, false)
+
+	result, complete := summaries.summaries(start, start.Add(2*blockSize), blockSize)
+	require.True(t, complete)
+	require.Len(t, result, 2)
+	require.Equal(t, 5.0, result[0].Min)
+	require.Equal(t, 5.0, result[0].Max)
+	require.True(t, math.IsNaN(result[0].Last))
+	require.Equal(t, start.Add(2*time.Minute), result[0].LastTimestamp)
+	require.Equal(t, -1.0, result[1].Last)
+
+	// Blocks before the first write may hold values the summaries never saw.
+	_, complete = summaries.summaries(start.Add(-blockSize), start, blockSize)
+	require.False(t, complete)
+
+	summaries.markIncomplete(start.Add(blockSize))
+	_, complete = summaries.summaries(start.Add(blockSize), start.Add(2*blockSize), blockSize)
+	require.False(t, complete)
+	_, complete = summaries.summaries(start, start.Add(blockSize), blockSize)
+	require.True(t, complete)
+
+	summaries.expire(start.Add(blockSize))
+	result, _ = summaries.summaries(start, start.Add(2*blockSize), blockSize)
+	require.Len(t, result, 1)
+	require.Equal(t, start.Add(blockSize), result[0].BlockStart)
+}
+
+func TestValueSummariesColdWritesFirstBlockIncomplete(t *testing.T) {
+	var (
+		blockSize = 2 * time.Hour
+		start     = time.Now().Truncate(blockSize)
+		summaries valueSummaries
+	)
+	summaries.update(start, start, 1, true)
+	summaries.update(start.Add(blockSize), start.Add(blockSize), 2, true)
+
+	_, complete := summaries.summaries(start, start.Add(blockSize), blockSize)
+	require.False(t, complete)
+	_, complete = summaries.summaries(start.Add(blockSize), start.Add(2*blockSize), blockSize)
+	require.True(t, complete)
+}
+
+func TestSeriesWriteUpdatesValueSummaries(t *testing.T) {
+	opts := newSeriesTestOptions().SetValueSummariesEnabled(true)
+	blockSize := opts.RetentionOptions().BlockSize()
+	start := time.Now().Truncate(blockSize)
+	curr := start
+	opts = opts.SetClockOptions(opts.ClockOptions().SetNowFn(func() time.Time {
+		return curr
+	}))
+	series := NewDatabaseSeries(DatabaseSeriesOptions{
+		ID:      ident.StringID("foo"),
+		Options: opts,
+	}).(*dbSeries)
+
+	for _, v := range []float64{3, 1, 2} {
+		curr = curr.Add(time.Second)
+		verifyWriteToSeries(t, series, DecodedTestValue{
+			Timestamp: curr,
+			Value:     v,
+			Unit:      xtime.Second,
+		})
+	}
+
+	result, complete := series.ValueSummaries(start, curr.Add(time.Second))
+	require.True(t, complete)
+	require.Len(t, result, 1)
+	require.Equal(t, 1.0, result[0].Min)
+	require.Equal(t, 3.0, result[0].Max)
+	require.Equal(t, 2.0, result[0].Last)
+	require.Equal(t, curr, result[0].LastTimestamp)
+
+	series.Reset(DatabaseSeriesOptions{ID: ident.StringID("foo"), Options: opts})
+	_, complete = series.ValueSummaries(start, curr.Add(time.Second))
+	require.False(t, complete)
+}
@@ -26,6 +26,7 @@ import (
 	"fmt"
 	"io"
 	"math"
+	"sort"
 	"sync"
 	"time"
 
@@ -96,6 +97,25 @@ type filesetPathsBeforeFn func(
 	t time.Time,
 ) ([]string, error)
 
+type moveFileSetFn func(
+	fileset fs.FileSetFile,
+	dstFilePathPrefix string,
+	opts fs.Options,
+) error
+
+type readTombstonesFn func(
+	filePathPrefix string,
+	namespace ident.ID,
+	shardID uint32,
+) (*fs.Tombstones, error)
+
+type writeTombstonesFn func(
+	opts fs.Options,
+	namespace ident.ID,
+	shardID uint32,
+	tombstones *fs.Tombstones,
+) error
+
 type tickPolicy int
 
 const (
@@ -151,6 +171,7 @@ type dbShard struct {
 	block.DatabaseBlockRetriever
 	opts                     Options
 	seriesOpts               series.Options
+	writeClassSeriesOpts     []series.Options
 	nowFn                    clock.NowFn
 	state                    dbShardState
 	namespace                namespace.Metadata
@@ -160,20 +181,29 @@ type dbShard struct {
 	increasingIndex          increasingIndex
 	seriesPool               series.DatabaseSeriesPool
 	reverseIndex             namespaceIndex
+	quotas                   *namespaceQuotas
 	insertQueue              *dbShardInsertQueue
 	lookup                   *shardMap
 	list                     *list.List
 	bootstrapState           BootstrapState
 	newMergerFn              fs.NewMergerFn
 	newFSMergeWithMemFn      newFSMergeWithMemFn
+	newFSMergeWithVolumesFn  fs.NewVolumesMergeWithFn
 	filesetsFn               filesetsFn
 	filesetPathsBeforeFn     filesetPathsBeforeFn
 	deleteFilesFn            deleteFilesFn
+	moveFileSetFn            moveFileSetFn
 	snapshotFilesFn          snapshotFilesFn
+	readTombstonesFn         readTombstonesFn
+	writeTombstonesFn        writeTombstonesFn
 	sleepFn                  func(time.Duration)
 	identifierPool           ident.Pool
 	contextPool              context.Pool
 	flushState               shardFlushState
+	tombstones               *fs.Tombstones
+	tombstonesWriteLock      sync.Mutex
+	seriesTTLsLock           sync.Mutex
+	seriesTTLOpts            map[seriesTTLOptionsKey]series.Options
 	tickWg                   *sync.WaitGroup
 	runtimeOptsListenClosers []xclose.SimpleCloser
 	currRuntimeOptions       dbShardRuntimeOptions
@@ -183,6 +213,13 @@ type dbShard struct {
 	shard                    uint32
 }
 
+// seriesTTLOptionsKey is the key of the series options of series with a TTL,
+// writeClass is the index of the write class of the series or -1 if none.
+type seriesTTLOptionsKey struct {
+	writeClass int
+	ttl        time.Duration
+}
+
 // NB(r): dbShardRuntimeOptions does not contain its own
 // mutex as some of the variables are needed each write
 // which already at least acquires read lock from the shard
@@ -247,6 +284,7 @@ func newDatabaseShard(
 	namespaceReaderMgr databaseNamespaceReaderManager,
 	increasingIndex increasingIndex,
 	reverseIndex namespaceIndex,
+	quotas *namespaceQuotas,
 	needsBootstrap bool,
 	opts Options,
 	seriesOpts series.Options,
@@ -255,32 +293,40 @@ func newDatabaseShard(
 		SubScope("dbshard")
 
 	s := &dbShard{
-		opts:                 opts,
-		seriesOpts:           seriesOpts,
-		nowFn:                opts.ClockOptions().NowFn(),
-		state:                dbShardStateOpen,
-		namespace:            namespaceMetadata,
-		shard:                shard,
-		namespaceReaderMgr:   namespaceReaderMgr,
-		increasingIndex:      increasingIndex,
-		seriesPool:           opts.DatabaseSeriesPool(),
-		reverseIndex:         reverseIndex,
-		lookup:               newShardMap(shardMapOptions{}),
-		list:                 list.New(),
-		newMergerFn:          fs.NewMerger,
-		newFSMergeWithMemFn:  newFSMergeWithMem,
-		filesetsFn:           fs.DataFiles,
-		filesetPathsBeforeFn: fs.DataFileSetsBefore,
-		deleteFilesFn:        fs.DeleteFiles,
-		snapshotFilesFn:      fs.SnapshotFiles,
-		sleepFn:              time.Sleep,
-		identifierPool:       opts.IdentifierPool(),
-		contextPool:          opts.ContextPool(),
-		flushState:           newShardFlushState(),
-		tickWg:               &sync.WaitGroup{},
-		logger:               opts.InstrumentOptions().Logger(),
-		metrics:              newDatabaseShardMetrics(shard, scope),
-	}
+		opts:                    opts,
+		seriesOpts:              seriesOpts,
+		nowFn:                   opts.ClockOptions().NowFn(),
+		state:                   dbShardStateOpen,
+		namespace:               namespaceMetadata,
+		shard:                   shard,
+		namespaceReaderMgr:      namespaceReaderMgr,
+		increasingIndex:         increasingIndex,
+		seriesPool:              opts.DatabaseSeriesPool(),
+		reverseIndex:            reverseIndex,
+		quotas:                  quotas,
+		lookup:                  newShardMap(shardMapOptions{}),
+		list:                    list.New(),
+		newMergerFn:             fs.NewMerger,
+		newFSMergeWithMemFn:     newFSMergeWithMem,
+		newFSMergeWithVolumesFn: fs.NewVolumesMergeWith,
+		filesetsFn:              fs.DataFiles,
+		filesetPathsBeforeFn:    fs.DataFileSetsBefore,
+		deleteFilesFn:           fs.DeleteFiles,
+		moveFileSetFn:           fs.MoveDataFileSet,
+		snapshotFilesFn:         fs.SnapshotFiles,
+		readTombstonesFn:        fs.ReadTombstones,
+		writeTombstonesFn:       fs.WriteTombstones,
+		sleepFn:                 time.Sleep,
+		identifierPool:          opts.IdentifierPool(),
+		contextPool:             opts.ContextPool(),
+		flushState:              newShardFlushState(),
+		tombstones:              fs.NewTombstones(),
+		tickWg:                  &sync.WaitGroup{},
+		logger:                  opts.InstrumentOptions().Logger(),
+		metrics:                 newDatabaseShardMetrics(shard, scope),
+	}
+	s.writeClassSeriesOpts = newWriteClassSeriesOptions(namespaceMetadata, seriesOpts)
+	s.seriesTTLOpts = make(map[seriesTTLOptionsKey]series.Options)
 	s.insertQueue = newDatabaseShardInsertQueue(s.insertSeriesBatch,
 		s.nowFn, scope)
 
@@ -308,6 +354,67 @@ func newDatabaseShard(
 	return s
 }
 
+// newWriteClassSeriesOptions returns the series options for series of each of
+// the write classes of the namespace, these only differ from the namespace
+// series options by the buffer past and buffer future of the write class.
+func newWriteClassSeriesOptions(
+	md namespace.Metadata,
+	seriesOpts series.Options,
+) []series.Options {
+	classes := md.Options().WriteClasses()
+	if len(classes) == 0 {
+		return nil
+	}
+
+	results := make([]series.Options, 0, len(classes))
+	for _, class := range classes {
+		ropts := seriesOpts.RetentionOptions().
+			SetBufferPast(class.BufferPast()).
+			SetBufferFuture(class.BufferFuture())
+		results = append(results, seriesOpts.SetRetentionOptions(ropts))
+	}
+	return results
+}
+
+// seriesOptionsForTags returns the series options for a new series with the
+// given tags, which are the ones of the first write class that matches them
+// with the retention period shortened to the TTL of the series if it has one.
+func (s *dbShard) seriesOptionsForTags(tags ident.Tags) (series.Options, error) {
+	var (
+		writeClass = -1
+		opts       = s.seriesOpts
+	)
+	for i, class := range s.namespace.Options().WriteClasses() {
+		if i < len(s.writeClassSeriesOpts) && class.Matches(tags) {
+			writeClass = i
+			opts = s.writeClassSeriesOpts[i]
+			break
+		}
+	}
+
+	ttl, ok, err := retention.SeriesTTL(ident.NewTagsIterator(tags))
+	if err != nil {
+		return nil, xerrors.NewInvalidParamsError(err)
+	}
+	ropts := opts.RetentionOptions()
+	if !ok || ttl >= ropts.RetentionPeriod() {
+		return opts, nil
+	}
+
+	// Series options are shared between all the series with the same write
+	// class and TTL, the TTLs are also used to know which blocks need to
+	// have expired series dropped from them.
+	key := seriesTTLOptionsKey{writeClass: writeClass, ttl: ttl}
+	s.seriesTTLsLock.Lock()
+	defer s.seriesTTLsLock.Unlock()
+	ttlOpts, ok := s.seriesTTLOpts[key]
+	if !ok {
+		ttlOpts = opts.SetRetentionOptions(ropts.SetRetentionPeriod(ttl))
+		s.seriesTTLOpts[key] = ttlOpts
+	}
+	return ttlOpts, nil
+}
+
 func (s *dbShard) setBlockRetriever(retriever block.DatabaseBlockRetriever) {
 	// If using the block retriever then set the block retriever field
 	// and set the series block retriever as the shard itself and
@@ -812,6 +919,7 @@ func (s *dbShard) purgeExpiredSeries(expiredEntries []*lookup.Entry) {
 		// NB(xichen): if we get here, we are guaranteed that there can be
 		// no more reads/writes to this series while the lock is held, so it's
 		// safe to remove it.
+		s.quotas.seriesRemoved(series.Tags())
 		series.Close()
 		s.list.Remove(elem)
 		s.lookup.Delete(id)
@@ -819,6 +927,120 @@ func (s *dbShard) purgeExpiredSeries(expiredEntries []*lookup.Entry) {
 	s.Unlock()
 }
 
+func (s *dbShard) DeleteSeries(ids []ident.ID, deletedAt time.Time) error {
+	for _, id := range ids {
+		s.tombstones.Add(id, deletedAt)
+	}
+
+	// Persist the tombstones before removing the series from memory so that
+	// the deletion is durable once this call returns.
+	if err := s.persistTombstones(); err != nil {
+		return err
+	}
+
+	s.Lock()
+	for _, id := range ids {
+		elem, exists := s.lookup.Get(id)
+		if !exists {
+			continue
+		}
+		entry := elem.Value.(*lookup.Entry)
+		s.quotas.seriesRemoved(entry.Series.Tags())
+		s.list.Remove(elem)
+		s.lookup.Delete(id)
+		// NB: if the series is currently being read from or written to it is
+		// detached from the shard but not closed, since the other users still
+		// hold a reference to it, and left for the garbage collector instead.
+		if entry.ReaderWriterCount() == 0 {
+			entry.Series.Close()
+		}
+	}
+	s.Unlock()
+	return nil
+}
+
+func (s *dbShard) persistTombstones() error {
+	s.tombstonesWriteLock.Lock()
+	defer s.tombstonesWriteLock.Unlock()
+	fsOpts := s.opts.CommitLogOptions().FilesystemOptions()
+	return s.writeTombstonesFn(fsOpts, s.namespace.ID(), s.shard, s.tombstones)
+}
+
+func (s *dbShard) SeriesDeleted(id ident.ID) bool {
+	if _, deleted := s.tombstones.DeletedAt(id); !deleted {
+		return false
+	}
+
+	// A series that was written to again after it was deleted has live data
+	// and must remain visible.
+	s.RLock()
+	_, exists := s.lookup.Get(id)
+	s.RUnlock()
+	return !exists
+}
+
+func (s *dbShard) SeriesDeletedAt(id ident.ID) (time.Time, bool) {
+	return s.tombstones.DeletedAt(id)
+}
+
+func (s *dbShard) NumDeletedSeries() int {
+	return s.tombstones.Len()
+}
+
+func (s *dbShard) SeriesValueMatches(
+	id ident.ID,
+	filter index.ValueFilter,
+	start, end time.Time,
+) bool {
+	s.RLock()
+	entry, _, err := s.lookupEntryWithLock(id)
+	if entry != nil {
+		entry.IncrementReaderWriterCount()
+		defer entry.DecrementReaderWriterCount()
+	}
+	s.RUnlock()
+	if err != nil {
+		// Series that are not held in memory have no value summaries.
+		return true
+	}
+
+	summaries, complete := entry.Series.ValueSummaries(start, end)
+	if !complete {
+		return true
+	}
+	return valueSummariesMatch(summaries, filter, start, end)
+}
+
+// valueSummariesMatch returns whether the values summarized for [start, end)
+// may satisfy the filter, the summaries must cover every value in the range.
+func valueSummariesMatch(
+	summaries []series.ValueSummary,
+	filter index.ValueFilter,
+	start, end time.Time,
+) bool {
+	if len(summaries) == 0 {
+		return false
+	}
+	if filter.Latest {
+		latest := summaries[len(summaries)-1]
+		if latest.LastTimestamp.Before(start) {
+			// No values were written in the range.
+			return false
+		}
+		if latest.LastTimestamp.Before(end) {
+			return filter.Matches(latest.Last)
+		}
+		// The latest block extends past the range, fall back to comparing
+		// every value in the range.
+	}
+	for _, summary := range summaries {
+		if filter.MatchesRange(summary.Min, summary.Max) {
+			return true
+		}
+	}
+	return false
+}
+
 func (s *dbShard) WriteTagged(
 	ctx context.Context,
 	id ident.ID,
@@ -857,6 +1079,11 @@ func (s *dbShard) writeAndIndex(
 	wOpts series.WriteOptions,
 	shouldReverseIndex bool,
 ) (ts.Series, bool, error) {
+	// Drop writes for datapoints that were deleted.
+	if s.tombstones.DatapointDeleted(id, timestamp) {
+		return ts.Series{}, false, nil
+	}
+
 	// Prepare write
 	entry, opts, err := s.tryRetrieveWritableSeries(id)
 	if err != nil {
@@ -865,6 +1092,13 @@ func (s *dbShard) writeAndIndex(
 
 	writable := entry != nil
 
+	// Enforce the new series quotas before inserting a new series.
+	if !writable {
+		if err := s.quotas.checkNewSeries(tags); err != nil {
+			return ts.Series{}, false, err
+		}
+	}
+
 	// If no entry and we are not writing new series asynchronously.
 	if !writable && !opts.writeNewSeriesAsync {
 		// Avoid double lookup by enqueueing insert immediately.
@@ -993,7 +1227,7 @@ func (s *dbShard) SeriesReadWriteRef(
 	if entry != nil {
 		// The read/write ref is already incremented.
 		return SeriesReadWriteRef{
-			Series:              entry.Series,
+			Series:              s.seriesWithTombstone(entry.Series),
 			Shard:               s.shard,
 			UniqueIndex:         entry.Index,
 			ReleaseReadWriteRef: entry,
@@ -1019,13 +1253,26 @@ func (s *dbShard) SeriesReadWriteRef(
 	}
 
 	return SeriesReadWriteRef{
-		Series:              entry.Series,
+		Series:              s.seriesWithTombstone(entry.Series),
 		Shard:               s.shard,
 		UniqueIndex:         entry.Index,
 		ReleaseReadWriteRef: entry,
 	}, nil
 }
 
+// seriesWithTombstone returns the series wrapped so that writes and block
+// loads of deleted data are dropped if the series has been deleted.
+func (s *dbShard) seriesWithTombstone(
+	entrySeries series.DatabaseSeries,
+) series.DatabaseSeries {
+	deletedAt, deleted := s.tombstones.DeletedAt(entrySeries.ID())
+	if !deleted {
+		return entrySeries
+	}
+	return newDeletedSeries(entrySeries, deletedAt, s.seriesOpts,
+		namespace.NewContextFrom(s.namespace))
+}
+
 func (s *dbShard) ReadEncoded(
 	ctx context.Context,
 	id ident.ID,
@@ -1052,15 +1299,47 @@ func (s *dbShard) ReadEncoded(
 		return nil, err
 	}
 
-	if entry != nil {
-		return entry.Series.ReadEncoded(ctx, start, end, nsCtx)
+	deletedAt, deleted := s.tombstones.DeletedAt(id)
+	if deleted {
+		// Only read from the block that contains the deletion time onwards,
+		// earlier blocks only hold data written before the deletion.
+		blockSize := s.namespace.Options().RetentionOptions().BlockSize()
+		if deletedBlockStart := deletedAt.Truncate(blockSize); start.Before(deletedBlockStart) {
+			start = deletedBlockStart
+		}
+		if !start.Before(end) {
+			return nil, nil
+		}
 	}
 
-	retriever := s.seriesBlockRetriever
-	onRetrieve := s.seriesOnRetrieveBlock
-	opts := s.seriesOpts
-	reader := series.NewReaderUsingRetriever(id, retriever, onRetrieve, nil, opts)
-	return reader.ReadEncoded(ctx, start, end, nsCtx)
+	var readers [][]xio.BlockReader
+	if entry != nil {
+		readers, err = entry.Series.ReadEncoded(ctx, start, end, nsCtx)
+	} else {
+		retriever := s.seriesBlockRetriever
+		onRetrieve := s.seriesOnRetrieveBlock
+		opts := s.seriesOpts
+		reader := series.NewReaderUsingRetriever(id, retriever, onRetrieve, nil, opts)
+		readers, err = reader.ReadEncoded(ctx, start, end, nsCtx)
+	}
+	if err != nil || !deleted {
+		return readers, err
+	}
+
+	// The block that contains the deletion time may hold persisted data from
+	// before the deletion which must be filtered out.
+	filtered := make([][]xio.BlockReader, 0, len(readers))
+	for _, blockReaders := range readers {
+		blockReaders, err = filterDeletedBlockReaders(ctx, blockReaders,
+			deletedAt, s.seriesOpts, nsCtx)
+		if err != nil {
+			return nil, err
+		}
+		if len(blockReaders) > 0 {
+			filtered = append(filtered, blockReaders)
+		}
+	}
+	return filtered, nil
 }
 
 // lookupEntryWithLock returns the entry for a given id while holding a read lock or a write lock.
@@ -1183,6 +1462,11 @@ func (s *dbShard) newShardEntry(
 	// handle on these.
 	seriesTags.NoFinalize()
 
+	seriesOpts, err := s.seriesOptionsForTags(seriesTags)
+	if err != nil {
+		return nil, err
+	}
+
 	uniqueIndex := s.increasingIndex.nextIndex()
 	newSeries := s.seriesPool.Get()
 	newSeries.Reset(series.DatabaseSeriesOptions{
@@ -1192,7 +1476,7 @@ func (s *dbShard) newShardEntry(
 		BlockRetriever:         s.seriesBlockRetriever,
 		OnRetrieveBlock:        s.seriesOnRetrieveBlock,
 		OnEvictedFromWiredList: s,
-		Options:                s.seriesOpts,
+		Options:                seriesOpts,
 	})
 	return lookup.NewEntry(newSeries, uniqueIndex), nil
 }
@@ -1366,6 +1650,7 @@ func (s *dbShard) insertNewShardEntryWithLock(entry *lookup.Entry) {
 		NoCopyKey:     true,
 		NoFinalizeKey: true,
 	})
+	s.quotas.seriesInserted(entry.Series.Tags())
 }
 
 func (s *dbShard) insertSeriesBatch(inserts []dbShardInsert) error {
@@ -1550,18 +1835,46 @@ func (s *dbShard) FetchBlocks(
 		return nil, err
 	}
 
-	if entry != nil {
-		return entry.Series.FetchBlocks(ctx, starts, nsCtx)
+	deletedAt, deleted := s.tombstones.DeletedAt(id)
+	if deleted {
+		// Do not return blocks that only hold deleted data.
+		blockSize := s.namespace.Options().RetentionOptions().BlockSize()
+		filtered := make([]time.Time, 0, len(starts))
+		for _, start := range starts {
+			if !s.tombstones.BlockDeleted(id, start, blockSize) {
+				filtered = append(filtered, start)
+			}
+		}
+		starts = filtered
 	}
 
-	retriever := s.seriesBlockRetriever
-	onRetrieve := s.seriesOnRetrieveBlock
-	opts := s.seriesOpts
-	// Nil for onRead callback because we don't want peer bootstrapping to impact
-	// the behavior of the LRU
-	var onReadCb block.OnReadBlock
-	reader := series.NewReaderUsingRetriever(id, retriever, onRetrieve, onReadCb, opts)
-	return reader.FetchBlocks(ctx, starts, nsCtx)
+	var results []block.FetchBlockResult
+	if entry != nil {
+		results, err = entry.Series.FetchBlocks(ctx, starts, nsCtx)
+	} else {
+		retriever := s.seriesBlockRetriever
+		onRetrieve := s.seriesOnRetrieveBlock
+		opts := s.seriesOpts
+		// Nil for onRead callback because we don't want peer bootstrapping to impact
+		// the behavior of the LRU
+		var onReadCb block.OnReadBlock
+		reader := series.NewReaderUsingRetriever(id, retriever, onRetrieve, onReadCb, opts)
+		results, err = reader.FetchBlocks(ctx, starts, nsCtx)
+	}
+	if err != nil || !deleted {
+		return results, err
+	}
+
+	// Filter out data from before the deletion in the block that contains
+	// the deletion time.
+	for i := range results {
+		if results[i].Err != nil {
+			continue
+		}
+		results[i].Blocks, results[i].Err = filterDeletedBlockReaders(ctx,
+			results[i].Blocks, deletedAt, s.seriesOpts, nsCtx)
+	}
+	return results, nil
 }
 
 func (s *dbShard) FetchBlocksForColdFlush(
@@ -1788,6 +2101,13 @@ func (s *dbShard) FetchBlocksMetadataV2(
 					blockStart, err)
 			}
 
+			if s.tombstones.BlockDeleted(id, blockStart, blockSize) {
+				// Data for this series in this block has been deleted.
+				id.Finalize()
+				tags.Close()
+				continue
+			}
+
 			blockResult := s.opts.FetchBlockMetadataResultsPool().Get()
 			value := block.FetchBlockMetadataResult{
 				Start: blockStart,
@@ -1853,6 +2173,19 @@ func (s *dbShard) PrepareBootstrap() error {
 	// needs to ask the shard whether certain time windows have been flushed or
 	// not.
 	s.initializeFlushStates()
+	return s.loadTombstones()
+}
+
+func (s *dbShard) loadTombstones() error {
+	fsOpts := s.opts.CommitLogOptions().FilesystemOptions()
+	tombstones, err := s.readTombstonesFn(fsOpts.FilePathPrefix(),
+		s.namespace.ID(), s.shard)
+	if err != nil {
+		return err
+	}
+	tombstones.ForEach(func(id ident.ID, deletedAt time.Time) {
+		s.tombstones.Add(id, deletedAt)
+	})
 	return nil
 }
 
@@ -1875,9 +2208,12 @@ func (s *dbShard) initializeFlushStates() {
 }
 
 func (s *dbShard) UpdateFlushStates() {
-	fsOpts := s.opts.CommitLogOptions().FilesystemOptions()
-	readInfoFilesResults := fs.ReadInfoFiles(fsOpts.FilePathPrefix(), s.namespace.ID(), s.shard,
-		fsOpts.InfoReaderBufferSize(), fsOpts.DecodingOptions())
+	var (
+		fsOpts                 = s.opts.CommitLogOptions().FilesystemOptions()
+		coldTierFilePathPrefix = s.namespace.Options().ColdTierOptions().FilePathPrefix()
+	)
+	readInfoFilesResults := fs.ReadTieredInfoFiles(fsOpts.FilePathPrefix(), coldTierFilePathPrefix,
+		s.namespace.ID(), s.shard, fsOpts.InfoReaderBufferSize(), fsOpts.DecodingOptions())
 
 	for _, result := range readInfoFilesResults {
 		if err := result.Err.Error(); err != nil {
@@ -2005,6 +2341,24 @@ func (s *dbShard) loadBlock(
 		result    loadBlockResult
 	)
 
+	// Do not load blocks for data that has been deleted, this ensures that
+	// repairs from peers do not resurrect deleted series.
+	blockSize := s.namespace.Options().RetentionOptions().BlockSize()
+	if s.tombstones.BlockDeleted(id, timestamp, blockSize) {
+		block.Close()
+		result.canFinalizeTags = true
+		return result, nil
+	}
+	if deletedAt, ok := s.tombstones.DeletedWithinBlock(id, timestamp, blockSize); ok {
+		remaining, err := filterDeletedBlock(block, deletedAt, s.seriesOpts,
+			namespace.NewContextFrom(s.namespace))
+		if err != nil || !remaining {
+			block.Close()
+			result.canFinalizeTags = true
+			return result, err
+		}
+	}
+
 	// First lookup if series already exists.
 	entry, shardOpts, err := s.tryRetrieveWritableSeries(id)
 	if err != nil && err != errShardEntryNotFound {
@@ -2162,6 +2516,96 @@ func (s *dbShard) WarmFlush(
 	return s.markWarmFlushStateSuccessOrError(blockStart, multiErr.FinalError())
 }
 
+func (s *dbShard) RollupFlush(
+	blockStart time.Time,
+	source namespace.Metadata,
+	flushPreparer persist.FlushPreparer,
+	nsCtx namespace.Context,
+) error {
+	// We don't flush data when the shard is still bootstrapping
+	s.RLock()
+	if s.bootstrapState != Bootstrapped {
+		s.RUnlock()
+		return errShardNotBootstrappedToFlush
+	}
+	s.RUnlock()
+
+	rollup, err := newShardRollup(s.opts, s.namespace.Options().AggregationOptions())
+	if err != nil {
+		return err
+	}
+	defer rollup.close()
+
+	var (
+		blockSize       = s.namespace.Options().RetentionOptions().BlockSize()
+		sourceBlockSize = source.Options().RetentionOptions().BlockSize()
+	)
+	for t := blockStart; t.Before(blockStart.Add(blockSize)); t = t.Add(sourceBlockSize) {
+		if err := rollup.addVolume(source, s.ID(), t, nsCtx); err != nil {
+			return s.markWarmFlushStateSuccessOrError(blockStart, err)
+		}
+	}
+
+	prepared, err := flushPreparer.PrepareData(persist.DataPrepareOptions{
+		NamespaceMetadata: s.namespace,
+		Shard:             s.ID(),
+		BlockStart:        blockStart,
+		// Rollups are only ever persisted once per block, as with warm
+		// flushes, so the volume index is always 0.
+		VolumeIndex:    0,
+		DeleteIfExists: false,
+		FileSetType:    persist.FileSetFlushType,
+	})
+	if err != nil {
+		return s.markWarmFlushStateSuccessOrError(blockStart, err)
+	}
+
+	// Rolled up series are indexed for the rolled up block rather than the
+	// current time so that queries over the block find them, the index blocks
+	// covering the block are usually sealed so they are indexed once the
+	// rolled up data has been persisted.
+	var (
+		multiErr xerrors.MultiError
+		docs     []doc.Document
+	)
+	err = rollup.persist(blockStart, prepared.Persist, nsCtx,
+		func(id ident.ID, tags ident.Tags) error {
+			_, err := s.insertSeriesSync(id, newTagsIterArg(ident.NewTagsIterator(tags)),
+				insertSyncOptions{
+					insertType: insertSync,
+				})
+			if err != nil {
+				return err
+			}
+			if s.reverseIndex == nil {
+				return nil
+			}
+			d, err := convert.FromMetric(id, tags)
+			if err != nil {
+				return err
+			}
+			docs = append(docs, d)
+			return nil
+		})
+	if err != nil {
+		multiErr = multiErr.Add(err)
+	}
+
+	if err := prepared.Close(); err != nil {
+		multiErr = multiErr.Add(err)
+	}
+
+	if multiErr.Empty() && s.reverseIndex != nil {
+		err := s.reverseIndex.WriteRollup(s.ID(), blockStart,
+			blockStart.Add(blockSize), docs)
+		if err != nil {
+			multiErr = multiErr.Add(err)
+		}
+	}
+
+	return s.markWarmFlushStateSuccessOrError(blockStart, multiErr.FinalError())
+}
+
 func (s *dbShard) ColdFlush(
 	flushPreparer persist.FlushPreparer,
 	resources coldFlushReuseableResources,
@@ -2242,7 +2686,7 @@ func (s *dbShard) ColdFlush(
 
 	merger := s.newMergerFn(resources.fsReader, s.opts.DatabaseBlockOptions().DatabaseBlockAllocSize(),
 		s.opts.SegmentReaderPool(), s.opts.MultiReaderIteratorPool(),
-		s.opts.IdentifierPool(), s.opts.EncoderPool(), s.opts.ContextPool(), s.namespace.Options())
+		s.opts.IdentifierPool(), s.opts.EncoderPool(), s.opts.ContextPool(), s.namespace.Options(), s.nowFn)
 	mergeWithMem := s.newFSMergeWithMemFn(s, s, dirtySeries, dirtySeriesToWrite)
 	// Loop through each block that we know has ColdWrites. Since each block
 	// has its own fileset, if we encounter an error while trying to persist
@@ -2269,42 +2713,7 @@ func (s *dbShard) ColdFlush(
 			continue
 		}
 
-		// After writing the full block successfully update the ColdVersionFlushed number. This will
-		// allow the SeekerManager to open a lease on the latest version of the fileset files because
-		// the BlockLeaseVerifier will check the ColdVersionFlushed value, but the buffer only looks at
-		// ColdVersionRetrievable so a concurrent tick will not yet cause the blocks in memory to be
-		// evicted (which is the desired behavior because we haven't updated the open leases yet which
-		// means the newly written data is not available for querying via the SeekerManager yet.)
-		s.setFlushStateColdVersionFlushed(startTime, nextVersion)
-
-		// Notify all block leasers that a new volume for the namespace/shard/blockstart
-		// has been created. This will block until all leasers have relinquished their
-		// leases.
-		_, err = s.opts.BlockLeaseManager().UpdateOpenLeases(block.LeaseDescriptor{
-			Namespace:  s.namespace.ID(),
-			Shard:      s.ID(),
-			BlockStart: startTime,
-		}, block.LeaseState{Volume: nextVersion})
-		// After writing the full block successfully **and** propagating the new lease to the
-		// BlockLeaseManager, update the ColdVersionRetrievable in the flush state. Once this function
-		// completes concurrent ticks will be able to evict the data from memory that was just flushed
-		// (which is now safe to do since the SeekerManager has been notified of the presence of new
-		// files).
-		//
-		// NB(rartoul): Ideally the ColdVersionRetrievable would only be updated if the call to UpdateOpenLeases
-		// succeeded, but that would allow the ColdVersionRetrievable and ColdVersionFlushed numbers to drift
-		// which would increase the complexity of the code to address a situation that is probably not
-		// recoverable (failure to UpdateOpenLeases is an invariant violated error).
-		s.setFlushStateColdVersionRetrievable(startTime, nextVersion)
-		if err != nil {
-			instrument.EmitAndLogInvariantViolation(s.opts.InstrumentOptions(), func(l *zap.Logger) {
-				l.With(
-					zap.String("namespace", s.namespace.ID().String()),
-					zap.Uint32("shard", s.ID()),
-					zap.Time("blockStart", startTime),
-					zap.Int("nextVersion", nextVersion),
-				).Error("failed to update open leases after updating flush state cold version")
-			})
+		if err := s.activateColdVersion(startTime, nextVersion); err != nil {
 			multiErr = multiErr.Add(err)
 			continue
 		}
@@ -2313,6 +2722,326 @@ func (s *dbShard) ColdFlush(
 	return multiErr.FinalError()
 }
 
+func (s *dbShard) CompactFileSets(
+	flushPreparer persist.FlushPreparer,
+	fsReader fs.DataFileSetReader,
+	minVolumes int,
+	limiter *compactionRateLimiter,
+	nsCtx namespace.Context,
+) (fileSetCompactionResult, error) {
+	var result fileSetCompactionResult
+
+	// We don't compact data when the shard is still bootstrapping.
+	s.RLock()
+	if s.bootstrapState != Bootstrapped {
+		s.RUnlock()
+		return result, errShardNotBootstrappedToFlush
+	}
+	// Use blockStatesSnapshotWithRLock to avoid having to re-acquire read lock.
+	blockStates := s.blockStatesSnapshotWithRLock()
+	s.RUnlock()
+
+	blockStatesSnapshot, bootstrapped := blockStates.UnwrapValue()
+	if !bootstrapped {
+		return result, errFlushStateIsNotInitialized
+	}
+
+	filesets, err := s.tieredDataFileSets()
+	if err != nil {
+		return result, err
+	}
+
+	volumesByBlock := make(map[xtime.UnixNano]fs.FileSetFilesSlice)
+	for _, fileset := range filesets {
+		if !fileset.HasCompleteCheckpointFile() {
+			continue
+		}
+		blockStart := xtime.ToUnixNano(fileset.ID.BlockStart)
+		volumesByBlock[blockStart] = append(volumesByBlock[blockStart], fileset)
+	}
+
+	var (
+		multiErr xerrors.MultiError
+		merger   = s.newMergerFn(fsReader, s.opts.DatabaseBlockOptions().DatabaseBlockAllocSize(),
+			s.opts.SegmentReaderPool(), s.opts.MultiReaderIteratorPool(),
+			s.opts.IdentifierPool(), s.opts.EncoderPool(), s.opts.ContextPool(), s.namespace.Options(), s.nowFn)
+	)
+	for blockStart, volumes := range volumesByBlock {
+		if len(volumes) < minVolumes {
+			continue
+		}
+
+		// Only compact blocks whose latest volume is the one being read from,
+		// this skips blocks that are mid way through a cold flush.
+		startTime := blockStart.ToTime()
+		state := blockStatesSnapshot.Snapshot[blockStart]
+		latest, ok := volumes.LatestVolumeForBlock(startTime)
+		if !ok || latest.ID.VolumeIndex != state.ColdVersion {
+			continue
+		}
+
+		blockResult, err := s.compactVolumes(merger, flushPreparer, volumes,
+			latest, limiter, nsCtx)
+		if err != nil {
+			multiErr = multiErr.Add(err)
+			continue
+		}
+		result.add(blockResult)
+	}
+
+	return result, multiErr.FinalError()
+}
+
+// compactVolumes merges the volumes of a block into a single new volume,
+// makes it the volume that is read from and removes the volumes it replaces.
+func (s *dbShard) compactVolumes(
+	merger fs.Merger,
+	flushPreparer persist.FlushPreparer,
+	volumes fs.FileSetFilesSlice,
+	latest fs.FileSetFile,
+	limiter *compactionRateLimiter,
+	nsCtx namespace.Context,
+) (fileSetCompactionResult, error) {
+	var result fileSetCompactionResult
+
+	bytesBefore, err := fileSetsSize(volumes)
+	if err != nil {
+		return result, err
+	}
+
+	// The oldest volume is merged with the newer volumes, which are read
+	// after it so that their data takes precedence.
+	sorted := append(fs.FileSetFilesSlice(nil), volumes...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].ID.VolumeIndex < sorted[j].ID.VolumeIndex
+	})
+	mergeWith, err := s.newFSMergeWithVolumesFn(fs.VolumesMergeWithOptions{
+		Volumes:        sorted[1:],
+		BlockSize:      s.namespace.Options().RetentionOptions().BlockSize(),
+		BytesPool:      s.opts.BytesPool(),
+		IdentifierPool: s.opts.IdentifierPool(),
+		DeletedAtFn:    s.SeriesDeletedAt,
+		Options:        s.opts.CommitLogOptions().FilesystemOptions(),
+	})
+	if err != nil {
+		return result, err
+	}
+
+	nextVersion := latest.ID.VolumeIndex + 1
+	err = merger.Merge(sorted[0].ID, mergeWith, nextVersion, flushPreparer, nsCtx)
+	if closeErr := mergeWith.Close(); err == nil {
+		err = closeErr
+	}
+	limiter.Limit(bytesBefore)
+	if err != nil {
+		return result, err
+	}
+
+	if err := s.activateColdVersion(latest.ID.BlockStart, nextVersion); err != nil {
+		return result, err
+	}
+
+	if err := s.deleteFilesFn(volumes.Filepaths()); err != nil {
+		return result, err
+	}
+
+	compacted, err := s.tieredDataFileSets()
+	if err != nil {
+		return result, err
+	}
+	var bytesAfter int64
+	for _, fileset := range compacted {
+		if fileset.ID.BlockStart.Equal(latest.ID.BlockStart) &&
+			fileset.ID.VolumeIndex == nextVersion {
+			bytesAfter, err = fileSetsSize(fs.FileSetFilesSlice{fileset})
+			if err != nil {
+				return result, err
+			}
+		}
+	}
+
+	result.numBlocks = 1
+	result.numVolumes = len(volumes)
+	result.bytesReclaimed = bytesBefore - bytesAfter
+	return result, nil
+}
+
+func (s *dbShard) DropExpiredSeries(
+	flushPreparer persist.FlushPreparer,
+	fsReader fs.DataFileSetReader,
+	ttls []time.Duration,
+	nsCtx namespace.Context,
+) (int, error) {
+	// The TTLs of series that are not indexed are only known once the
+	// series have been written to.
+	s.seriesTTLsLock.Lock()
+	allTTLs := make([]time.Duration, 0, len(ttls)+len(s.seriesTTLOpts))
+	allTTLs = append(allTTLs, ttls...)
+	for key := range s.seriesTTLOpts {
+		allTTLs = append(allTTLs, key.ttl)
+	}
+	s.seriesTTLsLock.Unlock()
+	if len(allTTLs) == 0 {
+		return 0, nil
+	}
+
+	// Merges drop the data of series whose TTL has expired for the block, so
+	// only volumes written before the TTL of some series expired for their
+	// block can still hold expired data and need to be rewritten.
+	var (
+		now       = s.nowFn()
+		blockSize = s.namespace.Options().RetentionOptions().BlockSize()
+	)
+	return s.rewriteLatestVolumes(flushPreparer, fsReader, nsCtx,
+		func(latest fs.FileSetFile) bool {
+			writtenAt, err := latest.CheckpointWrittenAt()
+			if err != nil {
+				s.logger.Warn("could not determine when fileset was written",
+					zap.Time("blockStart", latest.ID.BlockStart),
+					zap.Int("volume", latest.ID.VolumeIndex),
+					zap.Error(err))
+				return false
+			}
+			blockStart := latest.ID.BlockStart
+			for _, ttl := range allTTLs {
+				if retention.SeriesBlockExpired(ttl, blockStart, blockSize, now) &&
+					!retention.SeriesBlockExpired(ttl, blockStart, blockSize, writtenAt) {
+					return true
+				}
+			}
+			return false
+		})
+}
+
+// rewriteLatestVolumes rewrites the latest volume of the blocks for which the
+// include function returns true into a single new volume and removes the
+// volumes it replaces, dropping the data of deleted and expired series.
+func (s *dbShard) rewriteLatestVolumes(
+	flushPreparer persist.FlushPreparer,
+	fsReader fs.DataFileSetReader,
+	nsCtx namespace.Context,
+	include func(latest fs.FileSetFile) bool,
+) (int, error) {
+	var numBlocks int
+
+	// We don't rewrite data when the shard is still bootstrapping.
+	s.RLock()
+	if s.bootstrapState != Bootstrapped {
+		s.RUnlock()
+		return numBlocks, errShardNotBootstrappedToFlush
+	}
+	// Use blockStatesSnapshotWithRLock to avoid having to re-acquire read lock.
+	blockStates := s.blockStatesSnapshotWithRLock()
+	s.RUnlock()
+
+	blockStatesSnapshot, bootstrapped := blockStates.UnwrapValue()
+	if !bootstrapped {
+		return numBlocks, errFlushStateIsNotInitialized
+	}
+
+	filesets, err := s.tieredDataFileSets()
+	if err != nil {
+		return numBlocks, err
+	}
+
+	volumesByBlock := make(map[xtime.UnixNano]fs.FileSetFilesSlice)
+	for _, fileset := range filesets {
+		blockStart := xtime.ToUnixNano(fileset.ID.BlockStart)
+		volumesByBlock[blockStart] = append(volumesByBlock[blockStart], fileset)
+	}
+
+	var (
+		multiErr xerrors.MultiError
+		merger   = s.newMergerFn(fsReader, s.opts.DatabaseBlockOptions().DatabaseBlockAllocSize(),
+			s.opts.SegmentReaderPool(), s.opts.MultiReaderIteratorPool(),
+			s.opts.IdentifierPool(), s.opts.EncoderPool(), s.opts.ContextPool(), s.namespace.Options(), s.nowFn)
+		mergeWith = newFSMergeWithTombstones(s)
+	)
+	for blockStart, volumes := range volumesByBlock {
+		startTime := blockStart.ToTime()
+
+		// Only rewrite blocks whose latest volume is the one being read from,
+		// this skips blocks that are mid way through a cold flush.
+		state := blockStatesSnapshot.Snapshot[blockStart]
+		latest, ok := volumes.LatestVolumeForBlock(startTime)
+		if !ok || latest.ID.VolumeIndex != state.ColdVersion {
+			continue
+		}
+		if !include(latest) {
+			continue
+		}
+
+		// Every cold flush merges the latest volume with the cold writes so
+		// the latest volume holds all the data of the block, rewriting it
+		// drops the data of deleted series and allows the older volumes to
+		// be removed.
+		nextVersion := latest.ID.VolumeIndex + 1
+		err = merger.Merge(latest.ID, mergeWith, nextVersion, flushPreparer, nsCtx)
+		if err != nil {
+			multiErr = multiErr.Add(err)
+			continue
+		}
+
+		if err := s.activateColdVersion(startTime, nextVersion); err != nil {
+			multiErr = multiErr.Add(err)
+			continue
+		}
+
+		if err := s.deleteFilesFn(volumes.Filepaths()); err != nil {
+			multiErr = multiErr.Add(err)
+			continue
+		}
+
+		numBlocks++
+	}
+
+	return numBlocks, multiErr.FinalError()
+}
+
+// activateColdVersion makes a newly written volume for the block start the
+// one that is read from, once it has been persisted in full.
+func (s *dbShard) activateColdVersion(startTime time.Time, nextVersion int) error {
+	// After writing the full block successfully update the ColdVersionFlushed number. This will
+	// allow the SeekerManager to open a lease on the latest version of the fileset files because
+	// the BlockLeaseVerifier will check the ColdVersionFlushed value, but the buffer only looks at
+	// ColdVersionRetrievable so a concurrent tick will not yet cause the blocks in memory to be
+	// evicted (which is the desired behavior because we haven't updated the open leases yet which
+	// means the newly written data is not available for querying via the SeekerManager yet.)
+	s.setFlushStateColdVersionFlushed(startTime, nextVersion)
+
+	// Notify all block leasers that a new volume for the namespace/shard/blockstart
+	// has been created. This will block until all leasers have relinquished their
+	// leases.
+	_, err := s.opts.BlockLeaseManager().UpdateOpenLeases(block.LeaseDescriptor{
+		Namespace:  s.namespace.ID(),
+		Shard:      s.ID(),
+		BlockStart: startTime,
+	}, block.LeaseState{Volume: nextVersion})
+	// After writing the full block successfully **and** propagating the new lease to the
+	// BlockLeaseManager, update the ColdVersionRetrievable in the flush state. Once this function
+	// completes concurrent ticks will be able to evict the data from memory that was just flushed
+	// (which is now safe to do since the SeekerManager has been notified of the presence of new
+	// files).
+	//
+	// NB(rartoul): Ideally the ColdVersionRetrievable would only be updated if the call to UpdateOpenLeases
+	// succeeded, but that would allow the ColdVersionRetrievable and ColdVersionFlushed numbers to drift
+	// which would increase the complexity of the code to address a situation that is probably not
+	// recoverable (failure to UpdateOpenLeases is an invariant violated error).
+	s.setFlushStateColdVersionRetrievable(startTime, nextVersion)
+	if err != nil {
+		instrument.EmitAndLogInvariantViolation(s.opts.InstrumentOptions(), func(l *zap.Logger) {
+			l.With(
+				zap.String("namespace", s.namespace.ID().String()),
+				zap.Uint32("shard", s.ID()),
+				zap.Time("blockStart", startTime),
+				zap.Int("nextVersion", nextVersion),
+			).Error("failed to update open leases after updating flush state cold version")
+		})
+		return err
+	}
+	return nil
+}
+
 func (s *dbShard) Snapshot(
 	blockStart time.Time,
 	snapshotTime time.Time,
@@ -2468,22 +3197,37 @@ func (s *dbShard) removeAnyFlushStatesTooEarly(startTime time.Time) {
 }
 
 func (s *dbShard) CleanupExpiredFileSets(earliestToRetain time.Time) error {
-	filePathPrefix := s.opts.CommitLogOptions().FilesystemOptions().FilePathPrefix()
-	expired, err := s.filesetPathsBeforeFn(filePathPrefix, s.namespace.ID(), s.ID(), earliestToRetain)
-	if err != nil {
-		return fmt.Errorf("encountered errors when getting fileset files for prefix %s namespace %s shard %d: %v",
-			filePathPrefix, s.namespace.ID(), s.ID(), err)
+	var expired []string
+	for _, filePathPrefix := range s.dataFilePathPrefixes() {
+		tierExpired, err := s.filesetPathsBeforeFn(filePathPrefix, s.namespace.ID(), s.ID(), earliestToRetain)
+		if err != nil {
+			return fmt.Errorf("encountered errors when getting fileset files for prefix %s namespace %s shard %d: %v",
+				filePathPrefix, s.namespace.ID(), s.ID(), err)
+		}
+		expired = append(expired, tierExpired...)
+	}
+
+	if err := s.deleteFilesFn(expired); err != nil {
+		return err
 	}
 
-	return s.deleteFilesFn(expired)
+	// Tombstones that only cover expired blocks are no longer needed.
+	blockSize := s.namespace.Options().RetentionOptions().BlockSize()
+	if s.tombstones.ExpireBefore(earliestToRetain, blockSize) > 0 {
+		return s.persistTombstones()
+	}
+	return nil
 }
 
 func (s *dbShard) CleanupCompactedFileSets() error {
-	filePathPrefix := s.opts.CommitLogOptions().FilesystemOptions().FilePathPrefix()
-	filesets, err := s.filesetsFn(filePathPrefix, s.namespace.ID(), s.ID())
-	if err != nil {
-		return fmt.Errorf("encountered errors when getting fileset files for prefix %s namespace %s shard %d: %v",
-			filePathPrefix, s.namespace.ID(), s.ID(), err)
+	var filesets fs.FileSetFilesSlice
+	for _, filePathPrefix := range s.dataFilePathPrefixes() {
+		tierFilesets, err := s.filesetsFn(filePathPrefix, s.namespace.ID(), s.ID())
+		if err != nil {
+			return fmt.Errorf("encountered errors when getting fileset files for prefix %s namespace %s shard %d: %v",
+				filePathPrefix, s.namespace.ID(), s.ID(), err)
+		}
+		filesets = append(filesets, tierFilesets...)
 	}
 
 	// Get a snapshot of all states here to prevent constantly getting/releasing
@@ -2507,6 +3251,74 @@ func (s *dbShard) CleanupCompactedFileSets() error {
 	return s.deleteFilesFn(toDelete.Filepaths())
 }
 
+func (s *dbShard) MoveFileSetsToColdTier(endBefore time.Time) (int, error) {
+	coldTierOpts := s.namespace.Options().ColdTierOptions()
+	if !coldTierOpts.Enabled() {
+		return 0, nil
+	}
+
+	fsOpts := s.opts.CommitLogOptions().FilesystemOptions()
+	filesets, err := s.filesetsFn(fsOpts.FilePathPrefix(), s.namespace.ID(), s.ID())
+	if err != nil {
+		return 0, fmt.Errorf("encountered errors when getting fileset files for prefix %s namespace %s shard %d: %v",
+			fsOpts.FilePathPrefix(), s.namespace.ID(), s.ID(), err)
+	}
+
+	var (
+		multiErr  xerrors.MultiError
+		blockSize = s.namespace.Options().RetentionOptions().BlockSize()
+		moved     int
+	)
+	for _, fileset := range filesets {
+		if fileset.ID.BlockStart.Add(blockSize).After(endBefore) {
+			continue
+		}
+		// Filesets without a complete checkpoint file are either still being
+		// written or were left behind by a failed write, neither can be read.
+		if !fileset.HasCompleteCheckpointFile() {
+			continue
+		}
+		err := s.moveFileSetFn(fileset, coldTierOpts.FilePathPrefix(), fsOpts)
+		if err != nil {
+			multiErr = multiErr.Add(err)
+			continue
+		}
+		moved++
+	}
+
+	return moved, multiErr.FinalError()
+}
+
+// dataFilePathPrefixes returns the file path prefixes of the storage tiers
+// that hold the data filesets of the shard.
+func (s *dbShard) dataFilePathPrefixes() []string {
+	prefixes := []string{s.opts.CommitLogOptions().FilesystemOptions().FilePathPrefix()}
+	if coldTierOpts := s.namespace.Options().ColdTierOptions(); coldTierOpts.Enabled() {
+		prefixes = append(prefixes, coldTierOpts.FilePathPrefix())
+	}
+	return prefixes
+}
+
+// tieredDataFileSets returns the data filesets of the shard across the
+// primary and cold storage tiers.
+func (s *dbShard) tieredDataFileSets() (fs.FileSetFilesSlice, error) {
+	filePathPrefix := s.opts.CommitLogOptions().FilesystemOptions().FilePathPrefix()
+	filesets, err := s.filesetsFn(filePathPrefix, s.namespace.ID(), s.ID())
+	if err != nil {
+		return nil, err
+	}
+
+	coldTierOpts := s.namespace.Options().ColdTierOptions()
+	if !coldTierOpts.Enabled() {
+		return filesets, nil
+	}
+	coldFilesets, err := s.filesetsFn(coldTierOpts.FilePathPrefix(), s.namespace.ID(), s.ID())
+	if err != nil {
+		return nil, err
+	}
+	return fs.MergeTieredDataFileSets(filesets, coldFilesets), nil
+}
+
 func (s *dbShard) Repair(
 	ctx context.Context,
 	nsCtx namespace.Context,
@@ -32,6 +32,7 @@ import (
 	"time"
 	"unsafe"
 
+	"github.com/m3db/m3/src/dbnode/clock"
 	"github.com/m3db/m3/src/dbnode/encoding"
 	"github.com/m3db/m3/src/dbnode/namespace"
 	"github.com/m3db/m3/src/dbnode/persist"
@@ -40,6 +41,7 @@ import (
 	"github.com/m3db/m3/src/dbnode/runtime"
 	"github.com/m3db/m3/src/dbnode/storage/block"
 	"github.com/m3db/m3/src/dbnode/storage/bootstrap/result"
+	"github.com/m3db/m3/src/dbnode/storage/index"
 	"github.com/m3db/m3/src/dbnode/storage/series"
 	"github.com/m3db/m3/src/dbnode/storage/series/lookup"
 	"github.com/m3db/m3/src/dbnode/ts"
@@ -47,6 +49,7 @@ import (
 	"github.com/m3db/m3/src/dbnode/x/xio"
 	"github.com/m3db/m3/src/x/checked"
 	"github.com/m3db/m3/src/x/context"
+	xerrors "github.com/m3db/m3/src/x/errors"
 	"github.com/m3db/m3/src/x/ident"
 	"github.com/m3db/m3/src/x/pool"
 	xtest "github.com/m3db/m3/src/x/test"
@@ -83,7 +86,7 @@ func testDatabaseShardWithIndexFn(
 		SetBufferBucketVersionsPool(series.NewBufferBucketVersionsPool(nil)).
 		SetBufferBucketPool(series.NewBufferBucketPool(nil))
 	return newDatabaseShard(metadata, 0, nil, nsReaderMgr,
-		&testIncreasingIndex{}, idx, true, opts, seriesOpts).(*dbShard)
+		&testIncreasingIndex{}, idx, nil, true, opts, seriesOpts).(*dbShard)
 }
 
 func addMockSeries(ctrl *gomock.Controller, shard *dbShard, id ident.ID, tags ident.Tags, index uint64) *series.MockDatabaseSeries {
@@ -103,7 +106,7 @@ func TestShardDontNeedBootstrap(t *testing.T) {
 	defer closer()
 	seriesOpts := NewSeriesOptionsFromOptions(opts, testNs.Options().RetentionOptions())
 	shard := newDatabaseShard(testNs.metadata, 0, nil, nil,
-		&testIncreasingIndex{}, nil, false, opts, seriesOpts).(*dbShard)
+		&testIncreasingIndex{}, nil, nil, false, opts, seriesOpts).(*dbShard)
 	defer shard.Close()
 
 	require.Equal(t, Bootstrapped, shard.bootstrapState)
@@ -116,7 +119,7 @@ func TestShardErrorIfDoubleBootstrap(t *testing.T) {
 	defer closer()
 	seriesOpts := NewSeriesOptionsFromOptions(opts, testNs.Options().RetentionOptions())
 	shard := newDatabaseShard(testNs.metadata, 0, nil, nil,
-		&testIncreasingIndex{}, nil, false, opts, seriesOpts).(*dbShard)
+		&testIncreasingIndex{}, nil, nil, false, opts, seriesOpts).(*dbShard)
 	defer shard.Close()
 
 	require.Equal(t, Bootstrapped, shard.bootstrapState)
@@ -673,6 +676,103 @@ func TestShardColdFlushNoMergeIfNothingDirty(t *testing.T) {
 	}
 }
 
+func TestShardCompactFileSets(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	now := time.Now()
+	nowFn := func() time.Time {
+		return now
+	}
+	opts := DefaultTestOptions()
+	opts = opts.SetClockOptions(opts.ClockOptions().SetNowFn(nowFn))
+	blockSize := opts.SeriesOptions().RetentionOptions().BlockSize()
+	shard := testDatabaseShard(t, opts)
+	require.NoError(t, shard.Bootstrap())
+	shard.newMergerFn = newMergerTestFn
+
+	var mergeWiths []*noopVolumesMergeWith
+	shard.newFSMergeWithVolumesFn = func(
+		opts fs.VolumesMergeWithOptions,
+	) (fs.VolumesMergeWith, error) {
+		mergeWith := &noopVolumesMergeWith{opts: opts}
+		mergeWiths = append(mergeWiths, mergeWith)
+		return mergeWith, nil
+	}
+
+	t0 := now.Truncate(blockSize).Add(-10 * blockSize)
+	t1 := t0.Add(blockSize)
+	shard.markWarmFlushStateSuccess(t0)
+	shard.markWarmFlushStateSuccess(t1)
+	shard.setFlushStateColdVersionFlushed(t0, 3)
+	shard.setFlushStateColdVersionRetrievable(t0, 3)
+	shard.setFlushStateColdVersionFlushed(t1, 1)
+	shard.setFlushStateColdVersionRetrievable(t1, 1)
+
+	var filesets fs.FileSetFilesSlice
+	for _, v := range []struct {
+		blockStart time.Time
+		volumes    int
+	}{
+		{blockStart: t0, volumes: 4},
+		{blockStart: t1, volumes: 2},
+	} {
+		for i := 0; i < v.volumes; i++ {
+			filesets = append(filesets, fs.FileSetFile{
+				ID: fs.FileSetFileIdentifier{
+					Namespace:   shard.namespace.ID(),
+					Shard:       shard.ID(),
+					BlockStart:  v.blockStart,
+					VolumeIndex: i,
+				},
+				AbsoluteFilepaths:               []string{fmt.Sprintf("%d-%d", v.blockStart.UnixNano(), i)},
+				CachedHasCompleteCheckpointFile: fs.EvalTrue,
+			})
+		}
+	}
+	shard.filesetsFn = func(_ string, _ ident.ID, _ uint32) (fs.FileSetFilesSlice, error) {
+		return filesets, nil
+	}
+	var deletedFiles []string
+	shard.deleteFilesFn = func(files []string) error {
+		deletedFiles = append(deletedFiles, files...)
+		return nil
+	}
+
+	preparer := persist.NewMockFlushPreparer(ctrl)
+	fsReader := fs.NewMockDataFileSetReader(ctrl)
+	result, err := shard.CompactFileSets(preparer, fsReader, 3, nil, namespace.Context{})
+	require.NoError(t, err)
+	require.Equal(t, 1, result.numBlocks)
+	require.Equal(t, 4, result.numVolumes)
+
+	// Only t0 has enough volumes to be compacted, its oldest volume is merged
+	// with the newer volumes.
+	require.Equal(t, 1, len(mergeWiths))
+	require.Equal(t, filesets[1:4], mergeWiths[0].opts.Volumes)
+	require.True(t, mergeWiths[0].closed)
+
+	coldVersion, err := shard.RetrievableBlockColdVersion(t0)
+	require.NoError(t, err)
+	require.Equal(t, 4, coldVersion)
+	coldVersion, err = shard.RetrievableBlockColdVersion(t1)
+	require.NoError(t, err)
+	require.Equal(t, 1, coldVersion)
+
+	require.Equal(t, filesets[:4].Filepaths(), deletedFiles)
+}
+
+type noopVolumesMergeWith struct {
+	fs.MergeWith
+
+	opts   fs.VolumesMergeWithOptions
+	closed bool
+}
+
+func (m *noopVolumesMergeWith) Close() error {
+	m.closed = true
+	return nil
+}
+
 func newMergerTestFn(
 	reader fs.DataFileSetReader,
 	blockAllocSize int,
@@ -682,6 +782,7 @@ func newMergerTestFn(
 	encoderPool encoding.EncoderPool,
 	contextPool context.Pool,
 	nsOpts namespace.Options,
+	nowFn clock.NowFn,
 ) fs.Merger {
 	return &noopMerger{}
 }
@@ -727,6 +828,10 @@ func (m *noopMergeWith) ForEachRemaining(
 	return nil
 }
 
+func (m *noopMergeWith) DeletedAt(seriesID ident.ID) (time.Time, bool) {
+	return time.Time{}, false
+}
+
 func TestShardSnapshotShardNotBootstrapped(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -1283,6 +1388,56 @@ func TestPurgeExpiredSeriesEmptySeries(t *testing.T) {
 	shard.RUnlock()
 }
 
+func TestShardDeleteSeries(t *testing.T) {
+	opts := DefaultTestOptions()
+	shard := testDatabaseShard(t, opts)
+	defer shard.Close()
+
+	var persisted int
+	shard.writeTombstonesFn = func(
+		_ fs.Options,
+		_ ident.ID,
+		_ uint32,
+		tombstones *fs.Tombstones,
+	) error {
+		persisted = tombstones.Len()
+		return nil
+	}
+
+	var (
+		ctx       = opts.ContextPool().Get()
+		id        = ident.StringID("foo")
+		now       = opts.ClockOptions().NowFn()()
+		deletedAt = now.Add(time.Second)
+	)
+	defer ctx.Close()
+
+	_, wasWritten, err := shard.Write(ctx, id, now, 1.0, xtime.Second, nil, series.WriteOptions{})
+	require.NoError(t, err)
+	require.True(t, wasWritten)
+
+	require.NoError(t, shard.DeleteSeries([]ident.ID{id}, deletedAt))
+	require.Equal(t, 1, persisted)
+	require.Equal(t, 1, shard.NumDeletedSeries())
+	require.True(t, shard.SeriesDeleted(id))
+
+	shard.RLock()
+	require.Equal(t, 0, shard.lookup.Len())
+	shard.RUnlock()
+
+	// Writes for datapoints before the deletion are dropped.
+	_, wasWritten, err = shard.Write(ctx, id, now, 2.0, xtime.Second, nil, series.WriteOptions{})
+	require.NoError(t, err)
+	require.False(t, wasWritten)
+	require.True(t, shard.SeriesDeleted(id))
+
+	// Writes after the deletion make the series visible again.
+	_, wasWritten, err = shard.Write(ctx, id, deletedAt, 3.0, xtime.Second, nil, series.WriteOptions{})
+	require.NoError(t, err)
+	require.True(t, wasWritten)
+	require.False(t, shard.SeriesDeleted(id))
+}
+
 // This tests the scenario where a non-empty series is not expired.
 func TestPurgeExpiredSeriesNonEmptySeries(t *testing.T) {
 	ctrl := gomock.NewController(t)
@@ -1447,6 +1602,74 @@ func TestShardCleanupExpiredFileSets(t *testing.T) {
 	require.Equal(t, []string{defaultTestNs1ID.String(), "0"}, deletedFiles)
 }
 
+func TestShardCleanupExpiredFileSetsColdTier(t *testing.T) {
+	opts := DefaultTestOptions()
+	shard := testDatabaseShard(t, opts)
+	defer shard.Close()
+	md, err := namespace.NewMetadata(defaultTestNs1ID, defaultTestNs1Opts.
+		SetColdTierOptions(namespace.NewColdTierOptions().SetFilePathPrefix("cold")))
+	require.NoError(t, err)
+	shard.namespace = md
+
+	shard.filesetPathsBeforeFn = func(prefix string, _ ident.ID, _ uint32, _ time.Time) ([]string, error) {
+		return []string{prefix}, nil
+	}
+	var deletedFiles []string
+	shard.deleteFilesFn = func(files []string) error {
+		deletedFiles = append(deletedFiles, files...)
+		return nil
+	}
+	require.NoError(t, shard.CleanupExpiredFileSets(time.Now()))
+	hotPrefix := opts.CommitLogOptions().FilesystemOptions().FilePathPrefix()
+	require.Equal(t, []string{hotPrefix, "cold"}, deletedFiles)
+}
+
+func TestShardMoveFileSetsToColdTier(t *testing.T) {
+	opts := DefaultTestOptions()
+	shard := testDatabaseShard(t, opts)
+	defer shard.Close()
+
+	blockSize := defaultTestNs1Opts.RetentionOptions().BlockSize()
+	md, err := namespace.NewMetadata(defaultTestNs1ID, defaultTestNs1Opts.
+		SetColdTierOptions(namespace.NewColdTierOptions().
+			SetFilePathPrefix("cold").
+			SetAge(blockSize)))
+	require.NoError(t, err)
+	shard.namespace = md
+
+	var (
+		now     = time.Now().Truncate(blockSize)
+		fileset = func(blockStart time.Time, complete fs.LazyEvalBool) fs.FileSetFile {
+			f := fs.NewFileSetFile(fs.FileSetFileIdentifier{
+				Namespace:  defaultTestNs1ID,
+				BlockStart: blockStart,
+			}, "hot")
+			f.CachedHasCompleteCheckpointFile = complete
+			return f
+		}
+		filesets = fs.FileSetFilesSlice{
+			fileset(now.Add(-3*blockSize), fs.EvalTrue),
+			fileset(now.Add(-2*blockSize), fs.EvalFalse),
+			fileset(now.Add(-blockSize), fs.EvalTrue),
+		}
+	)
+	shard.filesetsFn = func(_ string, _ ident.ID, _ uint32) (fs.FileSetFilesSlice, error) {
+		return filesets, nil
+	}
+	var moved []time.Time
+	shard.moveFileSetFn = func(f fs.FileSetFile, dst string, _ fs.Options) error {
+		require.Equal(t, "cold", dst)
+		moved = append(moved, f.ID.BlockStart)
+		return nil
+	}
+
+	// Only complete filesets of blocks that ended before the cutoff are moved.
+	numMoved, err := shard.MoveFileSetsToColdTier(now.Add(-blockSize))
+	require.NoError(t, err)
+	require.Equal(t, 1, numMoved)
+	require.Equal(t, []time.Time{now.Add(-3 * blockSize)}, moved)
+}
+
 type testCloser struct {
 	called int
 }
@@ -1759,3 +1982,196 @@ func TestShardIterateBatchSize(t *testing.T) {
 
 	require.True(t, shardIterateBatchMinSize < iterateBatchSize(2000))
 }
+
+func TestShardWriteClassBufferPast(t *testing.T) {
+	opts := DefaultTestOptions()
+	iot, err := namespace.NewWriteClass("iot", "source", "device-.*",
+		90*time.Minute, 10*time.Minute)
+	require.NoError(t, err)
+	metadata, err := namespace.NewMetadata(defaultTestNs1ID,
+		defaultTestNs1Opts.SetWriteClasses([]namespace.WriteClass{iot}))
+	require.NoError(t, err)
+	seriesOpts := NewSeriesOptionsFromOptions(opts, defaultTestNs1Opts.RetentionOptions()).
+		SetBufferBucketVersionsPool(series.NewBufferBucketVersionsPool(nil)).
+		SetBufferBucketPool(series.NewBufferBucketPool(nil))
+	shard := newDatabaseShard(metadata, 0, nil, nil,
+		&testIncreasingIndex{}, nil, nil, false, opts, seriesOpts).(*dbShard)
+	defer shard.Close()
+
+	ctx := opts.ContextPool().Get()
+	defer ctx.Close()
+
+	late := opts.ClockOptions().NowFn()().Add(-time.Hour)
+	write := func(id, source string) error {
+		tags := ident.NewTags(ident.StringTag("source", source))
+		entry, err := shard.newShardEntry(ident.StringID(id), newTagsArg(tags))
+		require.NoError(t, err)
+		_, err = entry.Series.Write(ctx, late, 1.0, xtime.Second, nil,
+			series.WriteOptions{})
+		return err
+	}
+
+	// Series of the write class accept writes within its buffer past.
+	require.NoError(t, write("foo", "device-1"))
+
+	// Other series are bound by the buffer past of the namespace.
+	require.Error(t, write("bar", "web"))
+}
+
+func TestShardSeriesTTL(t *testing.T) {
+	opts := DefaultTestOptions()
+	shard := testDatabaseShard(t, opts)
+	defer shard.Close()
+
+	newEntry := func(id, ttl string) error {
+		tags := ident.NewTags(ident.StringTag(retention.SeriesTTLTagName, ttl))
+		_, err := shard.newShardEntry(ident.StringID(id), newTagsArg(tags))
+		return err
+	}
+
+	// Series with an invalid TTL are rejected.
+	err := newEntry("foo", "abc")
+	require.Error(t, err)
+	require.True(t, xerrors.IsInvalidParams(err))
+	require.Error(t, newEntry("foo", "-1h"))
+
+	// TTLs at least as long as the retention of the namespace are ignored.
+	retentionPeriod := shard.seriesOpts.RetentionOptions().RetentionPeriod()
+	require.NoError(t, newEntry("bar", retentionPeriod.String()))
+	require.Equal(t, 0, len(shard.seriesTTLOpts))
+
+	// Series with the same TTL share their options.
+	require.NoError(t, newEntry("baz", "6h"))
+	require.NoError(t, newEntry("qux", "6h"))
+	require.Equal(t, 1, len(shard.seriesTTLOpts))
+	ttlOpts, ok := shard.seriesTTLOpts[seriesTTLOptionsKey{writeClass: -1, ttl: 6 * time.Hour}]
+	require.True(t, ok)
+	require.Equal(t, 6*time.Hour, ttlOpts.RetentionOptions().RetentionPeriod())
+}
+
+func TestShardDropExpiredSeries(t *testing.T) {
+	dir, err := ioutil.TempDir("", "testdir")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Now()
+	opts := DefaultTestOptions()
+	opts = opts.SetClockOptions(opts.ClockOptions().SetNowFn(func() time.Time {
+		return now
+	}))
+	blockSize := opts.SeriesOptions().RetentionOptions().BlockSize()
+	shard := testDatabaseShard(t, opts)
+	defer shard.Close()
+	require.NoError(t, shard.Bootstrap())
+	shard.newMergerFn = newMergerTestFn
+
+	var (
+		ttl = 3 * blockSize
+		t0  = now.Truncate(blockSize).Add(-10 * blockSize)
+		t1  = t0.Add(blockSize)
+		t2  = now.Truncate(blockSize).Add(-blockSize)
+
+		filesets fs.FileSetFilesSlice
+	)
+	addFileSet := func(blockStart, writtenAt time.Time) string {
+		checkpointFilePath := fmt.Sprintf("%s/%d-checkpoint-0.db", dir, blockStart.UnixNano())
+		require.NoError(t, ioutil.WriteFile(checkpointFilePath, nil, 0666))
+		require.NoError(t, os.Chtimes(checkpointFilePath, writtenAt, writtenAt))
+		filesets = append(filesets, fs.FileSetFile{
+			ID:                fs.FileSetFileIdentifier{BlockStart: blockStart},
+			AbsoluteFilepaths: []string{checkpointFilePath},
+		})
+		shard.markWarmFlushStateSuccess(blockStart)
+		return checkpointFilePath
+	}
+	// Written before the TTL expired for the block.
+	expired := addFileSet(t0, t0.Add(blockSize+ttl-time.Minute))
+	// Written once the TTL had expired for the block, so already rewritten.
+	addFileSet(t1, t1.Add(blockSize+ttl))
+	// The TTL has not expired for the block yet.
+	addFileSet(t2, now.Add(-time.Minute))
+	shard.filesetsFn = func(_ string, _ ident.ID, _ uint32) (fs.FileSetFilesSlice, error) {
+		return filesets, nil
+	}
+	var deleted []string
+	shard.deleteFilesFn = func(files []string) error {
+		deleted = append(deleted, files...)
+		return nil
+	}
+
+	preparer := persist.NewMockFlushPreparer(ctrl)
+	reader := fs.NewMockDataFileSetReader(ctrl)
+
+	// Nothing to drop without any series with a TTL.
+	numBlocks, err := shard.DropExpiredSeries(preparer, reader, nil, namespace.Context{})
+	require.NoError(t, err)
+	require.Equal(t, 0, numBlocks)
+
+	numBlocks, err = shard.DropExpiredSeries(preparer, reader, []time.Duration{ttl}, namespace.Context{})
+	require.NoError(t, err)
+	require.Equal(t, 1, numBlocks)
+	require.Equal(t, []string{expired}, deleted)
+
+	coldVersion, err := shard.RetrievableBlockColdVersion(t0)
+	require.NoError(t, err)
+	require.Equal(t, 1, coldVersion)
+}
+
+func TestShardSeriesValueMatches(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	opts := DefaultTestOptions()
+	shard := testDatabaseShard(t, opts)
+	defer shard.Close()
+
+	var (
+		start  = time.Now().Truncate(time.Hour)
+		end    = start.Add(time.Hour)
+		filter = index.ValueFilter{Type: index.ValueFilterGreater, Value: 10}
+	)
+
+	// Series that are not in memory are never excluded.
+	require.True(t, shard.SeriesValueMatches(ident.StringID("foo"), filter, start, end))
+
+	partial := addMockSeries(ctrl, shard, ident.StringID("partial"), ident.Tags{}, 1)
+	partial.EXPECT().ValueSummaries(start, end).Return(nil, false)
+	require.True(t, shard.SeriesValueMatches(ident.StringID("partial"), filter, start, end))
+
+	low := addMockSeries(ctrl, shard, ident.StringID("low"), ident.Tags{}, 2)
+	low.EXPECT().ValueSummaries(start, end).Return([]series.ValueSummary{
+		{BlockStart: start, Min: 1, Max: 5, Last: 5, LastTimestamp: start},
+	}, true)
+	require.False(t, shard.SeriesValueMatches(ident.StringID("low"), filter, start, end))
+
+	high := addMockSeries(ctrl, shard, ident.StringID("high"), ident.Tags{}, 3)
+	high.EXPECT().ValueSummaries(start, end).Return([]series.ValueSummary{
+		{BlockStart: start, Min: 1, Max: 20, Last: 5, LastTimestamp: start},
+	}, true)
+	require.True(t, shard.SeriesValueMatches(ident.StringID("high"), filter, start, end))
+}
+
+func TestValueSummariesMatchLatest(t *testing.T) {
+	var (
+		start     = time.Now().Truncate(time.Hour)
+		end       = start.Add(time.Hour)
+		filter    = index.ValueFilter{Type: index.ValueFilterGreater, Value: 10, Latest: true}
+		summaries = []series.ValueSummary{
+			{BlockStart: start, Min: 1, Max: 20, Last: 5, LastTimestamp: start.Add(time.Minute)},
+		}
+	)
+	require.False(t, valueSummariesMatch(summaries, filter, start, end))
+	require.False(t, valueSummariesMatch(nil, filter, start, end))
+
+	// Values written after the end of the range fall back to comparing the
+	// values of the whole block.
+	summaries[0].LastTimestamp = end.Add(time.Minute)
+	require.True(t, valueSummariesMatch(summaries, filter, start, end))
+
+	// Values written before the start of the range are out of range.
+	summaries[0].LastTimestamp = start.Add(-time.Minute)
+	require.False(t, valueSummariesMatch(summaries, filter, start, end))
+}
@@ -34,7 +34,9 @@ import (
 	"github.com/m3db/m3/src/dbnode/namespace"
 	"github.com/m3db/m3/src/dbnode/persist"
 	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/dbnode/persist/fs/backup"
 	"github.com/m3db/m3/src/dbnode/persist/fs/commitlog"
+	"github.com/m3db/m3/src/dbnode/ratelimit"
 	"github.com/m3db/m3/src/dbnode/runtime"
 	"github.com/m3db/m3/src/dbnode/sharding"
 	"github.com/m3db/m3/src/dbnode/storage/block"
@@ -46,6 +48,7 @@ import (
 	"github.com/m3db/m3/src/dbnode/ts"
 	"github.com/m3db/m3/src/dbnode/x/xio"
 	"github.com/m3db/m3/src/dbnode/x/xpool"
+	"github.com/m3db/m3/src/m3ninx/doc"
 	"github.com/m3db/m3/src/x/context"
 	"github.com/m3db/m3/src/x/ident"
 	"github.com/m3db/m3/src/x/instrument"
@@ -55,6 +58,7 @@ import (
 	time0 "github.com/m3db/m3/src/x/time"
 
 	"github.com/golang/mock/gomock"
+	"github.com/pborman/uuid"
 )
 
 // MockIndexedErrorHandler is a mock of IndexedErrorHandler interface
@@ -327,6 +331,21 @@ func (mr *MockDatabaseMockRecorder) AggregateQuery(ctx, namespace, query, opts i
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AggregateQuery", reflect.TypeOf((*MockDatabase)(nil).AggregateQuery), ctx, namespace, query, opts)
 }
 
+// IndexCardinality mocks base method
+func (m *MockDatabase) IndexCardinality(ctx context.Context, namespace ident.ID, opts index.CardinalityOptions) (index.CardinalityResults, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IndexCardinality", ctx, namespace, opts)
+	ret0, _ := ret[0].(index.CardinalityResults)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IndexCardinality indicates an expected call of IndexCardinality
+func (mr *MockDatabaseMockRecorder) IndexCardinality(ctx, namespace, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IndexCardinality", reflect.TypeOf((*MockDatabase)(nil).IndexCardinality), ctx, namespace, opts)
+}
+
 // ReadEncoded mocks base method
 func (m *MockDatabase) ReadEncoded(ctx context.Context, namespace, id ident.ID, start, end time.Time) ([][]xio.BlockReader, error) {
 	m.ctrl.T.Helper()
@@ -458,6 +477,36 @@ func (mr *MockDatabaseMockRecorder) Truncate(namespace interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Truncate", reflect.TypeOf((*MockDatabase)(nil).Truncate), namespace)
 }
 
+// DeleteTagged mocks base method
+func (m *MockDatabase) DeleteTagged(ctx context.Context, namespace ident.ID, query index.Query, opts index.QueryOptions) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTagged", ctx, namespace, query, opts)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteTagged indicates an expected call of DeleteTagged
+func (mr *MockDatabaseMockRecorder) DeleteTagged(ctx, namespace, query, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTagged", reflect.TypeOf((*MockDatabase)(nil).DeleteTagged), ctx, namespace, query, opts)
+}
+
+// Backup mocks base method
+func (m *MockDatabase) Backup(namespace ident.ID, backupID string, store backup.BlobStore) (backup.Manifest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Backup", namespace, backupID, store)
+	ret0, _ := ret[0].(backup.Manifest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Backup indicates an expected call of Backup
+func (mr *MockDatabaseMockRecorder) Backup(namespace, backupID, store interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Backup", reflect.TypeOf((*MockDatabase)(nil).Backup), namespace, backupID, store)
+}
+
 // BootstrapState mocks base method
 func (m *MockDatabase) BootstrapState() DatabaseBootstrapState {
 	m.ctrl.T.Helper()
@@ -722,6 +771,21 @@ func (mr *MockdatabaseMockRecorder) AggregateQuery(ctx, namespace, query, opts i
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AggregateQuery", reflect.TypeOf((*Mockdatabase)(nil).AggregateQuery), ctx, namespace, query, opts)
 }
 
+// IndexCardinality mocks base method
+func (m *Mockdatabase) IndexCardinality(ctx context.Context, namespace ident.ID, opts index.CardinalityOptions) (index.CardinalityResults, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IndexCardinality", ctx, namespace, opts)
+	ret0, _ := ret[0].(index.CardinalityResults)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IndexCardinality indicates an expected call of IndexCardinality
+func (mr *MockdatabaseMockRecorder) IndexCardinality(ctx, namespace, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IndexCardinality", reflect.TypeOf((*Mockdatabase)(nil).IndexCardinality), ctx, namespace, opts)
+}
+
 // ReadEncoded mocks base method
 func (m *Mockdatabase) ReadEncoded(ctx context.Context, namespace, id ident.ID, start, end time.Time) ([][]xio.BlockReader, error) {
 	m.ctrl.T.Helper()
@@ -853,6 +917,36 @@ func (mr *MockdatabaseMockRecorder) Truncate(namespace interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Truncate", reflect.TypeOf((*Mockdatabase)(nil).Truncate), namespace)
 }
 
+// DeleteTagged mocks base method
+func (m *Mockdatabase) DeleteTagged(ctx context.Context, namespace ident.ID, query index.Query, opts index.QueryOptions) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTagged", ctx, namespace, query, opts)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteTagged indicates an expected call of DeleteTagged
+func (mr *MockdatabaseMockRecorder) DeleteTagged(ctx, namespace, query, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTagged", reflect.TypeOf((*Mockdatabase)(nil).DeleteTagged), ctx, namespace, query, opts)
+}
+
+// Backup mocks base method
+func (m *Mockdatabase) Backup(namespace ident.ID, backupID string, store backup.BlobStore) (backup.Manifest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Backup", namespace, backupID, store)
+	ret0, _ := ret[0].(backup.Manifest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Backup indicates an expected call of Backup
+func (mr *MockdatabaseMockRecorder) Backup(namespace, backupID, store interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Backup", reflect.TypeOf((*Mockdatabase)(nil).Backup), namespace, backupID, store)
+}
+
 // BootstrapState mocks base method
 func (m *Mockdatabase) BootstrapState() DatabaseBootstrapState {
 	m.ctrl.T.Helper()
@@ -1256,6 +1350,21 @@ func (mr *MockdatabaseNamespaceMockRecorder) AggregateQuery(ctx, query, opts int
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AggregateQuery", reflect.TypeOf((*MockdatabaseNamespace)(nil).AggregateQuery), ctx, query, opts)
 }
 
+// IndexCardinality mocks base method
+func (m *MockdatabaseNamespace) IndexCardinality(ctx context.Context, opts index.CardinalityOptions) (index.CardinalityResults, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IndexCardinality", ctx, opts)
+	ret0, _ := ret[0].(index.CardinalityResults)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IndexCardinality indicates an expected call of IndexCardinality
+func (mr *MockdatabaseNamespaceMockRecorder) IndexCardinality(ctx, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IndexCardinality", reflect.TypeOf((*MockdatabaseNamespace)(nil).IndexCardinality), ctx, opts)
+}
+
 // ReadEncoded mocks base method
 func (m *MockdatabaseNamespace) ReadEncoded(ctx context.Context, id ident.ID, start, end time.Time) ([][]xio.BlockReader, error) {
 	m.ctrl.T.Helper()
@@ -1345,6 +1454,20 @@ func (mr *MockdatabaseNamespaceMockRecorder) WarmFlush(blockStart, flush interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WarmFlush", reflect.TypeOf((*MockdatabaseNamespace)(nil).WarmFlush), blockStart, flush)
 }
 
+// RollupFlush mocks base method
+func (m *MockdatabaseNamespace) RollupFlush(blockStart time.Time, source databaseNamespace, flush persist.FlushPreparer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RollupFlush", blockStart, source, flush)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RollupFlush indicates an expected call of RollupFlush
+func (mr *MockdatabaseNamespaceMockRecorder) RollupFlush(blockStart, source, flush interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RollupFlush", reflect.TypeOf((*MockdatabaseNamespace)(nil).RollupFlush), blockStart, source, flush)
+}
+
 // FlushIndex mocks base method
 func (m *MockdatabaseNamespace) FlushIndex(flush persist.IndexFlush) error {
 	m.ctrl.T.Helper()
@@ -1373,6 +1496,36 @@ func (mr *MockdatabaseNamespaceMockRecorder) ColdFlush(flush interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ColdFlush", reflect.TypeOf((*MockdatabaseNamespace)(nil).ColdFlush), flush)
 }
 
+// CompactFileSets mocks base method
+func (m *MockdatabaseNamespace) CompactFileSets(flush persist.FlushPreparer, limiter *compactionRateLimiter) (fileSetCompactionResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompactFileSets", flush, limiter)
+	ret0, _ := ret[0].(fileSetCompactionResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CompactFileSets indicates an expected call of CompactFileSets
+func (mr *MockdatabaseNamespaceMockRecorder) CompactFileSets(flush, limiter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompactFileSets", reflect.TypeOf((*MockdatabaseNamespace)(nil).CompactFileSets), flush, limiter)
+}
+
+// DropExpiredSeries mocks base method
+func (m *MockdatabaseNamespace) DropExpiredSeries(flush persist.FlushPreparer) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DropExpiredSeries", flush)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DropExpiredSeries indicates an expected call of DropExpiredSeries
+func (mr *MockdatabaseNamespaceMockRecorder) DropExpiredSeries(flush interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DropExpiredSeries", reflect.TypeOf((*MockdatabaseNamespace)(nil).DropExpiredSeries), flush)
+}
+
 // Snapshot mocks base method
 func (m *MockdatabaseNamespace) Snapshot(blockStart, snapshotTime time.Time, flush persist.SnapshotPreparer) error {
 	m.ctrl.T.Helper()
@@ -1417,6 +1570,21 @@ func (mr *MockdatabaseNamespaceMockRecorder) Truncate() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Truncate", reflect.TypeOf((*MockdatabaseNamespace)(nil).Truncate))
 }
 
+// DeleteTagged mocks base method
+func (m *MockdatabaseNamespace) DeleteTagged(ctx context.Context, query index.Query, opts index.QueryOptions) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTagged", ctx, query, opts)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteTagged indicates an expected call of DeleteTagged
+func (mr *MockdatabaseNamespaceMockRecorder) DeleteTagged(ctx, query, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTagged", reflect.TypeOf((*MockdatabaseNamespace)(nil).DeleteTagged), ctx, query, opts)
+}
+
 // Repair mocks base method
 func (m *MockdatabaseNamespace) Repair(repairer databaseShardRepairer, tr time0.Range) error {
 	m.ctrl.T.Helper()
@@ -1836,6 +2004,20 @@ func (mr *MockdatabaseShardMockRecorder) WarmFlush(blockStart, flush, nsCtx inte
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WarmFlush", reflect.TypeOf((*MockdatabaseShard)(nil).WarmFlush), blockStart, flush, nsCtx)
 }
 
+// RollupFlush mocks base method
+func (m *MockdatabaseShard) RollupFlush(blockStart time.Time, source namespace.Metadata, flush persist.FlushPreparer, nsCtx namespace.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RollupFlush", blockStart, source, flush, nsCtx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RollupFlush indicates an expected call of RollupFlush
+func (mr *MockdatabaseShardMockRecorder) RollupFlush(blockStart, source, flush, nsCtx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RollupFlush", reflect.TypeOf((*MockdatabaseShard)(nil).RollupFlush), blockStart, source, flush, nsCtx)
+}
+
 // ColdFlush mocks base method
 func (m *MockdatabaseShard) ColdFlush(flush persist.FlushPreparer, resources coldFlushReuseableResources, nsCtx namespace.Context) error {
 	m.ctrl.T.Helper()
@@ -1850,6 +2032,36 @@ func (mr *MockdatabaseShardMockRecorder) ColdFlush(flush, resources, nsCtx inter
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ColdFlush", reflect.TypeOf((*MockdatabaseShard)(nil).ColdFlush), flush, resources, nsCtx)
 }
 
+// CompactFileSets mocks base method
+func (m *MockdatabaseShard) CompactFileSets(flush persist.FlushPreparer, reader fs.DataFileSetReader, minVolumes int, limiter *compactionRateLimiter, nsCtx namespace.Context) (fileSetCompactionResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompactFileSets", flush, reader, minVolumes, limiter, nsCtx)
+	ret0, _ := ret[0].(fileSetCompactionResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CompactFileSets indicates an expected call of CompactFileSets
+func (mr *MockdatabaseShardMockRecorder) CompactFileSets(flush, reader, minVolumes, limiter, nsCtx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompactFileSets", reflect.TypeOf((*MockdatabaseShard)(nil).CompactFileSets), flush, reader, minVolumes, limiter, nsCtx)
+}
+
+// DropExpiredSeries mocks base method
+func (m *MockdatabaseShard) DropExpiredSeries(flush persist.FlushPreparer, reader fs.DataFileSetReader, ttls []time.Duration, nsCtx namespace.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DropExpiredSeries", flush, reader, ttls, nsCtx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DropExpiredSeries indicates an expected call of DropExpiredSeries
+func (mr *MockdatabaseShardMockRecorder) DropExpiredSeries(flush, reader, ttls, nsCtx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DropExpiredSeries", reflect.TypeOf((*MockdatabaseShard)(nil).DropExpiredSeries), flush, reader, ttls, nsCtx)
+}
+
 // Snapshot mocks base method
 func (m *MockdatabaseShard) Snapshot(blockStart, snapshotStart time.Time, flush persist.SnapshotPreparer, nsCtx namespace.Context) error {
 	m.ctrl.T.Helper()
@@ -1907,6 +2119,21 @@ func (mr *MockdatabaseShardMockRecorder) CleanupCompactedFileSets() *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CleanupCompactedFileSets", reflect.TypeOf((*MockdatabaseShard)(nil).CleanupCompactedFileSets))
 }
 
+// MoveFileSetsToColdTier mocks base method
+func (m *MockdatabaseShard) MoveFileSetsToColdTier(endBefore time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MoveFileSetsToColdTier", endBefore)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MoveFileSetsToColdTier indicates an expected call of MoveFileSetsToColdTier
+func (mr *MockdatabaseShardMockRecorder) MoveFileSetsToColdTier(endBefore interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveFileSetsToColdTier", reflect.TypeOf((*MockdatabaseShard)(nil).MoveFileSetsToColdTier), endBefore)
+}
+
 // Repair mocks base method
 func (m *MockdatabaseShard) Repair(ctx context.Context, nsCtx namespace.Context, nsMeta namespace.Metadata, tr time0.Range, repairer databaseShardRepairer) (repair.MetadataComparisonResult, error) {
 	m.ctrl.T.Helper()
@@ -1953,6 +2180,77 @@ func (mr *MockdatabaseShardMockRecorder) SeriesReadWriteRef(id, tags, opts inter
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SeriesReadWriteRef", reflect.TypeOf((*MockdatabaseShard)(nil).SeriesReadWriteRef), id, tags, opts)
 }
 
+// DeleteSeries mocks base method
+func (m *MockdatabaseShard) DeleteSeries(ids []ident.ID, deletedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSeries", ids, deletedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSeries indicates an expected call of DeleteSeries
+func (mr *MockdatabaseShardMockRecorder) DeleteSeries(ids, deletedAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSeries", reflect.TypeOf((*MockdatabaseShard)(nil).DeleteSeries), ids, deletedAt)
+}
+
+// SeriesDeleted mocks base method
+func (m *MockdatabaseShard) SeriesDeleted(id ident.ID) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SeriesDeleted", id)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// SeriesDeleted indicates an expected call of SeriesDeleted
+func (mr *MockdatabaseShardMockRecorder) SeriesDeleted(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SeriesDeleted", reflect.TypeOf((*MockdatabaseShard)(nil).SeriesDeleted), id)
+}
+
+// SeriesDeletedAt mocks base method
+func (m *MockdatabaseShard) SeriesDeletedAt(id ident.ID) (time.Time, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SeriesDeletedAt", id)
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// SeriesDeletedAt indicates an expected call of SeriesDeletedAt
+func (mr *MockdatabaseShardMockRecorder) SeriesDeletedAt(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SeriesDeletedAt", reflect.TypeOf((*MockdatabaseShard)(nil).SeriesDeletedAt), id)
+}
+
+// NumDeletedSeries mocks base method
+func (m *MockdatabaseShard) NumDeletedSeries() int {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NumDeletedSeries")
+	ret0, _ := ret[0].(int)
+	return ret0
+}
+
+// NumDeletedSeries indicates an expected call of NumDeletedSeries
+func (mr *MockdatabaseShardMockRecorder) NumDeletedSeries() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NumDeletedSeries", reflect.TypeOf((*MockdatabaseShard)(nil).NumDeletedSeries))
+}
+
+// SeriesValueMatches mocks base method
+func (m *MockdatabaseShard) SeriesValueMatches(id ident.ID, filter index.ValueFilter, start, end time.Time) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SeriesValueMatches", id, filter, start, end)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// SeriesValueMatches indicates an expected call of SeriesValueMatches
+func (mr *MockdatabaseShardMockRecorder) SeriesValueMatches(id, filter, start, end interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SeriesValueMatches", reflect.TypeOf((*MockdatabaseShard)(nil).SeriesValueMatches), id, filter, start, end)
+}
+
 // MocknamespaceIndex is a mock of namespaceIndex interface
 type MocknamespaceIndex struct {
 	ctrl     *gomock.Controller
@@ -1988,6 +2286,30 @@ func (mr *MocknamespaceIndexMockRecorder) AssignShardSet(shardSet interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignShardSet", reflect.TypeOf((*MocknamespaceIndex)(nil).AssignShardSet), shardSet)
 }
 
+// AssignSeriesDeletedFilter mocks base method
+func (m *MocknamespaceIndex) AssignSeriesDeletedFilter(fn func(ident.ID) bool) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "AssignSeriesDeletedFilter", fn)
+}
+
+// AssignSeriesDeletedFilter indicates an expected call of AssignSeriesDeletedFilter
+func (mr *MocknamespaceIndexMockRecorder) AssignSeriesDeletedFilter(fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignSeriesDeletedFilter", reflect.TypeOf((*MocknamespaceIndex)(nil).AssignSeriesDeletedFilter), fn)
+}
+
+// AssignSeriesValueFilter mocks base method
+func (m *MocknamespaceIndex) AssignSeriesValueFilter(fn func(ident.ID, index.QueryOptions) bool) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "AssignSeriesValueFilter", fn)
+}
+
+// AssignSeriesValueFilter indicates an expected call of AssignSeriesValueFilter
+func (mr *MocknamespaceIndexMockRecorder) AssignSeriesValueFilter(fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignSeriesValueFilter", reflect.TypeOf((*MocknamespaceIndex)(nil).AssignSeriesValueFilter), fn)
+}
+
 // BlockStartForWriteTime mocks base method
 func (m *MocknamespaceIndex) BlockStartForWriteTime(writeTime time.Time) time0.UnixNano {
 	m.ctrl.T.Helper()
@@ -2016,6 +2338,20 @@ func (mr *MocknamespaceIndexMockRecorder) WriteBatch(batch interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteBatch", reflect.TypeOf((*MocknamespaceIndex)(nil).WriteBatch), batch)
 }
 
+// WriteRollup mocks base method
+func (m *MocknamespaceIndex) WriteRollup(shard uint32, blockStart, blockEnd time.Time, docs []doc.Document) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WriteRollup", shard, blockStart, blockEnd, docs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WriteRollup indicates an expected call of WriteRollup
+func (mr *MocknamespaceIndexMockRecorder) WriteRollup(shard, blockStart, blockEnd, docs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteRollup", reflect.TypeOf((*MocknamespaceIndex)(nil).WriteRollup), shard, blockStart, blockEnd, docs)
+}
+
 // Query mocks base method
 func (m *MocknamespaceIndex) Query(ctx context.Context, query index.Query, opts index.QueryOptions) (index.QueryResult, error) {
 	m.ctrl.T.Helper()
@@ -2046,6 +2382,36 @@ func (mr *MocknamespaceIndexMockRecorder) AggregateQuery(ctx, query, opts interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AggregateQuery", reflect.TypeOf((*MocknamespaceIndex)(nil).AggregateQuery), ctx, query, opts)
 }
 
+// Cardinality mocks base method
+func (m *MocknamespaceIndex) Cardinality(ctx context.Context, opts index.CardinalityOptions) (index.CardinalityResults, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Cardinality", ctx, opts)
+	ret0, _ := ret[0].(index.CardinalityResults)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Cardinality indicates an expected call of Cardinality
+func (mr *MocknamespaceIndexMockRecorder) Cardinality(ctx, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cardinality", reflect.TypeOf((*MocknamespaceIndex)(nil).Cardinality), ctx, opts)
+}
+
+// SeriesTTLs mocks base method
+func (m *MocknamespaceIndex) SeriesTTLs(ctx context.Context, start, end time.Time) ([]time.Duration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SeriesTTLs", ctx, start, end)
+	ret0, _ := ret[0].([]time.Duration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SeriesTTLs indicates an expected call of SeriesTTLs
+func (mr *MocknamespaceIndexMockRecorder) SeriesTTLs(ctx, start, end interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SeriesTTLs", reflect.TypeOf((*MocknamespaceIndex)(nil).SeriesTTLs), ctx, start, end)
+}
+
 // Bootstrap mocks base method
 func (m *MocknamespaceIndex) Bootstrap(bootstrapResults result.IndexResults) error {
 	m.ctrl.T.Helper()
@@ -2313,6 +2679,21 @@ func (mr *MockdatabaseFlushManagerMockRecorder) Flush(startTime interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Flush", reflect.TypeOf((*MockdatabaseFlushManager)(nil).Flush), startTime)
 }
 
+// Snapshot mocks base method
+func (m *MockdatabaseFlushManager) Snapshot(startTime time.Time) (uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Snapshot", startTime)
+	ret0, _ := ret[0].(uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Snapshot indicates an expected call of Snapshot
+func (mr *MockdatabaseFlushManagerMockRecorder) Snapshot(startTime interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Snapshot", reflect.TypeOf((*MockdatabaseFlushManager)(nil).Snapshot), startTime)
+}
+
 // LastSuccessfulSnapshotStartTime mocks base method
 func (m *MockdatabaseFlushManager) LastSuccessfulSnapshotStartTime() (time.Time, bool) {
 	m.ctrl.T.Helper()
@@ -2389,6 +2770,55 @@ func (mr *MockdatabaseCleanupManagerMockRecorder) Report() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Report", reflect.TypeOf((*MockdatabaseCleanupManager)(nil).Report))
 }
 
+// MockdatabaseCompactionManager is a mock of databaseCompactionManager interface
+type MockdatabaseCompactionManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockdatabaseCompactionManagerMockRecorder
+}
+
+// MockdatabaseCompactionManagerMockRecorder is the mock recorder for MockdatabaseCompactionManager
+type MockdatabaseCompactionManagerMockRecorder struct {
+	mock *MockdatabaseCompactionManager
+}
+
+// NewMockdatabaseCompactionManager creates a new mock instance
+func NewMockdatabaseCompactionManager(ctrl *gomock.Controller) *MockdatabaseCompactionManager {
+	mock := &MockdatabaseCompactionManager{ctrl: ctrl}
+	mock.recorder = &MockdatabaseCompactionManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockdatabaseCompactionManager) EXPECT() *MockdatabaseCompactionManagerMockRecorder {
+	return m.recorder
+}
+
+// Compact mocks base method
+func (m *MockdatabaseCompactionManager) Compact(t time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Compact", t)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Compact indicates an expected call of Compact
+func (mr *MockdatabaseCompactionManagerMockRecorder) Compact(t interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Compact", reflect.TypeOf((*MockdatabaseCompactionManager)(nil).Compact), t)
+}
+
+// Report mocks base method
+func (m *MockdatabaseCompactionManager) Report() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Report")
+}
+
+// Report indicates an expected call of Report
+func (mr *MockdatabaseCompactionManagerMockRecorder) Report() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Report", reflect.TypeOf((*MockdatabaseCompactionManager)(nil).Report))
+}
+
 // MockdatabaseFileSystemManager is a mock of databaseFileSystemManager interface
 type MockdatabaseFileSystemManager struct {
 	ctrl     *gomock.Controller
@@ -2440,6 +2870,21 @@ func (mr *MockdatabaseFileSystemManagerMockRecorder) Flush(t interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Flush", reflect.TypeOf((*MockdatabaseFileSystemManager)(nil).Flush), t)
 }
 
+// Snapshot mocks base method
+func (m *MockdatabaseFileSystemManager) Snapshot(t time.Time) (uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Snapshot", t)
+	ret0, _ := ret[0].(uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Snapshot indicates an expected call of Snapshot
+func (mr *MockdatabaseFileSystemManagerMockRecorder) Snapshot(t interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Snapshot", reflect.TypeOf((*MockdatabaseFileSystemManager)(nil).Snapshot), t)
+}
+
 // Disable mocks base method
 func (m *MockdatabaseFileSystemManager) Disable() fileOpStatus {
 	m.ctrl.T.Helper()
@@ -2790,6 +3235,21 @@ func (mr *MockdatabaseMediatorMockRecorder) EnableFileOps() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnableFileOps", reflect.TypeOf((*MockdatabaseMediator)(nil).EnableFileOps))
 }
 
+// Snapshot mocks base method
+func (m *MockdatabaseMediator) Snapshot(startTime time.Time) (uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Snapshot", startTime)
+	ret0, _ := ret[0].(uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Snapshot indicates an expected call of Snapshot
+func (mr *MockdatabaseMediatorMockRecorder) Snapshot(startTime interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Snapshot", reflect.TypeOf((*MockdatabaseMediator)(nil).Snapshot), startTime)
+}
+
 // Tick mocks base method
 func (m *MockdatabaseMediator) Tick(forceType forceType, startTime time.Time) error {
 	m.ctrl.T.Helper()
@@ -3526,6 +3986,34 @@ func (mr *MockOptionsMockRecorder) EncoderPool() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EncoderPool", reflect.TypeOf((*MockOptions)(nil).EncoderPool))
 }
 
+// SetHistogramEncoderPool mocks base method
+func (m *MockOptions) SetHistogramEncoderPool(value encoding.EncoderPool) Options {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetHistogramEncoderPool", value)
+	ret0, _ := ret[0].(Options)
+	return ret0
+}
+
+// SetHistogramEncoderPool indicates an expected call of SetHistogramEncoderPool
+func (mr *MockOptionsMockRecorder) SetHistogramEncoderPool(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetHistogramEncoderPool", reflect.TypeOf((*MockOptions)(nil).SetHistogramEncoderPool), value)
+}
+
+// HistogramEncoderPool mocks base method
+func (m *MockOptions) HistogramEncoderPool() encoding.EncoderPool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HistogramEncoderPool")
+	ret0, _ := ret[0].(encoding.EncoderPool)
+	return ret0
+}
+
+// HistogramEncoderPool indicates an expected call of HistogramEncoderPool
+func (mr *MockOptionsMockRecorder) HistogramEncoderPool() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HistogramEncoderPool", reflect.TypeOf((*MockOptions)(nil).HistogramEncoderPool))
+}
+
 // SetSegmentReaderPool mocks base method
 func (m *MockOptions) SetSegmentReaderPool(value xio.SegmentReaderPool) Options {
 	m.ctrl.T.Helper()
@@ -3946,6 +4434,62 @@ func (mr *MockOptionsMockRecorder) MemoryTracker() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MemoryTracker", reflect.TypeOf((*MockOptions)(nil).MemoryTracker))
 }
 
+// SetCompactionMinVolumes mocks base method
+func (m *MockOptions) SetCompactionMinVolumes(value int) Options {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetCompactionMinVolumes", value)
+	ret0, _ := ret[0].(Options)
+	return ret0
+}
+
+// SetCompactionMinVolumes indicates an expected call of SetCompactionMinVolumes
+func (mr *MockOptionsMockRecorder) SetCompactionMinVolumes(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCompactionMinVolumes", reflect.TypeOf((*MockOptions)(nil).SetCompactionMinVolumes), value)
+}
+
+// CompactionMinVolumes mocks base method
+func (m *MockOptions) CompactionMinVolumes() int {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompactionMinVolumes")
+	ret0, _ := ret[0].(int)
+	return ret0
+}
+
+// CompactionMinVolumes indicates an expected call of CompactionMinVolumes
+func (mr *MockOptionsMockRecorder) CompactionMinVolumes() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompactionMinVolumes", reflect.TypeOf((*MockOptions)(nil).CompactionMinVolumes))
+}
+
+// SetCompactionRateLimitOptions mocks base method
+func (m *MockOptions) SetCompactionRateLimitOptions(value ratelimit.Options) Options {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetCompactionRateLimitOptions", value)
+	ret0, _ := ret[0].(Options)
+	return ret0
+}
+
+// SetCompactionRateLimitOptions indicates an expected call of SetCompactionRateLimitOptions
+func (mr *MockOptionsMockRecorder) SetCompactionRateLimitOptions(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCompactionRateLimitOptions", reflect.TypeOf((*MockOptions)(nil).SetCompactionRateLimitOptions), value)
+}
+
+// CompactionRateLimitOptions mocks base method
+func (m *MockOptions) CompactionRateLimitOptions() ratelimit.Options {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompactionRateLimitOptions")
+	ret0, _ := ret[0].(ratelimit.Options)
+	return ret0
+}
+
+// CompactionRateLimitOptions indicates an expected call of CompactionRateLimitOptions
+func (mr *MockOptionsMockRecorder) CompactionRateLimitOptions() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompactionRateLimitOptions", reflect.TypeOf((*MockOptions)(nil).CompactionRateLimitOptions))
+}
+
 // SetMmapReporter mocks base method
 func (m *MockOptions) SetMmapReporter(mmapReporter mmap.Reporter) Options {
 	m.ctrl.T.Helper()
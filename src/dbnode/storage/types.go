@@ -30,7 +30,9 @@ import (
 	"github.com/m3db/m3/src/dbnode/namespace"
 	"github.com/m3db/m3/src/dbnode/persist"
 	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/dbnode/persist/fs/backup"
 	"github.com/m3db/m3/src/dbnode/persist/fs/commitlog"
+	"github.com/m3db/m3/src/dbnode/ratelimit"
 	"github.com/m3db/m3/src/dbnode/runtime"
 	"github.com/m3db/m3/src/dbnode/sharding"
 	"github.com/m3db/m3/src/dbnode/storage/block"
@@ -43,6 +45,7 @@ import (
 	"github.com/m3db/m3/src/dbnode/ts"
 	"github.com/m3db/m3/src/dbnode/x/xio"
 	"github.com/m3db/m3/src/dbnode/x/xpool"
+	"github.com/m3db/m3/src/m3ninx/doc"
 	"github.com/m3db/m3/src/x/context"
 	"github.com/m3db/m3/src/x/ident"
 	"github.com/m3db/m3/src/x/instrument"
@@ -50,6 +53,8 @@ import (
 	"github.com/m3db/m3/src/x/pool"
 	xsync "github.com/m3db/m3/src/x/sync"
 	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/pborman/uuid"
 )
 
 // PageToken is an opaque paging token.
@@ -160,6 +165,14 @@ type Database interface {
 		opts index.AggregationOptions,
 	) (index.AggregateQueryResult, error)
 
+	// IndexCardinality returns the number of distinct values and series of
+	// the fields and label pairs indexed by the given namespace.
+	IndexCardinality(
+		ctx context.Context,
+		namespace ident.ID,
+		opts index.CardinalityOptions,
+	) (index.CardinalityResults, error)
+
 	// ReadEncoded retrieves encoded segments for an ID
 	ReadEncoded(
 		ctx context.Context,
@@ -211,6 +224,24 @@ type Database interface {
 	// Truncate truncates data for the given namespace.
 	Truncate(namespace ident.ID) (int64, error)
 
+	// DeleteTagged deletes the series matching the given query from the
+	// given namespace and returns the number of series deleted.
+	DeleteTagged(
+		ctx context.Context,
+		namespace ident.ID,
+		query index.Query,
+		opts index.QueryOptions,
+	) (int64, error)
+
+	// Backup snapshots the database and then archives the flushed filesets
+	// and that snapshot of the given namespace to the blob store under the
+	// given backup ID, file operations are paused while the files are staged.
+	Backup(
+		namespace ident.ID,
+		backupID string,
+		store backup.BlobStore,
+	) (backup.Manifest, error)
+
 	// BootstrapState captures and returns a snapshot of the databases'
 	// bootstrap state.
 	BootstrapState() DatabaseBootstrapState
@@ -313,6 +344,13 @@ type databaseNamespace interface {
 		opts index.AggregationOptions,
 	) (index.AggregateQueryResult, error)
 
+	// IndexCardinality returns the number of distinct values and series of
+	// the fields and label pairs indexed by the namespace.
+	IndexCardinality(
+		ctx context.Context,
+		opts index.CardinalityOptions,
+	) (index.CardinalityResults, error)
+
 	// ReadEncoded reads data for given id within [start, end).
 	ReadEncoded(
 		ctx context.Context,
@@ -350,6 +388,14 @@ type databaseNamespace interface {
 	// WarmFlush flushes in-memory WarmWrites.
 	WarmFlush(blockStart time.Time, flush persist.FlushPreparer) error
 
+	// RollupFlush rolls up the flushed data of the source namespace for the
+	// block and persists it, only applies to aggregated namespaces.
+	RollupFlush(
+		blockStart time.Time,
+		source databaseNamespace,
+		flush persist.FlushPreparer,
+	) error
+
 	// FlushIndex flushes in-memory index data.
 	FlushIndex(
 		flush persist.IndexFlush,
@@ -360,6 +406,20 @@ type databaseNamespace interface {
 		flush persist.FlushPreparer,
 	) error
 
+	// CompactFileSets compacts the volumes of blocks that have accumulated
+	// at least the configured minimum number of volumes into a single volume.
+	CompactFileSets(
+		flush persist.FlushPreparer,
+		limiter *compactionRateLimiter,
+	) (fileSetCompactionResult, error)
+
+	// DropExpiredSeries rewrites the blocks of the namespace that contain
+	// data of series whose TTL has expired since the blocks were written,
+	// it returns the number of blocks rewritten.
+	DropExpiredSeries(
+		flush persist.FlushPreparer,
+	) (int, error)
+
 	// Snapshot snapshots unflushed in-memory WarmWrites.
 	Snapshot(blockStart, snapshotTime time.Time, flush persist.SnapshotPreparer) error
 
@@ -371,6 +431,14 @@ type databaseNamespace interface {
 	// Truncate truncates the in-memory data for this namespace.
 	Truncate() (int64, error)
 
+	// DeleteTagged deletes the series matching the given query and returns
+	// the number of series deleted.
+	DeleteTagged(
+		ctx context.Context,
+		query index.Query,
+		opts index.QueryOptions,
+	) (int64, error)
+
 	// Repair repairs the namespace data for a given time range
 	Repair(repairer databaseShardRepairer, tr xtime.Range) error
 
@@ -518,6 +586,15 @@ type databaseShard interface {
 		nsCtx namespace.Context,
 	) error
 
+	// RollupFlush rolls up the flushed data of the same shard of the source
+	// namespace for the block and persists it in this shard.
+	RollupFlush(
+		blockStart time.Time,
+		source namespace.Metadata,
+		flush persist.FlushPreparer,
+		nsCtx namespace.Context,
+	) error
+
 	// ColdFlush flushes the unflushed ColdWrites in this shard.
 	ColdFlush(
 		flush persist.FlushPreparer,
@@ -525,6 +602,27 @@ type databaseShard interface {
 		nsCtx namespace.Context,
 	) error
 
+	// CompactFileSets compacts the volumes of the blocks in this shard that
+	// have at least minVolumes volumes on disk into a single volume.
+	CompactFileSets(
+		flush persist.FlushPreparer,
+		reader fs.DataFileSetReader,
+		minVolumes int,
+		limiter *compactionRateLimiter,
+		nsCtx namespace.Context,
+	) (fileSetCompactionResult, error)
+
+	// DropExpiredSeries rewrites the blocks in this shard that contain data
+	// of series whose TTL has expired since the blocks were written, given
+	// the TTLs of the series indexed by the namespace. It returns the number
+	// of blocks rewritten.
+	DropExpiredSeries(
+		flush persist.FlushPreparer,
+		reader fs.DataFileSetReader,
+		ttls []time.Duration,
+		nsCtx namespace.Context,
+	) (int, error)
+
 	// Snapshot snapshot's the unflushed WarmWrites in this shard.
 	Snapshot(
 		blockStart time.Time,
@@ -544,6 +642,11 @@ type databaseShard interface {
 	// fileset for that block.
 	CleanupCompactedFileSets() error
 
+	// MoveFileSetsToColdTier moves the fileset files of blocks that ended
+	// before the given time to the cold tier of the namespace, returning the
+	// number of filesets moved.
+	MoveFileSetsToColdTier(endBefore time.Time) (int, error)
+
 	// Repair repairs the shard data for a given time.
 	Repair(
 		ctx context.Context,
@@ -566,6 +669,32 @@ type databaseShard interface {
 		tags ident.TagIterator,
 		opts ShardSeriesReadWriteRefOptions,
 	) (SeriesReadWriteRef, error)
+
+	// DeleteSeries removes the given series from the shard and persists a
+	// tombstone for each so that data written before the deletion time is
+	// no longer returned and is not resurrected by flushes, bootstraps or
+	// repairs.
+	DeleteSeries(ids []ident.ID, deletedAt time.Time) error
+
+	// SeriesDeleted returns whether the series has been deleted and has not
+	// been written to since.
+	SeriesDeleted(id ident.ID) bool
+
+	// SeriesDeletedAt returns the time the series was deleted if it has a
+	// tombstone, persisted data for the series before that time is deleted.
+	SeriesDeletedAt(id ident.ID) (time.Time, bool)
+
+	// NumDeletedSeries returns the number of series tombstones in the shard.
+	NumDeletedSeries() int
+
+	// SeriesValueMatches returns whether the values of the series in the
+	// given range may satisfy the filter, it returns true whenever the
+	// series values are not fully known.
+	SeriesValueMatches(
+		id ident.ID,
+		filter index.ValueFilter,
+		start, end time.Time,
+	) bool
 }
 
 // ShardSeriesReadWriteRefOptions are options for SeriesReadWriteRef
@@ -579,6 +708,15 @@ type namespaceIndex interface {
 	// AssignShardSet sets the shard set assignment and returns immediately.
 	AssignShardSet(shardSet sharding.ShardSet)
 
+	// AssignSeriesDeletedFilter sets the function used to exclude deleted
+	// series from query results, a nil function disables the filtering.
+	AssignSeriesDeletedFilter(fn func(id ident.ID) bool)
+
+	// AssignSeriesValueFilter sets the function used to exclude series whose
+	// values do not satisfy the value filter of a query, a nil function
+	// disables the filtering.
+	AssignSeriesValueFilter(fn func(id ident.ID, opts index.QueryOptions) bool)
+
 	// BlockStartForWriteTime returns the index block start
 	// time for the given writeTime.
 	BlockStartForWriteTime(
@@ -590,6 +728,15 @@ type namespaceIndex interface {
 		batch *index.WriteBatch,
 	) error
 
+	// WriteRollup indexes the documents of the series rolled up for the given
+	// shard and data block in the index block covering the data block, even
+	// when the index block has already been sealed.
+	WriteRollup(
+		shard uint32,
+		blockStart, blockEnd time.Time,
+		docs []doc.Document,
+	) error
+
 	// Query resolves the given query into known IDs.
 	Query(
 		ctx context.Context,
@@ -604,6 +751,20 @@ type namespaceIndex interface {
 		opts index.AggregationOptions,
 	) (index.AggregateQueryResult, error)
 
+	// Cardinality returns the number of distinct values and series of the
+	// fields and label pairs indexed by blocks in the given time range.
+	Cardinality(
+		ctx context.Context,
+		opts index.CardinalityOptions,
+	) (index.CardinalityResults, error)
+
+	// SeriesTTLs returns the distinct TTLs of the series indexed by blocks
+	// in the given time range.
+	SeriesTTLs(
+		ctx context.Context,
+		start, end time.Time,
+	) ([]time.Duration, error)
+
 	// Bootstrap bootstraps the index the provided segments.
 	Bootstrap(
 		bootstrapResults result.IndexResults,
@@ -684,6 +845,10 @@ type databaseFlushManager interface {
 	// Flush flushes in-memory data to persistent storage.
 	Flush(startTime time.Time) error
 
+	// Snapshot rotates the commit log, cold flushes and snapshots in-memory
+	// data to persistent storage, it returns the ID of the snapshot.
+	Snapshot(startTime time.Time) (uuid.UUID, error)
+
 	// LastSuccessfulSnapshotStartTime returns the start time of the last
 	// successful snapshot, if any.
 	LastSuccessfulSnapshotStartTime() (time.Time, bool)
@@ -701,6 +866,16 @@ type databaseCleanupManager interface {
 	Report()
 }
 
+// databaseCompactionManager manages compacting the volumes of filesets.
+type databaseCompactionManager interface {
+	// Compact compacts the volumes of blocks that have accumulated many
+	// volumes on disk.
+	Compact(t time.Time) error
+
+	// Report reports runtime information.
+	Report()
+}
+
 // databaseFileSystemManager manages the database related filesystem activities.
 type databaseFileSystemManager interface {
 	// Cleanup cleans up data not needed in the persistent storage.
@@ -709,6 +884,10 @@ type databaseFileSystemManager interface {
 	// Flush flushes in-memory data to persistent storage.
 	Flush(t time.Time) error
 
+	// Snapshot rotates the commit log, cold flushes and snapshots in-memory
+	// data to persistent storage, it returns the ID of the snapshot.
+	Snapshot(t time.Time) (uuid.UUID, error)
+
 	// Disable disables the filesystem manager and prevents it from
 	// performing file operations, returns the current file operation status.
 	Disable() fileOpStatus
@@ -794,6 +973,10 @@ type databaseMediator interface {
 	// EnableFileOps enables file operations.
 	EnableFileOps()
 
+	// Snapshot rotates the commit log and snapshots in-memory data to
+	// persistent storage, callers should disable file operations beforehand.
+	Snapshot(startTime time.Time) (uuid.UUID, error)
+
 	// Tick performs a tick.
 	Tick(forceType forceType, startTime time.Time) error
 
@@ -964,6 +1147,14 @@ type Options interface {
 	// EncoderPool returns the contextPool.
 	EncoderPool() encoding.EncoderPool
 
+	// SetHistogramEncoderPool sets the encoder pool used by namespaces with
+	// histograms enabled, histograms are not supported if it is nil.
+	SetHistogramEncoderPool(value encoding.EncoderPool) Options
+
+	// HistogramEncoderPool returns the encoder pool used by namespaces with
+	// histograms enabled.
+	HistogramEncoderPool() encoding.EncoderPool
+
 	// SetSegmentReaderPool sets the contextPool.
 	SetSegmentReaderPool(value xio.SegmentReaderPool) Options
 
@@ -1054,6 +1245,24 @@ type Options interface {
 	// MemoryTracker returns the MemoryTracker.
 	MemoryTracker() MemoryTracker
 
+	// SetCompactionMinVolumes sets the minimum number of volumes on disk for
+	// a block before they are compacted into a single volume, zero disables
+	// compaction.
+	SetCompactionMinVolumes(value int) Options
+
+	// CompactionMinVolumes returns the minimum number of volumes on disk for
+	// a block before they are compacted into a single volume, zero disables
+	// compaction.
+	CompactionMinVolumes() int
+
+	// SetCompactionRateLimitOptions sets the rate limit options for reading
+	// the volumes being compacted.
+	SetCompactionRateLimitOptions(value ratelimit.Options) Options
+
+	// CompactionRateLimitOptions returns the rate limit options for reading
+	// the volumes being compacted.
+	CompactionRateLimitOptions() ratelimit.Options
+
 	// SetMmapReporter sets the mmap reporter.
 	SetMmapReporter(mmapReporter mmap.Reporter) Options
 
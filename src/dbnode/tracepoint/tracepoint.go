@@ -52,6 +52,9 @@ const (
 	// DBAggregateQuery is the operation name for the db AggregateQuery path.
 	DBAggregateQuery = "storage.db.AggregateQuery"
 
+	// DBIndexCardinality is the operation name for the db IndexCardinality path.
+	DBIndexCardinality = "storage.db.IndexCardinality"
+
 	// DBReadEncoded is the operation name for the db ReadEncoded path.
 	DBReadEncoded = "storage.db.ReadEncoded"
 
@@ -73,9 +76,15 @@ const (
 	// NSIdxAggregateQuery is the operation name for the nsIndex AggregateQuery path.
 	NSIdxAggregateQuery = "storage.nsIndex.AggregateQuery"
 
+	// NSIdxCardinality is the operation name for the nsIndex Cardinality path.
+	NSIdxCardinality = "storage.nsIndex.Cardinality"
+
 	// NSIdxQueryHelper is the operation name for the nsIndex query path.
 	NSIdxQueryHelper = "storage.nsIndex.query"
 
+	// NSIdxQueryPage is the operation name for the nsIndex paginated query path.
+	NSIdxQueryPage = "storage.nsIndex.queryPage"
+
 	// NSIdxBlockQuery is the operation name for the nsIndex block query path.
 	NSIdxBlockQuery = "storage.nsIndex.blockQuery"
 
@@ -85,6 +94,9 @@ const (
 	// BlockQuery is the operation name for the index block query path.
 	BlockQuery = "storage/index.block.Query"
 
+	// BlockQueryPage is the operation name for the index block paginated query path.
+	BlockQueryPage = "storage/index.block.QueryPage"
+
 	// BlockAggregate is the operation name for the index block aggregate path.
 	BlockAggregate = "storage/index.block.Aggregate"
 )
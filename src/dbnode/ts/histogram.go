@@ -0,0 +1,194 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package ts
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+const (
+	// histogramAnnotationMagic is the first byte of annotations that hold a
+	// histogram, it is followed by the version of the histogram encoding.
+	histogramAnnotationMagic   byte = 0xf1
+	histogramAnnotationVersion byte = 1
+)
+
+var (
+	errHistogramAnnotationTooShort = errors.New("histogram annotation is too short")
+	errHistogramNoBuckets          = errors.New("histogram has no buckets")
+)
+
+// Histogram is a distribution of values as the cumulative counts of values
+// less than or equal to the upper bound of each of its buckets. Only the
+// buckets that are needed have to be present, the buckets of histograms of
+// the same series can differ between datapoints.
+type Histogram struct {
+	Buckets []HistogramBucket
+}
+
+// HistogramBucket is a single bucket of a histogram.
+type HistogramBucket struct {
+	UpperBound float64
+	Count      uint64
+}
+
+// HistogramDatapoint is a datapoint whose value is a histogram, the value of
+// the datapoint is the total count of the histogram.
+type HistogramDatapoint struct {
+	Datapoint
+	Histogram Histogram
+}
+
+// NewHistogramDatapoint returns a new histogram datapoint.
+func NewHistogramDatapoint(timestamp time.Time, h Histogram) HistogramDatapoint {
+	return HistogramDatapoint{
+		Datapoint: Datapoint{
+			Timestamp: timestamp,
+			Value:     float64(h.Count()),
+		},
+		Histogram: h,
+	}
+}
+
+// Equal returns whether one HistogramDatapoint is equal to another.
+func (d HistogramDatapoint) Equal(x HistogramDatapoint) bool {
+	return d.Datapoint.Equal(x.Datapoint) && d.Histogram.Equal(x.Histogram)
+}
+
+// Count returns the total count of the histogram.
+func (h Histogram) Count() uint64 {
+	if len(h.Buckets) == 0 {
+		return 0
+	}
+	return h.Buckets[len(h.Buckets)-1].Count
+}
+
+// Equal returns whether one Histogram is equal to another.
+func (h Histogram) Equal(x Histogram) bool {
+	if len(h.Buckets) != len(x.Buckets) {
+		return false
+	}
+	for i, b := range h.Buckets {
+		if b != x.Buckets[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Validate validates that the histogram has buckets with strictly increasing
+// upper bounds and cumulative counts.
+func (h Histogram) Validate() error {
+	if len(h.Buckets) == 0 {
+		return errHistogramNoBuckets
+	}
+	for i, b := range h.Buckets {
+		if math.IsNaN(b.UpperBound) {
+			return fmt.Errorf("histogram bucket %d has a NaN upper bound", i)
+		}
+		if i == 0 {
+			continue
+		}
+		prev := h.Buckets[i-1]
+		if b.UpperBound <= prev.UpperBound {
+			return fmt.Errorf("histogram bucket %d upper bound %v is not greater than %v",
+				i, b.UpperBound, prev.UpperBound)
+		}
+		if b.Count < prev.Count {
+			return fmt.Errorf("histogram bucket %d count %d is less than %d",
+				i, b.Count, prev.Count)
+		}
+	}
+	return nil
+}
+
+// Annotation returns the histogram encoded as an annotation, which is how
+// histograms are written alongside the datapoint that holds their count.
+func (h Histogram) Annotation() Annotation {
+	return h.AppendAnnotation(nil)
+}
+
+// AppendAnnotation appends the histogram encoded as an annotation to the
+// buffer and returns the extended buffer.
+func (h Histogram) AppendAnnotation(buf []byte) Annotation {
+	var scratch [binary.MaxVarintLen64]byte
+	buf = append(buf, histogramAnnotationMagic, histogramAnnotationVersion)
+	n := binary.PutUvarint(scratch[:], uint64(len(h.Buckets)))
+	buf = append(buf, scratch[:n]...)
+	for _, b := range h.Buckets {
+		binary.BigEndian.PutUint64(scratch[:8], math.Float64bits(b.UpperBound))
+		buf = append(buf, scratch[:8]...)
+		n = binary.PutUvarint(scratch[:], b.Count)
+		buf = append(buf, scratch[:n]...)
+	}
+	return buf
+}
+
+// IsHistogramAnnotation returns whether the annotation holds a histogram.
+func IsHistogramAnnotation(a Annotation) bool {
+	return len(a) >= 2 && a[0] == histogramAnnotationMagic
+}
+
+// HistogramFromAnnotation decodes a histogram from an annotation, the
+// returned bool is false if the annotation does not hold a histogram.
+func HistogramFromAnnotation(a Annotation) (Histogram, bool, error) {
+	if !IsHistogramAnnotation(a) {
+		return Histogram{}, false, nil
+	}
+	if version := a[1]; version != histogramAnnotationVersion {
+		return Histogram{}, true, fmt.Errorf(
+			"unknown histogram annotation version: %d", version)
+	}
+
+	a = a[2:]
+	numBuckets, n := binary.Uvarint(a)
+	if n <= 0 {
+		return Histogram{}, true, errHistogramAnnotationTooShort
+	}
+	a = a[n:]
+
+	// NB: Each bucket takes at least nine bytes so this bounds the
+	// allocation by the size of the annotation.
+	if numBuckets > uint64(len(a)/9) {
+		return Histogram{}, true, errHistogramAnnotationTooShort
+	}
+	buckets := make([]HistogramBucket, 0, int(numBuckets))
+	for i := uint64(0); i < numBuckets; i++ {
+		if len(a) < 8 {
+			return Histogram{}, true, errHistogramAnnotationTooShort
+		}
+		upperBound := math.Float64frombits(binary.BigEndian.Uint64(a[:8]))
+		count, n := binary.Uvarint(a[8:])
+		if n <= 0 {
+			return Histogram{}, true, errHistogramAnnotationTooShort
+		}
+		a = a[8+n:]
+		buckets = append(buckets, HistogramBucket{
+			UpperBound: upperBound,
+			Count:      count,
+		})
+	}
+	return Histogram{Buckets: buckets}, true, nil
+}
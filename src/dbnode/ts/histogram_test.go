@@ -0,0 +1,97 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package ts
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistogramAnnotationRoundTrip(t *testing.T) {
+	h := Histogram{Buckets: []HistogramBucket{
+		{UpperBound: 0.1, Count: 3},
+		{UpperBound: 1, Count: 10},
+		{UpperBound: math.Inf(1), Count: 1 << 40},
+	}}
+	require.NoError(t, h.Validate())
+
+	a := h.Annotation()
+	require.True(t, IsHistogramAnnotation(a))
+
+	decoded, ok, err := HistogramFromAnnotation(a)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.True(t, h.Equal(decoded))
+	assert.Equal(t, uint64(1<<40), decoded.Count())
+}
+
+func TestHistogramFromAnnotationNotHistogram(t *testing.T) {
+	_, ok, err := HistogramFromAnnotation(Annotation("foo"))
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	_, ok, err = HistogramFromAnnotation(nil)
+	require.NoError(t, err)
+	require.False(t, ok)
+}
+
+func TestHistogramFromAnnotationTruncated(t *testing.T) {
+	h := Histogram{Buckets: []HistogramBucket{
+		{UpperBound: 1, Count: 1},
+		{UpperBound: 2, Count: 2},
+	}}
+	a := h.Annotation()
+	for i := 2; i < len(a); i++ {
+		_, ok, err := HistogramFromAnnotation(a[:i])
+		require.True(t, ok)
+		require.Error(t, err)
+	}
+}
+
+func TestHistogramValidate(t *testing.T) {
+	require.Error(t, Histogram{}.Validate())
+	require.Error(t, Histogram{Buckets: []HistogramBucket{
+		{UpperBound: 2, Count: 1},
+		{UpperBound: 1, Count: 2},
+	}}.Validate())
+	require.Error(t, Histogram{Buckets: []HistogramBucket{
+		{UpperBound: 1, Count: 2},
+		{UpperBound: 2, Count: 1},
+	}}.Validate())
+	require.Error(t, Histogram{Buckets: []HistogramBucket{
+		{UpperBound: math.NaN(), Count: 1},
+	}}.Validate())
+}
+
+func TestNewHistogramDatapoint(t *testing.T) {
+	now := time.Now()
+	h := Histogram{Buckets: []HistogramBucket{
+		{UpperBound: 1, Count: 4},
+		{UpperBound: math.Inf(1), Count: 5},
+	}}
+	dp := NewHistogramDatapoint(now, h)
+	assert.Equal(t, 5.0, dp.Value)
+	assert.True(t, dp.Equal(NewHistogramDatapoint(now, h)))
+}
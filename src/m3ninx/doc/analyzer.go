@@ -0,0 +1,107 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package doc
+
+import (
+	"bytes"
+	"unicode"
+	"unicode/utf8"
+)
+
+// TokenizedFieldNamePrefix is the prefix of the field names reserved for the
+// tokens of tokenized fields.
+var TokenizedFieldNamePrefix = []byte("_m3ninx_tokens.")
+
+// TokenizedFieldName returns the reserved name of the field the tokens of the
+// given field are indexed under. Tokens are indexed under a separate field so
+// that term queries against the field itself keep matching its values exactly.
+func TokenizedFieldName(name []byte) []byte {
+	result := make([]byte, 0, len(TokenizedFieldNamePrefix)+len(name))
+	result = append(result, TokenizedFieldNamePrefix...)
+	return append(result, name...)
+}
+
+// IsTokenizedFieldName returns a bool indicating whether the field name is
+// reserved for the tokens of a tokenized field.
+func IsTokenizedFieldName(name []byte) bool {
+	return bytes.HasPrefix(name, TokenizedFieldNamePrefix)
+}
+
+// Analyzer splits the values of a tokenized field into the tokens that are
+// indexed in addition to the values themselves.
+type Analyzer interface {
+	// Tokens appends the tokens of the value to dst and returns the result. The
+	// tokens must remain valid for as long as the value does, they may contain
+	// duplicates.
+	Tokens(dst [][]byte, value []byte) [][]byte
+}
+
+// FieldAnalyzers are the analyzers of tokenized fields keyed by field name.
+type FieldAnalyzers map[string]Analyzer
+
+// NewFieldAnalyzers returns field analyzers that tokenize each of the fields
+// with the given analyzer, it returns nil if there are no fields.
+func NewFieldAnalyzers(fields []string, analyzer Analyzer) FieldAnalyzers {
+	if len(fields) == 0 {
+		return nil
+	}
+
+	analyzers := make(FieldAnalyzers, len(fields))
+	for _, field := range fields {
+		analyzers[field] = analyzer
+	}
+	return analyzers
+}
+
+// Analyzer returns the analyzer of the field and whether the field is tokenized.
+func (a FieldAnalyzers) Analyzer(field []byte) (Analyzer, bool) {
+	analyzer, ok := a[string(field)]
+	return analyzer, ok
+}
+
+type wordAnalyzer struct{}
+
+// NewWordAnalyzer returns an analyzer that splits values into words, i.e. the
+// runs of letters and digits of the value. Words are not normalized and so are
+// matched case sensitively.
+func NewWordAnalyzer() Analyzer {
+	return wordAnalyzer{}
+}
+
+func (wordAnalyzer) Tokens(dst [][]byte, value []byte) [][]byte {
+	start := -1
+	for i := 0; i < len(value); {
+		r, size := utf8.DecodeRune(value[i:])
+		isWord := unicode.IsLetter(r) || unicode.IsDigit(r)
+		switch {
+		case isWord && start < 0:
+			start = i
+		case !isWord && start >= 0:
+			dst = append(dst, value[start:i])
+			start = -1
+		}
+		i += size
+	}
+	if start >= 0 {
+		dst = append(dst, value[start:])
+	}
+	return dst
+}
@@ -0,0 +1,75 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package doc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenizedFieldName(t *testing.T) {
+	name := TokenizedFieldName([]byte("error"))
+	require.Equal(t, "_m3ninx_tokens.error", string(name))
+	require.True(t, IsTokenizedFieldName(name))
+	require.False(t, IsTokenizedFieldName([]byte("error")))
+}
+
+func TestWordAnalyzer(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected []string
+	}{
+		{value: "", expected: nil},
+		{value: "  -- ", expected: nil},
+		{value: "timeout", expected: []string{"timeout"}},
+		{
+			value:    "Connection refused: dial tcp 10.0.0.1:9000",
+			expected: []string{"Connection", "refused", "dial", "tcp", "10", "0", "0", "1", "9000"},
+		},
+		{value: "größe_über", expected: []string{"größe", "über"}},
+	}
+
+	analyzer := NewWordAnalyzer()
+	for _, test := range tests {
+		t.Run(test.value, func(t *testing.T) {
+			var actual []string
+			for _, token := range analyzer.Tokens(nil, []byte(test.value)) {
+				actual = append(actual, string(token))
+			}
+			require.Equal(t, test.expected, actual)
+		})
+	}
+}
+
+func TestFieldAnalyzers(t *testing.T) {
+	require.Nil(t, NewFieldAnalyzers(nil, NewWordAnalyzer()))
+
+	analyzers := NewFieldAnalyzers([]string{"error"}, NewWordAnalyzer())
+	_, ok := analyzers.Analyzer([]byte("error"))
+	require.True(t, ok)
+	_, ok = analyzers.Analyzer([]byte("other"))
+	require.False(t, ok)
+
+	var empty FieldAnalyzers
+	_, ok = empty.Analyzer([]byte("error"))
+	require.False(t, ok)
+}
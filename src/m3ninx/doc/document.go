@@ -165,6 +165,10 @@ func (d Document) Validate() error {
 			return errReservedFieldName
 		}
 
+		if IsTokenizedFieldName(f.Name) {
+			return fmt.Errorf("document contains reserved field name: %s", f.Name)
+		}
+
 		if !utf8.Valid(f.Value) {
 			return fmt.Errorf("document contains invalid field value: %v", f.Value)
 		}
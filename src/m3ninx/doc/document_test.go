@@ -478,6 +478,18 @@ func TestDocumentValidation(t *testing.T) {
 			},
 			expectedErr: true,
 		},
+		{
+			name: "document contains field with reserved tokenized field name",
+			input: Document{
+				Fields: []Field{
+					Field{
+						Name:  TokenizedFieldName([]byte("apple")),
+						Value: []byte("red"),
+					},
+				},
+			},
+			expectedErr: true,
+		},
 		{
 			name: "valid document",
 			input: Document{
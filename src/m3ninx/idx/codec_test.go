@@ -43,6 +43,26 @@ func TestMarshal(t *testing.T) {
 			name:  "regexp query",
 			query: MustCreateRegexpQuery([]byte("fruit"), []byte(".*ple")),
 		},
+		{
+			name:  "match query",
+			query: NewMatchQuery([]byte("fruit"), []byte("apple")),
+		},
+		{
+			name:  "prefix query",
+			query: NewPrefixQuery([]byte("fruit"), []byte("app")),
+		},
+		{
+			name:  "wildcard query",
+			query: MustCreateWildcardQuery([]byte("fruit"), []byte("*ppl?")),
+		},
+		{
+			name:  "range query",
+			query: mustQuery(NewRangeQuery([]byte("fruit"), []byte("a"), []byte("c"), true, false)),
+		},
+		{
+			name:  "numeric range query",
+			query: mustQuery(NewNumericRangeQuery([]byte("weight"), nil, []byte("10"), false, true)),
+		},
 		{
 			name:  "negation query",
 			query: NewNegationQuery(NewTermQuery([]byte("fruit"), []byte("apple"))),
@@ -85,3 +105,10 @@ func TestMarshal(t *testing.T) {
 		})
 	}
 }
+
+func mustQuery(q Query, err error) Query {
+	if err != nil {
+		panic(err)
+	}
+	return q
+}
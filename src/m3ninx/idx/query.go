@@ -72,6 +72,14 @@ func NewTermQuery(field, term []byte) Query {
 	}
 }
 
+// NewMatchQuery returns a new query for finding documents which contain a token in
+// the value of a tokenized field.
+func NewMatchQuery(field, token []byte) Query {
+	return Query{
+		query: query.NewMatchQuery(field, token),
+	}
+}
+
 // NewRegexpQuery returns a new query for finding documents which match a regular expression.
 func NewRegexpQuery(field, regexp []byte) (Query, error) {
 	q, err := query.NewRegexpQuery(field, regexp)
@@ -94,6 +102,61 @@ func MustCreateRegexpQuery(field, regexp []byte) Query {
 	}
 }
 
+// NewPrefixQuery returns a new query for finding documents which have a term starting
+// with the given prefix.
+func NewPrefixQuery(field, prefix []byte) Query {
+	return Query{
+		query: query.NewPrefixQuery(field, prefix),
+	}
+}
+
+// NewWildcardQuery returns a new query for finding documents which match a wildcard
+// pattern, where '*' matches zero or more characters and '?' matches exactly one.
+func NewWildcardQuery(field, wildcard []byte) (Query, error) {
+	q, err := query.NewWildcardQuery(field, wildcard)
+	if err != nil {
+		return Query{}, err
+	}
+	return Query{
+		query: q,
+	}, nil
+}
+
+// MustCreateWildcardQuery is like NewWildcardQuery but panics if the query cannot be created.
+func MustCreateWildcardQuery(field, wildcard []byte) Query {
+	q, err := query.NewWildcardQuery(field, wildcard)
+	if err != nil {
+		panic(err)
+	}
+	return Query{
+		query: q,
+	}
+}
+
+// NewRangeQuery returns a new query for finding documents which have a term
+// lexicographically between min and max, an empty bound is unbounded.
+func NewRangeQuery(field, min, max []byte, minInclusive, maxInclusive bool) (Query, error) {
+	q, err := query.NewRangeQuery(field, min, max, minInclusive, maxInclusive)
+	if err != nil {
+		return Query{}, err
+	}
+	return Query{
+		query: q,
+	}, nil
+}
+
+// NewNumericRangeQuery returns a new query for finding documents which have a term
+// which is a number between min and max, an empty bound is unbounded.
+func NewNumericRangeQuery(field, min, max []byte, minInclusive, maxInclusive bool) (Query, error) {
+	q, err := query.NewNumericRangeQuery(field, min, max, minInclusive, maxInclusive)
+	if err != nil {
+		return Query{}, err
+	}
+	return Query{
+		query: q,
+	}, nil
+}
+
 // NewNegationQuery returns a new query for finding documents which don't match a given query.
 func NewNegationQuery(q Query) Query {
 	return Query{
@@ -130,6 +193,11 @@ func (q Query) SearchQuery() search.Query {
 	return q.query
 }
 
+// Explain returns a description of how the query is evaluated against a segment.
+func (q Query) Explain() (search.Explanation, error) {
+	return query.Explain(q.query)
+}
+
 // Equal reports whether q is equal to o.
 func (q Query) Equal(o Query) bool {
 	return q.query.Equal(o.query)
@@ -1,5 +1,5 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/m3db/m3/src/m3ninx/index (interfaces: Reader,DocRetriever)
+// Source: github.com/m3db/m3/src/m3ninx/index (interfaces: Reader,DocRetriever,StatsReader)
 
 // Copyright (c) 2019 Uber Technologies, Inc.
 //
@@ -175,6 +175,21 @@ func (mr *MockReaderMockRecorder) MatchTerm(arg0, arg1 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MatchTerm", reflect.TypeOf((*MockReader)(nil).MatchTerm), arg0, arg1)
 }
 
+// MatchTerms mocks base method
+func (m *MockReader) MatchTerms(arg0 []byte, arg1 TermsMatcher) (postings.List, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MatchTerms", arg0, arg1)
+	ret0, _ := ret[0].(postings.List)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MatchTerms indicates an expected call of MatchTerms
+func (mr *MockReaderMockRecorder) MatchTerms(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MatchTerms", reflect.TypeOf((*MockReader)(nil).MatchTerms), arg0, arg1)
+}
+
 // MockDocRetriever is a mock of DocRetriever interface
 type MockDocRetriever struct {
 	ctrl     *gomock.Controller
@@ -212,3 +227,71 @@ func (mr *MockDocRetrieverMockRecorder) Doc(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Doc", reflect.TypeOf((*MockDocRetriever)(nil).Doc), arg0)
 }
+
+// MockStatsReader is a mock of StatsReader interface
+type MockStatsReader struct {
+	ctrl     *gomock.Controller
+	recorder *MockStatsReaderMockRecorder
+}
+
+// MockStatsReaderMockRecorder is the mock recorder for MockStatsReader
+type MockStatsReaderMockRecorder struct {
+	mock *MockStatsReader
+}
+
+// NewMockStatsReader creates a new mock instance
+func NewMockStatsReader(ctrl *gomock.Controller) *MockStatsReader {
+	mock := &MockStatsReader{ctrl: ctrl}
+	mock.recorder = &MockStatsReaderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockStatsReader) EXPECT() *MockStatsReaderMockRecorder {
+	return m.recorder
+}
+
+// NumDocs mocks base method
+func (m *MockStatsReader) NumDocs() (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NumDocs")
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NumDocs indicates an expected call of NumDocs
+func (mr *MockStatsReaderMockRecorder) NumDocs() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NumDocs", reflect.TypeOf((*MockStatsReader)(nil).NumDocs))
+}
+
+// NumTerms mocks base method
+func (m *MockStatsReader) NumTerms(arg0 []byte) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NumTerms", arg0)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NumTerms indicates an expected call of NumTerms
+func (mr *MockStatsReaderMockRecorder) NumTerms(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NumTerms", reflect.TypeOf((*MockStatsReader)(nil).NumTerms), arg0)
+}
+
+// TermCardinality mocks base method
+func (m *MockStatsReader) TermCardinality(arg0, arg1 []byte) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TermCardinality", arg0, arg1)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TermCardinality indicates an expected call of TermCardinality
+func (mr *MockStatsReaderMockRecorder) TermCardinality(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TermCardinality", reflect.TypeOf((*MockStatsReader)(nil).TermCardinality), arg0, arg1)
+}
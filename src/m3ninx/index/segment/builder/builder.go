@@ -46,6 +46,10 @@ type builder struct {
 	idSet        *IDsMap
 	fields       *fieldsMap
 	uniqueFields [][]byte
+
+	analyzers       doc.FieldAnalyzers
+	tokenizedFields map[string][]byte
+	tokens          [][]byte
 }
 
 // NewBuilderFromDocuments returns a builder from documents, it is
@@ -65,7 +69,9 @@ func NewBuilderFromDocuments(opts Options) (segment.DocumentsBuilder, error) {
 		fields: newFieldsMap(fieldsMapOptions{
 			InitialSize: opts.InitialCapacity(),
 		}),
-		uniqueFields: make([][]byte, 0, opts.InitialCapacity()),
+		uniqueFields:    make([][]byte, 0, opts.InitialCapacity()),
+		analyzers:       opts.FieldAnalyzers(),
+		tokenizedFields: make(map[string][]byte),
 	}, nil
 }
 
@@ -92,6 +98,13 @@ func (b *builder) Reset(offset postings.ID) {
 		b.uniqueFields[i] = nil
 	}
 	b.uniqueFields = b.uniqueFields[:0]
+
+	// Restore the default analyzers.
+	b.analyzers = b.opts.FieldAnalyzers()
+}
+
+func (b *builder) SetFieldAnalyzers(value doc.FieldAnalyzers) {
+	b.analyzers = value
 }
 
 func (b *builder) Insert(d doc.Document) ([]byte, error) {
@@ -158,7 +171,7 @@ func (b *builder) InsertBatch(batch index.Batch) error {
 
 		// Index the terms.
 		for _, f := range d.Fields {
-			if err := b.index(postings.ID(postingsListID), f); err != nil {
+			if err := b.indexField(postings.ID(postingsListID), f); err != nil {
 				if !batch.AllowPartialUpdates {
 					return err
 				}
@@ -182,6 +195,38 @@ func (b *builder) InsertBatch(batch index.Batch) error {
 	return nil
 }
 
+func (b *builder) indexField(id postings.ID, f doc.Field) error {
+	if err := b.index(id, f); err != nil {
+		return err
+	}
+
+	analyzer, ok := b.analyzers.Analyzer(f.Name)
+	if !ok {
+		return nil
+	}
+
+	// Index the tokens of tokenized fields under their reserved field name.
+	name, ok := b.tokenizedFields[string(f.Name)]
+	if !ok {
+		name = doc.TokenizedFieldName(f.Name)
+		b.tokenizedFields[string(f.Name)] = name
+	}
+
+	var err error
+	b.tokens = analyzer.Tokens(b.tokens[:0], f.Value)
+	for _, token := range b.tokens {
+		if err = b.index(id, doc.Field{Name: name, Value: token}); err != nil {
+			break
+		}
+	}
+
+	// Release references to the tokens.
+	for i := range b.tokens {
+		b.tokens[i] = nil
+	}
+	return err
+}
+
 func (b *builder) index(id postings.ID, f doc.Field) error {
 	terms, ok := b.fields.Get(f.Name)
 	if !ok {
@@ -136,6 +136,54 @@ func TestBuilderTerms(t *testing.T) {
 	}
 }
 
+func TestBuilderTokenizedFields(t *testing.T) {
+	opts := testOptions.SetFieldAnalyzers(doc.NewFieldAnalyzers(
+		[]string{"fruit"}, doc.NewWordAnalyzer()))
+	builder, err := NewBuilderFromDocuments(opts)
+	require.NoError(t, err)
+
+	_, err = builder.Insert(doc.Document{
+		ID: []byte("foo"),
+		Fields: []doc.Field{
+			doc.Field{
+				Name:  []byte("fruit"),
+				Value: []byte("red-apple"),
+			},
+			doc.Field{
+				Name:  []byte("color"),
+				Value: []byte("dark-red"),
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	// The original fields keep their exact terms.
+	termsIter, err := builder.Terms([]byte("fruit"))
+	require.NoError(t, err)
+	require.Equal(t, termPostings{"red-apple": []int{0}},
+		toTermPostings(t, termsIter))
+
+	// Only the configured fields are tokenized.
+	termsIter, err = builder.Terms(doc.TokenizedFieldName([]byte("fruit")))
+	require.NoError(t, err)
+	require.Equal(t, termPostings{"red": []int{0}, "apple": []int{0}},
+		toTermPostings(t, termsIter))
+
+	_, err = builder.Terms(doc.TokenizedFieldName([]byte("color")))
+	require.Error(t, err)
+
+	// Analyzers can be overridden until the next reset.
+	builder.Reset(0)
+	builder.SetFieldAnalyzers(nil)
+	_, err = builder.Insert(testDocuments[0])
+	require.NoError(t, err)
+
+	fieldsIter, err := builder.Fields()
+	require.NoError(t, err)
+	require.Equal(t, [][]byte{[]byte("color"), []byte("fruit")},
+		toSlice(t, fieldsIter))
+}
+
 func toSlice(t *testing.T, iter segment.OrderedBytesIterator) [][]byte {
 	elems := [][]byte{}
 	for iter.Next() {
@@ -38,6 +38,8 @@ type builderFromSegments struct {
 	termsIter      *termsIterFromSegments
 	offset         postings.ID
 	segmentsOffset postings.ID
+	filter         segment.DocumentsFilter
+	filtered       int
 }
 
 type segmentMetadata struct {
@@ -45,8 +47,8 @@ type segmentMetadata struct {
 	offset  postings.ID
 	// duplicatesAsc is a lookup of document IDs are duplicates
 	// in this segment, that is documents that are already
-	// contained by other segments and hence should not be
-	// returned when looking up documents.
+	// contained by other segments or that were filtered out
+	// and hence should not be returned when looking up documents.
 	duplicatesAsc []postings.ID
 }
 
@@ -82,6 +84,12 @@ func (b *builderFromSegments) Reset(offset postings.ID) {
 	b.segments = b.segments[:0]
 
 	b.termsIter.clear()
+	b.filter = nil
+	b.filtered = 0
+}
+
+func (b *builderFromSegments) SetFilter(keep segment.DocumentsFilter) {
+	b.filter = keep
 }
 
 func (b *builderFromSegments) AddSegments(segments []segment.Segment) error {
@@ -115,6 +123,12 @@ func (b *builderFromSegments) AddSegments(segments []segment.Segment) error {
 				duplicates = append(duplicates, iter.PostingsID())
 				continue
 			}
+			if b.filter != nil && !b.filter.Contains(d) {
+				// Skip filtered out documents the same as duplicates.
+				duplicates = append(duplicates, iter.PostingsID())
+				b.filtered++
+				continue
+			}
 			b.idSet.SetUnsafe(d.ID, struct{}{}, IDsMapSetUnsafeOptions{
 				NoCopyKey:     true,
 				NoFinalizeKey: true,
@@ -175,7 +189,16 @@ func (b *builderFromSegments) TermsIterable() segment.TermsIterable {
 }
 
 func (b *builderFromSegments) Fields() (segment.FieldsIterator, error) {
-	return newFieldIterFromSegments(b.segments)
+	iter, err := newFieldIterFromSegments(b.segments)
+	if err != nil {
+		return nil, err
+	}
+	if b.filtered == 0 {
+		return iter, nil
+	}
+	// Fields may only have been present on documents that were filtered
+	// out, these need to be skipped so they are not written without terms.
+	return newFilteredFieldsIter(iter, b), nil
 }
 
 func (b *builderFromSegments) Terms(field []byte) (segment.TermsIterator, error) {
@@ -51,3 +51,49 @@ func newFieldIterFromSegments(
 
 	return multiIter, nil
 }
+
+type filteredFieldsIter struct {
+	segment.FieldsIterator
+
+	terms segment.TermsIterable
+	err   error
+}
+
+func newFilteredFieldsIter(
+	iter segment.FieldsIterator,
+	terms segment.TermsIterable,
+) segment.FieldsIterator {
+	return &filteredFieldsIter{
+		FieldsIterator: iter,
+		terms:          terms,
+	}
+}
+
+func (i *filteredFieldsIter) Next() bool {
+	if i.err != nil {
+		return false
+	}
+	for i.FieldsIterator.Next() {
+		terms, err := i.terms.Terms(i.FieldsIterator.Current())
+		if err != nil {
+			i.err = err
+			return false
+		}
+		hasTerms := terms.Next()
+		if err := terms.Err(); err != nil {
+			i.err = err
+			return false
+		}
+		if hasTerms {
+			return true
+		}
+	}
+	return false
+}
+
+func (i *filteredFieldsIter) Err() error {
+	if i.err != nil {
+		return i.err
+	}
+	return i.FieldsIterator.Err()
+}
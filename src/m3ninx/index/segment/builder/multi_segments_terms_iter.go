@@ -131,19 +131,30 @@ func (i *termsIterFromSegments) Next() bool {
 		return false
 	}
 
-	if !i.keyIter.Next() {
-		return false
+	for i.keyIter.Next() {
+		if !i.currentPostingsList() {
+			return false
+		}
+		// Terms only present on duplicate or filtered out
+		// documents have no postings and are skipped.
+		if !i.currPostingsList.IsEmpty() {
+			return true
+		}
 	}
+	return false
+}
 
+func (i *termsIterFromSegments) currentPostingsList() bool {
 	// Create the overlayed postings list for this term
 	i.currPostingsList.Reset()
 	for _, iter := range i.keyIter.CurrentIters() {
 		termsKeyIter := iter.(*termsKeyIter)
 		_, list := termsKeyIter.iter.Current()
 
-		if termsKeyIter.segment.offset == 0 {
-			// No offset, which means is first segment we are combining from
-			// so can just direct union
+		if termsKeyIter.segment.offset == 0 &&
+			len(termsKeyIter.segment.duplicatesAsc) == 0 {
+			// No offset or skipped documents, which means is first segment
+			// we are combining from so can just direct union
 			i.currPostingsList.Union(list)
 			continue
 		}
@@ -91,6 +91,68 @@ func TestTermsIterFromSegmentsDeduplicates(t *testing.T) {
 	})
 }
 
+func TestTermsIterFromSegmentsFilters(t *testing.T) {
+	segments := []segment.Segment{
+		newTestSegmentWithDocs(t, []doc.Document{
+			{
+				ID: []byte("foo"),
+				Fields: []doc.Field{
+					{Name: []byte("fruit"), Value: []byte("apple")},
+					{Name: []byte("vegetable"), Value: []byte("carrot")},
+				},
+			},
+		}),
+		newTestSegmentWithDocs(t, []doc.Document{
+			{
+				ID: []byte("bar"),
+				Fields: []doc.Field{
+					{Name: []byte("fruit"), Value: []byte("apple")},
+					{Name: []byte("color"), Value: []byte("blue")},
+				},
+			},
+			{
+				ID: []byte("baz"),
+				Fields: []doc.Field{
+					{Name: []byte("fruit"), Value: []byte("watermelon")},
+					{Name: []byte("vegetable"), Value: []byte("kale")},
+				},
+			},
+			{
+				ID: []byte("bux"),
+				Fields: []doc.Field{
+					{Name: []byte("fruit"), Value: []byte("watermelon")},
+					{Name: []byte("color"), Value: []byte("red")},
+				},
+			},
+		}),
+	}
+
+	builder := NewBuilderFromSegments(testOptions)
+	builder.Reset(0)
+	builder.SetFilter(segment.DocumentsFilterFn(func(d doc.Document) bool {
+		return string(d.ID) != "foo" && string(d.ID) != "baz"
+	}))
+	require.NoError(t, builder.AddSegments(segments))
+	require.Equal(t, 2, len(builder.Docs()))
+
+	iter, err := builder.Terms([]byte("fruit"))
+	require.NoError(t, err)
+	assertTermsPostings(t, builder.Docs(), iter, termPostings{
+		"apple":      []int{0},
+		"watermelon": []int{1},
+	})
+
+	fieldsIter, err := builder.Fields()
+	require.NoError(t, err)
+	var fields []string
+	for fieldsIter.Next() {
+		fields = append(fields, string(fieldsIter.Current()))
+	}
+	require.NoError(t, fieldsIter.Err())
+	require.NoError(t, fieldsIter.Close())
+	require.Equal(t, []string{"color", "fruit"}, fields)
+}
+
 func assertTermsPostings(
 	t *testing.T,
 	docs []doc.Document,
@@ -21,6 +21,7 @@
 package builder
 
 import (
+	"github.com/m3db/m3/src/m3ninx/doc"
 	"github.com/m3db/m3/src/m3ninx/postings"
 	"github.com/m3db/m3/src/m3ninx/postings/roaring"
 	"github.com/m3db/m3/src/m3ninx/util"
@@ -49,12 +50,19 @@ type Options interface {
 
 	// PostingsListPool returns the postings list pool.
 	PostingsListPool() postings.Pool
+
+	// SetFieldAnalyzers sets the analyzers of the tokenized fields.
+	SetFieldAnalyzers(value doc.FieldAnalyzers) Options
+
+	// FieldAnalyzers returns the analyzers of the tokenized fields.
+	FieldAnalyzers() doc.FieldAnalyzers
 }
 
 type opts struct {
 	newUUIDFn       util.NewUUIDFn
 	initialCapacity int
 	postingsPool    postings.Pool
+	fieldAnalyzers  doc.FieldAnalyzers
 }
 
 // NewOptions returns new options.
@@ -95,3 +103,13 @@ func (o *opts) SetPostingsListPool(v postings.Pool) Options {
 func (o *opts) PostingsListPool() postings.Pool {
 	return o.postingsPool
 }
+
+func (o *opts) SetFieldAnalyzers(v doc.FieldAnalyzers) Options {
+	opts := *o
+	opts.fieldAnalyzers = v
+	return &opts
+}
+
+func (o *opts) FieldAnalyzers() doc.FieldAnalyzers {
+	return o.fieldAnalyzers
+}
@@ -21,19 +21,33 @@
 package docs
 
 import (
+	"errors"
 	"fmt"
 	"io"
 
 	"github.com/m3db/m3/src/m3ninx/doc"
 	"github.com/m3db/m3/src/m3ninx/index/segment/fst/encoding"
+	"github.com/m3db/m3/src/x/serialize"
 )
 
 const initialDataEncoderLen = 1024
 
+var (
+	errDictionaryWritten          = errors.New("documents dictionary has already been written")
+	errFieldMissingFromDictionary = errors.New("field is missing from the documents dictionary")
+)
+
 // DataWriter writes the data file for documents.
 type DataWriter struct {
 	writer io.Writer
 	enc    *encoding.Encoder
+
+	// dict is a dictionary of the field names and values of the documents,
+	// once it has been written fields are written as references into it.
+	// NB: it holds every distinct field name and value of the segment in
+	// memory until the writer is reset.
+	dict        *serialize.TagDictionaryBuilder
+	dictWritten bool
 }
 
 // NewDataWriter returns a new DataWriter.
@@ -48,8 +62,19 @@ func (w *DataWriter) Write(d doc.Document) (int, error) {
 	n := w.enc.PutBytes(d.ID)
 	n += w.enc.PutUvarint(uint64(len(d.Fields)))
 	for _, f := range d.Fields {
-		n += w.enc.PutBytes(f.Name)
-		n += w.enc.PutBytes(f.Value)
+		if !w.dictWritten {
+			n += w.enc.PutBytes(f.Name)
+			n += w.enc.PutBytes(f.Value)
+			continue
+		}
+
+		nameRef, valueRef, err := w.fieldRefs(f)
+		if err != nil {
+			w.enc.Reset()
+			return 0, err
+		}
+		n += w.enc.PutUvarint(nameRef)
+		n += w.enc.PutUvarint(valueRef)
 	}
 
 	if err := w.write(); err != nil {
@@ -59,6 +84,66 @@ func (w *DataWriter) Write(d doc.Document) (int, error) {
 	return n, nil
 }
 
+// AddToDictionary adds the field names and values of the document to the
+// dictionary written by WriteDictionary.
+func (w *DataWriter) AddToDictionary(d doc.Document) error {
+	if w.dictWritten {
+		return errDictionaryWritten
+	}
+	if w.dict == nil {
+		w.dict = serialize.NewTagDictionaryBuilder(serialize.NewTagEncoderOptions())
+	}
+	for _, f := range d.Fields {
+		if _, err := w.dict.Ref(f.Name); err != nil {
+			return err
+		}
+		if _, err := w.dict.Ref(f.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteDictionary writes the dictionary of the documents added with
+// AddToDictionary, documents written afterwards must only have fields from
+// the dictionary as they are written as references into it.
+func (w *DataWriter) WriteDictionary() (int, error) {
+	if w.dictWritten {
+		return 0, errDictionaryWritten
+	}
+	if w.dict == nil {
+		w.dict = serialize.NewTagDictionaryBuilder(serialize.NewTagEncoderOptions())
+	}
+
+	b := w.dict.Dictionary()
+	n, err := w.writer.Write(b)
+	if err != nil {
+		return 0, err
+	}
+	if n < len(b) {
+		return 0, io.ErrShortWrite
+	}
+
+	w.dictWritten = true
+	return n, nil
+}
+
+func (w *DataWriter) fieldRefs(f doc.Field) (uint64, uint64, error) {
+	numLiterals := w.dict.Len()
+	nameRef, err := w.dict.Ref(f.Name)
+	if err != nil {
+		return 0, 0, err
+	}
+	valueRef, err := w.dict.Ref(f.Value)
+	if err != nil {
+		return 0, 0, err
+	}
+	if w.dict.Len() != numLiterals {
+		return 0, 0, errFieldMissingFromDictionary
+	}
+	return nameRef, valueRef, nil
+}
+
 func (w *DataWriter) write() error {
 	b := w.enc.Bytes()
 	n, err := w.writer.Write(b)
@@ -76,11 +161,14 @@ func (w *DataWriter) write() error {
 func (w *DataWriter) Reset(wr io.Writer) {
 	w.writer = wr
 	w.enc.Reset()
+	w.dict = nil
+	w.dictWritten = false
 }
 
 // DataReader is a reader for the data file for documents.
 type DataReader struct {
 	data []byte
+	dict *serialize.TagDictionary
 }
 
 // NewDataReader returns a new DataReader.
@@ -90,6 +178,24 @@ func NewDataReader(data []byte) *DataReader {
 	}
 }
 
+// NewDictionaryDataReader returns a new DataReader for a data file which
+// begins with a dictionary of the field names and values of its documents.
+// NB: the whole dictionary is decoded when the reader is created, the reader
+// holds a slice referencing each of the distinct field names and values of
+// the segment for its lifetime, which costs memory proportional to their
+// number.
+func NewDictionaryDataReader(data []byte) (*DataReader, error) {
+	dict, _, err := serialize.DecodeTagDictionary(data,
+		serialize.NewTagSerializationLimits())
+	if err != nil {
+		return nil, fmt.Errorf("unable to read documents dictionary: %v", err)
+	}
+	return &DataReader{
+		data: data,
+		dict: dict,
+	}, nil
+}
+
 func (r *DataReader) Read(offset uint64) (doc.Document, error) {
 	if offset >= uint64(len(r.data)) {
 		return doc.Document{}, fmt.Errorf("invalid offset: %v is past the end of the data file", offset)
@@ -112,11 +218,11 @@ func (r *DataReader) Read(offset uint64) (doc.Document, error) {
 	}
 
 	for i := 0; i < n; i++ {
-		name, err := dec.Bytes()
+		name, err := r.readLiteral(dec)
 		if err != nil {
 			return doc.Document{}, err
 		}
-		val, err := dec.Bytes()
+		val, err := r.readLiteral(dec)
 		if err != nil {
 			return doc.Document{}, err
 		}
@@ -128,3 +234,18 @@ func (r *DataReader) Read(offset uint64) (doc.Document, error) {
 
 	return d, nil
 }
+
+func (r *DataReader) readLiteral(dec *encoding.Decoder) ([]byte, error) {
+	if r.dict == nil {
+		return dec.Bytes()
+	}
+	ref, err := dec.Uvarint()
+	if err != nil {
+		return nil, err
+	}
+	literal, ok := r.dict.Literal(ref)
+	if !ok {
+		return nil, fmt.Errorf("invalid field reference: %v is past the end of the dictionary", ref)
+	}
+	return literal, nil
+}
@@ -31,10 +31,95 @@ import (
 )
 
 func TestStoredFieldsData(t *testing.T) {
-	tests := []struct {
-		name string
-		docs []doc.Document
-	}{
+	tests := newStoredFieldsDataTests()
+
+	w := NewDataWriter(nil)
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				buf     = new(bytes.Buffer)
+				offsets = make([]int, 0)
+				idx     int
+			)
+			w.Reset(buf)
+
+			for i := range test.docs {
+				n, err := w.Write(test.docs[i])
+				require.NoError(t, err)
+				offsets = append(offsets, idx)
+				idx += n
+			}
+
+			r := NewDataReader(buf.Bytes())
+			for i := range test.docs {
+				actual, err := r.Read(uint64(offsets[i]))
+				require.NoError(t, err)
+				require.True(t, actual.Equal(test.docs[i]))
+			}
+		})
+	}
+}
+
+func TestStoredFieldsDataDictionary(t *testing.T) {
+	tests := newStoredFieldsDataTests()
+
+	w := NewDataWriter(nil)
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				buf     = new(bytes.Buffer)
+				offsets = make([]int, 0)
+			)
+			w.Reset(buf)
+
+			for i := range test.docs {
+				require.NoError(t, w.AddToDictionary(test.docs[i]))
+			}
+			idx, err := w.WriteDictionary()
+			require.NoError(t, err)
+
+			for i := range test.docs {
+				n, err := w.Write(test.docs[i])
+				require.NoError(t, err)
+				offsets = append(offsets, idx)
+				idx += n
+			}
+
+			r, err := NewDictionaryDataReader(buf.Bytes())
+			require.NoError(t, err)
+			for i := range test.docs {
+				actual, err := r.Read(uint64(offsets[i]))
+				require.NoError(t, err)
+				require.True(t, actual.Equal(test.docs[i]))
+			}
+		})
+	}
+}
+
+func TestStoredFieldsDataDictionaryMissingField(t *testing.T) {
+	w := NewDataWriter(new(bytes.Buffer))
+	_, err := w.WriteDictionary()
+	require.NoError(t, err)
+
+	_, err = w.Write(doc.Document{
+		ID: []byte("831992"),
+		Fields: []doc.Field{
+			doc.Field{
+				Name:  []byte("fruit"),
+				Value: []byte("apple"),
+			},
+		},
+	})
+	require.Equal(t, errFieldMissingFromDictionary, err)
+}
+
+type storedFieldsDataTest struct {
+	name string
+	docs []doc.Document
+}
+
+func newStoredFieldsDataTests() []storedFieldsDataTest {
+	return []storedFieldsDataTest{
 		{
 			name: "empty document",
 			docs: []doc.Document{
@@ -79,30 +164,4 @@ func TestStoredFieldsData(t *testing.T) {
 			docs: util.MustReadDocs("../../../../../util/testdata/node_exporter.json", 2000),
 		},
 	}
-
-	w := NewDataWriter(nil)
-	for _, test := range tests {
-		t.Run(test.name, func(t *testing.T) {
-			var (
-				buf     = new(bytes.Buffer)
-				offsets = make([]int, 0)
-				idx     int
-			)
-			w.Reset(buf)
-
-			for i := range test.docs {
-				n, err := w.Write(test.docs[i])
-				require.NoError(t, err)
-				offsets = append(offsets, idx)
-				idx += n
-			}
-
-			r := NewDataReader(buf.Bytes())
-			for i := range test.docs {
-				actual, err := r.Read(uint64(offsets[i]))
-				require.NoError(t, err)
-				require.True(t, actual.Equal(test.docs[i]))
-			}
-		})
-	}
 }
@@ -0,0 +1,82 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fst
+
+import (
+	"github.com/m3db/m3/src/m3ninx/index"
+	"github.com/m3db/m3/src/m3ninx/postings"
+)
+
+// fstIDsIter iterates the document IDs of a segment in increasing order by
+// walking the terms of the ID field, the postings list of each ID holds the
+// postings ID of its single document. An iterator without a postings iterator
+// iterates a segment without documents.
+type fstIDsIter struct {
+	postingsIter *fstTermsPostingsIter
+	currID       []byte
+	currPID      postings.ID
+	err          error
+}
+
+var _ index.IDIterator = &fstIDsIter{}
+
+func newFSTIDsIter(
+	retriever postingsListRetriever,
+	termsIter *fstTermsIter,
+) *fstIDsIter {
+	postingsIter := newFSTTermsPostingsIter()
+	postingsIter.reset(retriever, termsIter)
+	return &fstIDsIter{postingsIter: postingsIter}
+}
+
+func (i *fstIDsIter) Next() bool {
+	if i.postingsIter == nil || i.err != nil || !i.postingsIter.Next() {
+		return false
+	}
+
+	var pl postings.List
+	i.currID, pl = i.postingsIter.Current()
+	i.currPID, i.err = pl.Max()
+	return i.err == nil
+}
+
+func (i *fstIDsIter) Current() []byte {
+	return i.currID
+}
+
+func (i *fstIDsIter) PostingsID() postings.ID {
+	return i.currPID
+}
+
+func (i *fstIDsIter) Err() error {
+	if i.err != nil || i.postingsIter == nil {
+		return i.err
+	}
+	return i.postingsIter.Err()
+}
+
+func (i *fstIDsIter) Close() error {
+	i.currID = nil
+	if i.postingsIter == nil {
+		return nil
+	}
+	return i.postingsIter.Close()
+}
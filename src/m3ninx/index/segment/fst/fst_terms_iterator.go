@@ -21,6 +21,7 @@
 package fst
 
 import (
+	"github.com/m3db/m3/src/m3ninx/index"
 	sgmt "github.com/m3db/m3/src/m3ninx/index/segment"
 	xerrors "github.com/m3db/m3/src/x/errors"
 	"github.com/m3db/vellum"
@@ -29,6 +30,9 @@ import (
 type fstTermsIterOpts struct {
 	fst         *vellum.FST
 	finalizeFST bool
+	// matcher restricts the iterated terms when set, the FST is only walked
+	// along the automaton and range of the matcher.
+	matcher index.TermsMatcher
 }
 
 func (o fstTermsIterOpts) Close() error {
@@ -69,7 +73,15 @@ func (f *fstTermsIter) reset(opts fstTermsIterOpts) {
 	f.clear()
 
 	f.opts = opts
-	if err := f.iter.Reset(opts.fst, nil, nil, nil); err != nil {
+	var (
+		start, end []byte
+		aut        vellum.Automaton
+	)
+	if opts.matcher != nil {
+		start, end = opts.matcher.Range()
+		aut = opts.matcher.Automaton()
+	}
+	if err := f.iter.Reset(opts.fst, start, end, aut); err != nil {
 		f.handleIterErr(err)
 	}
 }
@@ -87,17 +99,23 @@ func (f *fstTermsIter) Next() bool {
 		return false
 	}
 
-	if f.firstNext {
-		f.firstNext = false
-	} else {
-		if err := f.iter.Next(); err != nil {
-			f.handleIterErr(err)
-			return false
+	for {
+		if f.firstNext {
+			f.firstNext = false
+		} else {
+			if err := f.iter.Next(); err != nil {
+				f.handleIterErr(err)
+				return false
+			}
 		}
-	}
 
-	f.current, f.currentValue = f.iter.Current()
-	return true
+		f.current, f.currentValue = f.iter.Current()
+		// NB: the automaton and range may accept a superset of the matching
+		// terms so each term is checked against the matcher.
+		if f.opts.matcher == nil || f.opts.matcher.MatchTerm(f.current) {
+			return true
+		}
+	}
 }
 
 func (f *fstTermsIter) CurrentOffset() uint64 {
@@ -48,6 +48,7 @@ import (
 var (
 	errReaderClosed            = errors.New("segment is closed")
 	errReaderNilRegexp         = errors.New("nil regexp provided")
+	errReaderNilTermsMatcher   = errors.New("nil terms matcher provided")
 	errUnsupportedMajorVersion = errors.New("unsupported major version")
 	errDocumentsDataUnset      = errors.New("documents data bytes are not set")
 	errDocumentsIdxUnset       = errors.New("documents index bytes are not set")
@@ -140,7 +141,14 @@ func NewSegment(data SegmentData, opts Options) (Segment, error) {
 		startInclusive = docsSliceReader.Base()
 		endExclusive = startInclusive + postings.ID(docsSliceReader.Len())
 	} else {
-		docsDataReader = docs.NewDataReader(data.DocsData.Bytes)
+		if data.Version.supportsDocumentsDictionary() {
+			docsDataReader, err = docs.NewDictionaryDataReader(data.DocsData.Bytes)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			docsDataReader = docs.NewDataReader(data.DocsData.Bytes)
+		}
 		docsIndexReader, err = docs.NewIndexReader(data.DocsIdxData.Bytes)
 		if err != nil {
 			return nil, fmt.Errorf("unable to load documents index: %v", err)
@@ -337,6 +345,8 @@ type termsIterable struct {
 	postingsIter *fstTermsPostingsIter
 }
 
+var _ sgmt.MatchingTermsIterable = (*termsIterable)(nil)
+
 func (i *termsIterable) Terms(field []byte) (sgmt.TermsIterator, error) {
 	i.r.RLock()
 	defer i.r.RUnlock()
@@ -361,6 +371,38 @@ func (i *termsIterable) Terms(field []byte) (sgmt.TermsIterator, error) {
 	return i.postingsIter, nil
 }
 
+func (i *termsIterable) MatchingTerms(
+	field []byte,
+	m index.TermsMatcher,
+) (sgmt.TermsIterator, error) {
+	if m == nil {
+		return nil, errReaderNilTermsMatcher
+	}
+
+	i.r.RLock()
+	defer i.r.RUnlock()
+	if i.r.closed {
+		return nil, errReaderClosed
+	}
+
+	termsFST, exists, err := i.r.retrieveTermsFSTWithRLock(field)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exists {
+		return sgmt.EmptyTermsIterator, nil
+	}
+
+	i.fieldsIter.reset(fstTermsIterOpts{
+		fst:         termsFST,
+		finalizeFST: true,
+		matcher:     m,
+	})
+	i.postingsIter.reset(i.r, i.fieldsIter)
+	return i.postingsIter, nil
+}
+
 func (r *fsSegment) UnmarshalPostingsListBitmap(b *pilosaroaring.Bitmap, offset uint64) error {
 	r.RLock()
 	defer r.RUnlock()
@@ -526,6 +568,82 @@ func (r *fsSegment) matchRegexpWithRLock(field []byte, compiled index.CompiledRe
 	return pl, nil
 }
 
+func (r *fsSegment) MatchTerms(field []byte, m index.TermsMatcher) (postings.List, error) {
+	r.RLock()
+	pl, err := r.matchTermsWithRLock(field, m)
+	r.RUnlock()
+	return pl, err
+}
+
+func (r *fsSegment) matchTermsWithRLock(field []byte, m index.TermsMatcher) (postings.List, error) {
+	if r.closed {
+		return nil, errReaderClosed
+	}
+
+	if m == nil {
+		return nil, errReaderNilTermsMatcher
+	}
+
+	termsFST, exists, err := r.retrieveTermsFSTWithRLock(field)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exists {
+		// i.e. we don't know anything about the field, so can early return an empty postings list
+		return r.opts.PostingsListPool().Get(), nil
+	}
+
+	var (
+		start, end    = m.Range()
+		fstCloser     = x.NewSafeCloser(termsFST)
+		iter, iterErr = termsFST.Search(m.Automaton(), start, end)
+		iterCloser    = x.NewSafeCloser(iter)
+		pls           []postings.List
+	)
+	defer func() {
+		iterCloser.Close()
+		fstCloser.Close()
+	}()
+
+	for {
+		if iterErr == vellum.ErrIteratorDone {
+			break
+		}
+
+		if iterErr != nil {
+			return nil, iterErr
+		}
+
+		// NB: the automaton and range may accept a superset of the matching
+		// terms so each term is checked against the matcher.
+		term, postingsOffset := iter.Current()
+		if m.MatchTerm(term) {
+			nextPl, err := r.retrievePostingsListWithRLock(postingsOffset)
+			if err != nil {
+				return nil, err
+			}
+			pls = append(pls, nextPl)
+		}
+		iterErr = iter.Next()
+	}
+
+	pl, err := roaring.Union(pls)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := iterCloser.Close(); err != nil {
+		return nil, err
+	}
+
+	if err := fstCloser.Close(); err != nil {
+		return nil, err
+	}
+
+	return pl, nil
+}
+
 func (r *fsSegment) MatchAll() (postings.MutableList, error) {
 	r.RLock()
 	defer r.RUnlock()
@@ -542,6 +660,51 @@ func (r *fsSegment) MatchAll() (postings.MutableList, error) {
 	return pl, nil
 }
 
+func (r *fsSegment) NumDocs() (int, error) {
+	r.RLock()
+	defer r.RUnlock()
+	if r.closed {
+		return 0, errReaderClosed
+	}
+
+	return int(r.endExclusive - r.startInclusive), nil
+}
+
+func (r *fsSegment) NumTerms(field []byte) (int, error) {
+	r.RLock()
+	defer r.RUnlock()
+	if r.closed {
+		return 0, errReaderClosed
+	}
+
+	termsFST, exists, err := r.retrieveTermsFSTWithRLock(field)
+	if err != nil {
+		return 0, err
+	}
+
+	if !exists {
+		return 0, nil
+	}
+
+	numTerms := termsFST.Len()
+	if err := termsFST.Close(); err != nil {
+		return 0, err
+	}
+
+	return numTerms, nil
+}
+
+func (r *fsSegment) TermCardinality(field []byte, term []byte) (int, error) {
+	// NB: this is as cheap as a term lookup since the cardinality of the
+	// postings list is computed without iterating it.
+	pl, err := r.MatchTerm(field, term)
+	if err != nil {
+		return 0, err
+	}
+
+	return pl.Len(), nil
+}
+
 func (r *fsSegment) Doc(id postings.ID) (doc.Document, error) {
 	r.RLock()
 	defer r.RUnlock()
@@ -582,6 +745,43 @@ func (r *fsSegment) AllDocs() (index.IDDocIterator, error) {
 	return index.NewIDDocIterator(r, pi), nil
 }
 
+// IDsAfter returns an iterator over the document IDs of the segment strictly
+// after the given ID in increasing order, it seeks into the terms of the ID
+// field so earlier IDs are never read.
+func (r *fsSegment) IDsAfter(after []byte) (index.IDIterator, error) {
+	r.RLock()
+	defer r.RUnlock()
+	if r.closed {
+		return nil, errReaderClosed
+	}
+
+	termsFST, exists, err := r.retrieveTermsFSTWithRLock(doc.IDReservedFieldName)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exists {
+		return &fstIDsIter{}, nil
+	}
+
+	var matcher index.TermsMatcher
+	if len(after) > 0 {
+		matcher, err = index.NewRangeTermsMatcher(after, nil, false, false, false)
+		if err != nil {
+			termsFST.Close()
+			return nil, err
+		}
+	}
+
+	termsIter := newFSTTermsIter()
+	termsIter.reset(fstTermsIterOpts{
+		fst:         termsFST,
+		finalizeFST: true,
+		matcher:     matcher,
+	})
+	return newFSTIDsIter(r, termsIter), nil
+}
+
 func (r *fsSegment) retrievePostingsListWithRLock(postingsOffset uint64) (postings.List, error) {
 	postingsBytes, err := r.retrieveBytesWithRLock(r.data.PostingsData.Bytes, postingsOffset)
 	if err != nil {
@@ -740,7 +940,11 @@ func (r *fsSegment) retrieveBytesWithRLock(base []byte, offset uint64) ([]byte,
 	return base[payloadStart:payloadEnd], nil
 }
 
-var _ index.Reader = &fsSegmentReader{}
+var (
+	_ index.Reader           = &fsSegmentReader{}
+	_ index.StatsReader      = &fsSegmentReader{}
+	_ index.OrderedIDsReader = &fsSegmentReader{}
+)
 
 type fsSegmentReader struct {
 	sync.RWMutex
@@ -791,6 +995,17 @@ func (sr *fsSegmentReader) MatchRegexp(field []byte, compiled index.CompiledRege
 	return pl, err
 }
 
+func (sr *fsSegmentReader) MatchTerms(field []byte, m index.TermsMatcher) (postings.List, error) {
+	sr.RLock()
+	if sr.closed {
+		sr.RUnlock()
+		return nil, errReaderClosed
+	}
+	pl, err := sr.fsSegment.MatchTerms(field, m)
+	sr.RUnlock()
+	return pl, err
+}
+
 func (sr *fsSegmentReader) MatchAll() (postings.MutableList, error) {
 	sr.RLock()
 	if sr.closed {
@@ -802,6 +1017,39 @@ func (sr *fsSegmentReader) MatchAll() (postings.MutableList, error) {
 	return pl, err
 }
 
+func (sr *fsSegmentReader) NumDocs() (int, error) {
+	sr.RLock()
+	if sr.closed {
+		sr.RUnlock()
+		return 0, errReaderClosed
+	}
+	n, err := sr.fsSegment.NumDocs()
+	sr.RUnlock()
+	return n, err
+}
+
+func (sr *fsSegmentReader) NumTerms(field []byte) (int, error) {
+	sr.RLock()
+	if sr.closed {
+		sr.RUnlock()
+		return 0, errReaderClosed
+	}
+	n, err := sr.fsSegment.NumTerms(field)
+	sr.RUnlock()
+	return n, err
+}
+
+func (sr *fsSegmentReader) TermCardinality(field []byte, term []byte) (int, error) {
+	sr.RLock()
+	if sr.closed {
+		sr.RUnlock()
+		return 0, errReaderClosed
+	}
+	n, err := sr.fsSegment.TermCardinality(field, term)
+	sr.RUnlock()
+	return n, err
+}
+
 func (sr *fsSegmentReader) Doc(id postings.ID) (doc.Document, error) {
 	sr.RLock()
 	if sr.closed {
@@ -835,6 +1083,17 @@ func (sr *fsSegmentReader) AllDocs() (index.IDDocIterator, error) {
 	return iter, err
 }
 
+func (sr *fsSegmentReader) IDsAfter(after []byte) (index.IDIterator, error) {
+	sr.RLock()
+	if sr.closed {
+		sr.RUnlock()
+		return nil, errReaderClosed
+	}
+	iter, err := sr.fsSegment.IDsAfter(after)
+	sr.RUnlock()
+	return iter, err
+}
+
 func (sr *fsSegmentReader) Close() error {
 	sr.Lock()
 	if sr.closed {
@@ -42,8 +42,16 @@ var (
 	// CurrentVersion describes the default current Version.
 	CurrentVersion Version = Version{Major: 1, Minor: 1}
 
+	// DocumentsDictionaryVersion describes the Version written when the
+	// documents dictionary is enabled, it is opt-in so that nodes can still
+	// be downgraded to a release which does not read it.
+	DocumentsDictionaryVersion Version = Version{Major: 1, Minor: 2}
+
 	// SupportedVersions lists all supported versions of the FST package.
 	SupportedVersions = []Version{
+		// 1.2 Adds a dictionary of the field names and values to the start
+		// of the documents data, with fields referencing the dictionary.
+		Version{Major: 1, Minor: 2},
 		// 1.1 Adds support for field level metadata in a proto object,
 		// and an additional postings list per Field referencing all
 		// documents which have that Field.
@@ -107,3 +115,7 @@ func (v Version) Supported() error {
 func (v Version) supportsFieldPostingsList() bool {
 	return v.Major == 1 && v.Minor >= 1
 }
+
+func (v Version) supportsDocumentsDictionary() bool {
+	return v.Major == 1 && v.Minor >= 2
+}
@@ -82,6 +82,16 @@ type WriterOptions struct {
 	// amount (e.g. 2x). You can disable this to speed up high fixed cost
 	// lookups to during building of the FST however.
 	DisableRegistry bool
+
+	// DocumentsDictionary writes segments at DocumentsDictionaryVersion, with
+	// the field names and values of the documents encoded once in a
+	// dictionary at the start of the documents data. This shrinks the
+	// documents data of segments with repeated tags, however the writer holds
+	// every distinct field name and value in memory while building and
+	// readers decode a reference to each of them when opening a segment, so
+	// both cost memory proportional to the number of unique tag values at
+	// flush and bootstrap.
+	DocumentsDictionary bool
 }
 
 // NewWriter returns a new writer.
@@ -92,6 +102,9 @@ func NewWriter(opts WriterOptions) (Writer, error) {
 // newWriterWithVersion is a constructor used by tests to override version.
 func newWriterWithVersion(opts WriterOptions, vers *Version) (Writer, error) {
 	v := CurrentVersion
+	if opts.DocumentsDictionary {
+		v = DocumentsDictionaryVersion
+	}
 	if vers != nil {
 		v = *vers
 	}
@@ -191,6 +204,15 @@ func (w *writer) Metadata() []byte {
 func (w *writer) WriteDocumentsData(iow io.Writer) error {
 	w.docDataWriter.Reset(iow)
 
+	var currOffset uint64
+	if w.version.supportsDocumentsDictionary() {
+		n, err := w.writeDocumentsDictionary()
+		if err != nil {
+			return err
+		}
+		currOffset = uint64(n)
+	}
+
 	iter, err := w.builder.AllDocs()
 	closer := x.NewSafeCloser(iter)
 	defer closer.Close()
@@ -198,7 +220,6 @@ func (w *writer) WriteDocumentsData(iow io.Writer) error {
 		return err
 	}
 
-	var currOffset uint64
 	if int64(cap(w.docOffsets)) < w.size {
 		w.docOffsets = make([]docOffset, 0, w.size)
 	}
@@ -216,6 +237,29 @@ func (w *writer) WriteDocumentsData(iow io.Writer) error {
 	return closer.Close()
 }
 
+func (w *writer) writeDocumentsDictionary() (int, error) {
+	iter, err := w.builder.AllDocs()
+	closer := x.NewSafeCloser(iter)
+	defer closer.Close()
+	if err != nil {
+		return 0, err
+	}
+
+	for iter.Next() {
+		if err := w.docDataWriter.AddToDictionary(iter.Current()); err != nil {
+			return 0, err
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return 0, err
+	}
+	if err := closer.Close(); err != nil {
+		return 0, err
+	}
+
+	return w.docDataWriter.WriteDictionary()
+}
+
 func (w *writer) WriteDocumentsIndex(iow io.Writer) error {
 	if !w.docsDataFileWritten {
 		return fmt.Errorf("documents data file has to be written before documents index file")
@@ -116,6 +116,10 @@ func newTestCases(t *testing.T, docs []doc.Document) []testSegmentCase {
 		Version{Major: 1, Minor: 1}, /* writer version */
 		Version{Major: 1, Minor: 1} /* reader version */)
 
+	fstWriter12Reader12 := newFSTSegmentWithVersion(t, memSeg, testOptions,
+		Version{Major: 1, Minor: 2}, /* writer version */
+		Version{Major: 1, Minor: 2} /* reader version */)
+
 	return []testSegmentCase{
 		testSegmentCase{ // mem sgmt v latest fst
 			name:     "mem v fst",
@@ -137,6 +141,11 @@ func newTestCases(t *testing.T, docs []doc.Document) []testSegmentCase {
 			expected: memSeg,
 			observed: fstWriter11Reader11,
 		},
+		testSegmentCase{ // mem sgmt v fst (WriterV1.2; ReaderV1.2)
+			name:     "mem v fstWriter12Reader12",
+			expected: memSeg,
+			observed: fstWriter12Reader12,
+		},
 	}
 }
 
@@ -152,6 +161,18 @@ func TestConstruction(t *testing.T) {
 	}
 }
 
+func TestWriterVersion(t *testing.T) {
+	w, err := NewWriter(WriterOptions{})
+	require.NoError(t, err)
+	require.Equal(t, CurrentVersion.Major, w.MajorVersion())
+	require.Equal(t, CurrentVersion.Minor, w.MinorVersion())
+
+	w, err = NewWriter(WriterOptions{DocumentsDictionary: true})
+	require.NoError(t, err)
+	require.Equal(t, DocumentsDictionaryVersion.Major, w.MajorVersion())
+	require.Equal(t, DocumentsDictionaryVersion.Minor, w.MinorVersion())
+}
+
 func TestSizeEquals(t *testing.T) {
 	for _, test := range testDocuments {
 		t.Run(test.name, func(t *testing.T) {
@@ -363,6 +384,55 @@ func TestPostingsListContainsID(t *testing.T) {
 	}
 }
 
+func TestReaderIDsAfter(t *testing.T) {
+	for _, test := range testDocuments {
+		t.Run(test.name, func(t *testing.T) {
+			for _, tc := range newTestCases(t, test.docs) {
+				t.Run(tc.name, func(t *testing.T) {
+					expSeg, obsSeg := tc.expected, tc.observed
+					expIDsIter, err := expSeg.TermsIterable().Terms(doc.IDReservedFieldName)
+					require.NoError(t, err)
+					var expIDs []string
+					for id := range toTermPostings(t, expIDsIter) {
+						expIDs = append(expIDs, id)
+					}
+					sort.Strings(expIDs)
+
+					obsReader, err := obsSeg.Reader()
+					require.NoError(t, err)
+					orderedReader, ok := obsReader.(index.OrderedIDsReader)
+					require.True(t, ok)
+
+					for _, after := range []string{"", expIDs[len(expIDs)/2], expIDs[len(expIDs)-1]} {
+						iter, err := orderedReader.IDsAfter([]byte(after))
+						require.NoError(t, err)
+
+						var obsIDs []string
+						for iter.Next() {
+							obsIDs = append(obsIDs, string(iter.Current()))
+							d, err := obsReader.Doc(iter.PostingsID())
+							require.NoError(t, err)
+							require.Equal(t, iter.Current(), d.ID)
+						}
+						require.NoError(t, iter.Err())
+						require.NoError(t, iter.Close())
+
+						idx := sort.SearchStrings(expIDs, after)
+						if idx < len(expIDs) && expIDs[idx] == after {
+							idx++
+						}
+						require.Equal(t, len(expIDs)-idx, len(obsIDs))
+						for i, id := range obsIDs {
+							require.Equal(t, expIDs[idx+i], id)
+						}
+					}
+					require.NoError(t, obsReader.Close())
+				})
+			}
+		})
+	}
+}
+
 func TestPostingsListRegexAll(t *testing.T) {
 	for _, test := range testDocuments {
 		t.Run(test.name, func(t *testing.T) {
@@ -394,6 +464,92 @@ func TestPostingsListRegexAll(t *testing.T) {
 	}
 }
 
+func TestPostingsListMatchTerms(t *testing.T) {
+	var (
+		wildcard, _     = index.NewWildcardTermsMatcher([]byte("*a?p*e"))
+		lexRange, _     = index.NewRangeTermsMatcher([]byte("apple"), []byte("pear"), true, false, false)
+		numericRange, _ = index.NewRangeTermsMatcher([]byte("0"), []byte("100"), false, true, true)
+		matchers        = map[string]index.TermsMatcher{
+			"prefix":        index.NewPrefixTermsMatcher([]byte("app")),
+			"empty prefix":  index.NewPrefixTermsMatcher(nil),
+			"wildcard":      wildcard,
+			"lexical range": lexRange,
+			"numeric range": numericRange,
+		}
+	)
+	for _, test := range testDocuments {
+		t.Run(test.name, func(t *testing.T) {
+			for _, tc := range newTestCases(t, test.docs) {
+				t.Run(tc.name, func(t *testing.T) {
+					expSeg, obsSeg := tc.expected, tc.observed
+					fieldsIter, err := expSeg.FieldsIterable().Fields()
+					require.NoError(t, err)
+					fields := toSlice(t, fieldsIter)
+					for name, m := range matchers {
+						for _, f := range fields {
+							reader, err := expSeg.Reader()
+							require.NoError(t, err)
+							expPl, err := reader.MatchTerms(f, m)
+							require.NoError(t, err)
+
+							obsReader, err := obsSeg.Reader()
+							require.NoError(t, err)
+							obsPl, err := obsReader.MatchTerms(f, m)
+							require.NoError(t, err)
+							require.True(t, expPl.Equal(obsPl), "matcher %s, field %s", name, f)
+						}
+					}
+				})
+			}
+		})
+	}
+}
+
+func TestTermsIterableMatchingTerms(t *testing.T) {
+	var (
+		wildcard, _ = index.NewWildcardTermsMatcher([]byte("*a?p*e"))
+		fuzzy, _    = index.NewFuzzyPrefixTermsMatcher([]byte("bananz"), 1)
+		matchers    = map[string]index.TermsMatcher{
+			"prefix":       index.NewPrefixTermsMatcher([]byte("app")),
+			"empty prefix": index.NewPrefixTermsMatcher(nil),
+			"wildcard":     wildcard,
+			"fuzzy prefix": fuzzy,
+		}
+	)
+	for _, test := range testDocuments {
+		t.Run(test.name, func(t *testing.T) {
+			for _, tc := range newTestCases(t, test.docs) {
+				t.Run(tc.name, func(t *testing.T) {
+					expSeg, obsSeg := tc.expected, tc.observed
+					fieldsIter, err := expSeg.FieldsIterable().Fields()
+					require.NoError(t, err)
+					fields := toSlice(t, fieldsIter)
+
+					obsIterable, ok := obsSeg.TermsIterable().(sgmt.MatchingTermsIterable)
+					require.True(t, ok)
+					for name, m := range matchers {
+						for _, f := range fields {
+							expTermsIter, err := expSeg.TermsIterable().Terms(f)
+							require.NoError(t, err)
+							expTerms := toTermPostings(t, expTermsIter)
+							for term := range expTerms {
+								if !m.MatchTerm([]byte(term)) {
+									delete(expTerms, term)
+								}
+							}
+
+							obsTermsIter, err := obsIterable.MatchingTerms(f, m)
+							require.NoError(t, err)
+							obsTerms := toTermPostings(t, obsTermsIter)
+							require.Equal(t, expTerms, obsTerms, "matcher %s, field %s", name, f)
+						}
+					}
+				})
+			}
+		})
+	}
+}
+
 func TestSegmentDocs(t *testing.T) {
 	for _, test := range testDocuments {
 		t.Run(test.name, func(t *testing.T) {
@@ -29,6 +29,7 @@ import (
 	"regexp"
 
 	"github.com/m3db/m3/src/m3ninx/doc"
+	"github.com/m3db/m3/src/m3ninx/index"
 	"github.com/m3db/m3/src/m3ninx/postings"
 
 	"github.com/golang/mock/gomock"
@@ -87,6 +88,21 @@ func (mr *MockReadableSegmentMockRecorder) matchRegexp(arg0, arg1 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "matchRegexp", reflect.TypeOf((*MockReadableSegment)(nil).matchRegexp), arg0, arg1)
 }
 
+// matchTerms mocks base method
+func (m *MockReadableSegment) matchTerms(arg0 []byte, arg1 index.TermsMatcher) (postings.List, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "matchTerms", arg0, arg1)
+	ret0, _ := ret[0].(postings.List)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// matchTerms indicates an expected call of matchTerms
+func (mr *MockReadableSegmentMockRecorder) matchTerms(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "matchTerms", reflect.TypeOf((*MockReadableSegment)(nil).matchTerms), arg0, arg1)
+}
+
 // matchTerm mocks base method
 func (m *MockReadableSegment) matchTerm(arg0, arg1 []byte) (postings.List, error) {
 	m.ctrl.T.Helper()
@@ -101,3 +117,18 @@ func (mr *MockReadableSegmentMockRecorder) matchTerm(arg0, arg1 interface{}) *go
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "matchTerm", reflect.TypeOf((*MockReadableSegment)(nil).matchTerm), arg0, arg1)
 }
+
+// numTerms mocks base method
+func (m *MockReadableSegment) numTerms(arg0 []byte) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "numTerms", arg0)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// numTerms indicates an expected call of numTerms
+func (mr *MockReadableSegmentMockRecorder) numTerms(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "numTerms", reflect.TypeOf((*MockReadableSegment)(nil).numTerms), arg0)
+}
@@ -32,6 +32,7 @@ import (
 var (
 	errSegmentReaderClosed = errors.New("segment reader is closed")
 	errReaderNilRegex      = errors.New("nil regex received")
+	errReaderNilMatcher    = errors.New("nil terms matcher received")
 )
 
 type reader struct {
@@ -96,6 +97,20 @@ func (r *reader) MatchRegexp(field []byte, compiled index.CompiledRegex) (postin
 	return r.segment.matchRegexp(field, compileRE)
 }
 
+func (r *reader) MatchTerms(field []byte, m index.TermsMatcher) (postings.List, error) {
+	r.RLock()
+	defer r.RUnlock()
+	if r.closed {
+		return nil, errSegmentReaderClosed
+	}
+
+	if m == nil {
+		return nil, errReaderNilMatcher
+	}
+
+	return r.segment.matchTerms(field, m)
+}
+
 func (r *reader) MatchAll() (postings.MutableList, error) {
 	r.RLock()
 	defer r.RUnlock()
@@ -111,6 +126,35 @@ func (r *reader) MatchAll() (postings.MutableList, error) {
 	return pl, nil
 }
 
+func (r *reader) NumDocs() (int, error) {
+	r.RLock()
+	defer r.RUnlock()
+	if r.closed {
+		return 0, errSegmentReaderClosed
+	}
+
+	return int(r.limits.endExclusive - r.limits.startInclusive), nil
+}
+
+func (r *reader) NumTerms(field []byte) (int, error) {
+	r.RLock()
+	defer r.RUnlock()
+	if r.closed {
+		return 0, errSegmentReaderClosed
+	}
+
+	return r.segment.numTerms(field)
+}
+
+func (r *reader) TermCardinality(field, term []byte) (int, error) {
+	pl, err := r.MatchTerm(field, term)
+	if err != nil {
+		return 0, err
+	}
+
+	return pl.Len(), nil
+}
+
 func (r *reader) Doc(id postings.ID) (doc.Document, error) {
 	r.RLock()
 	defer r.RUnlock()
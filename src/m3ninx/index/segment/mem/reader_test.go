@@ -59,6 +59,40 @@ func TestReaderMatchExact(t *testing.T) {
 	require.NoError(t, reader.Close())
 }
 
+func TestReaderStats(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	name, value := []byte("apple"), []byte("red")
+	postingsList := roaring.NewPostingsList()
+	require.NoError(t, postingsList.Insert(postings.ID(42)))
+	require.NoError(t, postingsList.Insert(postings.ID(50)))
+
+	segment := NewMockReadableSegment(mockCtrl)
+	gomock.InOrder(
+		segment.EXPECT().numTerms(name).Return(3, nil),
+		segment.EXPECT().matchTerm(name, value).Return(postingsList, nil),
+	)
+
+	reader := newReader(segment, readerDocRange{10, 55}, postings.NewPool(nil, roaring.NewPostingsList))
+	statsReader, ok := reader.(index.StatsReader)
+	require.True(t, ok)
+
+	numDocs, err := statsReader.NumDocs()
+	require.NoError(t, err)
+	require.Equal(t, 45, numDocs)
+
+	numTerms, err := statsReader.NumTerms(name)
+	require.NoError(t, err)
+	require.Equal(t, 3, numTerms)
+
+	cardinality, err := statsReader.TermCardinality(name, value)
+	require.NoError(t, err)
+	require.Equal(t, 2, cardinality)
+
+	require.NoError(t, reader.Close())
+}
+
 func TestReaderMatchRegex(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
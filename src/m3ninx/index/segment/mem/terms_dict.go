@@ -25,6 +25,7 @@ import (
 	"sync"
 
 	"github.com/m3db/m3/src/m3ninx/doc"
+	"github.com/m3db/m3/src/m3ninx/index"
 	sgmt "github.com/m3db/m3/src/m3ninx/index/segment"
 	"github.com/m3db/m3/src/m3ninx/postings"
 )
@@ -94,6 +95,19 @@ func (d *termsDict) Terms(field []byte) sgmt.TermsIterator {
 	return values.Keys()
 }
 
+func (d *termsDict) NumTerms(field []byte) int {
+	d.fields.RLock()
+	postingsMap, ok := d.fields.Get(field)
+	d.fields.RUnlock()
+	if !ok {
+		return 0
+	}
+	postingsMap.RLock()
+	n := postingsMap.Len()
+	postingsMap.RUnlock()
+	return n
+}
+
 func (d *termsDict) matchTerm(field, term []byte) (postings.List, bool) {
 	d.fields.RLock()
 	postingsMap, ok := d.fields.Get(field)
@@ -125,6 +139,23 @@ func (d *termsDict) MatchRegexp(
 	return pl
 }
 
+func (d *termsDict) MatchTerms(
+	field []byte,
+	matcher index.TermsMatcher,
+) postings.List {
+	d.fields.RLock()
+	postingsMap, ok := d.fields.Get(field)
+	d.fields.RUnlock()
+	if !ok {
+		return d.opts.PostingsListPool().Get()
+	}
+	pl, ok := postingsMap.GetTermsMatch(matcher)
+	if !ok {
+		return d.opts.PostingsListPool().Get()
+	}
+	return pl
+}
+
 func (d *termsDict) Reset() {
 	d.fields.Lock()
 	defer d.fields.Unlock()
@@ -24,6 +24,7 @@ import (
 	re "regexp"
 
 	"github.com/m3db/m3/src/m3ninx/doc"
+	"github.com/m3db/m3/src/m3ninx/index"
 	sgmt "github.com/m3db/m3/src/m3ninx/index/segment"
 	"github.com/m3db/m3/src/m3ninx/postings"
 )
@@ -49,12 +50,19 @@ type termsDictionary interface {
 	// given egular expression.
 	MatchRegexp(field []byte, compiled *re.Regexp) postings.List
 
+	// MatchTerms returns the postings list corresponding to documents which have
+	// a term matched by the given terms matcher.
+	MatchTerms(field []byte, matcher index.TermsMatcher) postings.List
+
 	// Fields returns the known fields.
 	Fields() sgmt.FieldsIterator
 
 	// Terms returns the known terms values for the given field.
 	Terms(field []byte) sgmt.TermsIterator
 
+	// NumTerms returns the number of known terms values for the given field.
+	NumTerms(field []byte) int
+
 	// Reset resets the terms dictionary for reuse.
 	Reset()
 }
@@ -72,6 +80,13 @@ type ReadableSegment interface {
 	// matchRegexp returns the postings list of documents which match the given regular expression.
 	matchRegexp(field []byte, compiled *re.Regexp) (postings.List, error)
 
+	// matchTerms returns the postings list of documents which have a term matched by
+	// the given terms matcher.
+	matchTerms(field []byte, matcher index.TermsMatcher) (postings.List, error)
+
 	// getDoc returns the document associated with the given ID.
 	getDoc(id postings.ID) (doc.Document, error)
+
+	// numTerms returns the number of terms of the given field.
+	numTerms(field []byte) (int, error)
 }
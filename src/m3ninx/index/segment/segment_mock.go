@@ -234,6 +234,44 @@ func (mr *MockTermsIterableMockRecorder) Terms(field interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Terms", reflect.TypeOf((*MockTermsIterable)(nil).Terms), field)
 }
 
+// MockMatchingTermsIterable is a mock of MatchingTermsIterable interface
+type MockMatchingTermsIterable struct {
+	ctrl     *gomock.Controller
+	recorder *MockMatchingTermsIterableMockRecorder
+}
+
+// MockMatchingTermsIterableMockRecorder is the mock recorder for MockMatchingTermsIterable
+type MockMatchingTermsIterableMockRecorder struct {
+	mock *MockMatchingTermsIterable
+}
+
+// NewMockMatchingTermsIterable creates a new mock instance
+func NewMockMatchingTermsIterable(ctrl *gomock.Controller) *MockMatchingTermsIterable {
+	mock := &MockMatchingTermsIterable{ctrl: ctrl}
+	mock.recorder = &MockMatchingTermsIterableMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockMatchingTermsIterable) EXPECT() *MockMatchingTermsIterableMockRecorder {
+	return m.recorder
+}
+
+// MatchingTerms mocks base method
+func (m *MockMatchingTermsIterable) MatchingTerms(field []byte, m index.TermsMatcher) (TermsIterator, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MatchingTerms", field, m)
+	ret0, _ := ret[0].(TermsIterator)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MatchingTerms indicates an expected call of MatchingTerms
+func (mr *MockMatchingTermsIterableMockRecorder) MatchingTerms(field, m interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MatchingTerms", reflect.TypeOf((*MockMatchingTermsIterable)(nil).MatchingTerms), field, m)
+}
+
 // MockOrderedBytesIterator is a mock of OrderedBytesIterator interface
 type MockOrderedBytesIterator struct {
 	ctrl     *gomock.Controller
@@ -1093,6 +1131,18 @@ func (mr *MockDocumentsBuilderMockRecorder) InsertBatch(b interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertBatch", reflect.TypeOf((*MockDocumentsBuilder)(nil).InsertBatch), b)
 }
 
+// SetFieldAnalyzers mocks base method
+func (m *MockDocumentsBuilder) SetFieldAnalyzers(value doc.FieldAnalyzers) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetFieldAnalyzers", value)
+}
+
+// SetFieldAnalyzers indicates an expected call of SetFieldAnalyzers
+func (mr *MockDocumentsBuilderMockRecorder) SetFieldAnalyzers(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetFieldAnalyzers", reflect.TypeOf((*MockDocumentsBuilder)(nil).SetFieldAnalyzers), value)
+}
+
 // MockSegmentsBuilder is a mock of SegmentsBuilder interface
 type MockSegmentsBuilder struct {
 	ctrl     *gomock.Controller
@@ -1187,6 +1237,18 @@ func (mr *MockSegmentsBuilderMockRecorder) AllDocs() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AllDocs", reflect.TypeOf((*MockSegmentsBuilder)(nil).AllDocs))
 }
 
+// SetFilter mocks base method
+func (m *MockSegmentsBuilder) SetFilter(keep DocumentsFilter) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetFilter", keep)
+}
+
+// SetFilter indicates an expected call of SetFilter
+func (mr *MockSegmentsBuilderMockRecorder) SetFilter(keep interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetFilter", reflect.TypeOf((*MockSegmentsBuilder)(nil).SetFilter), keep)
+}
+
 // AddSegments mocks base method
 func (m *MockSegmentsBuilder) AddSegments(segments []Segment) error {
 	m.ctrl.T.Helper()
@@ -81,6 +81,16 @@ type TermsIterable interface {
 	Terms(field []byte) (TermsIterator, error)
 }
 
+// MatchingTermsIterable can iterate over the segment terms of a field which
+// are matched by a terms matcher, it is not by default concurrency safe.
+type MatchingTermsIterable interface {
+	// MatchingTerms returns an iterator over the known terms values for the
+	// given field which are matched by the terms matcher, in order by name.
+	// Segments backed by a terms dictionary walk only the terms accepted by
+	// the automaton and range of the matcher.
+	MatchingTerms(field []byte, m index.TermsMatcher) (TermsIterator, error)
+}
+
 // OrderedBytesIterator iterates over a collection of []bytes in lexicographical order.
 type OrderedBytesIterator interface {
 	// Next returns a bool indicating if there are any more elements.
@@ -171,12 +181,34 @@ type Builder interface {
 type DocumentsBuilder interface {
 	Builder
 	index.Writer
+
+	// SetFieldAnalyzers sets the analyzers of the tokenized fields until
+	// the builder is next reset.
+	SetFieldAnalyzers(value doc.FieldAnalyzers)
+}
+
+// DocumentsFilter is a filter for documents.
+type DocumentsFilter interface {
+	// Contains returns whether the document should be retained.
+	Contains(d doc.Document) bool
+}
+
+// DocumentsFilterFn is a function that implements DocumentsFilter.
+type DocumentsFilterFn func(d doc.Document) bool
+
+// Contains returns whether the document should be retained.
+func (fn DocumentsFilterFn) Contains(d doc.Document) bool {
+	return fn(d)
 }
 
 // SegmentsBuilder is a builder that is built from segments.
 type SegmentsBuilder interface {
 	Builder
 
+	// SetFilter sets a filter on which documents to retain
+	// when building the segment, until the builder is next reset.
+	SetFilter(keep DocumentsFilter)
+
 	// AddSegments adds segments to build from.
 	AddSegments(segments []Segment) error
 }
@@ -0,0 +1,692 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package index
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/m3db/vellum"
+)
+
+// MaxFuzzyEdits is the maximum number of edits a fuzzy matcher allows, larger
+// distances match too many unrelated terms to be useful.
+const MaxFuzzyEdits = 2
+
+const (
+	// maxWildcardAutomatonStates is the maximum number of states the wildcard
+	// automaton may use before falling back to matching every term in range.
+	maxWildcardAutomatonStates = 1024
+
+	// maxFuzzyAutomatonStates is the maximum number of states the fuzzy
+	// automaton may use before falling back to matching every term.
+	maxFuzzyAutomatonStates = 1024
+
+	// fuzzyMatchState is the fuzzy automaton state reached once a prefix of
+	// the term matched, every continuation of the term matches.
+	fuzzyMatchState = 1
+
+	// deadState is the automaton state from which no match is possible.
+	deadState = 0
+)
+
+var (
+	errWildcardTrailingEscape = errors.New("wildcard ends with an unterminated escape")
+	errRangeNoBounds          = errors.New("range requires at least one bound")
+	errRangeNaNBound          = errors.New("range bound is not a number")
+	errFuzzyMaxEdits          = fmt.Errorf("fuzzy max edits must be between 0 and %d", MaxFuzzyEdits)
+
+	alwaysMatchAutomaton = &vellum.AlwaysMatch{}
+)
+
+// TermsMatcher matches the terms of a field. The automaton and range are used
+// to walk a sorted terms dictionary (e.g. an FST) directly and may accept a
+// superset of the matching terms, MatchTerm is the final predicate.
+type TermsMatcher interface {
+	// Automaton returns an automaton accepting at least every matching term.
+	Automaton() vellum.Automaton
+
+	// Range returns the bounds of the matching terms, a nil bound is unbounded.
+	Range() (startInclusive, endExclusive []byte)
+
+	// MatchTerm returns whether the term matches.
+	MatchTerm(term []byte) bool
+}
+
+type prefixTermsMatcher struct {
+	prefix []byte
+	end    []byte
+}
+
+// NewPrefixTermsMatcher returns a TermsMatcher which matches every term
+// starting with the given prefix.
+func NewPrefixTermsMatcher(prefix []byte) TermsMatcher {
+	return &prefixTermsMatcher{
+		prefix: prefix,
+		end:    prefixSuccessor(prefix),
+	}
+}
+
+func (m *prefixTermsMatcher) Automaton() vellum.Automaton {
+	return alwaysMatchAutomaton
+}
+
+func (m *prefixTermsMatcher) Range() ([]byte, []byte) {
+	return m.prefix, m.end
+}
+
+func (m *prefixTermsMatcher) MatchTerm(term []byte) bool {
+	return bytes.HasPrefix(term, m.prefix)
+}
+
+// prefixSuccessor returns the smallest key greater than every key with the
+// given prefix, or nil if there is no such key.
+func prefixSuccessor(prefix []byte) []byte {
+	for i := len(prefix) - 1; i >= 0; i-- {
+		if prefix[i] != 0xff {
+			end := make([]byte, i+1)
+			copy(end, prefix)
+			end[i]++
+			return end
+		}
+	}
+	return nil
+}
+
+type wildcardTokenType byte
+
+const (
+	wildcardLiteral wildcardTokenType = iota
+	wildcardAnyRune
+	wildcardAnyRunes
+)
+
+type wildcardToken struct {
+	typ     wildcardTokenType
+	literal byte
+}
+
+type wildcardRuneToken struct {
+	typ     wildcardTokenType
+	literal rune
+}
+
+func (t wildcardRuneToken) matchRune(r rune) bool {
+	return t.typ == wildcardAnyRune || (t.typ == wildcardLiteral && t.literal == r)
+}
+
+type wildcardTermsMatcher struct {
+	runes  []wildcardRuneToken
+	prefix []byte
+	end    []byte
+	aut    vellum.Automaton
+}
+
+// NewWildcardTermsMatcher returns a TermsMatcher which matches every term
+// matching the given wildcard pattern. The pattern supports '*' to match zero
+// or more characters, '?' to match exactly one character and '\' to escape
+// the next character.
+func NewWildcardTermsMatcher(pattern []byte) (TermsMatcher, error) {
+	var (
+		tokens   []wildcardToken
+		runes    []wildcardRuneToken
+		prefix   []byte
+		inPrefix = true
+	)
+	for i := 0; i < len(pattern); {
+		r, size := utf8.DecodeRune(pattern[i:])
+		switch r {
+		case '*':
+			inPrefix = false
+			tokens = append(tokens, wildcardToken{typ: wildcardAnyRunes})
+			runes = append(runes, wildcardRuneToken{typ: wildcardAnyRunes})
+			i += size
+			continue
+		case '?':
+			inPrefix = false
+			tokens = append(tokens, wildcardToken{typ: wildcardAnyRune})
+			runes = append(runes, wildcardRuneToken{typ: wildcardAnyRune})
+			i += size
+			continue
+		case '\\':
+			i += size
+			if i >= len(pattern) {
+				return nil, errWildcardTrailingEscape
+			}
+			r, size = utf8.DecodeRune(pattern[i:])
+		}
+		literal := pattern[i : i+size]
+		for _, b := range literal {
+			tokens = append(tokens, wildcardToken{typ: wildcardLiteral, literal: b})
+		}
+		runes = append(runes, wildcardRuneToken{typ: wildcardLiteral, literal: r})
+		if inPrefix {
+			prefix = append(prefix, literal...)
+		}
+		i += size
+	}
+
+	m := &wildcardTermsMatcher{
+		runes:  runes,
+		prefix: prefix,
+		end:    prefixSuccessor(prefix),
+	}
+	if aut, ok := newWildcardAutomaton(tokens); ok {
+		m.aut = aut
+	} else {
+		m.aut = alwaysMatchAutomaton
+	}
+	return m, nil
+}
+
+func (m *wildcardTermsMatcher) Automaton() vellum.Automaton {
+	return m.aut
+}
+
+func (m *wildcardTermsMatcher) Range() ([]byte, []byte) {
+	if len(m.prefix) == 0 {
+		return nil, nil
+	}
+	return m.prefix, m.end
+}
+
+func (m *wildcardTermsMatcher) MatchTerm(term []byte) bool {
+	var (
+		p, t         = 0, 0
+		starP, starT = -1, 0
+		tokens, n    = m.runes, len(m.runes)
+	)
+	for t < len(term) {
+		r, size := utf8.DecodeRune(term[t:])
+		switch {
+		case p < n && tokens[p].typ == wildcardAnyRunes:
+			starP, starT = p, t
+			p++
+		case p < n && tokens[p].matchRune(r):
+			p++
+			t += size
+		case starP >= 0:
+			// Backtrack and let the last '*' consume one more rune.
+			_, size = utf8.DecodeRune(term[starT:])
+			starT += size
+			p, t = starP+1, starT
+		default:
+			return false
+		}
+	}
+	for p < n && tokens[p].typ == wildcardAnyRunes {
+		p++
+	}
+	return p == n
+}
+
+// wildcardAutomaton is a DFA over bytes built from the wildcard tokens. Each
+// '?' accepts a leading byte followed by any number of UTF-8 continuation
+// bytes so the automaton accepts a superset of the matching terms.
+type wildcardAutomaton struct {
+	transitions [][256]int
+	matches     []bool
+	alwaysMatch []bool
+}
+
+func newWildcardAutomaton(tokens []wildcardToken) (*wildcardAutomaton, bool) {
+	// NFA state 2*pos is at the token pos, 2*pos+1 is after a '?' at pos-1
+	// has consumed the first byte of a character.
+	var (
+		numNFAStates = 2 * (len(tokens) + 1)
+		closure      = func(set []bool) {
+			for pos := 0; pos <= len(tokens); pos++ {
+				if set[2*pos+1] {
+					set[2*pos] = true
+				}
+				if set[2*pos] && pos < len(tokens) && tokens[pos].typ == wildcardAnyRunes {
+					set[2*(pos+1)] = true
+				}
+			}
+		}
+		key = func(set []bool) string {
+			var b strings.Builder
+			for _, v := range set {
+				if v {
+					b.WriteByte('1')
+				} else {
+					b.WriteByte('0')
+				}
+			}
+			return b.String()
+		}
+		// Only stars remaining means every suffix matches.
+		starsOnlyFrom = make([]bool, len(tokens)+1)
+	)
+	starsOnlyFrom[len(tokens)] = true
+	for pos := len(tokens) - 1; pos >= 0; pos-- {
+		starsOnlyFrom[pos] = starsOnlyFrom[pos+1] && tokens[pos].typ == wildcardAnyRunes
+	}
+
+	a := &wildcardAutomaton{
+		// State 0 is the dead state.
+		transitions: make([][256]int, 1),
+		matches:     make([]bool, 1),
+		alwaysMatch: make([]bool, 1),
+	}
+	var (
+		sets  = [][]bool{nil}
+		ids   = make(map[string]int)
+		start = make([]bool, numNFAStates)
+	)
+	start[0] = true
+	closure(start)
+	add := func(set []bool) int {
+		k := key(set)
+		if id, ok := ids[k]; ok {
+			return id
+		}
+		id := len(sets)
+		ids[k] = id
+		sets = append(sets, set)
+		a.transitions = append(a.transitions, [256]int{})
+		a.matches = append(a.matches, set[2*len(tokens)])
+		always := false
+		for pos := 0; pos <= len(tokens); pos++ {
+			if set[2*pos] && starsOnlyFrom[pos] {
+				always = true
+				break
+			}
+		}
+		a.alwaysMatch = append(a.alwaysMatch, always)
+		return id
+	}
+	add(start)
+
+	for id := 1; id < len(sets); id++ {
+		if len(sets) > maxWildcardAutomatonStates {
+			return nil, false
+		}
+		set := sets[id]
+		for c := 0; c < 256; c++ {
+			var (
+				b    = byte(c)
+				next = make([]bool, numNFAStates)
+				live bool
+			)
+			for pos := 0; pos <= len(tokens); pos++ {
+				if set[2*pos+1] && !utf8.RuneStart(b) {
+					next[2*pos+1] = true
+					live = true
+				}
+				if !set[2*pos] || pos == len(tokens) {
+					continue
+				}
+				switch tok := tokens[pos]; tok.typ {
+				case wildcardLiteral:
+					if tok.literal == b {
+						next[2*(pos+1)] = true
+						live = true
+					}
+				case wildcardAnyRune:
+					next[2*(pos+1)+1] = true
+					live = true
+				case wildcardAnyRunes:
+					next[2*pos] = true
+					live = true
+				}
+			}
+			if !live {
+				a.transitions[id][c] = deadState
+				continue
+			}
+			closure(next)
+			a.transitions[id][c] = add(next)
+		}
+	}
+	return a, true
+}
+
+func (a *wildcardAutomaton) Start() int {
+	return 1
+}
+
+func (a *wildcardAutomaton) IsMatch(s int) bool {
+	return a.matches[s]
+}
+
+func (a *wildcardAutomaton) CanMatch(s int) bool {
+	return s != deadState
+}
+
+func (a *wildcardAutomaton) WillAlwaysMatch(s int) bool {
+	return a.alwaysMatch[s]
+}
+
+func (a *wildcardAutomaton) Accept(s int, b byte) int {
+	return a.transitions[s][b]
+}
+
+type rangeTermsMatcher struct {
+	min, max                   []byte
+	minInclusive, maxInclusive bool
+
+	numeric            bool
+	minValue, maxValue float64
+	end                []byte
+}
+
+// NewRangeTermsMatcher returns a TermsMatcher which matches every term
+// between min and max. An empty bound is unbounded. If numeric is set the
+// bounds and terms are compared as floating point numbers and terms which
+// are not numbers never match, otherwise they are compared lexicographically.
+func NewRangeTermsMatcher(
+	min, max []byte,
+	minInclusive, maxInclusive bool,
+	numeric bool,
+) (TermsMatcher, error) {
+	if len(min) == 0 && len(max) == 0 {
+		return nil, errRangeNoBounds
+	}
+
+	m := &rangeTermsMatcher{
+		min:          min,
+		max:          max,
+		minInclusive: minInclusive,
+		maxInclusive: maxInclusive,
+		numeric:      numeric,
+	}
+	if numeric {
+		var err error
+		if len(min) > 0 {
+			if m.minValue, err = parseRangeBound(min); err != nil {
+				return nil, fmt.Errorf("invalid numeric range min %q: %v", min, err)
+			}
+		}
+		if len(max) > 0 {
+			if m.maxValue, err = parseRangeBound(max); err != nil {
+				return nil, fmt.Errorf("invalid numeric range max %q: %v", max, err)
+			}
+		}
+		return m, nil
+	}
+
+	if len(max) > 0 {
+		m.end = max
+		if maxInclusive {
+			m.end = append(append(make([]byte, 0, len(max)+1), max...), 0)
+		}
+	}
+	return m, nil
+}
+
+func parseRangeBound(b []byte) (float64, error) {
+	v, err := strconv.ParseFloat(string(b), 64)
+	if err != nil {
+		return 0, err
+	}
+	if math.IsNaN(v) {
+		return 0, errRangeNaNBound
+	}
+	return v, nil
+}
+
+func (m *rangeTermsMatcher) Automaton() vellum.Automaton {
+	return alwaysMatchAutomaton
+}
+
+func (m *rangeTermsMatcher) Range() ([]byte, []byte) {
+	if m.numeric {
+		// Numeric order does not follow the byte order of terms.
+		return nil, nil
+	}
+	return m.min, m.end
+}
+
+func (m *rangeTermsMatcher) MatchTerm(term []byte) bool {
+	if m.numeric {
+		v, err := strconv.ParseFloat(string(term), 64)
+		if err != nil || math.IsNaN(v) {
+			return false
+		}
+		if len(m.min) > 0 && (v < m.minValue || (v == m.minValue && !m.minInclusive)) {
+			return false
+		}
+		if len(m.max) > 0 && (v > m.maxValue || (v == m.maxValue && !m.maxInclusive)) {
+			return false
+		}
+		return true
+	}
+
+	if len(m.min) > 0 {
+		c := bytes.Compare(term, m.min)
+		if c < 0 || (c == 0 && !m.minInclusive) {
+			return false
+		}
+	}
+	if len(m.max) > 0 {
+		c := bytes.Compare(term, m.max)
+		if c > 0 || (c == 0 && !m.maxInclusive) {
+			return false
+		}
+	}
+	return true
+}
+
+type fuzzyPrefixTermsMatcher struct {
+	prefix   []byte
+	maxEdits int
+	aut      vellum.Automaton
+}
+
+// NewFuzzyPrefixTermsMatcher returns a TermsMatcher which matches every term
+// starting with a prefix within maxEdits byte insertions, deletions or
+// substitutions of the given prefix. A maxEdits of zero matches the same
+// terms as NewPrefixTermsMatcher.
+func NewFuzzyPrefixTermsMatcher(prefix []byte, maxEdits int) (TermsMatcher, error) {
+	if maxEdits < 0 || maxEdits > MaxFuzzyEdits {
+		return nil, errFuzzyMaxEdits
+	}
+	if maxEdits == 0 {
+		return NewPrefixTermsMatcher(prefix), nil
+	}
+
+	m := &fuzzyPrefixTermsMatcher{
+		prefix:   prefix,
+		maxEdits: maxEdits,
+	}
+	if aut, ok := newFuzzyPrefixAutomaton(prefix, maxEdits); ok {
+		m.aut = aut
+	} else {
+		m.aut = alwaysMatchAutomaton
+	}
+	return m, nil
+}
+
+func (m *fuzzyPrefixTermsMatcher) Automaton() vellum.Automaton {
+	return m.aut
+}
+
+func (m *fuzzyPrefixTermsMatcher) Range() ([]byte, []byte) {
+	// Edits to the first bytes of the prefix match terms anywhere in order.
+	return nil, nil
+}
+
+func (m *fuzzyPrefixTermsMatcher) MatchTerm(term []byte) bool {
+	var (
+		n    = len(m.prefix)
+		row  = newFuzzyRow(n)
+		next = make([]byte, n+1)
+	)
+	if int(row[n]) <= m.maxEdits {
+		return true
+	}
+	for _, b := range term {
+		fuzzyStep(m.prefix, row, b, m.maxEdits, next)
+		row, next = next, row
+		if int(row[n]) <= m.maxEdits {
+			return true
+		}
+		if int(minFuzzyDistance(row)) > m.maxEdits {
+			return false
+		}
+	}
+	return false
+}
+
+// newFuzzyRow returns the edit distances between each prefix of the pattern
+// and the empty string.
+func newFuzzyRow(n int) []byte {
+	row := make([]byte, n+1)
+	for i := range row {
+		row[i] = byte(i)
+	}
+	return row
+}
+
+// fuzzyStep computes in next the edit distances between each prefix of the
+// pattern and the input consumed so far followed by b, given the distances
+// in row before b. Distances are capped at maxEdits+1 so that rows of equal
+// behaviour compare equal.
+func fuzzyStep(pattern, row []byte, b byte, maxEdits int, next []byte) {
+	limit := byte(maxEdits + 1)
+	next[0] = minByte(row[0]+1, limit)
+	for i := 1; i < len(row); i++ {
+		d := row[i-1]
+		if pattern[i-1] != b {
+			d++
+		}
+		if row[i]+1 < d {
+			d = row[i] + 1
+		}
+		if next[i-1]+1 < d {
+			d = next[i-1] + 1
+		}
+		next[i] = minByte(d, limit)
+	}
+}
+
+func minFuzzyDistance(row []byte) byte {
+	min := row[0]
+	for _, d := range row[1:] {
+		if d < min {
+			min = d
+		}
+	}
+	return min
+}
+
+func minByte(a, b byte) byte {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// fuzzyPrefixAutomaton is a DFA over bytes whose states are the capped edit
+// distance rows of the pattern, it accepts exactly the matching terms.
+type fuzzyPrefixAutomaton struct {
+	start       int
+	transitions [][256]int
+}
+
+func newFuzzyPrefixAutomaton(pattern []byte, maxEdits int) (*fuzzyPrefixAutomaton, bool) {
+	var (
+		n = len(pattern)
+		a = &fuzzyPrefixAutomaton{
+			// State 0 is the dead state and state 1 the match state, both
+			// only transition to themselves.
+			transitions: make([][256]int, 2),
+		}
+		rows      = [][]byte{nil, nil}
+		ids       = make(map[string]int)
+		inPattern [256]bool
+	)
+	for c := range a.transitions[fuzzyMatchState] {
+		a.transitions[fuzzyMatchState][c] = fuzzyMatchState
+	}
+	for _, b := range pattern {
+		inPattern[b] = true
+	}
+
+	add := func(row []byte) int {
+		if int(row[n]) <= maxEdits {
+			return fuzzyMatchState
+		}
+		if int(minFuzzyDistance(row)) > maxEdits {
+			return deadState
+		}
+		k := string(row)
+		if id, ok := ids[k]; ok {
+			return id
+		}
+		id := len(rows)
+		ids[k] = id
+		rows = append(rows, row)
+		a.transitions = append(a.transitions, [256]int{})
+		return id
+	}
+	a.start = add(newFuzzyRow(n))
+
+	for id := 2; id < len(rows); id++ {
+		if len(rows) > maxFuzzyAutomatonStates {
+			return nil, false
+		}
+		var (
+			row   = rows[id]
+			other = -1
+		)
+		for c := 0; c < 256; c++ {
+			// Every byte absent from the pattern leads to the same state.
+			if !inPattern[c] && other >= 0 {
+				a.transitions[id][c] = other
+				continue
+			}
+			next := make([]byte, n+1)
+			fuzzyStep(pattern, row, byte(c), maxEdits, next)
+			a.transitions[id][c] = add(next)
+			if !inPattern[c] {
+				other = a.transitions[id][c]
+			}
+		}
+	}
+	return a, true
+}
+
+func (a *fuzzyPrefixAutomaton) Start() int {
+	return a.start
+}
+
+func (a *fuzzyPrefixAutomaton) IsMatch(s int) bool {
+	return s == fuzzyMatchState
+}
+
+func (a *fuzzyPrefixAutomaton) CanMatch(s int) bool {
+	return s != deadState
+}
+
+func (a *fuzzyPrefixAutomaton) WillAlwaysMatch(s int) bool {
+	return s == fuzzyMatchState
+}
+
+func (a *fuzzyPrefixAutomaton) Accept(s int, b byte) int {
+	return a.transitions[s][b]
+}
@@ -0,0 +1,286 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package index
+
+import (
+	"testing"
+
+	"github.com/m3db/vellum"
+	"github.com/stretchr/testify/require"
+)
+
+func automatonAccepts(a vellum.Automaton, term []byte) bool {
+	s := a.Start()
+	for _, b := range term {
+		if !a.CanMatch(s) {
+			return false
+		}
+		s = a.Accept(s, b)
+	}
+	return a.CanMatch(s) && a.IsMatch(s)
+}
+
+func inRange(m TermsMatcher, term []byte) bool {
+	start, end := m.Range()
+	return string(term) >= string(start) && (end == nil || string(term) < string(end))
+}
+
+func TestPrefixTermsMatcher(t *testing.T) {
+	m := NewPrefixTermsMatcher([]byte("app"))
+	for _, term := range []string{"app", "apple", "applesauce"} {
+		require.True(t, m.MatchTerm([]byte(term)), term)
+		require.True(t, inRange(m, []byte(term)), term)
+	}
+	for _, term := range []string{"ap", "banana", "aqp"} {
+		require.False(t, m.MatchTerm([]byte(term)), term)
+	}
+
+	start, end := m.Range()
+	require.Equal(t, []byte("app"), start)
+	require.Equal(t, []byte("apq"), end)
+}
+
+func TestPrefixSuccessor(t *testing.T) {
+	require.Equal(t, []byte("b"), prefixSuccessor([]byte("a")))
+	require.Equal(t, []byte("b"), prefixSuccessor([]byte("a\xff")))
+	require.Nil(t, prefixSuccessor([]byte("\xff\xff")))
+	require.Nil(t, prefixSuccessor(nil))
+}
+
+func TestWildcardTermsMatcher(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		term     string
+		expected bool
+	}{
+		{pattern: "foo*", term: "foo", expected: true},
+		{pattern: "foo*", term: "foobar", expected: true},
+		{pattern: "foo*", term: "fo", expected: false},
+		{pattern: "*bar", term: "foobar", expected: true},
+		{pattern: "*bar", term: "foobaz", expected: false},
+		{pattern: "f?o", term: "foo", expected: true},
+		{pattern: "f?o", term: "fo", expected: false},
+		{pattern: "f?o", term: "fééo", expected: false},
+		{pattern: "f?o", term: "féo", expected: true},
+		{pattern: "a*b*c", term: "aXbYbZc", expected: true},
+		{pattern: "a*b*c", term: "aXbYbZ", expected: false},
+		{pattern: `a\*`, term: "a*", expected: true},
+		{pattern: `a\*`, term: "ab", expected: false},
+		{pattern: `a\?`, term: "a?", expected: true},
+		{pattern: "", term: "", expected: true},
+		{pattern: "", term: "a", expected: false},
+		{pattern: "*", term: "", expected: true},
+		{pattern: "**", term: "anything", expected: true},
+	}
+
+	for _, test := range tests {
+		m, err := NewWildcardTermsMatcher([]byte(test.pattern))
+		require.NoError(t, err)
+
+		term := []byte(test.term)
+		require.Equal(t, test.expected, m.MatchTerm(term), "%s %s", test.pattern, test.term)
+		if test.expected {
+			// The automaton and range must accept a superset of the matching terms.
+			require.True(t, automatonAccepts(m.Automaton(), term), "%s %s", test.pattern, test.term)
+			require.True(t, inRange(m, term), "%s %s", test.pattern, test.term)
+		}
+	}
+}
+
+func TestWildcardTermsMatcherAutomatonPrunes(t *testing.T) {
+	m, err := NewWildcardTermsMatcher([]byte("foo.*.bar"))
+	require.NoError(t, err)
+
+	a := m.Automaton()
+	s := a.Start()
+	for _, b := range []byte("fox") {
+		s = a.Accept(s, b)
+	}
+	require.False(t, a.CanMatch(s))
+
+	s = a.Start()
+	for _, b := range []byte("foo.") {
+		s = a.Accept(s, b)
+	}
+	require.True(t, a.CanMatch(s))
+	require.False(t, a.WillAlwaysMatch(s))
+}
+
+func TestWildcardTermsMatcherTrailingEscape(t *testing.T) {
+	_, err := NewWildcardTermsMatcher([]byte(`foo\`))
+	require.Error(t, err)
+}
+
+func TestRangeTermsMatcher(t *testing.T) {
+	tests := []struct {
+		name                       string
+		min, max                   string
+		minInclusive, maxInclusive bool
+		numeric                    bool
+		matches, notMatches        []string
+	}{
+		{
+			name:         "lexicographic inclusive",
+			min:          "b",
+			max:          "d",
+			minInclusive: true,
+			maxInclusive: true,
+			matches:      []string{"b", "c", "czzz", "d"},
+			notMatches:   []string{"a", "d0", "e"},
+		},
+		{
+			name:       "lexicographic exclusive",
+			min:        "b",
+			max:        "d",
+			matches:    []string{"b0", "c"},
+			notMatches: []string{"b", "d"},
+		},
+		{
+			name:         "lexicographic unbounded max",
+			min:          "b",
+			minInclusive: true,
+			matches:      []string{"b", "zzz"},
+			notMatches:   []string{"a"},
+		},
+		{
+			name:         "numeric",
+			min:          "2",
+			max:          "10",
+			minInclusive: true,
+			numeric:      true,
+			matches:      []string{"2", "2.5", "9.99", "3e0"},
+			notMatches:   []string{"1", "10", "100", "abc", ""},
+		},
+		{
+			name:         "numeric unbounded min",
+			max:          "0",
+			maxInclusive: true,
+			numeric:      true,
+			matches:      []string{"0", "-1", "-1e9"},
+			notMatches:   []string{"1", "NaN"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m, err := NewRangeTermsMatcher([]byte(test.min), []byte(test.max),
+				test.minInclusive, test.maxInclusive, test.numeric)
+			require.NoError(t, err)
+
+			for _, term := range test.matches {
+				require.True(t, m.MatchTerm([]byte(term)), term)
+				require.True(t, inRange(m, []byte(term)), term)
+			}
+			for _, term := range test.notMatches {
+				require.False(t, m.MatchTerm([]byte(term)), term)
+			}
+		})
+	}
+}
+
+func TestRangeTermsMatcherErrors(t *testing.T) {
+	_, err := NewRangeTermsMatcher(nil, nil, true, true, false)
+	require.Error(t, err)
+
+	_, err = NewRangeTermsMatcher([]byte("abc"), nil, true, true, true)
+	require.Error(t, err)
+}
+
+func TestFuzzyPrefixTermsMatcher(t *testing.T) {
+	tests := []struct {
+		name                string
+		prefix              string
+		maxEdits            int
+		matches, notMatches []string
+	}{
+		{
+			name:       "one edit",
+			prefix:     "cpu",
+			maxEdits:   1,
+			matches:    []string{"cpu", "cpu_user", "cu", "cpy_idle", "xcpu", "pu", "gpu"},
+			notMatches: []string{"memory", "c", "xyu", "ucp"},
+		},
+		{
+			name:       "two edits",
+			prefix:     "memory",
+			maxEdits:   2,
+			matches:    []string{"memory_used", "mem0ry", "mmeory", "emory", "nemorx"},
+			notMatches: []string{"mem", "cpu", "disk_memory"},
+		},
+		{
+			name:       "prefix shorter than edits",
+			prefix:     "a",
+			maxEdits:   1,
+			matches:    []string{"", "a", "b", "zzz"},
+			notMatches: nil,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m, err := NewFuzzyPrefixTermsMatcher([]byte(test.prefix), test.maxEdits)
+			require.NoError(t, err)
+
+			for _, term := range test.matches {
+				require.True(t, m.MatchTerm([]byte(term)), term)
+				require.True(t, automatonAccepts(m.Automaton(), []byte(term)), term)
+				require.True(t, inRange(m, []byte(term)), term)
+			}
+			for _, term := range test.notMatches {
+				require.False(t, m.MatchTerm([]byte(term)), term)
+				require.False(t, automatonAccepts(m.Automaton(), []byte(term)), term)
+			}
+		})
+	}
+}
+
+func TestFuzzyPrefixTermsMatcherAutomatonAlwaysMatches(t *testing.T) {
+	m, err := NewFuzzyPrefixTermsMatcher([]byte("http"), 1)
+	require.NoError(t, err)
+
+	a := m.Automaton()
+	s := a.Start()
+	for _, b := range []byte("htt") {
+		s = a.Accept(s, b)
+	}
+	require.True(t, a.IsMatch(s))
+	require.True(t, a.WillAlwaysMatch(s))
+
+	s = a.Start()
+	for _, b := range []byte("xyz") {
+		s = a.Accept(s, b)
+	}
+	require.False(t, a.CanMatch(s))
+}
+
+func TestFuzzyPrefixTermsMatcherZeroEdits(t *testing.T) {
+	m, err := NewFuzzyPrefixTermsMatcher([]byte("app"), 0)
+	require.NoError(t, err)
+	require.Equal(t, NewPrefixTermsMatcher([]byte("app")), m)
+}
+
+func TestFuzzyPrefixTermsMatcherErrors(t *testing.T) {
+	_, err := NewFuzzyPrefixTermsMatcher([]byte("app"), -1)
+	require.Error(t, err)
+
+	_, err = NewFuzzyPrefixTermsMatcher([]byte("app"), MaxFuzzyEdits+1)
+	require.Error(t, err)
+}
@@ -72,6 +72,10 @@ type Readable interface {
 	// regular expression.
 	MatchRegexp(field []byte, c CompiledRegex) (postings.List, error)
 
+	// MatchTerms returns a postings list over all documents which have a term of
+	// the given field accepted by the terms matcher.
+	MatchTerms(field []byte, m TermsMatcher) (postings.List, error)
+
 	// MatchAll returns a postings list for all documents known to the Reader.
 	MatchAll() (postings.MutableList, error)
 
@@ -93,6 +97,28 @@ type CompiledRegex struct {
 	PrefixEnd   []byte
 }
 
+// StatsReader is implemented by readers which can provide statistics about the
+// terms of their segment, they are used to estimate the cost of searches.
+type StatsReader interface {
+	// NumDocs returns the number of documents known to the reader.
+	NumDocs() (int, error)
+
+	// NumTerms returns the number of distinct terms of the given field.
+	NumTerms(field []byte) (int, error)
+
+	// TermCardinality returns the number of documents which match the given term.
+	TermCardinality(field, term []byte) (int, error)
+}
+
+// OrderedIDsReader is implemented by readers which can iterate the IDs of the
+// documents of their segment in increasing order, they are used to resume
+// paginated searches from a document ID without reading earlier documents.
+type OrderedIDsReader interface {
+	// IDsAfter returns an iterator over the document IDs strictly after the
+	// given ID in increasing order, an empty ID iterates every document ID.
+	IDsAfter(after []byte) (IDIterator, error)
+}
+
 // DocRetriever returns the document associated with a postings ID. It returns
 // ErrDocNotFound if there is no document corresponding to the given postings ID.
 type DocRetriever interface {
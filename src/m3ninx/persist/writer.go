@@ -30,8 +30,10 @@ import (
 
 // NewMutableSegmentFileSetWriter returns a new IndexSegmentFileSetWriter for writing
 // out the provided Mutable Segment.
-func NewMutableSegmentFileSetWriter() (MutableSegmentFileSetWriter, error) {
-	w, err := fst.NewWriter(fst.WriterOptions{})
+func NewMutableSegmentFileSetWriter(
+	fstOpts fst.WriterOptions,
+) (MutableSegmentFileSetWriter, error) {
+	w, err := fst.NewWriter(fstOpts)
 	if err != nil {
 		return nil, err
 	}
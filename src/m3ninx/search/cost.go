@@ -0,0 +1,189 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package search
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/m3db/m3/src/m3ninx/index"
+)
+
+// Cost is the relative cost of evaluating a searcher against a segment.
+type Cost int
+
+const (
+	// LookupCost is the cost of looking up a single postings list, e.g. the
+	// postings list of a term or of all documents.
+	LookupCost Cost = iota
+
+	// ScanCost is the cost of unioning the postings lists of a contiguous
+	// range of terms, e.g. every term of a field or the terms with a prefix.
+	ScanCost
+
+	// AutomatonCost is the cost of matching every term of a field with an
+	// automaton, e.g. a regular expression.
+	AutomatonCost
+)
+
+func (c Cost) String() string {
+	switch c {
+	case LookupCost:
+		return "lookup"
+	case ScanCost:
+		return "scan"
+	case AutomatonCost:
+		return "automaton"
+	}
+	return fmt.Sprintf("unknown(%d)", int(c))
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (c Cost) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+// CostEstimator is implemented by searchers which know the relative cost of
+// their evaluation.
+type CostEstimator interface {
+	// Cost returns the relative cost of evaluating the searcher.
+	Cost() Cost
+}
+
+// SearcherCost returns the relative cost of evaluating the searcher, searchers
+// which do not implement CostEstimator are assumed to be the most expensive.
+func SearcherCost(s Searcher) Cost {
+	if e, ok := s.(CostEstimator); ok {
+		return e.Cost()
+	}
+	return AutomatonCost
+}
+
+// MaxSearchersCost returns the cost of the most expensive of the searchers.
+func MaxSearchersCost(searchers Searchers) Cost {
+	max := LookupCost
+	for _, s := range searchers {
+		if c := SearcherCost(s); c > max {
+			max = c
+		}
+	}
+	return max
+}
+
+// SortSearchersByCost returns a copy of the searchers in increasing order of
+// cost, preserving the declared order of searchers with the same cost.
+func SortSearchersByCost(searchers Searchers) Searchers {
+	sorted := make(Searchers, len(searchers))
+	copy(sorted, searchers)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return SearcherCost(sorted[i]) < SearcherCost(sorted[j])
+	})
+	return sorted
+}
+
+// UnknownEstimatedCost is the estimated cost of searchers which cannot estimate
+// the cost of their evaluation, they are assumed to be the most expensive.
+const UnknownEstimatedCost = math.MaxInt32
+
+// StatsCostEstimator is implemented by searchers which can estimate the cost of
+// their evaluation against a segment from the statistics of its reader.
+type StatsCostEstimator interface {
+	// EstimateCost returns the estimated number of terms and postings which are
+	// visited to evaluate the searcher against the segment of the reader.
+	EstimateCost(r index.StatsReader) (int, error)
+}
+
+// EstimateSearcherCost returns the estimated cost of evaluating the searcher
+// against the segment of the reader.
+func EstimateSearcherCost(s Searcher, r index.StatsReader) (int, error) {
+	e, ok := s.(StatsCostEstimator)
+	if !ok {
+		return UnknownEstimatedCost, nil
+	}
+	return e.EstimateCost(r)
+}
+
+// EstimateSearchersCost returns the estimated cost of evaluating all of the
+// searchers against the segment of the reader.
+func EstimateSearchersCost(searchers Searchers, r index.StatsReader) (int, error) {
+	total := 0
+	for _, s := range searchers {
+		cost, err := EstimateSearcherCost(s, r)
+		if err != nil {
+			return 0, err
+		}
+		total = AddEstimatedCosts(total, cost)
+	}
+	return total, nil
+}
+
+// AddEstimatedCosts adds two estimated costs, the sum is capped at the
+// UnknownEstimatedCost.
+func AddEstimatedCosts(a, b int) int {
+	if a >= UnknownEstimatedCost-b {
+		return UnknownEstimatedCost
+	}
+	return a + b
+}
+
+// SortSearchersByEstimatedCost returns a copy of the searchers in increasing order
+// of their estimated cost against the segment of the reader, preserving the declared
+// order of searchers with the same estimated cost.
+func SortSearchersByEstimatedCost(
+	searchers Searchers,
+	r index.StatsReader,
+) (Searchers, error) {
+	costs := make([]int, 0, len(searchers))
+	for _, s := range searchers {
+		cost, err := EstimateSearcherCost(s, r)
+		if err != nil {
+			return nil, err
+		}
+		costs = append(costs, cost)
+	}
+
+	sorted := make(Searchers, len(searchers))
+	copy(sorted, searchers)
+	sort.Stable(searchersByCost{searchers: sorted, costs: costs})
+	return sorted, nil
+}
+
+type searchersByCost struct {
+	searchers Searchers
+	costs     []int
+}
+
+func (s searchersByCost) Len() int           { return len(s.searchers) }
+func (s searchersByCost) Less(i, j int) bool { return s.costs[i] < s.costs[j] }
+func (s searchersByCost) Swap(i, j int) {
+	s.searchers[i], s.searchers[j] = s.searchers[j], s.searchers[i]
+	s.costs[i], s.costs[j] = s.costs[j], s.costs[i]
+}
+
+// Explanation describes how a query is evaluated, the children of a query
+// are listed in the order in which they are evaluated.
+type Explanation struct {
+	Query    string        `json:"query"`
+	Cost     Cost          `json:"cost"`
+	Negated  bool          `json:"negated,omitempty"`
+	Children []Explanation `json:"children,omitempty"`
+}
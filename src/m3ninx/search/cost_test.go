@@ -0,0 +1,151 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package search
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/m3db/m3/src/m3ninx/index"
+	"github.com/m3db/m3/src/m3ninx/postings"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+type costSearcher struct {
+	cost Cost
+}
+
+func (s costSearcher) Search(index.Reader) (postings.List, error) { return nil, nil }
+func (s costSearcher) Cost() Cost                                 { return s.cost }
+
+func TestSearcherCost(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	require.Equal(t, ScanCost, SearcherCost(costSearcher{cost: ScanCost}))
+	require.Equal(t, AutomatonCost, SearcherCost(NewMockSearcher(mockCtrl)))
+}
+
+func TestMaxSearchersCost(t *testing.T) {
+	require.Equal(t, LookupCost, MaxSearchersCost(nil))
+	require.Equal(t, ScanCost, MaxSearchersCost(Searchers{
+		costSearcher{cost: LookupCost},
+		costSearcher{cost: ScanCost},
+		costSearcher{cost: LookupCost},
+	}))
+}
+
+func TestSortSearchersByCost(t *testing.T) {
+	var (
+		automaton = costSearcher{cost: AutomatonCost}
+		scan      = costSearcher{cost: ScanCost}
+		lookup    = costSearcher{cost: LookupCost}
+		searchers = Searchers{automaton, scan, lookup}
+	)
+
+	sorted := SortSearchersByCost(searchers)
+	require.Equal(t, Searchers{lookup, scan, automaton}, sorted)
+
+	// The input must be left untouched.
+	require.Equal(t, Searchers{automaton, scan, lookup}, searchers)
+}
+
+type estimatedCostSearcher struct {
+	costSearcher
+	estimate int
+}
+
+func (s estimatedCostSearcher) EstimateCost(index.StatsReader) (int, error) {
+	return s.estimate, nil
+}
+
+func TestEstimateSearcherCost(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	reader := index.NewMockStatsReader(mockCtrl)
+
+	cost, err := EstimateSearcherCost(estimatedCostSearcher{estimate: 42}, reader)
+	require.NoError(t, err)
+	require.Equal(t, 42, cost)
+
+	cost, err = EstimateSearcherCost(NewMockSearcher(mockCtrl), reader)
+	require.NoError(t, err)
+	require.Equal(t, UnknownEstimatedCost, cost)
+
+	cost, err = EstimateSearchersCost(Searchers{
+		estimatedCostSearcher{estimate: 1},
+		estimatedCostSearcher{estimate: 2},
+	}, reader)
+	require.NoError(t, err)
+	require.Equal(t, 3, cost)
+
+	cost, err = EstimateSearchersCost(Searchers{
+		estimatedCostSearcher{estimate: 1},
+		NewMockSearcher(mockCtrl),
+	}, reader)
+	require.NoError(t, err)
+	require.Equal(t, UnknownEstimatedCost, cost)
+}
+
+func TestSortSearchersByEstimatedCost(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	var (
+		reader = index.NewMockStatsReader(mockCtrl)
+		// NB: the relative costs are ignored in favor of the estimates.
+		broad     = estimatedCostSearcher{costSearcher{LookupCost}, 1000}
+		selective = estimatedCostSearcher{costSearcher{AutomatonCost}, 10}
+		other     = estimatedCostSearcher{costSearcher{ScanCost}, 10}
+		unknown   = NewMockSearcher(mockCtrl)
+		searchers = Searchers{unknown, broad, selective, other}
+	)
+
+	sorted, err := SortSearchersByEstimatedCost(searchers, reader)
+	require.NoError(t, err)
+	require.Equal(t, Searchers{selective, other, broad, unknown}, sorted)
+
+	// The input must be left untouched.
+	require.Equal(t, Searchers{unknown, broad, selective, other}, searchers)
+}
+
+func TestExplanationJSON(t *testing.T) {
+	b, err := json.Marshal(Explanation{
+		Query: "conjunction(term(fruit, apple))",
+		Cost:  LookupCost,
+		Children: []Explanation{
+			{Query: "term(fruit, apple)", Cost: LookupCost},
+			{Query: "regexp(color, r.*)", Cost: AutomatonCost, Negated: true},
+		},
+	})
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"query": "conjunction(term(fruit, apple))",
+		"cost": "lookup",
+		"children": [
+			{"query": "term(fruit, apple)", "cost": "lookup"},
+			{"query": "regexp(color, r.*)", "cost": "automaton", "negated": true}
+		]
+	}`, string(b))
+}
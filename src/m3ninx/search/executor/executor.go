@@ -35,11 +35,18 @@ var (
 
 type newIteratorFn func(s search.Searcher, rs index.Readers) (doc.Iterator, error)
 
+type newOrderedIteratorFn func(
+	s search.Searcher,
+	rs index.Readers,
+	after []byte,
+) (doc.Iterator, error)
+
 type executor struct {
 	sync.RWMutex
 
-	newIteratorFn newIteratorFn
-	readers       index.Readers
+	newIteratorFn        newIteratorFn
+	newOrderedIteratorFn newOrderedIteratorFn
+	readers              index.Readers
 
 	closed bool
 }
@@ -47,8 +54,9 @@ type executor struct {
 // NewExecutor returns a new Executor for executing queries.
 func NewExecutor(rs index.Readers) search.Executor {
 	return &executor{
-		newIteratorFn: newIterator,
-		readers:       rs,
+		newIteratorFn:        newIterator,
+		newOrderedIteratorFn: newOrderedIterator,
+		readers:              rs,
 	}
 }
 
@@ -72,6 +80,26 @@ func (e *executor) Execute(q search.Query) (doc.Iterator, error) {
 	return iter, nil
 }
 
+func (e *executor) ExecuteAfter(q search.Query, after []byte) (doc.Iterator, error) {
+	e.RLock()
+	defer e.RUnlock()
+	if e.closed {
+		return nil, errExecutorClosed
+	}
+
+	s, err := q.Searcher()
+	if err != nil {
+		return nil, err
+	}
+
+	iter, err := e.newOrderedIteratorFn(s, e.readers, after)
+	if err != nil {
+		return nil, err
+	}
+
+	return iter, nil
+}
+
 func (e *executor) Close() error {
 	e.Lock()
 	if e.closed {
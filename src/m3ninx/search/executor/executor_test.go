@@ -71,3 +71,40 @@ func TestExecutor(t *testing.T) {
 	err = e.Close()
 	require.NoError(t, err)
 }
+
+func TestExecutorAfter(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	var (
+		q  = search.NewMockQuery(mockCtrl)
+		r  = index.NewMockReader(mockCtrl)
+		rs = index.Readers{r}
+	)
+	gomock.InOrder(
+		q.EXPECT().Searcher().Return(nil, nil),
+
+		r.EXPECT().Close().Return(nil),
+	)
+
+	e := NewExecutor(rs).(*executor)
+
+	// Override newOrderedIteratorFn to return test iterator.
+	e.newOrderedIteratorFn = func(
+		_ search.Searcher,
+		_ index.Readers,
+		after []byte,
+	) (doc.Iterator, error) {
+		require.Equal(t, []byte("foo"), after)
+		return newTestIterator(), nil
+	}
+
+	it, err := e.ExecuteAfter(q, []byte("foo"))
+	require.NoError(t, err)
+
+	err = it.Close()
+	require.NoError(t, err)
+
+	err = e.Close()
+	require.NoError(t, err)
+}
@@ -0,0 +1,272 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package executor
+
+import (
+	"bytes"
+	"container/heap"
+	"sort"
+
+	"github.com/m3db/m3/src/m3ninx/doc"
+	"github.com/m3db/m3/src/m3ninx/index"
+	"github.com/m3db/m3/src/m3ninx/postings"
+	"github.com/m3db/m3/src/m3ninx/search"
+	xerrors "github.com/m3db/m3/src/x/errors"
+)
+
+// orderedIterator returns the matching documents of every reader after an ID
+// in increasing order of their IDs by merging the ordered documents of each
+// reader, documents held by more than one reader are returned once.
+type orderedIterator struct {
+	iters readerItersHeap
+
+	currDoc doc.Document
+	hasCurr bool
+
+	err    error
+	closed bool
+}
+
+func newOrderedIterator(
+	s search.Searcher,
+	rs index.Readers,
+	after []byte,
+) (doc.Iterator, error) {
+	it := &orderedIterator{
+		iters: make(readerItersHeap, 0, len(rs)),
+	}
+	for _, reader := range rs {
+		iter, err := newReaderIter(s, reader, after)
+		if err != nil {
+			it.Close()
+			return nil, err
+		}
+
+		if !iter.Next() {
+			err := xerrors.FirstError(iter.Err(), iter.Close())
+			if err != nil {
+				it.Close()
+				return nil, err
+			}
+			continue
+		}
+		it.iters = append(it.iters, iter)
+	}
+	heap.Init(&it.iters)
+
+	return it, nil
+}
+
+func (it *orderedIterator) Next() bool {
+	if it.closed || it.err != nil {
+		return false
+	}
+
+	for len(it.iters) > 0 {
+		iter := it.iters[0]
+		d := iter.Current()
+		if iter.Next() {
+			heap.Fix(&it.iters, 0)
+		} else {
+			heap.Pop(&it.iters)
+			if err := xerrors.FirstError(iter.Err(), iter.Close()); err != nil {
+				it.err = err
+				return false
+			}
+		}
+
+		if it.hasCurr && bytes.Equal(d.ID, it.currDoc.ID) {
+			continue
+		}
+		it.currDoc = d
+		it.hasCurr = true
+		return true
+	}
+
+	return false
+}
+
+func (it *orderedIterator) Current() doc.Document {
+	return it.currDoc
+}
+
+func (it *orderedIterator) Err() error {
+	return it.err
+}
+
+func (it *orderedIterator) Close() error {
+	it.closed = true
+
+	multiErr := xerrors.NewMultiError()
+	for _, iter := range it.iters {
+		multiErr = multiErr.Add(iter.Close())
+	}
+	it.iters = nil
+	return multiErr.FinalError()
+}
+
+// newReaderIter returns the ordered matching documents of a reader. Readers
+// which iterate their IDs in order are walked from the ID onwards so that a
+// page of documents only reads up to the last document of the page, the
+// documents of other readers are read and sorted.
+func newReaderIter(
+	s search.Searcher,
+	reader index.Reader,
+	after []byte,
+) (doc.Iterator, error) {
+	pl, err := s.Search(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	if r, ok := reader.(index.OrderedIDsReader); ok {
+		ids, err := r.IDsAfter(after)
+		if err != nil {
+			return nil, err
+		}
+		return &orderedIDsReaderIter{
+			ids:       ids,
+			postings:  pl,
+			retriever: reader,
+		}, nil
+	}
+
+	iter, err := reader.Docs(pl)
+	if err != nil {
+		return nil, err
+	}
+
+	var docs []doc.Document
+	for iter.Next() {
+		if d := iter.Current(); bytes.Compare(d.ID, after) > 0 {
+			docs = append(docs, d)
+		}
+	}
+	if err := xerrors.FirstError(iter.Err(), iter.Close()); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(docs, func(i, j int) bool {
+		return bytes.Compare(docs[i].ID, docs[j].ID) < 0
+	})
+	return &sortedReaderIter{docs: docs, idx: -1}, nil
+}
+
+// orderedIDsReaderIter walks the IDs of a reader in order and returns the
+// documents of the IDs which match the search.
+type orderedIDsReaderIter struct {
+	ids       index.IDIterator
+	postings  postings.List
+	retriever index.DocRetriever
+
+	currDoc doc.Document
+	err     error
+}
+
+func (it *orderedIDsReaderIter) Next() bool {
+	if it.err != nil {
+		return false
+	}
+
+	for it.ids.Next() {
+		id := it.ids.PostingsID()
+		if !it.postings.Contains(id) {
+			continue
+		}
+
+		d, err := it.retriever.Doc(id)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		it.currDoc = d
+		return true
+	}
+
+	return false
+}
+
+func (it *orderedIDsReaderIter) Current() doc.Document {
+	return it.currDoc
+}
+
+func (it *orderedIDsReaderIter) Err() error {
+	if it.err != nil {
+		return it.err
+	}
+	return it.ids.Err()
+}
+
+func (it *orderedIDsReaderIter) Close() error {
+	return it.ids.Close()
+}
+
+// sortedReaderIter returns the documents of a reader sorted by their IDs.
+type sortedReaderIter struct {
+	docs []doc.Document
+	idx  int
+}
+
+func (it *sortedReaderIter) Next() bool {
+	if it.idx >= len(it.docs)-1 {
+		return false
+	}
+	it.idx++
+	return true
+}
+
+func (it *sortedReaderIter) Current() doc.Document {
+	return it.docs[it.idx]
+}
+
+func (it *sortedReaderIter) Err() error {
+	return nil
+}
+
+func (it *sortedReaderIter) Close() error {
+	it.docs = nil
+	return nil
+}
+
+// readerItersHeap is a min heap of reader iterators ordered by the ID of
+// their current document.
+type readerItersHeap []doc.Iterator
+
+func (h readerItersHeap) Len() int { return len(h) }
+
+func (h readerItersHeap) Less(i, j int) bool {
+	return bytes.Compare(h[i].Current().ID, h[j].Current().ID) < 0
+}
+
+func (h readerItersHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *readerItersHeap) Push(x interface{}) {
+	*h = append(*h, x.(doc.Iterator))
+}
+
+func (h *readerItersHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	iter := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return iter
+}
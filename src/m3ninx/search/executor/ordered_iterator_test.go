@@ -0,0 +1,135 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package executor
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/m3db/m3/src/m3ninx/doc"
+	"github.com/m3db/m3/src/m3ninx/index"
+	"github.com/m3db/m3/src/m3ninx/postings"
+	"github.com/m3db/m3/src/m3ninx/postings/roaring"
+	"github.com/m3db/m3/src/m3ninx/search"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+// testOrderedReader is a reader which iterates the IDs of its documents in
+// order, the documents are ordered by ID and their postings IDs are their
+// positions.
+type testOrderedReader struct {
+	*index.MockReader
+
+	docs []doc.Document
+}
+
+func (r *testOrderedReader) IDsAfter(after []byte) (index.IDIterator, error) {
+	idx := 0
+	for idx < len(r.docs) && bytes.Compare(r.docs[idx].ID, after) <= 0 {
+		idx++
+	}
+	return &testIDsIterator{docs: r.docs, idx: idx - 1}, nil
+}
+
+func (r *testOrderedReader) Doc(id postings.ID) (doc.Document, error) {
+	return r.docs[id], nil
+}
+
+type testIDsIterator struct {
+	docs []doc.Document
+	idx  int
+}
+
+func (it *testIDsIterator) Next() bool {
+	if it.idx >= len(it.docs)-1 {
+		return false
+	}
+	it.idx++
+	return true
+}
+
+func (it *testIDsIterator) Current() []byte         { return it.docs[it.idx].ID }
+func (it *testIDsIterator) PostingsID() postings.ID { return postings.ID(it.idx) }
+func (it *testIDsIterator) Err() error              { return nil }
+func (it *testIDsIterator) Close() error            { return nil }
+
+func newTestDocs(ids ...string) []doc.Document {
+	docs := make([]doc.Document, 0, len(ids))
+	for _, id := range ids {
+		docs = append(docs, doc.Document{ID: []byte(id)})
+	}
+	return docs
+}
+
+func TestOrderedIterator(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	// The first reader iterates its IDs in order, only "b", "c" and "f" match.
+	orderedReader := &testOrderedReader{
+		MockReader: index.NewMockReader(mockCtrl),
+		docs:       newTestDocs("a", "b", "c", "d", "f"),
+	}
+	orderedPL := roaring.NewPostingsList()
+	require.NoError(t, orderedPL.Insert(1))
+	require.NoError(t, orderedPL.Insert(2))
+	require.NoError(t, orderedPL.Insert(4))
+
+	// The second reader returns its documents unordered, "c" is held by both
+	// readers and "a" is not after the cursor.
+	unorderedDocs := newTestDocs("e", "a", "c")
+	unorderedPL := roaring.NewPostingsList()
+	require.NoError(t, unorderedPL.Insert(0))
+	unorderedDocIter := doc.NewMockIterator(mockCtrl)
+	gomock.InOrder(
+		unorderedDocIter.EXPECT().Next().Return(true),
+		unorderedDocIter.EXPECT().Current().Return(unorderedDocs[0]),
+		unorderedDocIter.EXPECT().Next().Return(true),
+		unorderedDocIter.EXPECT().Current().Return(unorderedDocs[1]),
+		unorderedDocIter.EXPECT().Next().Return(true),
+		unorderedDocIter.EXPECT().Current().Return(unorderedDocs[2]),
+		unorderedDocIter.EXPECT().Next().Return(false),
+		unorderedDocIter.EXPECT().Err().Return(nil),
+		unorderedDocIter.EXPECT().Close().Return(nil),
+	)
+	unorderedReader := index.NewMockReader(mockCtrl)
+	unorderedReader.EXPECT().Docs(unorderedPL).Return(unorderedDocIter, nil)
+
+	searcher := search.NewMockSearcher(mockCtrl)
+	gomock.InOrder(
+		searcher.EXPECT().Search(orderedReader).Return(orderedPL, nil),
+		searcher.EXPECT().Search(unorderedReader).Return(unorderedPL, nil),
+	)
+
+	readers := index.Readers{orderedReader, unorderedReader}
+	iter, err := newOrderedIterator(searcher, readers, []byte("a"))
+	require.NoError(t, err)
+
+	var ids []string
+	for iter.Next() {
+		ids = append(ids, string(iter.Current().ID))
+	}
+	require.NoError(t, iter.Err())
+	require.NoError(t, iter.Close())
+	require.Equal(t, []string{"b", "c", "e", "f"}, ids)
+}
@@ -60,9 +60,22 @@ func unmarshal(q *querypb.Query) (search.Query, error) {
 	case *querypb.Query_Term:
 		return NewTermQuery(q.Term.Field, q.Term.Term), nil
 
+	case *querypb.Query_Match:
+		return NewMatchQuery(q.Match.Field, q.Match.Token), nil
+
 	case *querypb.Query_Regexp:
 		return NewRegexpQuery(q.Regexp.Field, q.Regexp.Regexp)
 
+	case *querypb.Query_Prefix:
+		return NewPrefixQuery(q.Prefix.Field, q.Prefix.Prefix), nil
+
+	case *querypb.Query_Wildcard:
+		return NewWildcardQuery(q.Wildcard.Field, q.Wildcard.Wildcard)
+
+	case *querypb.Query_Range:
+		return newRangeQuery(q.Range.Field, q.Range.Min, q.Range.Max,
+			q.Range.MinInclusive, q.Range.MaxInclusive, q.Range.Numeric)
+
 	case *querypb.Query_Negation:
 		inner, err := unmarshal(q.Negation.Query)
 		if err != nil {
@@ -45,10 +45,30 @@ func TestMarshal(t *testing.T) {
 			name:  "term query",
 			query: NewTermQuery([]byte("fruit"), []byte("apple")),
 		},
+		{
+			name:  "match query",
+			query: NewMatchQuery([]byte("fruit"), []byte("apple")),
+		},
 		{
 			name:  "regexp query",
 			query: MustCreateRegexpQuery([]byte("fruit"), []byte(".*ple")),
 		},
+		{
+			name:  "prefix query",
+			query: NewPrefixQuery([]byte("fruit"), []byte("app")),
+		},
+		{
+			name:  "wildcard query",
+			query: MustCreateWildcardQuery([]byte("fruit"), []byte("a*l?")),
+		},
+		{
+			name:  "range query",
+			query: MustCreateRangeQuery([]byte("fruit"), []byte("apple"), []byte("banana"), true, false),
+		},
+		{
+			name:  "numeric range query",
+			query: MustCreateNumericRangeQuery([]byte("weight"), []byte("1.5"), nil, false, false),
+		},
 		{
 			name:  "negation query",
 			query: NewNegationQuery(NewTermQuery([]byte("fruit"), []byte("apple"))),
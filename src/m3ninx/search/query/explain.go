@@ -0,0 +1,107 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package query
+
+import (
+	"sort"
+
+	"github.com/m3db/m3/src/m3ninx/search"
+)
+
+// Explain returns a description of how the query is evaluated. The children of a
+// conjunction are listed in increasing order of relative cost with the negations last.
+// Against each segment the planner orders them by their cost estimated from the term
+// statistics of the segment instead, which is not reflected in the explanation.
+func Explain(q search.Query) (search.Explanation, error) {
+	s, err := q.Searcher()
+	if err != nil {
+		return search.Explanation{}, err
+	}
+
+	e := search.Explanation{
+		Query: q.String(),
+		Cost:  search.SearcherCost(s),
+	}
+
+	switch q := q.(type) {
+	case *ConjuctionQuery:
+		if len(q.queries) == 1 && len(q.negations) == 0 {
+			return Explain(q.queries[0])
+		}
+
+		queries, err := explainByCost(q.queries)
+		if err != nil {
+			return search.Explanation{}, err
+		}
+		negations, err := explainByCost(q.negations)
+		if err != nil {
+			return search.Explanation{}, err
+		}
+		for i := range negations {
+			negations[i].Negated = true
+		}
+		e.Children = append(queries, negations...)
+
+	case *DisjuctionQuery:
+		if len(q.queries) == 1 {
+			return Explain(q.queries[0])
+		}
+
+		children, err := explainAll(q.queries)
+		if err != nil {
+			return search.Explanation{}, err
+		}
+		e.Children = children
+
+	case *NegationQuery:
+		child, err := Explain(q.query)
+		if err != nil {
+			return search.Explanation{}, err
+		}
+		child.Negated = true
+		e.Children = []search.Explanation{child}
+	}
+
+	return e, nil
+}
+
+func explainAll(qs []search.Query) ([]search.Explanation, error) {
+	explanations := make([]search.Explanation, 0, len(qs))
+	for _, q := range qs {
+		e, err := Explain(q)
+		if err != nil {
+			return nil, err
+		}
+		explanations = append(explanations, e)
+	}
+	return explanations, nil
+}
+
+func explainByCost(qs []search.Query) ([]search.Explanation, error) {
+	explanations, err := explainAll(qs)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(explanations, func(i, j int) bool {
+		return explanations[i].Cost < explanations[j].Cost
+	})
+	return explanations, nil
+}
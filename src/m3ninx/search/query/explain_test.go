@@ -0,0 +1,114 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package query
+
+import (
+	"testing"
+
+	"github.com/m3db/m3/src/m3ninx/search"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExplain(t *testing.T) {
+	regexp, err := NewRegexpQuery([]byte("color"), []byte("r.*"))
+	require.NoError(t, err)
+
+	var (
+		term  = NewTermQuery([]byte("fruit"), []byte("apple"))
+		field = NewFieldQuery([]byte("size"))
+		other = NewTermQuery([]byte("fruit"), []byte("banana"))
+	)
+
+	tests := []struct {
+		name     string
+		query    search.Query
+		expected search.Explanation
+	}{
+		{
+			name:  "term",
+			query: term,
+			expected: search.Explanation{
+				Query: "term(fruit, apple)",
+				Cost:  search.LookupCost,
+			},
+		},
+		{
+			name:  "singular conjunction",
+			query: NewConjunctionQuery([]search.Query{field}),
+			expected: search.Explanation{
+				Query: "field(size)",
+				Cost:  search.ScanCost,
+			},
+		},
+		{
+			name: "conjunction ordered by cost with negations last",
+			query: NewConjunctionQuery([]search.Query{
+				regexp,
+				NewNegationQuery(other),
+				field,
+				term,
+			}),
+			expected: search.Explanation{
+				Query: "conjunction(regexp(color, r.*), field(size), term(fruit, apple)," +
+					"negation(term(fruit, banana)))",
+				Cost: search.AutomatonCost,
+				Children: []search.Explanation{
+					{Query: "term(fruit, apple)", Cost: search.LookupCost},
+					{Query: "field(size)", Cost: search.ScanCost},
+					{Query: "regexp(color, r.*)", Cost: search.AutomatonCost},
+					{Query: "term(fruit, banana)", Cost: search.LookupCost, Negated: true},
+				},
+			},
+		},
+		{
+			name:  "disjunction preserves order",
+			query: NewDisjunctionQuery([]search.Query{regexp, term}),
+			expected: search.Explanation{
+				Query: "disjunction(regexp(color, r.*), term(fruit, apple))",
+				Cost:  search.AutomatonCost,
+				Children: []search.Explanation{
+					{Query: "regexp(color, r.*)", Cost: search.AutomatonCost},
+					{Query: "term(fruit, apple)", Cost: search.LookupCost},
+				},
+			},
+		},
+		{
+			name:  "negation",
+			query: NewNegationQuery(field),
+			expected: search.Explanation{
+				Query: "negation(field(size))",
+				Cost:  search.ScanCost,
+				Children: []search.Explanation{
+					{Query: "field(size)", Cost: search.ScanCost, Negated: true},
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e, err := Explain(test.query)
+			require.NoError(t, err)
+			require.Equal(t, test.expected, e)
+		})
+	}
+}
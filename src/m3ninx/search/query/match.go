@@ -0,0 +1,82 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package query
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/m3db/m3/src/m3ninx/doc"
+	"github.com/m3db/m3/src/m3ninx/generated/proto/querypb"
+	"github.com/m3db/m3/src/m3ninx/search"
+	"github.com/m3db/m3/src/m3ninx/search/searcher"
+)
+
+// MatchQuery finds documents which contain the given token in the value of
+// a tokenized field.
+type MatchQuery struct {
+	field []byte
+	token []byte
+}
+
+// NewMatchQuery constructs a new MatchQuery for the given field and token.
+func NewMatchQuery(field, token []byte) search.Query {
+	return &MatchQuery{
+		field: field,
+		token: token,
+	}
+}
+
+// Searcher returns a searcher over the provided readers.
+func (q *MatchQuery) Searcher() (search.Searcher, error) {
+	return searcher.NewTermSearcher(doc.TokenizedFieldName(q.field), q.token), nil
+}
+
+// Equal reports whether q is equivalent to o.
+func (q *MatchQuery) Equal(o search.Query) bool {
+	o, ok := singular(o)
+	if !ok {
+		return false
+	}
+
+	inner, ok := o.(*MatchQuery)
+	if !ok {
+		return false
+	}
+
+	return bytes.Equal(q.field, inner.field) && bytes.Equal(q.token, inner.token)
+}
+
+// ToProto returns the Protobuf query struct corresponding to the match query.
+func (q *MatchQuery) ToProto() *querypb.Query {
+	match := querypb.MatchQuery{
+		Field: q.field,
+		Token: q.token,
+	}
+
+	return &querypb.Query{
+		Query: &querypb.Query_Match{Match: &match},
+	}
+}
+
+func (q *MatchQuery) String() string {
+	return fmt.Sprintf("match(%s, %s)", q.field, q.token)
+}
@@ -0,0 +1,161 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package query
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/m3db/m3/src/m3ninx/generated/proto/querypb"
+	"github.com/m3db/m3/src/m3ninx/index"
+	"github.com/m3db/m3/src/m3ninx/search"
+	"github.com/m3db/m3/src/m3ninx/search/searcher"
+)
+
+// RangeQuery finds documents which have a term between the given bounds. An empty
+// bound is unbounded. Terms are compared lexicographically unless the query is
+// numeric, in which case terms which are not numbers never match.
+type RangeQuery struct {
+	field        []byte
+	min, max     []byte
+	minInclusive bool
+	maxInclusive bool
+	numeric      bool
+	matcher      index.TermsMatcher
+}
+
+// NewRangeQuery constructs a new query for terms lexicographically between min and max.
+func NewRangeQuery(
+	field, min, max []byte,
+	minInclusive, maxInclusive bool,
+) (search.Query, error) {
+	return newRangeQuery(field, min, max, minInclusive, maxInclusive, false)
+}
+
+// NewNumericRangeQuery constructs a new query for terms which are numbers between
+// min and max.
+func NewNumericRangeQuery(
+	field, min, max []byte,
+	minInclusive, maxInclusive bool,
+) (search.Query, error) {
+	return newRangeQuery(field, min, max, minInclusive, maxInclusive, true)
+}
+
+func newRangeQuery(
+	field, min, max []byte,
+	minInclusive, maxInclusive bool,
+	numeric bool,
+) (search.Query, error) {
+	matcher, err := index.NewRangeTermsMatcher(min, max, minInclusive, maxInclusive, numeric)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RangeQuery{
+		field:        field,
+		min:          min,
+		max:          max,
+		minInclusive: minInclusive,
+		maxInclusive: maxInclusive,
+		numeric:      numeric,
+		matcher:      matcher,
+	}, nil
+}
+
+// MustCreateRangeQuery is like NewRangeQuery but panics if the query cannot be created.
+func MustCreateRangeQuery(
+	field, min, max []byte,
+	minInclusive, maxInclusive bool,
+) search.Query {
+	q, err := NewRangeQuery(field, min, max, minInclusive, maxInclusive)
+	if err != nil {
+		panic(err)
+	}
+	return q
+}
+
+// MustCreateNumericRangeQuery is like NewNumericRangeQuery but panics if the query
+// cannot be created.
+func MustCreateNumericRangeQuery(
+	field, min, max []byte,
+	minInclusive, maxInclusive bool,
+) search.Query {
+	q, err := NewNumericRangeQuery(field, min, max, minInclusive, maxInclusive)
+	if err != nil {
+		panic(err)
+	}
+	return q
+}
+
+// Searcher returns a searcher over the provided readers.
+func (q *RangeQuery) Searcher() (search.Searcher, error) {
+	return searcher.NewTermsMatcherSearcher(q.field, q.matcher), nil
+}
+
+// Equal reports whether q is equivalent to o.
+func (q *RangeQuery) Equal(o search.Query) bool {
+	o, ok := singular(o)
+	if !ok {
+		return false
+	}
+
+	inner, ok := o.(*RangeQuery)
+	if !ok {
+		return false
+	}
+
+	return bytes.Equal(q.field, inner.field) &&
+		bytes.Equal(q.min, inner.min) &&
+		bytes.Equal(q.max, inner.max) &&
+		q.minInclusive == inner.minInclusive &&
+		q.maxInclusive == inner.maxInclusive &&
+		q.numeric == inner.numeric
+}
+
+// ToProto returns the Protobuf query struct corresponding to the range query.
+func (q *RangeQuery) ToProto() *querypb.Query {
+	rng := querypb.RangeQuery{
+		Field:        q.field,
+		Min:          q.min,
+		Max:          q.max,
+		MinInclusive: q.minInclusive,
+		MaxInclusive: q.maxInclusive,
+		Numeric:      q.numeric,
+	}
+
+	return &querypb.Query{
+		Query: &querypb.Query_Range{Range: &rng},
+	}
+}
+
+func (q *RangeQuery) String() string {
+	name, lower, upper := "range", "(", ")"
+	if q.numeric {
+		name = "numeric_range"
+	}
+	if q.minInclusive {
+		lower = "["
+	}
+	if q.maxInclusive {
+		upper = "]"
+	}
+	return fmt.Sprintf("%s(%s, %s%s, %s%s)", name, q.field, lower, q.min, q.max, upper)
+}
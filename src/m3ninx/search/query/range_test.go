@@ -0,0 +1,137 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package query
+
+import (
+	"testing"
+
+	"github.com/m3db/m3/src/m3ninx/search"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRangeQuery(t *testing.T) {
+	tests := []struct {
+		name      string
+		min, max  []byte
+		numeric   bool
+		expectErr bool
+	}{
+		{
+			name: "lexicographic range should not return an error",
+			min:  []byte("apple"),
+			max:  []byte("banana"),
+		},
+		{
+			name:    "numeric range should not return an error",
+			min:     []byte("1"),
+			max:     []byte("2.5"),
+			numeric: true,
+		},
+		{
+			name:    "half open numeric range should not return an error",
+			max:     []byte("-3"),
+			numeric: true,
+		},
+		{
+			name:      "invalid numeric bound should return an error",
+			min:       []byte("apple"),
+			numeric:   true,
+			expectErr: true,
+		},
+		{
+			name:      "no bounds should return an error",
+			expectErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				q   search.Query
+				err error
+			)
+			if test.numeric {
+				q, err = NewNumericRangeQuery([]byte("fruit"), test.min, test.max, true, true)
+			} else {
+				q, err = NewRangeQuery([]byte("fruit"), test.min, test.max, true, true)
+			}
+
+			if test.expectErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			_, err = q.Searcher()
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestRangeQueryString(t *testing.T) {
+	q := MustCreateRangeQuery([]byte("fruit"), []byte("a"), []byte("b"), true, false)
+	require.Equal(t, "range(fruit, [a, b))", q.String())
+
+	q = MustCreateNumericRangeQuery([]byte("weight"), []byte("1"), []byte("2"), false, true)
+	require.Equal(t, "numeric_range(weight, (1, 2])", q.String())
+}
+
+func TestRangeQueryEqual(t *testing.T) {
+	tests := []struct {
+		name        string
+		left, right search.Query
+		expected    bool
+	}{
+		{
+			name:     "same field and bounds",
+			left:     MustCreateRangeQuery([]byte("fruit"), []byte("a"), []byte("b"), true, false),
+			right:    MustCreateRangeQuery([]byte("fruit"), []byte("a"), []byte("b"), true, false),
+			expected: true,
+		},
+		{
+			name: "singular conjunction query",
+			left: MustCreateRangeQuery([]byte("fruit"), []byte("a"), []byte("b"), true, false),
+			right: NewConjunctionQuery([]search.Query{
+				MustCreateRangeQuery([]byte("fruit"), []byte("a"), []byte("b"), true, false),
+			}),
+			expected: true,
+		},
+		{
+			name:     "different inclusivity",
+			left:     MustCreateRangeQuery([]byte("fruit"), []byte("a"), []byte("b"), true, false),
+			right:    MustCreateRangeQuery([]byte("fruit"), []byte("a"), []byte("b"), true, true),
+			expected: false,
+		},
+		{
+			name:     "numeric and lexicographic",
+			left:     MustCreateRangeQuery([]byte("fruit"), []byte("1"), []byte("2"), true, true),
+			right:    MustCreateNumericRangeQuery([]byte("fruit"), []byte("1"), []byte("2"), true, true),
+			expected: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.expected, test.left.Equal(test.right))
+		})
+	}
+}
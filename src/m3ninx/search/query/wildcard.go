@@ -0,0 +1,98 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package query
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/m3db/m3/src/m3ninx/generated/proto/querypb"
+	"github.com/m3db/m3/src/m3ninx/index"
+	"github.com/m3db/m3/src/m3ninx/search"
+	"github.com/m3db/m3/src/m3ninx/search/searcher"
+)
+
+// WildcardQuery finds documents which have a term matching the given wildcard
+// pattern, where '*' matches zero or more characters and '?' matches exactly one.
+type WildcardQuery struct {
+	field    []byte
+	wildcard []byte
+	matcher  index.TermsMatcher
+}
+
+// NewWildcardQuery constructs a new query for the given wildcard pattern.
+func NewWildcardQuery(field, wildcard []byte) (search.Query, error) {
+	matcher, err := index.NewWildcardTermsMatcher(wildcard)
+	if err != nil {
+		return nil, err
+	}
+
+	return &WildcardQuery{
+		field:    field,
+		wildcard: wildcard,
+		matcher:  matcher,
+	}, nil
+}
+
+// MustCreateWildcardQuery is like NewWildcardQuery but panics if the query cannot be created.
+func MustCreateWildcardQuery(field, wildcard []byte) search.Query {
+	q, err := NewWildcardQuery(field, wildcard)
+	if err != nil {
+		panic(err)
+	}
+	return q
+}
+
+// Searcher returns a searcher over the provided readers.
+func (q *WildcardQuery) Searcher() (search.Searcher, error) {
+	return searcher.NewTermsMatcherSearcher(q.field, q.matcher), nil
+}
+
+// Equal reports whether q is equivalent to o.
+func (q *WildcardQuery) Equal(o search.Query) bool {
+	o, ok := singular(o)
+	if !ok {
+		return false
+	}
+
+	inner, ok := o.(*WildcardQuery)
+	if !ok {
+		return false
+	}
+
+	return bytes.Equal(q.field, inner.field) && bytes.Equal(q.wildcard, inner.wildcard)
+}
+
+// ToProto returns the Protobuf query struct corresponding to the wildcard query.
+func (q *WildcardQuery) ToProto() *querypb.Query {
+	wildcard := querypb.WildcardQuery{
+		Field:    q.field,
+		Wildcard: q.wildcard,
+	}
+
+	return &querypb.Query{
+		Query: &querypb.Query_Wildcard{Wildcard: &wildcard},
+	}
+}
+
+func (q *WildcardQuery) String() string {
+	return fmt.Sprintf("wildcard(%s, %s)", q.field, q.wildcard)
+}
@@ -73,6 +73,21 @@ func (mr *MockExecutorMockRecorder) Execute(q interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Execute", reflect.TypeOf((*MockExecutor)(nil).Execute), q)
 }
 
+// ExecuteAfter mocks base method
+func (m *MockExecutor) ExecuteAfter(q Query, after []byte) (doc.Iterator, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExecuteAfter", q, after)
+	ret0, _ := ret[0].(doc.Iterator)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExecuteAfter indicates an expected call of ExecuteAfter
+func (mr *MockExecutorMockRecorder) ExecuteAfter(q, after interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteAfter", reflect.TypeOf((*MockExecutor)(nil).ExecuteAfter), q, after)
+}
+
 // Close mocks base method
 func (m *MockExecutor) Close() error {
 	m.ctrl.T.Helper()
@@ -36,3 +36,11 @@ func NewAllSearcher() search.Searcher {
 func (s *all) Search(r index.Reader) (postings.List, error) {
 	return r.MatchAll()
 }
+
+func (s *all) Cost() search.Cost {
+	return search.LookupCost
+}
+
+func (s *all) EstimateCost(r index.StatsReader) (int, error) {
+	return r.NumDocs()
+}
@@ -33,52 +33,60 @@ type conjunctionSearcher struct {
 
 // NewConjunctionSearcher returns a new Searcher which matches documents which match each
 // of the given searchers and none of the negations.
+//
+// The searchers are evaluated in increasing order of their estimated cost against the
+// segment being searched, which is estimated from the term statistics of the segment,
+// so that selective searchers short-circuit the evaluation of expensive searchers once
+// the intersection is empty. The negations are evaluated last, also in increasing order
+// of estimated cost. Segments without term statistics are searched in increasing order
+// of the relative cost of the searchers.
 func NewConjunctionSearcher(searchers, negations search.Searchers) (search.Searcher, error) {
 	if len(searchers) == 0 {
 		return nil, errEmptySearchers
 	}
 
 	return &conjunctionSearcher{
-		searchers: searchers,
-		negations: negations,
+		searchers: search.SortSearchersByCost(searchers),
+		negations: search.SortSearchersByCost(negations),
 	}, nil
 }
 
 func (s *conjunctionSearcher) Search(r index.Reader) (postings.List, error) {
+	searchers, negations, err := s.plan(r)
+	if err != nil {
+		return nil, err
+	}
+
 	var pl postings.MutableList
-	for _, sr := range s.searchers {
+	for _, sr := range searchers {
 		curr, err := sr.Search(r)
 		if err != nil {
 			return nil, err
 		}
 
-		// TODO: Sort the iterators so that we take the intersection in order of increasing size.
 		if pl == nil {
 			pl = curr.Clone()
-		} else {
-			if err := pl.Intersect(curr); err != nil {
-				return nil, err
-			}
+		} else if err := pl.Intersect(curr); err != nil {
+			return nil, err
 		}
 
-		// We can break early if the interescted postings list is ever empty.
+		// We can break early if the intersected postings list is ever empty.
 		if pl.IsEmpty() {
-			break
+			return pl, nil
 		}
 	}
 
-	for _, sr := range s.negations {
+	for _, sr := range negations {
 		curr, err := sr.Search(r)
 		if err != nil {
 			return nil, err
 		}
 
-		// TODO: Sort the iterators so that we take the set differences in order of decreasing size.
 		if err := pl.Difference(curr); err != nil {
 			return nil, err
 		}
 
-		// We can break early if the interescted postings list is ever empty.
+		// We can break early if the postings list is ever empty.
 		if pl.IsEmpty() {
 			break
 		}
@@ -86,3 +94,46 @@ func (s *conjunctionSearcher) Search(r index.Reader) (postings.List, error) {
 
 	return pl, nil
 }
+
+// plan returns the order in which the searchers and negations are evaluated against
+// the segment of the reader.
+func (s *conjunctionSearcher) plan(r index.Reader) (search.Searchers, search.Searchers, error) {
+	sr, ok := r.(index.StatsReader)
+	if !ok {
+		return s.searchers, s.negations, nil
+	}
+
+	searchers, err := search.SortSearchersByEstimatedCost(s.searchers, sr)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	negations, err := search.SortSearchersByEstimatedCost(s.negations, sr)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return searchers, negations, nil
+}
+
+func (s *conjunctionSearcher) Cost() search.Cost {
+	cost := search.MaxSearchersCost(s.searchers)
+	if negationsCost := search.MaxSearchersCost(s.negations); negationsCost > cost {
+		return negationsCost
+	}
+	return cost
+}
+
+func (s *conjunctionSearcher) EstimateCost(r index.StatsReader) (int, error) {
+	cost, err := search.EstimateSearchersCost(s.searchers, r)
+	if err != nil {
+		return 0, err
+	}
+
+	negationsCost, err := search.EstimateSearchersCost(s.negations, r)
+	if err != nil {
+		return 0, err
+	}
+
+	return search.AddEstimatedCosts(cost, negationsCost), nil
+}
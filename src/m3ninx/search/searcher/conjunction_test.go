@@ -102,6 +102,124 @@ func TestConjunctionSearcher(t *testing.T) {
 	require.True(t, pl.Equal(expected))
 }
 
+func TestConjunctionSearcherEvaluatesByCost(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	reader := index.NewMockReader(mockCtrl)
+
+	var (
+		field   = []byte("fruit")
+		term    = []byte("apple")
+		regexp  = []byte(".*pple")
+		compile = index.CompiledRegex{}
+	)
+
+	// The regexp searcher is more expensive than the term searcher so it is
+	// never evaluated once the term searcher returns an empty postings list.
+	reader.EXPECT().MatchTerm(field, term).Return(roaring.NewPostingsList(), nil)
+
+	s, err := NewConjunctionSearcher(search.Searchers{
+		NewRegexpSearcher(regexp, compile),
+		NewTermSearcher(field, term),
+	}, nil)
+	require.NoError(t, err)
+	require.Equal(t, search.AutomatonCost, search.SearcherCost(s))
+
+	pl, err := s.Search(reader)
+	require.NoError(t, err)
+	require.True(t, pl.IsEmpty())
+}
+
+type statsReader struct {
+	*index.MockReader
+	*index.MockStatsReader
+}
+
+func TestConjunctionSearcherEvaluatesByEstimatedCost(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	reader := statsReader{
+		MockReader:      index.NewMockReader(mockCtrl),
+		MockStatsReader: index.NewMockStatsReader(mockCtrl),
+	}
+
+	var (
+		fruit = []byte("fruit")
+		apple = []byte("apple")
+		shape = []byte("shape")
+		round = []byte("round")
+		color = []byte("color")
+	)
+
+	reader.MockStatsReader.EXPECT().TermCardinality(fruit, apple).Return(100, nil)
+	reader.MockStatsReader.EXPECT().TermCardinality(shape, round).Return(2, nil)
+	reader.MockStatsReader.EXPECT().NumTerms(color).Return(3, nil)
+	reader.MockStatsReader.EXPECT().NumDocs().Return(1000, nil)
+
+	applePL := roaring.NewPostingsList()
+	require.NoError(t, applePL.Insert(postings.ID(42)))
+	roundPL := roaring.NewPostingsList()
+	require.NoError(t, roundPL.Insert(postings.ID(43)))
+
+	// The rarest term is evaluated first, and the regexp searcher is never
+	// evaluated since the intersection of the terms is empty.
+	gomock.InOrder(
+		reader.MockReader.EXPECT().MatchTerm(shape, round).Return(roundPL, nil),
+		reader.MockReader.EXPECT().MatchTerm(fruit, apple).Return(applePL, nil),
+	)
+
+	s, err := NewConjunctionSearcher(search.Searchers{
+		NewRegexpSearcher(color, index.CompiledRegex{}),
+		NewTermSearcher(fruit, apple),
+		NewTermSearcher(shape, round),
+	}, nil)
+	require.NoError(t, err)
+
+	pl, err := s.Search(reader)
+	require.NoError(t, err)
+	require.True(t, pl.IsEmpty())
+}
+
+func TestConjunctionSearcherEvaluatesNegationsLast(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	reader := index.NewMockReader(mockCtrl)
+
+	var (
+		field = []byte("fruit")
+		first = []byte("apple")
+		other = []byte("banana")
+	)
+
+	firstPL := roaring.NewPostingsList()
+	require.NoError(t, firstPL.Insert(postings.ID(42)))
+	otherPL := roaring.NewPostingsList()
+	require.NoError(t, otherPL.Insert(postings.ID(42)))
+
+	// The cheapest negation removes every document so the regexp negation is
+	// never evaluated.
+	gomock.InOrder(
+		reader.EXPECT().MatchTerm(field, first).Return(firstPL, nil),
+		reader.EXPECT().MatchTerm(field, other).Return(otherPL, nil),
+	)
+
+	s, err := NewConjunctionSearcher(
+		search.Searchers{NewTermSearcher(field, first)},
+		search.Searchers{
+			NewRegexpSearcher(field, index.CompiledRegex{}),
+			NewTermSearcher(field, other),
+		},
+	)
+	require.NoError(t, err)
+
+	pl, err := s.Search(reader)
+	require.NoError(t, err)
+	require.True(t, pl.IsEmpty())
+}
+
 func TestConjunctionSearcherError(t *testing.T) {
 	tests := []struct {
 		name      string
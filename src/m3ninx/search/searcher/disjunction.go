@@ -59,3 +59,11 @@ func (s *disjunctionSearcher) Search(r index.Reader) (postings.List, error) {
 	}
 	return pl, nil
 }
+
+func (s *disjunctionSearcher) Cost() search.Cost {
+	return search.MaxSearchersCost(s.searchers)
+}
+
+func (s *disjunctionSearcher) EstimateCost(r index.StatsReader) (int, error) {
+	return search.EstimateSearchersCost(s.searchers, r)
+}
@@ -41,3 +41,11 @@ func NewEmptySearcher() search.Searcher {
 func (s *emptySearcher) Search(r index.Reader) (postings.List, error) {
 	return s.postings, nil
 }
+
+func (s *emptySearcher) Cost() search.Cost {
+	return search.LookupCost
+}
+
+func (s *emptySearcher) EstimateCost(index.StatsReader) (int, error) {
+	return 0, nil
+}
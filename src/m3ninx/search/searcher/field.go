@@ -40,3 +40,17 @@ func NewFieldSearcher(field []byte) (search.Searcher, error) {
 func (s *fieldSearcher) Search(r index.Reader) (postings.List, error) {
 	return r.MatchField(s.field)
 }
+
+func (s *fieldSearcher) Cost() search.Cost {
+	return search.ScanCost
+}
+
+func (s *fieldSearcher) EstimateCost(r index.StatsReader) (int, error) {
+	numTerms, err := r.NumTerms(s.field)
+	if err != nil || numTerms == 0 {
+		return 0, err
+	}
+
+	// NB: the postings list of a field is bounded by the number of documents.
+	return r.NumDocs()
+}
@@ -52,3 +52,21 @@ func (s *negationSearcher) Search(r index.Reader) (postings.List, error) {
 	pl.Difference(sPl)
 	return pl, nil
 }
+
+func (s *negationSearcher) Cost() search.Cost {
+	return search.SearcherCost(s.searcher)
+}
+
+func (s *negationSearcher) EstimateCost(r index.StatsReader) (int, error) {
+	numDocs, err := r.NumDocs()
+	if err != nil {
+		return 0, err
+	}
+
+	cost, err := search.EstimateSearcherCost(s.searcher, r)
+	if err != nil {
+		return 0, err
+	}
+
+	return search.AddEstimatedCosts(numDocs, cost), nil
+}
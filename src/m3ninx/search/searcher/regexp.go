@@ -43,3 +43,28 @@ func NewRegexpSearcher(field []byte, compiled index.CompiledRegex) search.Search
 func (s *regexpSearcher) Search(r index.Reader) (postings.List, error) {
 	return r.MatchRegexp(s.field, s.compiled)
 }
+
+func (s *regexpSearcher) Cost() search.Cost {
+	return search.AutomatonCost
+}
+
+func (s *regexpSearcher) EstimateCost(r index.StatsReader) (int, error) {
+	return estimateTermsScanCost(r, s.field)
+}
+
+// estimateTermsScanCost estimates the cost of matching each term of the field and
+// unioning the postings lists of the matched terms, which are bounded by the number
+// of documents.
+func estimateTermsScanCost(r index.StatsReader, field []byte) (int, error) {
+	numTerms, err := r.NumTerms(field)
+	if err != nil || numTerms == 0 {
+		return 0, err
+	}
+
+	numDocs, err := r.NumDocs()
+	if err != nil {
+		return 0, err
+	}
+
+	return search.AddEstimatedCosts(numTerms, numDocs), nil
+}
@@ -41,3 +41,11 @@ func NewTermSearcher(field, term []byte) search.Searcher {
 func (s *termSearcher) Search(r index.Reader) (postings.List, error) {
 	return r.MatchTerm(s.field, s.term)
 }
+
+func (s *termSearcher) Cost() search.Cost {
+	return search.LookupCost
+}
+
+func (s *termSearcher) EstimateCost(r index.StatsReader) (int, error) {
+	return r.TermCardinality(s.field, s.term)
+}
@@ -0,0 +1,53 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package searcher
+
+import (
+	"github.com/m3db/m3/src/m3ninx/index"
+	"github.com/m3db/m3/src/m3ninx/postings"
+	"github.com/m3db/m3/src/m3ninx/search"
+)
+
+type termsMatcherSearcher struct {
+	field   []byte
+	matcher index.TermsMatcher
+}
+
+// NewTermsMatcherSearcher returns a new searcher for finding documents which have a
+// term of the given field matched by the terms matcher, e.g. a prefix, wildcard or range.
+func NewTermsMatcherSearcher(field []byte, matcher index.TermsMatcher) search.Searcher {
+	return &termsMatcherSearcher{
+		field:   field,
+		matcher: matcher,
+	}
+}
+
+func (s *termsMatcherSearcher) Search(r index.Reader) (postings.List, error) {
+	return r.MatchTerms(s.field, s.matcher)
+}
+
+func (s *termsMatcherSearcher) Cost() search.Cost {
+	return search.ScanCost
+}
+
+func (s *termsMatcherSearcher) EstimateCost(r index.StatsReader) (int, error) {
+	return estimateTermsScanCost(r, s.field)
+}
@@ -34,6 +34,11 @@ type Executor interface {
 	// Execute executes a query over the Executor's snapshot.
 	Execute(q Query) (doc.Iterator, error)
 
+	// ExecuteAfter executes a query over the Executor's snapshot, returning
+	// the matching documents with IDs strictly after the given ID in increasing
+	// order of their IDs. An empty ID returns every matching document.
+	ExecuteAfter(q Query, after []byte) (doc.Iterator, error)
+
 	// Close closes the iterator.
 	Close() error
 }
@@ -0,0 +1,218 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/storage/index"
+	"github.com/m3db/m3/src/query/api/v1/options"
+	"github.com/m3db/m3/src/query/storage/m3"
+	"github.com/m3db/m3/src/query/util"
+	"github.com/m3db/m3/src/query/util/logging"
+	"github.com/m3db/m3/src/x/instrument"
+	xhttp "github.com/m3db/m3/src/x/net/http"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// IndexCardinalityURL is the url to get cardinality statistics of the index.
+	IndexCardinalityURL = RoutePrefixV1 + "/index/cardinality"
+
+	// IndexCardinalityHTTPMethod is the HTTP method used with this resource.
+	IndexCardinalityHTTPMethod = http.MethodGet
+
+	defaultIndexCardinalityLimit = 10
+	defaultIndexCardinalityRange = time.Hour
+)
+
+var (
+	errNoLocalClusters = errors.New("no local clusters configured")
+)
+
+// IndexCardinalityHandler reports the tag names with the most distinct
+// values and the tags with the most series of a namespace's index.
+type IndexCardinalityHandler struct {
+	clusters       m3.Clusters
+	instrumentOpts instrument.Options
+	nowFn          func() time.Time
+}
+
+// IndexCardinalityResponse is the response of the index cardinality endpoint.
+type IndexCardinalityResponse struct {
+	Namespace string                   `json:"namespace"`
+	NumSeries int64                    `json:"numSeries"`
+	TagNames  []TagNameCardinalityJSON `json:"tagNames"`
+	Tags      []TagCardinalityJSON     `json:"tags"`
+}
+
+// TagNameCardinalityJSON is the cardinality of a tag name.
+type TagNameCardinalityJSON struct {
+	Name      string `json:"name"`
+	NumValues int64  `json:"numValues"`
+	NumSeries int64  `json:"numSeries"`
+}
+
+// TagCardinalityJSON is the cardinality of a tag name and value pair.
+type TagCardinalityJSON struct {
+	Name      string `json:"name"`
+	Value     string `json:"value"`
+	NumSeries int64  `json:"numSeries"`
+}
+
+type indexCardinalityRequest struct {
+	namespace string
+	opts      index.CardinalityOptions
+}
+
+// NewIndexCardinalityHandler returns a new instance of handler.
+func NewIndexCardinalityHandler(opts options.HandlerOptions) http.Handler {
+	return &IndexCardinalityHandler{
+		clusters:       opts.Clusters(),
+		instrumentOpts: opts.InstrumentOpts(),
+		nowFn:          time.Now,
+	}
+}
+
+func (h *IndexCardinalityHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := logging.WithContext(r.Context(), h.instrumentOpts)
+
+	req, parseErr := h.parseRequest(r)
+	if parseErr != nil {
+		logger.Error("unable to parse request", zap.Error(parseErr.Inner()))
+		xhttp.Error(w, parseErr.Inner(), parseErr.Code())
+		return
+	}
+
+	ns, err := clusterNamespace(h.clusters, req.namespace)
+	if err != nil {
+		logger.Error("unable to find namespace", zap.Error(err))
+		xhttp.Error(w, err, http.StatusBadRequest)
+		return
+	}
+
+	results, err := ns.Session().IndexCardinality(ns.NamespaceID(), req.opts)
+	if err != nil {
+		logger.Error("unable to fetch index cardinality", zap.Error(err))
+		xhttp.Error(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	xhttp.WriteJSONResponse(w, newIndexCardinalityResponse(
+		ns.NamespaceID().String(), results), logger)
+}
+
+func (h *IndexCardinalityHandler) parseRequest(
+	r *http.Request,
+) (indexCardinalityRequest, *xhttp.ParseError) {
+	if err := r.ParseForm(); err != nil {
+		return indexCardinalityRequest{}, xhttp.NewParseError(err, http.StatusBadRequest)
+	}
+
+	var (
+		now = h.nowFn()
+		req = indexCardinalityRequest{
+			namespace: r.FormValue("namespace"),
+			opts: index.CardinalityOptions{
+				StartInclusive: now.Add(-defaultIndexCardinalityRange),
+				EndExclusive:   now,
+				Limit:          defaultIndexCardinalityLimit,
+			},
+		}
+		err error
+	)
+	if str := r.FormValue("start"); str != "" {
+		req.opts.StartInclusive, err = util.ParseTimeString(str)
+		if err != nil {
+			return indexCardinalityRequest{}, xhttp.NewParseError(err, http.StatusBadRequest)
+		}
+	}
+	if str := r.FormValue("end"); str != "" {
+		req.opts.EndExclusive, err = util.ParseTimeString(str)
+		if err != nil {
+			return indexCardinalityRequest{}, xhttp.NewParseError(err, http.StatusBadRequest)
+		}
+	}
+	if !req.opts.StartInclusive.Before(req.opts.EndExclusive) {
+		return indexCardinalityRequest{}, xhttp.NewParseError(
+			errors.New("start must be before end"), http.StatusBadRequest)
+	}
+	if str := r.FormValue("limit"); str != "" {
+		req.opts.Limit, err = strconv.Atoi(str)
+		if err != nil {
+			return indexCardinalityRequest{}, xhttp.NewParseError(err, http.StatusBadRequest)
+		}
+	}
+	for _, tag := range r.Form["tag"] {
+		req.opts.FieldFilter = append(req.opts.FieldFilter, []byte(tag))
+	}
+
+	return req, nil
+}
+
+// clusterNamespace returns the named cluster namespace, or the unaggregated
+// namespace if name is empty.
+func clusterNamespace(clusters m3.Clusters, name string) (m3.ClusterNamespace, error) {
+	if clusters == nil {
+		return nil, errNoLocalClusters
+	}
+	if name == "" {
+		return clusters.UnaggregatedClusterNamespace(), nil
+	}
+	for _, ns := range clusters.ClusterNamespaces() {
+		if ns.NamespaceID().String() == name {
+			return ns, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown namespace: %s", name)
+}
+
+func newIndexCardinalityResponse(
+	namespace string,
+	results index.CardinalityResults,
+) IndexCardinalityResponse {
+	resp := IndexCardinalityResponse{
+		Namespace: namespace,
+		NumSeries: results.NumSeries,
+		TagNames:  make([]TagNameCardinalityJSON, 0, len(results.Fields)),
+		Tags:      make([]TagCardinalityJSON, 0, len(results.Terms)),
+	}
+	for _, f := range results.Fields {
+		resp.TagNames = append(resp.TagNames, TagNameCardinalityJSON{
+			Name:      string(f.Field),
+			NumValues: f.NumValues,
+			NumSeries: f.NumSeries,
+		})
+	}
+	for _, t := range results.Terms {
+		resp.Tags = append(resp.Tags, TagCardinalityJSON{
+			Name:      string(t.Field),
+			Value:     string(t.Term),
+			NumSeries: t.NumSeries,
+		})
+	}
+	return resp
+}
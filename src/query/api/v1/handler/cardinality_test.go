@@ -0,0 +1,107 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/client"
+	"github.com/m3db/m3/src/dbnode/storage/index"
+	"github.com/m3db/m3/src/query/api/v1/options"
+	"github.com/m3db/m3/src/query/storage/m3"
+	"github.com/m3db/m3/src/x/ident"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndexCardinalityHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	session := client.NewMockSession(ctrl)
+	clusters, err := m3.NewClusters(m3.UnaggregatedClusterNamespaceDefinition{
+		NamespaceID: ident.StringID("default"),
+		Session:     session,
+		Retention:   48 * time.Hour,
+	})
+	require.NoError(t, err)
+
+	start := time.Unix(1500000000, 0)
+	end := start.Add(time.Hour)
+	session.EXPECT().IndexCardinality(ident.NewIDMatcher("default"),
+		index.CardinalityOptions{
+			StartInclusive: start,
+			EndExclusive:   end,
+			FieldFilter:    index.AggregateFieldFilter{[]byte("city")},
+			Limit:          5,
+		}).Return(index.CardinalityResults{
+		NumSeries: 3,
+		Fields: []index.FieldCardinality{
+			{Field: []byte("city"), NumValues: 2, NumSeries: 3},
+		},
+		Terms: []index.TermCardinality{
+			{Field: []byte("city"), Term: []byte("nyc"), NumSeries: 2},
+			{Field: []byte("city"), Term: []byte("sf"), NumSeries: 1},
+		},
+	}, nil)
+
+	h := NewIndexCardinalityHandler(options.EmptyHandlerOptions().
+		SetClusters(clusters))
+
+	req := httptest.NewRequest(IndexCardinalityHTTPMethod, IndexCardinalityURL+
+		"?start=1500000000&end=1500003600&tag=city&limit=5", nil)
+	recorder := httptest.NewRecorder()
+	h.ServeHTTP(recorder, req)
+	require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+
+	var resp IndexCardinalityResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+	require.Equal(t, IndexCardinalityResponse{
+		Namespace: "default",
+		NumSeries: 3,
+		TagNames: []TagNameCardinalityJSON{
+			{Name: "city", NumValues: 2, NumSeries: 3},
+		},
+		Tags: []TagCardinalityJSON{
+			{Name: "city", Value: "nyc", NumSeries: 2},
+			{Name: "city", Value: "sf", NumSeries: 1},
+		},
+	}, resp)
+
+	// Unknown namespaces are rejected.
+	req = httptest.NewRequest(IndexCardinalityHTTPMethod,
+		IndexCardinalityURL+"?namespace=unknown", nil)
+	recorder = httptest.NewRecorder()
+	h.ServeHTTP(recorder, req)
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+
+	// Start must be before end.
+	req = httptest.NewRequest(IndexCardinalityHTTPMethod, IndexCardinalityURL+
+		"?start=1500003600&end=1500000000", nil)
+	recorder = httptest.NewRecorder()
+	h.ServeHTTP(recorder, req)
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+}
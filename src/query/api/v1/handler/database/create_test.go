@@ -171,7 +171,10 @@ func testLocalType(t *testing.T, providedType string, placementExists bool) {
 							"blockSizeNanos": "3600000000000"
 						},
 						"schemaOptions": null,
-						"coldWritesEnabled": false
+						"coldWritesEnabled": false,
+						"aggregationOptions": null,
+						"coldTierOptions": null,
+						"writeClasses": []
 					}
 				}
 			}
@@ -329,7 +332,10 @@ func TestLocalTypeWithNumShards(t *testing.T) {
 							"blockSizeNanos": "3600000000000"
 						},
 						"schemaOptions": null,
-						"coldWritesEnabled": false
+						"coldWritesEnabled": false,
+						"aggregationOptions": null,
+						"coldTierOptions": null,
+						"writeClasses": []
 					}
 				}
 			}
@@ -439,7 +445,10 @@ func TestLocalWithBlockSizeNanos(t *testing.T) {
 							"blockSizeNanos": "10800000000000"
 						},
 						"schemaOptions": null,
-						"coldWritesEnabled": false
+						"coldWritesEnabled": false,
+						"aggregationOptions": null,
+						"coldTierOptions": null,
+						"writeClasses": []
 					}
 				}
 			}
@@ -553,7 +562,10 @@ func TestLocalWithBlockSizeExpectedSeriesDatapointsPerHour(t *testing.T) {
 							"blockSizeNanos": "%d"
 						},
 						"schemaOptions": null,
-						"coldWritesEnabled": false
+						"coldWritesEnabled": false,
+						"aggregationOptions": null,
+						"coldTierOptions": null,
+						"writeClasses": []
 					}
 				}
 			}
@@ -801,7 +813,10 @@ func testClusterTypeHosts(t *testing.T, placementExists bool) {
 							"blockSizeNanos": "3600000000000"
 						},
 						"schemaOptions": null,
-						"coldWritesEnabled": false
+						"coldWritesEnabled": false,
+						"aggregationOptions": null,
+						"coldTierOptions": null,
+						"writeClasses": []
 					}
 				}
 			}
@@ -933,7 +948,10 @@ func TestClusterTypeHostsWithIsolationGroup(t *testing.T) {
 							"blockSizeNanos": "3600000000000"
 						},
 						"schemaOptions": null,
-						"coldWritesEnabled": false
+						"coldWritesEnabled": false,
+						"aggregationOptions": null,
+						"coldTierOptions": null,
+						"writeClasses": []
 					}
 				}
 			}
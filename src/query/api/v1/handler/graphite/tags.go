@@ -0,0 +1,353 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package graphite
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/m3db/m3/src/query/api/v1/handler"
+	"github.com/m3db/m3/src/query/api/v1/handler/prometheus/handleroptions"
+	"github.com/m3db/m3/src/query/api/v1/options"
+	"github.com/m3db/m3/src/query/graphite/graphite"
+	graphiteStorage "github.com/m3db/m3/src/query/graphite/storage"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/storage"
+	"github.com/m3db/m3/src/query/util/json"
+	"github.com/m3db/m3/src/query/util/logging"
+	"github.com/m3db/m3/src/x/instrument"
+	xhttp "github.com/m3db/m3/src/x/net/http"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// TagsURL is the url for listing graphite tags.
+	TagsURL = handler.RoutePrefixV1 + "/graphite/tags"
+
+	// TagsAutoCompleteTagsURL is the url for auto completing graphite tags.
+	TagsAutoCompleteTagsURL = TagsURL + "/autoComplete/tags"
+
+	// TagsAutoCompleteValuesURL is the url for auto completing graphite tag
+	// values.
+	TagsAutoCompleteValuesURL = TagsURL + "/autoComplete/values"
+
+	defaultAutoCompleteLimit = 100
+)
+
+var (
+	// TagsHTTPMethods are the HTTP methods for the tags handlers.
+	TagsHTTPMethods = []string{http.MethodGet, http.MethodPost}
+
+	metricNameTag = models.NewTagOptions().MetricName()
+
+	errNoTag = errors.New("missing 'tag' parameter")
+)
+
+type tagsHandlerType int
+
+const (
+	listTagsHandlerType tagsHandlerType = iota
+	autoCompleteTagsHandlerType
+	autoCompleteValuesHandlerType
+)
+
+type graphiteTagsHandler struct {
+	handlerType         tagsHandlerType
+	storage             storage.Storage
+	fetchOptionsBuilder handleroptions.FetchOptionsBuilder
+	instrumentOpts      instrument.Options
+}
+
+// NewTagsHandler returns a new instance of the handler listing the tags of
+// graphite tagged series.
+func NewTagsHandler(opts options.HandlerOptions) http.Handler {
+	return newTagsHandler(listTagsHandlerType, opts)
+}
+
+// NewTagsAutoCompleteTagsHandler returns a new instance of the handler auto
+// completing the tags of graphite tagged series matching tag expressions.
+func NewTagsAutoCompleteTagsHandler(opts options.HandlerOptions) http.Handler {
+	return newTagsHandler(autoCompleteTagsHandlerType, opts)
+}
+
+// NewTagsAutoCompleteValuesHandler returns a new instance of the handler auto
+// completing the values of a tag of graphite tagged series matching tag
+// expressions.
+func NewTagsAutoCompleteValuesHandler(opts options.HandlerOptions) http.Handler {
+	return newTagsHandler(autoCompleteValuesHandlerType, opts)
+}
+
+func newTagsHandler(
+	handlerType tagsHandlerType,
+	opts options.HandlerOptions,
+) http.Handler {
+	return &graphiteTagsHandler{
+		handlerType:         handlerType,
+		storage:             opts.Storage(),
+		fetchOptionsBuilder: opts.FetchOptionsBuilder(),
+		instrumentOpts:      opts.InstrumentOpts(),
+	}
+}
+
+// tagsQuery is a parsed graphite tags request.
+type tagsQuery struct {
+	query *storage.CompleteTagsQuery
+	// exprTags are the tags used in the tag expressions of the request, which
+	// are not auto completed.
+	exprTags map[string]struct{}
+	// filter restricts listed tags.
+	filter *regexp.Regexp
+	// prefix restricts auto completed tags or values.
+	prefix string
+	limit  int
+}
+
+func (h *graphiteTagsHandler) parseRequest(
+	r *http.Request,
+) (tagsQuery, *xhttp.ParseError) {
+	if err := r.ParseForm(); err != nil {
+		return tagsQuery{}, xhttp.NewParseError(err, http.StatusBadRequest)
+	}
+
+	from, until, rErr := parseFromUntil(r)
+	if rErr != nil {
+		return tagsQuery{}, rErr
+	}
+
+	exprs := r.Form["expr"]
+	parsed := tagsQuery{
+		query: &storage.CompleteTagsQuery{
+			CompleteNameOnly: h.handlerType != autoCompleteValuesHandlerType,
+			Start:            from,
+			End:              until,
+		},
+		exprTags: make(map[string]struct{}, len(exprs)),
+	}
+
+	if len(exprs) == 0 {
+		// NB: match all graphite tagged series, which carry their path under
+		// the metric name tag.
+		parsed.query.TagMatchers = models.Matchers{{
+			Type: models.MatchField,
+			Name: metricNameTag,
+		}}
+	} else {
+		matchers, err := graphiteStorage.TranslateTagExpressionsToMatchers(exprs)
+		if err != nil {
+			return tagsQuery{}, xhttp.NewParseError(
+				fmt.Errorf("invalid 'expr': %v", err), http.StatusBadRequest)
+		}
+
+		parsed.query.TagMatchers = matchers
+		for _, expr := range exprs {
+			if idx := strings.IndexAny(expr, "!="); idx > 0 {
+				parsed.exprTags[expr[:idx]] = struct{}{}
+			}
+		}
+	}
+
+	switch h.handlerType {
+	case listTagsHandlerType:
+		if filter := r.FormValue("filter"); filter != "" {
+			re, err := regexp.Compile(filter)
+			if err != nil {
+				return tagsQuery{}, xhttp.NewParseError(
+					fmt.Errorf("invalid 'filter': %v", err), http.StatusBadRequest)
+			}
+
+			parsed.filter = re
+		}
+	case autoCompleteTagsHandlerType:
+		parsed.prefix = r.FormValue("tagPrefix")
+	case autoCompleteValuesHandlerType:
+		tag := r.FormValue("tag")
+		if tag == "" {
+			return tagsQuery{}, xhttp.NewParseError(errNoTag, http.StatusBadRequest)
+		}
+
+		parsed.prefix = r.FormValue("valuePrefix")
+		parsed.query.FilterNameTags = [][]byte{toStorageTagName(tag)}
+	}
+
+	if h.handlerType != listTagsHandlerType {
+		parsed.limit = defaultAutoCompleteLimit
+		if limit := r.FormValue("limit"); limit != "" {
+			n, err := strconv.Atoi(limit)
+			if err != nil || n <= 0 {
+				return tagsQuery{}, xhttp.NewParseError(
+					fmt.Errorf("invalid 'limit': %s", limit), http.StatusBadRequest)
+			}
+
+			parsed.limit = n
+		}
+	}
+
+	return parsed, nil
+}
+
+func (h *graphiteTagsHandler) ServeHTTP(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+	ctx := context.WithValue(r.Context(), handler.HeaderKey, r.Header)
+	logger := logging.WithContext(ctx, h.instrumentOpts)
+	w.Header().Set("Content-Type", "application/json")
+
+	query, rErr := h.parseRequest(r)
+	if rErr != nil {
+		xhttp.Error(w, rErr.Inner(), rErr.Code())
+		return
+	}
+
+	opts, rErr := h.fetchOptionsBuilder.NewFetchOptions(r)
+	if rErr != nil {
+		xhttp.Error(w, rErr.Inner(), rErr.Code())
+		return
+	}
+
+	result, err := h.storage.CompleteTags(ctx, query.query, opts)
+	if err != nil {
+		logger.Error("unable to complete tags", zap.Error(err))
+		xhttp.Error(w, err, http.StatusBadRequest)
+		return
+	}
+
+	handleroptions.AddWarningHeaders(w, result.Metadata)
+	switch h.handlerType {
+	case listTagsHandlerType:
+		err = tagsResultsJSON(w, query.tagNames(result))
+	case autoCompleteTagsHandlerType:
+		err = autoCompleteResultsJSON(w, query.tagNames(result))
+	default:
+		err = autoCompleteResultsJSON(w, query.tagValues(result))
+	}
+
+	if err != nil {
+		logger.Error("unable to write tags results", zap.Error(err))
+		xhttp.Error(w, err, http.StatusBadRequest)
+	}
+}
+
+// tagNames returns the sorted graphite tag names of a completed tags result.
+func (q tagsQuery) tagNames(result *storage.CompleteTagsResult) []string {
+	names := make([]string, 0, len(result.CompletedTags))
+	for _, tag := range result.CompletedTags {
+		name, ok := toGraphiteTagName(tag.Name)
+		if !ok {
+			continue
+		}
+
+		if _, ok := q.exprTags[name]; ok {
+			continue
+		}
+
+		if q.filter != nil && !q.filter.MatchString(name) {
+			continue
+		}
+
+		if !strings.HasPrefix(name, q.prefix) {
+			continue
+		}
+
+		names = append(names, name)
+	}
+
+	return q.sortAndLimit(names)
+}
+
+// tagValues returns the sorted values of a completed tag values result.
+func (q tagsQuery) tagValues(result *storage.CompleteTagsResult) []string {
+	var values []string
+	for _, tag := range result.CompletedTags {
+		for _, value := range tag.Values {
+			if bytes.HasPrefix(value, []byte(q.prefix)) {
+				values = append(values, string(value))
+			}
+		}
+	}
+
+	return q.sortAndLimit(values)
+}
+
+func (q tagsQuery) sortAndLimit(results []string) []string {
+	sort.Strings(results)
+	if q.limit > 0 && len(results) > q.limit {
+		results = results[:q.limit]
+	}
+
+	return results
+}
+
+// toStorageTagName maps a graphite tag name to the tag it is stored under.
+func toStorageTagName(name string) []byte {
+	if name == graphite.TaggedNameTag {
+		return metricNameTag
+	}
+
+	return []byte(name)
+}
+
+// toGraphiteTagName maps a stored tag name to its graphite tag name, ignoring
+// the tags which hold the nodes of graphite paths.
+func toGraphiteTagName(name []byte) (string, bool) {
+	if bytes.Equal(name, metricNameTag) {
+		return graphite.TaggedNameTag, true
+	}
+
+	if bytes.HasPrefix(name, []byte("__g")) && bytes.HasSuffix(name, []byte("__")) {
+		return "", false
+	}
+
+	return string(name), true
+}
+
+func tagsResultsJSON(w http.ResponseWriter, tags []string) error {
+	jw := json.NewWriter(w)
+	jw.BeginArray()
+	for _, tag := range tags {
+		jw.BeginObject()
+		jw.BeginObjectField("tag")
+		jw.WriteString(tag)
+		jw.EndObject()
+	}
+
+	jw.EndArray()
+	return jw.Close()
+}
+
+func autoCompleteResultsJSON(w http.ResponseWriter, results []string) error {
+	jw := json.NewWriter(w)
+	jw.BeginArray()
+	for _, result := range results {
+		jw.WriteString(result)
+	}
+
+	jw.EndArray()
+	return jw.Close()
+}
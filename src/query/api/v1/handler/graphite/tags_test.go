@@ -0,0 +1,185 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package graphite
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/m3db/m3/src/query/api/v1/handler/prometheus/handleroptions"
+	"github.com/m3db/m3/src/query/api/v1/options"
+	"github.com/m3db/m3/src/query/block"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/storage"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTagsTestHandlerOptions(store storage.Storage) options.HandlerOptions {
+	builder := handleroptions.
+		NewFetchOptionsBuilder(handleroptions.FetchOptionsBuilderOptions{})
+	return options.EmptyHandlerOptions().
+		SetFetchOptionsBuilder(builder).
+		SetStorage(store)
+}
+
+func TestTagsHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := storage.NewMockStorage(ctrl)
+	store.EXPECT().CompleteTags(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(
+			_ context.Context,
+			query *storage.CompleteTagsQuery,
+			_ *storage.FetchOptions,
+		) (*storage.CompleteTagsResult, error) {
+			assert.True(t, query.CompleteNameOnly)
+			assert.Equal(t, models.Matchers{
+				{Type: models.MatchField, Name: b("__name__")},
+			}, query.TagMatchers)
+			return &storage.CompleteTagsResult{
+				CompleteNameOnly: true,
+				CompletedTags: []storage.CompletedTag{
+					{Name: b("__g0__")},
+					{Name: b("__name__")},
+					{Name: b("dc")},
+					{Name: b("app")},
+					{Name: b("host")},
+				},
+				Metadata: block.NewResultMetadata(),
+			}, nil
+		})
+
+	h := NewTagsHandler(newTagsTestHandlerOptions(store))
+	req := httptest.NewRequest(http.MethodGet, TagsURL+"?filter=^[adn]", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var actual []map[string]string
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &actual))
+	assert.Equal(t, []map[string]string{
+		{"tag": "app"},
+		{"tag": "dc"},
+		{"tag": "name"},
+	}, actual)
+}
+
+func TestTagsAutoCompleteTagsHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := storage.NewMockStorage(ctrl)
+	store.EXPECT().CompleteTags(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(
+			_ context.Context,
+			query *storage.CompleteTagsQuery,
+			_ *storage.FetchOptions,
+		) (*storage.CompleteTagsResult, error) {
+			assert.True(t, query.CompleteNameOnly)
+			require.Equal(t, 1, len(query.TagMatchers))
+			assert.Equal(t, b("__name__"), query.TagMatchers[0].Name)
+			assert.Equal(t, b("cpu"), query.TagMatchers[0].Value)
+			return &storage.CompleteTagsResult{
+				CompleteNameOnly: true,
+				CompletedTags: []storage.CompletedTag{
+					{Name: b("__name__")},
+					{Name: b("dc")},
+					{Name: b("disk")},
+					{Name: b("host")},
+					{Name: b("drive")},
+				},
+				Metadata: block.NewResultMetadata(),
+			}, nil
+		})
+
+	h := NewTagsAutoCompleteTagsHandler(newTagsTestHandlerOptions(store))
+	req := httptest.NewRequest(http.MethodGet,
+		TagsAutoCompleteTagsURL+"?expr=name%3Dcpu&tagPrefix=d&limit=2", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var actual []string
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &actual))
+	assert.Equal(t, []string{"dc", "disk"}, actual)
+}
+
+func TestTagsAutoCompleteValuesHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := storage.NewMockStorage(ctrl)
+	store.EXPECT().CompleteTags(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(
+			_ context.Context,
+			query *storage.CompleteTagsQuery,
+			_ *storage.FetchOptions,
+		) (*storage.CompleteTagsResult, error) {
+			assert.False(t, query.CompleteNameOnly)
+			assert.Equal(t, bs("__name__"), query.FilterNameTags)
+			return &storage.CompleteTagsResult{
+				CompletedTags: []storage.CompletedTag{
+					{Name: b("__name__"), Values: bs("disk.used", "cpu.load", "cpu.idle")},
+				},
+				Metadata: block.NewResultMetadata(),
+			}, nil
+		})
+
+	h := NewTagsAutoCompleteValuesHandler(newTagsTestHandlerOptions(store))
+	req := httptest.NewRequest(http.MethodGet,
+		TagsAutoCompleteValuesURL+"?tag=name&valuePrefix=cpu", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var actual []string
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &actual))
+	assert.Equal(t, []string{"cpu.idle", "cpu.load"}, actual)
+}
+
+func TestTagsAutoCompleteHandlersInvalidRequests(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	opts := newTagsTestHandlerOptions(storage.NewMockStorage(ctrl))
+	tests := []struct {
+		handler http.Handler
+		url     string
+	}{
+		{NewTagsHandler(opts), TagsURL + "?filter=(a"},
+		{NewTagsAutoCompleteTagsHandler(opts), TagsAutoCompleteTagsURL + "?expr=dc!%3Deast"},
+		{NewTagsAutoCompleteTagsHandler(opts), TagsAutoCompleteTagsURL + "?limit=-1"},
+		{NewTagsAutoCompleteValuesHandler(opts), TagsAutoCompleteValuesURL},
+	}
+
+	for _, test := range tests {
+		w := httptest.NewRecorder()
+		test.handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, test.url, nil))
+		assert.Equal(t, http.StatusBadRequest, w.Code, test.url)
+	}
+}
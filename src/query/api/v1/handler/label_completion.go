@@ -0,0 +1,263 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/storage/index"
+	"github.com/m3db/m3/src/m3ninx/idx"
+	"github.com/m3db/m3/src/query/api/v1/handler/prometheus"
+	"github.com/m3db/m3/src/query/api/v1/options"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/storage"
+	"github.com/m3db/m3/src/query/storage/m3"
+	"github.com/m3db/m3/src/query/util"
+	"github.com/m3db/m3/src/query/util/logging"
+	"github.com/m3db/m3/src/x/instrument"
+	xhttp "github.com/m3db/m3/src/x/net/http"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// LabelCompletionURL is the url to complete partial label names and values.
+	LabelCompletionURL = RoutePrefixV1 + "/labels/complete"
+
+	// LabelCompletionHTTPMethod is the HTTP method used with this resource.
+	LabelCompletionHTTPMethod = http.MethodGet
+
+	defaultLabelCompletionLimit    = 20
+	defaultLabelCompletionMaxEdits = 1
+	defaultLabelCompletionRange    = time.Hour
+
+	// minFuzzyLabelCompletionLength is the length of the shortest partial
+	// string completed fuzzily, edits to shorter strings match almost
+	// every label.
+	minFuzzyLabelCompletionLength = 3
+
+	labelCompletionMatchParam = "match[]"
+)
+
+// LabelCompletionHandler completes partial label names, or the partial
+// values of a label, with prefix and fuzzy matches ranked by the number of
+// series, optionally scoped to the series matching selectors.
+type LabelCompletionHandler struct {
+	clusters       m3.Clusters
+	tagOptions     models.TagOptions
+	instrumentOpts instrument.Options
+	nowFn          func() time.Time
+}
+
+// LabelCompletionResponse is the response of the label completion endpoint.
+type LabelCompletionResponse struct {
+	Namespace   string                `json:"namespace"`
+	Label       string                `json:"label,omitempty"`
+	Completions []LabelCompletionJSON `json:"completions"`
+}
+
+// LabelCompletionJSON is a completed label name or value.
+type LabelCompletionJSON struct {
+	Value     string `json:"value"`
+	NumSeries int64  `json:"numSeries"`
+	// Fuzzy is set when the value does not start with the partial string.
+	Fuzzy bool `json:"fuzzy,omitempty"`
+}
+
+type labelCompletionRequest struct {
+	namespace string
+	label     string
+	opts      index.CardinalityOptions
+}
+
+// NewLabelCompletionHandler returns a new instance of handler.
+func NewLabelCompletionHandler(opts options.HandlerOptions) http.Handler {
+	return &LabelCompletionHandler{
+		clusters:       opts.Clusters(),
+		tagOptions:     opts.TagOptions(),
+		instrumentOpts: opts.InstrumentOpts(),
+		nowFn:          time.Now,
+	}
+}
+
+func (h *LabelCompletionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := logging.WithContext(r.Context(), h.instrumentOpts)
+
+	req, parseErr := h.parseRequest(r)
+	if parseErr != nil {
+		logger.Error("unable to parse request", zap.Error(parseErr.Inner()))
+		xhttp.Error(w, parseErr.Inner(), parseErr.Code())
+		return
+	}
+
+	ns, err := clusterNamespace(h.clusters, req.namespace)
+	if err != nil {
+		logger.Error("unable to find namespace", zap.Error(err))
+		xhttp.Error(w, err, http.StatusBadRequest)
+		return
+	}
+
+	results, err := ns.Session().IndexCardinality(ns.NamespaceID(), req.opts)
+	if err != nil {
+		logger.Error("unable to complete labels", zap.Error(err))
+		xhttp.Error(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	xhttp.WriteJSONResponse(w, newLabelCompletionResponse(
+		ns.NamespaceID().String(), req, results), logger)
+}
+
+func (h *LabelCompletionHandler) parseRequest(
+	r *http.Request,
+) (labelCompletionRequest, *xhttp.ParseError) {
+	if err := r.ParseForm(); err != nil {
+		return labelCompletionRequest{}, xhttp.NewParseError(err, http.StatusBadRequest)
+	}
+
+	var (
+		now = h.nowFn()
+		req = labelCompletionRequest{
+			namespace: r.FormValue("namespace"),
+			label:     r.FormValue("label"),
+			opts: index.CardinalityOptions{
+				StartInclusive: now.Add(-defaultLabelCompletionRange),
+				EndExclusive:   now,
+				Limit:          defaultLabelCompletionLimit,
+				Match: index.CardinalityMatch{
+					Partial:  []byte(r.FormValue("q")),
+					MaxEdits: defaultLabelCompletionMaxEdits,
+				},
+			},
+		}
+		err error
+	)
+	if str := r.FormValue("start"); str != "" {
+		req.opts.StartInclusive, err = util.ParseTimeString(str)
+		if err != nil {
+			return labelCompletionRequest{}, xhttp.NewParseError(err, http.StatusBadRequest)
+		}
+	}
+	if str := r.FormValue("end"); str != "" {
+		req.opts.EndExclusive, err = util.ParseTimeString(str)
+		if err != nil {
+			return labelCompletionRequest{}, xhttp.NewParseError(err, http.StatusBadRequest)
+		}
+	}
+	if !req.opts.StartInclusive.Before(req.opts.EndExclusive) {
+		return labelCompletionRequest{}, xhttp.NewParseError(
+			errors.New("start must be before end"), http.StatusBadRequest)
+	}
+	if str := r.FormValue("limit"); str != "" {
+		req.opts.Limit, err = strconv.Atoi(str)
+		if err != nil {
+			return labelCompletionRequest{}, xhttp.NewParseError(err, http.StatusBadRequest)
+		}
+	}
+	if str := r.FormValue("fuzzy"); str != "" {
+		req.opts.Match.MaxEdits, err = strconv.Atoi(str)
+		if err != nil {
+			return labelCompletionRequest{}, xhttp.NewParseError(err, http.StatusBadRequest)
+		}
+	}
+	if len(req.opts.Match.Partial) < minFuzzyLabelCompletionLength {
+		req.opts.Match.MaxEdits = 0
+	}
+	if err := req.opts.Match.Validate(); err != nil {
+		return labelCompletionRequest{}, xhttp.NewParseError(err, http.StatusBadRequest)
+	}
+
+	if req.label == "" {
+		req.opts.Match.FieldNames = true
+	} else {
+		req.opts.FieldFilter = index.AggregateFieldFilter{[]byte(req.label)}
+	}
+
+	if len(r.Form[labelCompletionMatchParam]) > 0 {
+		query, parseErr := h.parseSelectors(r)
+		if parseErr != nil {
+			return labelCompletionRequest{}, parseErr
+		}
+		req.opts.Query = &query
+	}
+
+	return req, nil
+}
+
+// parseSelectors returns a query matching the series of any selector.
+func (h *LabelCompletionHandler) parseSelectors(
+	r *http.Request,
+) (index.Query, *xhttp.ParseError) {
+	fetchQueries, parseErr := prometheus.ParseSeriesMatchQuery(r, h.tagOptions)
+	if parseErr != nil {
+		return index.Query{}, parseErr
+	}
+
+	queries := make([]idx.Query, 0, len(fetchQueries))
+	for _, fetchQuery := range fetchQueries {
+		query, err := storage.FetchQueryToM3Query(fetchQuery, nil)
+		if err != nil {
+			return index.Query{}, xhttp.NewParseError(err, http.StatusBadRequest)
+		}
+		queries = append(queries, query.Query)
+	}
+	if len(queries) == 1 {
+		return index.Query{Query: queries[0]}, nil
+	}
+	return index.Query{Query: idx.NewDisjunctionQuery(queries...)}, nil
+}
+
+func newLabelCompletionResponse(
+	namespace string,
+	req labelCompletionRequest,
+	results index.CardinalityResults,
+) LabelCompletionResponse {
+	resp := LabelCompletionResponse{
+		Namespace: namespace,
+		Label:     req.label,
+	}
+	partial := req.opts.Match.Partial
+	if req.label == "" {
+		resp.Completions = make([]LabelCompletionJSON, 0, len(results.Fields))
+		for _, f := range results.Fields {
+			resp.Completions = append(resp.Completions, LabelCompletionJSON{
+				Value:     string(f.Field),
+				NumSeries: f.NumSeries,
+				Fuzzy:     !bytes.HasPrefix(f.Field, partial),
+			})
+		}
+		return resp
+	}
+
+	resp.Completions = make([]LabelCompletionJSON, 0, len(results.Terms))
+	for _, t := range results.Terms {
+		resp.Completions = append(resp.Completions, LabelCompletionJSON{
+			Value:     string(t.Term),
+			NumSeries: t.NumSeries,
+			Fuzzy:     !bytes.HasPrefix(t.Term, partial),
+		})
+	}
+	return resp
+}
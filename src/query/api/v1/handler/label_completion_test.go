@@ -0,0 +1,168 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/client"
+	"github.com/m3db/m3/src/dbnode/storage/index"
+	"github.com/m3db/m3/src/m3ninx/idx"
+	"github.com/m3db/m3/src/query/api/v1/options"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/storage/m3"
+	"github.com/m3db/m3/src/x/ident"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestLabelCompletionHandler(
+	t *testing.T,
+	session client.Session,
+) http.Handler {
+	clusters, err := m3.NewClusters(m3.UnaggregatedClusterNamespaceDefinition{
+		NamespaceID: ident.StringID("default"),
+		Session:     session,
+		Retention:   48 * time.Hour,
+	})
+	require.NoError(t, err)
+
+	return NewLabelCompletionHandler(options.EmptyHandlerOptions().
+		SetClusters(clusters).
+		SetTagOptions(models.NewTagOptions()))
+}
+
+func TestLabelCompletionHandlerNames(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	session := client.NewMockSession(ctrl)
+	h := newTestLabelCompletionHandler(t, session)
+
+	start := time.Unix(1500000000, 0)
+	end := start.Add(time.Hour)
+	session.EXPECT().IndexCardinality(ident.NewIDMatcher("default"),
+		index.CardinalityOptions{
+			StartInclusive: start,
+			EndExclusive:   end,
+			Limit:          5,
+			Match: index.CardinalityMatch{
+				Partial:    []byte("host"),
+				MaxEdits:   1,
+				FieldNames: true,
+			},
+		}).Return(index.CardinalityResults{
+		Fields: []index.FieldCardinality{
+			{Field: []byte("host"), NumValues: 2, NumSeries: 3},
+			{Field: []byte("ghost"), NumValues: 1, NumSeries: 5},
+		},
+	}, nil)
+
+	req := httptest.NewRequest(LabelCompletionHTTPMethod, LabelCompletionURL+
+		"?start=1500000000&end=1500003600&q=host&limit=5", nil)
+	recorder := httptest.NewRecorder()
+	h.ServeHTTP(recorder, req)
+	require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+
+	var resp LabelCompletionResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+	require.Equal(t, LabelCompletionResponse{
+		Namespace: "default",
+		Completions: []LabelCompletionJSON{
+			{Value: "host", NumSeries: 3},
+			{Value: "ghost", NumSeries: 5, Fuzzy: true},
+		},
+	}, resp)
+}
+
+func TestLabelCompletionHandlerValues(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	session := client.NewMockSession(ctrl)
+	h := newTestLabelCompletionHandler(t, session)
+
+	start := time.Unix(1500000000, 0)
+	end := start.Add(time.Hour)
+	session.EXPECT().IndexCardinality(ident.NewIDMatcher("default"),
+		gomock.Any()).DoAndReturn(func(
+		_ ident.ID,
+		opts index.CardinalityOptions,
+	) (index.CardinalityResults, error) {
+		require.Equal(t, start, opts.StartInclusive)
+		require.Equal(t, end, opts.EndExclusive)
+		require.Equal(t, defaultLabelCompletionLimit, opts.Limit)
+		require.Equal(t, index.AggregateFieldFilter{[]byte("city")}, opts.FieldFilter)
+		// Fuzzy matching is disabled for short partial strings.
+		require.Equal(t, index.CardinalityMatch{Partial: []byte("n")}, opts.Match)
+		require.NotNil(t, opts.Query)
+		require.True(t, index.NewQueryMatcher(index.Query{
+			Query: idx.NewTermQuery([]byte("service"), []byte("api")),
+		}).Matches(*opts.Query))
+		return index.CardinalityResults{
+			Terms: []index.TermCardinality{
+				{Field: []byte("city"), Term: []byte("nyc"), NumSeries: 2},
+			},
+		}, nil
+	})
+
+	req := httptest.NewRequest(LabelCompletionHTTPMethod, LabelCompletionURL+
+		"?start=1500000000&end=1500003600&label=city&q=n&match[]="+
+		url.QueryEscape(`{service="api"}`), nil)
+	recorder := httptest.NewRecorder()
+	h.ServeHTTP(recorder, req)
+	require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+
+	var resp LabelCompletionResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+	require.Equal(t, LabelCompletionResponse{
+		Namespace: "default",
+		Label:     "city",
+		Completions: []LabelCompletionJSON{
+			{Value: "nyc", NumSeries: 2},
+		},
+	}, resp)
+}
+
+func TestLabelCompletionHandlerBadRequests(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	h := newTestLabelCompletionHandler(t, client.NewMockSession(ctrl))
+	for _, query := range []string{
+		"?q=host&fuzzy=3",
+		"?q=host&limit=abc",
+		"?start=1500003600&end=1500000000",
+		"?match[]=" + url.QueryEscape("{"),
+		"?namespace=unknown",
+	} {
+		req := httptest.NewRequest(LabelCompletionHTTPMethod, LabelCompletionURL+query, nil)
+		recorder := httptest.NewRecorder()
+		h.ServeHTTP(recorder, req)
+		require.Equal(t, http.StatusBadRequest, recorder.Code, query)
+	}
+}
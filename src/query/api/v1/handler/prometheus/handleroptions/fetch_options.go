@@ -21,6 +21,7 @@
 package handleroptions
 
 import (
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"math"
@@ -40,8 +41,10 @@ const (
 	StepParam = "step"
 	// LookbackParam is the lookback parameter.
 	LookbackParam = "lookback"
-	maxInt64      = float64(math.MaxInt64)
-	minInt64      = float64(math.MinInt64)
+	// PageTokenParam is the page token parameter.
+	PageTokenParam = "page_token"
+	maxInt64       = float64(math.MaxInt64)
+	minInt64       = float64(math.MinInt64)
 )
 
 // FetchOptionsBuilder builds fetch options based on a request and default
@@ -95,6 +98,35 @@ func ParseLimit(req *http.Request, defaultLimit int) (int, error) {
 	return defaultLimit, nil
 }
 
+// ParsePageToken parses the page token of a request into the fetch options,
+// the fetch is paginated if the page token is set and an empty page token
+// requests the first page. Page tokens are URL safe base64 encoded.
+func ParsePageToken(req *http.Request, fetchOpts *storage.FetchOptions) error {
+	values, ok := req.URL.Query()[PageTokenParam]
+	if !ok {
+		return nil
+	}
+
+	if fetchOpts.Limit <= 0 {
+		return fmt.Errorf(
+			"paginated requests require a positive limit: limit=%d", fetchOpts.Limit)
+	}
+
+	str := values[0]
+	token, err := base64.RawURLEncoding.DecodeString(str)
+	if err != nil {
+		return fmt.Errorf(
+			"could not parse page token: input=%s, err=%v", str, err)
+	}
+
+	fetchOpts.Paginated = true
+	if len(token) > 0 {
+		fetchOpts.PageToken = token
+	}
+
+	return nil
+}
+
 // NewFetchOptions parses an http request into fetch options.
 func (b fetchOptionsBuilder) NewFetchOptions(
 	req *http.Request,
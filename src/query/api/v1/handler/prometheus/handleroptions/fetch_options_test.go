@@ -275,6 +275,33 @@ func TestParseDurationOverflowError(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestParsePageToken(t *testing.T) {
+	opts := storage.NewFetchOptions()
+	opts.Limit = 10
+	r := httptest.NewRequest(http.MethodGet, "/foo", nil)
+	require.NoError(t, ParsePageToken(r, opts))
+	assert.False(t, opts.Paginated)
+
+	r = httptest.NewRequest(http.MethodGet, "/foo?page_token=", nil)
+	require.NoError(t, ParsePageToken(r, opts))
+	assert.True(t, opts.Paginated)
+	assert.Nil(t, opts.PageToken)
+
+	opts = storage.NewFetchOptions()
+	opts.Limit = 10
+	r = httptest.NewRequest(http.MethodGet, "/foo?page_token=-_8", nil)
+	require.NoError(t, ParsePageToken(r, opts))
+	assert.True(t, opts.Paginated)
+	assert.Equal(t, []byte{0xfb, 0xff}, opts.PageToken)
+
+	r = httptest.NewRequest(http.MethodGet, "/foo?page_token=!", nil)
+	require.Error(t, ParsePageToken(r, opts))
+
+	opts.Limit = 0
+	r = httptest.NewRequest(http.MethodGet, "/foo?page_token=", nil)
+	require.Error(t, ParsePageToken(r, opts))
+}
+
 func TestFetchOptionsWithHeader(t *testing.T) {
 	type expectedLookback struct {
 		value time.Duration
@@ -57,3 +57,16 @@ func TestAddWarningHeaders(t *testing.T) {
 	assert.Equal(t, 1, len(recorder.Header()))
 	assert.Equal(t, ex, recorder.Header().Get(LimitHeader))
 }
+
+func TestAddNextPageTokenHeader(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	meta := block.NewResultMetadata()
+	AddNextPageTokenHeader(recorder, meta)
+	assert.Equal(t, 0, len(recorder.Header()))
+
+	recorder = httptest.NewRecorder()
+	meta.NextPageToken = []byte{0xfb, 0xff}
+	AddNextPageTokenHeader(recorder, meta)
+	assert.Equal(t, 1, len(recorder.Header()))
+	assert.Equal(t, "-_8", recorder.Header().Get(NextPageTokenHeader))
+}
@@ -21,6 +21,7 @@
 package handleroptions
 
 import (
+	"encoding/base64"
 	"net/http"
 	"strings"
 
@@ -60,6 +61,13 @@ const (
 	// the number of time series returned by each storage node.
 	LimitMaxSeriesHeader = "M3-Limit-Max-Series"
 
+	// NativeHistogramsHeader specifies that Prometheus bucket series in a
+	// remote write request should be folded into native histogram series.
+	// All the buckets of a histogram must be sent in the same request, the
+	// namespaces written to must also have histograms enabled.
+	// Valid values are "true" or "false".
+	NativeHistogramsHeader = "M3-Native-Histograms"
+
 	// UnaggregatedStoragePolicy specifies the unaggregated storage policy.
 	UnaggregatedStoragePolicy = "unaggregated"
 
@@ -85,6 +93,11 @@ const (
 	// LimitHeaderSeriesLimitApplied is the header applied when fetch results are
 	// maxed.
 	LimitHeaderSeriesLimitApplied = "max_fetch_series_limit_applied"
+
+	// NextPageTokenHeader is the header added to the response of a paginated
+	// request with the page token of the next page, it is not added once all
+	// pages have been returned.
+	NextPageTokenHeader = "M3-Next-Page-Token"
 )
 
 // AddWarningHeaders adds any warning headers present in the result's metadata.
@@ -111,3 +124,14 @@ func AddWarningHeaders(w http.ResponseWriter, meta block.ResultMetadata) {
 
 	w.Header().Set(LimitHeader, strings.Join(warnings, ","))
 }
+
+// AddNextPageTokenHeader adds the next page token header of a paginated
+// request, URL safe base64 encoded. No-op once all pages have been returned.
+func AddNextPageTokenHeader(w http.ResponseWriter, meta block.ResultMetadata) {
+	if meta.NextPageToken == nil {
+		return
+	}
+
+	w.Header().Set(NextPageTokenHeader,
+		base64.RawURLEncoding.EncodeToString(meta.NextPageToken))
+}
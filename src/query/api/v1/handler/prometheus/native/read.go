@@ -31,8 +31,10 @@ import (
 	"github.com/m3db/m3/src/query/api/v1/handler/prometheus/handleroptions"
 	"github.com/m3db/m3/src/query/api/v1/options"
 	"github.com/m3db/m3/src/query/block"
+	"github.com/m3db/m3/src/query/cache"
 	"github.com/m3db/m3/src/query/executor"
 	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/parser/promql"
 	"github.com/m3db/m3/src/query/storage"
 	"github.com/m3db/m3/src/query/ts"
 	"github.com/m3db/m3/src/query/util/logging"
@@ -76,6 +78,7 @@ type PromReadHandler struct {
 	tagOpts             models.TagOptions
 	promReadMetrics     promReadMetrics
 	instrumentOpts      instrument.Options
+	resultsCache        *cache.ResultsCache
 }
 
 type promReadMetrics struct {
@@ -129,6 +132,7 @@ func NewPromReadHandler(opts options.HandlerOptions) *PromReadHandler {
 		timeoutOps:          opts.TimeoutOpts(),
 		keepEmpty:           opts.Config().ResultOptions.KeepNans,
 		instrumentOpts:      opts.InstrumentOpts(),
+		resultsCache:        opts.ResultsCache(),
 	}
 
 	pointCount := float64(limits.MaxComputedDatapoints())
@@ -205,8 +209,7 @@ func (h *PromReadHandler) ServeHTTPWithEngine(
 		return nil, emptyReqParams, &RespError{Err: err, Code: http.StatusBadRequest}
 	}
 
-	result, err := read(ctx, engine, opts, fetchOpts, h.tagOpts,
-		w, params, h.instrumentOpts)
+	result, err := h.read(ctx, engine, opts, fetchOpts, w, params)
 	if err != nil {
 		sp := xopentracing.SpanFromContextOrNoop(ctx)
 		sp.LogFields(opentracinglog.Error(err))
@@ -225,6 +228,82 @@ func (h *PromReadHandler) ServeHTTPWithEngine(
 	return result.series, params, nil
 }
 
+// read reads the query result, from the results cache if it is enabled and
+// the query can be cached.
+func (h *PromReadHandler) read(
+	ctx context.Context,
+	engine executor.Engine,
+	opts *executor.QueryOptions,
+	fetchOpts *storage.FetchOptions,
+	w http.ResponseWriter,
+	params models.RequestParams,
+) (readResult, error) {
+	key, ok := h.resultsCacheKey(engine, fetchOpts, params)
+	if !ok {
+		return read(ctx, engine, opts, fetchOpts, h.tagOpts,
+			w, params, h.instrumentOpts)
+	}
+
+	// NB: watch for the client closing the connection once for the whole
+	// query, rather than once for each span read through the cache.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	handler.CloseWatcher(ctx, cancel, w, h.instrumentOpts)
+
+	result, err := h.resultsCache.Read(ctx, key, params, fetchOpts.Limit,
+		func(ctx context.Context, params models.RequestParams) (cache.Result, error) {
+			result, err := read(ctx, engine, opts, fetchOpts, h.tagOpts,
+				nil, params, h.instrumentOpts)
+			if err != nil {
+				return cache.Result{}, err
+			}
+
+			return cache.Result{Series: result.series, Meta: result.meta}, nil
+		})
+	if err != nil {
+		return readResult{meta: block.NewResultMetadata()}, err
+	}
+
+	return readResult{series: result.Series, meta: result.Meta}, nil
+}
+
+// resultsCacheKey returns the key identifying the query and the options that
+// affect its result in the results cache, and false if the query should not
+// be cached.
+func (h *PromReadHandler) resultsCacheKey(
+	engine executor.Engine,
+	fetchOpts *storage.FetchOptions,
+	params models.RequestParams,
+) (string, bool) {
+	if h.resultsCache == nil || params.Debug ||
+		fetchOpts.IncludeResolution || fetchOpts.Paginated ||
+		fetchOpts.RestrictQueryOptions.GetRestrictByTag() != nil {
+		return "", false
+	}
+
+	parser, err := promql.Parse(params.Query, params.Step, h.tagOpts,
+		engine.Options().ParseOptions())
+	if err != nil {
+		// NB: the read will return the parse error.
+		return "", false
+	}
+
+	key := fmt.Sprintf("query=%s;lookback=%v;limit=%d;block=%d;keepNans=%v",
+		parser.String(), params.LookbackDuration, fetchOpts.Limit,
+		fetchOpts.BlockType, params.KeepNans)
+	if restrict := fetchOpts.RestrictQueryOptions.GetRestrictByType(); restrict != nil {
+		key += fmt.Sprintf(";metricsType=%d;storagePolicy=%s",
+			restrict.MetricsType, restrict.StoragePolicy.String())
+	}
+
+	if fanout := fetchOpts.FanoutOptions; fanout != nil {
+		key += fmt.Sprintf(";fanout=%d,%d,%d", fanout.FanoutUnaggregated,
+			fanout.FanoutAggregated, fanout.FanoutAggregatedOptimized)
+	}
+
+	return key, true
+}
+
 func (h *PromReadHandler) validateRequest(params *models.RequestParams) error {
 	// Impose a rough limit on the number of returned time series. This is intended to prevent things like
 	// querying from the beginning of time with a 1s step size.
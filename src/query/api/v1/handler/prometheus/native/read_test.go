@@ -35,6 +35,7 @@ import (
 	"github.com/m3db/m3/src/query/api/v1/handler/prometheus/handleroptions"
 	"github.com/m3db/m3/src/query/api/v1/options"
 	"github.com/m3db/m3/src/query/block"
+	"github.com/m3db/m3/src/query/cache"
 	"github.com/m3db/m3/src/query/executor"
 	"github.com/m3db/m3/src/query/models"
 	"github.com/m3db/m3/src/query/storage"
@@ -254,6 +255,53 @@ func TestPromReadHandler_ServeHTTP_maxComputedDatapoints(t *testing.T) {
 	assert.Equal(t, expected, errResp.Error)
 }
 
+func TestPromReadHandlerResultsCacheKey(t *testing.T) {
+	setup := newTestSetup()
+	h := setup.Handlers.Read
+	params := models.RequestParams{
+		Query: `sum(rate(foo{bar="baz"}[1m]))`,
+		Step:  time.Minute,
+	}
+
+	// Without a results cache queries are never cached.
+	_, ok := h.resultsCacheKey(h.engine, setup.FetchOpts, params)
+	assert.False(t, ok)
+
+	resultsCache, err := cache.NewResultsCache(cache.ResultsCacheOptions{
+		Backend: cache.NewLRUBackend(1 << 20),
+	})
+	require.NoError(t, err)
+	h.resultsCache = resultsCache
+
+	key, ok := h.resultsCacheKey(h.engine, setup.FetchOpts, params)
+	require.True(t, ok)
+
+	// Equivalent queries share a key.
+	spaced := params
+	spaced.Query = `sum( rate( foo{ bar = "baz" }[1m] ) )`
+	spacedKey, ok := h.resultsCacheKey(h.engine, setup.FetchOpts, spaced)
+	require.True(t, ok)
+	assert.Equal(t, key, spacedKey)
+
+	limited := storage.NewFetchOptions()
+	limited.Limit = 10
+	limitedKey, ok := h.resultsCacheKey(h.engine, limited, params)
+	require.True(t, ok)
+	assert.NotEqual(t, key, limitedKey)
+
+	restricted := storage.NewFetchOptions()
+	restricted.RestrictQueryOptions = &storage.RestrictQueryOptions{
+		RestrictByTag: &storage.RestrictByTag{},
+	}
+	_, ok = h.resultsCacheKey(h.engine, restricted, params)
+	assert.False(t, ok)
+
+	debug := params
+	debug.Debug = true
+	_, ok = h.resultsCacheKey(h.engine, setup.FetchOpts, debug)
+	assert.False(t, ok)
+}
+
 func TestPromReadHandler_validateRequest(t *testing.T) {
 	dt := func(year int, month time.Month, day, hour int) time.Time {
 		return time.Date(year, month, day, hour, 0, 0, 0, time.UTC)
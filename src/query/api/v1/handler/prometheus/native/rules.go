@@ -0,0 +1,239 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package native
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/m3db/m3/src/query/api/v1/handler"
+	"github.com/m3db/m3/src/query/api/v1/options"
+	"github.com/m3db/m3/src/query/rules"
+	"github.com/m3db/m3/src/x/instrument"
+	xhttp "github.com/m3db/m3/src/x/net/http"
+)
+
+const (
+	// RulesURL is the url for listing rules, this matches the URL of the
+	// rules endpoint found on a Prometheus server.
+	RulesURL = handler.RoutePrefixV1 + "/rules"
+
+	// AlertsURL is the url for listing active alerts, this matches the URL
+	// of the alerts endpoint found on a Prometheus server.
+	AlertsURL = handler.RoutePrefixV1 + "/alerts"
+
+	statusSuccess = "success"
+)
+
+var (
+	// RulesHTTPMethods are the HTTP methods for the rules handler.
+	RulesHTTPMethods = []string{http.MethodGet}
+
+	// AlertsHTTPMethods are the HTTP methods for the alerts handler.
+	AlertsHTTPMethods = []string{http.MethodGet}
+)
+
+type rulesResponse struct {
+	Status string    `json:"status"`
+	Data   rulesData `json:"data"`
+}
+
+type rulesData struct {
+	Groups []ruleGroupResult `json:"groups"`
+}
+
+type ruleGroupResult struct {
+	Name     string        `json:"name"`
+	File     string        `json:"file"`
+	Rules    []interface{} `json:"rules"`
+	Interval float64       `json:"interval"`
+}
+
+type recordingRuleResult struct {
+	Name      string            `json:"name"`
+	Query     string            `json:"query"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	Health    rules.RuleHealth  `json:"health"`
+	LastError string            `json:"lastError,omitempty"`
+	Type      string            `json:"type"`
+}
+
+type alertingRuleResult struct {
+	State       string            `json:"state"`
+	Name        string            `json:"name"`
+	Query       string            `json:"query"`
+	Duration    float64           `json:"duration"`
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations"`
+	Alerts      []alertResult     `json:"alerts"`
+	Health      rules.RuleHealth  `json:"health"`
+	LastError   string            `json:"lastError,omitempty"`
+	Type        string            `json:"type"`
+}
+
+type alertsResponse struct {
+	Status string     `json:"status"`
+	Data   alertsData `json:"data"`
+}
+
+type alertsData struct {
+	Alerts []alertResult `json:"alerts"`
+}
+
+type alertResult struct {
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations"`
+	State       string            `json:"state"`
+	ActiveAt    *time.Time        `json:"activeAt,omitempty"`
+	Value       string            `json:"value"`
+}
+
+// RulesHandler lists the rules evaluated by the rule manager.
+type RulesHandler struct {
+	manager        *rules.Manager
+	instrumentOpts instrument.Options
+}
+
+// NewRulesHandler returns a new instance of the rules handler.
+func NewRulesHandler(opts options.HandlerOptions) http.Handler {
+	return &RulesHandler{
+		manager:        opts.RuleManager(),
+		instrumentOpts: opts.InstrumentOpts(),
+	}
+}
+
+func (h *RulesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	groups := []ruleGroupResult{}
+	if h.manager != nil {
+		for _, group := range h.manager.RuleGroups() {
+			groups = append(groups, newRuleGroupResult(group))
+		}
+	}
+
+	xhttp.WriteJSONResponse(w, rulesResponse{
+		Status: statusSuccess,
+		Data:   rulesData{Groups: groups},
+	}, h.instrumentOpts.Logger())
+}
+
+func newRuleGroupResult(group *rules.Group) ruleGroupResult {
+	result := ruleGroupResult{
+		Name:     group.Name(),
+		File:     group.File(),
+		Rules:    make([]interface{}, 0, len(group.Rules())),
+		Interval: group.Interval().Seconds(),
+	}
+
+	for _, rule := range group.Rules() {
+		var lastError string
+		if err := rule.LastError(); err != nil {
+			lastError = err.Error()
+		}
+
+		alertingRule, ok := rule.(*rules.AlertingRule)
+		if !ok {
+			result.Rules = append(result.Rules, recordingRuleResult{
+				Name:      rule.Name(),
+				Query:     rule.Query(),
+				Labels:    rule.Labels(),
+				Health:    rule.Health(),
+				LastError: lastError,
+				Type:      "recording",
+			})
+			continue
+		}
+
+		result.Rules = append(result.Rules, alertingRuleResult{
+			State:       alertingRule.State().String(),
+			Name:        alertingRule.Name(),
+			Query:       alertingRule.Query(),
+			Duration:    alertingRule.HoldDuration().Seconds(),
+			Labels:      nonNilMap(alertingRule.Labels()),
+			Annotations: nonNilMap(alertingRule.Annotations()),
+			Alerts:      newAlertResults(alertingRule.ActiveAlerts()),
+			Health:      alertingRule.Health(),
+			LastError:   lastError,
+			Type:        "alerting",
+		})
+	}
+
+	return result
+}
+
+// AlertsHandler lists the active alerts of the rule manager.
+type AlertsHandler struct {
+	manager        *rules.Manager
+	instrumentOpts instrument.Options
+}
+
+// NewAlertsHandler returns a new instance of the alerts handler.
+func NewAlertsHandler(opts options.HandlerOptions) http.Handler {
+	return &AlertsHandler{
+		manager:        opts.RuleManager(),
+		instrumentOpts: opts.InstrumentOpts(),
+	}
+}
+
+func (h *AlertsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	alerts := []alertResult{}
+	if h.manager != nil {
+		for _, rule := range h.manager.AlertingRules() {
+			alerts = append(alerts, newAlertResults(rule.ActiveAlerts())...)
+		}
+	}
+
+	xhttp.WriteJSONResponse(w, alertsResponse{
+		Status: statusSuccess,
+		Data:   alertsData{Alerts: alerts},
+	}, h.instrumentOpts.Logger())
+}
+
+func newAlertResults(alerts []rules.Alert) []alertResult {
+	results := make([]alertResult, 0, len(alerts))
+	for _, alert := range alerts {
+		labels := make(map[string]string, len(alert.Tags.Tags))
+		for _, tag := range alert.Tags.Tags {
+			labels[string(tag.Name)] = string(tag.Value)
+		}
+
+		activeAt := alert.ActiveAt
+		results = append(results, alertResult{
+			Labels:      labels,
+			Annotations: nonNilMap(alert.Annotations),
+			State:       alert.State.String(),
+			ActiveAt:    &activeAt,
+			Value:       strconv.FormatFloat(alert.Value, 'e', -1, 64),
+		})
+	}
+
+	return results
+}
+
+// nonNilMap returns an empty map in place of a nil map, so that it is
+// rendered as an empty object rather than null.
+func nonNilMap(m map[string]string) map[string]string {
+	if m == nil {
+		return map[string]string{}
+	}
+
+	return m
+}
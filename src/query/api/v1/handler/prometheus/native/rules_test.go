@@ -0,0 +1,156 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package native
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/query/api/v1/options"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/rules"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testRuleFile = `
+groups:
+  - name: requests
+    interval: 30s
+    rules:
+      - record: job:requests:sum
+        expr: sum by (job) (requests)
+      - alert: NoRequests
+        expr: job:requests:sum == 0
+        for: 5m
+        annotations:
+          summary: "{{ $labels.job }} has no requests"
+`
+
+func newTestRuleManager(t *testing.T) *rules.Manager {
+	dir, err := ioutil.TempDir("", "rules")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "requests.yml")
+	require.NoError(t, ioutil.WriteFile(path, []byte(testRuleFile), 0644))
+
+	queryFn := func(context.Context, string, time.Time) (rules.Vector, error) {
+		tags := models.NewTags(1, models.NewTagOptions()).
+			AddTag(models.Tag{Name: []byte("job"), Value: []byte("api")})
+		return rules.Vector{{Tags: tags, Value: 0}}, nil
+	}
+
+	manager, err := rules.NewManager(rules.ManagerOptions{
+		RuleFiles: []string{path},
+		QueryFn:   queryFn,
+	})
+	require.NoError(t, err)
+
+	manager.RuleGroups()[0].Eval(context.Background(), time.Unix(1000, 0))
+	return manager
+}
+
+func TestRulesHandler(t *testing.T) {
+	opts := options.EmptyHandlerOptions().
+		SetRuleManager(newTestRuleManager(t))
+
+	recorder := httptest.NewRecorder()
+	NewRulesHandler(opts).ServeHTTP(recorder,
+		httptest.NewRequest("GET", RulesURL, nil))
+
+	expected := `{
+		"status": "success",
+		"data": {
+			"groups": [{
+				"name": "requests",
+				"file": "` + opts.RuleManager().RuleGroups()[0].File() + `",
+				"interval": 30,
+				"rules": [
+					{
+						"name": "job:requests:sum",
+						"query": "sum by (job) (requests)",
+						"health": "ok",
+						"type": "recording"
+					},
+					{
+						"state": "pending",
+						"name": "NoRequests",
+						"query": "job:requests:sum == 0",
+						"duration": 300,
+						"labels": {},
+						"annotations": {"summary": "{{ $labels.job }} has no requests"},
+						"alerts": [{
+							"labels": {"alertname": "NoRequests", "job": "api"},
+							"annotations": {"summary": "api has no requests"},
+							"state": "pending",
+							"activeAt": "` + time.Unix(1000, 0).Format(time.RFC3339Nano) + `",
+							"value": "0e+00"
+						}],
+						"health": "ok",
+						"type": "alerting"
+					}
+				]
+			}]
+		}
+	}`
+	assert.JSONEq(t, expected, recorder.Body.String())
+}
+
+func TestAlertsHandler(t *testing.T) {
+	opts := options.EmptyHandlerOptions().
+		SetRuleManager(newTestRuleManager(t))
+
+	recorder := httptest.NewRecorder()
+	NewAlertsHandler(opts).ServeHTTP(recorder,
+		httptest.NewRequest("GET", AlertsURL, nil))
+
+	var resp alertsResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+	assert.Equal(t, statusSuccess, resp.Status)
+	require.Equal(t, 1, len(resp.Data.Alerts))
+	assert.Equal(t, map[string]string{"alertname": "NoRequests", "job": "api"},
+		resp.Data.Alerts[0].Labels)
+	assert.Equal(t, "pending", resp.Data.Alerts[0].State)
+}
+
+func TestRulesHandlersWithoutManager(t *testing.T) {
+	opts := options.EmptyHandlerOptions()
+
+	recorder := httptest.NewRecorder()
+	NewRulesHandler(opts).ServeHTTP(recorder,
+		httptest.NewRequest("GET", RulesURL, nil))
+	assert.JSONEq(t, `{"status":"success","data":{"groups":[]}}`,
+		recorder.Body.String())
+
+	recorder = httptest.NewRecorder()
+	NewAlertsHandler(opts).ServeHTTP(recorder,
+		httptest.NewRequest("GET", AlertsURL, nil))
+	assert.JSONEq(t, `{"status":"success","data":{"alerts":[]}}`,
+		recorder.Body.String())
+}
@@ -22,6 +22,7 @@ package remote
 
 import (
 	"context"
+	"errors"
 	"net/http"
 
 	"github.com/m3db/m3/src/query/api/v1/handler"
@@ -46,6 +47,9 @@ const (
 var (
 	// PromSeriesMatchHTTPMethods are the HTTP methods for this handler.
 	PromSeriesMatchHTTPMethods = []string{http.MethodGet, http.MethodPost}
+
+	errPaginatedMultipleMatchers = errors.New(
+		"paginated requests only support a single match[] selector")
 )
 
 // PromSeriesMatchHandler represents a handler for
@@ -86,6 +90,19 @@ func (h *PromSeriesMatchHandler) ServeHTTP(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	if err := handleroptions.ParsePageToken(r, opts); err != nil {
+		logger.Error("unable to parse page token", zap.Error(err))
+		xhttp.Error(w, err, http.StatusBadRequest)
+		return
+	}
+
+	// NB: a page token resumes a single query so paginated requests can
+	// only have a single matcher.
+	if opts.Paginated && len(queries) > 1 {
+		xhttp.Error(w, errPaginatedMultipleMatchers, http.StatusBadRequest)
+		return
+	}
+
 	results := make([]models.Metrics, len(queries))
 	meta := block.NewResultMetadata()
 	for i, query := range queries {
@@ -101,6 +118,7 @@ func (h *PromSeriesMatchHandler) ServeHTTP(w http.ResponseWriter, r *http.Reques
 	}
 
 	handleroptions.AddWarningHeaders(w, meta)
+	handleroptions.AddNextPageTokenHeader(w, meta)
 	// TODO: Support multiple result types
 	if err := prometheus.RenderSeriesMatchResultsJSON(w, results, false); err != nil {
 		logger.Error("unable to write matched series", zap.Error(err))
@@ -27,6 +27,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
@@ -159,6 +160,7 @@ type promWriteMetrics struct {
 	forwardErrors        tally.Counter
 	forwardDropped       tally.Counter
 	forwardLatency       tally.Histogram
+	nativeHistograms     tally.Counter
 }
 
 func newPromWriteMetrics(scope tally.Scope) (promWriteMetrics, error) {
@@ -217,6 +219,7 @@ func newPromWriteMetrics(scope tally.Scope) (promWriteMetrics, error) {
 		forwardErrors:        scope.SubScope("forward").Counter("errors"),
 		forwardDropped:       scope.SubScope("forward").Counter("dropped"),
 		forwardLatency:       scope.SubScope("forward").Histogram("latency", forwardLatencyBuckets),
+		nativeHistograms:     scope.SubScope("write").Counter("native-histogram-series"),
 	}, nil
 }
 
@@ -228,6 +231,13 @@ func (h *PromWriteHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	nativeHistograms, rErr := parseNativeHistogramsHeader(r)
+	if rErr != nil {
+		h.metrics.writeErrorsClient.Inc(1)
+		xhttp.Error(w, rErr.Inner(), rErr.Code())
+		return
+	}
+
 	// Begin async forwarding.
 	// NB(r): Be careful about not returning buffers to pool
 	// if the request bodies ever get pooled until after
@@ -263,7 +273,7 @@ func (h *PromWriteHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	batchErr := h.write(r.Context(), req, opts)
+	batchErr := h.write(r.Context(), req, opts, nativeHistograms)
 
 	// Record ingestion delay latency
 	now := h.nowFn()
@@ -401,13 +411,58 @@ func (h *PromWriteHandler) parseRequest(
 	return &req, opts, result, nil
 }
 
+func parseNativeHistogramsHeader(r *http.Request) (bool, *xhttp.ParseError) {
+	v := strings.TrimSpace(r.Header.Get(handleroptions.NativeHistogramsHeader))
+	if v == "" {
+		return false, nil
+	}
+
+	nativeHistograms, err := strconv.ParseBool(v)
+	if err != nil {
+		err = fmt.Errorf("could not parse native histograms header: %v", err)
+		return false, xhttp.NewParseError(err, http.StatusBadRequest)
+	}
+	return nativeHistograms, nil
+}
+
 func (h *PromWriteHandler) write(
 	ctx context.Context,
 	r *prompb.WriteRequest,
 	opts ingest.WriteOptions,
+	nativeHistograms bool,
 ) ingest.BatchError {
-	iter := newPromTSIter(r.Timeseries, h.tagOptions)
-	return h.downsamplerAndWriter.WriteBatch(ctx, iter, opts)
+	if !nativeHistograms {
+		iter := newPromTSIter(r.Timeseries, h.tagOptions)
+		return h.downsamplerAndWriter.WriteBatch(ctx, iter, opts)
+	}
+
+	folded, unfolded := storage.FoldPromBucketSeries(r.Timeseries, h.tagOptions)
+	h.metrics.nativeHistograms.Inc(int64(len(folded)))
+
+	var multiErr xerrors.MultiError
+	iter := newPromTSIter(unfolded, h.tagOptions)
+	if err := h.downsamplerAndWriter.WriteBatch(ctx, iter, opts); err != nil {
+		for _, err := range err.Errors() {
+			multiErr = multiErr.Add(err)
+		}
+	}
+
+	// NB: Histograms are carried by annotations which the downsampler does
+	// not aggregate, so native histogram series are only written directly.
+	histogramOpts := opts
+	histogramOpts.DownsampleOverride = true
+	histogramOpts.DownsampleMappingRules = nil
+	histogramIter := newNativeHistogramIter(folded)
+	if err := h.downsamplerAndWriter.WriteBatch(ctx, histogramIter, histogramOpts); err != nil {
+		for _, err := range err.Errors() {
+			multiErr = multiErr.Add(err)
+		}
+	}
+
+	if multiErr.NumErrors() == 0 {
+		return nil
+	}
+	return multiErr
 }
 
 func (h *PromWriteHandler) forward(
@@ -489,3 +544,63 @@ func (i *promTSIter) Reset() error {
 func (i *promTSIter) Error() error {
 	return nil
 }
+
+func newNativeHistogramIter(series []storage.NativeHistogramSeries) *nativeHistogramIter {
+	// Each datapoint is written individually since the histogram of each
+	// datapoint is carried by its own annotation.
+	var numDatapoints int
+	for _, s := range series {
+		numDatapoints += len(s.Datapoints)
+	}
+
+	var (
+		tags        = make([]models.Tags, 0, numDatapoints)
+		datapoints  = make([]ts.Datapoints, 0, numDatapoints)
+		annotations = make([][]byte, 0, numDatapoints)
+	)
+	for _, s := range series {
+		for _, dp := range s.Datapoints {
+			tags = append(tags, s.Tags)
+			datapoints = append(datapoints, ts.Datapoints{
+				ts.Datapoint{Timestamp: dp.Timestamp, Value: dp.Value},
+			})
+			annotations = append(annotations, dp.Histogram.Annotation())
+		}
+	}
+
+	return &nativeHistogramIter{
+		idx:         -1,
+		tags:        tags,
+		datapoints:  datapoints,
+		annotations: annotations,
+	}
+}
+
+type nativeHistogramIter struct {
+	idx         int
+	tags        []models.Tags
+	datapoints  []ts.Datapoints
+	annotations [][]byte
+}
+
+func (i *nativeHistogramIter) Next() bool {
+	i.idx++
+	return i.idx < len(i.tags)
+}
+
+func (i *nativeHistogramIter) Current() (models.Tags, ts.Datapoints, xtime.Unit, []byte) {
+	if len(i.tags) == 0 || i.idx < 0 || i.idx >= len(i.tags) {
+		return models.EmptyTags(), nil, 0, nil
+	}
+
+	return i.tags[i.idx], i.datapoints[i.idx], xtime.Millisecond, i.annotations[i.idx]
+}
+
+func (i *nativeHistogramIter) Reset() error {
+	i.idx = -1
+	return nil
+}
+
+func (i *nativeHistogramIter) Error() error {
+	return nil
+}
@@ -22,6 +22,7 @@ package remote
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io/ioutil"
@@ -33,10 +34,12 @@ import (
 
 	"github.com/m3db/m3/src/cmd/services/m3coordinator/ingest"
 	"github.com/m3db/m3/src/cmd/services/m3query/config"
+	dts "github.com/m3db/m3/src/dbnode/ts"
 	"github.com/m3db/m3/src/metrics/policy"
 	"github.com/m3db/m3/src/query/api/v1/handler/prometheus/handleroptions"
 	"github.com/m3db/m3/src/query/api/v1/handler/prometheus/remote/test"
 	"github.com/m3db/m3/src/query/api/v1/options"
+	"github.com/m3db/m3/src/query/generated/proto/prompb"
 	"github.com/m3db/m3/src/query/models"
 	"github.com/m3db/m3/src/query/storage"
 	xclock "github.com/m3db/m3/src/x/clock"
@@ -282,3 +285,113 @@ func TestPromWriteAggregatedMetricsWithHeader(t *testing.T) {
 	resp := writer.Result()
 	require.Equal(t, http.StatusOK, resp.StatusCode)
 }
+
+func TestPromWriteNativeHistograms(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	bucketLabels := func(le string) []prompb.Label {
+		return []prompb.Label{
+			{Name: []byte("__name__"), Value: []byte("latency_bucket")},
+			{Name: []byte("le"), Value: []byte(le)},
+		}
+	}
+	promReq := &prompb.WriteRequest{
+		Timeseries: []prompb.TimeSeries{
+			{
+				Labels:  bucketLabels("0.5"),
+				Samples: []prompb.Sample{{Value: 3, Timestamp: 1000}},
+			},
+			{
+				Labels:  bucketLabels("+Inf"),
+				Samples: []prompb.Sample{{Value: 5, Timestamp: 1000}},
+			},
+			{
+				Labels: []prompb.Label{
+					{Name: []byte("__name__"), Value: []byte("latency_count")},
+				},
+				Samples: []prompb.Sample{{Value: 5, Timestamp: 1000}},
+			},
+		},
+	}
+
+	mockDownsamplerAndWriter := ingest.NewMockDownsamplerAndWriter(ctrl)
+	gomock.InOrder(
+		mockDownsamplerAndWriter.EXPECT().
+			WriteBatch(gomock.Any(), gomock.Any(), ingest.WriteOptions{}).
+			DoAndReturn(func(
+				_ context.Context,
+				iter ingest.DownsampleAndWriteIter,
+				_ ingest.WriteOptions,
+			) ingest.BatchError {
+				require.True(t, iter.Next())
+				tags, _, _, _ := iter.Current()
+				name, _ := tags.Name()
+				require.Equal(t, "latency_count", string(name))
+				require.False(t, iter.Next())
+				return nil
+			}),
+		mockDownsamplerAndWriter.EXPECT().
+			WriteBatch(gomock.Any(), gomock.Any(), ingest.WriteOptions{
+				DownsampleOverride: true,
+			}).
+			DoAndReturn(func(
+				_ context.Context,
+				iter ingest.DownsampleAndWriteIter,
+				_ ingest.WriteOptions,
+			) ingest.BatchError {
+				require.True(t, iter.Next())
+				tags, datapoints, _, annotation := iter.Current()
+				_, hasBucket := tags.Bucket()
+				require.False(t, hasBucket)
+				value, ok := tags.Get(storage.NativeHistogramTagName)
+				require.True(t, ok)
+				require.Equal(t, storage.NativeHistogramTagValue, value)
+
+				require.Equal(t, 1, len(datapoints))
+				require.Equal(t, float64(5), datapoints[0].Value)
+				h, ok, err := dts.HistogramFromAnnotation(annotation)
+				require.NoError(t, err)
+				require.True(t, ok)
+				require.Equal(t, []dts.HistogramBucket{
+					{UpperBound: 0.5, Count: 3},
+					{UpperBound: math.Inf(1), Count: 5},
+				}, h.Buckets)
+				require.False(t, iter.Next())
+				return nil
+			}),
+	)
+
+	opts := makeOptions(mockDownsamplerAndWriter)
+	writeHandler, err := NewPromWriteHandler(opts)
+	require.NoError(t, err)
+
+	promReqBody := test.GeneratePromWriteRequestBody(t, promReq)
+	req := httptest.NewRequest(PromWriteHTTPMethod, PromWriteURL, promReqBody)
+	req.Header.Add(handleroptions.NativeHistogramsHeader, "true")
+
+	writer := httptest.NewRecorder()
+	writeHandler.ServeHTTP(writer, req)
+	resp := writer.Result()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestPromWriteNativeHistogramsInvalidHeader(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDownsamplerAndWriter := ingest.NewMockDownsamplerAndWriter(ctrl)
+	opts := makeOptions(mockDownsamplerAndWriter)
+	writeHandler, err := NewPromWriteHandler(opts)
+	require.NoError(t, err)
+
+	promReq := test.GeneratePromWriteRequest()
+	promReqBody := test.GeneratePromWriteRequestBody(t, promReq)
+	req := httptest.NewRequest(PromWriteHTTPMethod, PromWriteURL, promReqBody)
+	req.Header.Add(handleroptions.NativeHistogramsHeader, "not-a-bool")
+
+	writer := httptest.NewRecorder()
+	writeHandler.ServeHTTP(writer, req)
+	resp := writer.Result()
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
@@ -27,6 +27,7 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/m3db/m3/src/m3ninx/search"
 	"github.com/m3db/m3/src/query/api/v1/handler/prometheus/handleroptions"
 	"github.com/m3db/m3/src/query/api/v1/options"
 	"github.com/m3db/m3/src/query/storage"
@@ -74,6 +75,27 @@ func (h *SearchHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if explain := r.URL.Query().Get("explain"); explain != "" {
+		ok, err := strconv.ParseBool(explain)
+		if err != nil {
+			logger.Error("unable to parse explain", zap.Error(err))
+			xhttp.Error(w, err, http.StatusBadRequest)
+			return
+		}
+
+		if ok {
+			explanation, err := h.explain(query, opts)
+			if err != nil {
+				logger.Error("unable to explain query", zap.Error(err))
+				xhttp.Error(w, err, http.StatusBadRequest)
+				return
+			}
+
+			xhttp.WriteJSONResponse(w, explanation, logger)
+			return
+		}
+	}
+
 	results, err := h.search(r.Context(), query, opts)
 	if err != nil {
 		logger.Error("unable to fetch data", zap.Error(err))
@@ -81,6 +103,7 @@ func (h *SearchHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	handleroptions.AddNextPageTokenHeader(w, results.Metadata)
 	xhttp.WriteJSONResponse(w, results, logger)
 }
 
@@ -113,6 +136,10 @@ func (h *SearchHandler) parseURLParams(r *http.Request) (*storage.FetchOptions,
 		}
 	}
 
+	if err := handleroptions.ParsePageToken(r, fetchOpts); err != nil {
+		return nil, xhttp.NewParseError(err, http.StatusBadRequest)
+	}
+
 	return fetchOpts, nil
 }
 
@@ -124,6 +151,19 @@ func (h *SearchHandler) search(
 	return h.store.SearchSeries(ctx, query, opts)
 }
 
+// explain returns how the index evaluates the query against each segment, rather
+// than the results of the query.
+func (h *SearchHandler) explain(
+	query *storage.FetchQuery,
+	opts *storage.FetchOptions,
+) (search.Explanation, error) {
+	m3query, err := storage.FetchQueryToM3Query(query, opts)
+	if err != nil {
+		return search.Explanation{}, err
+	}
+	return m3query.Explain()
+}
+
 func firstParseError(errs ...*xhttp.ParseError) *xhttp.ParseError {
 	for _, err := range errs {
 		if err != nil {
@@ -26,12 +26,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 	"time"
 
 	"github.com/m3db/m3/src/dbnode/client"
+	"github.com/m3db/m3/src/dbnode/storage/index"
 	"github.com/m3db/m3/src/query/api/v1/handler/prometheus/handleroptions"
 	"github.com/m3db/m3/src/query/api/v1/options"
 	"github.com/m3db/m3/src/query/models"
@@ -135,3 +137,67 @@ func TestSearchEndpoint(t *testing.T) {
 	defer resp.Body.Close()
 	require.NotNil(t, resp)
 }
+
+func TestSearchEndpointPaginated(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store, session := m3.NewStorageAndSession(t, ctrl)
+	session.EXPECT().FetchTaggedIDsPage(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(generateTagIters(ctrl), index.PageToken("next"), nil)
+
+	builder := handleroptions.
+		NewFetchOptionsBuilder(handleroptions.FetchOptionsBuilderOptions{})
+	opts := options.EmptyHandlerOptions().
+		SetStorage(store).SetFetchOptionsBuilder(builder)
+	server := httptest.NewServer(NewSearchHandler(opts))
+	defer server.Close()
+
+	urlWithPageToken := fmt.Sprintf("%s%s", server.URL, "?limit=1&page_token=")
+	req, _ := http.NewRequest("POST", urlWithPageToken, generateSearchBody(t))
+	req.Header.Add("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.NotEmpty(t, resp.Header.Get(handleroptions.NextPageTokenHeader))
+}
+
+func TestSearchEndpointPaginatedRequiresLimit(t *testing.T) {
+	searchHandler := searchServer(t)
+	server := httptest.NewServer(searchHandler)
+	defer server.Close()
+
+	urlWithPageToken := fmt.Sprintf("%s%s", server.URL, "?page_token=")
+	req, _ := http.NewRequest("POST", urlWithPageToken, generateSearchBody(t))
+	req.Header.Add("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestSearchEndpointExplain(t *testing.T) {
+	searchHandler := searchServer(t)
+	server := httptest.NewServer(searchHandler)
+	defer server.Close()
+
+	urlWithExplain := fmt.Sprintf("%s%s", server.URL, "?explain=true")
+	req, _ := http.NewRequest("POST", urlWithExplain, generateSearchBody(t))
+	req.Header.Add("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	body, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"query": "conjunction(term(foo, bar), term(biz, baz))",
+		"cost": "lookup",
+		"children": [
+			{"query": "term(foo, bar)", "cost": "lookup"},
+			{"query": "term(biz, baz)", "cost": "lookup"}
+		]
+	}`, string(body))
+}
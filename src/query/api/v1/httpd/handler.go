@@ -169,6 +169,12 @@ func (h *Handler) RegisterRoutes() error {
 	h.router.HandleFunc(native.PromReadInstantURL,
 		wrapped(native.NewPromReadInstantHandler(h.options)).ServeHTTP,
 	).Methods(native.PromReadInstantHTTPMethods...)
+	h.router.HandleFunc(native.RulesURL,
+		wrapped(native.NewRulesHandler(h.options)).ServeHTTP,
+	).Methods(native.RulesHTTPMethods...)
+	h.router.HandleFunc(native.AlertsURL,
+		wrapped(native.NewAlertsHandler(h.options)).ServeHTTP,
+	).Methods(native.AlertsHTTPMethods...)
 
 	// InfluxDB write endpoint.
 	h.router.HandleFunc(influxdb.InfluxWriteURL,
@@ -182,6 +188,16 @@ func (h *Handler) RegisterRoutes() error {
 		wrapped(m3json.NewWriteJSONHandler(h.options)).ServeHTTP,
 	).Methods(m3json.JSONWriteHTTPMethod)
 
+	// Index cardinality endpoint.
+	h.router.HandleFunc(handler.IndexCardinalityURL,
+		wrapped(handler.NewIndexCardinalityHandler(h.options)).ServeHTTP,
+	).Methods(handler.IndexCardinalityHTTPMethod)
+
+	// Label completion endpoint.
+	h.router.HandleFunc(handler.LabelCompletionURL,
+		wrapped(handler.NewLabelCompletionHandler(h.options)).ServeHTTP,
+	).Methods(handler.LabelCompletionHTTPMethod)
+
 	// Tag completion endpoints.
 	h.router.HandleFunc(native.CompleteTagsURL,
 		wrapped(native.NewCompleteTagsHandler(h.options)).ServeHTTP,
@@ -217,6 +233,18 @@ func (h *Handler) RegisterRoutes() error {
 		wrapped(graphite.NewFindHandler(h.options)).ServeHTTP,
 	).Methods(graphite.FindHTTPMethods...)
 
+	h.router.HandleFunc(graphite.TagsURL,
+		wrapped(graphite.NewTagsHandler(h.options)).ServeHTTP,
+	).Methods(graphite.TagsHTTPMethods...)
+
+	h.router.HandleFunc(graphite.TagsAutoCompleteTagsURL,
+		wrapped(graphite.NewTagsAutoCompleteTagsHandler(h.options)).ServeHTTP,
+	).Methods(graphite.TagsHTTPMethods...)
+
+	h.router.HandleFunc(graphite.TagsAutoCompleteValuesURL,
+		wrapped(graphite.NewTagsAutoCompleteValuesHandler(h.options)).ServeHTTP,
+	).Methods(graphite.TagsHTTPMethods...)
+
 	placementOpts, err := h.placementOpts()
 	if err != nil {
 		return err
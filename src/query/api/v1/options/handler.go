@@ -31,9 +31,11 @@ import (
 	"github.com/m3db/m3/src/cmd/services/m3query/config"
 	"github.com/m3db/m3/src/query/api/v1/handler/prometheus"
 	"github.com/m3db/m3/src/query/api/v1/handler/prometheus/handleroptions"
+	"github.com/m3db/m3/src/query/cache"
 	"github.com/m3db/m3/src/query/cost"
 	"github.com/m3db/m3/src/query/executor"
 	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/rules"
 	"github.com/m3db/m3/src/query/storage"
 	"github.com/m3db/m3/src/query/storage/m3"
 	"github.com/m3db/m3/src/x/clock"
@@ -137,6 +139,17 @@ type HandlerOptions interface {
 	// SetNowFn sets the now function.
 	SetNowFn(f clock.NowFn) HandlerOptions
 
+	// ResultsCache returns the results cache for range queries, nil if
+	// results are not cached.
+	ResultsCache() *cache.ResultsCache
+	// SetResultsCache sets the results cache for range queries.
+	SetResultsCache(c *cache.ResultsCache) HandlerOptions
+
+	// RuleManager returns the rule manager, nil if rules are not evaluated.
+	RuleManager() *rules.Manager
+	// SetRuleManager sets the rule manager.
+	SetRuleManager(m *rules.Manager) HandlerOptions
+
 	// InstrumentOpts returns the instrumentation optoins.
 	InstrumentOpts() instrument.Options
 	// SetInstrumentOpts sets instrumentation options.
@@ -163,6 +176,8 @@ type handlerOptions struct {
 	placementServiceNames []string
 	serviceOptionDefaults []handleroptions.ServiceOptionsDefault
 	nowFn                 clock.NowFn
+	resultsCache          *cache.ResultsCache
+	ruleManager           *rules.Manager
 }
 
 // EmptyHandlerOptions returns  default handler options.
@@ -406,3 +421,23 @@ func (o *handlerOptions) SetNowFn(n clock.NowFn) HandlerOptions {
 	options.nowFn = n
 	return &options
 }
+
+func (o *handlerOptions) ResultsCache() *cache.ResultsCache {
+	return o.resultsCache
+}
+
+func (o *handlerOptions) SetResultsCache(c *cache.ResultsCache) HandlerOptions {
+	options := *o
+	options.resultsCache = c
+	return &options
+}
+
+func (o *handlerOptions) RuleManager() *rules.Manager {
+	return o.ruleManager
+}
+
+func (o *handlerOptions) SetRuleManager(m *rules.Manager) HandlerOptions {
+	options := *o
+	options.ruleManager = m
+	return &options
+}
@@ -25,6 +25,7 @@ import (
 	"fmt"
 	"time"
 
+	dts "github.com/m3db/m3/src/dbnode/ts"
 	"github.com/m3db/m3/src/query/cost"
 	"github.com/m3db/m3/src/query/models"
 	xcost "github.com/m3db/m3/src/x/cost"
@@ -168,6 +169,29 @@ func NewColStep(t time.Time, values []float64) Step {
 	return ColStep{time: t, values: values}
 }
 
+// HistogramColStep is a column step that also holds native histograms.
+type HistogramColStep struct {
+	ColStep
+	histograms []dts.Histogram
+}
+
+// Histograms for the column
+func (c HistogramColStep) Histograms() []dts.Histogram {
+	return c.histograms
+}
+
+// NewHistogramColStep creates a new column step holding native histograms.
+func NewHistogramColStep(
+	t time.Time,
+	values []float64,
+	histograms []dts.Histogram,
+) HistogramStep {
+	return HistogramColStep{
+		ColStep:    ColStep{time: t, values: values},
+		histograms: histograms,
+	}
+}
+
 // NewColumnBlockBuilder creates a new column block builder
 func NewColumnBlockBuilder(
 	queryCtx *models.QueryContext,
@@ -25,6 +25,7 @@ import (
 	"fmt"
 	"time"
 
+	dts "github.com/m3db/m3/src/dbnode/ts"
 	xerrors "github.com/m3db/m3/src/x/errors"
 )
 
@@ -223,14 +224,49 @@ func (it *containerStepIter) Current() Step {
 		values: curr.Values(),
 	}
 
+	histograms := appendStepHistograms(nil, curr, 0)
 	for _, iter := range it.its[1:] {
 		curr := iter.Current()
+		histograms = appendStepHistograms(histograms, curr,
+			len(accumulatorStep.values))
 		accumulatorStep.values = append(accumulatorStep.values, curr.Values()...)
 	}
 
+	if histograms != nil {
+		return HistogramColStep{
+			ColStep:    accumulatorStep,
+			histograms: histograms,
+		}
+	}
+
 	return accumulatorStep
 }
 
+// appendStepHistograms appends the native histograms of a step following the
+// given number of series, the histograms are only allocated once a step that
+// holds native histograms is encountered.
+func appendStepHistograms(
+	histograms []dts.Histogram,
+	step Step,
+	numPrevSeries int,
+) []dts.Histogram {
+	h, ok := step.(HistogramStep)
+	if !ok {
+		if histograms == nil {
+			return nil
+		}
+
+		return append(histograms, make([]dts.Histogram, len(step.Values()))...)
+	}
+
+	if histograms == nil {
+		histograms = make([]dts.Histogram, numPrevSeries,
+			numPrevSeries+len(h.Histograms()))
+	}
+
+	return append(histograms, h.Histograms()...)
+}
+
 func (b *containerBlock) SeriesIter() (SeriesIter, error) {
 	if b.err != nil {
 		return nil, b.err
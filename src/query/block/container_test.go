@@ -22,9 +22,11 @@ package block
 
 import (
 	"fmt"
+	"math"
 	"testing"
 	"time"
 
+	dts "github.com/m3db/m3/src/dbnode/ts"
 	"github.com/m3db/m3/src/query/models"
 	"github.com/m3db/m3/src/query/ts"
 	xtest "github.com/m3db/m3/src/x/test"
@@ -181,6 +183,34 @@ func TestContainerStepIter(t *testing.T) {
 	assert.NotPanics(t, func() { it.Close() })
 }
 
+func TestContainerStepIterHistograms(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	h := dts.Histogram{Buckets: []dts.HistogramBucket{
+		{UpperBound: 1, Count: 2},
+		{UpperBound: math.Inf(1), Count: 3},
+	}}
+
+	it := NewMockStepIter(ctrl)
+	it.EXPECT().Current().Return(NewColStep(now, []float64{1, 2}))
+	itTwo := NewMockStepIter(ctrl)
+	itTwo.EXPECT().Current().Return(NewHistogramColStep(now,
+		[]float64{3}, []dts.Histogram{h}))
+
+	container := &containerStepIter{its: []StepIter{it, itTwo}}
+	step, ok := container.Current().(HistogramStep)
+	require.True(t, ok)
+	assert.Equal(t, []float64{1, 2, 3}, step.Values())
+	assert.Equal(t, []dts.Histogram{{}, {}, h}, step.Histograms())
+
+	// Steps without native histograms do not hold any histograms.
+	it.EXPECT().Current().Return(NewColStep(now, []float64{1}))
+	itTwo.EXPECT().Current().Return(NewColStep(now, []float64{2}))
+	_, ok = container.Current().(HistogramStep)
+	assert.False(t, ok)
+}
+
 func buildUnconsolidatedSeriesBlock(ctrl *gomock.Controller,
 	v float64, first bool) Block {
 	b := NewMockBlock(ctrl)
@@ -84,10 +84,20 @@ func (it *lazyStepIter) Current() Step {
 		vals = append(vals, vt(val))
 	}
 
-	return ColStep{
+	step := ColStep{
 		time:   tt(c.Time()),
 		values: vals,
 	}
+
+	// NB: Value transforms do not apply to the native histograms.
+	if h, ok := c.(HistogramStep); ok {
+		return HistogramColStep{
+			ColStep:    step,
+			histograms: h.Histograms(),
+		}
+	}
+
+	return step
 }
 
 func (b *lazyBlock) SeriesIter() (SeriesIter, error) {
@@ -65,6 +65,9 @@ type ResultMetadata struct {
 	Warnings Warnings
 	// Resolutions is a list of resolutions for series obtained by this query.
 	Resolutions []int64
+	// NextPageToken is the token to fetch the next page of a paginated query,
+	// it is nil once all pages have been returned.
+	NextPageToken []byte
 }
 
 // NewResultMetadata creates a new result metadata.
@@ -121,6 +124,13 @@ func (m ResultMetadata) CombineMetadata(other ResultMetadata) ResultMetadata {
 		Resolutions: combineResolutions(m.Resolutions, other.Resolutions),
 	}
 
+	// NB: page tokens are opaque to everything but the storage that returned
+	// them, a paginated query is only ever served by a single storage.
+	meta.NextPageToken = m.NextPageToken
+	if meta.NextPageToken == nil {
+		meta.NextPageToken = other.NextPageToken
+	}
+
 	return meta
 }
 
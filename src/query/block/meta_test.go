@@ -134,3 +134,13 @@ func TestMergeResolutions(t *testing.T) {
 	require.Equal(t, 6, len(merge.Resolutions))
 	assert.Equal(t, []int64{1, 2, 3, 4, 5, 6}, merge.Resolutions)
 }
+
+func TestMergeNextPageToken(t *testing.T) {
+	r := NewResultMetadata()
+	rTwo := NewResultMetadata()
+	assert.Nil(t, r.CombineMetadata(rTwo).NextPageToken)
+
+	rTwo.NextPageToken = []byte("next")
+	assert.Equal(t, []byte("next"), r.CombineMetadata(rTwo).NextPageToken)
+	assert.Equal(t, []byte("next"), rTwo.CombineMetadata(r).NextPageToken)
+}
@@ -24,6 +24,7 @@ import (
 	"io"
 	"time"
 
+	dts "github.com/m3db/m3/src/dbnode/ts"
 	"github.com/m3db/m3/src/query/models"
 )
 
@@ -47,6 +48,8 @@ const (
 	BlockContainer
 	// BlockEmpty is a block with metadata but no series or values.
 	BlockEmpty
+	// BlockUnconsolidated is a block of raw series datapoints held in memory.
+	BlockUnconsolidated
 	// BlockTest is a block used for testing only.
 	BlockTest
 )
@@ -133,6 +136,16 @@ type Step interface {
 	Values() []float64
 }
 
+// HistogramStep is a step of a block that holds native histogram series, in
+// addition to the value of each series it holds their native histograms.
+type HistogramStep interface {
+	Step
+	// Histograms returns the native histogram of each series at this step,
+	// series without a native histogram at this step have a histogram with
+	// no buckets.
+	Histograms() []dts.Histogram
+}
+
 // Builder builds Blocks.
 type Builder interface {
 	// AddCols adds the given number of columns to the block.
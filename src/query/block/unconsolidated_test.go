@@ -0,0 +1,119 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package block
+
+import (
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/ts"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func buildUnconsolidatedTestSeries(count int) []UnconsolidatedSeries {
+	series := make([]UnconsolidatedSeries, 0, count)
+	for i := 0; i < count; i++ {
+		dps := ts.Datapoints{
+			{Timestamp: start.Add(time.Second), Value: float64(i)},
+			{Timestamp: start.Add(time.Second * 30), Value: float64(i * 10)},
+		}
+
+		series = append(series, NewUnconsolidatedSeries(dps, SeriesMeta{
+			Name: []byte{byte('a' + i)},
+			Tags: models.MustMakeTags("a", string([]byte{byte('a' + i)})),
+		}))
+	}
+
+	return series
+}
+
+func TestUnconsolidatedBlock(t *testing.T) {
+	meta := Metadata{
+		Tags:   models.MustMakeTags("a", "b"),
+		Bounds: testBound,
+	}
+
+	series := buildUnconsolidatedTestSeries(3)
+	bl := NewUnconsolidatedBlock(meta, series)
+	assert.True(t, meta.Equals(bl.Meta()))
+	assert.Equal(t, BlockUnconsolidated, bl.Info().Type())
+
+	_, err := bl.StepIter()
+	assert.Error(t, err)
+
+	iter, err := bl.SeriesIter()
+	require.NoError(t, err)
+	assert.Equal(t, 3, iter.SeriesCount())
+	assert.Equal(t, []SeriesMeta{
+		series[0].Meta, series[1].Meta, series[2].Meta,
+	}, iter.SeriesMeta())
+
+	var actual []UnconsolidatedSeries
+	for iter.Next() {
+		actual = append(actual, iter.Current())
+	}
+
+	assert.NoError(t, iter.Err())
+	assert.Equal(t, series, actual)
+	assert.NoError(t, bl.Close())
+}
+
+func TestUnconsolidatedBlockMultiSeriesIter(t *testing.T) {
+	meta := Metadata{Bounds: testBound}
+	series := buildUnconsolidatedTestSeries(5)
+	bl := NewUnconsolidatedBlock(meta, series)
+
+	_, err := bl.MultiSeriesIter(0)
+	assert.Error(t, err)
+
+	batches, err := bl.MultiSeriesIter(3)
+	require.NoError(t, err)
+	require.Equal(t, 3, len(batches))
+
+	var actual []UnconsolidatedSeries
+	for i, expectedSize := range []int{2, 2, 1} {
+		batch := batches[i]
+		assert.Equal(t, expectedSize, batch.Size)
+		assert.Equal(t, expectedSize, batch.Iter.SeriesCount())
+		for batch.Iter.Next() {
+			actual = append(actual, batch.Iter.Current())
+		}
+
+		assert.NoError(t, batch.Iter.Err())
+	}
+
+	assert.Equal(t, series, actual)
+
+	batches, err = bl.MultiSeriesIter(8)
+	require.NoError(t, err)
+	require.Equal(t, 8, len(batches))
+	for i, batch := range batches {
+		if i < len(series) {
+			assert.Equal(t, 1, batch.Size)
+		} else {
+			assert.Equal(t, 0, batch.Size)
+			assert.False(t, batch.Iter.Next())
+		}
+	}
+}
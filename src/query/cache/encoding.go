@@ -0,0 +1,239 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cache
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/m3db/m3/src/query/models"
+)
+
+const entryVersion = 1
+
+var errEntryTruncated = errors.New("cache entry truncated")
+
+// entry is the cached result of a query over a single interval.
+type entry struct {
+	start  time.Time
+	step   time.Duration
+	steps  int
+	series []entrySeries
+}
+
+// entrySeries is a series within an entry, with a value for each step.
+type entrySeries struct {
+	name   []byte
+	tags   models.Tags
+	values []float64
+}
+
+func encodeEntry(e entry) []byte {
+	enc := entryEncoder{buf: make([]byte, 0, 64)}
+	enc.buf = append(enc.buf, entryVersion)
+	enc.varint(e.start.UnixNano())
+	enc.varint(int64(e.step))
+	enc.uvarint(uint64(e.steps))
+	enc.uvarint(uint64(len(e.series)))
+	for _, s := range e.series {
+		enc.bytes(s.name)
+		enc.uvarint(uint64(len(s.tags.Tags)))
+		for _, tag := range s.tags.Tags {
+			enc.bytes(tag.Name)
+			enc.bytes(tag.Value)
+		}
+
+		for _, v := range s.values {
+			var scratch [8]byte
+			binary.LittleEndian.PutUint64(scratch[:], math.Float64bits(v))
+			enc.buf = append(enc.buf, scratch[:]...)
+		}
+	}
+
+	return enc.buf
+}
+
+func decodeEntry(b []byte, tagOpts models.TagOptions) (entry, error) {
+	if len(b) == 0 {
+		return entry{}, errEntryTruncated
+	}
+
+	if b[0] != entryVersion {
+		return entry{}, fmt.Errorf("unknown cache entry version: %d", b[0])
+	}
+
+	var (
+		dec         = entryDecoder{buf: b[1:]}
+		start       = dec.varint()
+		step        = dec.varint()
+		steps       = dec.uvarint()
+		seriesCount = dec.uvarint()
+	)
+
+	if dec.err != nil {
+		return entry{}, dec.err
+	}
+
+	// NB: guard against allocating for corrupt counts, every series takes at
+	// least a byte per step.
+	if seriesCount > uint64(len(dec.buf)) || steps > uint64(len(dec.buf)) {
+		return entry{}, errEntryTruncated
+	}
+
+	e := entry{
+		start:  time.Unix(0, start),
+		step:   time.Duration(step),
+		steps:  int(steps),
+		series: make([]entrySeries, 0, seriesCount),
+	}
+
+	for i := uint64(0); i < seriesCount; i++ {
+		name := dec.bytes()
+		tagCount := dec.uvarint()
+		if dec.err != nil {
+			return entry{}, dec.err
+		}
+
+		if tagCount > uint64(len(dec.buf)) {
+			return entry{}, errEntryTruncated
+		}
+
+		tags := models.NewTags(int(tagCount), tagOpts)
+		for j := uint64(0); j < tagCount; j++ {
+			tags = tags.AddTagWithoutNormalizing(models.Tag{
+				Name:  dec.bytes(),
+				Value: dec.bytes(),
+			})
+		}
+
+		values := make([]float64, 0, e.steps)
+		for j := 0; j < e.steps; j++ {
+			values = append(values, dec.float64())
+		}
+
+		if dec.err != nil {
+			return entry{}, dec.err
+		}
+
+		e.series = append(e.series, entrySeries{
+			name:   name,
+			tags:   tags,
+			values: values,
+		})
+	}
+
+	if len(dec.buf) != 0 {
+		return entry{}, fmt.Errorf("cache entry has %d trailing bytes",
+			len(dec.buf))
+	}
+
+	return e, nil
+}
+
+type entryEncoder struct {
+	buf []byte
+}
+
+func (e *entryEncoder) uvarint(v uint64) {
+	var scratch [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(scratch[:], v)
+	e.buf = append(e.buf, scratch[:n]...)
+}
+
+func (e *entryEncoder) varint(v int64) {
+	var scratch [binary.MaxVarintLen64]byte
+	n := binary.PutVarint(scratch[:], v)
+	e.buf = append(e.buf, scratch[:n]...)
+}
+
+func (e *entryEncoder) bytes(b []byte) {
+	e.uvarint(uint64(len(b)))
+	e.buf = append(e.buf, b...)
+}
+
+type entryDecoder struct {
+	buf []byte
+	err error
+}
+
+func (d *entryDecoder) uvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+
+	v, n := binary.Uvarint(d.buf)
+	if n <= 0 {
+		d.err = errEntryTruncated
+		return 0
+	}
+
+	d.buf = d.buf[n:]
+	return v
+}
+
+func (d *entryDecoder) varint() int64 {
+	if d.err != nil {
+		return 0
+	}
+
+	v, n := binary.Varint(d.buf)
+	if n <= 0 {
+		d.err = errEntryTruncated
+		return 0
+	}
+
+	d.buf = d.buf[n:]
+	return v
+}
+
+func (d *entryDecoder) bytes() []byte {
+	length := d.uvarint()
+	if d.err != nil {
+		return nil
+	}
+
+	if length > uint64(len(d.buf)) {
+		d.err = errEntryTruncated
+		return nil
+	}
+
+	b := d.buf[:length]
+	d.buf = d.buf[length:]
+	return b
+}
+
+func (d *entryDecoder) float64() float64 {
+	if d.err != nil {
+		return 0
+	}
+
+	if len(d.buf) < 8 {
+		d.err = errEntryTruncated
+		return 0
+	}
+
+	v := math.Float64frombits(binary.LittleEndian.Uint64(d.buf))
+	d.buf = d.buf[8:]
+	return v
+}
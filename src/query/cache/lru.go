@@ -0,0 +1,112 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+)
+
+type lruBackend struct {
+	mu       sync.Mutex
+	maxBytes int
+	size     int
+	list     *list.List
+	entries  map[string]*list.Element
+}
+
+type lruEntry struct {
+	key   string
+	value []byte
+}
+
+func (e *lruEntry) size() int {
+	return len(e.key) + len(e.value)
+}
+
+// NewLRUBackend creates an in-memory backend which evicts the least recently
+// used values once the total size of the stored keys and values exceeds the
+// given number of bytes.
+func NewLRUBackend(maxBytes int) Backend {
+	return &lruBackend{
+		maxBytes: maxBytes,
+		list:     list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+func (b *lruBackend) Fetch(
+	_ context.Context,
+	keys []string,
+) (map[string][]byte, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	values := make(map[string][]byte, len(keys))
+	for _, key := range keys {
+		elem, ok := b.entries[key]
+		if !ok {
+			continue
+		}
+
+		b.list.MoveToFront(elem)
+		values[key] = elem.Value.(*lruEntry).value
+	}
+
+	return values, nil
+}
+
+func (b *lruBackend) Store(
+	_ context.Context,
+	values map[string][]byte,
+) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for key, value := range values {
+		entry := &lruEntry{key: key, value: value}
+		if elem, ok := b.entries[key]; ok {
+			b.remove(elem)
+		}
+
+		// NB: values that can never fit are not stored, rather than evicting
+		// everything else.
+		if entry.size() > b.maxBytes {
+			continue
+		}
+
+		b.entries[key] = b.list.PushFront(entry)
+		b.size += entry.size()
+	}
+
+	for b.size > b.maxBytes {
+		b.remove(b.list.Back())
+	}
+
+	return nil
+}
+
+func (b *lruBackend) remove(elem *list.Element) {
+	entry := b.list.Remove(elem).(*lruEntry)
+	delete(b.entries, entry.key)
+	b.size -= entry.size()
+}
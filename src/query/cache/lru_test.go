@@ -0,0 +1,95 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cache
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLRUBackend(t *testing.T) {
+	ctx := context.Background()
+	// NB: each key and value pair below is 4 bytes.
+	backend := NewLRUBackend(12)
+
+	require.NoError(t, backend.Store(ctx, map[string][]byte{
+		"a": []byte("aaa"),
+		"b": []byte("bbb"),
+		"c": []byte("ccc"),
+	}))
+
+	values, err := backend.Fetch(ctx, []string{"a", "b", "c", "d"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]byte{
+		"a": []byte("aaa"),
+		"b": []byte("bbb"),
+		"c": []byte("ccc"),
+	}, values)
+
+	// Use "a" so that "b" is the least recently used value.
+	_, err = backend.Fetch(ctx, []string{"c"})
+	require.NoError(t, err)
+	_, err = backend.Fetch(ctx, []string{"a"})
+	require.NoError(t, err)
+
+	require.NoError(t, backend.Store(ctx, map[string][]byte{
+		"d": []byte("ddd"),
+	}))
+
+	values, err = backend.Fetch(ctx, []string{"a", "b", "c", "d"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]byte{
+		"a": []byte("aaa"),
+		"c": []byte("ccc"),
+		"d": []byte("ddd"),
+	}, values)
+
+	// Replacing a value accounts for the size of the replaced value.
+	require.NoError(t, backend.Store(ctx, map[string][]byte{
+		"a": []byte("aaaaaaa"),
+	}))
+
+	values, err = backend.Fetch(ctx, []string{"a", "b", "c", "d"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]byte{
+		"a": []byte("aaaaaaa"),
+		"d": []byte("ddd"),
+	}, values)
+}
+
+func TestLRUBackendSkipsOversizedValues(t *testing.T) {
+	ctx := context.Background()
+	backend := NewLRUBackend(8)
+
+	require.NoError(t, backend.Store(ctx, map[string][]byte{
+		"a": []byte("aaa"),
+	}))
+	require.NoError(t, backend.Store(ctx, map[string][]byte{
+		"b": []byte("bbbbbbbbbbbb"),
+	}))
+
+	values, err := backend.Fetch(ctx, []string{"a", "b"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]byte{"a": []byte("aaa")}, values)
+}
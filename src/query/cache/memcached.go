@@ -0,0 +1,77 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cache
+
+import (
+	"context"
+	"time"
+
+	xerrors "github.com/m3db/m3/src/x/errors"
+)
+
+// MemcachedClient is a client for a memcached-compatible store, which most
+// memcached client libraries satisfy with a thin adapter.
+type MemcachedClient interface {
+	// GetMulti returns the values for any of the given keys that are present
+	// in the store.
+	GetMulti(keys []string) (map[string][]byte, error)
+	// Set stores the value for the given key, expiring it after the given
+	// duration.
+	Set(key string, value []byte, expiration time.Duration) error
+}
+
+type memcachedBackend struct {
+	client     MemcachedClient
+	expiration time.Duration
+}
+
+// NewMemcachedBackend creates a backend for a memcached-compatible store,
+// storing values with the given expiration.
+func NewMemcachedBackend(
+	client MemcachedClient,
+	expiration time.Duration,
+) Backend {
+	return &memcachedBackend{
+		client:     client,
+		expiration: expiration,
+	}
+}
+
+func (b *memcachedBackend) Fetch(
+	_ context.Context,
+	keys []string,
+) (map[string][]byte, error) {
+	return b.client.GetMulti(keys)
+}
+
+func (b *memcachedBackend) Store(
+	_ context.Context,
+	values map[string][]byte,
+) error {
+	multiErr := xerrors.NewMultiError()
+	for key, value := range values {
+		if err := b.client.Set(key, value, b.expiration); err != nil {
+			multiErr = multiErr.Add(err)
+		}
+	}
+
+	return multiErr.FinalError()
+}
@@ -0,0 +1,82 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testMemcachedClient struct {
+	values      map[string][]byte
+	expirations map[string]time.Duration
+}
+
+func (c *testMemcachedClient) GetMulti(
+	keys []string,
+) (map[string][]byte, error) {
+	values := make(map[string][]byte)
+	for _, key := range keys {
+		if value, ok := c.values[key]; ok {
+			values[key] = value
+		}
+	}
+
+	return values, nil
+}
+
+func (c *testMemcachedClient) Set(
+	key string,
+	value []byte,
+	expiration time.Duration,
+) error {
+	c.values[key] = value
+	c.expirations[key] = expiration
+	return nil
+}
+
+func TestMemcachedBackend(t *testing.T) {
+	var (
+		ctx    = context.Background()
+		client = &testMemcachedClient{
+			values:      make(map[string][]byte),
+			expirations: make(map[string]time.Duration),
+		}
+		backend = NewMemcachedBackend(client, time.Hour)
+	)
+
+	require.NoError(t, backend.Store(ctx, map[string][]byte{
+		"a": []byte("aaa"),
+		"b": []byte("bbb"),
+	}))
+	assert.Equal(t, map[string]time.Duration{
+		"a": time.Hour,
+		"b": time.Hour,
+	}, client.expirations)
+
+	values, err := backend.Fetch(ctx, []string{"a", "c"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]byte{"a": []byte("aaa")}, values)
+}
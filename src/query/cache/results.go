@@ -0,0 +1,549 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/m3db/m3/src/query/block"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/ts"
+	"github.com/m3db/m3/src/x/clock"
+	"github.com/m3db/m3/src/x/instrument"
+
+	"github.com/uber-go/tally"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultSplitInterval = 24 * time.Hour
+	defaultMaxFreshness  = 10 * time.Minute
+)
+
+var errNoBackend = errors.New("results cache requires a backend")
+
+// ResultsCacheOptions are the options for a results cache.
+type ResultsCacheOptions struct {
+	// Backend stores the cached results.
+	Backend Backend
+	// SplitInterval is the interval range queries are split into, each
+	// completed interval is cached separately. Defaults to a day.
+	SplitInterval time.Duration
+	// MaxFreshness is how far back from now an interval must end before its
+	// results are considered immutable and are cached. Defaults to ten
+	// minutes.
+	MaxFreshness time.Duration
+	// TagOptions are the tag options for decoded series.
+	TagOptions models.TagOptions
+	// InstrumentOptions are the instrument options.
+	InstrumentOptions instrument.Options
+	// NowFn is the function used to get the current time, defaults to
+	// time.Now.
+	NowFn clock.NowFn
+}
+
+// ResultsCache caches the results of range queries. Queries are split into
+// step aligned intervals, the results of intervals that have completed are
+// cached and only the intervals that are missing or too recent to be cached
+// are read.
+type ResultsCache struct {
+	backend       Backend
+	splitInterval time.Duration
+	maxFreshness  time.Duration
+	tagOpts       models.TagOptions
+	nowFn         clock.NowFn
+	logger        *zap.Logger
+	metrics       resultsCacheMetrics
+}
+
+type resultsCacheMetrics struct {
+	hits        tally.Counter
+	misses      tally.Counter
+	uncacheable tally.Counter
+	fetchErrors tally.Counter
+	storeErrors tally.Counter
+}
+
+func newResultsCacheMetrics(scope tally.Scope) resultsCacheMetrics {
+	return resultsCacheMetrics{
+		hits:        scope.Counter("hits"),
+		misses:      scope.Counter("misses"),
+		uncacheable: scope.Counter("uncacheable"),
+		fetchErrors: scope.Counter("fetch-errors"),
+		storeErrors: scope.Counter("store-errors"),
+	}
+}
+
+// NewResultsCache creates a new results cache.
+func NewResultsCache(opts ResultsCacheOptions) (*ResultsCache, error) {
+	if opts.Backend == nil {
+		return nil, errNoBackend
+	}
+
+	if opts.SplitInterval < 0 {
+		return nil, fmt.Errorf("results cache split interval must be "+
+			"positive, got %v", opts.SplitInterval)
+	}
+
+	if opts.MaxFreshness < 0 {
+		return nil, fmt.Errorf("results cache max freshness must not be "+
+			"negative, got %v", opts.MaxFreshness)
+	}
+
+	if opts.SplitInterval == 0 {
+		opts.SplitInterval = defaultSplitInterval
+	}
+
+	if opts.MaxFreshness == 0 {
+		opts.MaxFreshness = defaultMaxFreshness
+	}
+
+	if opts.TagOptions == nil {
+		opts.TagOptions = models.NewTagOptions()
+	}
+
+	if opts.InstrumentOptions == nil {
+		opts.InstrumentOptions = instrument.NewOptions()
+	}
+
+	if opts.NowFn == nil {
+		opts.NowFn = time.Now
+	}
+
+	return &ResultsCache{
+		backend:       opts.Backend,
+		splitInterval: opts.SplitInterval,
+		maxFreshness:  opts.MaxFreshness,
+		tagOpts:       opts.TagOptions,
+		nowFn:         opts.NowFn,
+		logger:        opts.InstrumentOptions.Logger(),
+		metrics: newResultsCacheMetrics(
+			opts.InstrumentOptions.MetricsScope()),
+	}, nil
+}
+
+// interval is a split interval of a query.
+type interval struct {
+	start     time.Time
+	end       time.Time
+	immutable bool
+	key       string
+	entry     *entry
+}
+
+// Read returns the result of the range query with the given params, reading
+// any intervals that are not cached with the given read function. The key
+// must uniquely identify the query and any options other than the start, end
+// and step that affect its result. If limit is positive, at most limit series
+// are returned once the intervals are merged, since each read of intervals
+// missing from the cache only limits the series of that read.
+func (c *ResultsCache) Read(
+	ctx context.Context,
+	key string,
+	params models.RequestParams,
+	limit int,
+	readFn ReadFn,
+) (Result, error) {
+	step := params.Step
+	if step <= 0 ||
+		params.Start.UnixNano()%int64(step) != 0 ||
+		c.splitInterval%step != 0 {
+		// NB: steps only line up across queries, and with the split
+		// intervals, if the start is aligned to the step and the step
+		// divides the split interval.
+		c.metrics.uncacheable.Inc(1)
+		return readFn(ctx, params)
+	}
+
+	var (
+		start     = params.Start
+		end       = params.ExclusiveEnd()
+		intervals = c.intervals(key, start, end, step)
+	)
+
+	c.fetch(ctx, intervals, step)
+
+	meta := block.NewResultMetadata()
+	merged := newSeriesMerger(start, end, step)
+	toStore := make(map[string][]byte)
+	for i := 0; i < len(intervals); {
+		if intervals[i].entry != nil {
+			merged.addEntry(*intervals[i].entry)
+			i++
+			continue
+		}
+
+		// Read contiguous intervals that are missing from the cache at once.
+		j := i
+		for j < len(intervals) && intervals[j].entry == nil {
+			j++
+		}
+
+		span := intervals[i:j]
+		i = j
+
+		spanParams := params
+		spanParams.Start = maxTime(start, span[0].start)
+		if span[0].immutable {
+			// NB: read the whole of intervals that will be cached.
+			spanParams.Start = span[0].start
+		}
+
+		last := span[len(span)-1]
+		spanParams.End = minTime(end, last.end)
+		if last.immutable {
+			spanParams.End = last.end
+		}
+
+		spanParams.IncludeEnd = false
+		result, err := readFn(ctx, spanParams)
+		if err != nil {
+			return Result{}, err
+		}
+
+		meta = meta.CombineMetadata(result.Meta)
+		merged.addSeries(result.Series)
+
+		if !result.Meta.Exhaustive || len(result.Meta.Warnings) > 0 {
+			// NB: partial results must not be cached.
+			continue
+		}
+
+		for _, in := range span {
+			if in.immutable {
+				toStore[in.key] = encodeEntry(
+					newEntry(in.start, in.end, step, result.Series))
+			}
+		}
+	}
+
+	if len(toStore) > 0 {
+		if err := c.backend.Store(ctx, toStore); err != nil {
+			c.metrics.storeErrors.Inc(1)
+			c.logger.Warn("unable to store results in cache", zap.Error(err))
+		}
+	}
+
+	series := merged.series()
+	if limit > 0 && len(series) > limit {
+		series = series[:limit]
+		meta.Exhaustive = false
+	}
+
+	return Result{
+		Series: series,
+		Meta:   meta,
+	}, nil
+}
+
+func (c *ResultsCache) intervals(
+	key string,
+	start time.Time,
+	end time.Time,
+	step time.Duration,
+) []interval {
+	var (
+		split           = int64(c.splitInterval)
+		startNanos      = start.UnixNano()
+		intervalNanos   = startNanos - startNanos%split
+		immutableBefore = c.nowFn().Add(-c.maxFreshness)
+		prefix          = c.keyPrefix(key, step)
+		intervals       []interval
+	)
+
+	if startNanos < 0 && startNanos%split != 0 {
+		intervalNanos -= split
+	}
+
+	for ; intervalNanos < end.UnixNano(); intervalNanos += split {
+		in := interval{
+			start: time.Unix(0, intervalNanos),
+			end:   time.Unix(0, intervalNanos+split),
+		}
+
+		in.immutable = !in.end.After(immutableBefore)
+		if in.immutable {
+			in.key = prefix + strconv.FormatInt(intervalNanos, 10)
+		}
+
+		intervals = append(intervals, in)
+	}
+
+	return intervals
+}
+
+func (c *ResultsCache) keyPrefix(key string, step time.Duration) string {
+	h := sha256.New()
+	// NB: hash.Hash never returns an error on write.
+	_, _ = h.Write([]byte(key))
+	_, _ = h.Write([]byte("|" + step.String() + "|" + c.splitInterval.String()))
+	return hex.EncodeToString(h.Sum(nil)) + ":"
+}
+
+// fetch sets the entries of any cached intervals.
+func (c *ResultsCache) fetch(
+	ctx context.Context,
+	intervals []interval,
+	step time.Duration,
+) {
+	keys := make([]string, 0, len(intervals))
+	for _, in := range intervals {
+		if in.immutable {
+			keys = append(keys, in.key)
+		}
+	}
+
+	if len(keys) == 0 {
+		return
+	}
+
+	values, err := c.backend.Fetch(ctx, keys)
+	if err != nil {
+		c.metrics.fetchErrors.Inc(1)
+		c.logger.Warn("unable to fetch results from cache", zap.Error(err))
+		values = nil
+	}
+
+	var (
+		hits  int64
+		steps = int(c.splitInterval / step)
+	)
+
+	for i, in := range intervals {
+		value, ok := values[in.key]
+		if !in.immutable || !ok {
+			continue
+		}
+
+		e, err := decodeEntry(value, c.tagOpts)
+		if err != nil {
+			c.logger.Warn("unable to decode cached results", zap.Error(err))
+			continue
+		}
+
+		if !e.start.Equal(in.start) || e.step != step || e.steps != steps {
+			continue
+		}
+
+		intervals[i].entry = &e
+		hits++
+	}
+
+	c.metrics.hits.Inc(hits)
+	c.metrics.misses.Inc(int64(len(keys)) - hits)
+}
+
+// newEntry creates an entry from the values of the given series within the
+// interval, skipping any series without values in it.
+func newEntry(
+	start time.Time,
+	end time.Time,
+	step time.Duration,
+	series []*ts.Series,
+) entry {
+	var (
+		steps   = int(end.Sub(start) / step)
+		entries = make([]entrySeries, 0, len(series))
+	)
+
+	for _, s := range series {
+		var (
+			values = make([]float64, steps)
+			found  bool
+		)
+
+		for i := range values {
+			values[i] = math.NaN()
+		}
+
+		vals := s.Values()
+		for i := 0; i < vals.Len(); i++ {
+			dp := vals.DatapointAt(i)
+			idx, ok := stepIndex(dp.Timestamp, start, end, step)
+			if !ok || math.IsNaN(dp.Value) {
+				continue
+			}
+
+			values[idx] = dp.Value
+			found = true
+		}
+
+		if !found {
+			continue
+		}
+
+		entries = append(entries, entrySeries{
+			name:   s.Name(),
+			tags:   s.Tags,
+			values: values,
+		})
+	}
+
+	return entry{
+		start:  start,
+		step:   step,
+		steps:  steps,
+		series: entries,
+	}
+}
+
+// seriesMerger merges series by ID into series with a value at each step of
+// the query.
+type seriesMerger struct {
+	start  time.Time
+	end    time.Time
+	step   time.Duration
+	steps  int
+	merged map[string]*mergedSeries
+}
+
+type mergedSeries struct {
+	id     string
+	name   []byte
+	tags   models.Tags
+	values ts.FixedResolutionMutableValues
+}
+
+func newSeriesMerger(
+	start time.Time,
+	end time.Time,
+	step time.Duration,
+) *seriesMerger {
+	return &seriesMerger{
+		start:  start,
+		end:    end,
+		step:   step,
+		steps:  int(end.Sub(start) / step),
+		merged: make(map[string]*mergedSeries),
+	}
+}
+
+func (m *seriesMerger) get(name []byte, tags models.Tags) *mergedSeries {
+	id := string(tags.ID())
+	if s, ok := m.merged[id]; ok {
+		return s
+	}
+
+	s := &mergedSeries{
+		id:     id,
+		name:   name,
+		tags:   tags,
+		values: ts.NewFixedStepValues(m.step, m.steps, math.NaN(), m.start),
+	}
+
+	m.merged[id] = s
+	return s
+}
+
+func (m *seriesMerger) set(s *mergedSeries, t time.Time, v float64) {
+	if math.IsNaN(v) {
+		return
+	}
+
+	if idx, ok := stepIndex(t, m.start, m.end, m.step); ok {
+		s.values.SetValueAt(idx, v)
+	}
+}
+
+func (m *seriesMerger) addEntry(e entry) {
+	for _, es := range e.series {
+		s := m.get(es.name, es.tags)
+		for i, v := range es.values {
+			m.set(s, e.start.Add(time.Duration(i)*e.step), v)
+		}
+	}
+}
+
+func (m *seriesMerger) addSeries(series []*ts.Series) {
+	for _, rs := range series {
+		var (
+			s    = m.get(rs.Name(), rs.Tags)
+			vals = rs.Values()
+		)
+
+		for i := 0; i < vals.Len(); i++ {
+			dp := vals.DatapointAt(i)
+			m.set(s, dp.Timestamp, dp.Value)
+		}
+	}
+}
+
+func (m *seriesMerger) series() []*ts.Series {
+	merged := make([]*mergedSeries, 0, len(m.merged))
+	for _, s := range m.merged {
+		merged = append(merged, s)
+	}
+
+	sort.Slice(merged, func(i, j int) bool {
+		return merged[i].id < merged[j].id
+	})
+
+	series := make([]*ts.Series, 0, len(merged))
+	for _, s := range merged {
+		series = append(series, ts.NewSeries(s.name, s.values, s.tags))
+	}
+
+	return series
+}
+
+// stepIndex returns the index of the step at the given time within
+// [start, end), if the time falls on a step.
+func stepIndex(
+	t time.Time,
+	start time.Time,
+	end time.Time,
+	step time.Duration,
+) (int, bool) {
+	if t.Before(start) || !t.Before(end) {
+		return 0, false
+	}
+
+	offset := t.Sub(start)
+	if offset%step != 0 {
+		return 0, false
+	}
+
+	return int(offset / step), true
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+
+	return b
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+
+	return b
+}
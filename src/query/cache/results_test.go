@@ -0,0 +1,380 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cache
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/query/block"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/ts"
+	"github.com/m3db/m3/src/x/instrument"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
+)
+
+var (
+	testDay   = time.Unix(0, 0).Add(100 * 24 * time.Hour)
+	testNames = []string{"a", "b"}
+)
+
+type testReader struct {
+	calls []models.RequestParams
+	meta  block.ResultMetadata
+	err   error
+}
+
+func newTestReader() *testReader {
+	return &testReader{meta: block.NewResultMetadata()}
+}
+
+func testValue(name string, t time.Time) float64 {
+	return float64(t.Unix()) + float64(name[0])
+}
+
+// read returns a series for each test name, series "b" only has values at
+// even hours.
+func (r *testReader) read(
+	_ context.Context,
+	params models.RequestParams,
+) (Result, error) {
+	r.calls = append(r.calls, params)
+	if r.err != nil {
+		return Result{}, r.err
+	}
+
+	var (
+		end    = params.ExclusiveEnd()
+		steps  = int(end.Sub(params.Start) / params.Step)
+		series = make([]*ts.Series, 0, len(testNames))
+	)
+
+	for _, name := range testNames {
+		values := ts.NewFixedStepValues(params.Step, steps, math.NaN(),
+			params.Start)
+		for i := 0; i < steps; i++ {
+			t := params.Start.Add(time.Duration(i) * params.Step)
+			if name == "b" && t.Hour()%2 != 0 {
+				continue
+			}
+
+			values.SetValueAt(i, testValue(name, t))
+		}
+
+		tags := models.NewTags(1, models.NewTagOptions()).
+			AddTag(models.Tag{Name: []byte("name"), Value: []byte(name)})
+		series = append(series, ts.NewSeries([]byte(name), values, tags))
+	}
+
+	return Result{Series: series, Meta: r.meta}, nil
+}
+
+func newTestResultsCache(
+	t *testing.T,
+	backend Backend,
+	now time.Time,
+) (*ResultsCache, tally.TestScope) {
+	scope := tally.NewTestScope("", nil)
+	cache, err := NewResultsCache(ResultsCacheOptions{
+		Backend:           backend,
+		InstrumentOptions: instrument.NewOptions().SetMetricsScope(scope),
+		NowFn:             func() time.Time { return now },
+	})
+	require.NoError(t, err)
+	return cache, scope
+}
+
+func testParams(start, end time.Time) models.RequestParams {
+	return models.RequestParams{
+		Start:      start,
+		End:        end,
+		Step:       time.Hour,
+		IncludeEnd: true,
+	}
+}
+
+func assertResult(
+	t *testing.T,
+	params models.RequestParams,
+	result Result,
+) {
+	steps := int(params.ExclusiveEnd().Sub(params.Start) / params.Step)
+	require.Equal(t, len(testNames), len(result.Series))
+	for i, name := range testNames {
+		s := result.Series[i]
+		assert.Equal(t, name, string(s.Name()))
+		require.Equal(t, steps, s.Len())
+		for j := 0; j < steps; j++ {
+			dp := s.Values().DatapointAt(j)
+			assert.Equal(t, params.Start.Add(time.Duration(j)*params.Step),
+				dp.Timestamp)
+			if name == "b" && dp.Timestamp.Hour()%2 != 0 {
+				assert.True(t, math.IsNaN(dp.Value))
+				continue
+			}
+
+			assert.Equal(t, testValue(name, dp.Timestamp), dp.Value)
+		}
+	}
+}
+
+func counter(scope tally.TestScope, name string) int64 {
+	c, ok := scope.Snapshot().Counters()[name+"+"]
+	if !ok {
+		return 0
+	}
+
+	return c.Value()
+}
+
+func TestResultsCacheOnlyReadsTail(t *testing.T) {
+	var (
+		ctx        = context.Background()
+		now        = testDay.Add(2*24*time.Hour + 12*time.Hour)
+		cache, sc  = newTestResultsCache(t, NewLRUBackend(1<<20), now)
+		reader     = newTestReader()
+		params     = testParams(testDay.Add(6*time.Hour), now.Add(-time.Hour))
+		cacheStart = testDay
+	)
+
+	result, err := cache.Read(ctx, "query", params, 0, reader.read)
+	require.NoError(t, err)
+	assertResult(t, params, result)
+
+	// The first read is missing every interval, and reads the whole of the
+	// completed intervals so that they can be cached.
+	require.Equal(t, 1, len(reader.calls))
+	assert.Equal(t, cacheStart, reader.calls[0].Start)
+	assert.Equal(t, now, reader.calls[0].End)
+	assert.False(t, reader.calls[0].IncludeEnd)
+	assert.Equal(t, int64(0), counter(sc, "hits"))
+	assert.Equal(t, int64(2), counter(sc, "misses"))
+
+	reader.calls = nil
+	result, err = cache.Read(ctx, "query", params, 0, reader.read)
+	require.NoError(t, err)
+	assertResult(t, params, result)
+
+	// The second read only reads the interval that is too recent to cache.
+	require.Equal(t, 1, len(reader.calls))
+	assert.Equal(t, testDay.Add(2*24*time.Hour), reader.calls[0].Start)
+	assert.Equal(t, now, reader.calls[0].End)
+	assert.Equal(t, int64(2), counter(sc, "hits"))
+
+	// A query for a different range of completed intervals is served from
+	// the cache.
+	reader.calls = nil
+	params = testParams(testDay.Add(time.Hour), testDay.Add(30*time.Hour))
+	result, err = cache.Read(ctx, "query", params, 0, reader.read)
+	require.NoError(t, err)
+	assertResult(t, params, result)
+	assert.Equal(t, 0, len(reader.calls))
+
+	// A different query is not.
+	result, err = cache.Read(ctx, "other", params, 0, reader.read)
+	require.NoError(t, err)
+	assertResult(t, params, result)
+	assert.Equal(t, 1, len(reader.calls))
+}
+
+func TestResultsCacheReadsMissingIntervals(t *testing.T) {
+	var (
+		ctx       = context.Background()
+		now       = testDay.Add(10 * 24 * time.Hour)
+		cache, _  = newTestResultsCache(t, NewLRUBackend(1<<20), now)
+		reader    = newTestReader()
+		dayParams = testParams(testDay.Add(24*time.Hour),
+			testDay.Add(47*time.Hour))
+	)
+
+	// Cache only the second day.
+	_, err := cache.Read(ctx, "query", dayParams, 0, reader.read)
+	require.NoError(t, err)
+
+	reader.calls = nil
+	params := testParams(testDay, testDay.Add(3*24*time.Hour-time.Hour))
+	result, err := cache.Read(ctx, "query", params, 0, reader.read)
+	require.NoError(t, err)
+	assertResult(t, params, result)
+
+	require.Equal(t, 2, len(reader.calls))
+	assert.Equal(t, testDay, reader.calls[0].Start)
+	assert.Equal(t, testDay.Add(24*time.Hour), reader.calls[0].End)
+	assert.Equal(t, testDay.Add(2*24*time.Hour), reader.calls[1].Start)
+	assert.Equal(t, testDay.Add(3*24*time.Hour), reader.calls[1].End)
+}
+
+func TestResultsCacheLimitsMergedSeries(t *testing.T) {
+	var (
+		ctx       = context.Background()
+		now       = testDay.Add(10 * 24 * time.Hour)
+		cache, _  = newTestResultsCache(t, NewLRUBackend(1<<20), now)
+		reader    = newTestReader()
+		dayParams = testParams(testDay, testDay.Add(23*time.Hour))
+		params    = testParams(testDay, testDay.Add(2*24*time.Hour-time.Hour))
+	)
+
+	// Cache the first day, so that the cached and read series are merged.
+	_, err := cache.Read(ctx, "query", dayParams, 0, reader.read)
+	require.NoError(t, err)
+
+	result, err := cache.Read(ctx, "query", params, 1, reader.read)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(result.Series))
+	assert.Equal(t, "a", string(result.Series[0].Name()))
+	assert.Equal(t, 2*24, result.Series[0].Len())
+	assert.False(t, result.Meta.Exhaustive)
+
+	result, err = cache.Read(ctx, "query", params, 2, reader.read)
+	require.NoError(t, err)
+	assertResult(t, params, result)
+	assert.True(t, result.Meta.Exhaustive)
+}
+
+func TestResultsCacheUncacheable(t *testing.T) {
+	var (
+		ctx       = context.Background()
+		now       = testDay.Add(10 * 24 * time.Hour)
+		cache, sc = newTestResultsCache(t, NewLRUBackend(1<<20), now)
+		reader    = newTestReader()
+		params    = testParams(testDay.Add(time.Minute), testDay.Add(time.Hour))
+	)
+
+	for i := 0; i < 2; i++ {
+		_, err := cache.Read(ctx, "query", params, 0, reader.read)
+		require.NoError(t, err)
+	}
+
+	// Unaligned queries are passed through as is.
+	require.Equal(t, 2, len(reader.calls))
+	assert.Equal(t, params, reader.calls[0])
+	assert.Equal(t, params, reader.calls[1])
+	assert.Equal(t, int64(2), counter(sc, "uncacheable"))
+}
+
+func TestResultsCacheDoesNotCachePartialResults(t *testing.T) {
+	var (
+		ctx      = context.Background()
+		now      = testDay.Add(10 * 24 * time.Hour)
+		cache, _ = newTestResultsCache(t, NewLRUBackend(1<<20), now)
+		reader   = newTestReader()
+		params   = testParams(testDay, testDay.Add(23*time.Hour))
+	)
+
+	reader.meta.AddWarning("remote", "unavailable")
+	for i := 0; i < 2; i++ {
+		result, err := cache.Read(ctx, "query", params, 0, reader.read)
+		require.NoError(t, err)
+		assertResult(t, params, result)
+		assert.Equal(t, 1, len(result.Meta.Warnings))
+	}
+
+	assert.Equal(t, 2, len(reader.calls))
+}
+
+func TestResultsCacheReadError(t *testing.T) {
+	var (
+		ctx      = context.Background()
+		now      = testDay.Add(10 * 24 * time.Hour)
+		cache, _ = newTestResultsCache(t, NewLRUBackend(1<<20), now)
+		reader   = newTestReader()
+		params   = testParams(testDay, testDay.Add(23*time.Hour))
+	)
+
+	reader.err = errors.New("read error")
+	_, err := cache.Read(ctx, "query", params, 0, reader.read)
+	assert.Equal(t, reader.err, err)
+}
+
+type errBackend struct{}
+
+func (errBackend) Fetch(context.Context, []string) (map[string][]byte, error) {
+	return nil, errors.New("fetch error")
+}
+
+func (errBackend) Store(context.Context, map[string][]byte) error {
+	return errors.New("store error")
+}
+
+func TestResultsCacheBackendErrors(t *testing.T) {
+	var (
+		ctx       = context.Background()
+		now       = testDay.Add(10 * 24 * time.Hour)
+		cache, sc = newTestResultsCache(t, errBackend{}, now)
+		reader    = newTestReader()
+		params    = testParams(testDay, testDay.Add(23*time.Hour))
+	)
+
+	result, err := cache.Read(ctx, "query", params, 0, reader.read)
+	require.NoError(t, err)
+	assertResult(t, params, result)
+	assert.Equal(t, int64(1), counter(sc, "fetch-errors"))
+	assert.Equal(t, int64(1), counter(sc, "store-errors"))
+}
+
+func TestEntryEncoding(t *testing.T) {
+	tags := models.NewTags(2, models.NewTagOptions()).
+		AddTag(models.Tag{Name: []byte("a"), Value: []byte("1")}).
+		AddTag(models.Tag{Name: []byte("b"), Value: []byte("2")})
+	e := entry{
+		start: testDay,
+		step:  time.Minute,
+		steps: 3,
+		series: []entrySeries{
+			{name: []byte("foo"), tags: tags, values: []float64{1, math.NaN(), 3}},
+			{name: []byte("bar"), tags: tags, values: []float64{4, 5, 6}},
+		},
+	}
+
+	encoded := encodeEntry(e)
+	decoded, err := decodeEntry(encoded, models.NewTagOptions())
+	require.NoError(t, err)
+
+	assert.True(t, e.start.Equal(decoded.start))
+	assert.Equal(t, e.step, decoded.step)
+	assert.Equal(t, e.steps, decoded.steps)
+	require.Equal(t, len(e.series), len(decoded.series))
+	for i, s := range e.series {
+		d := decoded.series[i]
+		assert.Equal(t, s.name, d.name)
+		assert.Equal(t, s.tags.Tags, d.tags.Tags)
+		require.Equal(t, len(s.values), len(d.values))
+		for j, v := range s.values {
+			if math.IsNaN(v) {
+				assert.True(t, math.IsNaN(d.values[j]))
+				continue
+			}
+
+			assert.Equal(t, v, d.values[j])
+		}
+	}
+
+	for i := 0; i < len(encoded); i++ {
+		_, err := decodeEntry(encoded[:i], models.NewTagOptions())
+		assert.Error(t, err)
+	}
+}
@@ -81,6 +81,12 @@ func (o Options) TimeSpec() TimeSpec {
 	return o.timeSpec
 }
 
+// SetTimeSpec returns a copy of the options with the given TimeSpec.
+func (o Options) SetTimeSpec(ts TimeSpec) Options {
+	o.timeSpec = ts
+	return o
+}
+
 // Debug returns the Debug option.
 func (o Options) Debug() bool {
 	return o.debug
@@ -156,3 +162,12 @@ type BoundSpec struct {
 	// Offset is the offset for the operation.
 	Offset time.Duration
 }
+
+// SubqueryOp is an operation whose inputs are evaluated over a different
+// time range and step than the operation itself.
+type SubqueryOp interface {
+	BoundOp
+	// InputTimeSpec returns the time spec the inputs of the operation are
+	// evaluated over, given the time spec of the operation itself.
+	InputTimeSpec(ts TimeSpec) TimeSpec
+}
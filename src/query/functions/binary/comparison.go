@@ -26,6 +26,7 @@ import (
 	"github.com/m3db/m3/src/query/block"
 	"github.com/m3db/m3/src/query/executor/transform"
 	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/storage"
 )
 
 const (
@@ -85,6 +86,54 @@ var (
 	}
 )
 
+var (
+	comparisonValueFilterTypes = map[string]storage.ValueFilterType{
+		EqType:        storage.ValueFilterEqual,
+		NotEqType:     storage.ValueFilterNotEqual,
+		GreaterType:   storage.ValueFilterGreater,
+		LesserType:    storage.ValueFilterLess,
+		GreaterEqType: storage.ValueFilterGreaterEqual,
+		LesserEqType:  storage.ValueFilterLessEqual,
+	}
+
+	// flippedComparisonTypes maps a comparison with the scalar on the left
+	// hand side to the equivalent comparison with the scalar on the right.
+	flippedComparisonTypes = map[string]string{
+		EqType:        EqType,
+		NotEqType:     NotEqType,
+		GreaterType:   LesserType,
+		LesserType:    GreaterType,
+		GreaterEqType: LesserEqType,
+		LesserEqType:  GreaterEqType,
+	}
+)
+
+// ComparisonValueFilter returns the value filter that lets storage skip
+// series that never satisfy a comparison between a series and a scalar.
+// Comparisons returning bool values keep every series so have no filter.
+func ComparisonValueFilter(
+	opType string,
+	returnBool bool,
+	scalarOnLHS bool,
+	scalar float64,
+) (storage.ValueFilter, bool) {
+	if returnBool || math.IsNaN(scalar) {
+		return storage.ValueFilter{}, false
+	}
+	if scalarOnLHS {
+		flipped, ok := flippedComparisonTypes[opType]
+		if !ok {
+			return storage.ValueFilter{}, false
+		}
+		opType = flipped
+	}
+	filterType, ok := comparisonValueFilterTypes[opType]
+	if !ok {
+		return storage.ValueFilter{}, false
+	}
+	return storage.ValueFilter{Type: filterType, Value: scalar}, true
+}
+
 // Builds a comparison processing function if able. If wrong opType supplied,
 // returns no function and false.
 func buildComparisonFunction(
@@ -0,0 +1,63 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package binary
+
+import (
+	"math"
+	"testing"
+
+	"github.com/m3db/m3/src/query/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComparisonValueFilter(t *testing.T) {
+	tests := []struct {
+		opType      string
+		scalarOnLHS bool
+		expected    storage.ValueFilterType
+	}{
+		{EqType, false, storage.ValueFilterEqual},
+		{NotEqType, true, storage.ValueFilterNotEqual},
+		{GreaterType, false, storage.ValueFilterGreater},
+		{GreaterType, true, storage.ValueFilterLess},
+		{LesserEqType, false, storage.ValueFilterLessEqual},
+		{LesserEqType, true, storage.ValueFilterGreaterEqual},
+	}
+
+	for _, tt := range tests {
+		filter, ok := ComparisonValueFilter(tt.opType, false, tt.scalarOnLHS, 2)
+		require.True(t, ok)
+		assert.Equal(t, storage.ValueFilter{Type: tt.expected, Value: 2}, filter)
+	}
+}
+
+func TestComparisonValueFilterNoFilter(t *testing.T) {
+	_, ok := ComparisonValueFilter(GreaterType, true, false, 2)
+	assert.False(t, ok)
+
+	_, ok = ComparisonValueFilter(GreaterType, false, false, math.NaN())
+	assert.False(t, ok)
+
+	_, ok = ComparisonValueFilter(PlusType, false, false, 2)
+	assert.False(t, ok)
+}
@@ -46,6 +46,10 @@ type FetchOp struct {
 	Range    time.Duration
 	Offset   time.Duration
 	Matchers models.Matchers
+
+	// ValueFilter, when set, lets storage skip series whose values cannot
+	// satisfy the comparison applied to the fetched series.
+	ValueFilter *storage.ValueFilter
 }
 
 // FetchNode is a fetch execution node.
@@ -120,6 +124,7 @@ func (n *FetchNode) fetch(queryCtx *models.QueryContext) (block.Result, error) {
 		End:         endTime.Add(-1 * offset),
 		TagMatchers: n.op.Matchers,
 		Interval:    timeSpec.Step,
+		ValueFilter: n.op.ValueFilter,
 	}, opts)
 }
 
@@ -26,11 +26,13 @@ import (
 	"sort"
 	"strconv"
 
+	dts "github.com/m3db/m3/src/dbnode/ts"
 	"github.com/m3db/m3/src/query/block"
 	"github.com/m3db/m3/src/query/executor/transform"
 	"github.com/m3db/m3/src/query/functions/utils"
 	"github.com/m3db/m3/src/query/models"
 	"github.com/m3db/m3/src/query/parser"
+	"github.com/m3db/m3/src/query/storage"
 	"github.com/m3db/m3/src/query/util"
 )
 
@@ -38,8 +40,9 @@ const (
 	// HistogramQuantileType calculates the quantile for histogram buckets.
 	//
 	// NB: each sample must contain a tag with a bucket name (given by tag
-	// options) that denotes the upper bound of that bucket; series without this
-	// tag are ignored.
+	// options) that denotes the upper bound of that bucket, or be a native
+	// histogram series whose histograms are read directly from the steps of
+	// the block; other series are ignored.
 	HistogramQuantileType = "histogram_quantile"
 	initIndexBucketLength = 10
 )
@@ -133,6 +136,30 @@ func (b indexedBuckets) Less(i, j int) bool {
 
 type bucketedSeries map[string]indexedBuckets
 
+// nativeSeries is a series that holds native histograms.
+type nativeSeries struct {
+	idx  int
+	tags models.Tags
+}
+
+func gatherNativeSeries(metas []block.SeriesMeta) []nativeSeries {
+	var native []nativeSeries
+	for i, meta := range metas {
+		tags := meta.Tags
+		if !storage.IsNativeHistogramSeries(tags) {
+			continue
+		}
+
+		excludeTags := [][]byte{tags.Opts.MetricName(), storage.NativeHistogramTagName}
+		native = append(native, nativeSeries{
+			idx:  i,
+			tags: tags.TagsWithoutKeys(excludeTags),
+		})
+	}
+
+	return native
+}
+
 func gatherSeriesToBuckets(metas []block.SeriesMeta) bucketedSeries {
 	bucketsForID := make(bucketedSeries, initIndexBucketLength)
 	for i, meta := range metas {
@@ -258,30 +285,38 @@ func (n *histogramQuantileNode) ProcessBlock(
 	meta := b.Meta()
 	seriesMetas := utils.FlattenMetadata(meta, stepIter.SeriesMeta())
 	bucketedSeries := gatherSeriesToBuckets(seriesMetas)
+	nativeSeries := gatherNativeSeries(seriesMetas)
 
 	q := n.op.q
 	if q < 0 || q > 1 {
-		return processInvalidQuantile(queryCtx, q, bucketedSeries, meta, stepIter, n.controller)
+		return processInvalidQuantile(queryCtx, q, bucketedSeries,
+			nativeSeries, meta, stepIter, n.controller)
 	}
 
-	return processValidQuantile(queryCtx, q, bucketedSeries, meta, stepIter, n.controller)
+	return processValidQuantile(queryCtx, q, bucketedSeries,
+		nativeSeries, meta, stepIter, n.controller)
 }
 
 func setupBuilder(
 	queryCtx *models.QueryContext,
 	bucketedSeries bucketedSeries,
+	nativeSeries []nativeSeries,
 	meta block.Metadata,
 	stepIter block.StepIter,
 	controller *transform.Controller,
 ) (block.Builder, error) {
-	metas := make([]block.SeriesMeta, len(bucketedSeries))
-	idx := 0
+	metas := make([]block.SeriesMeta, 0, len(bucketedSeries)+len(nativeSeries))
 	for _, v := range bucketedSeries {
-		metas[idx] = block.SeriesMeta{
+		metas = append(metas, block.SeriesMeta{
 			Tags: v.tags,
-		}
+		})
+	}
 
-		idx++
+	// NB: native histogram series are output after the bucketed series.
+	for _, v := range nativeSeries {
+		metas = append(metas, block.SeriesMeta{
+			Tags: v.tags,
+		})
 	}
 
 	meta.Tags, metas = utils.DedupeMetadata(metas, meta.Tags.Opts)
@@ -301,13 +336,15 @@ func processValidQuantile(
 	queryCtx *models.QueryContext,
 	q float64,
 	bucketedSeries bucketedSeries,
+	nativeSeries []nativeSeries,
 	meta block.Metadata,
 	stepIter block.StepIter,
 	controller *transform.Controller,
 ) (block.Block, error) {
 	sanitizeBuckets(bucketedSeries)
 
-	builder, err := setupBuilder(queryCtx, bucketedSeries, meta, stepIter, controller)
+	builder, err := setupBuilder(queryCtx, bucketedSeries, nativeSeries,
+		meta, stepIter, controller)
 	if err != nil {
 		return nil, err
 	}
@@ -317,7 +354,8 @@ func processValidQuantile(
 		values := step.Values()
 		bucketValues := make([]bucketValue, 0, initIndexBucketLength)
 
-		aggregatedValues := make([]float64, len(bucketedSeries))
+		aggregatedValues := make([]float64,
+			len(bucketedSeries)+len(nativeSeries))
 		idx := 0
 		for _, b := range bucketedSeries {
 			buckets := b.buckets
@@ -340,6 +378,29 @@ func processValidQuantile(
 			idx++
 		}
 
+		var histograms []dts.Histogram
+		if histogramStep, ok := step.(block.HistogramStep); ok {
+			histograms = histogramStep.Histograms()
+		}
+
+		for _, native := range nativeSeries {
+			// clear previous bucket values.
+			bucketValues = bucketValues[:0]
+			if native.idx < len(histograms) {
+				for _, bucket := range histograms[native.idx].Buckets {
+					bucketValues = append(
+						bucketValues, bucketValue{
+							upperBound: bucket.UpperBound,
+							value:      float64(bucket.Count),
+						},
+					)
+				}
+			}
+
+			aggregatedValues[idx] = bucketQuantile(q, bucketValues)
+			idx++
+		}
+
 		if err := builder.AppendValues(index, aggregatedValues); err != nil {
 			return nil, err
 		}
@@ -356,11 +417,13 @@ func processInvalidQuantile(
 	queryCtx *models.QueryContext,
 	q float64,
 	bucketedSeries bucketedSeries,
+	nativeSeries []nativeSeries,
 	meta block.Metadata,
 	stepIter block.StepIter,
 	controller *transform.Controller,
 ) (block.Block, error) {
-	builder, err := setupBuilder(queryCtx, bucketedSeries, meta, stepIter, controller)
+	builder, err := setupBuilder(queryCtx, bucketedSeries, nativeSeries,
+		meta, stepIter, controller)
 	if err != nil {
 		return nil, err
 	}
@@ -373,7 +436,7 @@ func processInvalidQuantile(
 	}
 
 	setValue := math.Inf(sign)
-	outValues := make([]float64, len(bucketedSeries))
+	outValues := make([]float64, len(bucketedSeries)+len(nativeSeries))
 	util.Memset(outValues, setValue)
 	for index := 0; stepIter.Next(); index++ {
 		if err := builder.AppendValues(index, outValues); err != nil {
@@ -25,10 +25,12 @@ import (
 	"testing"
 	"time"
 
+	dts "github.com/m3db/m3/src/dbnode/ts"
 	"github.com/m3db/m3/src/query/block"
 	"github.com/m3db/m3/src/query/executor/transform"
 	"github.com/m3db/m3/src/query/models"
 	"github.com/m3db/m3/src/query/parser"
+	"github.com/m3db/m3/src/query/storage"
 	"github.com/m3db/m3/src/query/test"
 	"github.com/m3db/m3/src/query/test/executor"
 
@@ -295,3 +297,108 @@ func TestQuantileFunctionForInvalidQValues(t *testing.T) {
 	actual = testQuantileFunctionWithQ(t, 0.8)
 	test.EqualsWithNansWithDelta(t, [][]float64{{15.6, 20, math.NaN(), 2, math.NaN()}}, actual, 0.00001)
 }
+
+type histogramStepBlock struct {
+	block.Block
+	histograms [][]dts.Histogram
+}
+
+func (b histogramStepBlock) StepIter() (block.StepIter, error) {
+	iter, err := b.Block.StepIter()
+	if err != nil {
+		return nil, err
+	}
+
+	return &histogramStepIter{StepIter: iter, histograms: b.histograms, idx: -1}, nil
+}
+
+type histogramStepIter struct {
+	block.StepIter
+	histograms [][]dts.Histogram
+	idx        int
+}
+
+func (it *histogramStepIter) Next() bool {
+	it.idx++
+	return it.StepIter.Next()
+}
+
+func (it *histogramStepIter) Current() block.Step {
+	step := it.StepIter.Current()
+	return block.NewHistogramColStep(step.Time(), step.Values(), it.histograms[it.idx])
+}
+
+func TestQuantileFunctionNativeHistograms(t *testing.T) {
+	op, err := NewHistogramQuantileOp([]interface{}{0.5}, HistogramQuantileType)
+	require.NoError(t, err)
+
+	tagOpts := models.NewTagOptions().
+		SetIDSchemeType(models.TypeQuoted).
+		SetMetricName([]byte("name")).
+		SetBucketName([]byte("bucket"))
+
+	tags := models.NewTags(3, tagOpts).SetName([]byte("foo")).AddTag(models.Tag{
+		Name:  []byte("bar"),
+		Value: []byte("baz"),
+	})
+
+	seriesMetas := []block.SeriesMeta{
+		{Tags: tags.Clone().AddTag(models.Tag{
+			Name:  storage.NativeHistogramTagName,
+			Value: storage.NativeHistogramTagValue,
+		})},
+		// this series should not be part of the output, since it is neither a
+		// bucket series nor a native histogram series.
+		{Tags: tags.Clone()},
+	}
+
+	v := [][]float64{
+		{4, 4, 10},
+		{1, 2, 3},
+	}
+
+	h1 := dts.Histogram{Buckets: []dts.HistogramBucket{
+		{UpperBound: 1, Count: 2},
+		{UpperBound: 2, Count: 4},
+		{UpperBound: math.Inf(1), Count: 4},
+	}}
+	h2 := dts.Histogram{Buckets: []dts.HistogramBucket{
+		{UpperBound: 1, Count: 0},
+		{UpperBound: 10, Count: 10},
+		{UpperBound: math.Inf(1), Count: 10},
+	}}
+
+	histograms := [][]dts.Histogram{
+		{h1, {}},
+		// no histogram in the lookback of this step.
+		{{}, {}},
+		{h2, {}},
+	}
+
+	bounds := models.Bounds{
+		Start:    time.Now(),
+		Duration: time.Minute * 3,
+		StepSize: time.Minute,
+	}
+
+	bl := histogramStepBlock{
+		Block:      test.NewBlockFromValuesWithSeriesMeta(bounds, seriesMetas, v),
+		histograms: histograms,
+	}
+
+	c, sink := executor.NewControllerWithSink(parser.NodeID(1))
+	node := op.(histogramQuantileOp).Node(c, transform.Options{})
+	err = node.Process(models.NoopQueryContext(), parser.NodeID(0), bl)
+	require.NoError(t, err)
+
+	test.EqualsWithNansWithDelta(t,
+		[][]float64{{1, math.NaN(), 5.5}}, sink.Values, 0.00001)
+	require.Equal(t, 1, len(sink.Metas))
+
+	// NB: the tags of the single output series are common to the block.
+	outTags := sink.Meta.Tags.Add(sink.Metas[0].Tags)
+	require.Equal(t, 1, outTags.Len())
+	value, ok := outTags.Get([]byte("bar"))
+	require.True(t, ok)
+	assert.Equal(t, []byte("baz"), value)
+}
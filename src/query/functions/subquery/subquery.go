@@ -0,0 +1,210 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package subquery
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/m3db/m3/src/query/block"
+	"github.com/m3db/m3/src/query/executor/transform"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/parser"
+	"github.com/m3db/m3/src/query/ts"
+)
+
+// SubqueryType evaluates its input expression at the subquery step over the
+// subquery range, yielding a range vector for the temporal functions.
+const SubqueryType = "subquery"
+
+// NewSubqueryOp creates a new subquery operation for the given subquery
+// range, offset and step.
+func NewSubqueryOp(
+	queryRange time.Duration,
+	offset time.Duration,
+	step time.Duration,
+) (parser.Params, error) {
+	if queryRange <= 0 {
+		return nil, fmt.Errorf("subquery range must be positive, received: %v",
+			queryRange)
+	}
+
+	if offset < 0 {
+		return nil, fmt.Errorf("subquery offset must be positive, received: %v",
+			offset)
+	}
+
+	if step <= 0 {
+		return nil, fmt.Errorf("subquery step must be positive, received: %v",
+			step)
+	}
+
+	return subqueryOp{
+		queryRange: queryRange,
+		offset:     offset,
+		step:       step,
+	}, nil
+}
+
+type subqueryOp struct {
+	queryRange time.Duration
+	offset     time.Duration
+	step       time.Duration
+}
+
+var _ transform.SubqueryOp = subqueryOp{}
+
+// OpType for the operator.
+func (o subqueryOp) OpType() string {
+	return SubqueryType
+}
+
+// String representation.
+func (o subqueryOp) String() string {
+	return fmt.Sprintf("type: %s, range: %v, offset: %v, step: %v",
+		o.OpType(), o.queryRange, o.offset, o.step)
+}
+
+// Bounds returns the bounds for this operation.
+func (o subqueryOp) Bounds() transform.BoundSpec {
+	return transform.BoundSpec{
+		Range:  o.queryRange,
+		Offset: o.offset,
+	}
+}
+
+// InputTimeSpec returns the time spec the subquery expression is evaluated
+// over. As in Prometheus, the subquery expression is evaluated at the
+// multiples of the subquery step from the start of the earliest subquery
+// range up to the end of the latest one, i.e. the last query step less the
+// offset.
+func (o subqueryOp) InputTimeSpec(timeSpec transform.TimeSpec) transform.TimeSpec {
+	lastStep := timeSpec.Start
+	if steps := timeSpec.Bounds().Steps(); steps > 1 {
+		lastStep = lastStep.Add(time.Duration(steps-1) * timeSpec.Step)
+	}
+
+	var (
+		start = timeSpec.Start.Add(-1 * (o.offset + o.queryRange)).UnixNano()
+		end   = lastStep.Add(-1 * o.offset).UnixNano()
+		step  = int64(o.step)
+	)
+
+	first := step * (start / step)
+	if first < start {
+		first += step
+	}
+
+	last := step * (end / step)
+	if last > end {
+		last -= step
+	}
+
+	return transform.TimeSpec{
+		Start: time.Unix(0, first),
+		// NB: the end is exclusive.
+		End:  time.Unix(0, last+step),
+		Now:  timeSpec.Now,
+		Step: o.step,
+	}
+}
+
+// Node creates an execution node.
+func (o subqueryOp) Node(
+	controller *transform.Controller,
+	opts transform.Options,
+) transform.OpNode {
+	return &subqueryNode{
+		op:         o,
+		controller: controller,
+		timeSpec:   opts.TimeSpec(),
+	}
+}
+
+type subqueryNode struct {
+	op         subqueryOp
+	controller *transform.Controller
+	timeSpec   transform.TimeSpec
+}
+
+func (n *subqueryNode) Params() parser.Params {
+	return n.op
+}
+
+// Process the block.
+func (n *subqueryNode) Process(
+	queryCtx *models.QueryContext,
+	ID parser.NodeID,
+	b block.Block,
+) error {
+	return transform.ProcessSimpleBlock(n, n.controller, queryCtx, ID, b)
+}
+
+// ProcessBlock collects the values of the subquery expression at each
+// subquery step into raw series datapoints, and yields them in a block with
+// the bounds of the enclosing query so that range functions are evaluated at
+// the query steps.
+func (n *subqueryNode) ProcessBlock(
+	queryCtx *models.QueryContext,
+	ID parser.NodeID,
+	b block.Block,
+) (block.Block, error) {
+	iter, err := b.StepIter()
+	if err != nil {
+		return nil, err
+	}
+
+	seriesMeta := iter.SeriesMeta()
+	datapoints := make([]ts.Datapoints, len(seriesMeta))
+	for iter.Next() {
+		var (
+			step = iter.Current()
+			t    = step.Time().Add(n.op.offset)
+		)
+
+		for i, v := range step.Values() {
+			// NB: steps where the subquery expression has no value do not yield
+			// a datapoint.
+			if math.IsNaN(v) {
+				continue
+			}
+
+			datapoints[i] = append(datapoints[i], ts.Datapoint{
+				Timestamp: t,
+				Value:     v,
+			})
+		}
+	}
+
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+
+	series := make([]block.UnconsolidatedSeries, 0, len(seriesMeta))
+	for i, meta := range seriesMeta {
+		series = append(series, block.NewUnconsolidatedSeries(datapoints[i], meta))
+	}
+
+	meta := b.Meta()
+	meta.Bounds = n.timeSpec.Bounds()
+	return block.NewUnconsolidatedBlock(meta, series), nil
+}
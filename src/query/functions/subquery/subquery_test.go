@@ -0,0 +1,230 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package subquery
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/query/block"
+	"github.com/m3db/m3/src/query/executor/transform"
+	"github.com/m3db/m3/src/query/functions/temporal"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/parser"
+	"github.com/m3db/m3/src/query/test"
+	"github.com/m3db/m3/src/query/test/executor"
+	"github.com/m3db/m3/src/query/test/transformtest"
+	"github.com/m3db/m3/src/query/ts"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	nan = math.NaN()
+	// NB: aligned to a multiple of a minute.
+	start = time.Unix(1500000000, 0)
+)
+
+func TestNewSubqueryOp(t *testing.T) {
+	_, err := NewSubqueryOp(0, 0, time.Minute)
+	assert.Error(t, err)
+
+	_, err = NewSubqueryOp(time.Hour, -1*time.Minute, time.Minute)
+	assert.Error(t, err)
+
+	_, err = NewSubqueryOp(time.Hour, 0, 0)
+	assert.Error(t, err)
+
+	op, err := NewSubqueryOp(time.Hour, time.Minute, 30*time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, SubqueryType, op.OpType())
+	assert.Equal(t, "type: subquery, range: 1h0m0s, offset: 1m0s, step: 30s",
+		op.String())
+
+	boundOp, ok := op.(transform.BoundOp)
+	require.True(t, ok)
+	assert.Equal(t, transform.BoundSpec{
+		Range:  time.Hour,
+		Offset: time.Minute,
+	}, boundOp.Bounds())
+}
+
+func TestSubqueryInputTimeSpec(t *testing.T) {
+	op, err := NewSubqueryOp(5*time.Minute, time.Minute, time.Minute)
+	require.NoError(t, err)
+
+	subqueryOp, ok := op.(transform.SubqueryOp)
+	require.True(t, ok)
+
+	timeSpec := transform.TimeSpec{
+		Start: start.Add(4*time.Minute + 30*time.Second),
+		End:   start.Add(time.Hour),
+		Now:   start.Add(time.Hour),
+		Step:  15 * time.Second,
+	}
+
+	// NB: the first subquery step is the first multiple of the subquery step
+	// after the start of the earliest subquery range.
+	actual := subqueryOp.InputTimeSpec(timeSpec)
+	assert.Equal(t, start.Add(-1*time.Minute).UnixNano(), actual.Start.UnixNano())
+	assert.Equal(t, start.Add(59*time.Minute).UnixNano(), actual.End.UnixNano())
+	assert.Equal(t, timeSpec.Now, actual.Now)
+	assert.Equal(t, time.Minute, actual.Step)
+
+	timeSpec.Start = start.Add(6 * time.Minute)
+	actual = subqueryOp.InputTimeSpec(timeSpec)
+	assert.Equal(t, start.UnixNano(), actual.Start.UnixNano())
+
+	// NB: the last subquery step is the last multiple of the subquery step
+	// before the last query step less the offset, i.e. 59m15s.
+	timeSpec.End = start.Add(time.Hour + 30*time.Second)
+	actual = subqueryOp.InputTimeSpec(timeSpec)
+	assert.Equal(t, start.Add(time.Hour).UnixNano(), actual.End.UnixNano())
+	assert.Equal(t, 60, actual.Bounds().Steps())
+}
+
+func TestSubqueryProcessBlock(t *testing.T) {
+	op, err := NewSubqueryOp(3*time.Minute, time.Minute, time.Minute)
+	require.NoError(t, err)
+
+	outer := transform.TimeSpec{
+		Start: start.Add(4 * time.Minute),
+		End:   start.Add(7 * time.Minute),
+		Step:  time.Minute,
+	}
+
+	seriesMeta := test.NewSeriesMeta("a", 2)
+	bl := test.NewBlockFromValuesWithSeriesMeta(models.Bounds{
+		Start:    start,
+		Duration: 3 * time.Minute,
+		StepSize: time.Minute,
+	}, seriesMeta, [][]float64{{1, 2, 3}, {nan, 4, 5}})
+
+	c, _ := executor.NewControllerWithSink(parser.NodeID(1))
+	node := op.(transform.Params).Node(c, transformtest.Options(t,
+		transform.OptionsParams{TimeSpec: outer}))
+	sqNode, ok := node.(*subqueryNode)
+	require.True(t, ok)
+
+	result, err := sqNode.ProcessBlock(models.NoopQueryContext(),
+		parser.NodeID(0), bl)
+	require.NoError(t, err)
+	assert.Equal(t, block.BlockUnconsolidated, result.Info().Type())
+	assert.Equal(t, outer.Bounds(), result.Meta().Bounds)
+
+	iter, err := result.SeriesIter()
+	require.NoError(t, err)
+	assert.Equal(t, seriesMeta, iter.SeriesMeta())
+
+	// NB: datapoints are shifted by the subquery offset, and steps without a
+	// value do not yield a datapoint.
+	expected := []ts.Datapoints{
+		{
+			{Timestamp: start.Add(time.Minute), Value: 1},
+			{Timestamp: start.Add(2 * time.Minute), Value: 2},
+			{Timestamp: start.Add(3 * time.Minute), Value: 3},
+		},
+		{
+			{Timestamp: start.Add(2 * time.Minute), Value: 4},
+			{Timestamp: start.Add(3 * time.Minute), Value: 5},
+		},
+	}
+
+	var actual []ts.Datapoints
+	for iter.Next() {
+		actual = append(actual, iter.Current().Datapoints())
+	}
+
+	require.NoError(t, iter.Err())
+	assert.Equal(t, expected, actual)
+}
+
+var subqueryTemporalTests = []struct {
+	name     string
+	opType   string
+	offset   time.Duration
+	expected [][]float64
+}{
+	{
+		name:   "sum_over_time",
+		opType: temporal.SumType,
+		// NB: ranges are inclusive of both ends, i.e. the first step covers the
+		// subquery steps from 1m to 4m.
+		expected: [][]float64{{14, 18, 22}, {4, 8, 8}},
+	},
+	{
+		name:     "count_over_time",
+		opType:   temporal.CountType,
+		expected: [][]float64{{4, 4, 4}, {2, 2, 2}},
+	},
+	{
+		name:     "sum_over_time with offset",
+		opType:   temporal.SumType,
+		offset:   time.Minute,
+		expected: [][]float64{{10, 14, 18}, {4, 4, 8}},
+	},
+}
+
+func TestSubqueryTemporalFunctions(t *testing.T) {
+	for _, tt := range subqueryTemporalTests {
+		t.Run(tt.name, func(t *testing.T) {
+			op, err := NewSubqueryOp(3*time.Minute, tt.offset, time.Minute)
+			require.NoError(t, err)
+
+			temporalOp, err := temporal.NewAggOp(
+				[]interface{}{3 * time.Minute}, tt.opType)
+			require.NoError(t, err)
+
+			outer := transform.TimeSpec{
+				Start: start.Add(4 * time.Minute),
+				End:   start.Add(7 * time.Minute),
+				Step:  time.Minute,
+			}
+
+			// NB: the subquery expression is evaluated at each minute, including
+			// steps before the earliest subquery range.
+			bl := test.NewBlockFromValuesWithSeriesMeta(models.Bounds{
+				Start:    start,
+				Duration: 7 * time.Minute,
+				StepSize: time.Minute,
+			}, test.NewSeriesMeta("a", 2), [][]float64{
+				{1, 2, 3, 4, 5, 6, 7},
+				{nan, 1, nan, 3, nan, 5, nan},
+			})
+
+			opts := transformtest.Options(t,
+				transform.OptionsParams{TimeSpec: outer})
+			c, sink := executor.NewControllerWithSink(parser.NodeID(2))
+			temporalNode := temporalOp.Node(c, opts)
+			sqController := &transform.Controller{ID: parser.NodeID(1)}
+			sqController.AddTransform(temporalNode)
+			node := op.(transform.Params).Node(sqController, opts)
+
+			err = node.Process(models.NoopQueryContext(), parser.NodeID(0), bl)
+			require.NoError(t, err)
+
+			assert.Equal(t, outer.Bounds(), sink.Meta.Bounds)
+			test.EqualsWithNansWithDelta(t, tt.expected, sink.Values, 0.0001)
+		})
+	}
+}
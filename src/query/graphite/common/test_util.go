@@ -135,6 +135,15 @@ func (s *MovingAverageStorage) FetchByQuery(
 	return s.fetchByIDs(ctx, []string{query}, opts)
 }
 
+// FetchByTags builds a new series from the input tag expressions
+func (s *MovingAverageStorage) FetchByTags(
+	ctx context.Context,
+	tagExpressions []string,
+	opts storage.FetchOptions,
+) (*storage.FetchResult, error) {
+	return s.fetchByIDs(ctx, tagExpressions, opts)
+}
+
 // FetchByIDs builds a new series from the input query
 func (s *MovingAverageStorage) fetchByIDs(
 	ctx context.Context,
@@ -20,7 +20,12 @@
 
 package graphite
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
 
 const (
 	// graphiteFormat is the format for graphite metric tag names, which will be
@@ -38,11 +43,19 @@ const (
 
 	// MatchAllPattern that is used to match all metrics.
 	MatchAllPattern = ".*"
+
+	// TaggedNameTag is the tag which refers to the path of a tagged series,
+	// e.g. in the tag expression 'name=cpu.load'.
+	TaggedNameTag = "name"
+
+	taggedSeparator = ";"
 )
 
 var (
 	// Should never be modified after init().
 	preFormattedTagNames [][]byte
+
+	errEmptyTaggedPath = errors.New("tagged series must have a non-empty path")
 )
 
 func init() {
@@ -65,3 +78,105 @@ func TagName(idx int) []byte {
 func generateTagName(idx int) []byte {
 	return []byte(fmt.Sprintf(graphiteFormat, idx))
 }
+
+// TaggedName is a parsed graphite tagged series name, which takes the form
+// path;tag1=value1;tag2=value2.
+type TaggedName struct {
+	// Path is the dotted path of the series.
+	Path string
+	// Tags are the tags of the series, excluding the path.
+	Tags map[string]string
+}
+
+// IsTaggedName returns true if the given series name carries tags.
+func IsTaggedName(name string) bool {
+	return strings.Contains(name, taggedSeparator)
+}
+
+// ParseTaggedName parses a graphite series name of the form
+// path;tag1=value1;tag2=value2. A name without any tags parses to just its
+// path.
+func ParseTaggedName(name string) (TaggedName, error) {
+	parts := strings.Split(name, taggedSeparator)
+	if len(parts[0]) == 0 {
+		return TaggedName{}, errEmptyTaggedPath
+	}
+
+	parsed := TaggedName{
+		Path: parts[0],
+		Tags: make(map[string]string, len(parts)-1),
+	}
+
+	for _, part := range parts[1:] {
+		idx := strings.Index(part, "=")
+		if idx <= 0 {
+			return TaggedName{}, fmt.Errorf("invalid tag %q in series %s", part, name)
+		}
+
+		tag, value := part[:idx], part[idx+1:]
+		if err := ValidateTag(tag, value); err != nil {
+			return TaggedName{}, fmt.Errorf("invalid tag %q in series %s: %v",
+				part, name, err)
+		}
+
+		parsed.Tags[tag] = value
+	}
+
+	return parsed, nil
+}
+
+// ValidateTag returns an error if the tag of a tagged series name is invalid.
+func ValidateTag(tag, value string) error {
+	if tag == TaggedNameTag {
+		return fmt.Errorf("tag %s is reserved for the series path", TaggedNameTag)
+	}
+
+	if strings.ContainsAny(tag, "!^=") {
+		return errors.New("tag names may not contain any of '!^='")
+	}
+
+	if len(value) == 0 {
+		return errors.New("tag values may not be empty")
+	}
+
+	if strings.HasPrefix(value, "~") {
+		return errors.New("tag values may not start with '~'")
+	}
+
+	return nil
+}
+
+// Tag returns the value of the given tag, resolving TaggedNameTag to the
+// path of the series.
+func (n TaggedName) Tag(tag string) (string, bool) {
+	if tag == TaggedNameTag {
+		return n.Path, true
+	}
+
+	value, ok := n.Tags[tag]
+	return value, ok
+}
+
+// String returns the canonical form of the name, with tags sorted by name.
+func (n TaggedName) String() string {
+	if len(n.Tags) == 0 {
+		return n.Path
+	}
+
+	tags := make([]string, 0, len(n.Tags))
+	for tag := range n.Tags {
+		tags = append(tags, tag)
+	}
+
+	sort.Strings(tags)
+	var b strings.Builder
+	b.WriteString(n.Path)
+	for _, tag := range tags {
+		b.WriteString(taggedSeparator)
+		b.WriteString(tag)
+		b.WriteString("=")
+		b.WriteString(n.Tags[tag])
+	}
+
+	return b.String()
+}
@@ -33,3 +33,43 @@ func TestTagName(t *testing.T) {
 		require.Equal(t, expected, TagName(i))
 	}
 }
+
+func TestParseTaggedName(t *testing.T) {
+	parsed, err := ParseTaggedName("cpu.load;dc=east;app=web")
+	require.NoError(t, err)
+	require.Equal(t, "cpu.load", parsed.Path)
+	require.Equal(t, map[string]string{"dc": "east", "app": "web"}, parsed.Tags)
+	require.Equal(t, "cpu.load;app=web;dc=east", parsed.String())
+
+	path, ok := parsed.Tag(TaggedNameTag)
+	require.True(t, ok)
+	require.Equal(t, "cpu.load", path)
+
+	value, ok := parsed.Tag("dc")
+	require.True(t, ok)
+	require.Equal(t, "east", value)
+
+	_, ok = parsed.Tag("host")
+	require.False(t, ok)
+
+	parsed, err = ParseTaggedName("cpu.load")
+	require.NoError(t, err)
+	require.Equal(t, "cpu.load", parsed.String())
+	require.False(t, IsTaggedName("cpu.load"))
+	require.True(t, IsTaggedName("cpu.load;dc=east"))
+}
+
+func TestParseTaggedNameInvalid(t *testing.T) {
+	for _, name := range []string{
+		";dc=east",
+		"cpu.load;dc",
+		"cpu.load;=east",
+		"cpu.load;dc=",
+		"cpu.load;dc=~east",
+		"cpu.load;d!c=east",
+		"cpu.load;name=foo",
+	} {
+		_, err := ParseTaggedName(name)
+		require.Error(t, err, name)
+	}
+}
@@ -23,10 +23,12 @@ package native
 import (
 	"fmt"
 	"math"
+	"sort"
 	"strings"
 
 	"github.com/m3db/m3/src/query/graphite/common"
 	"github.com/m3db/m3/src/query/graphite/errors"
+	"github.com/m3db/m3/src/query/graphite/graphite"
 	"github.com/m3db/m3/src/query/graphite/ts"
 )
 
@@ -247,6 +249,60 @@ func groupByNode(ctx *common.Context, series singlePathSpec, node int, fname str
 		metaSeries[key] = append(metaSeries[key], s)
 	}
 
+	return applyFnToMetaSeries(ctx, series, metaSeries, fname)
+}
+
+// groupByTags takes a serieslist of graphite tagged series and maps a callback
+// to subgroups within as defined by a common set of tags
+//
+//    &target=groupByTags(seriesByTag("name=cpu","dc=dc1"),"sumSeries","dc")
+//
+//  Would return multiple series which are each the result of applying the "sumSeries" function
+//  to groups joined on the given tags, named after the callback unless the 'name' tag is
+//  grouped on, resulting in a list of series like
+//
+//    sumSeries;dc=dc1,...
+func groupByTags(ctx *common.Context, series singlePathSpec, fname string, tags ...string) (ts.SeriesList, error) {
+	metaSeries := make(map[string][]*ts.Series)
+	for _, s := range series.Values {
+		parsed, err := graphite.ParseTaggedName(s.Name())
+		if err != nil {
+			return ts.NewSeriesList(), errors.NewInvalidParamsError(
+				fmt.Errorf("could not group %s by tags: %v", s.Name(), err))
+		}
+
+		key := graphite.TaggedName{
+			Path: fname,
+			Tags: make(map[string]string, len(tags)),
+		}
+		for _, tag := range tags {
+			value, ok := parsed.Tag(tag)
+			if !ok {
+				continue
+			}
+
+			if tag == graphite.TaggedNameTag {
+				key.Path = value
+			} else {
+				key.Tags[tag] = value
+			}
+		}
+
+		name := key.String()
+		metaSeries[name] = append(metaSeries[name], s)
+	}
+
+	return applyFnToMetaSeries(ctx, series, metaSeries, fname)
+}
+
+// applyFnToMetaSeries combines each group of series in metaSeries, keyed by the
+// name of the resulting series, with the given summarize function.
+func applyFnToMetaSeries(
+	ctx *common.Context,
+	series singlePathSpec,
+	metaSeries map[string][]*ts.Series,
+	fname string,
+) (ts.SeriesList, error) {
 	if fname == "" {
 		fname = "sum"
 	}
@@ -427,3 +483,295 @@ func countSeries(ctx *common.Context, seriesList multiplePathSpecs) (ts.SeriesLi
 	r.Values = count.Values
 	return r, nil
 }
+
+// aggregate combines a series list into a single series using the given
+// aggregation function, which may also be given in its series form, e.g.
+//
+//    &target=aggregate(host.cpu-[0-7].cpu-{user,system}.value,"sum")
+//
+//  Is equivalent to sumSeries(host.cpu-[0-7].cpu-{user,system}.value). Steps
+//  where the ratio of non-null values is below xFilesFactor are left null.
+func aggregate(
+	ctx *common.Context,
+	series singlePathSpec,
+	fname string,
+	xFilesFactor float64,
+) (ts.SeriesList, error) {
+	f, fname, err := getAggregationFunc(fname)
+	if err != nil {
+		return ts.NewSeriesList(), err
+	}
+
+	if len(series.Values) == 0 {
+		return ts.SeriesList(series), nil
+	}
+
+	name := wrapPathExpr(fname+"Series", ts.SeriesList(series))
+	result, err := aggregateSeries(ctx, ts.SeriesList(series), name, f, xFilesFactor)
+	if err != nil {
+		return ts.NewSeriesList(), err
+	}
+
+	r := ts.SeriesList(series)
+	r.Values = []*ts.Series{result}
+	return r, nil
+}
+
+// aggregateWithWildcards splits the given set of series into sub-groupings
+// based on wildcard matches in the hierarchy, then aggregates the values in
+// each grouping with the given aggregation function
+//
+//    &target=aggregateWithWildcards(host.cpu-[0-7].cpu-{user,system}.value,"sum",1)
+//
+//  Would return a series for each of host.cpu-user.value and host.cpu-system.value,
+//  in the order the groupings first appear in the series list.
+func aggregateWithWildcards(
+	ctx *common.Context,
+	series singlePathSpec,
+	fname string,
+	positions ...int,
+) (ts.SeriesList, error) {
+	f, fname, err := getAggregationFunc(fname)
+	if err != nil {
+		return ts.NewSeriesList(), err
+	}
+
+	wildcards := make(map[int]struct{}, len(positions))
+	for _, position := range positions {
+		wildcards[position] = struct{}{}
+	}
+
+	var (
+		keys   []string
+		groups = make(map[string][]*ts.Series)
+	)
+
+	for _, s := range series.Values {
+		var (
+			parts    = strings.Split(s.Name(), ".")
+			newParts = make([]string, 0, len(parts))
+		)
+		for i, part := range parts {
+			if _, wildcard := wildcards[i]; !wildcard {
+				newParts = append(newParts, part)
+			}
+		}
+
+		key := strings.Join(newParts, ".")
+		if _, exists := groups[key]; !exists {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], s)
+	}
+
+	newSeries := make([]*ts.Series, 0, len(keys))
+	for _, key := range keys {
+		seriesList := ts.SeriesList{
+			Values:   groups[key],
+			Metadata: series.Metadata,
+		}
+		aggregated, err := aggregateSeries(ctx, seriesList, key, f, 0)
+		if err != nil {
+			return ts.NewSeriesList(), err
+		}
+		aggregated.Specification = wrapPathExpr(fname+"Series", seriesList)
+		newSeries = append(newSeries, aggregated)
+	}
+
+	r := ts.SeriesList(series)
+	r.Values = newSeries
+	return r, nil
+}
+
+// sumSeriesLists iterates over two series lists of the same length and sums
+// each pair of series, returning a series list of the same length.
+func sumSeriesLists(
+	ctx *common.Context,
+	seriesListFirstPos singlePathSpec,
+	seriesListSecondPos singlePathSpec,
+) (ts.SeriesList, error) {
+	if len(seriesListFirstPos.Values) != len(seriesListSecondPos.Values) {
+		err := errors.NewInvalidParamsError(fmt.Errorf(
+			"sumSeriesLists arguments must have equal length but have %d and %d",
+			len(seriesListFirstPos.Values), len(seriesListSecondPos.Values)))
+		return ts.NewSeriesList(), err
+	}
+
+	results := make([]*ts.Series, 0, len(seriesListFirstPos.Values))
+	for i, first := range seriesListFirstPos.Values {
+		second := seriesListSecondPos.Values[i]
+		name := fmt.Sprintf("sumSeries(%s,%s)", first.Name(), second.Name())
+		summed, err := aggregateSeries(ctx, ts.NewSeriesListWithSeries(first, second),
+			name, aggregateSum, 0)
+		if err != nil {
+			return ts.NewSeriesList(), err
+		}
+		results = append(results, summed)
+	}
+
+	return ts.SeriesList{
+		Values:   results,
+		Metadata: seriesListFirstPos.Metadata.CombineMetadata(seriesListSecondPos.Metadata),
+	}, nil
+}
+
+// aggregateSeries combines multiple series into a single series by applying the
+// aggregation function to the non-NaN values at each step. If the series use
+// different time intervals, the coarsest time will apply. Steps where the ratio of
+// non-NaN values is below xFilesFactor are left as NaN.
+func aggregateSeries(
+	ctx *common.Context,
+	series ts.SeriesList,
+	name string,
+	f aggregationFunc,
+	xFilesFactor float64,
+) (*ts.Series, error) {
+	normalized, start, end, millisPerStep, err := common.Normalize(ctx, series)
+	if err != nil {
+		err := errors.NewInvalidParamsError(fmt.Errorf("aggregate series error: %v", err))
+		return nil, err
+	}
+
+	var (
+		numSeries = normalized.Len()
+		numSteps  = ts.NumSteps(start, end, millisPerStep)
+		vals      = ts.NewValues(ctx, millisPerStep, numSteps)
+		row       = make([]float64, 0, numSeries)
+	)
+
+	for i := 0; i < numSteps; i++ {
+		row = row[:0]
+		for _, s := range normalized.Values {
+			if v := s.ValueAt(i); !math.IsNaN(v) {
+				row = append(row, v)
+			}
+		}
+
+		if len(row) > 0 && float64(len(row))/float64(numSeries) >= xFilesFactor {
+			vals.SetValueAt(i, f(row))
+		}
+	}
+
+	return ts.NewSeries(ctx, name, start, vals), nil
+}
+
+// aggregationFunc reduces a non-empty set of non-NaN values to a single value.
+type aggregationFunc func(values []float64) float64
+
+// aggregationFuncs are the aggregation functions which may be named by graphite
+// functions such as aggregate, filterSeries or highest.
+var aggregationFuncs = map[string]aggregationFunc{
+	"average":  aggregateAverage,
+	"avg":      aggregateAverage,
+	"median":   aggregateMedian,
+	"sum":      aggregateSum,
+	"total":    aggregateSum,
+	"min":      aggregateMin,
+	"max":      aggregateMax,
+	"diff":     aggregateDiff,
+	"stddev":   aggregateStdDev,
+	"count":    aggregateCount,
+	"range":    aggregateRange,
+	"rangeOf":  aggregateRange,
+	"multiply": aggregateMultiply,
+	"last":     aggregateLast,
+	"current":  aggregateLast,
+}
+
+// getAggregationFunc returns the aggregation function for the given name along
+// with the name stripped of any "Series" suffix, so that both "sum" and
+// "sumSeries" resolve to the same function.
+func getAggregationFunc(fname string) (aggregationFunc, string, error) {
+	fname = strings.TrimSuffix(fname, "Series")
+	f, exists := aggregationFuncs[fname]
+	if !exists {
+		return nil, "", errors.NewInvalidParamsError(fmt.Errorf("invalid func %s", fname))
+	}
+	return f, fname, nil
+}
+
+// aggregationSeriesReducer returns a series reducer which applies the
+// aggregation function to the non-NaN values of a series, returning NaN
+// for series without any values.
+func aggregationSeriesReducer(f aggregationFunc) ts.SeriesReducer {
+	return func(s *ts.Series) float64 {
+		values := s.SafeValues()
+		if len(values) == 0 {
+			return math.NaN()
+		}
+		return f(values)
+	}
+}
+
+func aggregateAverage(values []float64) float64 {
+	return aggregateSum(values) / float64(len(values))
+}
+
+func aggregateMedian(values []float64) float64 {
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
+
+func aggregateSum(values []float64) float64 {
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	return sum
+}
+
+func aggregateMin(values []float64) float64 {
+	result := values[0]
+	for _, v := range values[1:] {
+		result = math.Min(result, v)
+	}
+	return result
+}
+
+func aggregateMax(values []float64) float64 {
+	result := values[0]
+	for _, v := range values[1:] {
+		result = math.Max(result, v)
+	}
+	return result
+}
+
+func aggregateDiff(values []float64) float64 {
+	return values[0] - aggregateSum(values[1:])
+}
+
+func aggregateStdDev(values []float64) float64 {
+	avg := aggregateAverage(values)
+	sum := 0.0
+	for _, v := range values {
+		sum += (v - avg) * (v - avg)
+	}
+	return math.Sqrt(sum / float64(len(values)))
+}
+
+func aggregateCount(values []float64) float64 {
+	return float64(len(values))
+}
+
+func aggregateRange(values []float64) float64 {
+	return aggregateMax(values) - aggregateMin(values)
+}
+
+func aggregateMultiply(values []float64) float64 {
+	product := 1.0
+	for _, v := range values {
+		product *= v
+	}
+	return product
+}
+
+func aggregateLast(values []float64) float64 {
+	return values[len(values)-1]
+}
@@ -24,6 +24,7 @@ import (
 	"fmt"
 	"math"
 	"sort"
+	"strings"
 	"testing"
 	"time"
 
@@ -150,6 +151,14 @@ func (e mockEngine) FetchByQuery(
 	return e.fn(ctx, query, opts)
 }
 
+func (e mockEngine) FetchByTags(
+	ctx context.Context,
+	tagExpressions []string,
+	opts storage.FetchOptions,
+) (*storage.FetchResult, error) {
+	return e.fn(ctx, strings.Join(tagExpressions, ","), opts)
+}
+
 func TestVariadicSumSeries(t *testing.T) {
 	expr, err := compile("sumSeries(foo.bar.*, foo.baz.*)")
 	require.NoError(t, err)
@@ -202,6 +211,67 @@ func TestAverageSeries(t *testing.T) {
 	testAggregatedSeries(t, averageSeries, 15.0, 28.0/3, 10.0, 17.0, "invalid avg value for step %d")
 }
 
+func TestAggregate(t *testing.T) {
+	for _, test := range []struct {
+		fname              string
+		ev1, ev2, ev3, ev4 float64
+	}{
+		{"sum", 15.0, 28.0, 30.0, 17.0},
+		{"sumSeries", 15.0, 28.0, 30.0, 17.0},
+		{"maxSeries", 15.0, 15.0, 17.0, 17.0},
+		{"min", 15.0, 3.0, 3.0, 17.0},
+		{"avg", 15.0, 28.0 / 3, 10.0, 17.0},
+		{"median", 15.0, 10.0, 10.0, 17.0},
+		{"diff", 15.0, -8.0, -10.0, 17.0},
+		{"multiply", 15.0, 450.0, 510.0, 17.0},
+	} {
+		fname := test.fname
+		testAggregatedSeries(t, func(ctx *common.Context, series multiplePathSpecs) (ts.SeriesList, error) {
+			return aggregate(ctx, singlePathSpec(series), fname, 0)
+		}, test.ev1, test.ev2, test.ev3, test.ev4, "invalid "+fname+" value for step %d")
+	}
+}
+
+func TestAggregateCountAndRange(t *testing.T) {
+	ctx, consolidationTestSeries := newConsolidationTestSeries()
+	defer ctx.Close()
+
+	for _, series := range consolidationTestSeries {
+		series.Specification = series.Name()
+	}
+
+	for _, test := range []struct {
+		fname        string
+		xFilesFactor float64
+		name         string
+		expected     []float64
+	}{
+		{"count", 0, "countSeries(a,b,c,d)", []float64{1, 3, 3, 1}},
+		{"rangeOf", 0, "rangeOfSeries(a,b,c,d)", []float64{0, 12, 14, 0}},
+		{"sum", 0.5, "sumSeries(a,b,c,d)", []float64{math.NaN(), 28, 30, math.NaN()}},
+	} {
+		r, err := aggregate(ctx, singlePathSpec{Values: consolidationTestSeries},
+			test.fname, test.xFilesFactor)
+		require.NoError(t, err)
+		require.Equal(t, 1, r.Len())
+
+		series := r.Values[0]
+		assert.Equal(t, test.name, series.Name())
+		require.Equal(t, 12, series.Len())
+		for i := 0; i < series.Len(); i++ {
+			expected := test.expected[i/3]
+			if math.IsNaN(expected) {
+				assert.True(t, math.IsNaN(series.ValueAt(i)), "invalid %s value for step %d", test.fname, i)
+			} else {
+				assert.Equal(t, expected, series.ValueAt(i), "invalid %s value for step %d", test.fname, i)
+			}
+		}
+	}
+
+	_, err := aggregate(ctx, singlePathSpec{Values: consolidationTestSeries}, "foo", 0)
+	require.Error(t, err)
+}
+
 func TestDivideSeries(t *testing.T) {
 	ctx, consolidationTestSeries := newConsolidationTestSeries()
 	defer ctx.Close()
@@ -331,6 +401,71 @@ func TestSumSeriesWithWildcards(t *testing.T) {
 	}
 }
 
+func TestAggregateWithWildcards(t *testing.T) {
+	ctx, _ := newConsolidationTestSeries()
+	defer ctx.Close()
+
+	input := []common.TestSeries{
+		{"web.host-1.num-requests.value", []float64{10.0, 10.0, 15.0, 10.0, 15.0}},
+		{"web.host-1.avg-response.value", []float64{70.0, 20.0, 30.0, 40.0, 50.0}},
+		{"web.host-2.avg-response.value", []float64{20.0, 30.0, 40.0, 50.0, 60.0}},
+		{"web.host-2.num-requests.value", []float64{20.0, 30.0, 15.0, 10.0, 15.0}},
+	}
+	expected := []common.TestSeries{
+		{"web.num-requests", []float64{20.0, 30.0, 15.0, 10.0, 15.0}},
+		{"web.avg-response", []float64{70.0, 30.0, 40.0, 50.0, 60.0}},
+	}
+
+	start := consolidationStartTime
+	step := 12000
+	timeSeries := generateSeriesList(ctx, start, input, step)
+	output, err := aggregateWithWildcards(ctx, singlePathSpec{
+		Values: timeSeries,
+	}, "max", 1, 3)
+	require.NoError(t, err)
+	common.CompareOutputsAndExpected(t, step, start, expected, output.Values)
+
+	_, err = aggregateWithWildcards(ctx, singlePathSpec{
+		Values: timeSeries,
+	}, "foo", 1)
+	require.Error(t, err)
+}
+
+func TestSumSeriesLists(t *testing.T) {
+	ctx, _ := newConsolidationTestSeries()
+	defer ctx.Close()
+
+	first := []common.TestSeries{
+		{"foo.a", []float64{1.0, 2.0, math.NaN()}},
+		{"foo.b", []float64{3.0, math.NaN(), math.NaN()}},
+	}
+	second := []common.TestSeries{
+		{"bar.a", []float64{10.0, 20.0, 30.0}},
+		{"bar.b", []float64{30.0, 40.0, math.NaN()}},
+	}
+	expected := []common.TestSeries{
+		{"sumSeries(foo.a,bar.a)", []float64{11.0, 22.0, 30.0}},
+		{"sumSeries(foo.b,bar.b)", []float64{33.0, 40.0, math.NaN()}},
+	}
+
+	start := consolidationStartTime
+	step := 10000
+	output, err := sumSeriesLists(ctx, singlePathSpec{
+		Values: generateSeriesList(ctx, start, first, step),
+	}, singlePathSpec{
+		Values: generateSeriesList(ctx, start, second, step),
+	})
+	require.NoError(t, err)
+	common.CompareOutputsAndExpected(t, step, start, expected, output.Values)
+
+	_, err = sumSeriesLists(ctx, singlePathSpec{
+		Values: generateSeriesList(ctx, start, first, step),
+	}, singlePathSpec{
+		Values: generateSeriesList(ctx, start, second[:1], step),
+	})
+	require.Error(t, err)
+}
+
 func TestGroupByNode(t *testing.T) {
 	var (
 		start, _ = time.Parse(time.RFC1123, "Mon, 27 Jul 2015 19:41:19 GMT")
@@ -401,6 +536,78 @@ func TestGroupByNode(t *testing.T) {
 	}
 }
 
+func TestGroupByTags(t *testing.T) {
+	ctx := common.NewTestContext()
+	defer ctx.Close()
+
+	ctx.Engine = mockEngine{fn: func(
+		ctx context.Context,
+		query string,
+		options storage.FetchOptions,
+	) (*storage.FetchResult, error) {
+		require.Equal(t, "name=cpu", query)
+		start := options.StartTime
+		return storage.NewFetchResult(ctx, []*ts.Series{
+			ts.NewSeries(ctx, "cpu;dc=east;host=a", start, ts.NewConstantValues(ctx, 1, 3, 1000)),
+			ts.NewSeries(ctx, "cpu;dc=east;host=b", start, ts.NewConstantValues(ctx, 2, 3, 1000)),
+			ts.NewSeries(ctx, "cpu;dc=west;host=c", start, ts.NewConstantValues(ctx, 4, 3, 1000)),
+		}, block.NewResultMetadata()), nil
+	}}
+
+	tests := []struct {
+		query    string
+		expected map[string]float64
+	}{
+		{
+			query: "groupByTags(seriesByTag('name=cpu'), 'sum', 'dc')",
+			expected: map[string]float64{
+				"sum;dc=east": 3,
+				"sum;dc=west": 4,
+			},
+		},
+		{
+			query: "groupByTags(seriesByTag('name=cpu'), 'max', 'name', 'dc')",
+			expected: map[string]float64{
+				"cpu;dc=east": 2,
+				"cpu;dc=west": 4,
+			},
+		},
+		{
+			query: "groupByTags(seriesByTag('name=cpu'), 'avg')",
+			expected: map[string]float64{
+				"avg": 7.0 / 3,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		expr, err := compile(test.query)
+		require.NoError(t, err)
+
+		r, err := expr.Execute(ctx)
+		require.NoError(t, err)
+
+		actual := make(map[string]float64, r.Len())
+		for _, series := range r.Values {
+			actual[series.Name()] = series.ValueAt(0)
+		}
+
+		assert.Equal(t, test.expected, actual, test.query)
+	}
+}
+
+func TestGroupByTagsInvalidName(t *testing.T) {
+	ctx := common.NewTestContext()
+	defer ctx.Close()
+
+	series := ts.NewSeries(ctx, "cpu;dc", ctx.StartTime,
+		ts.NewConstantValues(ctx, 1, 3, 1000))
+	_, err := groupByTags(ctx, singlePathSpec{
+		Values: []*ts.Series{series},
+	}, "sum", "dc")
+	require.Error(t, err)
+}
+
 func TestWeightedAverage(t *testing.T) {
 	ctx, _ := newConsolidationTestSeries()
 	defer ctx.Close()
@@ -21,7 +21,12 @@
 package native
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/m3db/m3/src/query/graphite/common"
+	"github.com/m3db/m3/src/query/graphite/errors"
+	"github.com/m3db/m3/src/query/graphite/graphite"
 	"github.com/m3db/m3/src/query/graphite/ts"
 )
 
@@ -43,6 +48,57 @@ func aliasByNode(ctx *common.Context, seriesList singlePathSpec, nodes ...int) (
 	return common.AliasByNode(ctx, ts.SeriesList(seriesList), nodes...)
 }
 
+// aliasByTags renames graphite tagged series according to a subset of their
+// tags, given by name, and of the nodes of their path, given by index.
+func aliasByTags(ctx *common.Context, seriesList singlePathSpec, tags ...genericInterface) (ts.SeriesList, error) {
+	renamed := make([]*ts.Series, 0, len(seriesList.Values))
+	for _, series := range seriesList.Values {
+		// NB: as with aliasByNode, use the innermost name of series which
+		// have been wrapped by other functions.
+		name := series.Name()
+		left := strings.LastIndex(name, "(") + 1
+		name = name[left:]
+		if right := strings.IndexAny(name, ",)"); right != -1 {
+			name = name[:right]
+		}
+
+		parsed, err := graphite.ParseTaggedName(name)
+		if err != nil {
+			return ts.NewSeriesList(), errors.NewInvalidParamsError(fmt.Errorf(
+				"could not alias %s by tags: %v", series.Name(), err))
+		}
+
+		nodes := strings.Split(parsed.Path, ".")
+		newNameParts := make([]string, 0, len(tags))
+		for _, tag := range tags {
+			switch t := tag.(type) {
+			case float64:
+				node := int(t)
+				if node < 0 {
+					node += len(nodes)
+				}
+				if node < 0 || node >= len(nodes) {
+					continue
+				}
+				newNameParts = append(newNameParts, nodes[node])
+			case string:
+				if value, ok := parsed.Tag(t); ok {
+					newNameParts = append(newNameParts, value)
+				}
+			default:
+				err := errors.NewInvalidParamsError(fmt.Errorf(
+					"invalid tag %v, must be a tag name or node index", tag))
+				return ts.NewSeriesList(), err
+			}
+		}
+
+		renamed = append(renamed, series.RenamedTo(strings.Join(newNameParts, ".")))
+	}
+
+	seriesList.Values = renamed
+	return ts.SeriesList(seriesList), nil
+}
+
 // aliasSub runs series names through a regex search/replace.
 func aliasSub(ctx *common.Context, input singlePathSpec, search, replace string) (ts.SeriesList, error) {
 	return common.AliasSub(ctx, ts.SeriesList(input), search, replace)
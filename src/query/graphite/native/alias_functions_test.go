@@ -24,7 +24,10 @@ import (
 	"testing"
 	"time"
 
+	"github.com/m3db/m3/src/query/block"
 	"github.com/m3db/m3/src/query/graphite/common"
+	"github.com/m3db/m3/src/query/graphite/context"
+	"github.com/m3db/m3/src/query/graphite/storage"
 	"github.com/m3db/m3/src/query/graphite/ts"
 
 	"github.com/stretchr/testify/assert"
@@ -217,3 +220,45 @@ func TestAliasByNodeWithComposition(t *testing.T) {
 	assert.Equal(t, "~~~", results.Values[2].Name())
 	assert.Equal(t, "", results.Values[3].Name())
 }
+
+func TestAliasByTags(t *testing.T) {
+	ctx := common.NewTestContext()
+	defer ctx.Close()
+
+	ctx.Engine = mockEngine{fn: func(
+		ctx context.Context,
+		query string,
+		options storage.FetchOptions,
+	) (*storage.FetchResult, error) {
+		start := options.StartTime
+		return storage.NewFetchResult(ctx, []*ts.Series{
+			ts.NewSeries(ctx, "cpu.load;dc=east;host=a", start, ts.NewConstantValues(ctx, 1, 3, 1000)),
+			ts.NewSeries(ctx, "cpu.load;dc=west", start, ts.NewConstantValues(ctx, 2, 3, 1000)),
+		}, block.NewResultMetadata()), nil
+	}}
+
+	tests := []struct {
+		query    string
+		expected []string
+	}{
+		{"aliasByTags(seriesByTag('name=cpu.load'), 'dc', 'host')", []string{"east.a", "west"}},
+		{"aliasByTags(seriesByTag('name=cpu.load'), 1, 'dc')", []string{"load.east", "load.west"}},
+		{"aliasByTags(seriesByTag('name=cpu.load'), 'name', -2)", []string{"cpu.load.cpu", "cpu.load.cpu"}},
+		{"aliasByTags(scale(seriesByTag('name=cpu.load'), 2), 'dc')", []string{"east", "west"}},
+	}
+
+	for _, test := range tests {
+		expr, err := compile(test.query)
+		require.NoError(t, err)
+
+		r, err := expr.Execute(ctx)
+		require.NoError(t, err)
+
+		var names []string
+		for _, series := range r.Values {
+			names = append(names, series.Name())
+		}
+
+		assert.Equal(t, test.expected, names, test.query)
+	}
+}
@@ -32,6 +32,8 @@ import (
 
 	"github.com/m3db/m3/src/query/graphite/common"
 	"github.com/m3db/m3/src/query/graphite/errors"
+	"github.com/m3db/m3/src/query/graphite/graphite"
+	"github.com/m3db/m3/src/query/graphite/storage"
 	"github.com/m3db/m3/src/query/graphite/ts"
 )
 
@@ -171,6 +173,45 @@ func currentBelow(ctx *common.Context, series singlePathSpec, n float64) (ts.Ser
 	return belowByFunction(ctx, series, sr, n)
 }
 
+// filterOperators are the comparison operators accepted by filterSeries.
+var filterOperators = map[string]valueComparator{
+	"=":  func(v, threshold float64) bool { return v == threshold },
+	"!=": func(v, threshold float64) bool { return v != threshold },
+	">":  func(v, threshold float64) bool { return v > threshold },
+	">=": func(v, threshold float64) bool { return v >= threshold },
+	"<":  func(v, threshold float64) bool { return v < threshold },
+	"<=": func(v, threshold float64) bool { return v <= threshold },
+}
+
+// filterSeries takes one metric or a wildcard seriesList followed by an aggregation
+// function, a comparison operator and a threshold, and returns only the metrics for
+// which the aggregated value of the series compares with the threshold, e.g.
+//
+//    &target=filterSeries(system.interface.eth*.packetsSent,"max",">",1000)
+//
+//  Would return only the interfaces which sent more than 1000 packets/min at some point.
+func filterSeries(
+	ctx *common.Context,
+	series singlePathSpec,
+	fname string,
+	operator string,
+	threshold float64,
+) (ts.SeriesList, error) {
+	f, _, err := getAggregationFunc(fname)
+	if err != nil {
+		return ts.NewSeriesList(), err
+	}
+
+	vc, ok := filterOperators[operator]
+	if !ok {
+		return ts.NewSeriesList(), errors.NewInvalidParamsError(fmt.Errorf("invalid operator %s", operator))
+	}
+
+	return compareByFunction(ctx, series, aggregationSeriesReducer(f), func(v, threshold float64) bool {
+		return !math.IsNaN(v) && vc(v, threshold)
+	}, threshold)
+}
+
 // constantLine takes value and creates a constant line at value.
 func constantLine(ctx *common.Context, value float64) (ts.SeriesList, error) {
 	newSeries, err := common.ConstantLine(ctx, value)
@@ -517,6 +558,17 @@ func highestAverage(_ *common.Context, input singlePathSpec, n int) (ts.SeriesLi
 	return takeByFunction(input, n, sr, ts.Descending)
 }
 
+// highest takes one metric or a wildcard seriesList followed by an integer n and
+// an aggregation function.  Out of all metrics passed, draws only the N metrics
+// with the highest aggregated value in the time period specified.
+func highest(_ *common.Context, input singlePathSpec, n int, fname string) (ts.SeriesList, error) {
+	f, _, err := getAggregationFunc(fname)
+	if err != nil {
+		return ts.NewSeriesList(), err
+	}
+	return takeByFunction(input, n, aggregationSeriesReducer(f), ts.Descending)
+}
+
 // fallbackSeries takes one metric or a wildcard seriesList, and a second fallback metric.
 // If the wildcard does not match any series, draws the fallback metric.
 func fallbackSeries(_ *common.Context, input singlePathSpec, fallback singlePathSpec) (ts.SeriesList, error) {
@@ -552,53 +604,90 @@ func lowestCurrent(_ *common.Context, input singlePathSpec, n int) (ts.SeriesLis
 	return takeByFunction(input, n, sr, ts.Ascending)
 }
 
+// lowest takes one metric or a wildcard seriesList followed by an integer n and
+// an aggregation function.  Out of all metrics passed, draws only the N metrics
+// with the lowest aggregated value in the time period specified.
+func lowest(_ *common.Context, input singlePathSpec, n int, fname string) (ts.SeriesList, error) {
+	f, _, err := getAggregationFunc(fname)
+	if err != nil {
+		return ts.NewSeriesList(), err
+	}
+	return takeByFunction(input, n, aggregationSeriesReducer(f), ts.Ascending)
+}
+
 // windowSizeFunc calculates window size for moving average calculation
 type windowSizeFunc func(stepSize int) int
 
 // movingAverage calculates the moving average of a metric (or metrics) over a time interval.
 func movingAverage(ctx *common.Context, input singlePathSpec, windowSizeValue genericInterface) (*binaryContextShifter, error) {
-	if len(input.Values) == 0 {
-		return nil, nil
-	}
-
-	var delta time.Duration
-	var wf windowSizeFunc
-	var ws string
+	return movingWindow(ctx, input, windowSizeValue, "movingAverage", movingRunningSum(true))
+}
 
+// parseWindowSize parses the window size of a moving function, given either as
+// an interval string or as a number of points, into the duration of the window,
+// a function returning the number of points in the window for a step size, and
+// the window size as formatted in the name of the resulting series.
+func parseWindowSize(
+	windowSizeValue genericInterface,
+	input singlePathSpec,
+) (time.Duration, windowSizeFunc, string, error) {
 	switch windowSizeValue := windowSizeValue.(type) {
 	case string:
 		interval, err := common.ParseInterval(windowSizeValue)
 		if err != nil {
-			return nil, err
+			return 0, nil, "", err
 		}
 		if interval <= 0 {
 			err := errors.NewInvalidParamsError(fmt.Errorf(
 				"windowSize must be positive but instead is %v",
 				interval))
-			return nil, err
+			return 0, nil, "", err
 		}
-		wf = func(stepSize int) int { return int(int64(delta/time.Millisecond) / int64(stepSize)) }
-		ws = fmt.Sprintf("%q", windowSizeValue)
-		delta = interval
+		wf := func(stepSize int) int { return int(int64(interval/time.Millisecond) / int64(stepSize)) }
+		return interval, wf, fmt.Sprintf("%q", windowSizeValue), nil
 	case float64:
 		windowSizeInt := int(windowSizeValue)
 		if windowSizeInt <= 0 {
 			err := errors.NewInvalidParamsError(fmt.Errorf(
 				"windowSize must be positive but instead is %d",
 				windowSizeInt))
-			return nil, err
+			return 0, nil, "", err
 		}
-		wf = func(_ int) int { return windowSizeInt }
-		ws = fmt.Sprintf("%d", windowSizeInt)
+		wf := func(_ int) int { return windowSizeInt }
 		maxStepSize := input.Values[0].MillisPerStep()
 		for i := 1; i < len(input.Values); i++ {
 			maxStepSize = int(math.Max(float64(maxStepSize), float64(input.Values[i].MillisPerStep())))
 		}
-		delta = time.Duration(maxStepSize*windowSizeInt) * time.Millisecond
+		delta := time.Duration(maxStepSize*windowSizeInt) * time.Millisecond
+		return delta, wf, fmt.Sprintf("%d", windowSizeInt), nil
 	default:
 		err := errors.NewInvalidParamsError(fmt.Errorf(
 			"windowSize must be either a string or an int but instead is a %T",
 			windowSizeValue))
+		return 0, nil, "", err
+	}
+}
+
+// movingWindowTransformFunc computes the values of a moving function for a series
+// given the series combined with its bootstrap, the offset of the series within it
+// and the number of points in the window.
+type movingWindowTransformFunc func(bootstrap *ts.Series, offset, windowPoints int, vals ts.MutableValues)
+
+// movingWindow applies a moving function with the given window size to each
+// series, bootstrapping the window from before the start of the series.
+func movingWindow(
+	ctx *common.Context,
+	input singlePathSpec,
+	windowSizeValue genericInterface,
+	fname string,
+	fn movingWindowTransformFunc,
+) (*binaryContextShifter, error) {
+	if len(input.Values) == 0 {
+		return nil, nil
+	}
+
+	delta, wf, ws, err := parseWindowSize(windowSizeValue, input)
+	if err != nil {
 		return nil, err
 	}
 
@@ -630,44 +719,12 @@ func movingAverage(ctx *common.Context, input singlePathSpec, windowSizeValue ge
 				return ts.NewSeriesList(), err
 			}
 
-			numSteps := series.Len()
-			offset := bootstrap.Len() - numSteps
-			vals := ts.NewValues(ctx, series.MillisPerStep(), numSteps)
-			sum := 0.0
-			num := 0
-			for i := 0; i < numSteps; i++ {
-				// skip if the number of points received is less than the number of points
-				// in the lookback window.
-				if offset < windowPoints {
-					continue
-				}
-				if i == 0 {
-					for j := offset - windowPoints; j < offset; j++ {
-						v := bootstrap.ValueAt(j)
-						if !math.IsNaN(v) {
-							sum += v
-							num++
-						}
-					}
-				} else {
-					prev := bootstrap.ValueAt(i + offset - windowPoints - 1)
-					next := bootstrap.ValueAt(i + offset - 1)
-					if !math.IsNaN(prev) {
-						sum -= prev
-						num--
-					}
-					if !math.IsNaN(next) {
-						sum += next
-						num++
-					}
-				}
-				if num > 0 {
-					vals.SetValueAt(i, sum/float64(num))
-				}
-			}
-			name := fmt.Sprintf("movingAverage(%s,%s)", series.Name(), ws)
-			newSeries := ts.NewSeries(ctx, name, series.StartTime(), vals)
-			results = append(results, newSeries)
+			offset := bootstrap.Len() - series.Len()
+			vals := ts.NewValues(ctx, stepSize, series.Len())
+			fn(bootstrap, offset, windowPoints, vals)
+
+			name := fmt.Sprintf("%s(%s,%s)", fname, series.Name(), ws)
+			results = append(results, ts.NewSeries(ctx, name, series.StartTime(), vals))
 		}
 
 		original.Values = results
@@ -680,6 +737,139 @@ func movingAverage(ctx *common.Context, input singlePathSpec, windowSizeValue ge
 	}, nil
 }
 
+// movingRunningSum returns a moving window transform which keeps a running sum
+// of the non-NaN values in the window preceding each point, updating it as the
+// window slides rather than rebuilding the window for every point. The sum is
+// divided by the number of values in the window when average is set.
+func movingRunningSum(average bool) movingWindowTransformFunc {
+	return func(bootstrap *ts.Series, offset, windowPoints int, vals ts.MutableValues) {
+		// skip if the number of points received is less than the number of points
+		// in the lookback window.
+		if offset < windowPoints {
+			return
+		}
+
+		sum := 0.0
+		num := 0
+		for i := 0; i < vals.Len(); i++ {
+			if i == 0 {
+				for j := offset - windowPoints; j < offset; j++ {
+					v := bootstrap.ValueAt(j)
+					if !math.IsNaN(v) {
+						sum += v
+						num++
+					}
+				}
+			} else {
+				prev := bootstrap.ValueAt(i + offset - windowPoints - 1)
+				next := bootstrap.ValueAt(i + offset - 1)
+				if !math.IsNaN(prev) {
+					sum -= prev
+					num--
+				}
+				if !math.IsNaN(next) {
+					sum += next
+					num++
+				}
+			}
+			if num == 0 {
+				continue
+			}
+			if average {
+				vals.SetValueAt(i, sum/float64(num))
+			} else {
+				vals.SetValueAt(i, sum)
+			}
+		}
+	}
+}
+
+// movingAggregation returns a moving window transform which applies the
+// aggregation function to the non-NaN values in the window preceding each
+// point, it is used by the functions which cannot be updated incrementally.
+func movingAggregation(f aggregationFunc) movingWindowTransformFunc {
+	return func(bootstrap *ts.Series, offset, windowPoints int, vals ts.MutableValues) {
+		// skip if the number of points received is less than the number of points
+		// in the lookback window.
+		if offset < windowPoints {
+			return
+		}
+
+		window := make([]float64, 0, windowPoints)
+		for i := 0; i < vals.Len(); i++ {
+			window = window[:0]
+			for j := i + offset - windowPoints; j < i+offset; j++ {
+				if v := bootstrap.ValueAt(j); !math.IsNaN(v) {
+					window = append(window, v)
+				}
+			}
+			if len(window) > 0 {
+				vals.SetValueAt(i, f(window))
+			}
+		}
+	}
+}
+
+// movingSum calculates the moving sum of a metric (or metrics) over a time interval.
+func movingSum(ctx *common.Context, input singlePathSpec, windowSize genericInterface) (*binaryContextShifter, error) {
+	return movingWindow(ctx, input, windowSize, "movingSum", movingRunningSum(false))
+}
+
+// movingMin calculates the moving minimum of a metric (or metrics) over a time interval.
+func movingMin(ctx *common.Context, input singlePathSpec, windowSize genericInterface) (*binaryContextShifter, error) {
+	return movingWindow(ctx, input, windowSize, "movingMin", movingAggregation(aggregateMin))
+}
+
+// movingMax calculates the moving maximum of a metric (or metrics) over a time interval.
+func movingMax(ctx *common.Context, input singlePathSpec, windowSize genericInterface) (*binaryContextShifter, error) {
+	return movingWindow(ctx, input, windowSize, "movingMax", movingAggregation(aggregateMax))
+}
+
+// exponentialMovingAverage calculates the exponential moving average of a metric
+// (or metrics) over a time interval. The average is seeded with the average of the
+// window preceding the series, then each point p updates the average to
+// c * p + (1 - c) * average, where c is 2 / (windowPoints + 1). As with the other
+// moving functions the value at each point reflects the points preceding it.
+func exponentialMovingAverage(
+	ctx *common.Context,
+	input singlePathSpec,
+	windowSize genericInterface,
+) (*binaryContextShifter, error) {
+	return movingWindow(ctx, input, windowSize, "exponentialMovingAverage",
+		func(bootstrap *ts.Series, offset, windowPoints int, vals ts.MutableValues) {
+			var (
+				constant = 2.0 / (float64(windowPoints) + 1)
+				ema      = 0.0
+				sum      = 0.0
+				num      = 0
+			)
+
+			for j := offset - windowPoints; j < offset; j++ {
+				if j < 0 {
+					continue
+				}
+				if v := bootstrap.ValueAt(j); !math.IsNaN(v) {
+					sum += v
+					num++
+				}
+			}
+			if num > 0 {
+				ema = sum / float64(num)
+			}
+
+			for i := 0; i < vals.Len(); i++ {
+				if i > 0 {
+					v := bootstrap.ValueAt(i + offset - 1)
+					if math.IsNaN(v) {
+						continue
+					}
+					ema = constant*v + (1-constant)*ema
+				}
+				vals.SetValueAt(i, ema)
+			}
+		})
+}
+
 // totalFunc takes an index and returns a total value for that index
 type totalFunc func(int) float64
 
@@ -845,6 +1035,48 @@ func group(_ *common.Context, input multiplePathSpecs) (ts.SeriesList, error) {
 	return ts.SeriesList(input), nil
 }
 
+// seriesByTag returns the graphite tagged series which match all of the given
+// tag expressions, e.g.
+//
+//    &target=seriesByTag('name=cpu.load','dc=~us-.*','host!=db1')
+//
+// At least one expression must match only non-empty tag values, and the path
+// of a series is matched with the 'name' tag.
+func seriesByTag(ctx *common.Context, tagExpressions ...string) (ts.SeriesList, error) {
+	begin := time.Now()
+	opts := storage.FetchOptions{
+		StartTime: ctx.StartTime,
+		EndTime:   ctx.EndTime,
+		DataOptions: storage.DataOptions{
+			Timeout: ctx.Timeout,
+			Limit:   ctx.Limit,
+		},
+	}
+
+	result, err := ctx.Engine.FetchByTags(ctx, tagExpressions, opts)
+	if err != nil {
+		return ts.NewSeriesList(), err
+	}
+
+	spec := fmt.Sprintf("seriesByTag('%s')", strings.Join(tagExpressions, "','"))
+	if ctx.TracingEnabled() {
+		ctx.Trace(common.Trace{
+			ActivityName: fmt.Sprintf("fetch %s", spec),
+			Duration:     time.Since(begin),
+			Outputs:      common.TraceStats{NumSeries: len(result.SeriesList)},
+		})
+	}
+
+	for _, r := range result.SeriesList {
+		r.Specification = spec
+	}
+
+	return ts.SeriesList{
+		Values:   result.SeriesList,
+		Metadata: result.Metadata,
+	}, nil
+}
+
 func derivativeTemplate(ctx *common.Context, input singlePathSpec, nameTemplate string,
 	fn func(float64, float64) float64) (ts.SeriesList, error) {
 
@@ -1826,16 +2058,472 @@ func threshold(ctx *common.Context, value float64, label string, color string) (
 	return ts.NewSeriesListWithSeries(series), nil
 }
 
+// evaluateTarget compiles and executes a target built by a function from its
+// arguments within the given context.
+func evaluateTarget(ctx *common.Context, target string) (ts.SeriesList, error) {
+	expr, err := compile(target)
+	if err != nil {
+		return ts.NewSeriesList(), err
+	}
+	return expr.Execute(ctx)
+}
+
+// applyByNode takes a seriesList and applies the template function to each unique
+// prefix of the series names up to and including the given node, with '%' in the
+// template replaced by the prefix, e.g.
+//
+//    &target=applyByNode(servers.*.disk.bytes_free,1,"divideSeries(%.disk.bytes_free,sumSeries(%.disk.bytes_*))")
+//
+//  Would return the fraction of free disk space for each server. If newName is given,
+//  the resulting series are renamed to it with '%' replaced by the prefix.
+func applyByNode(
+	ctx *common.Context,
+	seriesList singlePathSpec,
+	nodeNum int,
+	templateFunction string,
+	newName string,
+) (ts.SeriesList, error) {
+	if nodeNum < 0 {
+		err := errors.NewInvalidParamsError(fmt.Errorf("nodeNum must not be negative but is %d", nodeNum))
+		return ts.NewSeriesList(), err
+	}
+
+	var (
+		prefixes []string
+		seen     = make(map[string]struct{})
+	)
+
+	for _, series := range seriesList.Values {
+		parts := strings.Split(series.Name(), ".")
+		prefix := strings.Join(parts[:min(nodeNum+1, len(parts))], ".")
+		if _, exists := seen[prefix]; exists {
+			continue
+		}
+		seen[prefix] = struct{}{}
+		prefixes = append(prefixes, prefix)
+	}
+	sort.Strings(prefixes)
+
+	r := ts.SeriesList(seriesList)
+	results := make([]*ts.Series, 0, len(prefixes))
+	for _, prefix := range prefixes {
+		output, err := evaluateTarget(ctx, strings.Replace(templateFunction, "%", prefix, -1))
+		if err != nil {
+			return ts.NewSeriesList(), err
+		}
+
+		for _, series := range output.Values {
+			if newName != "" {
+				series = series.RenamedTo(strings.Replace(newName, "%", prefix, -1))
+			}
+			results = append(results, series)
+		}
+		r.Metadata = r.Metadata.CombineMetadata(output.Metadata)
+	}
+
+	r.Values = results
+	r.SortApplied = false
+	return r, nil
+}
+
+// delay shifts all values of each series by the given number of steps, filling
+// the points left behind with null. A negative number of steps shifts the values
+// backwards instead.
+func delay(ctx *common.Context, input singlePathSpec, steps int) (ts.SeriesList, error) {
+	results := make([]*ts.Series, 0, len(input.Values))
+	for _, series := range input.Values {
+		outvals := ts.NewValues(ctx, series.MillisPerStep(), series.Len())
+		for i := 0; i < series.Len(); i++ {
+			if j := i - steps; j >= 0 && j < series.Len() {
+				outvals.SetValueAt(i, series.ValueAt(j))
+			}
+		}
+
+		name := fmt.Sprintf("delay(%s,%d)", series.Name(), steps)
+		results = append(results, ts.NewSeries(ctx, name, series.StartTime(), outvals))
+	}
+
+	r := ts.SeriesList(input)
+	r.Values = results
+	return r, nil
+}
+
+// integralByInterval shows the sum over time like integral, but resets the sum to
+// zero at each interval boundary, aligned to the start of the requested time range.
+func integralByInterval(ctx *common.Context, input singlePathSpec, intervalUnit string) (ts.SeriesList, error) {
+	interval, err := common.ParseInterval(intervalUnit)
+	if err != nil {
+		return ts.NewSeriesList(), err
+	}
+	if interval < 0 {
+		interval = -interval
+	}
+	if interval == 0 {
+		return ts.NewSeriesList(), common.ErrInvalidIntervalFormat
+	}
+
+	intervalNum := func(t time.Time) float64 {
+		return math.Floor(float64(t.Sub(ctx.StartTime)) / float64(interval))
+	}
+
+	results := make([]*ts.Series, 0, len(input.Values))
+	for _, series := range input.Values {
+		var (
+			outvals = ts.NewValues(ctx, series.MillisPerStep(), series.Len())
+			step    = time.Duration(series.MillisPerStep()) * time.Millisecond
+			current float64
+		)
+
+		for i := 0; i < series.Len(); i++ {
+			t := series.StartTimeForStep(i)
+			if intervalNum(t) != intervalNum(t.Add(-step)) {
+				current = 0
+			}
+			if v := series.ValueAt(i); !math.IsNaN(v) {
+				current += v
+			}
+			outvals.SetValueAt(i, current)
+		}
+
+		newName := fmt.Sprintf("integralByInterval(%s,%q)", series.Name(), intervalUnit)
+		results = append(results, ts.NewSeries(ctx, newName, series.StartTime(), outvals))
+	}
+
+	r := ts.SeriesList(input)
+	r.Values = results
+	return r, nil
+}
+
+// interpolate fills in gaps of null values in each series by linear interpolation
+// between the values either side of the gap, provided the gap is no longer than
+// limit points. Null values at the start or end of a series are left as is.
+func interpolate(ctx *common.Context, input singlePathSpec, limit float64) (ts.SeriesList, error) {
+	results := make([]*ts.Series, 0, len(input.Values))
+	for _, series := range input.Values {
+		outvals := ts.NewValues(ctx, series.MillisPerStep(), series.Len())
+		lastIndex := -1
+		for i := 0; i < series.Len(); i++ {
+			v := series.ValueAt(i)
+			if math.IsNaN(v) {
+				continue
+			}
+			outvals.SetValueAt(i, v)
+
+			gap := i - lastIndex - 1
+			if lastIndex >= 0 && gap > 0 && float64(gap) <= limit {
+				last := series.ValueAt(lastIndex)
+				for j := lastIndex + 1; j < i; j++ {
+					fraction := float64(j-lastIndex) / float64(gap+1)
+					outvals.SetValueAt(j, last+(v-last)*fraction)
+				}
+			}
+			lastIndex = i
+		}
+
+		name := fmt.Sprintf("interpolate(%s)", series.Name())
+		results = append(results, ts.NewSeries(ctx, name, series.StartTime(), outvals))
+	}
+
+	r := ts.SeriesList(input)
+	r.Values = results
+	return r, nil
+}
+
+// linearRegression graphs the linear regression function by the least squares
+// method, fitted to each series over the time range from startSourceAt to
+// endSourceAt, which default to the requested time range. Relative times are
+// relative to the end of the requested time range.
+func linearRegression(
+	ctx *common.Context,
+	_ singlePathSpec,
+	startSourceAt string,
+	endSourceAt string,
+) (*binaryContextShifter, error) {
+	var (
+		sourceStart, sourceEnd = ctx.StartTime, ctx.EndTime
+		err                    error
+	)
+
+	if startSourceAt != "" {
+		sourceStart, err = graphite.ParseTime(startSourceAt, ctx.EndTime, 0)
+		if err != nil {
+			return nil, errors.NewInvalidParamsError(fmt.Errorf("invalid startSourceAt %s: %v", startSourceAt, err))
+		}
+	}
+	if endSourceAt != "" {
+		sourceEnd, err = graphite.ParseTime(endSourceAt, ctx.EndTime, 0)
+		if err != nil {
+			return nil, errors.NewInvalidParamsError(fmt.Errorf("invalid endSourceAt %s: %v", endSourceAt, err))
+		}
+	}
+	if !sourceStart.Before(sourceEnd) {
+		err := errors.NewInvalidParamsError(fmt.Errorf(
+			"source start %v must be before source end %v", sourceStart, sourceEnd))
+		return nil, err
+	}
+
+	contextShiftingFn := func(c *common.Context) *common.Context {
+		opts := common.NewChildContextOptions()
+		opts.AdjustTimeRange(sourceStart.Sub(c.StartTime), sourceEnd.Sub(c.EndTime), 0, 0)
+		childCtx := c.NewChildContext(opts)
+		return childCtx
+	}
+
+	transformerFn := func(source, original ts.SeriesList) (ts.SeriesList, error) {
+		nameToSource := make(map[string]*ts.Series, source.Len())
+		for _, series := range source.Values {
+			nameToSource[series.Name()] = series
+		}
+
+		results := make([]*ts.Series, 0, original.Len())
+		for _, series := range original.Values {
+			src, found := nameToSource[series.Name()]
+			if !found {
+				continue
+			}
+
+			factor, offset, ok := linearRegressionAnalysis(src)
+			if !ok {
+				continue
+			}
+
+			vals := ts.NewValues(ctx, series.MillisPerStep(), series.Len())
+			for i := 0; i < series.Len(); i++ {
+				t := float64(series.StartTimeForStep(i).Unix())
+				vals.SetValueAt(i, offset+t*factor)
+			}
+
+			name := fmt.Sprintf("linearRegression(%s, %d, %d)",
+				series.Name(), sourceStart.Unix(), sourceEnd.Unix())
+			results = append(results, ts.NewSeries(ctx, name, series.StartTime(), vals))
+		}
+
+		original.Values = results
+		return original, nil
+	}
+
+	return &binaryContextShifter{
+		ContextShiftFunc:  contextShiftingFn,
+		BinaryTransformer: transformerFn,
+	}, nil
+}
+
+// linearRegressionAnalysis returns the factor and offset of the least squares line
+// through the non-NaN values of the series, with time in seconds, or false if the
+// series does not have enough values to fit a line.
+func linearRegressionAnalysis(series *ts.Series) (float64, float64, bool) {
+	var n, sumI, sumV, sumII, sumIV float64
+	for i := 0; i < series.Len(); i++ {
+		v := series.ValueAt(i)
+		if math.IsNaN(v) {
+			continue
+		}
+		fi := float64(i)
+		n++
+		sumI += fi
+		sumV += v
+		sumII += fi * fi
+		sumIV += fi * v
+	}
+
+	denominator := n*sumII - sumI*sumI
+	if denominator == 0 {
+		return 0, 0, false
+	}
+
+	stepInSecs := float64(series.MillisPerStep()) / millisPerSecond
+	factor := (n*sumIV - sumI*sumV) / denominator / stepInSecs
+	offset := (sumII*sumV-sumIV*sumI)/denominator - factor*float64(series.StartTime().Unix())
+	return factor, offset, true
+}
+
+// minMax normalizes each series to values between 0 and 1, relative to the
+// minimum and maximum values of the series.
+func minMax(ctx *common.Context, seriesList singlePathSpec) (ts.SeriesList, error) {
+	results := make([]*ts.Series, len(seriesList.Values))
+	for idx, series := range seriesList.Values {
+		var (
+			minimum  = series.SafeMin()
+			maximum  = series.SafeMax()
+			numSteps = series.Len()
+			vals     = ts.NewValues(ctx, series.MillisPerStep(), numSteps)
+		)
+
+		for i := 0; i < numSteps; i++ {
+			v := series.ValueAt(i)
+			if math.IsNaN(v) {
+				continue
+			}
+			if maximum == minimum {
+				vals.SetValueAt(i, 0)
+			} else {
+				vals.SetValueAt(i, (v-minimum)/(maximum-minimum))
+			}
+		}
+
+		name := fmt.Sprintf("minMax(%s)", series.Name())
+		results[idx] = ts.NewSeries(ctx, name, series.StartTime(), vals)
+	}
+
+	r := ts.SeriesList(seriesList)
+	r.Values = results
+	return r, nil
+}
+
+// pow raises each element of a collection of time series to the given power.
+func pow(ctx *common.Context, input singlePathSpec, factor float64) (ts.SeriesList, error) {
+	return transform(
+		ctx,
+		input,
+		func(fname string) string {
+			newName := fmt.Sprintf("%s,"+common.FloatingPointFormat, fname, factor)
+			return fmt.Sprintf(wrappingFmt, "pow", newName)
+		},
+		common.MaintainNaNTransformer(func(v float64) float64 { return math.Pow(v, factor) }),
+	)
+}
+
+// timeStack draws the selected metrics shifted back in time by each multiple of
+// timeShiftUnit from timeShiftStart up to, but excluding, timeShiftEnd, stacked on
+// the current time range. If no sign is given, a minus sign ( - ) is implied.
+func timeStack(
+	ctx *common.Context,
+	input singlePathSpec,
+	timeShiftUnit string,
+	timeShiftStart int,
+	timeShiftEnd int,
+) (ts.SeriesList, error) {
+	if len(input.Values) == 0 {
+		return ts.SeriesList(input), nil
+	}
+
+	if !(strings.HasPrefix(timeShiftUnit, "+") || strings.HasPrefix(timeShiftUnit, "-")) {
+		timeShiftUnit = "-" + timeShiftUnit
+	}
+
+	delta, err := common.ParseInterval(timeShiftUnit)
+	if err != nil {
+		return ts.NewSeriesList(), errors.NewInvalidParamsError(fmt.Errorf(
+			"invalid timeShiftUnit parameter %s: %v", timeShiftUnit, err))
+	}
+
+	// NB: all series in the list share the same specification, which is
+	// evaluated again for each shifted time range.
+	var (
+		spec    = input.Values[0].Specification
+		r       = ts.SeriesList(input)
+		results []*ts.Series
+	)
+
+	for shift := timeShiftStart; shift < timeShiftEnd; shift++ {
+		innerDelta := delta * time.Duration(shift)
+		opts := common.NewChildContextOptions()
+		opts.AdjustTimeRange(innerDelta, innerDelta, 0, 0)
+		shifted, err := evaluateTarget(ctx.NewChildContext(opts), spec)
+		if err != nil {
+			return ts.NewSeriesList(), err
+		}
+
+		for _, series := range shifted.Values {
+			name := fmt.Sprintf("timeShift(%s, %s, %d)", series.Name(), timeShiftUnit, shift)
+			results = append(results, series.Shift(-innerDelta).RenamedTo(name))
+		}
+		r.Metadata = r.Metadata.CombineMetadata(shifted.Metadata)
+	}
+
+	r.Values = results
+	return r, nil
+}
+
+// useSeriesAbove compares the maximum of each series against the given value and,
+// for each series above it, draws the series named by running its name through the
+// regex search and replace instead, e.g.
+//
+//    &target=useSeriesAbove(ganglia.metric1.reqs,10,"reqs","time")
+//
+//  Would draw ganglia.metric1.time if the maximum of ganglia.metric1.reqs is above 10.
+func useSeriesAbove(
+	ctx *common.Context,
+	seriesList singlePathSpec,
+	value float64,
+	search string,
+	replace string,
+) (ts.SeriesList, error) {
+	above, err := maximumAbove(ctx, seriesList, value)
+	if err != nil {
+		return ts.NewSeriesList(), err
+	}
+
+	renamed, err := common.AliasSub(ctx, above, search, replace)
+	if err != nil {
+		return ts.NewSeriesList(), err
+	}
+
+	r := ts.SeriesList(seriesList)
+	results := make([]*ts.Series, 0, renamed.Len())
+	for _, series := range renamed.Values {
+		output, err := evaluateTarget(ctx, series.Name())
+		if err != nil {
+			return ts.NewSeriesList(), err
+		}
+
+		if output.Len() > 0 {
+			results = append(results, output.Values[0])
+		}
+		r.Metadata = r.Metadata.CombineMetadata(output.Metadata)
+	}
+
+	r.Values = results
+	return r, nil
+}
+
+// verticalLine draws a vertical line at the given timestamp, which must be within
+// the requested time range, labelled with the given label or the timestamp.
+// A relative timestamp is relative to the end of the requested time range.
+func verticalLine(ctx *common.Context, timestamp string, label string, _ string) (ts.SeriesList, error) {
+	t, err := graphite.ParseTime(timestamp, ctx.EndTime, 0)
+	if err != nil {
+		return ts.NewSeriesList(), errors.NewInvalidParamsError(fmt.Errorf(
+			"invalid timestamp %s: %v", timestamp, err))
+	}
+
+	if t.Before(ctx.StartTime) {
+		return ts.NewSeriesList(), errors.NewInvalidParamsError(fmt.Errorf(
+			"verticalLine timestamp %s is before the start of the range", timestamp))
+	}
+	if t.After(ctx.EndTime) {
+		return ts.NewSeriesList(), errors.NewInvalidParamsError(fmt.Errorf(
+			"verticalLine timestamp %s is after the end of the range", timestamp))
+	}
+
+	if label == "" {
+		label = timestamp
+	}
+
+	vals := ts.NewValues(ctx, millisPerSecond, 2)
+	vals.SetValueAt(0, 1)
+	vals.SetValueAt(1, 1)
+	return ts.NewSeriesListWithSeries(ts.NewSeries(ctx, label, t, vals)), nil
+}
+
 func init() {
 	// functions - in alpha ordering
 	MustRegisterFunction(absolute)
+	MustRegisterFunction(aggregate).WithDefaultParams(map[uint8]interface{}{
+		3: 0.0, // xFilesFactor
+	})
 	MustRegisterFunction(aggregateLine).WithDefaultParams(map[uint8]interface{}{
 		2: "avg", // f
 	})
+	MustRegisterFunction(aggregateWithWildcards)
 	MustRegisterFunction(alias)
 	MustRegisterFunction(aliasByMetric)
 	MustRegisterFunction(aliasByNode)
+	MustRegisterFunction(aliasByTags)
 	MustRegisterFunction(aliasSub)
+	MustRegisterFunction(applyByNode).WithDefaultParams(map[uint8]interface{}{
+		4: "", // newName
+	})
 	MustRegisterFunction(asPercent).WithDefaultParams(map[uint8]interface{}{
 		2: []*ts.Series(nil), // total
 	})
@@ -1852,13 +2540,21 @@ func init() {
 	MustRegisterFunction(dashed).WithDefaultParams(map[uint8]interface{}{
 		2: 5.0, // dashLength
 	})
+	MustRegisterFunction(delay)
 	MustRegisterFunction(derivative)
 	MustRegisterFunction(diffSeries)
 	MustRegisterFunction(divideSeries)
 	MustRegisterFunction(exclude)
+	MustRegisterFunction(exponentialMovingAverage)
 	MustRegisterFunction(fallbackSeries)
+	MustRegisterFunction(filterSeries)
 	MustRegisterFunction(group)
 	MustRegisterFunction(groupByNode)
+	MustRegisterFunction(groupByTags)
+	MustRegisterFunction(highest).WithDefaultParams(map[uint8]interface{}{
+		2: 1,         // n
+		3: "average", // func
+	})
 	MustRegisterFunction(highestAverage)
 	MustRegisterFunction(highestCurrent)
 	MustRegisterFunction(highestMax)
@@ -1868,24 +2564,40 @@ func init() {
 	MustRegisterFunction(holtWintersForecast)
 	MustRegisterFunction(identity)
 	MustRegisterFunction(integral)
+	MustRegisterFunction(integralByInterval)
+	MustRegisterFunction(interpolate).WithDefaultParams(map[uint8]interface{}{
+		2: math.Inf(1), // limit
+	})
 	MustRegisterFunction(isNonNull)
 	MustRegisterFunction(keepLastValue).WithDefaultParams(map[uint8]interface{}{
 		2: -1, // limit
 	})
 	MustRegisterFunction(legendValue)
 	MustRegisterFunction(limit)
+	MustRegisterFunction(linearRegression).WithDefaultParams(map[uint8]interface{}{
+		2: "", // startSourceAt
+		3: "", // endSourceAt
+	})
 	MustRegisterFunction(logarithm).WithDefaultParams(map[uint8]interface{}{
 		2: 10, // base
 	})
+	MustRegisterFunction(lowest).WithDefaultParams(map[uint8]interface{}{
+		2: 1,         // n
+		3: "average", // func
+	})
 	MustRegisterFunction(lowestAverage)
 	MustRegisterFunction(lowestCurrent)
 	MustRegisterFunction(maxSeries)
 	MustRegisterFunction(maximumAbove)
+	MustRegisterFunction(minMax)
 	MustRegisterFunction(minSeries)
 	MustRegisterFunction(minimumAbove)
 	MustRegisterFunction(mostDeviant)
 	MustRegisterFunction(movingAverage)
+	MustRegisterFunction(movingMax)
 	MustRegisterFunction(movingMedian)
+	MustRegisterFunction(movingMin)
+	MustRegisterFunction(movingSum)
 	MustRegisterFunction(multiplySeries)
 	MustRegisterFunction(nonNegativeDerivative).WithDefaultParams(map[uint8]interface{}{
 		2: math.NaN(), // maxValue
@@ -1899,6 +2611,7 @@ func init() {
 	MustRegisterFunction(perSecond).WithDefaultParams(map[uint8]interface{}{
 		2: math.NaN(), // maxValue
 	})
+	MustRegisterFunction(pow)
 	MustRegisterFunction(rangeOfSeries)
 	MustRegisterFunction(randomWalkFunction).WithDefaultParams(map[uint8]interface{}{
 		2: 60, // step
@@ -1910,6 +2623,11 @@ func init() {
 	MustRegisterFunction(removeEmptySeries)
 	MustRegisterFunction(scale)
 	MustRegisterFunction(scaleToSeconds)
+	MustRegisterFunction(seriesByTag)
+	MustRegisterFunction(smartSummarize).WithDefaultParams(map[uint8]interface{}{
+		3: "sum", // fname
+		4: "",    // alignTo
+	})
 	MustRegisterFunction(sortByMaxima)
 	MustRegisterFunction(sortByName)
 	MustRegisterFunction(sortByTotal)
@@ -1926,6 +2644,7 @@ func init() {
 		4: false, // alignToFrom
 	})
 	MustRegisterFunction(sumSeries)
+	MustRegisterFunction(sumSeriesLists)
 	MustRegisterFunction(sumSeriesWithWildcards)
 	MustRegisterFunction(sustainedAbove)
 	MustRegisterFunction(sustainedBelow)
@@ -1939,9 +2658,19 @@ func init() {
 	MustRegisterFunction(timeShift).WithDefaultParams(map[uint8]interface{}{
 		3: true, // resetEnd
 	})
+	MustRegisterFunction(timeStack).WithDefaultParams(map[uint8]interface{}{
+		2: "1d", // timeShiftUnit
+		3: 0,    // timeShiftStart
+		4: 7,    // timeShiftEnd
+	})
 	MustRegisterFunction(transformNull).WithDefaultParams(map[uint8]interface{}{
 		2: 0.0, // defaultValue
 	})
+	MustRegisterFunction(useSeriesAbove)
+	MustRegisterFunction(verticalLine).WithDefaultParams(map[uint8]interface{}{
+		2: "", // label
+		3: "", // color
+	})
 	MustRegisterFunction(weightedAverage)
 
 	// alias functions - in alpha ordering
@@ -1951,10 +2680,6 @@ func init() {
 	MustRegisterAliasedFunction("max", maxSeries)
 	MustRegisterAliasedFunction("min", minSeries)
 	MustRegisterAliasedFunction("randomWalk", randomWalkFunction)
-	// NB(jayp): Graphite docs say that smartSummarize is the "smarter experimental version of
-	// summarize". Well, I am not sure about smarter, but aliasing satisfies the experimental
-	// aspect.
-	MustRegisterAliasedFunction("smartSummarize", summarize)
 	MustRegisterAliasedFunction("sum", sumSeries)
 	MustRegisterAliasedFunction("time", timeFunction)
 }
@@ -682,6 +682,36 @@ func TestMovingAverageError(t *testing.T) {
 	testMovingAverageError(t, "movingAverage(foo.bar.baz, 0)")
 }
 
+func TestMovingFunctionsSuccess(t *testing.T) {
+	values := []float64{12.0, 19.0, -10.0, math.NaN(), 10.0}
+	bootstrap := []float64{3.0, 4.0, 5.0}
+	tests := []struct {
+		fname    string
+		expected []float64
+	}{
+		{"movingSum", []float64{12.0, 21.0, 36.0, 21.0, 9.0}},
+		{"movingMin", []float64{3.0, 4.0, 5.0, -10.0, -10.0}},
+		{"movingMax", []float64{5.0, 12.0, 19.0, 19.0, 19.0}},
+		{"exponentialMovingAverage", []float64{4.0, 8.0, 13.5, 1.75, math.NaN()}},
+	}
+
+	for _, test := range tests {
+		testMovingAverage(t, fmt.Sprintf("%s(foo.bar.baz, '30s')", test.fname),
+			fmt.Sprintf("%s(foo.bar.baz,\"30s\")", test.fname), values, bootstrap, test.expected)
+		testMovingAverage(t, fmt.Sprintf("%s(foo.bar.baz, 3)", test.fname),
+			fmt.Sprintf("%s(foo.bar.baz,3)", test.fname), values, bootstrap, test.expected)
+		testMovingAverage(t, fmt.Sprintf("%s(foo.bar.baz, 3)", test.fname),
+			fmt.Sprintf("%s(foo.bar.baz,3)", test.fname), nil, nil, nil)
+	}
+}
+
+func TestMovingFunctionsError(t *testing.T) {
+	for _, fname := range []string{"movingSum", "movingMin", "movingMax", "exponentialMovingAverage"} {
+		testMovingAverageError(t, fmt.Sprintf("%s(foo.bar.baz, '-30s')", fname))
+		testMovingAverageError(t, fmt.Sprintf("%s(foo.bar.baz, 0)", fname))
+	}
+}
+
 func TestIsNonNull(t *testing.T) {
 	ctx := common.NewTestContext()
 	defer ctx.Close()
@@ -1961,6 +1991,12 @@ func (*mockStorage) FetchByQuery(
 	return storage.NewFetchResult(ctx, nil, block.NewResultMetadata()), nil
 }
 
+func (*mockStorage) FetchByTags(
+	ctx xctx.Context, tagExpressions []string, opts storage.FetchOptions,
+) (*storage.FetchResult, error) {
+	return storage.NewFetchResult(ctx, nil, block.NewResultMetadata()), nil
+}
+
 func TestHoltWintersForecast(t *testing.T) {
 	ctx := common.NewTestContext()
 	ctx.Engine = NewEngine(
@@ -2775,15 +2811,476 @@ func TestThreshold(t *testing.T) {
 	require.Equal(t, "1.000", results[0].Name())
 }
 
+func TestHighestAndLowest(t *testing.T) {
+	ctx := common.NewTestContext()
+	defer ctx.Close()
+
+	nan := math.NaN()
+	inputs := []common.TestSeries{
+		{"a", []float64{1, 2, 3}},
+		{"b", []float64{5, nan, 2}},
+		{"c", []float64{nan, nan, nan}},
+	}
+
+	tests := []struct {
+		f        func(*common.Context, singlePathSpec, int, string) (ts.SeriesList, error)
+		n        int
+		fname    string
+		expected []common.TestSeries
+	}{
+		{highest, 2, "average", []common.TestSeries{inputs[1], inputs[0]}},
+		{highest, 1, "max", []common.TestSeries{inputs[1]}},
+		{highest, 1, "sumSeries", []common.TestSeries{inputs[1]}},
+		{lowest, 2, "sum", []common.TestSeries{inputs[2], inputs[0]}},
+		{lowest, 1, "current", []common.TestSeries{inputs[2]}},
+	}
+
+	start := ctx.StartTime
+	step := 100
+	for _, test := range tests {
+		outputs, err := test.f(ctx, singlePathSpec{
+			Values: generateSeriesList(ctx, start, inputs, step),
+		}, test.n, test.fname)
+		require.NoError(t, err)
+		common.CompareOutputsAndExpected(t, step, start, test.expected, outputs.Values)
+	}
+
+	_, err := highest(ctx, singlePathSpec{
+		Values: generateSeriesList(ctx, start, inputs, step),
+	}, 1, "foo")
+	require.Error(t, err)
+}
+
+func TestFilterSeries(t *testing.T) {
+	ctx := common.NewTestContext()
+	defer ctx.Close()
+
+	nan := math.NaN()
+	inputs := []common.TestSeries{
+		{"a", []float64{1, 2, 3}},
+		{"b", []float64{5, nan, 2}},
+		{"c", []float64{nan, nan, nan}},
+	}
+
+	tests := []struct {
+		fname     string
+		operator  string
+		threshold float64
+		expected  []common.TestSeries
+	}{
+		{"max", ">", 3, []common.TestSeries{inputs[1]}},
+		{"max", ">=", 3, []common.TestSeries{inputs[0], inputs[1]}},
+		{"max", "<=", 3, []common.TestSeries{inputs[0]}},
+		{"max", "!=", 3, []common.TestSeries{inputs[1]}},
+		{"sum", "=", 6, []common.TestSeries{inputs[0]}},
+		{"last", "<", 3, []common.TestSeries{inputs[1]}},
+		{"count", "=", 3, []common.TestSeries{inputs[0]}},
+	}
+
+	start := ctx.StartTime
+	step := 100
+	for _, test := range tests {
+		outputs, err := filterSeries(ctx, singlePathSpec{
+			Values: generateSeriesList(ctx, start, inputs, step),
+		}, test.fname, test.operator, test.threshold)
+		require.NoError(t, err)
+		common.CompareOutputsAndExpected(t, step, start, test.expected, outputs.Values)
+	}
+
+	_, err := filterSeries(ctx, singlePathSpec{
+		Values: generateSeriesList(ctx, start, inputs, step),
+	}, "max", "~", 3)
+	require.Error(t, err)
+
+	_, err = filterSeries(ctx, singlePathSpec{
+		Values: generateSeriesList(ctx, start, inputs, step),
+	}, "foo", ">", 3)
+	require.Error(t, err)
+}
+
+func TestIntegralByInterval(t *testing.T) {
+	ctx := common.NewTestContext()
+	defer ctx.Close()
+
+	var (
+		start  = ctx.StartTime
+		step   = 60000
+		series = ts.NewSeries(ctx, "foo", start,
+			common.NewTestSeriesValues(ctx, step, []float64{1, 2, math.NaN(), 4, 5, 6}))
+	)
+
+	results, err := integralByInterval(ctx, singlePathSpec{
+		Values: []*ts.Series{series},
+	}, "2min")
+	require.NoError(t, err)
+
+	expected := common.TestSeries{
+		Name: "integralByInterval(foo,\"2min\")",
+		Data: []float64{1, 3, 0, 4, 5, 11},
+	}
+	common.CompareOutputsAndExpected(t, step, start,
+		[]common.TestSeries{expected}, results.Values)
+
+	_, err = integralByInterval(ctx, singlePathSpec{
+		Values: []*ts.Series{series},
+	}, "0min")
+	require.Error(t, err)
+}
+
+func TestInterpolate(t *testing.T) {
+	ctx := common.NewTestContext()
+	defer ctx.Close()
+
+	var (
+		nan    = math.NaN()
+		start  = ctx.StartTime
+		step   = 10000
+		values = []float64{nan, 1, nan, nan, 4, nan, 6, nan}
+	)
+
+	tests := []struct {
+		limit    float64
+		expected []float64
+	}{
+		{math.Inf(1), []float64{nan, 1, 2, 3, 4, 5, 6, nan}},
+		{1, []float64{nan, 1, nan, nan, 4, 5, 6, nan}},
+		{0, values},
+	}
+
+	for _, test := range tests {
+		series := ts.NewSeries(ctx, "foo", start, common.NewTestSeriesValues(ctx, step, values))
+		results, err := interpolate(ctx, singlePathSpec{
+			Values: []*ts.Series{series},
+		}, test.limit)
+		require.NoError(t, err)
+
+		expected := common.TestSeries{Name: "interpolate(foo)", Data: test.expected}
+		common.CompareOutputsAndExpected(t, step, start,
+			[]common.TestSeries{expected}, results.Values)
+	}
+}
+
+func TestLinearRegression(t *testing.T) {
+	var (
+		start       = time.Unix(1500000000, 0).UTC()
+		end         = start.Add(5 * time.Minute)
+		sourceStart = start.Add(-5 * time.Minute)
+		step        = 60000
+	)
+
+	ctx := common.NewContext(common.ContextOptions{
+		Start: start,
+		End:   end,
+		Engine: mockEngine{fn: func(
+			ctx xctx.Context,
+			query string,
+			opts storage.FetchOptions,
+		) (*storage.FetchResult, error) {
+			values := []float64{1, math.NaN(), 3, 4, 5}
+			if opts.StartTime.Equal(sourceStart) {
+				values = []float64{-4, -3, -2, -1, 0}
+			}
+			return storage.NewFetchResult(ctx, []*ts.Series{
+				ts.NewSeries(ctx, "foo", opts.StartTime, common.NewTestSeriesValues(ctx, step, values)),
+			}, block.NewResultMetadata()), nil
+		}},
+	})
+	defer ctx.Close()
+
+	tests := []struct {
+		target   string
+		name     string
+		expected []float64
+	}{
+		{
+			"linearRegression(foo)",
+			fmt.Sprintf("linearRegression(foo, %d, %d)", start.Unix(), end.Unix()),
+			[]float64{1, 2, 3, 4, 5},
+		},
+		{
+			fmt.Sprintf("linearRegression(foo, '%d', '%d')", sourceStart.Unix(), start.Unix()),
+			fmt.Sprintf("linearRegression(foo, %d, %d)", sourceStart.Unix(), start.Unix()),
+			[]float64{1, 2, 3, 4, 5},
+		},
+		{
+			"linearRegression(foo, '-10min', '-5min')",
+			fmt.Sprintf("linearRegression(foo, %d, %d)", sourceStart.Unix(), start.Unix()),
+			[]float64{1, 2, 3, 4, 5},
+		},
+	}
+
+	for _, test := range tests {
+		expr, err := compile(test.target)
+		require.NoError(t, err)
+		res, err := expr.Execute(ctx)
+		require.NoError(t, err)
+
+		expected := common.TestSeries{Name: test.name, Data: test.expected}
+		common.CompareOutputsAndExpected(t, step, start,
+			[]common.TestSeries{expected}, res.Values)
+	}
+}
+
+func TestMinMax(t *testing.T) {
+	ctx := common.NewTestContext()
+	defer ctx.Close()
+
+	nan := math.NaN()
+	start := ctx.StartTime
+	step := 10000
+	tests := []struct {
+		values   []float64
+		expected []float64
+	}{
+		{[]float64{1, nan, 3, 5}, []float64{0, nan, 0.5, 1}},
+		{[]float64{2, 2, nan}, []float64{0, 0, nan}},
+		{[]float64{nan, nan}, []float64{nan, nan}},
+	}
+
+	for _, test := range tests {
+		series := ts.NewSeries(ctx, "foo", start, common.NewTestSeriesValues(ctx, step, test.values))
+		results, err := minMax(ctx, singlePathSpec{
+			Values: []*ts.Series{series},
+		})
+		require.NoError(t, err)
+
+		expected := common.TestSeries{Name: "minMax(foo)", Data: test.expected}
+		common.CompareOutputsAndExpected(t, step, start,
+			[]common.TestSeries{expected}, results.Values)
+	}
+}
+
+func TestPow(t *testing.T) {
+	ctx := common.NewTestContext()
+	defer ctx.Close()
+
+	start := ctx.StartTime
+	step := 10000
+	series := ts.NewSeries(ctx, "foo", start,
+		common.NewTestSeriesValues(ctx, step, []float64{1, 2, math.NaN(), 3}))
+
+	results, err := pow(ctx, singlePathSpec{
+		Values: []*ts.Series{series},
+	}, 2)
+	require.NoError(t, err)
+
+	expected := common.TestSeries{
+		Name: "pow(foo,2.000)",
+		Data: []float64{1, 4, math.NaN(), 9},
+	}
+	common.CompareOutputsAndExpected(t, step, start,
+		[]common.TestSeries{expected}, results.Values)
+}
+
+func TestDelay(t *testing.T) {
+	ctx := common.NewTestContext()
+	defer ctx.Close()
+
+	nan := math.NaN()
+	start := ctx.StartTime
+	step := 10000
+	tests := []struct {
+		steps    int
+		expected []float64
+	}{
+		{0, []float64{1, 2, 3, 4}},
+		{2, []float64{nan, nan, 1, 2}},
+		{-1, []float64{2, 3, 4, nan}},
+		{5, []float64{nan, nan, nan, nan}},
+	}
+
+	for _, test := range tests {
+		series := ts.NewSeries(ctx, "foo", start,
+			common.NewTestSeriesValues(ctx, step, []float64{1, 2, 3, 4}))
+		results, err := delay(ctx, singlePathSpec{
+			Values: []*ts.Series{series},
+		}, test.steps)
+		require.NoError(t, err)
+
+		expected := common.TestSeries{
+			Name: fmt.Sprintf("delay(foo,%d)", test.steps),
+			Data: test.expected,
+		}
+		common.CompareOutputsAndExpected(t, step, start,
+			[]common.TestSeries{expected}, results.Values)
+	}
+}
+
+func TestTimeStack(t *testing.T) {
+	var (
+		start = time.Unix(1500000000, 0).UTC()
+		end   = start.Add(2 * time.Minute)
+		step  = 60000
+	)
+
+	ctx := common.NewContext(common.ContextOptions{
+		Start: start,
+		End:   end,
+		Engine: mockEngine{fn: func(
+			ctx xctx.Context,
+			query string,
+			opts storage.FetchOptions,
+		) (*storage.FetchResult, error) {
+			// NB: values are the number of minutes the range was shifted by.
+			shift := float64(start.Sub(opts.StartTime) / time.Minute)
+			return storage.NewFetchResult(ctx, []*ts.Series{
+				ts.NewSeries(ctx, query, opts.StartTime,
+					common.NewTestSeriesValues(ctx, step, []float64{shift, shift})),
+			}, block.NewResultMetadata()), nil
+		}},
+	})
+	defer ctx.Close()
+
+	expr, err := compile("timeStack(foo.bar, '1min', 0, 3)")
+	require.NoError(t, err)
+	res, err := expr.Execute(ctx)
+	require.NoError(t, err)
+
+	expected := []common.TestSeries{
+		{"timeShift(foo.bar, -1min, 0)", []float64{0, 0}},
+		{"timeShift(foo.bar, -1min, 1)", []float64{1, 1}},
+		{"timeShift(foo.bar, -1min, 2)", []float64{2, 2}},
+	}
+	common.CompareOutputsAndExpected(t, step, start, expected, res.Values)
+}
+
+func TestUseSeriesAbove(t *testing.T) {
+	ctx := common.NewTestContext()
+	defer ctx.Close()
+
+	step := 60000
+	ctx.Engine = mockEngine{fn: func(
+		ctx xctx.Context,
+		query string,
+		opts storage.FetchOptions,
+	) (*storage.FetchResult, error) {
+		var series []*ts.Series
+		switch query {
+		case "foo.*.reqs":
+			series = []*ts.Series{
+				ts.NewSeries(ctx, "foo.a.reqs", opts.StartTime,
+					common.NewTestSeriesValues(ctx, step, []float64{1, 5})),
+				ts.NewSeries(ctx, "foo.b.reqs", opts.StartTime,
+					common.NewTestSeriesValues(ctx, step, []float64{20, 1})),
+			}
+		case "foo.b.time":
+			series = []*ts.Series{
+				ts.NewSeries(ctx, "foo.b.time", opts.StartTime,
+					common.NewTestSeriesValues(ctx, step, []float64{7, 8})),
+			}
+		default:
+			return nil, fmt.Errorf("unexpected query: %s", query)
+		}
+		return storage.NewFetchResult(ctx, series, block.NewResultMetadata()), nil
+	}}
+
+	expr, err := compile("useSeriesAbove(foo.*.reqs, 10, 'reqs', 'time')")
+	require.NoError(t, err)
+	res, err := expr.Execute(ctx)
+	require.NoError(t, err)
+
+	expected := []common.TestSeries{{"foo.b.time", []float64{7, 8}}}
+	common.CompareOutputsAndExpected(t, step, ctx.StartTime, expected, res.Values)
+}
+
+func TestApplyByNode(t *testing.T) {
+	ctx := common.NewTestContext()
+	defer ctx.Close()
+
+	step := 60000
+	newSeries := func(ctx xctx.Context, name string, start time.Time, v float64) *ts.Series {
+		return ts.NewSeries(ctx, name, start, common.NewTestSeriesValues(ctx, step, []float64{v, v}))
+	}
+	ctx.Engine = mockEngine{fn: func(
+		ctx xctx.Context,
+		query string,
+		opts storage.FetchOptions,
+	) (*storage.FetchResult, error) {
+		var series []*ts.Series
+		switch query {
+		case "servers.*.disk.bytes_free":
+			series = []*ts.Series{
+				newSeries(ctx, "servers.s2.disk.bytes_free", opts.StartTime, 30),
+				newSeries(ctx, "servers.s1.disk.bytes_free", opts.StartTime, 10),
+			}
+		case "servers.s1.disk.bytes_free":
+			series = []*ts.Series{newSeries(ctx, query, opts.StartTime, 10)}
+		case "servers.s2.disk.bytes_free":
+			series = []*ts.Series{newSeries(ctx, query, opts.StartTime, 30)}
+		case "servers.s1.disk.bytes_*":
+			series = []*ts.Series{
+				newSeries(ctx, "servers.s1.disk.bytes_free", opts.StartTime, 10),
+				newSeries(ctx, "servers.s1.disk.bytes_used", opts.StartTime, 30),
+			}
+		case "servers.s2.disk.bytes_*":
+			series = []*ts.Series{
+				newSeries(ctx, "servers.s2.disk.bytes_free", opts.StartTime, 30),
+				newSeries(ctx, "servers.s2.disk.bytes_used", opts.StartTime, 10),
+			}
+		default:
+			return nil, fmt.Errorf("unexpected query: %s", query)
+		}
+		return storage.NewFetchResult(ctx, series, block.NewResultMetadata()), nil
+	}}
+
+	expr, err := compile("applyByNode(servers.*.disk.bytes_free, 1, " +
+		"'divideSeries(%.disk.bytes_free,sumSeries(%.disk.bytes_*))', '%.disk.pct_free')")
+	require.NoError(t, err)
+	res, err := expr.Execute(ctx)
+	require.NoError(t, err)
+
+	expected := []common.TestSeries{
+		{"servers.s1.disk.pct_free", []float64{0.25, 0.25}},
+		{"servers.s2.disk.pct_free", []float64{0.75, 0.75}},
+	}
+	common.CompareOutputsAndExpected(t, step, ctx.StartTime, expected, res.Values)
+}
+
+func TestVerticalLine(t *testing.T) {
+	var (
+		start = time.Unix(1500000000, 0).UTC()
+		end   = start.Add(time.Hour)
+		ctx   = common.NewContext(common.ContextOptions{Start: start, End: end})
+		at    = start.Add(time.Minute)
+	)
+	defer ctx.Close()
+
+	timestamp := fmt.Sprintf("%d", at.Unix())
+	r, err := verticalLine(ctx, timestamp, "deploy", "")
+	require.NoError(t, err)
+	expected := []common.TestSeries{{"deploy", []float64{1, 1}}}
+	common.CompareOutputsAndExpected(t, 1000, at, expected, r.Values)
+
+	r, err = verticalLine(ctx, timestamp, "", "")
+	require.NoError(t, err)
+	require.Equal(t, 1, r.Len())
+	assert.Equal(t, timestamp, r.Values[0].Name())
+
+	// Relative timestamps are relative to the end of the range.
+	r, err = verticalLine(ctx, "-59min", "deploy", "")
+	require.NoError(t, err)
+	common.CompareOutputsAndExpected(t, 1000, at, expected, r.Values)
+
+	_, err = verticalLine(ctx, fmt.Sprintf("%d", start.Add(-time.Minute).Unix()), "", "")
+	require.Error(t, err)
+
+	_, err = verticalLine(ctx, fmt.Sprintf("%d", end.Add(time.Minute).Unix()), "", "")
+	require.Error(t, err)
+}
+
 func TestFunctionsRegistered(t *testing.T) {
 	fnames := []string{
 		"abs",
 		"absolute",
+		"aggregate",
 		"aggregateLine",
+		"aggregateWithWildcards",
 		"alias",
 		"aliasByMetric",
 		"aliasByNode",
+		"aliasByTags",
 		"aliasSub",
+		"applyByNode",
 		"asPercent",
 		"averageAbove",
 		"averageSeries",
@@ -2797,13 +3294,18 @@ func TestFunctionsRegistered(t *testing.T) {
 		"currentAbove",
 		"currentBelow",
 		"dashed",
+		"delay",
 		"derivative",
 		"diffSeries",
 		"divideSeries",
 		"exclude",
+		"exponentialMovingAverage",
 		"fallbackSeries",
+		"filterSeries",
 		"group",
 		"groupByNode",
+		"groupByTags",
+		"highest",
 		"highestAverage",
 		"highestCurrent",
 		"highestMax",
@@ -2813,29 +3315,38 @@ func TestFunctionsRegistered(t *testing.T) {
 		"holtWintersForecast",
 		"identity",
 		"integral",
+		"integralByInterval",
+		"interpolate",
 		"isNonNull",
 		"keepLastValue",
 		"legendValue",
 		"limit",
+		"linearRegression",
 		"log",
 		"logarithm",
+		"lowest",
 		"lowestAverage",
 		"lowestCurrent",
 		"max",
 		"maxSeries",
 		"maximumAbove",
 		"min",
+		"minMax",
 		"minSeries",
 		"minimumAbove",
 		"mostDeviant",
 		"movingAverage",
+		"movingMax",
 		"movingMedian",
+		"movingMin",
+		"movingSum",
 		"multiplySeries",
 		"nonNegativeDerivative",
 		"nPercentile",
 		"offset",
 		"offsetToZero",
 		"perSecond",
+		"pow",
 		"randomWalk",
 		"randomWalkFunction",
 		"rangeOfSeries",
@@ -2846,6 +3357,8 @@ func TestFunctionsRegistered(t *testing.T) {
 		"removeEmptySeries",
 		"scale",
 		"scaleToSeconds",
+		"seriesByTag",
+		"smartSummarize",
 		"sortByMaxima",
 		"sortByName",
 		"sortByTotal",
@@ -2855,11 +3368,15 @@ func TestFunctionsRegistered(t *testing.T) {
 		"sum",
 		"sumSeries",
 		"summarize",
+		"sumSeriesLists",
 		"threshold",
 		"time",
 		"timeFunction",
 		"timeShift",
+		"timeStack",
 		"transformNull",
+		"useSeriesAbove",
+		"verticalLine",
 		"weightedAverage",
 	}
 
@@ -23,6 +23,7 @@ package native
 import (
 	"fmt"
 	"math"
+	"strings"
 	"time"
 
 	"github.com/m3db/m3/src/query/graphite/common"
@@ -41,17 +42,8 @@ func summarize(
 		fname = "sum"
 	}
 
-	interval, err := common.ParseInterval(intervalS)
-	if err != nil || interval <= 0 {
-		err := errors.NewInvalidParamsError(fmt.Errorf(
-			"invalid interval %s: %v", interval, err))
-		return ts.NewSeriesList(), err
-	}
-
-	f, fexists := summarizeFuncs[fname]
-	if !fexists {
-		err := errors.NewInvalidParamsError(fmt.Errorf(
-			"invalid func %s", fname))
+	interval, f, err := parseSummarizeParams(intervalS, fname)
+	if err != nil {
 		return ts.NewSeriesList(), err
 	}
 
@@ -71,6 +63,105 @@ func summarize(
 	return r, nil
 }
 
+// smartSummarize summarizes each series into interval buckets of a certain size,
+// with buckets aligned to the start of the requested time range rather than to
+// the epoch. If alignTo is given, such as "1d" or "hours", the start of the time
+// range is first truncated to that unit and the series are fetched from there.
+func smartSummarize(
+	ctx *common.Context,
+	_ singlePathSpec,
+	intervalS, fname, alignTo string,
+) (*unaryContextShifter, error) {
+	if fname == "" {
+		fname = "sum"
+	}
+
+	interval, f, err := parseSummarizeParams(intervalS, fname)
+	if err != nil {
+		return nil, err
+	}
+
+	var shift time.Duration
+	if alignTo != "" {
+		alignedStart, err := truncateToUnit(ctx.StartTime, alignTo)
+		if err != nil {
+			return nil, err
+		}
+		shift = alignedStart.Sub(ctx.StartTime)
+	}
+
+	contextShiftingFn := func(c *common.Context) *common.Context {
+		opts := common.NewChildContextOptions()
+		opts.AdjustTimeRange(shift, 0, 0, 0)
+		childCtx := c.NewChildContext(opts)
+		return childCtx
+	}
+
+	transformerFn := func(input ts.SeriesList) (ts.SeriesList, error) {
+		results := make([]*ts.Series, len(input.Values))
+		for i, series := range input.Values {
+			name := fmt.Sprintf("smartSummarize(%s, \"%s\", \"%s\")", series.Name(), intervalS, fname)
+			results[i] = summarizeTimeSeries(ctx, name, series, interval, f.consolidationFunc, true)
+		}
+
+		input.Values = results
+		return input, nil
+	}
+
+	return &unaryContextShifter{
+		ContextShiftFunc: contextShiftingFn,
+		UnaryTransformer: transformerFn,
+	}, nil
+}
+
+// parseSummarizeParams parses the interval and looks up the summarize function
+// shared by the summarize functions.
+func parseSummarizeParams(intervalS, fname string) (time.Duration, funcInfo, error) {
+	interval, err := common.ParseInterval(intervalS)
+	if err != nil || interval <= 0 {
+		err := errors.NewInvalidParamsError(fmt.Errorf(
+			"invalid interval %s: %v", interval, err))
+		return 0, funcInfo{}, err
+	}
+
+	f, fexists := summarizeFuncs[fname]
+	if !fexists {
+		err := errors.NewInvalidParamsError(fmt.Errorf(
+			"invalid func %s", fname))
+		return 0, funcInfo{}, err
+	}
+
+	return interval, f, nil
+}
+
+// truncateToUnit truncates the time to the start of the unit given as an
+// interval such as "1d" or "hours"; any count of units is ignored.
+func truncateToUnit(t time.Time, unit string) (time.Time, error) {
+	trimmed := strings.TrimLeft(unit, "+-0123456789")
+	switch {
+	case strings.HasPrefix(trimmed, "s"):
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, t.Location()), nil
+	case strings.HasPrefix(trimmed, "min"):
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, t.Location()), nil
+	case strings.HasPrefix(trimmed, "h"):
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, t.Location()), nil
+	case strings.HasPrefix(trimmed, "d"):
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location()), nil
+	case strings.HasPrefix(trimmed, "w"):
+		// Weeks start on Monday.
+		daysSinceMonday := (int(t.Weekday()) + 6) % 7
+		return time.Date(t.Year(), t.Month(), t.Day()-daysSinceMonday, 0, 0, 0, 0, t.Location()), nil
+	case strings.HasPrefix(trimmed, "mon"):
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location()), nil
+	case strings.HasPrefix(trimmed, "m"):
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, t.Location()), nil
+	case strings.HasPrefix(trimmed, "y"):
+		return time.Date(t.Year(), time.January, 1, 0, 0, 0, 0, t.Location()), nil
+	}
+
+	return t, errors.NewInvalidParamsError(fmt.Errorf("invalid alignTo unit %s", unit))
+}
+
 type summarizeBucket struct {
 	count int
 	accum float64
@@ -132,3 +132,106 @@ func TestSummarizeInvalidInterval(t *testing.T) {
 	}, "-1hour", "avg", false)
 	require.Error(t, err)
 }
+
+func TestSmartSummarize(t *testing.T) {
+	var (
+		start  = time.Unix(131, 0)
+		end    = time.Unix(251, 0)
+		ctx    = common.NewContext(common.ContextOptions{Start: start, End: end})
+		vals   = ts.NewValues(ctx, 10000, 12)
+		series = []*ts.Series{ts.NewSeries(ctx, "foo", start, vals)}
+	)
+
+	defer ctx.Close()
+
+	for i := 0; i < vals.Len(); i++ {
+		vals.SetValueAt(i, float64(i))
+	}
+
+	tests := []struct {
+		name          string
+		interval      string
+		fname         string
+		expectedStart time.Time
+		expectedVals  []float64
+	}{
+		{"smartSummarize(foo, \"30s\", \"sum\")", "30s", "",
+			start, []float64{3, 12, 21, 30},
+		},
+		{"smartSummarize(foo, \"1min\", \"sum\")", "1min", "sum",
+			start, []float64{15, 51},
+		},
+		{"smartSummarize(foo, \"1min\", \"avg\")", "1min", "avg",
+			start, []float64{2.5, 8.5},
+		},
+	}
+
+	for _, test := range tests {
+		shifter, err := smartSummarize(ctx, singlePathSpec{}, test.interval, test.fname, "")
+		require.NoError(t, err)
+
+		outSeries, err := shifter.UnaryTransformer(ts.SeriesList{Values: series})
+		require.NoError(t, err)
+		require.Equal(t, 1, len(outSeries.Values))
+
+		out := outSeries.Values[0]
+		assert.Equal(t, test.name, out.Name(), "incorrect name for %s", test.name)
+		assert.Equal(t, test.expectedStart, out.StartTime(), "incorrect start for %s", test.name)
+		require.Equal(t, len(test.expectedVals), out.Len(), "incorrect len for %s", test.name)
+
+		for i := 0; i < out.Len(); i++ {
+			assert.Equal(t, test.expectedVals[i], out.ValueAt(i), "incorrect val %d for %s", i, test.name)
+		}
+	}
+
+	shifter, err := smartSummarize(ctx, singlePathSpec{}, "1min", "sum", "1min")
+	require.NoError(t, err)
+	shifted := shifter.ContextShiftFunc(ctx)
+	assert.Equal(t, time.Unix(120, 0), shifted.StartTime)
+	assert.Equal(t, end, shifted.EndTime)
+
+	shifter, err = smartSummarize(ctx, singlePathSpec{}, "5m", "sum", "5m")
+	require.NoError(t, err)
+	shifted = shifter.ContextShiftFunc(ctx)
+	assert.Equal(t, time.Unix(120, 0), shifted.StartTime)
+
+	shifter, err = smartSummarize(ctx, singlePathSpec{}, "1y", "sum", "1y")
+	require.NoError(t, err)
+	shifted = shifter.ContextShiftFunc(ctx)
+	assert.Equal(t, time.Date(ctx.StartTime.Year(), time.January, 1, 0, 0, 0, 0,
+		ctx.StartTime.Location()), shifted.StartTime)
+
+	_, err = smartSummarize(ctx, singlePathSpec{}, "0min", "sum", "")
+	require.Error(t, err)
+
+	_, err = smartSummarize(ctx, singlePathSpec{}, "1min", "sum", "1fortnight")
+	require.Error(t, err)
+}
+
+func TestTruncateToUnit(t *testing.T) {
+	// NB: a Wednesday.
+	now := time.Date(2019, time.March, 13, 14, 35, 27, 500, time.UTC)
+
+	tests := []struct {
+		unit     string
+		expected time.Time
+	}{
+		{"10s", time.Date(2019, time.March, 13, 14, 35, 27, 0, time.UTC)},
+		{"1min", time.Date(2019, time.March, 13, 14, 35, 0, 0, time.UTC)},
+		{"5m", time.Date(2019, time.March, 13, 14, 35, 0, 0, time.UTC)},
+		{"1h", time.Date(2019, time.March, 13, 14, 0, 0, 0, time.UTC)},
+		{"1d", time.Date(2019, time.March, 13, 0, 0, 0, 0, time.UTC)},
+		{"1w", time.Date(2019, time.March, 11, 0, 0, 0, 0, time.UTC)},
+		{"1mon", time.Date(2019, time.March, 1, 0, 0, 0, 0, time.UTC)},
+		{"1y", time.Date(2019, time.January, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, test := range tests {
+		truncated, err := truncateToUnit(now, test.unit)
+		require.NoError(t, err)
+		assert.Equal(t, test.expected, truncated, "incorrect truncation for %s", test.unit)
+	}
+
+	_, err := truncateToUnit(now, "1fortnight")
+	require.Error(t, err)
+}
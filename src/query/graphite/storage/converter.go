@@ -21,12 +21,30 @@
 package storage
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
 	"github.com/m3db/m3/src/query/graphite/graphite"
 	"github.com/m3db/m3/src/query/models"
 )
 
+var (
+	// metricNameTag is the tag graphite tagged series store their path under.
+	metricNameTag = models.NewTagOptions().MetricName()
+
+	errNoPositiveTagExpression = errors.New("at least one tag expression must " +
+		"match a non-empty value")
+)
+
 const (
 	wildcard = "*"
+
+	// simpleGlobRunes are the wildcards of globs which are converted to
+	// regexps the index can serve with prefix and wildcard queries.
+	simpleGlobRunes = "*?"
 )
 
 func convertMetricPartToMatcher(
@@ -41,6 +59,14 @@ func convertMetricPartToMatcher(
 		}, nil
 	}
 
+	if value, ok := simpleGlobToRegexPattern(metric); ok {
+		return models.Matcher{
+			Type:  models.MatchRegexp,
+			Name:  graphite.TagName(count),
+			Value: value,
+		}, nil
+	}
+
 	value, isRegex, err := graphite.GlobToRegexPattern(metric)
 	if err != nil {
 		return models.Matcher{}, err
@@ -59,9 +85,132 @@ func convertMetricPartToMatcher(
 	}, nil
 }
 
+// simpleGlobToRegexPattern converts a glob which only uses the '*' and '?'
+// wildcards into a regexp of literals, '.*' and '.', which the index query
+// planner serves with prefix and wildcard queries rather than a regexp. Each
+// matcher matches a single path node, which never contains the hierarchy
+// separator, so these are equivalent to the separator excluding patterns.
+func simpleGlobToRegexPattern(glob string) ([]byte, bool) {
+	if !strings.ContainsAny(glob, simpleGlobRunes) {
+		return nil, false
+	}
+
+	var buff bytes.Buffer
+	for _, r := range glob {
+		switch r {
+		case '*':
+			buff.WriteString(".*")
+		case '?':
+			buff.WriteString(".")
+		case '|':
+			// NB: the glob converter treats '|' as a regexp alternation.
+			return nil, false
+		default:
+			if !strings.ContainsRune(graphite.ValidIdentifierRunes, r) {
+				return nil, false
+			}
+			buff.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+
+	return buff.Bytes(), true
+}
+
 func matcherTerminator(count int) models.Matcher {
 	return models.Matcher{
 		Type: models.MatchNotField,
 		Name: graphite.TagName(count),
 	}
 }
+
+// convertTagExpressionToMatcher converts a graphite tag expression, as used by
+// seriesByTag, to a matcher. Supported expressions are:
+//
+//	tag=spec    tag value exactly matches spec
+//	tag!=spec   tag value does not exactly match spec
+//	tag=~spec   tag value matches the regular expression spec
+//	tag!=~spec  tag value does not match the regular expression spec
+//
+// As in graphite, regular expressions are anchored only at the start, and an
+// empty spec with = or != matches series without or with the tag respectively.
+// The returned bool indicates whether the matcher requires a non-empty value.
+func convertTagExpressionToMatcher(expr string) (models.Matcher, bool, error) {
+	idx := strings.IndexAny(expr, "!=")
+	if idx <= 0 {
+		return models.Matcher{}, false,
+			fmt.Errorf("invalid tag expression: %s", expr)
+	}
+
+	var (
+		tag      = expr[:idx]
+		name     = []byte(tag)
+		op       = expr[idx:]
+		value    string
+		negate   bool
+		isRegexp bool
+	)
+	if strings.ContainsAny(tag, ";^") {
+		return models.Matcher{}, false,
+			fmt.Errorf("invalid tag in expression: %s", expr)
+	}
+
+	if tag == graphite.TaggedNameTag {
+		name = metricNameTag
+	}
+
+	switch {
+	case strings.HasPrefix(op, "!=~"):
+		value, negate, isRegexp = op[3:], true, true
+	case strings.HasPrefix(op, "!="):
+		value, negate = op[2:], true
+	case strings.HasPrefix(op, "=~"):
+		value, isRegexp = op[2:], true
+	case strings.HasPrefix(op, "="):
+		value = op[1:]
+	default:
+		return models.Matcher{}, false,
+			fmt.Errorf("invalid operator in tag expression: %s", expr)
+	}
+
+	if isRegexp {
+		// NB: graphite regexps are only anchored at the start while matchers
+		// are fully anchored, so allow any suffix.
+		matchType := models.MatchRegexp
+		if negate {
+			matchType = models.MatchNotRegexp
+		}
+
+		pattern := "(?:" + value + ").*"
+		re, err := regexp.Compile("^" + pattern + "$")
+		if err != nil {
+			return models.Matcher{}, false, err
+		}
+
+		m, err := models.NewMatcher(matchType, name, []byte(pattern))
+		if err != nil {
+			return models.Matcher{}, false, err
+		}
+
+		return m, !negate && !re.MatchString(""), nil
+	}
+
+	if len(value) == 0 {
+		matchType := models.MatchNotField
+		if negate {
+			matchType = models.MatchField
+		}
+
+		return models.Matcher{Type: matchType, Name: name}, negate, nil
+	}
+
+	matchType := models.MatchEqual
+	if negate {
+		matchType = models.MatchNotEqual
+	}
+
+	return models.Matcher{
+		Type:  matchType,
+		Name:  name,
+		Value: []byte(value),
+	}, !negate, nil
+}
@@ -45,6 +45,29 @@ func TestConvertMetricPartToMatcher(t *testing.T) {
 	}
 }
 
+func TestConvertSimpleGlobToMatcher(t *testing.T) {
+	tests := []struct {
+		glob     string
+		expected string
+	}{
+		{glob: "foo*", expected: "foo.*"},
+		{glob: "f?o*bar", expected: "f.o.*bar"},
+		{glob: "*$bar", expected: `.*\$bar`},
+	}
+
+	for _, test := range tests {
+		expected := models.Matcher{
+			Type:  models.MatchRegexp,
+			Name:  graphite.TagName(0),
+			Value: []byte(test.expected),
+		}
+
+		actual, err := convertMetricPartToMatcher(0, test.glob)
+		require.NoError(t, err)
+		assert.Equal(t, expected, actual)
+	}
+}
+
 func TestConvertWildcardToMatcher(t *testing.T) {
 	metric := "*"
 	for i := 0; i < 100; i++ {
@@ -21,15 +21,17 @@
 package storage
 
 import (
+	"bytes"
 	"context"
-	"errors"
 	"fmt"
 	"math"
+	"strings"
 	"time"
 
 	"github.com/m3db/m3/src/query/block"
 	"github.com/m3db/m3/src/query/cost"
 	xctx "github.com/m3db/m3/src/query/graphite/context"
+	"github.com/m3db/m3/src/query/graphite/errors"
 	"github.com/m3db/m3/src/query/graphite/graphite"
 	"github.com/m3db/m3/src/query/graphite/ts"
 	"github.com/m3db/m3/src/query/models"
@@ -97,6 +99,44 @@ func TranslateQueryToMatchersWithTerminator(
 	return matchers, nil
 }
 
+// TranslateTagExpressionsToMatchers converts graphite tag expressions, as used
+// by seriesByTag, to tag matchers. At least one expression must match only
+// non-empty values, and series are restricted to graphite tagged series.
+func TranslateTagExpressionsToMatchers(
+	exprs []string,
+) (models.Matchers, error) {
+	var (
+		matchers    = make(models.Matchers, 0, len(exprs)+1)
+		hasPositive bool
+		hasName     bool
+	)
+	for _, expr := range exprs {
+		m, positive, err := convertTagExpressionToMatcher(expr)
+		if err != nil {
+			return nil, err
+		}
+
+		hasPositive = hasPositive || positive
+		hasName = hasName || bytes.Equal(m.Name, metricNameTag)
+		matchers = append(matchers, m)
+	}
+
+	if !hasPositive {
+		return nil, errNoPositiveTagExpression
+	}
+
+	if !hasName {
+		// NB: ensure only tagged series, which carry their path under the
+		// metric name tag, are matched.
+		matchers = append(matchers, models.Matcher{
+			Type: models.MatchField,
+			Name: metricNameTag,
+		})
+	}
+
+	return matchers, nil
+}
+
 // GetQueryTerminatorTagName will return the name for the terminator matcher in
 // the given pattern. This is useful for filtering out any additional results.
 func GetQueryTerminatorTagName(query string) []byte {
@@ -110,15 +150,36 @@ func translateQuery(query string, opts FetchOptions) (*storage.FetchQuery, error
 		return nil, err
 	}
 
+	return newFetchQuery(query, matchers, opts), nil
+}
+
+func translateTagQuery(
+	exprs []string,
+	opts FetchOptions,
+) (*storage.FetchQuery, error) {
+	matchers, err := TranslateTagExpressionsToMatchers(exprs)
+	if err != nil {
+		return nil, err
+	}
+
+	raw := fmt.Sprintf("seriesByTag('%s')", strings.Join(exprs, "','"))
+	return newFetchQuery(raw, matchers, opts), nil
+}
+
+func newFetchQuery(
+	raw string,
+	matchers models.Matchers,
+	opts FetchOptions,
+) *storage.FetchQuery {
 	return &storage.FetchQuery{
-		Raw:         query,
+		Raw:         raw,
 		TagMatchers: matchers,
 		Start:       opts.StartTime,
 		End:         opts.EndTime,
 		// NB: interval is not used for initial consolidation step from the storage
 		// so it's fine to use default here.
 		Interval: time.Duration(0),
-	}, nil
+	}
 }
 
 func truncateBoundsToResolution(
@@ -220,6 +281,25 @@ func (s *m3WrappedStore) FetchByQuery(
 		}, nil
 	}
 
+	return s.fetch(ctx, m3query, opts)
+}
+
+func (s *m3WrappedStore) FetchByTags(
+	ctx xctx.Context, tagExpressions []string, opts FetchOptions,
+) (*FetchResult, error) {
+	m3query, err := translateTagQuery(tagExpressions, opts)
+	if err != nil {
+		// NB: unlike path queries, tag expressions are explicitly given by the
+		// user so an invalid expression is surfaced as an error.
+		return nil, errors.NewInvalidParamsError(err)
+	}
+
+	return s.fetch(ctx, m3query, opts)
+}
+
+func (s *m3WrappedStore) fetch(
+	ctx xctx.Context, m3query *storage.FetchQuery, opts FetchOptions,
+) (*FetchResult, error) {
 	m3ctx, cancel := context.WithTimeout(ctx.RequestContext(), opts.Timeout)
 	defer cancel()
 	fetchOptions := storage.NewFetchOptions()
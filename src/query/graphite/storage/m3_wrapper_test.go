@@ -64,12 +64,12 @@ func TestTranslateQuery(t *testing.T) {
 	expected := models.Matchers{
 		{Type: models.MatchEqual, Name: graphite.TagName(0), Value: []byte("foo")},
 		{Type: models.MatchRegexp, Name: graphite.TagName(1), Value: []byte("ba[rz]")},
-		{Type: models.MatchRegexp, Name: graphite.TagName(2), Value: []byte(`q[^\.]*x`)},
+		{Type: models.MatchRegexp, Name: graphite.TagName(2), Value: []byte(`q.*x`)},
 		{Type: models.MatchEqual, Name: graphite.TagName(3), Value: []byte("terminator")},
 		{Type: models.MatchEqual, Name: graphite.TagName(4), Value: []byte("will")},
 		{Type: models.MatchEqual, Name: graphite.TagName(5), Value: []byte("be")},
 		{Type: models.MatchField, Name: graphite.TagName(6)},
-		{Type: models.MatchRegexp, Name: graphite.TagName(7), Value: []byte(`back[^\.]`)},
+		{Type: models.MatchRegexp, Name: graphite.TagName(7), Value: []byte(`back.`)},
 		{Type: models.MatchNotField, Name: graphite.TagName(8)},
 	}
 
@@ -246,3 +246,102 @@ func TestFetchByInvalidQuery(t *testing.T) {
 	assert.NoError(t, err)
 	require.Equal(t, 0, len(result.SeriesList))
 }
+
+func TestTranslateTagExpressionsToMatchers(t *testing.T) {
+	matchers, err := TranslateTagExpressionsToMatchers([]string{
+		"dc=east", "app!=web", "host=~db", "role!=~test|dev", "env=", "rack!=",
+	})
+	require.NoError(t, err)
+	expected := models.Matchers{
+		{Type: models.MatchEqual, Name: []byte("dc"), Value: []byte("east")},
+		{Type: models.MatchNotEqual, Name: []byte("app"), Value: []byte("web")},
+		{Type: models.MatchRegexp, Name: []byte("host"), Value: []byte("(?:db).*")},
+		{Type: models.MatchNotRegexp, Name: []byte("role"), Value: []byte("(?:test|dev).*")},
+		{Type: models.MatchNotField, Name: []byte("env")},
+		{Type: models.MatchField, Name: []byte("rack")},
+		{Type: models.MatchField, Name: []byte("__name__")},
+	}
+
+	require.Equal(t, len(expected), len(matchers))
+	for i, m := range matchers {
+		assert.Equal(t, expected[i].Type, m.Type)
+		assert.Equal(t, expected[i].Name, m.Name)
+		assert.Equal(t, expected[i].Value, m.Value)
+	}
+
+	matchers, err = TranslateTagExpressionsToMatchers([]string{"name=cpu.load"})
+	require.NoError(t, err)
+	require.Equal(t, models.Matchers{
+		{Type: models.MatchEqual, Name: []byte("__name__"), Value: []byte("cpu.load")},
+	}, matchers)
+}
+
+func TestTranslateTagExpressionsToMatchersInvalid(t *testing.T) {
+	for _, exprs := range [][]string{
+		{},
+		{"dc"},
+		{"=east"},
+		{"dc!=east"},
+		{"dc=~.*"},
+		{"dc=", "app!=~web"},
+		{"dc=~(east"},
+	} {
+		_, err := TranslateTagExpressionsToMatchers(exprs)
+		assert.Error(t, err, fmt.Sprint(exprs))
+	}
+}
+
+func TestFetchByTags(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	store := storage.NewMockStorage(ctrl)
+	resolution := 10 * time.Second
+	start := time.Now().Add(time.Hour * -1).Truncate(resolution).Add(time.Second)
+	steps := 3
+	res := buildResult(ctrl, resolution, 1, steps, start)
+	res.Metadata = block.ResultMetadata{
+		Exhaustive:  true,
+		LocalOnly:   true,
+		Resolutions: []int64{int64(resolution)},
+	}
+
+	store.EXPECT().FetchBlocks(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(
+			_ context.Context,
+			query *storage.FetchQuery,
+			_ *storage.FetchOptions,
+		) (block.Result, error) {
+			assert.Equal(t, "seriesByTag('name=cpu','dc=east')", query.Raw)
+			require.Equal(t, 2, len(query.TagMatchers))
+			assert.Equal(t, []byte("__name__"), query.TagMatchers[0].Name)
+			assert.Equal(t, []byte("dc"), query.TagMatchers[1].Name)
+			return res, nil
+		})
+
+	childEnforcer := cost.NewMockChainedEnforcer(ctrl)
+	childEnforcer.EXPECT().Close()
+
+	enforcer := cost.NewMockChainedEnforcer(ctrl)
+	enforcer.EXPECT().Child(cost.QueryLevel).Return(childEnforcer).MinTimes(1)
+
+	wrapper := NewM3WrappedStorage(store, enforcer, instrument.NewOptions())
+	ctx := xctx.New()
+	ctx.SetRequestContext(context.TODO())
+	end := start.Add(time.Duration(steps) * resolution)
+	opts := FetchOptions{
+		StartTime: start,
+		EndTime:   end,
+		DataOptions: DataOptions{
+			Timeout: time.Minute,
+		},
+	}
+
+	result, err := wrapper.FetchByTags(ctx, []string{"name=cpu", "dc=east"}, opts)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(result.SeriesList))
+	assert.Equal(t, "a0", result.SeriesList[0].Name())
+
+	_, err = wrapper.FetchByTags(ctx, []string{"dc!=east"}, opts)
+	require.Error(t, err)
+}
@@ -55,6 +55,12 @@ type Storage interface {
 	FetchByQuery(
 		ctx context.Context, query string, opts FetchOptions,
 	) (*FetchResult, error)
+
+	// FetchByTags fetches timeseries data for graphite tagged series matching
+	// all of the given tag expressions.
+	FetchByTags(
+		ctx context.Context, tagExpressions []string, opts FetchOptions,
+	) (*FetchResult, error)
 }
 
 // FetchResult provides a fetch result and meta information.
@@ -252,6 +252,10 @@ func (t Tags) prependMetaLen() (int, []int) {
 }
 
 func (t Tags) graphiteID() []byte {
+	if name, ok := t.Name(); ok {
+		return t.graphiteTaggedID(name)
+	}
+
 	// TODO: pool these bytes.
 	id := make([]byte, t.idLenGraphite())
 	idx := 0
@@ -275,6 +279,40 @@ func (t Tags) idLenGraphite() int {
 	return idLen
 }
 
+// graphiteTaggedID generates the ID for a Graphite tagged series, which
+// carries its path under the metric name tag. The ID takes the canonical
+// Graphite form of the path followed by the remaining tags sorted by name,
+// i.e. path;tag1=value1;tag2=value2.
+func (t Tags) graphiteTaggedID(name []byte) []byte {
+	metricName := t.Opts.MetricName()
+	tagged := Tags{Opts: t.Opts, Tags: make([]Tag, 0, t.Len()-1)}
+	idLen := len(name)
+	for _, tag := range t.Tags {
+		if bytes.Equal(tag.Name, metricName) {
+			continue
+		}
+
+		tagged.Tags = append(tagged.Tags, tag)
+		idLen += len(tag.Name) + len(tag.Value) + 2 // account for separators
+	}
+
+	sort.Sort(tagged)
+
+	// TODO: pool these bytes.
+	id := make([]byte, idLen)
+	idx := copy(id, name)
+	for _, tag := range tagged.Tags {
+		id[idx] = graphiteTag
+		idx++
+		idx += copy(id[idx:], tag.Name)
+		id[idx] = eq
+		idx++
+		idx += copy(id[idx:], tag.Value)
+	}
+
+	return id
+}
+
 func (t Tags) tagSubset(keys [][]byte, include bool) Tags {
 	tags := NewTags(t.Len(), t.Opts)
 	for _, tag := range t.Tags {
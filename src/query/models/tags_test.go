@@ -82,6 +82,21 @@ func TestLongTagNewIDOutOfOrderGraphite(t *testing.T) {
 	assert.Equal(t, []byte("v0.v1.v2.v3.v4.v5.v6.v7.v8.v9.v10.v11.v12"), actual)
 }
 
+func TestTaggedIDGraphite(t *testing.T) {
+	opts := NewTagOptions().SetIDSchemeType(TypeGraphite)
+	tags := NewTags(3, opts).AddTags([]Tag{
+		{Name: []byte("dc"), Value: []byte("east")},
+		{Name: []byte("__name__"), Value: []byte("foo.bar")},
+		{Name: []byte("app"), Value: []byte("web")},
+	})
+
+	actual := tags.ID()
+	assert.Equal(t, []byte("foo.bar;app=web;dc=east"), actual)
+
+	nameOnly := NewTags(1, opts).SetName([]byte("foo.bar"))
+	assert.Equal(t, []byte("foo.bar"), nameOnly.ID())
+}
+
 func TestHashedID(t *testing.T) {
 	tags := testLongTagIDOutOfOrder(t, TypeLegacy)
 	actual := tags.HashedID()
@@ -27,6 +27,7 @@ import (
 // Separators for tags.
 const (
 	graphiteSep  = byte('.')
+	graphiteTag  = byte(';')
 	sep          = byte(',')
 	finish       = byte('!')
 	eq           = byte('=')
@@ -75,6 +76,10 @@ const (
 	// NB: when TypeGraphite is specified, tags are ordered numerically rather
 	// than lexically.
 	//
+	// Graphite tagged series store their path under the metric name tag and
+	// generate IDs in canonical graphite form with the remaining tags sorted:
+	// {__name__:a.b},{dc:east},{app:web} -> a.b;app=web;dc=east
+	//
 	// NB 2: while the graphite scheme is valid, it is not available to choose as
 	// a general ID scheme; instead, it is set on any metric coming through the
 	// graphite ingestion path.
@@ -21,6 +21,8 @@
 package promql
 
 import (
+	"time"
+
 	"github.com/m3db/m3/src/query/models"
 	"github.com/m3db/m3/src/query/parser"
 	pql "github.com/prometheus/prometheus/promql"
@@ -40,6 +42,10 @@ type ParseFunctionExpr func(
 // ParseFn is a function that parses a query to a Prometheus expression.
 type ParseFn func(query string) (pql.Expr, error)
 
+// defaultSubqueryStep matches the default Prometheus evaluation interval,
+// which subqueries without an explicit step are evaluated at.
+const defaultSubqueryStep = time.Minute
+
 func defaultParseFn(query string) (pql.Expr, error) {
 	return pql.ParseExpr(query)
 }
@@ -55,18 +61,26 @@ type ParseOptions interface {
 	FunctionParseExpr() ParseFunctionExpr
 	// SetFunctionParseExpr sets the parsing function.
 	SetFunctionParseExpr(f ParseFunctionExpr) ParseOptions
+
+	// SubqueryDefaultStep gets the step for subqueries that do not specify one.
+	SubqueryDefaultStep() time.Duration
+	// SetSubqueryDefaultStep sets the step for subqueries that do not
+	// specify one.
+	SetSubqueryDefaultStep(step time.Duration) ParseOptions
 }
 
 type parseOptions struct {
-	fn          ParseFn
-	fnParseExpr ParseFunctionExpr
+	fn                  ParseFn
+	fnParseExpr         ParseFunctionExpr
+	subqueryDefaultStep time.Duration
 }
 
 // NewParseOptions creates a new parse options.
 func NewParseOptions() ParseOptions {
 	return &parseOptions{
-		fn:          defaultParseFn,
-		fnParseExpr: NewFunctionExpr,
+		fn:                  defaultParseFn,
+		fnParseExpr:         NewFunctionExpr,
+		subqueryDefaultStep: defaultSubqueryStep,
 	}
 }
 
@@ -89,3 +103,13 @@ func (o *parseOptions) SetFunctionParseExpr(f ParseFunctionExpr) ParseOptions {
 	opts.fnParseExpr = f
 	return &opts
 }
+
+func (o *parseOptions) SubqueryDefaultStep() time.Duration {
+	return o.subqueryDefaultStep
+}
+
+func (o *parseOptions) SetSubqueryDefaultStep(step time.Duration) ParseOptions {
+	opts := *o
+	opts.subqueryDefaultStep = step
+	return &opts
+}
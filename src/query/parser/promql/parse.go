@@ -25,9 +25,11 @@ import (
 	"time"
 
 	"github.com/m3db/m3/src/query/block"
+	"github.com/m3db/m3/src/query/functions"
 	"github.com/m3db/m3/src/query/functions/binary"
 	"github.com/m3db/m3/src/query/functions/lazy"
 	"github.com/m3db/m3/src/query/functions/scalar"
+	"github.com/m3db/m3/src/query/functions/subquery"
 	"github.com/m3db/m3/src/query/models"
 	"github.com/m3db/m3/src/query/parser"
 
@@ -35,10 +37,11 @@ import (
 )
 
 type promParser struct {
-	stepSize          time.Duration
-	expr              pql.Expr
-	tagOpts           models.TagOptions
-	parseFunctionExpr ParseFunctionExpr
+	stepSize            time.Duration
+	subqueryDefaultStep time.Duration
+	expr                pql.Expr
+	tagOpts             models.TagOptions
+	parseFunctionExpr   ParseFunctionExpr
 }
 
 // Parse takes a promQL string and converts parses it into a DAG.
@@ -55,18 +58,20 @@ func Parse(
 	}
 
 	return &promParser{
-		expr:              expr,
-		stepSize:          stepSize,
-		tagOpts:           tagOpts,
-		parseFunctionExpr: parseOptions.FunctionParseExpr(),
+		expr:                expr,
+		stepSize:            stepSize,
+		subqueryDefaultStep: parseOptions.SubqueryDefaultStep(),
+		tagOpts:             tagOpts,
+		parseFunctionExpr:   parseOptions.FunctionParseExpr(),
 	}, nil
 }
 
 func (p *promParser) DAG() (parser.Nodes, parser.Edges, error) {
 	state := &parseState{
-		stepSize:          p.stepSize,
-		tagOpts:           p.tagOpts,
-		parseFunctionExpr: p.parseFunctionExpr,
+		stepSize:            p.stepSize,
+		subqueryDefaultStep: p.subqueryDefaultStep,
+		tagOpts:             p.tagOpts,
+		parseFunctionExpr:   p.parseFunctionExpr,
 	}
 
 	err := state.walk(p.expr)
@@ -82,11 +87,12 @@ func (p *promParser) String() string {
 }
 
 type parseState struct {
-	stepSize          time.Duration
-	edges             parser.Edges
-	transforms        parser.Nodes
-	tagOpts           models.TagOptions
-	parseFunctionExpr ParseFunctionExpr
+	stepSize            time.Duration
+	subqueryDefaultStep time.Duration
+	edges               parser.Edges
+	transforms          parser.Nodes
+	tagOpts             models.TagOptions
+	parseFunctionExpr   ParseFunctionExpr
 }
 
 func (p *parseState) lastTransformID() parser.NodeID {
@@ -125,6 +131,56 @@ func (p *parseState) addLazyUnaryTransform(unaryOp string) error {
 	return nil
 }
 
+// addValueFilter sets a value filter on the fetch of a vector selector that
+// is compared with a number literal so that storage can skip series whose
+// values never satisfy the comparison.
+func (p *parseState) addValueFilter(
+	n *pql.BinaryExpr,
+	lhsStart, rhsStart int,
+) {
+	var (
+		lhs         = unwrapParenExpr(n.LHS)
+		rhs         = unwrapParenExpr(n.RHS)
+		literal     *pql.NumberLiteral
+		fetchIndex  int
+		scalarOnLHS bool
+	)
+	if _, ok := lhs.(*pql.VectorSelector); ok {
+		literal, _ = rhs.(*pql.NumberLiteral)
+		fetchIndex = lhsStart
+	} else if _, ok := rhs.(*pql.VectorSelector); ok {
+		literal, _ = lhs.(*pql.NumberLiteral)
+		fetchIndex = rhsStart
+		scalarOnLHS = true
+	}
+	if literal == nil {
+		return
+	}
+
+	filter, ok := binary.ComparisonValueFilter(getBinaryOpType(n.Op),
+		n.ReturnBool, scalarOnLHS, float64(literal.Val))
+	if !ok {
+		return
+	}
+
+	fetch, ok := p.transforms[fetchIndex].Op.(functions.FetchOp)
+	if !ok {
+		return
+	}
+	fetch.ValueFilter = &filter
+	p.transforms[fetchIndex].Op = fetch
+}
+
+func unwrapParenExpr(expr pql.Expr) pql.Expr {
+	for {
+		paren, ok := expr.(*pql.ParenExpr)
+		if !ok {
+			return expr
+		}
+		expr = paren.Expr
+	}
+}
+
 func (p *parseState) addLazyOffsetTransform(offset time.Duration) error {
 	// NB: if offset is <= 0, we do not apply any offsets.
 	if offset == 0 {
@@ -172,6 +228,39 @@ func adjustOffset(offset time.Duration, step time.Duration) time.Duration {
 	return offset + step - align
 }
 
+// walkSubquery adds the subquery expression evaluated at the subquery step,
+// followed by the subquery transform which collects its values into a range
+// vector for the enclosing range function.
+func (p *parseState) walkSubquery(n *pql.SubqueryExpr) error {
+	step := n.Step
+	if step == 0 {
+		step = p.subqueryDefaultStep
+	}
+
+	// NB: offsets within the subquery are aligned to the subquery step, which
+	// it is evaluated at, rather than to the query step.
+	queryStep := p.stepSize
+	p.stepSize = step
+	err := p.walk(n.Expr)
+	p.stepSize = queryStep
+	if err != nil {
+		return err
+	}
+
+	op, err := subquery.NewSubqueryOp(n.Range, n.Offset, step)
+	if err != nil {
+		return err
+	}
+
+	opTransform := parser.NewTransformFromOperation(op, p.transformLen())
+	p.edges = append(p.edges, parser.Edge{
+		ParentID: p.lastTransformID(),
+		ChildID:  opTransform.ID,
+	})
+	p.transforms = append(p.transforms, opTransform)
+	return nil
+}
+
 func (p *parseState) walk(node pql.Node) error {
 	if node == nil {
 		return nil
@@ -299,6 +388,10 @@ func (p *parseState) walk(node pql.Node) error {
 					argValues = append(argValues, e.Range)
 				}
 
+				if e, ok := expr.(*pql.SubqueryExpr); ok {
+					argValues = append(argValues, e.Range)
+				}
+
 				if err := p.walk(expr); err != nil {
 					return err
 				}
@@ -344,18 +437,21 @@ func (p *parseState) walk(node pql.Node) error {
 		return nil
 
 	case *pql.BinaryExpr:
+		lhsStart := p.transformLen()
 		err := p.walk(n.LHS)
 		if err != nil {
 			return err
 		}
 
 		lhsID := p.lastTransformID()
+		rhsStart := p.transformLen()
 		err = p.walk(n.RHS)
 		if err != nil {
 			return err
 		}
 
 		rhsID := p.lastTransformID()
+		p.addValueFilter(n, lhsStart, rhsStart)
 		op, err := NewBinaryOperator(n, lhsID, rhsID)
 		if err != nil {
 			return err
@@ -383,6 +479,9 @@ func (p *parseState) walk(node pql.Node) error {
 		p.transforms = append(p.transforms, opTransform)
 		return nil
 
+	case *pql.SubqueryExpr:
+		return p.walkSubquery(n)
+
 	case *pql.ParenExpr:
 		// Evaluate inside of paren expressions
 		return p.walk(n.Expr)
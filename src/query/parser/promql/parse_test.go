@@ -31,10 +31,12 @@ import (
 	"github.com/m3db/m3/src/query/functions/lazy"
 	"github.com/m3db/m3/src/query/functions/linear"
 	"github.com/m3db/m3/src/query/functions/scalar"
+	"github.com/m3db/m3/src/query/functions/subquery"
 	"github.com/m3db/m3/src/query/functions/tag"
 	"github.com/m3db/m3/src/query/functions/temporal"
 	"github.com/m3db/m3/src/query/models"
 	"github.com/m3db/m3/src/query/parser"
+	"github.com/m3db/m3/src/query/storage"
 
 	"github.com/prometheus/prometheus/promql"
 	pql "github.com/prometheus/prometheus/promql"
@@ -381,6 +383,37 @@ func TestBinaryParses(t *testing.T) {
 	}
 }
 
+var valueFilterParseTests = []struct {
+	q        string
+	expected *storage.ValueFilter
+}{
+	{"up > 10", &storage.ValueFilter{Type: storage.ValueFilterGreater, Value: 10}},
+	{"10 < (up)", &storage.ValueFilter{Type: storage.ValueFilterGreater, Value: 10}},
+	{"up <= 10", &storage.ValueFilter{Type: storage.ValueFilterLessEqual, Value: 10}},
+	{"up > bool 10", nil},
+	{"up + 10", nil},
+	{"up > up", nil},
+}
+
+func TestComparisonValueFilterParses(t *testing.T) {
+	for _, tt := range valueFilterParseTests {
+		t.Run(tt.q, func(t *testing.T) {
+			p, err := Parse(tt.q, time.Second,
+				models.NewTagOptions(), NewParseOptions())
+			require.NoError(t, err)
+			transforms, _, err := p.DAG()
+			require.NoError(t, err)
+			for _, transform := range transforms {
+				fetch, ok := transform.Op.(functions.FetchOp)
+				if !ok {
+					continue
+				}
+				assert.Equal(t, tt.expected, fetch.ValueFilter)
+			}
+		})
+	}
+}
+
 func TestParenPrecedenceParses(t *testing.T) {
 	p, err := Parse("(5^(up-6))", time.Second,
 		models.NewTagOptions(), NewParseOptions())
@@ -497,6 +530,90 @@ func TestFailedTemporalParse(t *testing.T) {
 	require.Error(t, err)
 }
 
+var subqueryParseTests = []struct {
+	q              string
+	expectedTypes  []string
+	expectedString string
+}{
+	{
+		"max_over_time(rate(up[5m])[1h:1m])",
+		[]string{functions.FetchType, temporal.RateType,
+			subquery.SubqueryType, temporal.MaxType},
+		"type: subquery, range: 1h0m0s, offset: 0s, step: 1m0s",
+	},
+	{
+		"rate(up[10m:])",
+		[]string{functions.FetchType, subquery.SubqueryType, temporal.RateType},
+		"type: subquery, range: 10m0s, offset: 0s, step: 1m0s",
+	},
+	{
+		"deriv(sum(up)[30m:15s] offset 5m)",
+		[]string{functions.FetchType, aggregation.SumType,
+			subquery.SubqueryType, temporal.DerivType},
+		"type: subquery, range: 30m0s, offset: 5m0s, step: 15s",
+	},
+}
+
+func TestSubqueryParses(t *testing.T) {
+	for _, tt := range subqueryParseTests {
+		t.Run(tt.q, func(t *testing.T) {
+			p, err := Parse(tt.q, time.Second, models.NewTagOptions(),
+				NewParseOptions())
+			require.NoError(t, err)
+			transforms, edges, err := p.DAG()
+			require.NoError(t, err)
+			require.Len(t, transforms, len(tt.expectedTypes))
+			require.Len(t, edges, len(tt.expectedTypes)-1)
+			for i, expectedType := range tt.expectedTypes {
+				id := parser.NodeID(fmt.Sprint(i))
+				assert.Equal(t, expectedType, transforms[i].Op.OpType())
+				assert.Equal(t, id, transforms[i].ID)
+				if i > 0 {
+					assert.Equal(t, parser.NodeID(fmt.Sprint(i-1)), edges[i-1].ParentID)
+					assert.Equal(t, id, edges[i-1].ChildID)
+				}
+			}
+
+			subqueryIdx := len(tt.expectedTypes) - 2
+			assert.Equal(t, tt.expectedString, transforms[subqueryIdx].Op.String())
+		})
+	}
+}
+
+func TestSubqueryDefaultStep(t *testing.T) {
+	q := "max_over_time(up[1h:])"
+	opts := NewParseOptions().SetSubqueryDefaultStep(30 * time.Second)
+	p, err := Parse(q, time.Second, models.NewTagOptions(), opts)
+	require.NoError(t, err)
+	transforms, _, err := p.DAG()
+	require.NoError(t, err)
+	require.Len(t, transforms, 3)
+	assert.Equal(t, "type: subquery, range: 1h0m0s, offset: 0s, step: 30s",
+		transforms[1].Op.String())
+}
+
+func TestSubqueryInnerOffsetAlignsToSubqueryStep(t *testing.T) {
+	q := "max_over_time(up[1h:2m] offset 3m)"
+	p, err := Parse(q, time.Second, models.NewTagOptions(), NewParseOptions())
+	require.NoError(t, err)
+	transforms, _, err := p.DAG()
+	require.NoError(t, err)
+	require.Len(t, transforms, 3)
+	assert.Equal(t, "type: subquery, range: 1h0m0s, offset: 3m0s, step: 2m0s",
+		transforms[1].Op.String())
+
+	q = "max_over_time((up offset 3m)[1h:2m])"
+	p, err = Parse(q, time.Second, models.NewTagOptions(), NewParseOptions())
+	require.NoError(t, err)
+	transforms, _, err = p.DAG()
+	require.NoError(t, err)
+	require.Len(t, transforms, 4)
+	assert.Equal(t, lazy.OffsetType, transforms[1].Op.OpType())
+	fetch, ok := transforms[0].Op.(functions.FetchOp)
+	require.True(t, ok)
+	assert.Equal(t, 4*time.Minute, fetch.Offset)
+}
+
 func TestMissingTagsDoNotPanic(t *testing.T) {
 	q := `label_join(up, "foo", ",")`
 	p, err := Parse(q, time.Second, models.NewTagOptions(), NewParseOptions())
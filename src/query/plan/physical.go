@@ -38,6 +38,10 @@ type PhysicalPlan struct {
 	Debug            bool
 	BlockType        models.FetchedBlockType
 	LookbackDuration time.Duration
+
+	// inputTimeSpecs are the time specs the inputs of subquery steps are
+	// evaluated over.
+	inputTimeSpecs map[parser.NodeID]transform.TimeSpec
 }
 
 // ResultOp is responsible for delivering results to the clients.
@@ -86,11 +90,76 @@ func NewPhysicalPlan(
 }
 
 func (p PhysicalPlan) shiftTime() PhysicalPlan {
+	p.inputTimeSpecs = make(map[parser.NodeID]transform.TimeSpec)
+	p.TimeSpec = p.shiftScope(p.TimeSpec, []parser.NodeID{p.ResultStep.Parent})
+	return p
+}
+
+// shiftScope shifts the time spec for the steps evaluated over it, starting
+// from the given steps, and derives the time specs for the inputs of any
+// subquery steps within that scope. As in Prometheus, subquery expressions are
+// evaluated at the exact steps of the subquery ranges of the query steps, so
+// their time specs are derived from the time spec before it is shifted and the
+// subquery steps do not shift the time spec themselves.
+func (p PhysicalPlan) shiftScope(
+	timeSpec transform.TimeSpec,
+	ids []parser.NodeID,
+) transform.TimeSpec {
+	steps, subqueries := p.scope(ids)
+	for _, step := range subqueries {
+		op := step.Transform.Op.(transform.SubqueryOp)
+		p.inputTimeSpecs[step.ID()] = p.shiftScope(
+			op.InputTimeSpec(timeSpec), step.Parents)
+	}
+
+	return p.shiftTimeSpec(timeSpec, steps)
+}
+
+// scope returns the given steps and their ancestors which are evaluated over
+// the same time spec, excluding subquery steps which are returned separately
+// since their ancestors are evaluated over a separate time spec.
+func (p PhysicalPlan) scope(
+	ids []parser.NodeID,
+) ([]LogicalStep, []LogicalStep) {
+	var (
+		steps      []LogicalStep
+		subqueries []LogicalStep
+		visited    = make(map[parser.NodeID]struct{}, len(p.steps))
+	)
+
+	for len(ids) > 0 {
+		id := ids[len(ids)-1]
+		ids = ids[:len(ids)-1]
+		if _, ok := visited[id]; ok {
+			continue
+		}
+
+		visited[id] = struct{}{}
+		step, ok := p.steps[id]
+		if !ok {
+			continue
+		}
+
+		if _, ok := step.Transform.Op.(transform.SubqueryOp); ok {
+			subqueries = append(subqueries, step)
+			continue
+		}
+
+		steps = append(steps, step)
+		ids = append(ids, step.Parents...)
+	}
+
+	return steps, subqueries
+}
+
+func (p PhysicalPlan) shiftTimeSpec(
+	timeSpec transform.TimeSpec,
+	steps []LogicalStep,
+) transform.TimeSpec {
 	var maxRange time.Duration
 	// Start offset with lookback
 	maxOffset := p.LookbackDuration
-	for _, transformID := range p.pipeline {
-		node := p.steps[transformID]
+	for _, node := range steps {
 		boundOp, ok := node.Transform.Op.(transform.BoundOp)
 		if !ok {
 			continue
@@ -107,16 +176,16 @@ func (p PhysicalPlan) shiftTime() PhysicalPlan {
 	}
 
 	startShift := maxOffset + maxRange
-	shift := startShift % p.TimeSpec.Step
-	extraStep := p.TimeSpec.Step
+	shift := startShift % timeSpec.Step
+	extraStep := timeSpec.Step
 	if shift == 0 {
 		// NB: if the start is divisible by offset, no need to take an extra step.
 		extraStep = 0
 	}
 
 	alignedShift := startShift - extraStep - shift
-	p.TimeSpec.Start = p.TimeSpec.Start.Add(-1 * alignedShift)
-	return p
+	timeSpec.Start = timeSpec.Start.Add(-1 * alignedShift)
+	return timeSpec
 }
 
 func (p PhysicalPlan) createResultNode() (PhysicalPlan, error) {
@@ -158,6 +227,13 @@ func (p PhysicalPlan) Step(ID parser.NodeID) (LogicalStep, bool) {
 	return step, ok
 }
 
+// InputTimeSpec returns the time spec that the inputs of the step with the
+// given ID are evaluated over, if it differs from the time spec of the plan.
+func (p PhysicalPlan) InputTimeSpec(ID parser.NodeID) (transform.TimeSpec, bool) {
+	timeSpec, ok := p.inputTimeSpecs[ID]
+	return timeSpec, ok
+}
+
 // String representation of the physical plan.
 func (p PhysicalPlan) String() string {
 	return fmt.Sprintf("StepCount: %s, Pipeline: %s, Result: %s, TimeSpec: %v",
@@ -27,6 +27,7 @@ import (
 
 	"github.com/m3db/m3/src/query/functions"
 	"github.com/m3db/m3/src/query/functions/aggregation"
+	"github.com/m3db/m3/src/query/functions/subquery"
 	"github.com/m3db/m3/src/query/models"
 	"github.com/m3db/m3/src/query/parser"
 
@@ -101,3 +102,58 @@ func TestShiftTime(t *testing.T) {
 		Add(-1*(time.Minute+time.Hour+defaultLookbackDuration)), p.TimeSpec.Start,
 		"start time offset by fetch")
 }
+
+func TestShiftTimeSubquery(t *testing.T) {
+	fetchTransform := parser.NewTransformFromOperation(
+		functions.FetchOp{Range: 5 * time.Minute}, 1)
+	sq, err := subquery.NewSubqueryOp(time.Hour, 10*time.Minute, time.Minute)
+	require.NoError(t, err)
+	subqueryTransform := parser.NewTransformFromOperation(sq, 2)
+	agg, err := aggregation.NewAggregationOp(aggregation.CountType, aggregation.NodeParams{})
+	require.NoError(t, err)
+	countTransform := parser.NewTransformFromOperation(agg, 3)
+	transforms := parser.Nodes{fetchTransform, subqueryTransform, countTransform}
+	edges := parser.Edges{
+		parser.Edge{
+			ParentID: fetchTransform.ID,
+			ChildID:  subqueryTransform.ID,
+		},
+		parser.Edge{
+			ParentID: subqueryTransform.ID,
+			ChildID:  countTransform.ID,
+		},
+	}
+
+	lp, err := NewLogicalPlan(transforms, edges)
+	require.NoError(t, err)
+
+	params := testRequestParams()
+	params.Start = time.Unix(1500000000, 0)
+	params.End = params.Start.Add(time.Hour)
+	params.Now = params.End
+
+	p, err := NewPhysicalPlan(lp, params)
+	require.NoError(t, err)
+
+	// NB: the query is only shifted by the lookback, the subquery range and
+	// offset are covered by the time spec of the subquery expression.
+	outerStart := params.Start.Add(-1 * defaultLookbackDuration)
+	assert.Equal(t, outerStart, p.TimeSpec.Start)
+	assert.Equal(t, time.Second, p.TimeSpec.Step)
+
+	_, ok := p.InputTimeSpec(countTransform.ID)
+	assert.False(t, ok)
+
+	inner, ok := p.InputTimeSpec(subqueryTransform.ID)
+	require.True(t, ok)
+
+	// NB: the subquery is evaluated at the exact steps of the earliest range of
+	// the query, shifted by the range of the fetch within the subquery.
+	innerStart := params.Start.Add(-1 * (time.Hour + 10*time.Minute)).
+		Add(-1 * (5*time.Minute + defaultLookbackDuration))
+	assert.Equal(t, innerStart.UnixNano(), inner.Start.UnixNano())
+	// NB: the last subquery step is at 49m, the last multiple of the subquery
+	// step before the last query step at 59m59s less the offset.
+	assert.Equal(t, params.Start.Add(50*time.Minute).UnixNano(), inner.End.UnixNano())
+	assert.Equal(t, time.Minute, inner.Step)
+}
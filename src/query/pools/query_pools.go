@@ -25,6 +25,7 @@ import (
 
 	"github.com/m3db/m3/src/dbnode/namespace"
 	"github.com/m3db/m3/src/dbnode/encoding"
+	"github.com/m3db/m3/src/dbnode/encoding/histogram"
 	"github.com/m3db/m3/src/dbnode/encoding/m3tsz"
 	"github.com/m3db/m3/src/dbnode/x/xpool"
 	xconfig "github.com/m3db/m3/src/x/config"
@@ -142,7 +143,7 @@ func BuildIteratorPools() encoding.IteratorPools {
 	encodingOpts := encoding.NewOptions()
 	readerIterAlloc := func(r io.Reader, _ namespace.SchemaDescr) encoding.ReaderIterator {
 		intOptimized := m3tsz.DefaultIntOptimizationEnabled
-		return m3tsz.NewReaderIterator(r, intOptimized, encodingOpts)
+		return histogram.NewReaderIterator(r, intOptimized, encodingOpts)
 	}
 
 	pools.multiReaderIterator.Init(readerIterAlloc)
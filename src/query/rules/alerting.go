@@ -0,0 +1,371 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package rules
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/m3db/m3/src/query/models"
+)
+
+const (
+	// alertMetricName is the name of the series recording active alerts.
+	alertMetricName = "ALERTS"
+	// alertNameLabel is the label holding the name of the alerting rule.
+	alertNameLabel = "alertname"
+	// alertStateLabel is the label holding the state of an active alert.
+	alertStateLabel = "alertstate"
+
+	// resolvedRetention is how long resolved alerts are kept, so that they
+	// are sent to Alertmanager as resolved.
+	resolvedRetention = 15 * time.Minute
+)
+
+// AlertState is the state of an alert.
+type AlertState int
+
+const (
+	// StateInactive is the state of an alert that is not active.
+	StateInactive AlertState = iota
+	// StatePending is the state of an alert that has been active for less
+	// than the hold duration of its rule.
+	StatePending
+	// StateFiring is the state of an alert that has been active for longer
+	// than the hold duration of its rule.
+	StateFiring
+)
+
+func (s AlertState) String() string {
+	switch s {
+	case StateInactive:
+		return "inactive"
+	case StatePending:
+		return "pending"
+	case StateFiring:
+		return "firing"
+	}
+
+	return fmt.Sprintf("unknown alert state: %d", int(s))
+}
+
+// Alert is an alert for a single series of the result of an alerting rule.
+type Alert struct {
+	// State is the state of the alert.
+	State AlertState
+	// Tags are the labels of the alert.
+	Tags models.Tags
+	// Annotations are the expanded annotations of the alert.
+	Annotations map[string]string
+	// Value is the value of the series at the last evaluation.
+	Value float64
+	// ActiveAt is when the alert became active.
+	ActiveAt time.Time
+	// FiredAt is when the alert started firing.
+	FiredAt time.Time
+	// ResolvedAt is when the alert was resolved, zero if it is active.
+	ResolvedAt time.Time
+	// LastSentAt is when the alert was last sent to Alertmanager.
+	LastSentAt time.Time
+	// ValidUntil is when the alert expires unless it is sent again.
+	ValidUntil time.Time
+}
+
+func (a *Alert) needsSending(t time.Time, resendDelay time.Duration) bool {
+	if a.State == StatePending {
+		return false
+	}
+
+	// Resolved alerts are sent once as soon as they are resolved.
+	if a.ResolvedAt.After(a.LastSentAt) {
+		return true
+	}
+
+	return !a.LastSentAt.Add(resendDelay).After(t)
+}
+
+// AlertingRule generates alerts for each series in the result of a query,
+// which fire once they have been active for the hold duration of the rule.
+type AlertingRule struct {
+	ruleState
+
+	name         string
+	query        string
+	holdDuration time.Duration
+	labels       map[string]string
+	annotations  map[string]string
+
+	alertsLock sync.RWMutex
+	active     map[string]*Alert
+}
+
+// NewAlertingRule creates a new alerting rule. Labels and annotations may
+// use Go templates referring to the labels and value of each series with
+// $labels and $value.
+func NewAlertingRule(
+	name string,
+	query string,
+	holdDuration time.Duration,
+	labels map[string]string,
+	annotations map[string]string,
+) *AlertingRule {
+	return &AlertingRule{
+		ruleState:    newRuleState(),
+		name:         name,
+		query:        query,
+		holdDuration: holdDuration,
+		labels:       labels,
+		annotations:  annotations,
+		active:       make(map[string]*Alert),
+	}
+}
+
+// Name returns the name of the alert.
+func (r *AlertingRule) Name() string { return r.name }
+
+// Query returns the query the rule evaluates.
+func (r *AlertingRule) Query() string { return r.query }
+
+// HoldDuration returns how long alerts must be active before they fire.
+func (r *AlertingRule) HoldDuration() time.Duration { return r.holdDuration }
+
+// Labels returns the labels added to alerts.
+func (r *AlertingRule) Labels() map[string]string { return r.labels }
+
+// Annotations returns the annotations of alerts.
+func (r *AlertingRule) Annotations() map[string]string { return r.annotations }
+
+// Eval evaluates the rule at the given time, updating the state of its
+// alerts and returning an ALERTS series for each pending or firing alert.
+func (r *AlertingRule) Eval(
+	ctx context.Context,
+	t time.Time,
+	queryFn QueryFn,
+) (Vector, error) {
+	vector, err := r.eval(ctx, t, queryFn)
+	r.setEvaluation(t, err)
+	return vector, err
+}
+
+func (r *AlertingRule) eval(
+	ctx context.Context,
+	t time.Time,
+	queryFn QueryFn,
+) (Vector, error) {
+	vector, err := queryFn(ctx, r.query, t)
+	if err != nil {
+		return nil, err
+	}
+
+	r.alertsLock.Lock()
+	defer r.alertsLock.Unlock()
+
+	results := make(map[string]struct{}, len(vector))
+	for _, sample := range vector {
+		var (
+			sampleLabels = tagsToMap(sample.Tags)
+			labels       = make(map[string]string, len(r.labels)+1)
+			annotations  = make(map[string]string, len(r.annotations))
+		)
+
+		for name, text := range r.labels {
+			labels[name] = expandTemplate(name, text, sampleLabels, sample.Value)
+		}
+
+		for name, text := range r.annotations {
+			annotations[name] = expandTemplate(name, text, sampleLabels,
+				sample.Value)
+		}
+
+		labels[alertNameLabel] = r.name
+		tags := withLabels(sample.Tags.WithoutName(), labels)
+		id := string(tags.ID())
+		if _, ok := results[id]; ok {
+			return nil, fmt.Errorf("vector contains metrics with the same "+
+				"labelset after applying alert labels: %s", id)
+		}
+
+		results[id] = struct{}{}
+		if alert, ok := r.active[id]; ok && alert.State != StateInactive {
+			alert.Value = sample.Value
+			alert.Annotations = annotations
+			continue
+		}
+
+		r.active[id] = &Alert{
+			State:       StatePending,
+			Tags:        tags,
+			Annotations: annotations,
+			Value:       sample.Value,
+			ActiveAt:    t,
+		}
+	}
+
+	var alerts Vector
+	for id, alert := range r.active {
+		if _, ok := results[id]; !ok {
+			// Pending alerts are dropped as soon as they are no longer
+			// active, firing alerts are kept as resolved for a while so that
+			// they are sent as resolved.
+			if alert.State == StatePending ||
+				(!alert.ResolvedAt.IsZero() &&
+					t.Sub(alert.ResolvedAt) > resolvedRetention) {
+				delete(r.active, id)
+				continue
+			}
+
+			if alert.State != StateInactive {
+				alert.State = StateInactive
+				alert.ResolvedAt = t
+			}
+
+			continue
+		}
+
+		if alert.State == StatePending && t.Sub(alert.ActiveAt) >= r.holdDuration {
+			alert.State = StateFiring
+			alert.FiredAt = t
+		}
+
+		alerts = append(alerts, Sample{
+			Tags: withLabels(alert.Tags, map[string]string{
+				alertStateLabel: alert.State.String(),
+			}).SetName([]byte(alertMetricName)),
+			Value: 1,
+		})
+	}
+
+	return alerts, nil
+}
+
+// State returns the most severe state of the alerts of the rule.
+func (r *AlertingRule) State() AlertState {
+	r.alertsLock.RLock()
+	defer r.alertsLock.RUnlock()
+
+	state := StateInactive
+	for _, alert := range r.active {
+		if alert.State > state {
+			state = alert.State
+		}
+	}
+
+	return state
+}
+
+// ActiveAlerts returns copies of the pending and firing alerts of the rule,
+// sorted by their labels.
+func (r *AlertingRule) ActiveAlerts() []Alert {
+	r.alertsLock.RLock()
+	defer r.alertsLock.RUnlock()
+
+	alerts := make([]Alert, 0, len(r.active))
+	for _, alert := range r.active {
+		if alert.State != StateInactive {
+			alerts = append(alerts, *alert)
+		}
+	}
+
+	sortAlerts(alerts)
+	return alerts
+}
+
+// alertsToSend returns copies of the alerts that need to be sent to
+// Alertmanager at the given time, marking them as sent.
+func (r *AlertingRule) alertsToSend(
+	t time.Time,
+	resendDelay time.Duration,
+	interval time.Duration,
+) []Alert {
+	r.alertsLock.Lock()
+	defer r.alertsLock.Unlock()
+
+	validity := resendDelay
+	if interval > validity {
+		validity = interval
+	}
+
+	var alerts []Alert
+	for _, alert := range r.active {
+		if !alert.needsSending(t, resendDelay) {
+			continue
+		}
+
+		alert.LastSentAt = t
+		if alert.ResolvedAt.IsZero() {
+			// NB: firing alerts expire in Alertmanager unless they are sent
+			// again, in case the coordinator stops evaluating them.
+			alert.ValidUntil = t.Add(4 * validity)
+		}
+
+		alerts = append(alerts, *alert)
+	}
+
+	sortAlerts(alerts)
+	return alerts
+}
+
+func sortAlerts(alerts []Alert) {
+	sort.Slice(alerts, func(i, j int) bool {
+		return bytes.Compare(alerts[i].Tags.ID(), alerts[j].Tags.ID()) < 0
+	})
+}
+
+// expandTemplate expands the template text with the labels and value of a
+// series, returning the error in place of the text if it fails to expand.
+func expandTemplate(
+	name string,
+	text string,
+	labels map[string]string,
+	value float64,
+) string {
+	// NB: matches the variables Prometheus defines for rule templates.
+	const defs = "{{$labels := .Labels}}{{$value := .Value}}"
+	if !strings.Contains(text, "{{") {
+		return text
+	}
+
+	tmpl, err := template.New(name).Option("missingkey=zero").Parse(defs + text)
+	if err != nil {
+		return fmt.Sprintf("<error expanding template: %v>", err)
+	}
+
+	var buf bytes.Buffer
+	data := struct {
+		Labels map[string]string
+		Value  float64
+	}{
+		Labels: labels,
+		Value:  value,
+	}
+
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return fmt.Sprintf("<error expanding template: %v>", err)
+	}
+
+	return buf.String()
+}
@@ -0,0 +1,142 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package rules
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlertingRuleLifecycle(t *testing.T) {
+	var (
+		ctx   = context.Background()
+		start = time.Unix(1000, 0)
+		rule  = NewAlertingRule("HighLatency", "latency > 1", 2*time.Minute,
+			map[string]string{"severity": "{{ $labels.job }}-page"},
+			map[string]string{"summary": "latency is {{ $value }}"})
+		active = newTestQueryFn(Vector{
+			{Tags: newTestTags("__name__", "latency", "job", "api"), Value: 2},
+		}, nil)
+		inactive = newTestQueryFn(nil, nil)
+	)
+
+	// The alert is pending until it has been active for the hold duration.
+	vector, err := rule.Eval(ctx, start, active)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(vector))
+	assert.Equal(t, map[string]string{
+		"__name__":   "ALERTS",
+		"alertname":  "HighLatency",
+		"alertstate": "pending",
+		"job":        "api",
+		"severity":   "api-page",
+	}, tagsToMap(vector[0].Tags))
+	assert.Equal(t, 1.0, vector[0].Value)
+	assert.Equal(t, StatePending, rule.State())
+
+	alerts := rule.ActiveAlerts()
+	require.Equal(t, 1, len(alerts))
+	assert.Equal(t, map[string]string{
+		"alertname": "HighLatency",
+		"job":       "api",
+		"severity":  "api-page",
+	}, tagsToMap(alerts[0].Tags))
+	assert.Equal(t, map[string]string{"summary": "latency is 2"},
+		alerts[0].Annotations)
+	assert.Equal(t, start, alerts[0].ActiveAt)
+
+	// Pending alerts are not sent.
+	assert.Equal(t, 0, len(rule.alertsToSend(start, time.Minute, time.Minute)))
+
+	vector, err = rule.Eval(ctx, start.Add(2*time.Minute), active)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(vector))
+	assert.Equal(t, "firing", tagsToMap(vector[0].Tags)["alertstate"])
+	assert.Equal(t, StateFiring, rule.State())
+
+	sent := rule.alertsToSend(start.Add(2*time.Minute), time.Minute, time.Minute)
+	require.Equal(t, 1, len(sent))
+	assert.Equal(t, start.Add(2*time.Minute), sent[0].FiredAt)
+	assert.Equal(t, start.Add(6*time.Minute), sent[0].ValidUntil)
+
+	// Firing alerts are only sent again after the resend delay.
+	assert.Equal(t, 0, len(rule.alertsToSend(start.Add(150*time.Second),
+		time.Minute, time.Minute)))
+	assert.Equal(t, 1, len(rule.alertsToSend(start.Add(3*time.Minute),
+		time.Minute, time.Minute)))
+
+	// Resolved alerts are sent once as resolved.
+	resolvedAt := start.Add(4 * time.Minute)
+	vector, err = rule.Eval(ctx, resolvedAt, inactive)
+	require.NoError(t, err)
+	assert.Equal(t, 0, len(vector))
+	assert.Equal(t, StateInactive, rule.State())
+	assert.Equal(t, 0, len(rule.ActiveAlerts()))
+
+	sent = rule.alertsToSend(resolvedAt, time.Minute, time.Minute)
+	require.Equal(t, 1, len(sent))
+	assert.Equal(t, resolvedAt, sent[0].ResolvedAt)
+
+	// Resolved alerts are dropped after the retention period.
+	_, err = rule.Eval(ctx, resolvedAt.Add(resolvedRetention+time.Minute),
+		inactive)
+	require.NoError(t, err)
+	assert.Equal(t, 0, len(rule.active))
+}
+
+func TestAlertingRuleDropsPendingAlerts(t *testing.T) {
+	var (
+		ctx    = context.Background()
+		start  = time.Unix(1000, 0)
+		rule   = NewAlertingRule("HighLatency", "latency > 1", time.Minute, nil, nil)
+		active = newTestQueryFn(Vector{
+			{Tags: newTestTags("job", "api"), Value: 2},
+		}, nil)
+	)
+
+	_, err := rule.Eval(ctx, start, active)
+	require.NoError(t, err)
+	assert.Equal(t, 1, len(rule.active))
+
+	_, err = rule.Eval(ctx, start.Add(30*time.Second), newTestQueryFn(nil, nil))
+	require.NoError(t, err)
+	assert.Equal(t, 0, len(rule.active))
+
+	// Once active again the hold duration restarts.
+	_, err = rule.Eval(ctx, start.Add(time.Minute), active)
+	require.NoError(t, err)
+	assert.Equal(t, StatePending, rule.State())
+}
+
+func TestExpandTemplate(t *testing.T) {
+	labels := map[string]string{"job": "api"}
+	assert.Equal(t, "plain", expandTemplate("a", "plain", labels, 1))
+	assert.Equal(t, "api is 1.5",
+		expandTemplate("a", "{{ $labels.job }} is {{ $value }}", labels, 1.5))
+	assert.Equal(t, " is missing",
+		expandTemplate("a", "{{ $labels.foo }} is missing", labels, 1))
+	assert.Contains(t, expandTemplate("a", "{{ $labels.job ", labels, 1),
+		"<error expanding template")
+}
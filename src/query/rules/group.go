@@ -0,0 +1,202 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package rules
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/m3db/m3/src/cmd/services/m3coordinator/ingest"
+	"github.com/m3db/m3/src/query/ts"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"go.uber.org/zap"
+)
+
+// Group is a group of rules evaluated in order at a fixed interval.
+type Group struct {
+	mu sync.RWMutex
+
+	name     string
+	file     string
+	interval time.Duration
+	rules    []Rule
+	opts     ManagerOptions
+	metrics  managerMetrics
+	logger   *zap.Logger
+
+	lastEvaluation     time.Time
+	evaluationDuration time.Duration
+
+	doneCh chan struct{}
+	wg     sync.WaitGroup
+}
+
+func newGroup(
+	name string,
+	file string,
+	interval time.Duration,
+	rules []Rule,
+	opts ManagerOptions,
+	metrics managerMetrics,
+) *Group {
+	return &Group{
+		name:     name,
+		file:     file,
+		interval: interval,
+		rules:    rules,
+		opts:     opts,
+		metrics:  metrics,
+		logger: opts.InstrumentOptions.Logger().With(
+			zap.String("file", file), zap.String("group", name)),
+		doneCh: make(chan struct{}),
+	}
+}
+
+// Name returns the name of the group.
+func (g *Group) Name() string { return g.name }
+
+// File returns the file the group was loaded from.
+func (g *Group) File() string { return g.file }
+
+// Interval returns the evaluation interval of the group.
+func (g *Group) Interval() time.Duration { return g.interval }
+
+// Rules returns the rules of the group.
+func (g *Group) Rules() []Rule { return g.rules }
+
+// LastEvaluation returns the time of the last evaluation of the group.
+func (g *Group) LastEvaluation() time.Time {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.lastEvaluation
+}
+
+// EvaluationDuration returns how long the last evaluation of the group took.
+func (g *Group) EvaluationDuration() time.Duration {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.evaluationDuration
+}
+
+// Eval evaluates each rule of the group in order at the given time, writing
+// their results and sending any alerts to Alertmanager.
+func (g *Group) Eval(ctx context.Context, t time.Time) {
+	start := g.opts.NowFn()
+	for _, rule := range g.rules {
+		g.metrics.evaluations.Inc(1)
+		vector, err := rule.Eval(ctx, t, g.opts.QueryFn)
+		if err != nil {
+			g.metrics.evaluationFailures.Inc(1)
+			g.logger.Warn("rule evaluation failed",
+				zap.String("rule", rule.Name()), zap.Error(err))
+			continue
+		}
+
+		if alertingRule, ok := rule.(*AlertingRule); ok {
+			g.sendAlerts(ctx, alertingRule, t)
+		}
+
+		g.write(ctx, rule, vector, t)
+	}
+
+	duration := g.opts.NowFn().Sub(start)
+	g.metrics.evaluationLatency.Record(duration)
+
+	g.mu.Lock()
+	g.lastEvaluation = t
+	g.evaluationDuration = duration
+	g.mu.Unlock()
+}
+
+func (g *Group) write(ctx context.Context, rule Rule, vector Vector, t time.Time) {
+	if g.opts.Writer == nil {
+		return
+	}
+
+	var errs int64
+	for _, sample := range vector {
+		datapoints := ts.Datapoints{{Timestamp: t, Value: sample.Value}}
+		err := g.opts.Writer.Write(ctx, sample.Tags, datapoints,
+			xtime.Millisecond, nil, ingest.WriteOptions{})
+		if err != nil {
+			if errs == 0 {
+				g.logger.Warn("unable to write rule result",
+					zap.String("rule", rule.Name()), zap.Error(err))
+			}
+
+			errs++
+		}
+	}
+
+	g.metrics.writeErrors.Inc(errs)
+}
+
+func (g *Group) sendAlerts(ctx context.Context, rule *AlertingRule, t time.Time) {
+	if g.opts.Notifier == nil {
+		return
+	}
+
+	alerts := rule.alertsToSend(t, g.opts.ResendDelay, g.interval)
+	if err := g.opts.Notifier.Send(ctx, alerts); err != nil {
+		g.metrics.notificationErrors.Inc(1)
+		g.logger.Warn("unable to send alerts",
+			zap.String("rule", rule.Name()), zap.Error(err))
+	}
+}
+
+// start starts evaluating the group at each multiple of its interval.
+func (g *Group) start() {
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go func() {
+			select {
+			case <-g.doneCh:
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
+
+		for {
+			next := g.opts.NowFn().Truncate(g.interval).Add(g.interval)
+			timer := time.NewTimer(next.Sub(g.opts.NowFn()))
+			select {
+			case <-g.doneCh:
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+
+			g.Eval(ctx, next)
+		}
+	}()
+}
+
+// stop stops evaluating the group, waiting for any evaluation in progress.
+func (g *Group) stop() {
+	close(g.doneCh)
+	g.wg.Wait()
+}
@@ -0,0 +1,248 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package rules
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/m3db/m3/src/cmd/services/m3coordinator/ingest"
+	"github.com/m3db/m3/src/x/clock"
+	xerrors "github.com/m3db/m3/src/x/errors"
+	"github.com/m3db/m3/src/x/instrument"
+
+	"github.com/prometheus/prometheus/pkg/rulefmt"
+	"github.com/uber-go/tally"
+)
+
+const (
+	defaultEvaluationInterval = time.Minute
+	defaultResendDelay        = time.Minute
+)
+
+var (
+	errNoQueryFn      = errors.New("rule manager requires a query function")
+	errManagerStarted = errors.New("rule manager already started")
+	errManagerClosed  = errors.New("rule manager already closed")
+)
+
+// ManagerOptions are the options for a rule manager.
+type ManagerOptions struct {
+	// RuleFiles are the patterns of the Prometheus rule files to load.
+	RuleFiles []string
+	// EvaluationInterval is the interval of groups that do not set one,
+	// defaults to a minute.
+	EvaluationInterval time.Duration
+	// QueryFn evaluates the queries of rules.
+	QueryFn QueryFn
+	// Writer writes the results of rules, results are not written if unset.
+	Writer ingest.DownsamplerAndWriter
+	// Notifier sends alerts to Alertmanager, alerts are not sent if unset.
+	Notifier *Notifier
+	// ResendDelay is how long to wait before sending an alert that is still
+	// firing to Alertmanager again, defaults to a minute.
+	ResendDelay time.Duration
+	// InstrumentOptions are the instrument options.
+	InstrumentOptions instrument.Options
+	// NowFn is the function used to get the current time, defaults to
+	// time.Now.
+	NowFn clock.NowFn
+}
+
+// Manager loads groups of rules and evaluates them.
+type Manager struct {
+	mu sync.Mutex
+
+	groups  []*Group
+	started bool
+	closed  bool
+}
+
+type managerMetrics struct {
+	evaluations        tally.Counter
+	evaluationFailures tally.Counter
+	writeErrors        tally.Counter
+	notificationErrors tally.Counter
+	evaluationLatency  tally.Timer
+}
+
+func newManagerMetrics(scope tally.Scope) managerMetrics {
+	return managerMetrics{
+		evaluations:        scope.Counter("evaluations"),
+		evaluationFailures: scope.Counter("evaluation-failures"),
+		writeErrors:        scope.Counter("write-errors"),
+		notificationErrors: scope.Counter("notification-errors"),
+		evaluationLatency:  scope.Timer("evaluation-latency"),
+	}
+}
+
+// NewManager creates a new rule manager, loading the rule groups of each
+// rule file.
+func NewManager(opts ManagerOptions) (*Manager, error) {
+	if opts.QueryFn == nil {
+		return nil, errNoQueryFn
+	}
+
+	if opts.EvaluationInterval <= 0 {
+		opts.EvaluationInterval = defaultEvaluationInterval
+	}
+
+	if opts.ResendDelay <= 0 {
+		opts.ResendDelay = defaultResendDelay
+	}
+
+	if opts.InstrumentOptions == nil {
+		opts.InstrumentOptions = instrument.NewOptions()
+	}
+
+	if opts.NowFn == nil {
+		opts.NowFn = time.Now
+	}
+
+	var files []string
+	for _, pattern := range opts.RuleFiles {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rule file pattern %s: %v",
+				pattern, err)
+		}
+
+		files = append(files, matches...)
+	}
+
+	sort.Strings(files)
+	metrics := newManagerMetrics(opts.InstrumentOptions.MetricsScope())
+	manager := &Manager{}
+	for _, file := range files {
+		groups, err := loadGroups(file, opts, metrics)
+		if err != nil {
+			return nil, err
+		}
+
+		manager.groups = append(manager.groups, groups...)
+	}
+
+	return manager, nil
+}
+
+func loadGroups(
+	file string,
+	opts ManagerOptions,
+	metrics managerMetrics,
+) ([]*Group, error) {
+	ruleGroups, errs := rulefmt.ParseFile(file)
+	if len(errs) > 0 {
+		multiErr := xerrors.NewMultiError()
+		for _, err := range errs {
+			multiErr = multiErr.Add(fmt.Errorf("%s: %v", file, err))
+		}
+
+		return nil, multiErr.FinalError()
+	}
+
+	groups := make([]*Group, 0, len(ruleGroups.Groups))
+	for _, ruleGroup := range ruleGroups.Groups {
+		interval := time.Duration(ruleGroup.Interval)
+		if interval <= 0 {
+			interval = opts.EvaluationInterval
+		}
+
+		rules := make([]Rule, 0, len(ruleGroup.Rules))
+		for _, r := range ruleGroup.Rules {
+			if r.Alert != "" {
+				rules = append(rules, NewAlertingRule(r.Alert, r.Expr,
+					time.Duration(r.For), r.Labels, r.Annotations))
+				continue
+			}
+
+			rules = append(rules, NewRecordingRule(r.Record, r.Expr, r.Labels))
+		}
+
+		groups = append(groups, newGroup(ruleGroup.Name, file, interval,
+			rules, opts, metrics))
+	}
+
+	return groups, nil
+}
+
+// Start starts evaluating each group of rules.
+func (m *Manager) Start() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.closed {
+		return errManagerClosed
+	}
+
+	if m.started {
+		return errManagerStarted
+	}
+
+	m.started = true
+	for _, group := range m.groups {
+		group.start()
+	}
+
+	return nil
+}
+
+// Close stops evaluating rules.
+func (m *Manager) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.closed {
+		return errManagerClosed
+	}
+
+	m.closed = true
+	if m.started {
+		for _, group := range m.groups {
+			group.stop()
+		}
+	}
+
+	return nil
+}
+
+// RuleGroups returns the groups of rules, ordered by file and then by their
+// order within the file.
+func (m *Manager) RuleGroups() []*Group {
+	return m.groups
+}
+
+// AlertingRules returns the alerting rules of every group.
+func (m *Manager) AlertingRules() []*AlertingRule {
+	var rules []*AlertingRule
+	for _, group := range m.groups {
+		for _, rule := range group.rules {
+			if alertingRule, ok := rule.(*AlertingRule); ok {
+				rules = append(rules, alertingRule)
+			}
+		}
+	}
+
+	return rules
+}
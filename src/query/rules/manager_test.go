@@ -0,0 +1,221 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package rules
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/cmd/services/m3coordinator/ingest"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/ts"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testRuleFile = `
+groups:
+  - name: requests
+    interval: 30s
+    rules:
+      - record: job:requests:sum
+        expr: sum by (job) (requests)
+        labels:
+          team: a
+      - alert: NoRequests
+        expr: job:requests:sum == 0
+        labels:
+          severity: page
+        annotations:
+          summary: "{{ $labels.job }} has no requests"
+  - name: defaults
+    rules:
+      - record: up:count
+        expr: count(up)
+`
+
+func writeTestRuleFile(t *testing.T, dir, name, contents string) string {
+	path := filepath.Join(dir, name)
+	require.NoError(t, ioutil.WriteFile(path, []byte(contents), 0644))
+	return path
+}
+
+func TestManagerLoadsRuleFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rules")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := writeTestRuleFile(t, dir, "requests.yml", testRuleFile)
+	manager, err := NewManager(ManagerOptions{
+		RuleFiles: []string{filepath.Join(dir, "*.yml")},
+		QueryFn:   newTestQueryFn(nil, nil),
+	})
+	require.NoError(t, err)
+
+	groups := manager.RuleGroups()
+	require.Equal(t, 2, len(groups))
+
+	assert.Equal(t, "requests", groups[0].Name())
+	assert.Equal(t, path, groups[0].File())
+	assert.Equal(t, 30*time.Second, groups[0].Interval())
+	require.Equal(t, 2, len(groups[0].Rules()))
+	assert.Equal(t, "job:requests:sum", groups[0].Rules()[0].Name())
+	assert.Equal(t, map[string]string{"team": "a"}, groups[0].Rules()[0].Labels())
+
+	alertingRules := manager.AlertingRules()
+	require.Equal(t, 1, len(alertingRules))
+	assert.Equal(t, "NoRequests", alertingRules[0].Name())
+	assert.Equal(t, "job:requests:sum == 0", alertingRules[0].Query())
+	assert.Equal(t, map[string]string{
+		"summary": "{{ $labels.job }} has no requests",
+	}, alertingRules[0].Annotations())
+
+	// Groups without an interval use the evaluation interval.
+	assert.Equal(t, "defaults", groups[1].Name())
+	assert.Equal(t, defaultEvaluationInterval, groups[1].Interval())
+
+	require.NoError(t, manager.Start())
+	assert.Equal(t, errManagerStarted, manager.Start())
+	require.NoError(t, manager.Close())
+	assert.Equal(t, errManagerClosed, manager.Close())
+}
+
+func TestManagerInvalidRuleFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rules")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := writeTestRuleFile(t, dir, "invalid.yml", `
+groups:
+  - name: invalid
+    rules:
+      - record: foo
+        expr: sum(
+`)
+	_, err = NewManager(ManagerOptions{
+		RuleFiles: []string{path},
+		QueryFn:   newTestQueryFn(nil, nil),
+	})
+	assert.Error(t, err)
+
+	_, err = NewManager(ManagerOptions{RuleFiles: []string{path}})
+	assert.Equal(t, errNoQueryFn, err)
+}
+
+func TestGroupEval(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dir, err := ioutil.TempDir("", "rules")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	var received int
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			received++
+		}))
+	defer server.Close()
+
+	notifier, err := NewNotifier(NotifierOptions{URLs: []string{server.URL}})
+	require.NoError(t, err)
+
+	now := time.Unix(1000, 0)
+	queryFn := func(_ context.Context, query string, t time.Time) (Vector, error) {
+		assert.Equal(t, now, t)
+		switch query {
+		case "sum by (job) (requests)":
+			return Vector{{Tags: newTestTags("job", "api"), Value: 0}}, nil
+		case "job:requests:sum == 0":
+			return Vector{{
+				Tags:  newTestTags("__name__", "job:requests:sum", "job", "api"),
+				Value: 0,
+			}}, nil
+		}
+
+		return nil, nil
+	}
+
+	writer := ingest.NewMockDownsamplerAndWriter(ctrl)
+	expectWrite := func(expected map[string]string, value float64) {
+		writer.EXPECT().
+			Write(gomock.Any(), gomock.Any(), gomock.Any(),
+				xtime.Millisecond, nil, ingest.WriteOptions{}).
+			DoAndReturn(func(
+				_ context.Context,
+				tags models.Tags,
+				datapoints ts.Datapoints,
+				_ xtime.Unit,
+				_ []byte,
+				_ ingest.WriteOptions,
+			) error {
+				assert.Equal(t, expected, tagsToMap(tags))
+				assert.Equal(t, ts.Datapoints{{Timestamp: now, Value: value}},
+					datapoints)
+				return nil
+			})
+	}
+
+	expectWrite(map[string]string{
+		"__name__": "job:requests:sum",
+		"job":      "api",
+		"team":     "a",
+	}, 0)
+	expectWrite(map[string]string{
+		"__name__":   "ALERTS",
+		"alertname":  "NoRequests",
+		"alertstate": "firing",
+		"job":        "api",
+		"severity":   "page",
+	}, 1)
+
+	writeTestRuleFile(t, dir, "requests.yml", testRuleFile)
+	manager, err := NewManager(ManagerOptions{
+		RuleFiles: []string{filepath.Join(dir, "*.yml")},
+		QueryFn:   queryFn,
+		Writer:    writer,
+		Notifier:  notifier,
+		NowFn:     func() time.Time { return now },
+	})
+	require.NoError(t, err)
+
+	group := manager.RuleGroups()[0]
+	group.Eval(context.Background(), now)
+	assert.Equal(t, now, group.LastEvaluation())
+	assert.Equal(t, 1, received)
+
+	alerts := manager.AlertingRules()[0].ActiveAlerts()
+	require.Equal(t, 1, len(alerts))
+	assert.Equal(t, map[string]string{"summary": "api has no requests"},
+		alerts[0].Annotations)
+	for _, rule := range group.Rules() {
+		assert.Equal(t, HealthGood, rule.Health())
+	}
+}
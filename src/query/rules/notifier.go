@@ -0,0 +1,165 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package rules
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	xerrors "github.com/m3db/m3/src/x/errors"
+)
+
+const (
+	alertmanagerAlertsPath = "/api/v1/alerts"
+
+	defaultNotifierTimeout = 10 * time.Second
+)
+
+var errNoAlertmanagerURLs = errors.New("notifier requires alertmanager URLs")
+
+// NotifierOptions are the options for an Alertmanager notifier.
+type NotifierOptions struct {
+	// URLs are the base URLs of the Alertmanager instances to notify.
+	URLs []string
+	// Timeout is the timeout for each notification, defaults to ten seconds.
+	Timeout time.Duration
+	// Client is the HTTP client used to notify Alertmanager, defaults to
+	// the default HTTP client.
+	Client *http.Client
+}
+
+// Notifier sends alerts to Alertmanager.
+type Notifier struct {
+	urls    []string
+	timeout time.Duration
+	client  *http.Client
+}
+
+// alertmanagerAlert is an alert in the format of the Alertmanager API.
+type alertmanagerAlert struct {
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations"`
+	StartsAt     time.Time         `json:"startsAt,omitempty"`
+	EndsAt       time.Time         `json:"endsAt,omitempty"`
+	GeneratorURL string            `json:"generatorURL,omitempty"`
+}
+
+// NewNotifier creates a new Alertmanager notifier.
+func NewNotifier(opts NotifierOptions) (*Notifier, error) {
+	if len(opts.URLs) == 0 {
+		return nil, errNoAlertmanagerURLs
+	}
+
+	urls := make([]string, 0, len(opts.URLs))
+	for _, u := range opts.URLs {
+		if _, err := url.ParseRequestURI(u); err != nil {
+			return nil, fmt.Errorf("invalid alertmanager URL %s: %v", u, err)
+		}
+
+		urls = append(urls, strings.TrimSuffix(u, "/")+alertmanagerAlertsPath)
+	}
+
+	if opts.Timeout <= 0 {
+		opts.Timeout = defaultNotifierTimeout
+	}
+
+	if opts.Client == nil {
+		opts.Client = http.DefaultClient
+	}
+
+	return &Notifier{
+		urls:    urls,
+		timeout: opts.Timeout,
+		client:  opts.Client,
+	}, nil
+}
+
+// Send sends the alerts to each Alertmanager.
+func (n *Notifier) Send(ctx context.Context, alerts []Alert) error {
+	if len(alerts) == 0 {
+		return nil
+	}
+
+	payload := make([]alertmanagerAlert, 0, len(alerts))
+	for _, alert := range alerts {
+		endsAt := alert.ValidUntil
+		if !alert.ResolvedAt.IsZero() {
+			endsAt = alert.ResolvedAt
+		}
+
+		payload = append(payload, alertmanagerAlert{
+			Labels:      tagsToMap(alert.Tags),
+			Annotations: alert.Annotations,
+			StartsAt:    alert.FiredAt,
+			EndsAt:      endsAt,
+		})
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	multiErr := xerrors.NewMultiError()
+	for _, u := range n.urls {
+		if err := n.post(ctx, u, body); err != nil {
+			multiErr = multiErr.Add(err)
+		}
+	}
+
+	return multiErr.FinalError()
+}
+
+func (n *Notifier) post(ctx context.Context, u string, body []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, n.timeout)
+	defer cancel()
+
+	req, err := http.NewRequest(http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := n.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+	// NB: drain the body so that the connection can be reused.
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("alertmanager %s returned status code %d",
+			u, resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,107 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package rules
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotifierSend(t *testing.T) {
+	var received [][]alertmanagerAlert
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Equal(t, alertmanagerAlertsPath, r.URL.Path)
+
+			var alerts []alertmanagerAlert
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&alerts))
+			received = append(received, alerts)
+		}))
+	defer server.Close()
+
+	notifier, err := NewNotifier(NotifierOptions{URLs: []string{server.URL + "/"}})
+	require.NoError(t, err)
+
+	firedAt := time.Unix(1000, 0).UTC()
+	alerts := []Alert{
+		{
+			State:       StateFiring,
+			Tags:        newTestTags("alertname", "HighLatency", "job", "api"),
+			Annotations: map[string]string{"summary": "high latency"},
+			FiredAt:     firedAt,
+			ValidUntil:  firedAt.Add(time.Hour),
+		},
+		{
+			State:      StateInactive,
+			Tags:       newTestTags("alertname", "HighLatency", "job", "db"),
+			FiredAt:    firedAt,
+			ResolvedAt: firedAt.Add(time.Minute),
+			ValidUntil: firedAt.Add(time.Hour),
+		},
+	}
+
+	require.NoError(t, notifier.Send(context.Background(), alerts))
+	require.NoError(t, notifier.Send(context.Background(), nil))
+
+	require.Equal(t, 1, len(received))
+	require.Equal(t, 2, len(received[0]))
+	assert.Equal(t, map[string]string{
+		"alertname": "HighLatency",
+		"job":       "api",
+	}, received[0][0].Labels)
+	assert.Equal(t, map[string]string{"summary": "high latency"},
+		received[0][0].Annotations)
+	assert.True(t, firedAt.Equal(received[0][0].StartsAt))
+	assert.True(t, firedAt.Add(time.Hour).Equal(received[0][0].EndsAt))
+	assert.True(t, firedAt.Add(time.Minute).Equal(received[0][1].EndsAt))
+}
+
+func TestNotifierSendError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+	defer server.Close()
+
+	notifier, err := NewNotifier(NotifierOptions{URLs: []string{server.URL}})
+	require.NoError(t, err)
+
+	err = notifier.Send(context.Background(), []Alert{
+		{State: StateFiring, Tags: newTestTags("alertname", "a")},
+	})
+	assert.Error(t, err)
+}
+
+func TestNewNotifierErrors(t *testing.T) {
+	_, err := NewNotifier(NotifierOptions{})
+	assert.Equal(t, errNoAlertmanagerURLs, err)
+
+	_, err = NewNotifier(NotifierOptions{URLs: []string{"not a url"}})
+	assert.Error(t, err)
+}
@@ -0,0 +1,138 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package rules
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/m3db/m3/src/query/block"
+	"github.com/m3db/m3/src/query/executor"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/parser/promql"
+	"github.com/m3db/m3/src/query/storage"
+)
+
+// instantQueryStep is the step of instant queries, which only evaluate a
+// single step.
+const instantQueryStep = time.Second
+
+// NewEngineQueryFn returns a query function that evaluates instant queries
+// with the given engine, each query is cancelled after the given timeout if
+// it is positive.
+func NewEngineQueryFn(
+	engine executor.Engine,
+	tagOpts models.TagOptions,
+	timeout time.Duration,
+) QueryFn {
+	return func(ctx context.Context, query string, t time.Time) (Vector, error) {
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+
+		engineOpts := engine.Options()
+		parser, err := promql.Parse(query, instantQueryStep, tagOpts,
+			engineOpts.ParseOptions())
+		if err != nil {
+			return nil, err
+		}
+
+		fetchOpts := storage.NewFetchOptions()
+		fetchOpts.Step = instantQueryStep
+		params := models.RequestParams{
+			Start:            t,
+			End:              t,
+			Now:              t,
+			Timeout:          timeout,
+			Step:             instantQueryStep,
+			Query:            query,
+			IncludeEnd:       true,
+			BlockType:        fetchOpts.BlockType,
+			FormatType:       models.FormatPromQL,
+			LookbackDuration: engineOpts.LookbackDuration(),
+		}
+
+		result, err := engine.ExecuteExpr(ctx, parser,
+			&executor.QueryOptions{}, fetchOpts, params)
+		if err != nil {
+			return nil, err
+		}
+
+		resultChan := result.ResultChan()
+		defer func() {
+			for range resultChan {
+				// NB: drain result channel in case of early termination.
+			}
+		}()
+
+		var vector Vector
+		for blkResult := range resultChan {
+			if err := blkResult.Err; err != nil {
+				return nil, err
+			}
+
+			vector, err = appendLastStep(vector, blkResult.Block)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return vector, nil
+	}
+}
+
+// appendLastStep appends the values of each series at the last step of the
+// block, skipping series without a value.
+func appendLastStep(vector Vector, b block.Block) (Vector, error) {
+	defer b.Close()
+
+	iter, err := b.StepIter()
+	if err != nil {
+		return nil, err
+	}
+
+	var values []float64
+	for iter.Next() {
+		// NB: copy the values since iterators may reuse them.
+		values = append(values[:0], iter.Current().Values()...)
+	}
+
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+
+	commonTags := b.Meta().Tags.Tags
+	for i, meta := range iter.SeriesMeta() {
+		if i >= len(values) || math.IsNaN(values[i]) {
+			continue
+		}
+
+		vector = append(vector, Sample{
+			Tags:  meta.Tags.AddTags(commonTags),
+			Value: values[i],
+		})
+	}
+
+	return vector, nil
+}
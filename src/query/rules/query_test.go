@@ -0,0 +1,80 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package rules
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/query/block"
+	"github.com/m3db/m3/src/query/executor"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/storage/mock"
+	"github.com/m3db/m3/src/query/test"
+	"github.com/m3db/m3/src/x/instrument"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEngineQueryFn(t *testing.T) {
+	store := mock.NewMockStorage()
+	engine := executor.NewEngine(executor.NewEngineOptions().
+		SetStore(store).
+		SetLookbackDuration(time.Minute).
+		SetGlobalEnforcer(nil).
+		SetInstrumentOptions(instrument.NewOptions()))
+
+	values, bounds := test.GenerateValuesAndBounds([][]float64{
+		{0, 1, 2},
+		{3, 4, math.NaN()},
+	}, &models.Bounds{
+		Start:    time.Unix(1000, 0),
+		Duration: 3 * time.Minute,
+		StepSize: time.Minute,
+	})
+	meta := block.Metadata{
+		Bounds:         bounds,
+		Tags:           models.NewTags(0, models.NewTagOptions()),
+		ResultMetadata: block.NewResultMetadata(),
+	}
+
+	b := test.NewBlockFromValuesWithMetaAndSeriesMeta(meta,
+		test.NewSeriesMeta("dummy", len(values)), values)
+	store.SetFetchBlocksResult(block.Result{Blocks: []block.Block{b}}, nil)
+
+	queryFn := NewEngineQueryFn(engine, models.NewTagOptions(), time.Minute)
+	vector, err := queryFn(context.Background(), "dummy0", bounds.Start)
+	require.NoError(t, err)
+
+	// Only series with a value at the last step are returned.
+	require.Equal(t, 1, len(vector))
+	assert.Equal(t, map[string]string{
+		"__name__": "dummy0",
+		"dummy0":   "dummy0",
+	}, tagsToMap(vector[0].Tags))
+	assert.Equal(t, 2.0, vector[0].Value)
+
+	_, err = queryFn(context.Background(), "sum(", bounds.Start)
+	assert.Error(t, err)
+}
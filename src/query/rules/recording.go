@@ -0,0 +1,130 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package rules
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/m3db/m3/src/query/models"
+)
+
+// RecordingRule records the result of a query as a new series.
+type RecordingRule struct {
+	ruleState
+
+	name   string
+	query  string
+	labels map[string]string
+}
+
+// NewRecordingRule creates a new recording rule which records the result of
+// the query as series with the given name and additional labels.
+func NewRecordingRule(
+	name string,
+	query string,
+	labels map[string]string,
+) *RecordingRule {
+	return &RecordingRule{
+		ruleState: newRuleState(),
+		name:      name,
+		query:     query,
+		labels:    labels,
+	}
+}
+
+// Name returns the name of the recorded series.
+func (r *RecordingRule) Name() string { return r.name }
+
+// Query returns the query the rule evaluates.
+func (r *RecordingRule) Query() string { return r.query }
+
+// Labels returns the labels added to the recorded series.
+func (r *RecordingRule) Labels() map[string]string { return r.labels }
+
+// Eval evaluates the rule at the given time, returning the series to record.
+func (r *RecordingRule) Eval(
+	ctx context.Context,
+	t time.Time,
+	queryFn QueryFn,
+) (Vector, error) {
+	vector, err := r.eval(ctx, t, queryFn)
+	r.setEvaluation(t, err)
+	return vector, err
+}
+
+func (r *RecordingRule) eval(
+	ctx context.Context,
+	t time.Time,
+	queryFn QueryFn,
+) (Vector, error) {
+	vector, err := queryFn(ctx, r.query, t)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]struct{}, len(vector))
+	for i, sample := range vector {
+		tags := withLabels(sample.Tags.SetName([]byte(r.name)), r.labels)
+		id := string(tags.ID())
+		if _, ok := seen[id]; ok {
+			return nil, fmt.Errorf("vector contains metrics with the same "+
+				"labelset after applying rule labels: %s", id)
+		}
+
+		seen[id] = struct{}{}
+		vector[i].Tags = tags
+	}
+
+	return vector, nil
+}
+
+// withLabels returns a copy of the tags with the given labels added, or
+// replacing the value of existing tags.
+func withLabels(tags models.Tags, labels map[string]string) models.Tags {
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	tags = tags.Clone()
+	for _, name := range names {
+		tags = tags.AddOrUpdateTag(models.Tag{
+			Name:  []byte(name),
+			Value: []byte(labels[name]),
+		})
+	}
+
+	return tags
+}
+
+// tagsToMap returns the tags as a map of tag names to values.
+func tagsToMap(tags models.Tags) map[string]string {
+	m := make(map[string]string, len(tags.Tags))
+	for _, tag := range tags.Tags {
+		m[string(tag.Name)] = string(tag.Value)
+	}
+
+	return m
+}
@@ -0,0 +1,103 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package rules
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/query/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestTags(nameValues ...string) models.Tags {
+	tags := models.NewTags(len(nameValues)/2, models.NewTagOptions())
+	for i := 0; i < len(nameValues); i += 2 {
+		tags = tags.AddTag(models.Tag{
+			Name:  []byte(nameValues[i]),
+			Value: []byte(nameValues[i+1]),
+		})
+	}
+
+	return tags
+}
+
+func newTestQueryFn(vector Vector, err error) QueryFn {
+	return func(context.Context, string, time.Time) (Vector, error) {
+		// NB: return a copy since rules may modify the vector.
+		return append(Vector(nil), vector...), err
+	}
+}
+
+func TestRecordingRuleEval(t *testing.T) {
+	rule := NewRecordingRule("job:requests:sum", "sum by (job) (requests)",
+		map[string]string{"team": "a"})
+	assert.Equal(t, HealthUnknown, rule.Health())
+
+	now := time.Now()
+	queryFn := newTestQueryFn(Vector{
+		{Tags: newTestTags("job", "foo"), Value: 1},
+		{Tags: newTestTags("__name__", "requests", "job", "bar"), Value: 2},
+	}, nil)
+
+	vector, err := rule.Eval(context.Background(), now, queryFn)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(vector))
+	assert.Equal(t, map[string]string{
+		"__name__": "job:requests:sum",
+		"job":      "foo",
+		"team":     "a",
+	}, tagsToMap(vector[0].Tags))
+	assert.Equal(t, 1.0, vector[0].Value)
+	assert.Equal(t, map[string]string{
+		"__name__": "job:requests:sum",
+		"job":      "bar",
+		"team":     "a",
+	}, tagsToMap(vector[1].Tags))
+	assert.Equal(t, 2.0, vector[1].Value)
+
+	assert.Equal(t, HealthGood, rule.Health())
+	assert.NoError(t, rule.LastError())
+	assert.Equal(t, now, rule.LastEvaluation())
+}
+
+func TestRecordingRuleEvalErrors(t *testing.T) {
+	rule := NewRecordingRule("job:requests:sum", "requests", nil)
+
+	queryErr := errors.New("query error")
+	_, err := rule.Eval(context.Background(), time.Now(),
+		newTestQueryFn(nil, queryErr))
+	assert.Equal(t, queryErr, err)
+	assert.Equal(t, HealthBad, rule.Health())
+	assert.Equal(t, queryErr, rule.LastError())
+
+	// Series that only differ by name collide once renamed.
+	_, err = rule.Eval(context.Background(), time.Now(), newTestQueryFn(Vector{
+		{Tags: newTestTags("__name__", "a", "job", "foo"), Value: 1},
+		{Tags: newTestTags("__name__", "b", "job", "foo"), Value: 2},
+	}, nil))
+	assert.Error(t, err)
+	assert.Equal(t, HealthBad, rule.Health())
+}
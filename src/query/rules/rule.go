@@ -0,0 +1,68 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package rules
+
+import (
+	"sync"
+	"time"
+)
+
+// ruleState tracks the result of the last evaluation of a rule.
+type ruleState struct {
+	mu             sync.RWMutex
+	health         RuleHealth
+	lastError      error
+	lastEvaluation time.Time
+}
+
+func newRuleState() ruleState {
+	return ruleState{health: HealthUnknown}
+}
+
+func (s *ruleState) setEvaluation(t time.Time, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lastEvaluation = t
+	s.lastError = err
+	s.health = HealthGood
+	if err != nil {
+		s.health = HealthBad
+	}
+}
+
+func (s *ruleState) Health() RuleHealth {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.health
+}
+
+func (s *ruleState) LastError() error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.lastError
+}
+
+func (s *ruleState) LastEvaluation() time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.lastEvaluation
+}
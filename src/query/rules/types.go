@@ -0,0 +1,74 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package rules
+
+import (
+	"context"
+	"time"
+
+	"github.com/m3db/m3/src/query/models"
+)
+
+// Sample is the value of a single series in the result of an instant query.
+type Sample struct {
+	// Tags are the tags of the series.
+	Tags models.Tags
+	// Value is the value of the series.
+	Value float64
+}
+
+// Vector is the result of an instant query.
+type Vector []Sample
+
+// QueryFn evaluates an instant query at the given time.
+type QueryFn func(ctx context.Context, query string, t time.Time) (Vector, error)
+
+// RuleHealth describes the health of a rule as of its last evaluation.
+type RuleHealth string
+
+const (
+	// HealthUnknown is the health of a rule that has not been evaluated.
+	HealthUnknown RuleHealth = "unknown"
+	// HealthGood is the health of a rule that was evaluated successfully.
+	HealthGood RuleHealth = "ok"
+	// HealthBad is the health of a rule that failed to evaluate.
+	HealthBad RuleHealth = "err"
+)
+
+// Rule is a recording or alerting rule.
+type Rule interface {
+	// Name returns the name of the rule, the name of the recorded series
+	// for recording rules and the alert name for alerting rules.
+	Name() string
+	// Query returns the query the rule evaluates.
+	Query() string
+	// Labels returns the labels added to the output of the rule.
+	Labels() map[string]string
+	// Eval evaluates the rule at the given time, returning the series to
+	// write to storage.
+	Eval(ctx context.Context, t time.Time, queryFn QueryFn) (Vector, error)
+	// Health returns the health of the rule as of its last evaluation.
+	Health() RuleHealth
+	// LastError returns the error of the last evaluation, if any.
+	LastError() error
+	// LastEvaluation returns the time of the last evaluation.
+	LastEvaluation() time.Time
+}
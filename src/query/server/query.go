@@ -44,6 +44,7 @@ import (
 	"github.com/m3db/m3/src/query/api/v1/handler/prometheus/handleroptions"
 	"github.com/m3db/m3/src/query/api/v1/httpd"
 	"github.com/m3db/m3/src/query/api/v1/options"
+	"github.com/m3db/m3/src/query/cache"
 	m3dbcluster "github.com/m3db/m3/src/query/cluster/m3db"
 	"github.com/m3db/m3/src/query/executor"
 	"github.com/m3db/m3/src/query/models"
@@ -51,6 +52,7 @@ import (
 	"github.com/m3db/m3/src/query/policy/filter"
 	"github.com/m3db/m3/src/query/pools"
 	tsdbRemote "github.com/m3db/m3/src/query/remote"
+	"github.com/m3db/m3/src/query/rules"
 	"github.com/m3db/m3/src/query/storage"
 	"github.com/m3db/m3/src/query/storage/fanout"
 	"github.com/m3db/m3/src/query/storage/m3"
@@ -126,6 +128,11 @@ type RunOptions struct {
 
 	// CustomPromQLParseFunction is a custom PromQL parsing function.
 	CustomPromQLParseFunction promql.ParseFn
+
+	// ResultsCacheBackend is a custom backend for the results cache, such as
+	// a memcached-compatible store, used instead of the in-memory backend
+	// when the results cache is configured.
+	ResultsCacheBackend cache.Backend
 }
 
 // Run runs the server programmatically given a filename for the configuration file.
@@ -329,6 +336,42 @@ func Run(runOpts RunOptions) {
 		logger.Fatal("unable to set up handler options", zap.Error(err))
 	}
 
+	if cacheCfg := cfg.ResultsCache; cacheCfg != nil {
+		backend := runOpts.ResultsCacheBackend
+		if backend == nil {
+			backend = cache.NewLRUBackend(cacheCfg.InMemory.MaxBytesOrDefault())
+		}
+
+		resultsCache, err := cache.NewResultsCache(cache.ResultsCacheOptions{
+			Backend:       backend,
+			SplitInterval: cacheCfg.SplitInterval,
+			MaxFreshness:  cacheCfg.MaxFreshness,
+			TagOptions:    tagOptions,
+			InstrumentOptions: instrumentOptions.SetMetricsScope(
+				instrumentOptions.MetricsScope().SubScope("results-cache")),
+		})
+		if err != nil {
+			logger.Fatal("unable to set up results cache", zap.Error(err))
+		}
+
+		handlerOptions = handlerOptions.SetResultsCache(resultsCache)
+	}
+
+	if rulesCfg := cfg.Rules; rulesCfg != nil {
+		ruleManager, err := newRuleManager(*rulesCfg, engine, tagOptions,
+			downsamplerAndWriter, instrumentOptions)
+		if err != nil {
+			logger.Fatal("unable to set up rule manager", zap.Error(err))
+		}
+
+		if err := ruleManager.Start(); err != nil {
+			logger.Fatal("unable to start rule manager", zap.Error(err))
+		}
+
+		defer ruleManager.Close()
+		handlerOptions = handlerOptions.SetRuleManager(ruleManager)
+	}
+
 	handler := httpd.NewHandler(handlerOptions, runOpts.CustomHandlers...)
 	if err := handler.RegisterRoutes(); err != nil {
 		logger.Fatal("unable to register routes", zap.Error(err))
@@ -994,3 +1037,41 @@ func newDownsamplerAndWriter(storage storage.Storage, downsampler downsample.Dow
 
 	return ingest.NewDownsamplerAndWriter(storage, downsampler, downAndWriteWorkerPool), nil
 }
+
+func newRuleManager(
+	cfg config.RulesConfiguration,
+	engine executor.Engine,
+	tagOptions models.TagOptions,
+	downsamplerAndWriter ingest.DownsamplerAndWriter,
+	instrumentOptions instrument.Options,
+) (*rules.Manager, error) {
+	var (
+		notifier    *rules.Notifier
+		resendDelay time.Duration
+	)
+
+	if amCfg := cfg.Alertmanager; amCfg != nil {
+		var err error
+		notifier, err = rules.NewNotifier(rules.NotifierOptions{
+			URLs:    amCfg.URLs,
+			Timeout: amCfg.Timeout,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		resendDelay = amCfg.ResendDelay
+	}
+
+	return rules.NewManager(rules.ManagerOptions{
+		RuleFiles:          cfg.RuleFiles,
+		EvaluationInterval: cfg.EvaluationInterval,
+		QueryFn: rules.NewEngineQueryFn(engine, tagOptions,
+			cfg.QueryTimeoutOrDefault()),
+		Writer:      downsamplerAndWriter,
+		Notifier:    notifier,
+		ResendDelay: resendDelay,
+		InstrumentOptions: instrumentOptions.SetMetricsScope(
+			instrumentOptions.MetricsScope().SubScope("rules")),
+	})
+}
@@ -23,6 +23,7 @@ package fanout
 import (
 	"bytes"
 	"context"
+	goerrors "errors"
 	"fmt"
 	"sync"
 
@@ -41,6 +42,9 @@ import (
 
 const initMetricMapSize = 10
 
+var errPaginatedMultipleStores = xerrors.NewInvalidParamsError(
+	goerrors.New("paginated queries are not supported across multiple stores"))
+
 type fanoutStorage struct {
 	stores             []storage.Storage
 	fetchFilter        filter.Storage
@@ -278,6 +282,10 @@ func (s *fanoutStorage) SearchSeries(
 	// behind an accumulator.
 	metricMap := make(map[string]models.Metric, initMetricMapSize)
 	stores := filterStores(s.stores, s.fetchFilter, query)
+	if len(stores) > 1 && options.Paginated {
+		return nil, errPaginatedMultipleStores
+	}
+
 	metadata := block.NewResultMetadata()
 	for _, store := range stores {
 		results, err := store.SearchSeries(ctx, query, options)
@@ -336,6 +344,10 @@ func (s *fanoutStorage) CompleteTags(
 		return stores[0].CompleteTags(ctx, query, options)
 	}
 
+	if len(stores) > 1 && options.Paginated {
+		return nil, errPaginatedMultipleStores
+	}
+
 	accumulatedTags := storage.NewCompleteTagsResultBuilder(query.CompleteNameOnly)
 	metadata := block.NewResultMetadata()
 	for _, store := range stores {
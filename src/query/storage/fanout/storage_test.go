@@ -38,6 +38,7 @@ import (
 	"github.com/m3db/m3/src/query/test/m3"
 	"github.com/m3db/m3/src/query/test/seriesiter"
 	"github.com/m3db/m3/src/query/ts"
+	xerrors "github.com/m3db/m3/src/x/errors"
 	"github.com/m3db/m3/src/x/ident"
 	"github.com/m3db/m3/src/x/instrument"
 	xtest "github.com/m3db/m3/src/x/test"
@@ -225,6 +226,29 @@ func TestCompleteTagsError(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestFanoutPaginatedMultipleStoresError(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	filter := func(_ storage.Query, _ storage.Storage) bool { return true }
+	tFilter := func(_ storage.CompleteTagsQuery, _ storage.Storage) bool { return true }
+	stores := []storage.Storage{
+		storage.NewMockStorage(ctrl),
+		storage.NewMockStorage(ctrl),
+	}
+	store := NewStorage(stores, filter, filter, tFilter, instrument.NewOptions())
+	opts := storage.NewFetchOptions()
+	opts.Paginated = true
+
+	_, err := store.SearchSeries(context.TODO(), &storage.FetchQuery{}, opts)
+	require.Error(t, err)
+	assert.True(t, xerrors.IsInvalidParams(err))
+
+	_, err = store.CompleteTags(context.TODO(), &storage.CompleteTagsQuery{}, opts)
+	require.Error(t, err)
+	assert.True(t, xerrors.IsInvalidParams(err))
+}
+
 // Error continuation tests below.
 func TestFanoutSearchErrorContinues(t *testing.T) {
 	ctrl := xtest.NewController(t)
@@ -23,6 +23,7 @@ package storage
 import (
 	"bytes"
 	"fmt"
+	"regexp/syntax"
 
 	"github.com/m3db/m3/src/dbnode/storage/index"
 	"github.com/m3db/m3/src/m3ninx/idx"
@@ -92,9 +93,37 @@ func FetchOptionsToM3Options(fetchOptions *FetchOptions, fetchQuery *FetchQuery)
 		Limit:          fetchOptions.Limit,
 		StartInclusive: fetchQuery.Start,
 		EndExclusive:   fetchQuery.End,
+		ValueFilter:    valueFilterToM3(fetchQuery.ValueFilter),
 	}
 }
 
+func valueFilterToM3(filter *ValueFilter) *index.ValueFilter {
+	if filter == nil {
+		return nil
+	}
+
+	var filterType index.ValueFilterType
+	switch filter.Type {
+	case ValueFilterEqual:
+		filterType = index.ValueFilterEqual
+	case ValueFilterNotEqual:
+		filterType = index.ValueFilterNotEqual
+	case ValueFilterGreater:
+		filterType = index.ValueFilterGreater
+	case ValueFilterGreaterEqual:
+		filterType = index.ValueFilterGreaterEqual
+	case ValueFilterLess:
+		filterType = index.ValueFilterLess
+	case ValueFilterLessEqual:
+		filterType = index.ValueFilterLessEqual
+	default:
+		// The filter only prunes results, an unknown filter prunes nothing.
+		return nil
+	}
+
+	return &index.ValueFilter{Type: filterType, Value: filter.Value}
+}
+
 func convertAggregateQueryType(completeNameOnly bool) index.AggregationType {
 	if completeNameOnly {
 		return index.AggregateTagNames
@@ -173,7 +202,11 @@ func matcherToQuery(matcher models.Matcher) (idx.Query, error) {
 		if bytes.Equal(dotStar, matcher.Value) {
 			query = idx.NewFieldQuery(matcher.Name)
 		} else {
-			query, err = idx.NewRegexpQuery(matcher.Name, matcher.Value)
+			var ok bool
+			query, ok, err = regexpTermsQuery(matcher.Name, matcher.Value)
+			if err == nil && !ok {
+				query, err = idx.NewRegexpQuery(matcher.Name, matcher.Value)
+			}
 		}
 		if err != nil {
 			return idx.Query{}, err
@@ -212,3 +245,69 @@ func matcherToQuery(matcher models.Matcher) (idx.Query, error) {
 		return idx.Query{}, fmt.Errorf("unsupported query type: %v", matcher)
 	}
 }
+
+// regexpTermsQuery returns a prefix or wildcard query equivalent to a regexp
+// built only from literals, '.' and '.*', which can be served by walking the
+// matching terms rather than evaluating the regexp against every term.
+// NB: '.' does not match a newline so these are only equivalent for tag
+// values without newlines, which tag values are not expected to contain.
+func regexpTermsQuery(name, value []byte) (idx.Query, bool, error) {
+	re, err := syntax.Parse(string(value), syntax.Perl)
+	if err != nil {
+		return idx.Query{}, false, nil
+	}
+
+	re = re.Simplify()
+	subs := []*syntax.Regexp{re}
+	if re.Op == syntax.OpConcat {
+		subs = re.Sub
+	}
+
+	var (
+		wildcard  []byte
+		prefix    []byte
+		wildcards int
+		isPrefix  bool
+	)
+	for i, sub := range subs {
+		switch {
+		case sub.Op == syntax.OpLiteral && sub.Flags&syntax.FoldCase == 0:
+			for _, r := range sub.Rune {
+				if r == '*' || r == '?' || r == '\\' {
+					wildcard = append(wildcard, '\\')
+				}
+				wildcard = append(wildcard, string(r)...)
+			}
+			if wildcards == 0 {
+				prefix = append(prefix, string(sub.Rune)...)
+			}
+		case isAnyChar(sub):
+			wildcard = append(wildcard, '?')
+			wildcards++
+		case sub.Op == syntax.OpStar && len(sub.Sub) == 1 && isAnyChar(sub.Sub[0]):
+			wildcard = append(wildcard, '*')
+			wildcards++
+			isPrefix = wildcards == 1 && i == len(subs)-1
+		default:
+			return idx.Query{}, false, nil
+		}
+	}
+
+	if wildcards == 0 {
+		return idx.Query{}, false, nil
+	}
+
+	if isPrefix {
+		return idx.NewPrefixQuery(name, prefix), true, nil
+	}
+
+	q, err := idx.NewWildcardQuery(name, wildcard)
+	if err != nil {
+		return idx.Query{}, false, err
+	}
+	return q, true, nil
+}
+
+func isAnyChar(re *syntax.Regexp) bool {
+	return re.Op == syntax.OpAnyChar || re.Op == syntax.OpAnyCharNotNL
+}
@@ -131,6 +131,72 @@ func TestFetchQueryToM3Query(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:     "regexp match -> prefix",
+			expected: "prefix(t1, v1)",
+			matchers: models.Matchers{
+				{
+					Type:  models.MatchRegexp,
+					Name:  []byte("t1"),
+					Value: []byte("v1.*"),
+				},
+			},
+		},
+		{
+			name:     "regexp match case insensitive",
+			expected: "regexp(t1, (?i)v1.*)",
+			matchers: models.Matchers{
+				{
+					Type:  models.MatchRegexp,
+					Name:  []byte("t1"),
+					Value: []byte("(?i)v1.*"),
+				},
+			},
+		},
+		{
+			name:     "regexp match -> wildcard",
+			expected: "wildcard(t1, v?1*x)",
+			matchers: models.Matchers{
+				{
+					Type:  models.MatchRegexp,
+					Name:  []byte("t1"),
+					Value: []byte("v.1.*x"),
+				},
+			},
+		},
+		{
+			name:     "regexp match -> escaped wildcard",
+			expected: `wildcard(t1, v\*?)`,
+			matchers: models.Matchers{
+				{
+					Type:  models.MatchRegexp,
+					Name:  []byte("t1"),
+					Value: []byte(`v\*.`),
+				},
+			},
+		},
+		{
+			name:     "regexp match with alternation",
+			expected: "regexp(t1, v1.*|v2)",
+			matchers: models.Matchers{
+				{
+					Type:  models.MatchRegexp,
+					Name:  []byte("t1"),
+					Value: []byte("v1.*|v2"),
+				},
+			},
+		},
+		{
+			name:     "regexp match negated -> prefix",
+			expected: "negation(prefix(t1, v1))",
+			matchers: models.Matchers{
+				{
+					Type:  models.MatchNotRegexp,
+					Name:  []byte("t1"),
+					Value: []byte("v1.*"),
+				},
+			},
+		},
 		{
 			name:     "regexp match negated",
 			expected: "negation(regexp(t1, v1))",
@@ -236,3 +302,23 @@ func TestFetchOptionsToAggregateOptions(t *testing.T) {
 	require.Equal(t, 1, len(aggOpts.FieldFilter))
 	require.Equal(t, "filter", string(aggOpts.FieldFilter[0]))
 }
+
+func TestFetchOptionsToM3OptionsValueFilter(t *testing.T) {
+	end := time.Now()
+	query := &FetchQuery{
+		Start: end.Add(-1 * time.Hour),
+		End:   end,
+	}
+
+	opts := FetchOptionsToM3Options(&FetchOptions{Limit: 7}, query)
+	assert.Equal(t, 7, opts.Limit)
+	assert.Nil(t, opts.ValueFilter)
+
+	query.ValueFilter = &ValueFilter{Type: ValueFilterLessEqual, Value: 3}
+	opts = FetchOptionsToM3Options(&FetchOptions{}, query)
+	require.NotNil(t, opts.ValueFilter)
+	assert.Equal(t, index.ValueFilter{
+		Type:  index.ValueFilterLessEqual,
+		Value: 3,
+	}, *opts.ValueFilter)
+}
@@ -0,0 +1,134 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package m3
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/m3db/m3/src/dbnode/storage/index"
+	xerrors "github.com/m3db/m3/src/x/errors"
+)
+
+const namespacePageTokenVersion = 1
+
+var (
+	errNamespacePageTokenBadVarint = errors.New("page token has bad varint")
+	errNamespacePageTokenBadLength = errors.New("page token has bad length")
+)
+
+// namespacePageToken is the page token of a paginated query. The cluster
+// namespaces are paged through one at a time in the order they are
+// configured, the token holds the namespace being paged through along with
+// the session page token to resume that namespace from.
+type namespacePageToken struct {
+	namespace string
+	pageToken index.PageToken
+}
+
+func decodeNamespacePageToken(token []byte) (namespacePageToken, error) {
+	version, n := binary.Uvarint(token)
+	if n <= 0 {
+		return namespacePageToken{}, errNamespacePageTokenBadVarint
+	}
+	if version != namespacePageTokenVersion {
+		return namespacePageToken{},
+			fmt.Errorf("page token has unknown version: %d", version)
+	}
+	token = token[n:]
+
+	length, n := binary.Uvarint(token)
+	if n <= 0 {
+		return namespacePageToken{}, errNamespacePageTokenBadVarint
+	}
+	token = token[n:]
+	if uint64(len(token)) < length {
+		return namespacePageToken{}, errNamespacePageTokenBadLength
+	}
+
+	result := namespacePageToken{namespace: string(token[:length])}
+	if rest := token[length:]; len(rest) > 0 {
+		result.pageToken = index.PageToken(rest)
+	}
+
+	return result, nil
+}
+
+func (t namespacePageToken) encode() []byte {
+	var (
+		buf     = make([]byte, 0, 2*binary.MaxVarintLen64+len(t.namespace)+len(t.pageToken))
+		scratch [binary.MaxVarintLen64]byte
+	)
+	n := binary.PutUvarint(scratch[:], namespacePageTokenVersion)
+	buf = append(buf, scratch[:n]...)
+	n = binary.PutUvarint(scratch[:], uint64(len(t.namespace)))
+	buf = append(buf, scratch[:n]...)
+	buf = append(buf, t.namespace...)
+	return append(buf, t.pageToken...)
+}
+
+// resumeNamespacePage returns the index of the namespace a paginated query
+// resumes from along with the session page token to resume it from, which
+// is nil for the first page of the namespace.
+func resumeNamespacePage(
+	namespaces ClusterNamespaces,
+	token []byte,
+) (int, index.PageToken, error) {
+	if len(token) == 0 {
+		return 0, nil, nil
+	}
+
+	decoded, err := decodeNamespacePageToken(token)
+	if err != nil {
+		return 0, nil, xerrors.NewInvalidParamsError(err)
+	}
+
+	for i, namespace := range namespaces {
+		if namespace.NamespaceID().String() == decoded.namespace {
+			return i, decoded.pageToken, nil
+		}
+	}
+
+	return 0, nil, xerrors.NewInvalidParamsError(fmt.Errorf(
+		"page token namespace not found: %s", decoded.namespace))
+}
+
+// nextNamespacePageToken returns the page token of the page following the
+// page of the namespace at idx that returned the session page token next,
+// it returns nil once every namespace has been exhausted.
+func nextNamespacePageToken(
+	namespaces ClusterNamespaces,
+	idx int,
+	next index.PageToken,
+) []byte {
+	if next == nil {
+		idx++
+		if idx >= len(namespaces) {
+			return nil
+		}
+	}
+
+	return namespacePageToken{
+		namespace: namespaces[idx].NamespaceID().String(),
+		pageToken: next,
+	}.encode()
+}
@@ -0,0 +1,124 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package m3
+
+import (
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/client"
+	"github.com/m3db/m3/src/dbnode/storage/index"
+	xerrors "github.com/m3db/m3/src/x/errors"
+	"github.com/m3db/m3/src/x/ident"
+	xtest "github.com/m3db/m3/src/x/test"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestPageNamespaces(
+	t *testing.T,
+	ctrl *gomock.Controller,
+) ClusterNamespaces {
+	clusters, err := NewClusters(UnaggregatedClusterNamespaceDefinition{
+		NamespaceID: ident.StringID("metrics_unaggregated"),
+		Session:     client.NewMockSession(ctrl),
+		Retention:   test1MonthRetention,
+	}, AggregatedClusterNamespaceDefinition{
+		NamespaceID: ident.StringID("metrics_aggregated_1m:30d"),
+		Session:     client.NewMockSession(ctrl),
+		Retention:   test1MonthRetention,
+		Resolution:  time.Minute,
+	})
+	require.NoError(t, err)
+	return clusters.ClusterNamespaces()
+}
+
+func TestNamespacePageTokenRoundTrip(t *testing.T) {
+	for _, token := range []namespacePageToken{
+		{namespace: "foo"},
+		{namespace: "foo", pageToken: index.PageToken("bar")},
+	} {
+		decoded, err := decodeNamespacePageToken(token.encode())
+		require.NoError(t, err)
+		assert.Equal(t, token, decoded)
+	}
+}
+
+func TestNamespacePageTokenInvalid(t *testing.T) {
+	for _, token := range [][]byte{
+		{0xff},
+		{0x2, 0x0},
+		{0x1, 0x5, 'f', 'o'},
+	} {
+		_, err := decodeNamespacePageToken(token)
+		assert.Error(t, err)
+	}
+}
+
+func TestResumeNamespacePage(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+	namespaces := newTestPageNamespaces(t, ctrl)
+
+	idx, pageToken, err := resumeNamespacePage(namespaces, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 0, idx)
+	assert.Nil(t, pageToken)
+
+	token := namespacePageToken{
+		namespace: "metrics_aggregated_1m:30d",
+		pageToken: index.PageToken("bar"),
+	}
+	idx, pageToken, err = resumeNamespacePage(namespaces, token.encode())
+	require.NoError(t, err)
+	assert.Equal(t, 1, idx)
+	assert.Equal(t, index.PageToken("bar"), pageToken)
+
+	token.namespace = "unknown"
+	_, _, err = resumeNamespacePage(namespaces, token.encode())
+	require.Error(t, err)
+	assert.True(t, xerrors.IsInvalidParams(err))
+}
+
+func TestNextNamespacePageToken(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+	namespaces := newTestPageNamespaces(t, ctrl)
+
+	next, err := decodeNamespacePageToken(
+		nextNamespacePageToken(namespaces, 0, index.PageToken("bar")))
+	require.NoError(t, err)
+	assert.Equal(t, namespacePageToken{
+		namespace: "metrics_unaggregated",
+		pageToken: index.PageToken("bar"),
+	}, next)
+
+	next, err = decodeNamespacePageToken(
+		nextNamespacePageToken(namespaces, 0, nil))
+	require.NoError(t, err)
+	assert.Equal(t, namespacePageToken{
+		namespace: "metrics_aggregated_1m:30d",
+	}, next)
+
+	assert.Nil(t, nextNamespacePageToken(namespaces, 1, nil))
+}
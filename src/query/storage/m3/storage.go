@@ -28,6 +28,7 @@ import (
 	"time"
 
 	"github.com/m3db/m3/src/dbnode/client"
+	"github.com/m3db/m3/src/dbnode/storage/index"
 	"github.com/m3db/m3/src/query/block"
 	"github.com/m3db/m3/src/query/cost"
 	"github.com/m3db/m3/src/query/errors"
@@ -419,6 +420,11 @@ func (s *m3storage) CompleteTags(
 		return nil, errNoNamespacesConfigured
 	}
 
+	if options.Paginated {
+		return s.completeTagsPage(namespaces, query, m3query, aggOpts,
+			options.PageToken)
+	}
+
 	var mu sync.Mutex
 	aggIterators := make([]client.AggregatedTagsIterator, 0, len(namespaces))
 	defer func() {
@@ -447,26 +453,8 @@ func (s *m3storage) CompleteTags(
 			aggIterators = append(aggIterators, aggTagIter)
 			mu.Unlock()
 
-			completedTags := make([]storage.CompletedTag, 0, aggTagIter.Remaining())
-			for aggTagIter.Next() {
-				name, values := aggTagIter.Current()
-				tagValues := make([][]byte, 0, values.Remaining())
-				for values.Next() {
-					tagValues = append(tagValues, values.Current().Bytes())
-				}
-
-				if err := values.Err(); err != nil {
-					multiErr.add(err)
-					return
-				}
-
-				completedTags = append(completedTags, storage.CompletedTag{
-					Name:   name.Bytes(),
-					Values: tagValues,
-				})
-			}
-
-			if err := aggTagIter.Err(); err != nil {
+			completedTags, err := completedTagsFromIterator(aggTagIter)
+			if err != nil {
 				multiErr.add(err)
 				return
 			}
@@ -494,6 +482,78 @@ func (s *m3storage) CompleteTags(
 	return &built, nil
 }
 
+// completeTagsPage returns a single page of a paginated tag completion, each
+// page is read from a single namespace.
+func (s *m3storage) completeTagsPage(
+	namespaces ClusterNamespaces,
+	query *storage.CompleteTagsQuery,
+	m3query index.Query,
+	aggOpts index.AggregationOptions,
+	token []byte,
+) (*storage.CompleteTagsResult, error) {
+	idx, pageToken, err := resumeNamespacePage(namespaces, token)
+	if err != nil {
+		return nil, err
+	}
+
+	namespace := namespaces[idx]
+	aggOpts.PageToken = pageToken
+	aggTagIter, next, err := namespace.Session().AggregatePage(
+		namespace.NamespaceID(), m3query, aggOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	defer aggTagIter.Finalize()
+	completedTags, err := completedTagsFromIterator(aggTagIter)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := block.NewResultMetadata()
+	metadata.NextPageToken = nextNamespacePageToken(namespaces, idx, next)
+	metadata.Exhaustive = metadata.NextPageToken == nil
+	accumulatedTags := storage.NewCompleteTagsResultBuilder(query.CompleteNameOnly)
+	if err := accumulatedTags.Add(&storage.CompleteTagsResult{
+		CompleteNameOnly: query.CompleteNameOnly,
+		CompletedTags:    completedTags,
+		Metadata:         metadata,
+	}); err != nil {
+		return nil, err
+	}
+
+	built := accumulatedTags.Build()
+	return &built, nil
+}
+
+func completedTagsFromIterator(
+	aggTagIter client.AggregatedTagsIterator,
+) ([]storage.CompletedTag, error) {
+	completedTags := make([]storage.CompletedTag, 0, aggTagIter.Remaining())
+	for aggTagIter.Next() {
+		name, values := aggTagIter.Current()
+		tagValues := make([][]byte, 0, values.Remaining())
+		for values.Next() {
+			tagValues = append(tagValues, values.Current().Bytes())
+		}
+
+		if err := values.Err(); err != nil {
+			return nil, err
+		}
+
+		completedTags = append(completedTags, storage.CompletedTag{
+			Name:   name.Bytes(),
+			Values: tagValues,
+		})
+	}
+
+	if err := aggTagIter.Err(); err != nil {
+		return nil, err
+	}
+
+	return completedTags, nil
+}
+
 func (s *m3storage) SearchCompressed(
 	ctx context.Context,
 	query *storage.FetchQuery,
@@ -537,6 +597,11 @@ func (s *m3storage) SearchCompressed(
 		return tagResult, noop, errNoNamespacesConfigured
 	}
 
+	if options.Paginated {
+		return s.searchCompressedPage(namespaces, m3query, m3opts,
+			options.PageToken)
+	}
+
 	wg.Add(len(namespaces))
 	for _, namespace := range namespaces {
 		namespace := namespace // Capture var
@@ -557,6 +622,35 @@ func (s *m3storage) SearchCompressed(
 	return tagResult, result.Close, err
 }
 
+// searchCompressedPage returns a single page of a paginated search, each
+// page is read from a single namespace.
+func (s *m3storage) searchCompressedPage(
+	namespaces ClusterNamespaces,
+	m3query index.Query,
+	m3opts index.QueryOptions,
+	token []byte,
+) (TagResult, Cleanup, error) {
+	idx, pageToken, err := resumeNamespacePage(namespaces, token)
+	if err != nil {
+		return TagResult{Metadata: block.NewResultMetadata()}, noop, err
+	}
+
+	var (
+		namespace = namespaces[idx]
+		result    = NewMultiFetchTagsResult()
+	)
+	m3opts.PageToken = pageToken
+	iter, next, err := namespace.Session().FetchTaggedIDsPage(
+		namespace.NamespaceID(), m3query, m3opts)
+	meta := block.NewResultMetadata()
+	meta.NextPageToken = nextNamespacePageToken(namespaces, idx, next)
+	meta.Exhaustive = meta.NextPageToken == nil
+	result.Add(iter, meta, err)
+
+	tagResult, err := result.FinalResult()
+	return tagResult, result.Close, err
+}
+
 func (s *m3storage) Write(
 	ctx context.Context,
 	query *storage.WriteQuery,
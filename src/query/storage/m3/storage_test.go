@@ -30,11 +30,13 @@ import (
 
 	"github.com/m3db/m3/src/dbnode/client"
 	"github.com/m3db/m3/src/dbnode/encoding"
+	"github.com/m3db/m3/src/dbnode/storage/index"
 	"github.com/m3db/m3/src/query/models"
 	"github.com/m3db/m3/src/query/storage"
 	"github.com/m3db/m3/src/query/test/seriesiter"
 	"github.com/m3db/m3/src/query/ts"
 	"github.com/m3db/m3/src/query/ts/m3db"
+	xerrors "github.com/m3db/m3/src/x/errors"
 	"github.com/m3db/m3/src/x/ident"
 	"github.com/m3db/m3/src/x/instrument"
 	"github.com/m3db/m3/src/x/sync"
@@ -562,6 +564,88 @@ func TestLocalSearchSuccess(t *testing.T) {
 	}
 }
 
+func TestLocalSearchPaginated(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+	store, sessions := setup(t, ctrl)
+
+	iter := client.NewMockTaggedIDsIterator(ctrl)
+	gomock.InOrder(
+		iter.EXPECT().Next().Return(true),
+		iter.EXPECT().Current().Return(
+			ident.StringID("metrics_unaggregated"),
+			ident.StringID("foo"),
+			ident.NewTagsIterator(ident.NewTags(
+				ident.Tag{Name: ident.StringID("qux"), Value: ident.StringID("qaz")})),
+		),
+		iter.EXPECT().Next().Return(false),
+		iter.EXPECT().Err().Return(nil),
+		iter.EXPECT().Finalize(),
+	)
+
+	emptyIter := client.NewMockTaggedIDsIterator(ctrl)
+	gomock.InOrder(
+		emptyIter.EXPECT().Next().Return(false),
+		emptyIter.EXPECT().Err().Return(nil),
+		emptyIter.EXPECT().Finalize(),
+	)
+
+	session := sessions.unaggregated1MonthRetention
+	gomock.InOrder(
+		session.EXPECT().
+			FetchTaggedIDsPage(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(
+				_ ident.ID, _ index.Query, opts index.QueryOptions,
+			) (client.TaggedIDsIterator, index.PageToken, error) {
+				assert.Nil(t, opts.PageToken)
+				return iter, index.PageToken("next"), nil
+			}),
+		session.EXPECT().
+			FetchTaggedIDsPage(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(
+				_ ident.ID, _ index.Query, opts index.QueryOptions,
+			) (client.TaggedIDsIterator, index.PageToken, error) {
+				assert.Equal(t, index.PageToken("next"), opts.PageToken)
+				return emptyIter, nil, nil
+			}),
+	)
+
+	opts := buildFetchOpts()
+	opts.Paginated = true
+	result, err := store.SearchSeries(context.TODO(), newFetchReq(), opts)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(result.Metrics))
+	assert.Equal(t, []byte("foo"), result.Metrics[0].ID)
+	assert.False(t, result.Metadata.Exhaustive)
+	require.NotNil(t, result.Metadata.NextPageToken)
+
+	// The unaggregated namespace is exhausted by the second page so the next
+	// page is the first page of the following namespace.
+	opts.PageToken = result.Metadata.NextPageToken
+	result, err = store.SearchSeries(context.TODO(), newFetchReq(), opts)
+	require.NoError(t, err)
+	require.Equal(t, 0, len(result.Metrics))
+	assert.False(t, result.Metadata.Exhaustive)
+
+	next, err := decodeNamespacePageToken(result.Metadata.NextPageToken)
+	require.NoError(t, err)
+	assert.Equal(t, "metrics_aggregated_1m:30d", next.namespace)
+	assert.Nil(t, next.pageToken)
+}
+
+func TestLocalSearchPaginatedInvalidPageToken(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+	store, _ := setup(t, ctrl)
+
+	opts := buildFetchOpts()
+	opts.Paginated = true
+	opts.PageToken = namespacePageToken{namespace: "unknown"}.encode()
+	_, err := store.SearchSeries(context.TODO(), newFetchReq(), opts)
+	require.Error(t, err)
+	assert.True(t, xerrors.IsInvalidParams(err))
+}
+
 func newTestIteratorPools(ctrl *gomock.Controller) encoding.IteratorPools {
 	pools := encoding.NewMockIteratorPools(ctrl)
 
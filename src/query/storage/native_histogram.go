@@ -0,0 +1,193 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"math"
+	"sort"
+	"strconv"
+
+	dts "github.com/m3db/m3/src/dbnode/ts"
+	"github.com/m3db/m3/src/query/generated/proto/prompb"
+	"github.com/m3db/m3/src/query/models"
+)
+
+var (
+	// NativeHistogramTagName is the name of the tag added to series that
+	// hold native histogram datapoints rather than float datapoints.
+	NativeHistogramTagName = []byte("__m3_histogram__")
+	// NativeHistogramTagValue is the value of the native histogram tag.
+	NativeHistogramTagValue = []byte("native")
+)
+
+// NativeHistogramSeries is a series of native histogram datapoints.
+type NativeHistogramSeries struct {
+	Tags       models.Tags
+	Datapoints []dts.HistogramDatapoint
+}
+
+// IsNativeHistogramTag returns whether a tag marks a series as a native
+// histogram series.
+func IsNativeHistogramTag(name, value []byte) bool {
+	return string(name) == string(NativeHistogramTagName) &&
+		string(value) == string(NativeHistogramTagValue)
+}
+
+// IsNativeHistogramSeries returns whether the tags of a series mark it as a
+// native histogram series.
+func IsNativeHistogramSeries(tags models.Tags) bool {
+	value, ok := tags.Get(NativeHistogramTagName)
+	return ok && string(value) == string(NativeHistogramTagValue)
+}
+
+// FormatBucketUpperBound formats the upper bound of a bucket the same way
+// Prometheus formats the value of bucket tags.
+func FormatBucketUpperBound(upperBound float64) string {
+	return strconv.FormatFloat(upperBound, 'f', -1, 64)
+}
+
+type promBucketGroup struct {
+	tags    models.Tags
+	members []int
+	buckets map[int64][]dts.HistogramBucket
+	invalid bool
+}
+
+// FoldPromBucketSeries folds Prometheus bucket series, which are series with
+// a bucket tag, into native histogram series. Bucket series that share the
+// same tags other than the bucket tag are folded into a single series with the
+// bucket tag removed and the native histogram tag added, each timestamp of the
+// folded series holds a histogram made up of the buckets at that timestamp.
+// Series that are not bucket series, or whose buckets do not make up valid
+// histograms, are returned unmodified.
+func FoldPromBucketSeries(
+	timeseries []prompb.TimeSeries,
+	tagOptions models.TagOptions,
+) ([]NativeHistogramSeries, []prompb.TimeSeries) {
+	var (
+		groups      = make(map[string]*promBucketGroup)
+		groupsOrder = make([]*promBucketGroup, 0, len(timeseries))
+		unfolded    = make([]prompb.TimeSeries, 0, len(timeseries))
+		excludeTags = [][]byte{tagOptions.BucketName()}
+	)
+	for i, series := range timeseries {
+		tags := PromLabelsToM3Tags(series.Labels, tagOptions)
+		value, ok := tags.Bucket()
+		if !ok {
+			unfolded = append(unfolded, series)
+			continue
+		}
+
+		upperBound, err := strconv.ParseFloat(string(value), 64)
+		if err != nil {
+			unfolded = append(unfolded, series)
+			continue
+		}
+
+		tags = tags.TagsWithoutKeys(excludeTags).AddTag(models.Tag{
+			Name:  NativeHistogramTagName,
+			Value: NativeHistogramTagValue,
+		})
+		id := string(tags.ID())
+		group, ok := groups[id]
+		if !ok {
+			group = &promBucketGroup{
+				tags:    tags,
+				buckets: make(map[int64][]dts.HistogramBucket),
+			}
+			groups[id] = group
+			groupsOrder = append(groupsOrder, group)
+		}
+
+		group.members = append(group.members, i)
+		for _, sample := range series.Samples {
+			count, ok := bucketCount(sample.Value)
+			if !ok {
+				// Counts that cannot be represented as a histogram bucket,
+				// such as staleness markers, make the group unfoldable.
+				group.invalid = true
+				break
+			}
+			group.buckets[sample.Timestamp] = append(
+				group.buckets[sample.Timestamp], dts.HistogramBucket{
+					UpperBound: upperBound,
+					Count:      count,
+				})
+		}
+	}
+
+	folded := make([]NativeHistogramSeries, 0, len(groupsOrder))
+	for _, group := range groupsOrder {
+		series, ok := group.fold()
+		if !ok {
+			for _, idx := range group.members {
+				unfolded = append(unfolded, timeseries[idx])
+			}
+			continue
+		}
+		folded = append(folded, series)
+	}
+
+	return folded, unfolded
+}
+
+func (g *promBucketGroup) fold() (NativeHistogramSeries, bool) {
+	if g.invalid {
+		return NativeHistogramSeries{}, false
+	}
+
+	timestamps := make([]int64, 0, len(g.buckets))
+	for timestamp := range g.buckets {
+		timestamps = append(timestamps, timestamp)
+	}
+	sort.Slice(timestamps, func(i, j int) bool {
+		return timestamps[i] < timestamps[j]
+	})
+
+	datapoints := make([]dts.HistogramDatapoint, 0, len(timestamps))
+	for _, timestamp := range timestamps {
+		buckets := g.buckets[timestamp]
+		sort.Slice(buckets, func(i, j int) bool {
+			return buckets[i].UpperBound < buckets[j].UpperBound
+		})
+
+		h := dts.Histogram{Buckets: buckets}
+		if err := h.Validate(); err != nil {
+			return NativeHistogramSeries{}, false
+		}
+
+		datapoints = append(datapoints, dts.NewHistogramDatapoint(
+			PromTimestampToTime(timestamp), h))
+	}
+
+	return NativeHistogramSeries{
+		Tags:       g.tags,
+		Datapoints: datapoints,
+	}, true
+}
+
+func bucketCount(value float64) (uint64, bool) {
+	if value < 0 || value >= math.MaxUint64 || value != math.Trunc(value) {
+		// NB: NaN values also fail the truncation equality check.
+		return 0, false
+	}
+	return uint64(value), true
+}
@@ -0,0 +1,116 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"math"
+	"testing"
+
+	dts "github.com/m3db/m3/src/dbnode/ts"
+	"github.com/m3db/m3/src/query/generated/proto/prompb"
+	"github.com/m3db/m3/src/query/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestBucketSeries(
+	name, le string,
+	samples ...prompb.Sample,
+) prompb.TimeSeries {
+	return prompb.TimeSeries{
+		Labels: []prompb.Label{
+			{Name: promDefaultName, Value: []byte(name)},
+			{Name: []byte("foo"), Value: []byte("bar")},
+			{Name: promDefaultBucketName, Value: []byte(le)},
+		},
+		Samples: samples,
+	}
+}
+
+func TestFoldPromBucketSeries(t *testing.T) {
+	var (
+		opts   = models.NewTagOptions()
+		series = []prompb.TimeSeries{
+			newTestBucketSeries("a_bucket", "+Inf",
+				prompb.Sample{Timestamp: 2000, Value: 7},
+				prompb.Sample{Timestamp: 1000, Value: 5}),
+			newTestBucketSeries("a_bucket", "0.5",
+				prompb.Sample{Timestamp: 1000, Value: 3},
+				prompb.Sample{Timestamp: 2000, Value: 4}),
+			{
+				Labels: []prompb.Label{
+					{Name: promDefaultName, Value: []byte("a_count")},
+				},
+				Samples: []prompb.Sample{{Timestamp: 1000, Value: 5}},
+			},
+			// Staleness markers can not be folded.
+			newTestBucketSeries("b_bucket", "1",
+				prompb.Sample{Timestamp: 1000, Value: math.NaN()}),
+			// Non cumulative buckets can not be folded.
+			newTestBucketSeries("c_bucket", "1",
+				prompb.Sample{Timestamp: 1000, Value: 2}),
+			newTestBucketSeries("c_bucket", "+Inf",
+				prompb.Sample{Timestamp: 1000, Value: 1}),
+		}
+	)
+
+	folded, unfolded := FoldPromBucketSeries(series, opts)
+	require.Equal(t, 1, len(folded))
+	require.Equal(t, []prompb.TimeSeries{
+		series[2], series[3], series[4], series[5],
+	}, unfolded)
+
+	tags := folded[0].Tags
+	_, hasBucket := tags.Bucket()
+	assert.False(t, hasBucket)
+	value, ok := tags.Get(NativeHistogramTagName)
+	require.True(t, ok)
+	assert.True(t, IsNativeHistogramTag(NativeHistogramTagName, value))
+	name, ok := tags.Name()
+	require.True(t, ok)
+	assert.Equal(t, "a_bucket", string(name))
+
+	expected := []dts.HistogramDatapoint{
+		dts.NewHistogramDatapoint(PromTimestampToTime(1000), dts.Histogram{
+			Buckets: []dts.HistogramBucket{
+				{UpperBound: 0.5, Count: 3},
+				{UpperBound: math.Inf(1), Count: 5},
+			},
+		}),
+		dts.NewHistogramDatapoint(PromTimestampToTime(2000), dts.Histogram{
+			Buckets: []dts.HistogramBucket{
+				{UpperBound: 0.5, Count: 4},
+				{UpperBound: math.Inf(1), Count: 7},
+			},
+		}),
+	}
+	require.Equal(t, len(expected), len(folded[0].Datapoints))
+	for i, dp := range folded[0].Datapoints {
+		assert.True(t, expected[i].Equal(dp))
+	}
+}
+
+func TestFormatBucketUpperBound(t *testing.T) {
+	assert.Equal(t, "+Inf", FormatBucketUpperBound(math.Inf(1)))
+	assert.Equal(t, "0.25", FormatBucketUpperBound(0.25))
+	assert.Equal(t, "10", FormatBucketUpperBound(10))
+}
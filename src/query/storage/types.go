@@ -100,6 +100,36 @@ type FetchQuery struct {
 	Start       time.Time       `json:"start"`
 	End         time.Time       `json:"end"`
 	Interval    time.Duration   `json:"interval"`
+
+	// ValueFilter, when set, allows storages to skip series whose values
+	// cannot satisfy it, it does not replace filtering the fetched values.
+	ValueFilter *ValueFilter `json:"valueFilter,omitempty"`
+}
+
+// ValueFilterType is the comparison a value filter applies.
+type ValueFilterType uint8
+
+const (
+	// ValueFilterEqual matches values equal to the filter value.
+	ValueFilterEqual ValueFilterType = iota
+	// ValueFilterNotEqual matches values not equal to the filter value.
+	ValueFilterNotEqual
+	// ValueFilterGreater matches values greater than the filter value.
+	ValueFilterGreater
+	// ValueFilterGreaterEqual matches values greater than or equal to the
+	// filter value.
+	ValueFilterGreaterEqual
+	// ValueFilterLess matches values less than the filter value.
+	ValueFilterLess
+	// ValueFilterLessEqual matches values less than or equal to the filter
+	// value.
+	ValueFilterLessEqual
+)
+
+// ValueFilter compares the values of a series with a scalar.
+type ValueFilter struct {
+	Type  ValueFilterType `json:"type"`
+	Value float64         `json:"value"`
 }
 
 // FetchOptions represents the options for fetch query.
@@ -127,6 +157,13 @@ type FetchOptions struct {
 	// IncludeResolution if set, appends resolution information to fetch results.
 	// Currently only used for graphite queries.
 	IncludeResolution bool
+	// Paginated if set returns a single page of at most Limit series per
+	// storage node for searches and tag completions, resumed from PageToken
+	// or from the first page if PageToken is not set.
+	Paginated bool
+	// PageToken is the token returned in the result metadata of the previous
+	// page of a paginated search or tag completion.
+	PageToken []byte
 }
 
 // FanoutOptions describes which namespaces should be fanned out to for
@@ -155,6 +155,19 @@ func (s *AsyncSession) FetchTagged(namespace ident.ID, q index.Query,
 	return s.session.FetchTagged(namespace, q, opts)
 }
 
+// FetchTaggedPage resolves the provided query to known IDs, and fetches the
+// data for a single page of them.
+func (s *AsyncSession) FetchTaggedPage(namespace ident.ID, q index.Query,
+	opts index.QueryOptions) (encoding.SeriesIterators, index.PageToken, error) {
+	s.RLock()
+	defer s.RUnlock()
+	if s.err != nil {
+		return nil, nil, s.err
+	}
+
+	return s.session.FetchTaggedPage(namespace, q, opts)
+}
+
 // FetchTaggedIDs resolves the provided query to known IDs.
 func (s *AsyncSession) FetchTaggedIDs(namespace ident.ID, q index.Query,
 	opts index.QueryOptions) (client.TaggedIDsIterator, bool, error) {
@@ -167,6 +180,18 @@ func (s *AsyncSession) FetchTaggedIDs(namespace ident.ID, q index.Query,
 	return s.session.FetchTaggedIDs(namespace, q, opts)
 }
 
+// FetchTaggedIDsPage resolves the provided query to a single page of known IDs.
+func (s *AsyncSession) FetchTaggedIDsPage(namespace ident.ID, q index.Query,
+	opts index.QueryOptions) (client.TaggedIDsIterator, index.PageToken, error) {
+	s.RLock()
+	defer s.RUnlock()
+	if s.err != nil {
+		return nil, nil, s.err
+	}
+
+	return s.session.FetchTaggedIDsPage(namespace, q, opts)
+}
+
 // Aggregate aggregates values from the database for the given set of constraints.
 func (s *AsyncSession) Aggregate(namespace ident.ID, q index.Query, opts index.AggregationOptions) (client.AggregatedTagsIterator, bool, error) {
 	s.RLock()
@@ -178,6 +203,30 @@ func (s *AsyncSession) Aggregate(namespace ident.ID, q index.Query, opts index.A
 	return s.session.Aggregate(namespace, q, opts)
 }
 
+// AggregatePage aggregates values from a single page of the series matching
+// the given set of constraints.
+func (s *AsyncSession) AggregatePage(namespace ident.ID, q index.Query, opts index.AggregationOptions) (client.AggregatedTagsIterator, index.PageToken, error) {
+	s.RLock()
+	defer s.RUnlock()
+	if s.err != nil {
+		return nil, nil, s.err
+	}
+
+	return s.session.AggregatePage(namespace, q, opts)
+}
+
+// IndexCardinality returns the number of distinct values and series of the
+// tag names and tags indexed by the namespace, merged across all hosts.
+func (s *AsyncSession) IndexCardinality(namespace ident.ID, opts index.CardinalityOptions) (index.CardinalityResults, error) {
+	s.RLock()
+	defer s.RUnlock()
+	if s.err != nil {
+		return index.CardinalityResults{}, s.err
+	}
+
+	return s.session.IndexCardinality(namespace, opts)
+}
+
 // ShardID returns the given shard for an ID for callers
 // to easily discern what shard is failing when operations
 // for given IDs begin failing.
@@ -0,0 +1,113 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package consolidators
+
+import (
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/ts"
+)
+
+// StepLookbackHistogramConsolidator consolidates the native histograms of a
+// series in a step-wise fashion alongside its values, which are consolidated
+// by a StepLookbackConsolidator. The histogram of each step is the last one
+// within the lookback of the step.
+type StepLookbackHistogramConsolidator struct {
+	*StepLookbackConsolidator
+
+	stepSize         time.Duration
+	earliestLookback time.Time
+	last             ts.Histogram
+	lastTimestamp    time.Time
+	hasLast          bool
+	unconsumed       []ts.Histogram
+}
+
+// Ensure StepLookbackHistogramConsolidator satisfies AnnotatedStepCollector.
+var _ AnnotatedStepCollector = (*StepLookbackHistogramConsolidator)(nil)
+
+// NewStepLookbackHistogramConsolidator creates a consolidator of the native
+// histograms of a series whose values are consolidated by the given
+// consolidator.
+func NewStepLookbackHistogramConsolidator(
+	consolidator *StepLookbackConsolidator,
+) *StepLookbackHistogramConsolidator {
+	return &StepLookbackHistogramConsolidator{
+		StepLookbackConsolidator: consolidator,
+		stepSize:                 consolidator.stepSize,
+		earliestLookback:         consolidator.earliestLookback,
+		unconsumed:               make([]ts.Histogram, 0, BufferSteps),
+	}
+}
+
+// AddAnnotatedPoint adds a datapoint to a given step if it's within the valid
+// time period, along with the histogram it holds if there is one.
+func (c *StepLookbackHistogramConsolidator) AddAnnotatedPoint(
+	dp ts.Datapoint,
+	annotation ts.Annotation,
+) error {
+	c.AddPoint(dp)
+	if dp.Timestamp.Before(c.earliestLookback) {
+		// this datapoint is too far in the past, it can be dropped.
+		return nil
+	}
+
+	h, ok, err := ts.HistogramFromAnnotation(annotation)
+	if err != nil {
+		return err
+	}
+
+	if ok {
+		c.last = h
+		c.lastTimestamp = dp.Timestamp
+		c.hasLast = true
+	}
+
+	return nil
+}
+
+// BufferStep adds the last viable histogram to the next unconsumed buffer
+// step, in addition to the consolidated value.
+func (c *StepLookbackHistogramConsolidator) BufferStep() {
+	c.StepLookbackConsolidator.BufferStep()
+
+	var h ts.Histogram
+	if c.hasLast && !c.lastTimestamp.Before(c.earliestLookback) {
+		h = c.last
+	}
+
+	c.earliestLookback = c.earliestLookback.Add(c.stepSize)
+	c.unconsumed = append(c.unconsumed, h)
+}
+
+// ConsolidateHistogramAndMoveToNext returns the histogram of the current step
+// and moves the consolidator to the next step, it must be called alongside
+// ConsolidateAndMoveToNext. Steps without a histogram have a histogram with no
+// buckets.
+func (c *StepLookbackHistogramConsolidator) ConsolidateHistogramAndMoveToNext() ts.Histogram {
+	if len(c.unconsumed) == 0 {
+		return ts.Histogram{}
+	}
+
+	h := c.unconsumed[0]
+	c.unconsumed = c.unconsumed[1:]
+	return h
+}
@@ -0,0 +1,114 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package consolidators
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/ts"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistogramConsolidator(t *testing.T) {
+	lookback := time.Minute
+	start := time.Now().Truncate(time.Hour)
+	consolidator := NewStepLookbackHistogramConsolidator(
+		NewStepLookbackConsolidator(
+			lookback,
+			lookback,
+			start,
+			TakeLast,
+		),
+	)
+
+	h1 := ts.Histogram{Buckets: []ts.HistogramBucket{
+		{UpperBound: 1, Count: 1},
+		{UpperBound: math.Inf(1), Count: 2},
+	}}
+	h2 := ts.Histogram{Buckets: []ts.HistogramBucket{
+		{UpperBound: 1, Count: 3},
+		{UpperBound: math.Inf(1), Count: 5},
+	}}
+
+	// NB: lookback limit: start-1
+	consolidator.BufferStep()
+
+	require.NoError(t, consolidator.AddAnnotatedPoint(ts.Datapoint{
+		Timestamp: start,
+		Value:     2,
+	}, h1.Annotation()))
+	require.NoError(t, consolidator.AddAnnotatedPoint(ts.Datapoint{
+		Timestamp: start.Add(time.Minute),
+		Value:     5,
+	}, h2.Annotation()))
+	consolidator.BufferStep()
+	consolidator.BufferStep()
+	consolidator.BufferStep()
+
+	// NB: a datapoint without a histogram annotation leaves no histogram.
+	require.NoError(t, consolidator.AddAnnotatedPoint(ts.Datapoint{
+		Timestamp: start.Add(3*time.Minute + time.Second),
+		Value:     3,
+	}, nil))
+	consolidator.BufferStep()
+
+	expected := []struct {
+		value     float64
+		histogram ts.Histogram
+	}{
+		{value: math.NaN()},
+		{value: 5, histogram: h2},
+		{value: 5, histogram: h2},
+		{value: math.NaN()},
+		{value: 3},
+	}
+
+	for i, e := range expected {
+		value := consolidator.ConsolidateAndMoveToNext()
+		h := consolidator.ConsolidateHistogramAndMoveToNext()
+		if math.IsNaN(e.value) {
+			assert.True(t, math.IsNaN(value), "step %d", i)
+		} else {
+			assert.Equal(t, e.value, value, "step %d", i)
+		}
+		assert.True(t, e.histogram.Equal(h), "step %d", i)
+	}
+}
+
+func TestHistogramConsolidatorInvalidAnnotation(t *testing.T) {
+	start := time.Now().Truncate(time.Hour)
+	consolidator := NewStepLookbackHistogramConsolidator(
+		NewStepLookbackConsolidator(time.Minute, time.Minute, start, TakeLast),
+	)
+
+	annotation := ts.Histogram{Buckets: []ts.HistogramBucket{
+		{UpperBound: 1, Count: 1},
+	}}.Annotation()
+	err := consolidator.AddAnnotatedPoint(ts.Datapoint{
+		Timestamp: start,
+		Value:     1,
+	}, annotation[:len(annotation)-1])
+	require.Error(t, err)
+}
@@ -43,6 +43,16 @@ type StepCollector interface {
 	BufferStepCount() int
 }
 
+// AnnotatedStepCollector is a StepCollector that also collects the annotations
+// of datapoints.
+type AnnotatedStepCollector interface {
+	StepCollector
+	// AddAnnotatedPoint adds a datapoint and its annotation to the current step
+	// if it's within the valid time period, the annotation is only valid for
+	// the duration of the call.
+	AddAnnotatedPoint(ts.Datapoint, ts.Annotation) error
+}
+
 // ConsolidationFunc consolidates a bunch of datapoints into a single float value.
 type ConsolidationFunc func(datapoints []ts.Datapoint) float64
 
@@ -21,14 +21,18 @@
 package m3db
 
 import (
+	dts "github.com/m3db/m3/src/dbnode/ts"
 	"github.com/m3db/m3/src/query/block"
+	"github.com/m3db/m3/src/query/storage"
 	"github.com/m3db/m3/src/query/ts/m3db/consolidators"
 )
 
 type encodedStepIter struct {
-	collectors []*consolidators.StepLookbackConsolidator
+	collectors     []*consolidators.StepLookbackConsolidator
+	histCollectors []*consolidators.StepLookbackHistogramConsolidator
 	encodedStepIterWithCollector
-	values []float64
+	values     []float64
+	histograms []dts.Histogram
 }
 
 func (b *encodedBlock) StepIter() (
@@ -42,6 +46,7 @@ func (b *encodedBlock) StepIter() (
 
 		collectors       = make([]*consolidators.StepLookbackConsolidator, len(iters))
 		seriesCollectors = make([]consolidators.StepCollector, len(iters))
+		histCollectors   []*consolidators.StepLookbackHistogramConsolidator
 	)
 
 	for i := range iters {
@@ -55,11 +60,31 @@ func (b *encodedBlock) StepIter() (
 
 	for i := range collectors {
 		seriesCollectors[i] = collectors[i]
+		if !storage.IsNativeHistogramSeries(b.seriesMetas[i].Tags) {
+			continue
+		}
+
+		// NB: native histogram series also consolidate their histograms so
+		// that histogram aware functions can read them directly.
+		if histCollectors == nil {
+			histCollectors = make(
+				[]*consolidators.StepLookbackHistogramConsolidator, len(iters))
+		}
+		histCollectors[i] = consolidators.
+			NewStepLookbackHistogramConsolidator(collectors[i])
+		seriesCollectors[i] = histCollectors[i]
+	}
+
+	var histograms []dts.Histogram
+	if histCollectors != nil {
+		histograms = make([]dts.Histogram, 0, len(iters))
 	}
 
 	iter := &encodedStepIter{
-		collectors: collectors,
-		values:     make([]float64, 0, len(iters)),
+		collectors:     collectors,
+		histCollectors: histCollectors,
+		values:         make([]float64, 0, len(iters)),
+		histograms:     histograms,
 		encodedStepIterWithCollector: encodedStepIterWithCollector{
 			lastBlock: b.lastBlock,
 
@@ -85,9 +110,30 @@ func (it *encodedStepIter) update() {
 	for _, consolidator := range it.collectors {
 		it.values = append(it.values, consolidator.ConsolidateAndMoveToNext())
 	}
+
+	if it.histCollectors == nil {
+		return
+	}
+
+	it.histograms = it.histograms[:0]
+	for _, consolidator := range it.histCollectors {
+		var h dts.Histogram
+		if consolidator != nil {
+			h = consolidator.ConsolidateHistogramAndMoveToNext()
+		}
+		it.histograms = append(it.histograms, h)
+	}
 }
 
 func (it *encodedStepIter) Current() block.Step {
+	if it.histCollectors != nil {
+		return block.NewHistogramColStep(
+			it.stepTime,
+			it.values,
+			it.histograms,
+		)
+	}
+
 	return block.NewColStep(
 		it.stepTime,
 		it.values,
@@ -25,6 +25,7 @@ import (
 	"time"
 
 	"github.com/m3db/m3/src/dbnode/encoding"
+	"github.com/m3db/m3/src/dbnode/ts"
 	"github.com/m3db/m3/src/query/block"
 	"github.com/m3db/m3/src/query/ts/m3db/consolidators"
 	xerrors "github.com/m3db/m3/src/x/errors"
@@ -82,7 +83,9 @@ func nextForStep(
 		// Record previously peeked value, and all potentially valid
 		// values, then apply consolidation function to them to get the
 		// consolidated point.
-		collector.AddPoint(point)
+		if err := addPoint(collector, point, peek.annotation); err != nil {
+			return peek, collector, err
+		}
 		// clear peeked point.
 		peek.started = false
 		// If this point is currently at the boundary, finish here as there is no
@@ -96,18 +99,22 @@ func nextForStep(
 	// range of this consolidated step; then consolidate those points into
 	// a value, set the next peek value.
 	for iter.Next() {
-		dp, _, _ := iter.Current()
+		dp, _, annotation := iter.Current()
 
 		// If this datapoint is before the current timestamp, add it as a
 		// consolidation candidate.
 		if !dp.Timestamp.After(stepTime) {
 			peek.started = false
-			collector.AddPoint(dp)
+			if err := addPoint(collector, dp, annotation); err != nil {
+				return peek, collector, err
+			}
 		} else {
 			// This point exists further than the current step.
 			// Set peeked value to this point, then consolidate the retrieved
-			// series.
+			// series. NB: the annotation is only valid until the iterator
+			// moves on, so it has to be copied.
 			peek.point = dp
+			peek.annotation = append(peek.annotation[:0], annotation...)
 			peek.started = true
 			return peek, collector, nil
 		}
@@ -117,6 +124,21 @@ func nextForStep(
 	return peek, collector, iter.Err()
 }
 
+// Adds a point to the collector, along with its annotation if the collector
+// collects annotations.
+func addPoint(
+	collector consolidators.StepCollector,
+	dp ts.Datapoint,
+	annotation ts.Annotation,
+) error {
+	if c, ok := collector.(consolidators.AnnotatedStepCollector); ok {
+		return c.AddAnnotatedPoint(dp, annotation)
+	}
+
+	collector.AddPoint(dp)
+	return nil
+}
+
 func (it *encodedStepIterWithCollector) nextParallel(steps int) error {
 	var (
 		multiErr     xerrors.MultiError
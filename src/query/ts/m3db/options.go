@@ -27,7 +27,7 @@ import (
 	"time"
 
 	"github.com/m3db/m3/src/dbnode/encoding"
-	"github.com/m3db/m3/src/dbnode/encoding/m3tsz"
+	"github.com/m3db/m3/src/dbnode/encoding/histogram"
 	"github.com/m3db/m3/src/dbnode/namespace"
 	"github.com/m3db/m3/src/query/models"
 	"github.com/m3db/m3/src/query/pools"
@@ -41,8 +41,8 @@ var (
 	defaultCount            = 10
 	defaultLookbackDuration = time.Duration(0)
 	defaultConsolidationFn  = consolidators.TakeLast
-	defaultIterAlloc        = func(r io.Reader, _ namespace.SchemaDescr) encoding.ReaderIterator {
-		return m3tsz.NewReaderIterator(r, m3tsz.DefaultIntOptimizationEnabled, encoding.NewOptions())
+	defaultIterAlloc        = func(r io.Reader, descr namespace.SchemaDescr) encoding.ReaderIterator {
+		return histogram.NewIterator(r, descr, encoding.NewOptions())
 	}
 )
 
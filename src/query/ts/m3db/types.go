@@ -78,7 +78,8 @@ type Options interface {
 }
 
 type peekValue struct {
-	started  bool
-	finished bool
-	point    ts.Datapoint
+	started    bool
+	finished   bool
+	point      ts.Datapoint
+	annotation ts.Annotation
 }
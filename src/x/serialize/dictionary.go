@@ -0,0 +1,404 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package serialize
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/m3db/m3/src/x/checked"
+	"github.com/m3db/m3/src/x/ident"
+)
+
+/*
+ * Dictionary serialization scheme, used to avoid repeating the same tag
+ * names and values across many encoded tag sets.
+ *
+ * A dictionary holds each distinct literal once:
+ * []byte(
+ *    DICTIONARY_MAGIC_MARKER + NUMBER_LITERALS
+ *                            + LENGTH(literal_0) + literal_0
+ *                            + ...
+ * )
+ *
+ * A tag set encoded against a dictionary references literals by position:
+ * []byte(
+ *    DICTIONARY_TAGS_MAGIC_MARKER + NUMBER_TAGS
+ *                                 + REF(name_0) + REF(value_0)
+ *                                 + ...
+ * )
+ *
+ * Where the magic markers are 2 bytes and NUMBER_LITERALS/LENGTH/NUMBER_TAGS/
+ * REF are uvarints.
+ */
+
+var (
+	dictionaryMagicBytes     = encodeUInt16(dictionaryMagicNumber)
+	dictionaryTagsMagicBytes = encodeUInt16(dictionaryTagsMagicNumber)
+)
+
+var (
+	errIncorrectDictionaryHeader = errors.New("dictionary header magic number does not match expected value")
+	errInvalidDictionaryRef      = errors.New("tag references a literal missing from the dictionary")
+	errInvalidByteStreamDecoding = errors.New("internal error, invalid byte stream while decoding uvarint")
+)
+
+// TagDictionary is a decoded dictionary of tag literals, the literals
+// reference the bytes the dictionary was decoded from.
+type TagDictionary struct {
+	literals [][]byte
+}
+
+// DecodeTagDictionary decodes a dictionary from the start of the provided
+// bytes, returning the dictionary and the number of bytes it occupied.
+// NB: the dictionary references the provided bytes rather than copying them.
+func DecodeTagDictionary(
+	b []byte,
+	limits TagSerializationLimits,
+) (*TagDictionary, int, error) {
+	if len(b) < 2 || decodeUInt16(b) != dictionaryMagicNumber {
+		return nil, 0, errIncorrectDictionaryHeader
+	}
+	n := 2
+
+	numLiterals, size := binary.Uvarint(b[n:])
+	if size <= 0 {
+		return nil, 0, errInvalidByteStreamDecoding
+	}
+	n += size
+
+	// Each literal takes at least a byte for its length.
+	if numLiterals > uint64(len(b)-n) {
+		return nil, 0, errInvalidByteStreamDecoding
+	}
+
+	maxLen := uint64(limits.MaxTagLiteralLength())
+	literals := make([][]byte, 0, int(numLiterals))
+	for i := uint64(0); i < numLiterals; i++ {
+		l, size := binary.Uvarint(b[n:])
+		if size <= 0 {
+			return nil, 0, errInvalidByteStreamDecoding
+		}
+		n += size
+
+		if l > maxLen {
+			return nil, 0, fmt.Errorf(
+				"tag literal too long [ limit = %d, observed = %d ]", maxLen, l)
+		}
+		if uint64(len(b)-n) < l {
+			return nil, 0, errInvalidByteStreamIDDecoding
+		}
+		literals = append(literals, b[n:n+int(l):n+int(l)])
+		n += int(l)
+	}
+
+	return &TagDictionary{literals: literals}, n, nil
+}
+
+// Len returns the number of literals in the dictionary.
+func (d *TagDictionary) Len() int {
+	return len(d.literals)
+}
+
+// Literal returns the literal a reference points to.
+func (d *TagDictionary) Literal(ref uint64) ([]byte, bool) {
+	if ref >= uint64(len(d.literals)) {
+		return nil, false
+	}
+	return d.literals[ref], true
+}
+
+// IsDictionaryEncodedTags returns whether the encoded tags reference
+// a dictionary rather than holding their literals inline.
+func IsDictionaryEncodedTags(b []byte) bool {
+	return len(b) >= 2 && decodeUInt16(b) == dictionaryTagsMagicNumber
+}
+
+// TagDictionaryBuilder builds a dictionary of tag literals, returning
+// references to the literals that can be resolved once the dictionary
+// has been decoded.
+type TagDictionaryBuilder struct {
+	refs     map[string]uint64
+	literals [][]byte
+	buf      []byte
+	opts     TagEncoderOptions
+}
+
+// NewTagDictionaryBuilder returns a new TagDictionaryBuilder.
+func NewTagDictionaryBuilder(opts TagEncoderOptions) *TagDictionaryBuilder {
+	return &TagDictionaryBuilder{
+		refs: make(map[string]uint64),
+		opts: opts,
+	}
+}
+
+// Ref returns the reference of a literal, adding it to the dictionary
+// if it is not present yet.
+// NB: the literal is copied so the caller is free to reuse it.
+func (b *TagDictionaryBuilder) Ref(literal []byte) (uint64, error) {
+	if len(literal) >= int(b.opts.TagSerializationLimits().MaxTagLiteralLength()) {
+		return 0, errTagLiteralTooLong
+	}
+	if ref, ok := b.refs[string(literal)]; ok {
+		return ref, nil
+	}
+	ref := uint64(len(b.literals))
+	literal = append([]byte(nil), literal...)
+	b.refs[string(literal)] = ref
+	b.literals = append(b.literals, literal)
+	return ref, nil
+}
+
+// Encode encodes the tags as references into the dictionary, the returned
+// bytes are owned by the caller.
+// NB: leaves the original iterator un-modified.
+func (b *TagDictionaryBuilder) Encode(srcTags ident.TagIterator) ([]byte, error) {
+	tags := srcTags.Duplicate()
+	defer tags.Close()
+
+	numTags := tags.Remaining()
+	max := int(b.opts.TagSerializationLimits().MaxNumberTags())
+	if numTags > max {
+		return nil, fmt.Errorf("too many tags to encode (%d), limit is: %d", numTags, max)
+	}
+
+	b.buf = append(b.buf[:0], dictionaryTagsMagicBytes...)
+	b.buf = appendUvarint(b.buf, uint64(numTags))
+	for tags.Next() {
+		tag := tags.Current()
+		name := tag.Name.Bytes()
+		if len(name) == 0 {
+			return nil, errEmptyTagNameLiteral
+		}
+		nameRef, err := b.Ref(name)
+		if err != nil {
+			return nil, err
+		}
+		valueRef, err := b.Ref(tag.Value.Bytes())
+		if err != nil {
+			return nil, err
+		}
+		b.buf = appendUvarint(b.buf, nameRef)
+		b.buf = appendUvarint(b.buf, valueRef)
+	}
+	if err := tags.Err(); err != nil {
+		return nil, err
+	}
+
+	return append([]byte(nil), b.buf...), nil
+}
+
+// Len returns the number of literals in the dictionary.
+func (b *TagDictionaryBuilder) Len() int {
+	return len(b.literals)
+}
+
+// Dictionary returns the encoded dictionary, the returned bytes are
+// owned by the caller.
+func (b *TagDictionaryBuilder) Dictionary() []byte {
+	size := len(dictionaryMagicBytes) + binary.MaxVarintLen64
+	for _, literal := range b.literals {
+		size += binary.MaxVarintLen64 + len(literal)
+	}
+	data := make([]byte, 0, size)
+	data = append(data, dictionaryMagicBytes...)
+	data = appendUvarint(data, uint64(len(b.literals)))
+	for _, literal := range b.literals {
+		data = appendUvarint(data, uint64(len(literal)))
+		data = append(data, literal...)
+	}
+	return data
+}
+
+// Reset resets the builder to build a new dictionary.
+func (b *TagDictionaryBuilder) Reset() {
+	for k := range b.refs {
+		delete(b.refs, k)
+	}
+	for i := range b.literals {
+		b.literals[i] = nil
+	}
+	b.literals = b.literals[:0]
+}
+
+type dictionaryDecoder struct {
+	checkedData checked.Bytes
+	data        []byte
+	nextCalls   int
+	length      int
+	remaining   int
+	err         error
+	current     ident.Tag
+
+	dict *TagDictionary
+	opts TagDecoderOptions
+}
+
+// NewTagDictionaryDecoder returns a TagDecoder for tags encoded against
+// the provided dictionary, the decoded tags reference the dictionary's
+// literals rather than copying them.
+func NewTagDictionaryDecoder(
+	dict *TagDictionary,
+	opts TagDecoderOptions,
+) TagDecoder {
+	return &dictionaryDecoder{
+		dict: dict,
+		opts: opts,
+	}
+}
+
+func (d *dictionaryDecoder) Reset(b checked.Bytes) {
+	d.resetForReuse()
+	d.checkedData = b
+	d.checkedData.IncRef()
+	d.data = d.checkedData.Bytes()
+
+	if !IsDictionaryEncodedTags(d.data) {
+		d.err = errIncorrectHeader
+		return
+	}
+	d.data = d.data[2:]
+
+	length, err := d.decodeUvarint()
+	if err != nil {
+		d.err = err
+		return
+	}
+
+	if limit := uint64(d.opts.TagSerializationLimits().MaxNumberTags()); length > limit {
+		d.err = fmt.Errorf("too many tags [ limit = %d, observed = %d ]", limit, length)
+		return
+	}
+
+	d.length = int(length)
+	d.remaining = int(length)
+}
+
+func (d *dictionaryDecoder) Next() bool {
+	d.current = ident.Tag{}
+	d.nextCalls++
+	if d.err != nil || d.remaining <= 0 {
+		return false
+	}
+
+	name, err := d.decodeLiteral()
+	if err != nil {
+		d.err = err
+		return false
+	}
+	if len(name) == 0 {
+		d.err = errEmptyTagNameLiteral
+		return false
+	}
+
+	value, err := d.decodeLiteral()
+	if err != nil {
+		d.err = err
+		return false
+	}
+
+	d.current = ident.Tag{
+		Name:  ident.BytesID(name),
+		Value: ident.BytesID(value),
+	}
+	d.remaining--
+	return true
+}
+
+func (d *dictionaryDecoder) decodeLiteral() ([]byte, error) {
+	ref, err := d.decodeUvarint()
+	if err != nil {
+		return nil, err
+	}
+	literal, ok := d.dict.Literal(ref)
+	if !ok {
+		return nil, errInvalidDictionaryRef
+	}
+	return literal, nil
+}
+
+func (d *dictionaryDecoder) decodeUvarint() (uint64, error) {
+	v, n := binary.Uvarint(d.data)
+	if n <= 0 {
+		return 0, errInvalidByteStreamDecoding
+	}
+	d.data = d.data[n:]
+	return v, nil
+}
+
+func (d *dictionaryDecoder) Current() ident.Tag {
+	return d.current
+}
+
+func (d *dictionaryDecoder) CurrentIndex() int {
+	return d.Len() - d.Remaining()
+}
+
+func (d *dictionaryDecoder) Err() error {
+	return d.err
+}
+
+func (d *dictionaryDecoder) Len() int {
+	return d.length
+}
+
+func (d *dictionaryDecoder) Remaining() int {
+	return d.remaining
+}
+
+func (d *dictionaryDecoder) resetForReuse() {
+	d.current = ident.Tag{}
+	d.data = nil
+	d.err = nil
+	d.length = 0
+	d.remaining = 0
+	d.nextCalls = 0
+	if d.checkedData != nil {
+		d.checkedData.DecRef()
+		if d.checkedData.NumRef() == 0 {
+			d.checkedData.Finalize()
+		}
+		d.checkedData = nil
+	}
+}
+
+func (d *dictionaryDecoder) Close() {
+	d.resetForReuse()
+}
+
+func (d *dictionaryDecoder) Duplicate() ident.TagIterator {
+	iter := NewTagDictionaryDecoder(d.dict, d.opts)
+	if d.checkedData == nil {
+		return iter
+	}
+	iter.Reset(d.checkedData)
+	for i := 0; i < d.nextCalls; i++ {
+		iter.Next()
+	}
+	return iter
+}
+
+func appendUvarint(b []byte, v uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], v)
+	return append(b, buf[:n]...)
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package serialize
+
+import (
+	"testing"
+
+	"github.com/m3db/m3/src/x/ident"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTagDictionaryRoundTrip(t *testing.T) {
+	b := NewTagDictionaryBuilder(NewTagEncoderOptions())
+
+	first, err := b.Encode(ident.NewTagsIterator(ident.NewTags(
+		ident.StringTag("city", "nyc"),
+		ident.StringTag("host", "a"),
+	)))
+	require.NoError(t, err)
+	second, err := b.Encode(ident.NewTagsIterator(ident.NewTags(
+		ident.StringTag("city", "nyc"),
+		ident.StringTag("host", "b"),
+	)))
+	require.NoError(t, err)
+	require.Equal(t, 5, b.Len())
+	require.True(t, IsDictionaryEncodedTags(first))
+	require.True(t, IsDictionaryEncodedTags(second))
+
+	data := append(b.Dictionary(), 0xff)
+	dict, n, err := DecodeTagDictionary(data, NewTagSerializationLimits())
+	require.NoError(t, err)
+	require.Equal(t, len(data)-1, n)
+	require.Equal(t, 5, dict.Len())
+
+	d := NewTagDictionaryDecoder(dict, testDecodeOpts)
+	d.Reset(wrapAsCheckedBytes(second))
+	require.Equal(t, 2, d.Remaining())
+	require.True(t, d.Next())
+	require.Equal(t, "city", d.Current().Name.String())
+	require.Equal(t, "nyc", d.Current().Value.String())
+
+	dupe := d.Duplicate()
+	require.True(t, d.Next())
+	require.Equal(t, "host", d.Current().Name.String())
+	require.Equal(t, "b", d.Current().Value.String())
+	require.False(t, d.Next())
+	require.NoError(t, d.Err())
+	d.Close()
+
+	require.Equal(t, 1, dupe.Remaining())
+	require.True(t, dupe.Next())
+	require.Equal(t, "b", dupe.Current().Value.String())
+	dupe.Close()
+}
+
+func TestTagDictionaryDecodeInvalidRef(t *testing.T) {
+	b := NewTagDictionaryBuilder(NewTagEncoderOptions())
+	encoded, err := b.Encode(ident.NewTagsIterator(ident.NewTags(
+		ident.StringTag("city", "nyc"),
+	)))
+	require.NoError(t, err)
+
+	empty := NewTagDictionaryBuilder(NewTagEncoderOptions())
+	dict, _, err := DecodeTagDictionary(empty.Dictionary(), NewTagSerializationLimits())
+	require.NoError(t, err)
+
+	d := NewTagDictionaryDecoder(dict, testDecodeOpts)
+	d.Reset(wrapAsCheckedBytes(encoded))
+	require.False(t, d.Next())
+	require.Equal(t, errInvalidDictionaryRef, d.Err())
+	d.Close()
+}
+
+func TestTagDictionaryDecodeInvalidHeader(t *testing.T) {
+	_, _, err := DecodeTagDictionary(headerMagicBytes, NewTagSerializationLimits())
+	require.Equal(t, errIncorrectDictionaryHeader, err)
+
+	require.False(t, IsDictionaryEncodedTags(headerMagicBytes))
+}
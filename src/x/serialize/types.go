@@ -31,6 +31,14 @@ var (
 	// headerMagicNumber is an internal header used to denote the beginning of
 	// an encoded stream.
 	headerMagicNumber uint16 = 10101
+
+	// dictionaryMagicNumber is an internal header used to denote the
+	// beginning of an encoded tag dictionary.
+	dictionaryMagicNumber uint16 = 10102
+
+	// dictionaryTagsMagicNumber is an internal header used to denote the
+	// beginning of an encoded stream referencing a tag dictionary.
+	dictionaryTagsMagicNumber uint16 = 10103
 )
 
 // TagEncoder encodes provided Tag iterators.